@@ -30,6 +30,123 @@ const (
 	// The value is interpreted as a token, and must equal the value of
 	// meta.ReconcileRequestAnnotation in order to reset the failure counts.
 	ResetRequestAnnotation string = "reconcile.fluxcd.io/resetAt"
+
+	// DryRunRequestAnnotation is the annotation used for triggering a one-off
+	// dry-run of the Helm release, of which the result is written to a
+	// ConfigMap. This is also handled when the HelmRelease is suspended.
+	// The value is interpreted as a token, and must equal the value of
+	// meta.ReconcileRequestAnnotation in order to trigger a dry-run.
+	DryRunRequestAnnotation string = "reconcile.fluxcd.io/dryRunAt"
+
+	// SupportBundleRequestAnnotation is the annotation used for triggering
+	// the one-off collection of a support bundle of the HelmRelease, which is
+	// written to a ConfigMap. This is also handled when the HelmRelease is
+	// suspended.
+	// The value is interpreted as a token, and must equal the value of
+	// meta.ReconcileRequestAnnotation in order to trigger the collection.
+	SupportBundleRequestAnnotation string = "reconcile.fluxcd.io/supportBundleAt"
+
+	// AcknowledgeRemediationRequestAnnotation is the annotation used for
+	// acknowledging the failures of the rollback remediation of a HelmRelease,
+	// so that releasing and remediating is attempted again after the
+	// configured maximum of rollback failures was reached.
+	// The value is interpreted as a token, and must equal the value of
+	// meta.ReconcileRequestAnnotation in order to acknowledge the failures.
+	AcknowledgeRemediationRequestAnnotation string = "reconcile.fluxcd.io/acknowledgeRemediationAt"
+
+	// EffectiveConfigRequestAnnotation is the annotation used for triggering
+	// a one-off dump of the effective configuration of the HelmRelease, i.e.
+	// its spec with the defaults of the API and the controller filled in,
+	// which is written to a ConfigMap. This is also handled when the
+	// HelmRelease is suspended.
+	// The value is interpreted as a token, and must equal the value of
+	// meta.ReconcileRequestAnnotation in order to trigger the dump.
+	EffectiveConfigRequestAnnotation string = "reconcile.fluxcd.io/effectiveConfigAt"
+
+	// ConfirmResumeRequestAnnotation is the annotation used for confirming
+	// the release of the pending changes of a HelmRelease which awaits
+	// confirmation after it was resumed from a long suspension.
+	// The value is interpreted as a token, and must equal the value of
+	// meta.ReconcileRequestAnnotation in order to confirm the release.
+	ConfirmResumeRequestAnnotation string = "reconcile.fluxcd.io/confirmResumeAt"
+
+	// FeatureGatesAnnotation is the annotation used for overriding the state
+	// of feature gates for an individual HelmRelease, in the same format as
+	// the --feature-gates flag of the controller (e.g. "HideSecrets=false").
+	// It only applies to the feature gates the controller allows to be
+	// overridden.
+	FeatureGatesAnnotation string = "helm.toolkit.fluxcd.io/feature-gates"
+
+	// PreviewAnnotation is the annotation used for stamping a HelmRelease for
+	// an ephemeral preview environment, e.g. of a pull request. The value is
+	// the identifier of the preview environment, which is appended as a
+	// suffix to the release name and target namespace. It must be a valid
+	// DNS label, e.g. "pr-123".
+	PreviewAnnotation string = "helm.toolkit.fluxcd.io/preview"
+
+	// SuspendAnnotation is the annotation used for suspending a HelmRelease
+	// without changing its spec. With a value of SuspendAnnotationAll, the
+	// reconciliation is suspended as with HelmReleaseSpec.Suspend. With a
+	// value of SuspendAnnotationRemediation, only the remediation of failed
+	// releases is suspended.
+	SuspendAnnotation string = "helm.toolkit.fluxcd.io/suspend"
+
+	// SuspendAnnotationAll is the value of SuspendAnnotation which suspends
+	// the reconciliation of the HelmRelease.
+	SuspendAnnotationAll string = "true"
+
+	// SuspendAnnotationRemediation is the value of SuspendAnnotation which
+	// suspends the remediation of failed releases, while the HelmRelease
+	// continues to be reconciled.
+	SuspendAnnotationRemediation string = "remediation"
+
+	// CriticalAnnotation is the annotation used for marking a HelmRelease as
+	// critical, e.g. because it releases the CNI or DNS of the cluster. With
+	// a value of "true", the validating webhook of the controller rejects the
+	// deletion of the HelmRelease, unless it has the BreakGlassAnnotation.
+	CriticalAnnotation string = "helm.toolkit.fluxcd.io/critical"
+
+	// BreakGlassAnnotation is the annotation used for allowing the deletion
+	// of a HelmRelease marked with the CriticalAnnotation, with a value of
+	// "true".
+	BreakGlassAnnotation string = "helm.toolkit.fluxcd.io/break-glass"
+
+	// StorageOwnerNameAnnotation is the annotation on the Helm storage
+	// Secrets (or ConfigMaps) of a release holding the name of the
+	// HelmRelease which owns the release.
+	StorageOwnerNameAnnotation string = "helm.toolkit.fluxcd.io/owner-name"
+
+	// StorageOwnerNamespaceAnnotation is the annotation on the Helm storage
+	// Secrets (or ConfigMaps) of a release holding the namespace of the
+	// HelmRelease which owns the release.
+	StorageOwnerNamespaceAnnotation string = "helm.toolkit.fluxcd.io/owner-namespace"
+
+	// StorageOwnerUIDAnnotation is the annotation on the Helm storage
+	// Secrets (or ConfigMaps) of a release holding the UID of the
+	// HelmRelease which owns the release. The controller refuses to modify
+	// a release of which the storage objects hold another UID.
+	StorageOwnerUIDAnnotation string = "helm.toolkit.fluxcd.io/owner-uid"
+
+	// EventVerbosityAnnotation is the annotation used for configuring the
+	// verbosity of the events recorded for a HelmRelease, overriding the
+	// verbosity of the controller. With a value of EventVerbosityWarning,
+	// only Warning events are recorded. With a value of EventVerbosityInfo,
+	// all events are recorded.
+	EventVerbosityAnnotation string = "helm.toolkit.fluxcd.io/event-verbosity"
+
+	// EventVerbosityInfo is the value of EventVerbosityAnnotation which
+	// records all events.
+	EventVerbosityInfo string = "info"
+
+	// EventVerbosityWarning is the value of EventVerbosityAnnotation which
+	// suppresses info-level (Normal) events.
+	EventVerbosityWarning string = "warning"
+
+	// ConcurrencyWeightAnnotation is the annotation used for marking a
+	// HelmRelease as heavyweight, with the number of concurrent reconciliation
+	// slots it occupies while being reconciled as value, e.g. "4". Without
+	// the annotation, a HelmRelease occupies a single slot.
+	ConcurrencyWeightAnnotation string = "helm.toolkit.fluxcd.io/concurrency-weight"
 )
 
 // ShouldHandleResetRequest returns true if the HelmRelease has a reset request
@@ -56,6 +173,73 @@ func ShouldHandleForceRequest(obj *HelmRelease) bool {
 	return handleRequest(obj, ForceRequestAnnotation, &obj.Status.LastHandledForceAt)
 }
 
+// ShouldHandleDryRunRequest returns true if the HelmRelease has a dry-run
+// request annotation, and the value of the annotation matches the value of
+// the meta.ReconcileRequestAnnotation annotation.
+//
+// To ensure that the dry-run request is handled only once, the value of
+// HelmReleaseStatus.LastHandledDryRunAt is updated to match the value of the
+// dry-run request annotation (even if the dry-run request is not handled
+// because the value of the meta.ReconcileRequestAnnotation annotation does
+// not match).
+func ShouldHandleDryRunRequest(obj *HelmRelease) bool {
+	return handleRequest(obj, DryRunRequestAnnotation, &obj.Status.LastHandledDryRunAt)
+}
+
+// ShouldHandleAcknowledgeRemediationRequest returns true if the HelmRelease
+// has a remediation acknowledge request annotation, and the value of the
+// annotation matches the value of the meta.ReconcileRequestAnnotation
+// annotation.
+//
+// To ensure that the acknowledge request is handled only once, the value of
+// HelmReleaseStatus.LastHandledAcknowledgeRemediationAt is updated to match
+// the value of the acknowledge request annotation (even if the request is not
+// handled because the value of the meta.ReconcileRequestAnnotation annotation
+// does not match).
+func ShouldHandleAcknowledgeRemediationRequest(obj *HelmRelease) bool {
+	return handleRequest(obj, AcknowledgeRemediationRequestAnnotation, &obj.Status.LastHandledAcknowledgeRemediationAt)
+}
+
+// ShouldHandleSupportBundleRequest returns true if the HelmRelease has a
+// support bundle request annotation, and the value of the annotation matches
+// the value of the meta.ReconcileRequestAnnotation annotation.
+//
+// To ensure that the support bundle request is handled only once, the value
+// of HelmReleaseStatus.LastHandledSupportBundleAt is updated to match the
+// value of the support bundle request annotation (even if the request is not
+// handled because the value of the meta.ReconcileRequestAnnotation annotation
+// does not match).
+func ShouldHandleSupportBundleRequest(obj *HelmRelease) bool {
+	return handleRequest(obj, SupportBundleRequestAnnotation, &obj.Status.LastHandledSupportBundleAt)
+}
+
+// ShouldHandleEffectiveConfigRequest returns true if the HelmRelease has an
+// effective configuration request annotation, and the value of the
+// annotation matches the value of the meta.ReconcileRequestAnnotation
+// annotation.
+//
+// To ensure that the effective configuration request is handled only once,
+// the value of HelmReleaseStatus.LastHandledEffectiveConfigAt is updated to
+// match the value of the request annotation (even if the request is not
+// handled because the value of the meta.ReconcileRequestAnnotation annotation
+// does not match).
+func ShouldHandleEffectiveConfigRequest(obj *HelmRelease) bool {
+	return handleRequest(obj, EffectiveConfigRequestAnnotation, &obj.Status.LastHandledEffectiveConfigAt)
+}
+
+// ShouldHandleConfirmResumeRequest returns true if the HelmRelease has a
+// confirm resume request annotation, and the value of the annotation matches
+// the value of the meta.ReconcileRequestAnnotation annotation.
+//
+// To ensure that the confirm resume request is handled only once, the value
+// of HelmReleaseStatus.LastHandledConfirmResumeAt is updated to match the
+// value of the confirm resume request annotation (even if the request is not
+// handled because the value of the meta.ReconcileRequestAnnotation annotation
+// does not match).
+func ShouldHandleConfirmResumeRequest(obj *HelmRelease) bool {
+	return handleRequest(obj, ConfirmResumeRequestAnnotation, &obj.Status.LastHandledConfirmResumeAt)
+}
+
 // handleRequest returns true if the HelmRelease has a request annotation, and
 // the value of the annotation matches the value of the meta.ReconcileRequestAnnotation
 // annotation.
@@ -78,6 +78,87 @@ func TestShouldHandleForceRequest(t *testing.T) {
 	})
 }
 
+func TestShouldHandleDryRunRequest(t *testing.T) {
+	t.Run("should handle dry-run request", func(t *testing.T) {
+		obj := &HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					meta.ReconcileRequestAnnotation: "b",
+					DryRunRequestAnnotation:         "b",
+				},
+			},
+			Status: HelmReleaseStatus{
+				LastHandledDryRunAt: "a",
+				ReconcileRequestStatus: meta.ReconcileRequestStatus{
+					LastHandledReconcileAt: "a",
+				},
+			},
+		}
+
+		if !ShouldHandleDryRunRequest(obj) {
+			t.Error("ShouldHandleDryRunRequest() = false")
+		}
+
+		if obj.Status.LastHandledDryRunAt != "b" {
+			t.Error("ShouldHandleDryRunRequest did not update LastHandledDryRunAt")
+		}
+	})
+}
+
+func TestShouldHandleSupportBundleRequest(t *testing.T) {
+	t.Run("should handle support bundle request", func(t *testing.T) {
+		obj := &HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					meta.ReconcileRequestAnnotation: "b",
+					SupportBundleRequestAnnotation:  "b",
+				},
+			},
+			Status: HelmReleaseStatus{
+				LastHandledSupportBundleAt: "a",
+				ReconcileRequestStatus: meta.ReconcileRequestStatus{
+					LastHandledReconcileAt: "a",
+				},
+			},
+		}
+
+		if !ShouldHandleSupportBundleRequest(obj) {
+			t.Error("ShouldHandleSupportBundleRequest() = false")
+		}
+
+		if obj.Status.LastHandledSupportBundleAt != "b" {
+			t.Error("ShouldHandleSupportBundleRequest did not update LastHandledSupportBundleAt")
+		}
+	})
+}
+
+func TestShouldHandleAcknowledgeRemediationRequest(t *testing.T) {
+	t.Run("should handle acknowledge remediation request", func(t *testing.T) {
+		obj := &HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					meta.ReconcileRequestAnnotation:         "b",
+					AcknowledgeRemediationRequestAnnotation: "b",
+				},
+			},
+			Status: HelmReleaseStatus{
+				LastHandledAcknowledgeRemediationAt: "a",
+				ReconcileRequestStatus: meta.ReconcileRequestStatus{
+					LastHandledReconcileAt: "a",
+				},
+			},
+		}
+
+		if !ShouldHandleAcknowledgeRemediationRequest(obj) {
+			t.Error("ShouldHandleAcknowledgeRemediationRequest() = false")
+		}
+
+		if obj.Status.LastHandledAcknowledgeRemediationAt != "b" {
+			t.Error("ShouldHandleAcknowledgeRemediationRequest did not update LastHandledAcknowledgeRemediationAt")
+		}
+	})
+}
+
 func Test_handleRequest(t *testing.T) {
 	const requestAnnotation = "requestAnnotation"
 
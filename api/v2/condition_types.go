@@ -29,6 +29,46 @@ const (
 	// (uninstall/rollback) due to a failure of the last release attempt against the
 	// latest desired state.
 	RemediatedCondition string = "Remediated"
+
+	// DeprecatedAPIsCondition represents the fact that the Kubernetes API
+	// server returned deprecation warnings while applying the Helm release,
+	// e.g. because the chart makes use of an API version which is going to be
+	// removed in a future Kubernetes version.
+	DeprecatedAPIsCondition string = "DeprecatedAPIs"
+
+	// HealthCheckIncompleteCondition represents the fact that the health
+	// check of the Helm release skipped resources of one or more kinds, e.g.
+	// because the controller is not allowed to read them.
+	HealthCheckIncompleteCondition string = "HealthCheckIncomplete"
+
+	// SuspendedCondition represents the fact that the reconciliation of the
+	// HelmRelease is suspended, and its other conditions reflect the last
+	// reconciliation before the suspension.
+	SuspendedCondition string = "Suspended"
+
+	// SourceVerifiedFailedCondition represents the fact that the source of
+	// the chart failed to verify the authenticity of the chart artifact, e.g.
+	// because it is not signed by a trusted key or identity.
+	SourceVerifiedFailedCondition string = "SourceVerifiedFailed"
+
+	// ResumePendingCondition represents the fact that the HelmRelease was
+	// resumed after a long suspension, and the release of the pending changes
+	// awaits confirmation.
+	ResumePendingCondition string = "ResumePending"
+
+	// PostDeployChecksPassedCondition represents the status of the last run
+	// of the post-deploy checks against the latest release.
+	PostDeployChecksPassedCondition string = "PostDeployChecksPassed"
+
+	// SourceDriftCondition represents the fact that the source of the chart
+	// resolved a lower chart version than previously released, or has not
+	// been ready for longer than the configured threshold.
+	SourceDriftCondition string = "SourceDrift"
+
+	// SecurityScanCondition represents the result of the scan of the
+	// rendered manifests of the last attempted revision by the security
+	// scanner of the controller.
+	SecurityScanCondition string = "SecurityScan"
 )
 
 const (
@@ -64,6 +104,11 @@ const (
 	// HelmRelease failed.
 	RollbackFailedReason string = "RollbackFailed"
 
+	// RemediationExhaustedReason represents the fact that the Helm rollback
+	// for the HelmRelease failed the maximum number of consecutive times, and
+	// no further attempts are made until this is acknowledged.
+	RemediationExhaustedReason string = "RemediationExhausted"
+
 	// UninstallSucceededReason represents the fact that the Helm uninstall for the
 	// HelmRelease succeeded.
 	UninstallSucceededReason string = "UninstallSucceeded"
@@ -72,11 +117,142 @@ const (
 	// HelmRelease failed.
 	UninstallFailedReason string = "UninstallFailed"
 
+	// OrphanedReason represents the fact that the Helm release of the
+	// HelmRelease was orphaned after it could not be uninstalled within the
+	// configured timeout.
+	OrphanedReason string = "Orphaned"
+
 	// ArtifactFailedReason represents the fact that the artifact download for the
 	// HelmRelease failed.
 	ArtifactFailedReason string = "ArtifactFailed"
 
+	// DeprecationWarningsReason represents the fact that the Kubernetes API
+	// server returned deprecation warnings for the HelmRelease.
+	DeprecationWarningsReason string = "DeprecationWarnings"
+
 	// DependencyNotReadyReason represents the fact that
 	// one of the dependencies is not ready.
 	DependencyNotReadyReason string = "DependencyNotReady"
+
+	// DependencyCycleReason represents the fact that the HelmRelease
+	// (transitively) depends on itself.
+	DependencyCycleReason string = "DependencyCycle"
+
+	// ReleaseNameConflictReason represents the fact that the Helm release
+	// targeted by the HelmRelease is managed by another HelmRelease.
+	ReleaseNameConflictReason string = "ReleaseNameConflict"
+
+	// SourceAPIUnavailableReason represents the fact that the API of the
+	// source kind the HelmRelease refers to is not installed in the cluster.
+	SourceAPIUnavailableReason string = "SourceAPIUnavailable"
+
+	// ReleaseNotOwnedReason represents the fact that the Helm storage of the
+	// release targeted by the HelmRelease is owned by another HelmRelease.
+	ReleaseNotOwnedReason string = "ReleaseNotOwned"
+
+	// HookEnvErrorReason represents the fact that the environment of the
+	// hooks of the HelmRelease could not be composed or written.
+	HookEnvErrorReason string = "HookEnvError"
+
+	// StorageMigrationFailedReason represents the fact that the Helm release
+	// could not be moved to the storage driver configured for the
+	// HelmRelease.
+	StorageMigrationFailedReason string = "StorageMigrationFailed"
+
+	// IncompatibleChartReason represents the fact that the chart can not be
+	// released with the Helm SDK of the controller, e.g. because of an
+	// unsupported chart apiVersion.
+	IncompatibleChartReason string = "IncompatibleChart"
+
+	// ChartTooLargeReason represents the fact that the chart artifact, or
+	// the chart decompressed from it, exceeds the size limits of the
+	// controller.
+	ChartTooLargeReason string = "ChartTooLarge"
+
+	// PreviewExpiredReason represents the fact that the HelmRelease stamped
+	// for a preview environment has outlived its TTL, and its Helm release
+	// is uninstalled.
+	PreviewExpiredReason string = "PreviewExpired"
+
+	// InvalidPreviewReason represents the fact that the identifier of the
+	// preview environment the HelmRelease is stamped for is not a valid DNS
+	// label.
+	InvalidPreviewReason string = "InvalidPreview"
+
+	// InsufficientPermissionsReason represents the fact that the controller
+	// lacks the permissions to read some of the resources of the Helm release
+	// during the health check.
+	InsufficientPermissionsReason string = "InsufficientPermissions"
+
+	// PhaseNotReadyReason represents the fact that not all HelmReleases of
+	// a phase of a HelmReleaseGroup are ready.
+	PhaseNotReadyReason string = "PhaseNotReady"
+
+	// VerificationFailedReason represents the fact that the source of the
+	// chart failed to verify the chart artifact.
+	VerificationFailedReason string = "VerificationFailed"
+
+	// UnverifiedArtifactReason represents the fact that a chart artifact of
+	// which the integrity could not be verified is released, because the
+	// artifact verification of the HelmRelease is set to warn.
+	UnverifiedArtifactReason string = "UnverifiedArtifact"
+
+	// AwaitingConfirmationReason represents the fact that the release of the
+	// pending changes of a resumed HelmRelease awaits confirmation.
+	AwaitingConfirmationReason string = "AwaitingConfirmation"
+
+	// ResumeConfirmedReason represents the fact that the release of the
+	// pending changes of a resumed HelmRelease has been confirmed, or is
+	// allowed to proceed automatically.
+	ResumeConfirmedReason string = "ResumeConfirmed"
+
+	// ValuesValidationFailedReason represents the fact that the composed
+	// values of the HelmRelease do not validate against the JSON schema of
+	// the chart.
+	ValuesValidationFailedReason string = "ValuesValidationFailed"
+
+	// ChartTemplateFailedReason represents the fact that the Go template
+	// expressions in the chart name or version of the HelmRelease could not
+	// be rendered.
+	ChartTemplateFailedReason string = "ChartTemplateFailed"
+
+	// PostDeployChecksFailedReason represents the fact that the post-deploy
+	// checks of the HelmRelease did not pass within the timeout.
+	PostDeployChecksFailedReason string = "PostDeployChecksFailed"
+
+	// ChartVersionDecreasedReason represents the fact that the chart version
+	// resolved by the source for the version constraint of the HelmRelease is
+	// lower than a version previously released for the same constraint, e.g.
+	// because versions were deleted from the chart repository.
+	ChartVersionDecreasedReason string = "ChartVersionDecreased"
+
+	// SourceStaleReason represents the fact that the source of the chart has
+	// not been ready for longer than the configured threshold, e.g. because
+	// the index of the chart repository can not be fetched.
+	SourceStaleReason string = "SourceStale"
+
+	// NodeRequirementsNotMetReason represents the fact that the nodes of the
+	// target cluster do not provide the capabilities required by the
+	// HelmRelease.
+	NodeRequirementsNotMetReason string = "NodeRequirementsNotMet"
+
+	// VerificationWindowFailedReason represents the fact that the resources
+	// of the Helm release became unhealthy within the verification window
+	// after a successful Helm upgrade.
+	VerificationWindowFailedReason string = "VerificationWindowFailed"
+
+	// NamespaceNotAllowedReason represents the fact that the reconciliation
+	// of HelmReleases in the namespace of the HelmRelease is not allowed by
+	// the ControllerConfig.
+	NamespaceNotAllowedReason string = "NamespaceNotAllowed"
+
+	// SecurityScanFailedReason represents the fact that the security scan of
+	// the rendered manifests reported findings at or above the severity
+	// threshold of the HelmRelease.
+	SecurityScanFailedReason string = "SecurityScanFailed"
+
+	// SecurityScanErrorReason represents the fact that the rendered
+	// manifests could not be scanned, e.g. because the security scanner is
+	// unavailable.
+	SecurityScanErrorReason string = "SecurityScanError"
 )
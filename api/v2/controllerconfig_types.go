@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ControllerConfigKind is the kind in string format.
+	ControllerConfigKind = "ControllerConfig"
+)
+
+// ControllerConfigSpec defines the defaults and policies of the controller.
+// Any field which is set takes precedence over the respective flag of the
+// controller.
+type ControllerConfigSpec struct {
+	// Timeout is the default timeout of the HelmReleases which do not
+	// configure '.spec.timeout'.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// MaxHistory is the default number of revisions saved by Helm for the
+	// HelmReleases which do not configure '.spec.maxHistory'. Use '0' for an
+	// unlimited number of revisions.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxHistory *int `json:"maxHistory,omitempty"`
+
+	// ArtifactVerification is the default strictness of the verification of
+	// the chart artifact of the HelmReleases which do not configure
+	// '.spec.artifactVerification'. It only takes effect when it is stricter
+	// than the '--artifact-verification' flag of the controller.
+	// +kubebuilder:validation:Enum=enforce;warn;disabled
+	// +optional
+	ArtifactVerification ArtifactVerification `json:"artifactVerification,omitempty"`
+
+	// MaxConcurrentReconciles is the number of HelmReleases which are
+	// reconciled concurrently, weighted by their concurrency weight. It can
+	// not exceed the '--concurrent' flag of the controller.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentReconciles *int `json:"maxConcurrentReconciles,omitempty"`
+
+	// AllowedNamespaces is the list of namespaces of which the HelmReleases
+	// are reconciled. When empty, the HelmReleases of all namespaces are
+	// reconciled, except for those in DeniedNamespaces.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// DeniedNamespaces is the list of namespaces of which the HelmReleases
+	// are not reconciled, taking precedence over AllowedNamespaces. The
+	// deletion of a HelmRelease in a denied namespace is still handled.
+	// +optional
+	DeniedNamespaces []string `json:"deniedNamespaces,omitempty"`
+
+	// FeatureGates configures the state of the feature gates which apply to
+	// the reconciliation of an individual HelmRelease, e.g.
+	// 'AllowDNSLookups'. Any override using the feature gates annotation
+	// on a HelmRelease takes precedence.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// AllowsNamespace returns if the HelmReleases in the given namespace are
+// allowed to be reconciled according to the AllowedNamespaces and
+// DeniedNamespaces.
+func (in ControllerConfigSpec) AllowsNamespace(namespace string) bool {
+	if slices.Contains(in.DeniedNamespaces, namespace) {
+		return false
+	}
+	return len(in.AllowedNamespaces) == 0 || slices.Contains(in.AllowedNamespaces, namespace)
+}
+
+// ControllerConfigStatus defines the observed state of a ControllerConfig.
+type ControllerConfigStatus struct {
+	// ObservedGeneration is the last observed generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions holds the conditions for the ControllerConfig.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description=""
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description=""
+
+// ControllerConfig is the Schema for the controllerconfigs API. It holds the
+// defaults and policies of the controller, which are applied without a
+// restart of the controller when they change.
+type ControllerConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ControllerConfigSpec `json:"spec,omitempty"`
+	// +kubebuilder:default:={"observedGeneration":-1}
+	Status ControllerConfigStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the status conditions of the object.
+func (in ControllerConfig) GetConditions() []metav1.Condition {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the status conditions on the object.
+func (in *ControllerConfig) SetConditions(conditions []metav1.Condition) {
+	in.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// ControllerConfigList contains a list of ControllerConfig objects.
+type ControllerConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ControllerConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ControllerConfig{}, &ControllerConfigList{})
+}
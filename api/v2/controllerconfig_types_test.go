@@ -0,0 +1,46 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+)
+
+func TestControllerConfigSpec_AllowsNamespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowed   []string
+		denied    []string
+		namespace string
+		want      bool
+	}{
+		{name: "without lists", namespace: "default", want: true},
+		{name: "allowed", allowed: []string{"default"}, namespace: "default", want: true},
+		{name: "not allowed", allowed: []string{"apps"}, namespace: "default", want: false},
+		{name: "denied", denied: []string{"default"}, namespace: "default", want: false},
+		{name: "not denied", denied: []string{"kube-system"}, namespace: "default", want: true},
+		{name: "denied takes precedence", allowed: []string{"default"}, denied: []string{"default"}, namespace: "default", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := ControllerConfigSpec{AllowedNamespaces: tt.allowed, DeniedNamespaces: tt.denied}
+			if got := spec.AllowsNamespace(tt.namespace); got != tt.want {
+				t.Errorf("AllowsNamespace() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v2
 
 import (
+	"strconv"
 	"strings"
 	"time"
 
@@ -55,7 +56,35 @@ type HelmReleaseSpec struct {
 	// +optional
 	ChartRef *CrossNamespaceSourceReference `json:"chartRef,omitempty"`
 
-	// Interval at which to reconcile the Helm release.
+	// ChartOverrides holds a list of chart source overrides. The first
+	// override of which the attributes match the cluster attributes of the
+	// controller is applied to the Chart or ChartRef.
+	// +optional
+	ChartOverrides []ChartOverride `json:"chartOverrides,omitempty"`
+
+	// ProxySecretRef is a reference to a Secret in the same namespace as the
+	// HelmRelease, containing the proxy configuration used to download the
+	// chart artifact of this HelmRelease. The Secret may contain the
+	// 'HTTP_PROXY', 'HTTPS_PROXY' and 'NO_PROXY' keys, which take precedence
+	// over the proxy configuration of the controller.
+	// +optional
+	ProxySecretRef *meta.LocalObjectReference `json:"proxySecretRef,omitempty"`
+
+	// ArtifactVerification controls the strictness of the verification of
+	// the chart artifact. It only takes effect when it is stricter than the
+	// minimum configured for the controller. With 'enforce', a chart
+	// artifact of which the digest can not be verified is not released.
+	// With 'warn', the chart artifact is released regardless, and a warning
+	// event is emitted. With 'disabled', the digest is not verified, and
+	// '.spec.chart.spec.verify' is not passed on to the HelmChart, which
+	// disables the verification of the chart signature.
+	// +kubebuilder:validation:Enum=enforce;warn;disabled
+	// +optional
+	ArtifactVerification ArtifactVerification `json:"artifactVerification,omitempty"`
+
+	// Interval at which to reconcile the Helm release. When set to zero, the
+	// Helm release is only reconciled on changes to the HelmRelease or its
+	// source, on request, and at the resync interval of the controller.
 	// +kubebuilder:validation:Type=string
 	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
 	// +required
@@ -76,6 +105,12 @@ type HelmReleaseSpec struct {
 	// +optional
 	Suspend bool `json:"suspend,omitempty"`
 
+	// ResumeVerification configures a verification pass which is performed
+	// when the HelmRelease is resumed after having been suspended for longer
+	// than a threshold, before any accumulated changes are released.
+	// +optional
+	ResumeVerification *ResumeVerification `json:"resumeVerification,omitempty"`
+
 	// ReleaseName used for the Helm release. Defaults to a composition of
 	// '[TargetNamespace-]Name'.
 	// +kubebuilder:validation:MinLength=1
@@ -84,6 +119,12 @@ type HelmReleaseSpec struct {
 	// +optional
 	ReleaseName string `json:"releaseName,omitempty"`
 
+	// ReleaseDescription is the description recorded in the Helm release on
+	// install and upgrade. Defaults to the description set by Helm.
+	// +kubebuilder:validation:MaxLength=512
+	// +optional
+	ReleaseDescription string `json:"releaseDescription,omitempty"`
+
 	// TargetNamespace to target when performing operations for the HelmRelease.
 	// Defaults to the namespace of the HelmRelease.
 	// +kubebuilder:validation:MinLength=1
@@ -100,11 +141,46 @@ type HelmReleaseSpec struct {
 	// +optional
 	StorageNamespace string `json:"storageNamespace,omitempty"`
 
-	// DependsOn may contain a meta.NamespacedObjectReference slice with
+	// StorageAnnotations are added to the Secrets (or ConfigMaps) of the Helm
+	// storage of the release, to provide ownership and change context to
+	// e.g. backup tools or policy engines.
+	// +optional
+	StorageAnnotations map[string]string `json:"storageAnnotations,omitempty"`
+
+	// StorageDriver is the Helm storage driver used to store the release
+	// information. Defaults to the storage driver configured for the
+	// controller, which is 'secret' unless configured otherwise.
+	// +kubebuilder:validation:Enum=secret;configmap;sql
+	// +optional
+	StorageDriver string `json:"storageDriver,omitempty"`
+
+	// StorageSQLSecretRef is a reference to a Secret in the same namespace as
+	// the HelmRelease, containing the connection string (DSN) of the
+	// PostgreSQL database used by the 'sql' storage driver.
+	// The connection string is read from the 'dsn' key of the Secret, unless
+	// another key is specified.
+	// When not set, the connection string configured for the controller is
+	// used.
+	// +optional
+	StorageSQLSecretRef *meta.SecretKeyReference `json:"storageSQLSecretRef,omitempty"`
+
+	// DependsOn may contain a DependencyReference slice with
 	// references to HelmRelease resources that must be ready before this HelmRelease
-	// can be reconciled.
+	// can be reconciled. The referenced HelmReleases may be in other namespaces,
+	// and may be required to have a chart version matching a constraint.
 	// +optional
-	DependsOn []meta.NamespacedObjectReference `json:"dependsOn,omitempty"`
+	DependsOn []DependencyReference `json:"dependsOn,omitempty"`
+
+	// Wait holds the configuration for waiting on arbitrary cluster resources
+	// to be ready before a Helm action is performed for this HelmRelease.
+	// +optional
+	Wait *Wait `json:"wait,omitempty"`
+
+	// NodeRequirements holds the capabilities the nodes of the target cluster
+	// must provide before a Helm action is performed for this HelmRelease,
+	// e.g. a CPU architecture or the presence of GPUs.
+	// +optional
+	NodeRequirements *NodeRequirements `json:"nodeRequirements,omitempty"`
 
 	// Timeout is the time to wait for any individual Kubernetes operation (like Jobs
 	// for hooks) during the performance of a Helm action. Defaults to '5m0s'.
@@ -114,10 +190,21 @@ type HelmReleaseSpec struct {
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
 
 	// MaxHistory is the number of revisions saved by Helm for this HelmRelease.
-	// Use '0' for an unlimited number of revisions; defaults to '5'.
+	// Use '0' for an unlimited number of revisions; defaults to '5', unless
+	// configured otherwise on the controller.
 	// +optional
 	MaxHistory *int `json:"maxHistory,omitempty"`
 
+	// HistoryPolicy determines which revisions of the release are retained
+	// in the Helm storage. With 'all', the most recent MaxHistory revisions
+	// are retained regardless of their status. With 'pruneFailed', failed and
+	// pending revisions are pruned after each Helm action unless they are the
+	// latest revision, while the most recent MaxHistory successful revisions
+	// are retained as rollback targets. Defaults to 'all'.
+	// +kubebuilder:validation:Enum=all;pruneFailed
+	// +optional
+	HistoryPolicy HistoryPolicy `json:"historyPolicy,omitempty"`
+
 	// The name of the Kubernetes service account to impersonate
 	// when reconciling this HelmRelease.
 	// +kubebuilder:validation:MinLength=1
@@ -146,6 +233,19 @@ type HelmReleaseSpec struct {
 	// +optional
 	DriftDetection *DriftDetection `json:"driftDetection,omitempty"`
 
+	// Preview holds the configuration for HelmReleases stamped for an
+	// ephemeral preview environment using the PreviewAnnotation.
+	// +optional
+	Preview *Preview `json:"preview,omitempty"`
+
+	// Prune enables the deletion of resources which are part of the previous
+	// release, but absent from the new release, after a successful upgrade.
+	// This covers resources Helm does not delete itself, e.g. because they
+	// were adopted by the release. Resources annotated with
+	// 'helm.sh/resource-policy: keep' are not deleted.
+	// +optional
+	Prune bool `json:"prune,omitempty"`
+
 	// Install holds the configuration for Helm install actions for this HelmRelease.
 	// +optional
 	Install *Install `json:"install,omitempty"`
@@ -158,6 +258,24 @@ type HelmReleaseSpec struct {
 	// +optional
 	Test *Test `json:"test,omitempty"`
 
+	// HealthCheck holds the configuration for the health assessment of the
+	// resources of the release for this HelmRelease.
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+
+	// PostDeployChecks holds the configuration for the checks run against
+	// the endpoints of the release after a Helm install or upgrade action,
+	// before the HelmRelease is marked as Ready.
+	// +optional
+	PostDeployChecks *PostDeployChecks `json:"postDeployChecks,omitempty"`
+
+	// SecurityScan holds the configuration for the scan of the rendered
+	// manifests of the release, and the container images referenced by
+	// them, by the security scanner of the controller before they are
+	// applied.
+	// +optional
+	SecurityScan *SecurityScan `json:"securityScan,omitempty"`
+
 	// Rollback holds the configuration for Helm rollback actions for this HelmRelease.
 	// +optional
 	Rollback *Rollback `json:"rollback,omitempty"`
@@ -170,6 +288,13 @@ type HelmReleaseSpec struct {
 	// and information about how they should be merged.
 	ValuesFrom []ValuesReference `json:"valuesFrom,omitempty"`
 
+	// ValuesFromFields holds references to fields of arbitrary objects in the
+	// same namespace as the HelmRelease, of which the values are merged at a
+	// target path into the Helm values. They are merged in order, after
+	// ValuesFrom and Values.
+	// +optional
+	ValuesFromFields []FieldValuesReference `json:"valuesFromFields,omitempty"`
+
 	// Values holds the values for this Helm release.
 	// +optional
 	Values *apiextensionsv1.JSON `json:"values,omitempty"`
@@ -178,10 +303,152 @@ type HelmReleaseSpec struct {
 	// of their definition.
 	// +optional
 	PostRenderers []PostRenderer `json:"postRenderers,omitempty"`
+
+	// CommonMetadata specifies labels and annotations that are applied to all
+	// resources rendered by the Helm chart, after any PostRenderers have been
+	// applied. Hooks are not affected.
+	// +optional
+	CommonMetadata *CommonMetadata `json:"commonMetadata,omitempty"`
 }
 
-// +kubebuilder:object:generate=false
-type ValuesReference = meta.ValuesReference
+// ChartOverride overrides the chart source of a HelmRelease when the cluster
+// attributes of the controller match, allowing a single HelmRelease to
+// resolve to e.g. a region-local mirror or a different release track.
+type ChartOverride struct {
+	// MatchAttributes holds the attributes which must all be equal to the
+	// cluster attributes of the controller (configured using the
+	// --cluster-attributes flag) for the override to apply.
+	// +kubebuilder:validation:MinProperties=1
+	// +required
+	MatchAttributes map[string]string `json:"matchAttributes"`
+
+	// Version overrides the version of the HelmChart template.
+	// Only applies when Chart is set.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// SourceRef overrides the source reference of the HelmChart template.
+	// Only applies when Chart is set.
+	// +optional
+	SourceRef *CrossNamespaceObjectReference `json:"sourceRef,omitempty"`
+
+	// ChartRef overrides the ChartRef. Only applies when ChartRef is set.
+	// +optional
+	ChartRef *CrossNamespaceSourceReference `json:"chartRef,omitempty"`
+}
+
+// Matches returns true if all MatchAttributes are equal to the given
+// attributes.
+func (in ChartOverride) Matches(attributes map[string]string) bool {
+	if len(in.MatchAttributes) == 0 {
+		return false
+	}
+	for k, v := range in.MatchAttributes {
+		if a, ok := attributes[k]; !ok || a != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ValuesReference contains a reference to a resource containing Helm values,
+// and optionally the key they can be found at.
+type ValuesReference struct {
+	// Kind of the values referent, valid values are ('Secret', 'ConfigMap',
+	// 'HelmRelease'). A HelmRelease referent contributes its own composed
+	// values as a layer, which allows values to be shared by a hierarchy of
+	// HelmReleases.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap;HelmRelease
+	// +required
+	Kind string `json:"kind"`
+
+	// Name of the values referent. Should reside in the same namespace as the
+	// referring resource.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +required
+	Name string `json:"name"`
+
+	// ValuesKey is the data key where the values.yaml or a specific value can be
+	// found at. Defaults to 'values.yaml'. Not applicable to HelmRelease
+	// referents.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[\-._a-zA-Z0-9]+$`
+	// +optional
+	ValuesKey string `json:"valuesKey,omitempty"`
+
+	// TargetPath is the YAML dot notation path the value should be merged at. When
+	// set, the ValuesKey is expected to be a single flat value. Defaults to 'None',
+	// which results in the values getting merged at the root.
+	// +kubebuilder:validation:MaxLength=250
+	// +kubebuilder:validation:Pattern=`^([a-zA-Z0-9_\-.\\\/]|\[[0-9]{1,5}\])+$`
+	// +optional
+	TargetPath string `json:"targetPath,omitempty"`
+
+	// Optional marks this ValuesReference as optional. When set, a not found error
+	// for the values reference is ignored, but any ValuesKey, TargetPath or
+	// transient error will still result in a reconciliation failure.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
+
+// GetValuesKey returns the defined ValuesKey, or the default ('values.yaml').
+func (in ValuesReference) GetValuesKey() string {
+	if in.ValuesKey == "" {
+		return "values.yaml"
+	}
+	return in.ValuesKey
+}
+
+// MetaValuesReference returns the reference as a meta.ValuesReference. It
+// must only be used for Secret and ConfigMap referents.
+func (in ValuesReference) MetaValuesReference() meta.ValuesReference {
+	return meta.ValuesReference{
+		Kind:       in.Kind,
+		Name:       in.Name,
+		ValuesKey:  in.ValuesKey,
+		TargetPath: in.TargetPath,
+		Optional:   in.Optional,
+	}
+}
+
+// FieldValuesReference contains a reference to a field of an object in the
+// same namespace as the HelmRelease, for example the status of an
+// infrastructure resource managed by another controller.
+type FieldValuesReference struct {
+	// APIVersion of the referent.
+	// +required
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referent.
+	// +required
+	Kind string `json:"kind"`
+
+	// Name of the referent.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +required
+	Name string `json:"name"`
+
+	// FieldPath is the JSONPath expression selecting the field of the
+	// referent, e.g. '{.spec.clusterIP}'. It must select a single value.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	FieldPath string `json:"fieldPath"`
+
+	// TargetPath is the YAML dot notation path the value of the field is
+	// merged at, e.g. 'service.ip'.
+	// +kubebuilder:validation:MaxLength=250
+	// +kubebuilder:validation:Pattern=`^([a-zA-Z0-9_\-.\\\/]|\[[0-9]{1,5}\])+$`
+	// +required
+	TargetPath string `json:"targetPath"`
+
+	// Optional marks this FieldValuesReference as optional. When set, a not
+	// found referent or field is ignored, but any error will still result in
+	// a reconciliation failure.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
 
 // Kustomize Helm PostRenderer specification.
 type Kustomize struct {
@@ -197,6 +464,17 @@ type Kustomize struct {
 	Images []kustomize.Image `json:"images,omitempty" json:"images,omitempty"`
 }
 
+// CommonMetadata defines the common labels and annotations.
+type CommonMetadata struct {
+	// Annotations to be added to the object's metadata.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Labels to be added to the object's metadata.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
 // PostRenderer contains a Helm PostRenderer specification.
 type PostRenderer struct {
 	// Kustomization to apply as PostRenderer.
@@ -339,7 +617,7 @@ type HelmChartTemplateSpec struct {
 	SourceRef CrossNamespaceObjectReference `json:"sourceRef"`
 
 	// Interval at which to check the v1.Source for updates. Defaults to
-	// 'HelmReleaseSpec.Interval'.
+	// 'HelmReleaseSpec.Interval', or to 10m when that is zero.
 	// +kubebuilder:validation:Type=string
 	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
 	// +optional
@@ -404,6 +682,29 @@ type HelmChartTemplateVerification struct {
 	// trusted public keys.
 	// +optional
 	SecretRef *meta.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// MatchOIDCIdentity specifies the identity matching criteria to use
+	// while verifying an OCI Helm chart which was signed using Cosign keyless
+	// signing. The chart's identity is deemed to be verified if any of the
+	// specified matchers match against the identity.
+	// +optional
+	MatchOIDCIdentity []OIDCIdentityMatch `json:"matchOIDCIdentity,omitempty"`
+}
+
+// OIDCIdentityMatch specifies options for verifying the certificate identity,
+// i.e. the issuer and the subject of the certificate.
+type OIDCIdentityMatch struct {
+	// Issuer specifies the regex pattern to match against to verify
+	// the OIDC issuer in the Fulcio certificate. The pattern must be a
+	// valid Go regular expression.
+	// +required
+	Issuer string `json:"issuer"`
+
+	// Subject specifies the regex pattern to match against to verify
+	// the identity subject in the Fulcio certificate. The pattern must
+	// be a valid Go regular expression.
+	// +required
+	Subject string `json:"subject"`
 }
 
 // Remediation defines a consistent interface for InstallRemediation and
@@ -419,6 +720,38 @@ type Remediation interface {
 	RetriesExhausted(hr *HelmRelease) bool
 }
 
+// ResumeVerification holds the configuration for the verification pass
+// performed when a HelmRelease is resumed after a long suspension.
+type ResumeVerification struct {
+	// Threshold is the minimum duration of the suspension after which
+	// resuming the HelmRelease first renders the release with a dry-run, and
+	// reports the pending changes.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +required
+	Threshold metav1.Duration `json:"threshold"`
+
+	// AutoProceed makes the controller proceed with the release right after
+	// the pending changes are reported. When false, the release is only
+	// made after it is confirmed using the confirm resume request annotation.
+	// +optional
+	AutoProceed bool `json:"autoProceed,omitempty"`
+}
+
+// Preview holds the configuration for HelmReleases stamped for an ephemeral
+// preview environment.
+type Preview struct {
+	// TTL is the duration after which the Helm release of a HelmRelease
+	// stamped for a preview environment is uninstalled, counted from the
+	// creation of the HelmRelease. The HelmRelease itself is kept, and
+	// marked as stalled, until it is deleted. When not set, the Helm release
+	// is not uninstalled automatically.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
 // Install holds the configuration for Helm install actions performed for this
 // HelmRelease.
 type Install struct {
@@ -464,6 +797,11 @@ type Install struct {
 	// +optional
 	DisableSchemaValidation bool `json:"disableSchemaValidation,omitempty"`
 
+	// SubNotes tells the Helm install action to render the NOTES.txt of the
+	// subcharts, in addition to the NOTES.txt of the parent chart.
+	// +optional
+	SubNotes bool `json:"subNotes,omitempty"`
+
 	// Replace tells the Helm install action to re-use the 'ReleaseName', but only
 	// if that name is a deleted release which remains in the history.
 	// +optional
@@ -504,6 +842,13 @@ type Install struct {
 	// On uninstall, the namespace will not be garbage collected.
 	// +optional
 	CreateNamespace bool `json:"createNamespace,omitempty"`
+
+	// ExtraEnv holds the environment variables exposed to the hooks of the
+	// Helm install action. The variables are written to a Secret named
+	// '<release-name>-hook-env' in the namespace of the release, which hooks
+	// can consume using e.g. 'envFrom'.
+	// +optional
+	ExtraEnv []ExtraEnvVar `json:"extraEnv,omitempty"`
 }
 
 // GetTimeout returns the configured timeout for the Helm install action,
@@ -587,6 +932,22 @@ func (in InstallRemediation) RetriesExhausted(hr *HelmRelease) bool {
 	return in.Retries >= 0 && in.GetFailureCount(hr) > int64(in.Retries)
 }
 
+// ExtraEnvVar is an environment variable exposed to the hooks of a Helm
+// action, with its value read from a Secret.
+type ExtraEnvVar struct {
+	// Name of the environment variable.
+	// +kubebuilder:validation:Pattern="^[A-Za-z_][A-Za-z0-9_]*$"
+	// +required
+	Name string `json:"name"`
+
+	// SecretKeyRef is a reference to a Secret in the same namespace as the
+	// HelmRelease, containing the value of the environment variable. The
+	// value is read from the key with the name of the environment variable,
+	// unless another key is specified.
+	// +required
+	SecretKeyRef meta.SecretKeyReference `json:"secretKeyRef"`
+}
+
 // CRDsPolicy defines the install/upgrade approach to use for CRDs when
 // installing or upgrading a HelmRelease.
 type CRDsPolicy string
@@ -647,6 +1008,11 @@ type Upgrade struct {
 	// +optional
 	DisableSchemaValidation bool `json:"disableSchemaValidation,omitempty"`
 
+	// SubNotes tells the Helm upgrade action to render the NOTES.txt of the
+	// subcharts, in addition to the NOTES.txt of the parent chart.
+	// +optional
+	SubNotes bool `json:"subNotes,omitempty"`
+
 	// Force forces resource updates through a replacement strategy.
 	// +optional
 	Force bool `json:"force,omitempty"`
@@ -681,6 +1047,23 @@ type Upgrade struct {
 	// +kubebuilder:validation:Enum=Skip;Create;CreateReplace
 	// +optional
 	CRDs CRDsPolicy `json:"crds,omitempty"`
+
+	// ExtraEnv holds the environment variables exposed to the hooks of the
+	// Helm upgrade action. The variables are written to a Secret named
+	// '<release-name>-hook-env' in the namespace of the release, which hooks
+	// can consume using e.g. 'envFrom'.
+	// +optional
+	ExtraEnv []ExtraEnvVar `json:"extraEnv,omitempty"`
+
+	// VerificationWindow is the duration after a successful Helm upgrade
+	// action during which the controller keeps monitoring the health of the
+	// resources of the release. When the resources become unhealthy within
+	// the window, the upgrade is counted as failed and the release is rolled
+	// back to the previous release.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	VerificationWindow *metav1.Duration `json:"verificationWindow,omitempty"`
 }
 
 // GetTimeout returns the configured timeout for the Helm upgrade action, or the
@@ -724,6 +1107,28 @@ type UpgradeRemediation struct {
 	// +kubebuilder:validation:Enum=rollback;uninstall
 	// +optional
 	Strategy *RemediationStrategy `json:"strategy,omitempty"`
+
+	// MaxRollbackFailures is the number of consecutive times the rollback
+	// remediation may fail, before the controller stops attempting to
+	// release and remediate. The HelmRelease is then marked as Stalled with
+	// reason 'RemediationExhausted', until the failures are acknowledged
+	// using the 'reconcile.fluxcd.io/acknowledgeRemediationAt' annotation.
+	// Defaults to '0', which disables this limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRollbackFailures int `json:"maxRollbackFailures,omitempty"`
+}
+
+// GetMaxRollbackFailures returns the number of consecutive times the rollback
+// remediation may fail.
+func (in UpgradeRemediation) GetMaxRollbackFailures() int {
+	return in.MaxRollbackFailures
+}
+
+// RollbackFailuresExhausted returns true if the rollback remediation failed
+// the maximum number of consecutive times.
+func (in UpgradeRemediation) RollbackFailuresExhausted(hr *HelmRelease) bool {
+	return in.MaxRollbackFailures > 0 && hr.Status.RollbackFailures >= int64(in.MaxRollbackFailures)
 }
 
 // GetRetries returns the number of retries that should be attempted on
@@ -773,6 +1178,55 @@ func (in UpgradeRemediation) RetriesExhausted(hr *HelmRelease) bool {
 	return in.Retries >= 0 && in.GetFailureCount(hr) > int64(in.Retries)
 }
 
+// ArtifactVerification is the strictness of the verification of a chart
+// artifact.
+type ArtifactVerification string
+
+const (
+	// ArtifactVerificationEnforce refuses to release a chart artifact of
+	// which the integrity can not be verified.
+	ArtifactVerificationEnforce ArtifactVerification = "enforce"
+
+	// ArtifactVerificationWarn releases a chart artifact of which the
+	// integrity can not be verified, and warns about it.
+	ArtifactVerificationWarn ArtifactVerification = "warn"
+
+	// ArtifactVerificationDisabled disables the verification of the
+	// integrity and signature of a chart artifact.
+	ArtifactVerificationDisabled ArtifactVerification = "disabled"
+)
+
+// strictness returns the strictness of the ArtifactVerification, which is
+// higher for a stricter verification. An empty or unknown value has the
+// lowest strictness.
+func (in ArtifactVerification) strictness() int {
+	switch in {
+	case ArtifactVerificationEnforce:
+		return 3
+	case ArtifactVerificationWarn:
+		return 2
+	case ArtifactVerificationDisabled:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// HistoryPolicy determines which revisions of a release are retained in the
+// Helm storage.
+type HistoryPolicy string
+
+const (
+	// AllHistoryPolicy retains the most recent revisions up to the maximum
+	// history, regardless of their status.
+	AllHistoryPolicy HistoryPolicy = "all"
+
+	// PruneFailedHistoryPolicy prunes all failed and pending revisions except
+	// for the latest, and retains the most recent successful revisions up to
+	// the maximum history.
+	PruneFailedHistoryPolicy HistoryPolicy = "pruneFailed"
+)
+
 // RemediationStrategy returns the strategy to use to remediate a failed install
 // or upgrade.
 type RemediationStrategy string
@@ -820,6 +1274,170 @@ func (in Test) GetTimeout(defaultTimeout metav1.Duration) metav1.Duration {
 	return *in.Timeout
 }
 
+// HealthCheck holds the configuration for the health assessment of the
+// resources of the release for this HelmRelease.
+type HealthCheck struct {
+	// Enable enables the kstatus based health assessment of the resources in
+	// the manifest of the release, after an Helm install or upgrade action
+	// has been performed. The result is reflected in the Healthy condition.
+	// +optional
+	Enable bool `json:"enable,omitempty"`
+
+	// Timeout is the time to wait for the resources to become healthy.
+	// Defaults to 'HelmReleaseSpec.Timeout'.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// GetTimeout returns the configured timeout for the health assessment,
+// or the given default.
+func (in HealthCheck) GetTimeout(defaultTimeout metav1.Duration) metav1.Duration {
+	if in.Timeout == nil {
+		return defaultTimeout
+	}
+	return *in.Timeout
+}
+
+// PostDeployChecks holds the configuration for the checks run against the
+// endpoints of the release after it has been deployed.
+type PostDeployChecks struct {
+	// HTTP is a list of checks which pass when an HTTP GET request to their
+	// URL returns the expected status code.
+	// +optional
+	HTTP []HTTPCheck `json:"http,omitempty"`
+
+	// TCP is a list of checks which pass when a TCP connection to their
+	// address can be established.
+	// +optional
+	TCP []TCPCheck `json:"tcp,omitempty"`
+
+	// Timeout is the time to wait for all checks to pass, during which
+	// failing checks are retried. Defaults to 'HelmReleaseSpec.Timeout'.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// GetTimeout returns the configured timeout for the post-deploy checks,
+// or the given default.
+func (in PostDeployChecks) GetTimeout(defaultTimeout metav1.Duration) metav1.Duration {
+	if in.Timeout == nil {
+		return defaultTimeout
+	}
+	return *in.Timeout
+}
+
+// IsEmpty returns true if no checks are configured.
+func (in PostDeployChecks) IsEmpty() bool {
+	return len(in.HTTP) == 0 && len(in.TCP) == 0
+}
+
+// HTTPCheck holds the configuration of an HTTP post-deploy check.
+type HTTPCheck struct {
+	// URL is the HTTP(S) URL the GET request is made to, e.g.
+	// 'http://podinfo.default.svc:9898/readyz'.
+	// +kubebuilder:validation:Pattern="^https?://.+$"
+	// +required
+	URL string `json:"url"`
+
+	// ExpectedStatus is the HTTP status code the response must have for the
+	// check to pass. Defaults to any 2xx status code.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	ExpectedStatus int `json:"expectedStatus,omitempty"`
+
+	// Timeout is the time to wait for a single request to complete.
+	// Defaults to '5s'.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// TCPCheck holds the configuration of a TCP post-deploy check.
+type TCPCheck struct {
+	// Address is the 'host:port' address the connection is made to, e.g.
+	// 'redis.default.svc:6379'.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Address string `json:"address"`
+
+	// Timeout is the time to wait for a single connection attempt to
+	// succeed. Defaults to '5s'.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// SecuritySeverity is the severity of a finding of a security scan.
+type SecuritySeverity string
+
+const (
+	// SecuritySeverityLow is the severity of low risk findings.
+	SecuritySeverityLow SecuritySeverity = "LOW"
+	// SecuritySeverityMedium is the severity of medium risk findings.
+	SecuritySeverityMedium SecuritySeverity = "MEDIUM"
+	// SecuritySeverityHigh is the severity of high risk findings.
+	SecuritySeverityHigh SecuritySeverity = "HIGH"
+	// SecuritySeverityCritical is the severity of critical risk findings.
+	SecuritySeverityCritical SecuritySeverity = "CRITICAL"
+)
+
+// SecurityScanAction is the action taken when a security scan reports
+// findings at or above the severity threshold.
+type SecurityScanAction string
+
+const (
+	// SecurityScanActionBlock prevents the release from being applied.
+	SecurityScanActionBlock SecurityScanAction = "block"
+	// SecurityScanActionWarn applies the release, and reports the findings
+	// in the SecurityScan condition.
+	SecurityScanActionWarn SecurityScanAction = "warn"
+)
+
+// SecurityScan holds the configuration for the scan of the rendered manifests
+// of a release before they are applied.
+type SecurityScan struct {
+	// Severity is the minimum severity of the findings which fail the scan.
+	// Defaults to 'HIGH'.
+	// +kubebuilder:validation:Enum=LOW;MEDIUM;HIGH;CRITICAL
+	// +kubebuilder:default:=HIGH
+	// +optional
+	Severity SecuritySeverity `json:"severity,omitempty"`
+
+	// Action is the action taken when the scan fails, or the manifests can
+	// not be scanned. 'block' prevents the release from being applied, while
+	// 'warn' only reports the failure in the SecurityScan condition.
+	// Defaults to 'block'.
+	// +kubebuilder:validation:Enum=block;warn
+	// +kubebuilder:default:=block
+	// +optional
+	Action SecurityScanAction `json:"action,omitempty"`
+}
+
+// GetSeverity returns the configured severity threshold, or the default of
+// SecuritySeverityHigh.
+func (in SecurityScan) GetSeverity() SecuritySeverity {
+	if in.Severity == "" {
+		return SecuritySeverityHigh
+	}
+	return in.Severity
+}
+
+// GetAction returns the configured action, or the default of
+// SecurityScanActionBlock.
+func (in SecurityScan) GetAction() SecurityScanAction {
+	if in.Action == "" {
+		return SecurityScanActionBlock
+	}
+	return in.Action
+}
+
 // Filter holds the configuration for individual Helm test filters.
 type Filter struct {
 	// Name is the name of the test.
@@ -920,6 +1538,19 @@ type Uninstall struct {
 	// +kubebuilder:validation:Enum=background;foreground;orphan
 	// +optional
 	DeletionPropagation *string `json:"deletionPropagation,omitempty"`
+
+	// OrphanTimeout is the duration after which the Helm release is
+	// orphaned, and the finalization of the HelmRelease allowed to proceed,
+	// when the remote cluster referenced by 'HelmReleaseSpec.KubeConfig'
+	// cannot be reached to uninstall the release. For example, because the
+	// cluster no longer exists. Other uninstall failures never orphan the
+	// release. The duration is counted from the deletion of the HelmRelease.
+	// When not set, the deletion of the HelmRelease is blocked until the
+	// release has been uninstalled.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	OrphanTimeout *metav1.Duration `json:"orphanTimeout,omitempty"`
 }
 
 // GetTimeout returns the configured timeout for the Helm uninstall action, or
@@ -940,6 +1571,39 @@ func (in Uninstall) GetDeletionPropagation() string {
 	return *in.DeletionPropagation
 }
 
+// Wait holds the configuration for waiting on cluster resources to be ready
+// before a Helm action is performed for this HelmRelease.
+type Wait struct {
+	// For is a list of references to cluster resources which must be ready
+	// before a Helm action is performed, e.g. a CustomResourceDefinition
+	// required by the chart, or a Secret referenced by the values.
+	// The resources are looked up in the cluster the release is made to,
+	// with the permissions of the ServiceAccount used for the release.
+	// +required
+	For []WaitForReference `json:"for"`
+}
+
+// NodeRequirements holds the capabilities the nodes of the target cluster
+// must provide before a Helm action is performed for this HelmRelease. Only
+// schedulable nodes are taken into account.
+type NodeRequirements struct {
+	// Architectures is a list of CPU architectures, as reported by the
+	// 'kubernetes.io/arch' label of the nodes, which must each be provided by
+	// at least one node, e.g. 'arm64'.
+	// +optional
+	Architectures []string `json:"architectures,omitempty"`
+
+	// NodeSelectors is a list of label selectors which must each match at
+	// least one node, e.g. to require the presence of GPU nodes.
+	// +optional
+	NodeSelectors []metav1.LabelSelector `json:"nodeSelectors,omitempty"`
+}
+
+// IsEmpty returns true if no requirements are configured.
+func (in NodeRequirements) IsEmpty() bool {
+	return len(in.Architectures) == 0 && len(in.NodeSelectors) == 0
+}
+
 // ReleaseAction is the action to perform a Helm release.
 type ReleaseAction string
 
@@ -961,6 +1625,18 @@ type HelmReleaseStatus struct {
 	// +optional
 	ObservedPostRenderersDigest string `json:"observedPostRenderersDigest,omitempty"`
 
+	// ObservedSourceArtifactRevision is the revision of the source artifact
+	// of the last successful reconciliation attempt.
+	// +optional
+	ObservedSourceArtifactRevision string `json:"observedSourceArtifactRevision,omitempty"`
+
+	// SourceArtifact holds the metadata of the source artifact of the last
+	// successful reconciliation attempt. It allows the chart to be
+	// reproduced, even after the artifact has been garbage collected by the
+	// source-controller.
+	// +optional
+	SourceArtifact *SourceArtifact `json:"sourceArtifact,omitempty"`
+
 	// LastAttemptedGeneration is the last generation the controller attempted
 	// to reconcile.
 	// +optional
@@ -983,11 +1659,34 @@ type HelmReleaseStatus struct {
 	// +optional
 	StorageNamespace string `json:"storageNamespace,omitempty"`
 
+	// StorageDriver is the Helm storage driver of the Helm release storage
+	// for the current release.
+	// +optional
+	StorageDriver string `json:"storageDriver,omitempty"`
+
 	// History holds the history of Helm releases performed for this HelmRelease
 	// up to the last successfully completed release.
 	// +optional
 	History Snapshots `json:"history,omitempty"`
 
+	// Inventory contains the list of Kubernetes resource object references
+	// of the manifest of the latest Helm release in the History.
+	// +optional
+	Inventory *ResourceInventory `json:"inventory,omitempty"`
+
+	// LastReleaseNotes is the rendered NOTES.txt of the last successful Helm
+	// release, truncated to 1024 characters.
+	// +optional
+	LastReleaseNotes string `json:"lastReleaseNotes,omitempty"`
+
+	// InFlightAction is the journal entry of the Helm action which is being
+	// performed for this HelmRelease. It is set before the action is started,
+	// and removed once it has finished. When it is present at the start of a
+	// reconciliation, the action has been interrupted (e.g. by a restart of
+	// the controller), and the Helm release may be in an incomplete state.
+	// +optional
+	InFlightAction *InFlightAction `json:"inFlightAction,omitempty"`
+
 	// LastAttemptedReleaseAction is the last release action performed for this
 	// HelmRelease. It is used to determine the active remediation strategy.
 	// +kubebuilder:validation:Enum=install;upgrade
@@ -1009,6 +1708,13 @@ type HelmReleaseStatus struct {
 	// +optional
 	UpgradeFailures int64 `json:"upgradeFailures,omitempty"`
 
+	// RollbackFailures is the number of consecutive failed rollback
+	// remediations. Contrary to the other failure counts, it is only reset by
+	// a successful rollback, or by acknowledging the failures using the
+	// 'reconcile.fluxcd.io/acknowledgeRemediationAt' annotation.
+	// +optional
+	RollbackFailures int64 `json:"rollbackFailures,omitempty"`
+
 	// LastAttemptedRevision is the Source revision of the last reconciliation
 	// attempt. For OCIRepository  sources, the 12 first characters of the digest are
 	// appended to the chart version e.g. "1.2.3+1234567890ab".
@@ -1046,12 +1752,98 @@ type HelmReleaseStatus struct {
 	// +optional
 	LastHandledResetAt string `json:"lastHandledResetAt,omitempty"`
 
+	// LastHandledDryRunAt holds the value of the most recent dry-run request
+	// value, so a change of the annotation value can be detected.
+	// +optional
+	LastHandledDryRunAt string `json:"lastHandledDryRunAt,omitempty"`
+
+	// LastHandledAcknowledgeRemediationAt holds the value of the most recent
+	// remediation acknowledge request value, so a change of the annotation
+	// value can be detected.
+	// +optional
+	LastHandledAcknowledgeRemediationAt string `json:"lastHandledAcknowledgeRemediationAt,omitempty"`
+
+	// LastHandledSupportBundleAt holds the value of the most recent support
+	// bundle request value, so a change of the annotation value can be
+	// detected.
+	// +optional
+	LastHandledSupportBundleAt string `json:"lastHandledSupportBundleAt,omitempty"`
+
+	// LastHandledEffectiveConfigAt holds the value of the most recent
+	// effective configuration request value, so a change of the annotation
+	// value can be detected.
+	// +optional
+	LastHandledEffectiveConfigAt string `json:"lastHandledEffectiveConfigAt,omitempty"`
+
+	// LastHandledConfirmResumeAt holds the value of the most recent confirm
+	// resume request value, so a change of the annotation value can be
+	// detected.
+	// +optional
+	LastHandledConfirmResumeAt string `json:"lastHandledConfirmResumeAt,omitempty"`
+
 	meta.ReconcileRequestStatus `json:",inline"`
 }
 
-// ClearHistory clears the History.
+// SourceArtifact holds the metadata of a source artifact.
+type SourceArtifact struct {
+	// URL is the HTTP address of the artifact as exposed by the
+	// source-controller.
+	// +required
+	URL string `json:"url"`
+
+	// Revision is a human-readable identifier traceable in the origin source
+	// system.
+	// +required
+	Revision string `json:"revision"`
+
+	// Digest is the digest of the artifact in the form of
+	// '<algorithm>:<checksum>'.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// LastUpdateTime is the timestamp corresponding to the last update of
+	// the artifact.
+	// +required
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+}
+
+// InFlightAction is a journal entry of a Helm action performed for a
+// HelmRelease.
+type InFlightAction struct {
+	// Name of the action, e.g. 'install' or 'upgrade'.
+	// +required
+	Name string `json:"name"`
+
+	// ReleaseName is the name of the Helm release the action is performed
+	// for.
+	// +required
+	ReleaseName string `json:"releaseName"`
+
+	// ReleaseNamespace is the namespace of the Helm release the action is
+	// performed for.
+	// +required
+	ReleaseNamespace string `json:"releaseNamespace"`
+
+	// Revision is the revision of the latest Helm release known when the
+	// action was started, or zero if there was none.
+	// +optional
+	Revision int `json:"revision,omitempty"`
+
+	// StartedAt is the time at which the action was started.
+	// +required
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// Holder is the identity of the controller instance which started the
+	// action, e.g. the name of its Pod.
+	// +optional
+	Holder string `json:"holder,omitempty"`
+}
+
+// ClearHistory clears the History, and the Inventory of the latest release
+// in the History.
 func (in *HelmReleaseStatus) ClearHistory() {
 	in.History = nil
+	in.Inventory = nil
 }
 
 // ClearFailures clears the failure counters.
@@ -1080,6 +1872,18 @@ const (
 	// SourceIndexKey is the key used for indexing HelmReleases based on
 	// their sources.
 	SourceIndexKey string = ".metadata.source"
+
+	// DependsOnIndexKey is the key used for indexing HelmReleases based on
+	// the HelmReleases they depend on.
+	DependsOnIndexKey string = ".metadata.dependsOn"
+
+	// ValuesFromIndexKey is the key used for indexing HelmReleases based on
+	// the HelmReleases they import values from.
+	ValuesFromIndexKey string = ".metadata.valuesFrom"
+
+	// ReleaseNameIndexKey is the key used for indexing HelmReleases based on
+	// the Helm release they target.
+	ReleaseNameIndexKey string = ".metadata.releaseName"
 )
 
 // +genclient
@@ -1101,6 +1905,17 @@ type HelmRelease struct {
 	Status HelmReleaseStatus `json:"status,omitempty"`
 }
 
+// GetChartOverride returns the first ChartOverride matching the given
+// cluster attributes, or nil.
+func (in *HelmRelease) GetChartOverride(attributes map[string]string) *ChartOverride {
+	for i := range in.Spec.ChartOverrides {
+		if in.Spec.ChartOverrides[i].Matches(attributes) {
+			return &in.Spec.ChartOverrides[i]
+		}
+	}
+	return nil
+}
+
 // GetDriftDetection returns the configuration for detecting and handling
 // differences between the manifest in the Helm storage and the resources
 // currently existing in the cluster.
@@ -1129,6 +1944,12 @@ func (in *HelmRelease) GetUpgrade() Upgrade {
 	return *in.Spec.Upgrade
 }
 
+// HasExtraEnv returns true if environment variables are configured for the
+// hooks of the Helm install or upgrade actions of this HelmRelease.
+func (in *HelmRelease) HasExtraEnv() bool {
+	return len(in.GetInstall().ExtraEnv) > 0 || len(in.GetUpgrade().ExtraEnv) > 0
+}
+
 // GetTest returns the configuration for Helm test actions for this HelmRelease.
 func (in *HelmRelease) GetTest() Test {
 	if in.Spec.Test == nil {
@@ -1137,6 +1958,33 @@ func (in *HelmRelease) GetTest() Test {
 	return *in.Spec.Test
 }
 
+// GetPostDeployChecks returns the configuration for the post-deploy checks
+// of the release, or an empty PostDeployChecks.
+func (in *HelmRelease) GetPostDeployChecks() PostDeployChecks {
+	if in.Spec.PostDeployChecks == nil {
+		return PostDeployChecks{}
+	}
+	return *in.Spec.PostDeployChecks
+}
+
+// GetNodeRequirements returns the capabilities the nodes of the target
+// cluster must provide, or an empty NodeRequirements.
+func (in *HelmRelease) GetNodeRequirements() NodeRequirements {
+	if in.Spec.NodeRequirements == nil {
+		return NodeRequirements{}
+	}
+	return *in.Spec.NodeRequirements
+}
+
+// GetHealthCheck returns the configuration for the health assessment of the
+// resources of the release for this HelmRelease.
+func (in *HelmRelease) GetHealthCheck() HealthCheck {
+	if in.Spec.HealthCheck == nil {
+		return HealthCheck{}
+	}
+	return *in.Spec.HealthCheck
+}
+
 // GetRollback returns the configuration for Helm rollback actions for this
 // HelmRelease.
 func (in *HelmRelease) GetRollback() Rollback {
@@ -1168,12 +2016,49 @@ func (in HelmRelease) GetActiveRemediation() Remediation {
 	}
 }
 
+// IsSuspended returns true if the reconciliation of the HelmRelease is
+// suspended, either by Spec.Suspend or by the SuspendAnnotation.
+func (in HelmRelease) IsSuspended() bool {
+	return in.Spec.Suspend || in.GetAnnotations()[SuspendAnnotation] == SuspendAnnotationAll
+}
+
+// IsDeletionProtected returns true if the HelmRelease is marked as critical
+// by the CriticalAnnotation, without the BreakGlassAnnotation allowing its
+// deletion.
+func (in HelmRelease) IsDeletionProtected() bool {
+	return in.GetAnnotations()[CriticalAnnotation] == "true" &&
+		in.GetAnnotations()[BreakGlassAnnotation] != "true"
+}
+
+// GetConcurrencyWeight returns the number of concurrent reconciliation slots
+// the HelmRelease occupies as configured by the ConcurrencyWeightAnnotation.
+// It defaults to 1 if the annotation is absent or not a positive integer.
+func (in HelmRelease) GetConcurrencyWeight() int {
+	weight, err := strconv.Atoi(in.GetAnnotations()[ConcurrencyWeightAnnotation])
+	if err != nil || weight < 1 {
+		return 1
+	}
+	return weight
+}
+
+// IsRemediationSuspended returns true if the remediation of failed releases
+// of the HelmRelease is suspended by the SuspendAnnotation.
+func (in HelmRelease) IsRemediationSuspended() bool {
+	return in.GetAnnotations()[SuspendAnnotation] == SuspendAnnotationRemediation
+}
+
 // GetRequeueAfter returns the duration after which the HelmRelease
 // must be reconciled again.
 func (in HelmRelease) GetRequeueAfter() time.Duration {
 	return in.Spec.Interval.Duration
 }
 
+// IsEventDriven returns true if the HelmRelease has an interval of zero, in
+// which case it is not reconciled periodically but only on changes.
+func (in HelmRelease) IsEventDriven() bool {
+	return in.Spec.Interval.Duration == 0
+}
+
 // GetValues unmarshals the raw values to a map[string]interface{} and returns
 // the result.
 func (in HelmRelease) GetValues() map[string]interface{} {
@@ -1188,23 +2073,50 @@ func (in HelmRelease) GetValues() map[string]interface{} {
 // '[TargetNamespace-]Name'.
 func (in HelmRelease) GetReleaseName() string {
 	if in.Spec.ReleaseName != "" {
-		return in.Spec.ReleaseName
+		return withPreviewSuffix(in.Spec.ReleaseName, in.GetPreviewID())
 	}
 	if in.Spec.TargetNamespace != "" {
-		return strings.Join([]string{in.Spec.TargetNamespace, in.Name}, "-")
+		return withPreviewSuffix(strings.Join([]string{in.Spec.TargetNamespace, in.Name}, "-"), in.GetPreviewID())
 	}
-	return in.Name
+	return withPreviewSuffix(in.Name, in.GetPreviewID())
 }
 
 // GetReleaseNamespace returns the configured TargetNamespace, or the namespace
-// of the HelmRelease.
+// of the HelmRelease. For a HelmRelease stamped for a preview environment,
+// the TargetNamespace is suffixed with the preview identifier.
 func (in HelmRelease) GetReleaseNamespace() string {
 	if in.Spec.TargetNamespace != "" {
-		return in.Spec.TargetNamespace
+		return withPreviewSuffix(in.Spec.TargetNamespace, in.GetPreviewID())
 	}
 	return in.Namespace
 }
 
+// GetPreviewID returns the identifier of the preview environment the
+// HelmRelease is stamped for using the PreviewAnnotation, or an empty string.
+func (in HelmRelease) GetPreviewID() string {
+	return in.GetAnnotations()[PreviewAnnotation]
+}
+
+// GetPreviewExpiry returns the time after which the Helm release of the
+// HelmRelease stamped for a preview environment must be uninstalled, and true. If the HelmRelease is not
+// stamped for a preview environment, or no TTL is configured, it returns
+// false.
+func (in HelmRelease) GetPreviewExpiry() (time.Time, bool) {
+	if in.GetPreviewID() == "" || in.Spec.Preview == nil || in.Spec.Preview.TTL == nil {
+		return time.Time{}, false
+	}
+	return in.CreationTimestamp.Add(in.Spec.Preview.TTL.Duration), true
+}
+
+// withPreviewSuffix returns the given name suffixed with the preview
+// identifier, or the name as is if the identifier is empty.
+func withPreviewSuffix(name, previewID string) string {
+	if previewID == "" {
+		return name
+	}
+	return strings.Join([]string{name, previewID}, "-")
+}
+
 // GetStorageNamespace returns the configured StorageNamespace for helm, or the namespace
 // of the HelmRelease.
 func (in HelmRelease) GetStorageNamespace() string {
@@ -1219,6 +2131,17 @@ func (in HelmRelease) GetHelmChartName() string {
 	return strings.Join([]string{in.Namespace, in.Name}, "-")
 }
 
+// GetArtifactVerification returns the configured ArtifactVerification if
+// it is stricter than the given minimum, or the minimum. This prevents the
+// verification from being relaxed below the minimum configured by the
+// operator of the controller.
+func (in HelmRelease) GetArtifactVerification(minimum ArtifactVerification) ArtifactVerification {
+	if in.Spec.ArtifactVerification.strictness() > minimum.strictness() {
+		return in.Spec.ArtifactVerification
+	}
+	return minimum
+}
+
 // GetTimeout returns the configured Timeout, or the default of 300s.
 func (in HelmRelease) GetTimeout() metav1.Duration {
 	if in.Spec.Timeout == nil {
@@ -1235,6 +2158,15 @@ func (in HelmRelease) GetMaxHistory() int {
 	return *in.Spec.MaxHistory
 }
 
+// GetHistoryPolicy returns the configured HistoryPolicy, or the default of
+// AllHistoryPolicy.
+func (in HelmRelease) GetHistoryPolicy() HistoryPolicy {
+	if in.Spec.HistoryPolicy == "" {
+		return AllHistoryPolicy
+	}
+	return in.Spec.HistoryPolicy
+}
+
 // UsePersistentClient returns the configured PersistentClient, or the default
 // of true.
 func (in HelmRelease) UsePersistentClient() bool {
@@ -1246,7 +2178,14 @@ func (in HelmRelease) UsePersistentClient() bool {
 
 // GetDependsOn returns the list of dependencies across-namespaces.
 func (in HelmRelease) GetDependsOn() []meta.NamespacedObjectReference {
-	return in.Spec.DependsOn
+	if in.Spec.DependsOn == nil {
+		return nil
+	}
+	deps := make([]meta.NamespacedObjectReference, 0, len(in.Spec.DependsOn))
+	for _, d := range in.Spec.DependsOn {
+		deps = append(deps, d.NamespacedObjectReference())
+	}
+	return deps
 }
 
 // GetConditions returns the status conditions of the object.
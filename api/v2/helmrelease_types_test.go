@@ -0,0 +1,294 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHelmRelease_GetReleaseName(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          HelmReleaseSpec
+		previewID     string
+		wantName      string
+		wantNamespace string
+	}{
+		{
+			name:          "defaults to name and namespace",
+			wantName:      "podinfo",
+			wantNamespace: "default",
+		},
+		{
+			name:          "prefixes target namespace",
+			spec:          HelmReleaseSpec{TargetNamespace: "apps"},
+			wantName:      "apps-podinfo",
+			wantNamespace: "apps",
+		},
+		{
+			name:          "release name",
+			spec:          HelmReleaseSpec{ReleaseName: "release", TargetNamespace: "apps"},
+			wantName:      "release",
+			wantNamespace: "apps",
+		},
+		{
+			name:          "preview without target namespace",
+			previewID:     "pr-1",
+			wantName:      "podinfo-pr-1",
+			wantNamespace: "default",
+		},
+		{
+			name:          "preview with target namespace",
+			spec:          HelmReleaseSpec{TargetNamespace: "apps"},
+			previewID:     "pr-1",
+			wantName:      "apps-podinfo-pr-1",
+			wantNamespace: "apps-pr-1",
+		},
+		{
+			name:          "preview with release name",
+			spec:          HelmReleaseSpec{ReleaseName: "release"},
+			previewID:     "pr-1",
+			wantName:      "release-pr-1",
+			wantNamespace: "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "podinfo",
+					Namespace: "default",
+				},
+				Spec: tt.spec,
+			}
+			if tt.previewID != "" {
+				obj.SetAnnotations(map[string]string{PreviewAnnotation: tt.previewID})
+			}
+
+			if got := obj.GetReleaseName(); got != tt.wantName {
+				t.Errorf("GetReleaseName() = %q, want %q", got, tt.wantName)
+			}
+			if got := obj.GetReleaseNamespace(); got != tt.wantNamespace {
+				t.Errorf("GetReleaseNamespace() = %q, want %q", got, tt.wantNamespace)
+			}
+		})
+	}
+}
+
+func TestHelmRelease_GetPreviewExpiry(t *testing.T) {
+	created := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	ttl := &Preview{TTL: &metav1.Duration{Duration: 24 * time.Hour}}
+
+	tests := []struct {
+		name       string
+		previewID  string
+		preview    *Preview
+		wantExpiry time.Time
+		wantOK     bool
+	}{
+		{
+			name:       "preview with TTL",
+			previewID:  "pr-1",
+			preview:    ttl,
+			wantExpiry: created.Add(24 * time.Hour),
+			wantOK:     true,
+		},
+		{
+			name:    "TTL without preview",
+			preview: ttl,
+		},
+		{
+			name:      "preview without TTL",
+			previewID: "pr-1",
+			preview:   &Preview{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: created,
+					Annotations:       map[string]string{PreviewAnnotation: tt.previewID},
+				},
+				Spec: HelmReleaseSpec{Preview: tt.preview},
+			}
+
+			got, ok := obj.GetPreviewExpiry()
+			if ok != tt.wantOK {
+				t.Fatalf("GetPreviewExpiry() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !got.Equal(tt.wantExpiry) {
+				t.Errorf("GetPreviewExpiry() = %v, want %v", got, tt.wantExpiry)
+			}
+		})
+	}
+}
+
+func TestHelmRelease_IsSuspended(t *testing.T) {
+	tests := []struct {
+		name                     string
+		suspend                  bool
+		annotation               string
+		wantSuspended            bool
+		wantRemediationSuspended bool
+	}{
+		{
+			name: "not suspended",
+		},
+		{
+			name:          "suspended by spec",
+			suspend:       true,
+			wantSuspended: true,
+		},
+		{
+			name:          "suspended by annotation",
+			annotation:    SuspendAnnotationAll,
+			wantSuspended: true,
+		},
+		{
+			name:                     "remediation suspended by annotation",
+			annotation:               SuspendAnnotationRemediation,
+			wantRemediationSuspended: true,
+		},
+		{
+			name:       "unknown annotation value",
+			annotation: "false",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := HelmRelease{
+				Spec: HelmReleaseSpec{Suspend: tt.suspend},
+			}
+			if tt.annotation != "" {
+				obj.SetAnnotations(map[string]string{SuspendAnnotation: tt.annotation})
+			}
+
+			if got := obj.IsSuspended(); got != tt.wantSuspended {
+				t.Errorf("IsSuspended() = %v, want %v", got, tt.wantSuspended)
+			}
+			if got := obj.IsRemediationSuspended(); got != tt.wantRemediationSuspended {
+				t.Errorf("IsRemediationSuspended() = %v, want %v", got, tt.wantRemediationSuspended)
+			}
+		})
+	}
+}
+
+func TestHelmRelease_IsDeletionProtected(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			name: "without annotations",
+		},
+		{
+			name:        "critical",
+			annotations: map[string]string{CriticalAnnotation: "true"},
+			want:        true,
+		},
+		{
+			name:        "critical with break-glass",
+			annotations: map[string]string{CriticalAnnotation: "true", BreakGlassAnnotation: "true"},
+		},
+		{
+			name:        "not critical",
+			annotations: map[string]string{CriticalAnnotation: "false"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := HelmRelease{}
+			obj.SetAnnotations(tt.annotations)
+
+			if got := obj.IsDeletionProtected(); got != tt.want {
+				t.Errorf("IsDeletionProtected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHelmRelease_GetConcurrencyWeight(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{name: "without annotation", want: 1},
+		{name: "heavyweight", value: "4", want: 4},
+		{name: "zero", value: "0", want: 1},
+		{name: "negative", value: "-2", want: 1},
+		{name: "invalid", value: "heavy", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := HelmRelease{}
+			if tt.value != "" {
+				obj.SetAnnotations(map[string]string{ConcurrencyWeightAnnotation: tt.value})
+			}
+
+			if got := obj.GetConcurrencyWeight(); got != tt.want {
+				t.Errorf("GetConcurrencyWeight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHelmRelease_GetArtifactVerification(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    ArtifactVerification
+		minimum ArtifactVerification
+		want    ArtifactVerification
+	}{
+		{
+			name:    "not configured",
+			minimum: ArtifactVerificationWarn,
+			want:    ArtifactVerificationWarn,
+		},
+		{
+			name:    "stricter than minimum",
+			spec:    ArtifactVerificationEnforce,
+			minimum: ArtifactVerificationWarn,
+			want:    ArtifactVerificationEnforce,
+		},
+		{
+			name:    "less strict than minimum",
+			spec:    ArtifactVerificationDisabled,
+			minimum: ArtifactVerificationEnforce,
+			want:    ArtifactVerificationEnforce,
+		},
+		{
+			name:    "equal to minimum",
+			spec:    ArtifactVerificationDisabled,
+			minimum: ArtifactVerificationDisabled,
+			want:    ArtifactVerificationDisabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := HelmRelease{Spec: HelmReleaseSpec{ArtifactVerification: tt.spec}}
+			if got := obj.GetArtifactVerification(tt.minimum); got != tt.want {
+				t.Errorf("GetArtifactVerification() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// HelmReleaseGroupKind is the kind in string format.
+	HelmReleaseGroupKind = "HelmReleaseGroup"
+)
+
+const (
+	// BarrierPolicyFirstRelease enforces the barrier between the phases of a
+	// HelmReleaseGroup for a HelmRelease until it has been released
+	// successfully for the first time.
+	BarrierPolicyFirstRelease = "FirstRelease"
+
+	// BarrierPolicyAlways enforces the barrier between the phases of a
+	// HelmReleaseGroup for every reconciliation of a HelmRelease.
+	BarrierPolicyAlways = "Always"
+)
+
+// HelmReleaseGroupSpec defines the ordered phases of a group of HelmReleases.
+type HelmReleaseGroupSpec struct {
+	// Phases is the ordered list of phases of the group. The HelmReleases of
+	// a phase are only reconciled once all HelmReleases of the preceding
+	// phases are ready, as determined by the BarrierPolicy.
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Phases []HelmReleaseGroupPhase `json:"phases"`
+
+	// BarrierPolicy determines for how long the barrier between the phases
+	// is enforced for a HelmRelease. With 'FirstRelease', a HelmRelease only
+	// waits for the preceding phases until it has been released successfully
+	// for the first time, so a later upgrade of a HelmRelease of a preceding
+	// phase does not hold off the reconciliation of the following phases.
+	// With 'Always', a HelmRelease waits for the preceding phases on every
+	// reconciliation. Defaults to 'FirstRelease'.
+	// +kubebuilder:validation:Enum=FirstRelease;Always
+	// +optional
+	BarrierPolicy string `json:"barrierPolicy,omitempty"`
+}
+
+// HelmReleaseGroupPhase selects the HelmReleases of a phase of a
+// HelmReleaseGroup.
+type HelmReleaseGroupPhase struct {
+	// Name of the phase.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Name string `json:"name"`
+
+	// Selector selects the HelmReleases in the namespace of the group which
+	// are part of the phase. A HelmRelease matching the selectors of multiple
+	// phases is part of the first of them.
+	// +required
+	Selector metav1.LabelSelector `json:"selector"`
+}
+
+// HelmReleaseGroupStatus defines the observed state of a HelmReleaseGroup.
+type HelmReleaseGroupStatus struct {
+	// ObservedGeneration is the last observed generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions holds the conditions for the HelmReleaseGroup.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// CurrentPhase is the name of the first phase of which not all
+	// HelmReleases are ready. It is empty when all phases are ready.
+	// +optional
+	CurrentPhase string `json:"currentPhase,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=hrg
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.currentPhase",description=""
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description=""
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description=""
+
+// HelmReleaseGroup is the Schema for the helmreleasegroups API. It orders the
+// HelmReleases in its namespace in phases, with a barrier between each phase.
+type HelmReleaseGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HelmReleaseGroupSpec `json:"spec,omitempty"`
+	// +kubebuilder:default:={"observedGeneration":-1}
+	Status HelmReleaseGroupStatus `json:"status,omitempty"`
+}
+
+// PhaseOf returns the index of the first phase of the group the given
+// HelmRelease is part of, or -1 if it is not part of the group. It returns
+// an error if the selector of a phase is invalid.
+func (in HelmReleaseGroup) PhaseOf(hr *HelmRelease) (int, error) {
+	if hr.Namespace != in.Namespace {
+		return -1, nil
+	}
+	for i, phase := range in.Spec.Phases {
+		selector, err := metav1.LabelSelectorAsSelector(&phase.Selector)
+		if err != nil {
+			return -1, err
+		}
+		if !selector.Empty() && selector.Matches(labels.Set(hr.GetLabels())) {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// GetBarrierPolicy returns the configured barrier policy of the group, or
+// BarrierPolicyFirstRelease if not set.
+func (in HelmReleaseGroup) GetBarrierPolicy() string {
+	if in.Spec.BarrierPolicy == "" {
+		return BarrierPolicyFirstRelease
+	}
+	return in.Spec.BarrierPolicy
+}
+
+// GetConditions returns the status conditions of the object.
+func (in HelmReleaseGroup) GetConditions() []metav1.Condition {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the status conditions on the object.
+func (in *HelmReleaseGroup) SetConditions(conditions []metav1.Condition) {
+	in.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// HelmReleaseGroupList contains a list of HelmReleaseGroup objects.
+type HelmReleaseGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HelmReleaseGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HelmReleaseGroup{}, &HelmReleaseGroupList{})
+}
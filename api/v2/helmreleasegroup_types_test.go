@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHelmReleaseGroup_PhaseOf(t *testing.T) {
+	group := HelmReleaseGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "bootstrap", Namespace: "flux-system"},
+		Spec: HelmReleaseGroupSpec{
+			Phases: []HelmReleaseGroupPhase{
+				{Name: "crds", Selector: metav1.LabelSelector{MatchLabels: map[string]string{"phase": "crds"}}},
+				{Name: "platform", Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "platform"}}},
+				{Name: "apps", Selector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"platform", "apps"}},
+				}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		labels    map[string]string
+		want      int
+	}{
+		{name: "first phase", labels: map[string]string{"phase": "crds"}, want: 0},
+		{name: "last phase", labels: map[string]string{"tier": "apps"}, want: 2},
+		{name: "first matching phase", labels: map[string]string{"tier": "platform"}, want: 1},
+		{name: "no matching phase", labels: map[string]string{"tier": "other"}, want: -1},
+		{name: "no labels", want: -1},
+		{name: "other namespace", namespace: "default", labels: map[string]string{"phase": "crds"}, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := tt.namespace
+			if namespace == "" {
+				namespace = group.Namespace
+			}
+			hr := &HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: namespace, Labels: tt.labels},
+			}
+			got, err := group.PhaseOf(hr)
+			if err != nil {
+				t.Fatalf("PhaseOf() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PhaseOf() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid selector", func(t *testing.T) {
+		invalid := group.DeepCopy()
+		invalid.Spec.Phases[0].Selector.MatchExpressions = []metav1.LabelSelectorRequirement{
+			{Key: "phase", Operator: "Unknown"},
+		}
+		if _, err := invalid.PhaseOf(&HelmRelease{ObjectMeta: metav1.ObjectMeta{Namespace: group.Namespace}}); err == nil {
+			t.Error("PhaseOf() expected error for invalid selector")
+		}
+	})
+}
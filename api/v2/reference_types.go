@@ -16,6 +16,10 @@ limitations under the License.
 
 package v2
 
+import (
+	"github.com/fluxcd/pkg/apis/meta"
+)
+
 // CrossNamespaceObjectReference contains enough information to let you locate
 // the typed referenced object at cluster level.
 type CrossNamespaceObjectReference struct {
@@ -68,3 +72,115 @@ type CrossNamespaceSourceReference struct {
 	// +optional
 	Namespace string `json:"namespace,omitempty"`
 }
+
+// DependencyReference contains enough information to locate a HelmRelease
+// the referring HelmRelease depends on, and optionally the chart version the
+// dependency is required to have.
+type DependencyReference struct {
+	// Name of the referent.
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the referent, when not specified it acts as LocalObjectReference.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Version is a semver range constraint the chart version of the latest
+	// release of the referent must match, e.g. '>=4.0.0 <5.0.0'. When not
+	// specified, any chart version is accepted.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// ReadyExpr is a CEL expression evaluated against the referent to decide
+	// if the dependency is ready, e.g. 'dep.status.observedGeneration ==
+	// dep.metadata.generation'. The referent is available as 'dep', and the
+	// referring HelmRelease as 'self'. When specified, the expression replaces
+	// the default readiness check of the referent having a Ready condition
+	// with status True for its latest generation.
+	// +optional
+	ReadyExpr string `json:"readyExpr,omitempty"`
+
+	// Outputs holds the fields of the referent which are merged into the
+	// values of the referring HelmRelease, e.g. the name of its release to
+	// derive the name of a Service from. The fields are merged after the
+	// values of ValuesFrom and ValuesFromFields.
+	// +optional
+	Outputs []DependencyOutput `json:"outputs,omitempty"`
+}
+
+// DependencyOutput contains a reference to a field of a HelmRelease
+// dependency, and the path it is merged at in the values of the referring
+// HelmRelease.
+type DependencyOutput struct {
+	// FieldPath is the JSONPath expression selecting the field of the
+	// referent, e.g. '{.status.history[0].name}'. It must select a single
+	// value.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	FieldPath string `json:"fieldPath"`
+
+	// TargetPath is the YAML dot notation path the value of the field is
+	// merged at, e.g. 'database.releaseName'.
+	// +kubebuilder:validation:MaxLength=250
+	// +kubebuilder:validation:Pattern=`^([a-zA-Z0-9_\-.\\\/]|\[[0-9]{1,5}\])+$`
+	// +required
+	TargetPath string `json:"targetPath"`
+}
+
+// FieldValuesReferences returns the Outputs as FieldValuesReference(s)
+// to the referent.
+func (in DependencyReference) FieldValuesReferences() []FieldValuesReference {
+	refs := make([]FieldValuesReference, 0, len(in.Outputs))
+	for _, o := range in.Outputs {
+		refs = append(refs, FieldValuesReference{
+			APIVersion: GroupVersion.String(),
+			Kind:       HelmReleaseKind,
+			Name:       in.Name,
+			FieldPath:  o.FieldPath,
+			TargetPath: o.TargetPath,
+		})
+	}
+	return refs
+}
+
+// NamespacedObjectReference returns the reference to the HelmRelease,
+// without the version constraint.
+func (in DependencyReference) NamespacedObjectReference() meta.NamespacedObjectReference {
+	return meta.NamespacedObjectReference{
+		Name:      in.Name,
+		Namespace: in.Namespace,
+	}
+}
+
+// WaitForReference contains enough information to locate a cluster resource
+// the referring HelmRelease waits for, and optionally the expression which
+// determines if the resource is ready.
+type WaitForReference struct {
+	// APIVersion of the referent.
+	// +required
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referent.
+	// +required
+	Kind string `json:"kind"`
+
+	// Name of the referent.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the referent, when not specified it defaults to the
+	// target namespace of the release for namespaced resources.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// ReadyExpr is a CEL expression evaluated against the referent to decide
+	// if it is ready, e.g. 'dep.status.conditions.exists(c, c.type ==
+	// "Established" && c.status == "True")'. The referent is available as
+	// 'dep', and the referring HelmRelease as 'self'. When not specified, the
+	// referent is ready if it exists and its kstatus is Current.
+	// +optional
+	ReadyExpr string `json:"readyExpr,omitempty"`
+}
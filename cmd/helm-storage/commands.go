@@ -0,0 +1,201 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	rspb "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/fluxcd/helm-controller/internal/release"
+)
+
+// list writes a table of the storage records of the named release to w.
+func list(ctx context.Context, w io.Writer, s *store, name string) error {
+	records, err := s.records(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no storage records found for release '%s/%s'", s.namespace, name)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REVISION\tSTATUS\tCHART\tAPP VERSION\tUPDATED\tRECORD\tDESCRIPTION")
+	for _, r := range records {
+		chart, appVersion, updated, description := "-", "-", "-", "-"
+		if r.decodeErr != nil {
+			description = fmt.Sprintf("failed to decode release: %s", r.decodeErr)
+		} else {
+			if v := chartVersion(r.release); v != "" {
+				chart = v
+				appVersion = r.release.Chart.Metadata.AppVersion
+			}
+			if r.release.Info != nil {
+				updated = r.release.Info.LastDeployed.Format(time.RFC3339)
+				description = r.release.Info.Description
+			}
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.revision, r.status, chart, appVersion, updated, r.name, description)
+	}
+	return tw.Flush()
+}
+
+// get writes the given revision of the named release to w, in the given
+// output format.
+func get(ctx context.Context, w io.Writer, s *store, name, revision, output string) error {
+	v, err := strconv.Atoi(revision)
+	if err != nil {
+		return fmt.Errorf("invalid revision '%s': %w", revision, err)
+	}
+	r, err := s.record(ctx, name, v)
+	if err != nil {
+		return err
+	}
+	if r.decodeErr != nil {
+		return fmt.Errorf("failed to decode storage record '%s': %w", r.name, r.decodeErr)
+	}
+	return printRelease(w, r.release, output)
+}
+
+// diff writes the differences in the chart version, values and manifest
+// between two revisions of the named release to w.
+func diff(ctx context.Context, w io.Writer, s *store, name, revisionA, revisionB string) error {
+	var releases []*rspb.Release
+	for _, revision := range []string{revisionA, revisionB} {
+		v, err := strconv.Atoi(revision)
+		if err != nil {
+			return fmt.Errorf("invalid revision '%s': %w", revision, err)
+		}
+		r, err := s.record(ctx, name, v)
+		if err != nil {
+			return err
+		}
+		if r.decodeErr != nil {
+			return fmt.Errorf("failed to decode storage record '%s': %w", r.name, r.decodeErr)
+		}
+		releases = append(releases, r.release)
+	}
+	return writeDiff(w, releases[0], releases[1])
+}
+
+// writeDiff writes the differences between release a and b to w.
+func writeDiff(w io.Writer, a, b *rspb.Release) error {
+	var found bool
+	for _, d := range []struct {
+		name string
+		a, b interface{}
+	}{
+		{name: "chart", a: chartVersion(a), b: chartVersion(b)},
+		{name: "values", a: a.Config, b: b.Config},
+		{name: "manifest", a: a.Manifest, b: b.Manifest},
+	} {
+		if diff := cmp.Diff(d.a, d.b); diff != "" {
+			found = true
+			if _, err := fmt.Fprintf(w, "--- %s (-%d +%d)\n%s\n", d.name, a.Version, b.Version, diff); err != nil {
+				return err
+			}
+		}
+	}
+	if !found {
+		_, err := fmt.Fprintf(w, "No differences between revision %d and %d\n", a.Version, b.Version)
+		return err
+	}
+	return nil
+}
+
+// decode decodes the release data in the given file (or stdin for "-"), and
+// writes it to w in the given output format.
+func decode(w io.Writer, file, output string) error {
+	var (
+		b   []byte
+		err error
+	)
+	if file == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	data := strings.TrimSpace(string(b))
+	rls, err := release.Decode(data)
+	if err != nil {
+		// The data of a Secret as returned by the Kubernetes API is base64
+		// encoded once more.
+		b, decodeErr := base64.StdEncoding.DecodeString(data)
+		if decodeErr != nil {
+			return fmt.Errorf("failed to decode release data: %w", err)
+		}
+		if rls, err = release.Decode(string(b)); err != nil {
+			return fmt.Errorf("failed to decode release data: %w", err)
+		}
+	}
+	return printRelease(w, rls, output)
+}
+
+// printRelease writes the release to w in the given output format.
+func printRelease(w io.Writer, rls *rspb.Release, output string) error {
+	var (
+		b   []byte
+		err error
+	)
+	switch output {
+	case "yaml":
+		b, err = yaml.Marshal(rls)
+	case "json":
+		b, err = json.MarshalIndent(rls, "", "  ")
+		b = append(b, '\n')
+	case "manifest":
+		b = []byte(rls.Manifest)
+	case "values":
+		b, err = yaml.Marshal(rls.Config)
+	case "notes":
+		if rls.Info != nil {
+			b = []byte(rls.Info.Notes)
+		}
+	default:
+		return fmt.Errorf("unsupported output format '%s'", output)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// chartVersion returns the name and version of the chart of the release.
+func chartVersion(rls *rspb.Release) string {
+	if rls.Chart == nil || rls.Chart.Metadata == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s", rls.Chart.Metadata.Name, rls.Chart.Metadata.Version)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	rspb "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func encodeRelease(t *testing.T, rls *rspb.Release) string {
+	t.Helper()
+
+	b, err := json.Marshal(rls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err = w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func testRelease(version int, chartVersion, manifest string) *rspb.Release {
+	return &rspb.Release{
+		Name:      "podinfo",
+		Namespace: "default",
+		Version:   version,
+		Info:      &rspb.Info{Status: rspb.StatusDeployed, Description: "Upgrade complete"},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "podinfo", Version: chartVersion, AppVersion: chartVersion},
+		},
+		Config:   map[string]interface{}{"replicas": version},
+		Manifest: manifest,
+	}
+}
+
+func testSecret(t *testing.T, rls *rspb.Release, data string) *corev1.Secret {
+	if data == "" {
+		data = encodeRelease(t, rls)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", rls.Name, rls.Version),
+			Namespace: rls.Namespace,
+			Labels: map[string]string{
+				"owner":   "helm",
+				"name":    rls.Name,
+				"status":  rls.Info.Status.String(),
+				"version": strconv.Itoa(rls.Version),
+			},
+		},
+		Data: map[string][]byte{"release": []byte(data)},
+	}
+}
+
+func newTestStore(t *testing.T) *store {
+	client := fake.NewSimpleClientset(
+		testSecret(t, testRelease(2, "6.0.1", "kind: Deployment\nreplicas: 2\n"), ""),
+		testSecret(t, testRelease(1, "6.0.0", "kind: Deployment\nreplicas: 1\n"), ""),
+		testSecret(t, testRelease(3, "6.0.1", ""), "invalid"),
+	)
+	return &store{client: client, namespace: "default", driver: "secret"}
+}
+
+func Test_list(t *testing.T) {
+	g := NewWithT(t)
+
+	var out bytes.Buffer
+	g.Expect(list(context.TODO(), &out, newTestStore(t), "podinfo")).To(Succeed())
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	g.Expect(lines).To(HaveLen(4))
+	g.Expect(string(lines[1])).To(MatchRegexp(`^1\s+deployed\s+podinfo-6.0.0\s+6.0.0`))
+	g.Expect(string(lines[2])).To(MatchRegexp(`^2\s+deployed\s+podinfo-6.0.1\s+6.0.1`))
+	g.Expect(string(lines[3])).To(ContainSubstring("failed to decode release"))
+
+	g.Expect(list(context.TODO(), &out, newTestStore(t), "other")).ToNot(Succeed())
+}
+
+func Test_get(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestStore(t)
+
+	var out bytes.Buffer
+	g.Expect(get(context.TODO(), &out, s, "podinfo", "2", "manifest")).To(Succeed())
+	g.Expect(out.String()).To(Equal("kind: Deployment\nreplicas: 2\n"))
+
+	out.Reset()
+	g.Expect(get(context.TODO(), &out, s, "podinfo", "1", "values")).To(Succeed())
+	g.Expect(out.String()).To(Equal("replicas: 1\n"))
+
+	g.Expect(get(context.TODO(), &out, s, "podinfo", "3", "yaml")).To(MatchError(ContainSubstring("failed to decode storage record")))
+	g.Expect(get(context.TODO(), &out, s, "podinfo", "4", "yaml")).To(MatchError(ContainSubstring("no storage record found")))
+	g.Expect(get(context.TODO(), &out, s, "podinfo", "1", "invalid")).To(MatchError(ContainSubstring("unsupported output format")))
+}
+
+func Test_diff(t *testing.T) {
+	g := NewWithT(t)
+	s := newTestStore(t)
+
+	var out bytes.Buffer
+	g.Expect(diff(context.TODO(), &out, s, "podinfo", "1", "2")).To(Succeed())
+	g.Expect(out.String()).To(ContainSubstring("--- chart (-1 +2)"))
+	g.Expect(out.String()).To(ContainSubstring("--- values (-1 +2)"))
+	g.Expect(out.String()).To(ContainSubstring("--- manifest (-1 +2)"))
+
+	out.Reset()
+	g.Expect(diff(context.TODO(), &out, s, "podinfo", "1", "1")).To(Succeed())
+	g.Expect(out.String()).To(Equal("No differences between revision 1 and 1\n"))
+}
+
+func Test_decode(t *testing.T) {
+	data := encodeRelease(t, testRelease(1, "6.0.0", "kind: Deployment\n"))
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "release data", data: data},
+		{name: "Secret data", data: base64.StdEncoding.EncodeToString([]byte(data)) + "\n"},
+		{name: "invalid data", data: "invalid", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			file := filepath.Join(t.TempDir(), "release")
+			g.Expect(os.WriteFile(file, []byte(tt.data), 0o600)).To(Succeed())
+
+			var out bytes.Buffer
+			err := decode(&out, file, "manifest")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(out.String()).To(Equal("kind: Deployment\n"))
+		})
+	}
+}
+
+func Test_importReleases(t *testing.T) {
+	g := NewWithT(t)
+
+	other := testRelease(1, "1.0.0", "")
+	other.Name = "other"
+	other.Chart.Metadata.Name = "other"
+	other.Config = nil
+	s := &store{
+		client: fake.NewSimpleClientset(
+			testSecret(t, testRelease(1, "6.0.0", ""), ""),
+			testSecret(t, testRelease(2, "6.0.1", ""), ""),
+			testSecret(t, other, ""),
+		),
+		namespace: "default",
+		driver:    "secret",
+	}
+	opts := importOptions{
+		interval:     5 * time.Minute,
+		repositories: map[string]string{"podinfo": "https://stefanprodan.github.io/podinfo"},
+	}
+
+	var out bytes.Buffer
+	g.Expect(importReleases(context.TODO(), &out, s, []string{"podinfo"}, opts)).To(Succeed())
+	g.Expect(out.String()).To(Equal(`---
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: HelmRepository
+metadata:
+  name: podinfo
+  namespace: default
+spec:
+  interval: 5m0s
+  url: https://stefanprodan.github.io/podinfo
+---
+apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: podinfo
+  namespace: default
+spec:
+  chart:
+    spec:
+      chart: podinfo
+      sourceRef:
+        kind: HelmRepository
+        name: podinfo
+      version: 6.0.1
+  interval: 5m0s
+  releaseName: podinfo
+  storageNamespace: default
+  targetNamespace: default
+  values:
+    replicas: 2
+`))
+
+	out.Reset()
+	g.Expect(importReleases(context.TODO(), &out, s, nil, opts)).To(Succeed())
+	g.Expect(out.String()).To(ContainSubstring("# The chart repository of 'other' could not be inferred"))
+	g.Expect(out.String()).To(ContainSubstring("url: " + repositoryURLPlaceholder))
+	g.Expect(bytes.Count(out.Bytes(), []byte("kind: HelmRelease\n"))).To(Equal(2))
+
+	g.Expect(importReleases(context.TODO(), &out, s, []string{"missing"}, opts)).To(MatchError(ContainSubstring("no deployed storage record found")))
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	rspb "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// repositoryURLPlaceholder is the URL set on generated HelmRepository objects
+// when the URL of the chart repository is not known.
+const repositoryURLPlaceholder = "https://charts.example.com"
+
+// importOptions holds the flags of the import command.
+type importOptions struct {
+	// interval is the reconciliation interval of the generated objects.
+	interval time.Duration
+	// repositories maps chart names to the URL of their chart repository.
+	repositories map[string]string
+}
+
+// importReleases writes a HelmRepository and HelmRelease manifest to w for
+// the deployed revision of each of the named releases, or of all releases if
+// no names are given.
+//
+// The generated HelmRelease pins the chart name and version of the deployed
+// revision, and carries the user supplied values of the release. Its release
+// name, target namespace and storage namespace are set to match the existing
+// release, which causes the controller to adopt the release on its first
+// reconciliation by upgrading it in place.
+func importReleases(ctx context.Context, w io.Writer, s *store, names []string, opts importOptions) error {
+	records, err := s.deployed(ctx)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var (
+		found        = make(map[string]bool, len(records))
+		repositories = make(map[string]bool)
+	)
+	for _, r := range records {
+		if len(wanted) > 0 && !wanted[r.releaseName] {
+			continue
+		}
+		if r.decodeErr != nil {
+			return fmt.Errorf("failed to decode storage record '%s': %w", r.name, r.decodeErr)
+		}
+		if r.release.Chart == nil || r.release.Chart.Metadata == nil {
+			return fmt.Errorf("storage record '%s' has no chart metadata", r.name)
+		}
+		found[r.releaseName] = true
+
+		chartName := r.release.Chart.Metadata.Name
+		if !repositories[chartName] {
+			repositories[chartName] = true
+			url, ok := opts.repositories[chartName]
+			var comment string
+			if !ok {
+				url = repositoryURLPlaceholder
+				comment = fmt.Sprintf("The chart repository of '%s' could not be inferred, replace the URL before applying.", chartName)
+			}
+			if err := writeObject(w, newHelmRepository(s.namespace, chartName, url, opts.interval), comment); err != nil {
+				return err
+			}
+		}
+
+		hr, err := newHelmRelease(r.release, opts.interval)
+		if err != nil {
+			return fmt.Errorf("failed to generate HelmRelease for release '%s/%s': %w", s.namespace, r.releaseName, err)
+		}
+		if err := writeObject(w, hr, ""); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		if !found[name] {
+			return fmt.Errorf("no deployed storage record found for release '%s/%s'", s.namespace, name)
+		}
+	}
+	return nil
+}
+
+// newHelmRepository returns a HelmRepository with the given name and URL.
+func newHelmRepository(namespace, name, url string, interval time.Duration) *sourcev1.HelmRepository {
+	return &sourcev1.HelmRepository{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: sourcev1.GroupVersion.String(),
+			Kind:       sourcev1.HelmRepositoryKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: sourcev1.HelmRepositorySpec{
+			URL:      url,
+			Interval: metav1.Duration{Duration: interval},
+		},
+	}
+}
+
+// newHelmRelease returns a HelmRelease which adopts the given release. The
+// chart is sourced from the HelmRepository named after the chart.
+func newHelmRelease(rls *rspb.Release, interval time.Duration) (*v2.HelmRelease, error) {
+	hr := &v2.HelmRelease{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v2.GroupVersion.String(),
+			Kind:       v2.HelmReleaseKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rls.Name,
+			Namespace: rls.Namespace,
+		},
+		Spec: v2.HelmReleaseSpec{
+			Chart: &v2.HelmChartTemplate{
+				Spec: v2.HelmChartTemplateSpec{
+					Chart:   rls.Chart.Metadata.Name,
+					Version: rls.Chart.Metadata.Version,
+					SourceRef: v2.CrossNamespaceObjectReference{
+						Kind: sourcev1.HelmRepositoryKind,
+						Name: rls.Chart.Metadata.Name,
+					},
+				},
+			},
+			Interval:         metav1.Duration{Duration: interval},
+			ReleaseName:      rls.Name,
+			TargetNamespace:  rls.Namespace,
+			StorageNamespace: rls.Namespace,
+		},
+	}
+	if len(rls.Config) > 0 {
+		b, err := json.Marshal(rls.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode values: %w", err)
+		}
+		hr.Spec.Values = &apiextensionsv1.JSON{Raw: b}
+	}
+	return hr, nil
+}
+
+// writeObject writes the object to w as a YAML document, without its status
+// and server populated metadata. A non-empty comment is written at the top
+// of the document.
+func writeObject(w io.Writer, obj runtime.Object, comment string) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	delete(u, "status")
+	if metadata, ok := u["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	b, err := yaml.Marshal(u)
+	if err != nil {
+		return err
+	}
+	header := "---\n"
+	if comment != "" {
+		header += "# " + comment + "\n"
+	}
+	if _, err = io.WriteString(w, header); err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// helm-storage lists, decodes and compares the Helm storage records of a
+// release, for debugging storage issues without requiring the helm binary.
+// It can also generate HelmRelease manifests which adopt existing releases.
+//
+// Usage:
+//
+//	helm-storage [flags] list RELEASE
+//	helm-storage [flags] get RELEASE REVISION
+//	helm-storage [flags] diff RELEASE REVISION REVISION
+//	helm-storage [flags] decode [FILE]
+//	helm-storage [flags] import [RELEASE...]
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+const usage = `helm-storage lists, decodes and compares Helm storage records.
+
+Usage:
+  helm-storage [flags] list RELEASE
+  helm-storage [flags] get RELEASE REVISION
+  helm-storage [flags] diff RELEASE REVISION REVISION
+  helm-storage [flags] decode [FILE]
+  helm-storage [flags] import [RELEASE...]
+
+The decode command reads the release data of a single storage record from FILE,
+or from stdin if omitted. The data may either be the value of the 'release' key
+of the Secret or ConfigMap as returned by kubectl, or the base64 decoded value.
+
+The import command writes a HelmRepository and HelmRelease manifest for the
+deployed revision of the given releases, or of all releases in the namespace if
+omitted. The HelmRelease adopts the existing release on its first
+reconciliation. Chart repository URLs can not be inferred from the release and
+must be provided using --repository, or replaced in the output.
+
+Flags:
+`
+
+func main() {
+	flags := flag.NewFlagSet("helm-storage", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	configFlags := genericclioptions.NewConfigFlags(true)
+	configFlags.AddFlags(flags)
+
+	var opts options
+	flags.StringVar(&opts.driver, "driver", "secret",
+		"The Helm storage driver of the release, either 'secret' or 'configmap'.")
+	flags.StringVarP(&opts.output, "output", "o", "yaml",
+		"The output format of the get and decode commands. One of 'yaml', 'json', 'manifest', 'values' or 'notes'.")
+	flags.DurationVar(&opts.interval, "interval", 10*time.Minute,
+		"The reconciliation interval of the objects generated by the import command.")
+	flags.StringToStringVar(&opts.repositories, "repository", nil,
+		"The chart repository URL of a chart for the import command, in the format CHART=URL. May be repeated.")
+	_ = flags.Parse(os.Args[1:])
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx, configFlags, opts, flags.Args()); err != nil {
+		if errors.Is(err, errUsage) {
+			flags.Usage()
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// errUsage is returned when the command is invoked with invalid arguments.
+var errUsage = errors.New("invalid usage")
+
+// options holds the flags of the commands.
+type options struct {
+	driver       string
+	output       string
+	interval     time.Duration
+	repositories map[string]string
+}
+
+func run(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts options, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	if args[0] == "decode" {
+		if len(args) > 2 {
+			return errUsage
+		}
+		file := "-"
+		if len(args) == 2 {
+			file = args[1]
+		}
+		return decode(os.Stdout, file, opts.output)
+	}
+
+	var expectedArgs int
+	switch args[0] {
+	case "list":
+		expectedArgs = 2
+	case "get":
+		expectedArgs = 3
+	case "diff":
+		expectedArgs = 4
+	case "import":
+		expectedArgs = len(args)
+	default:
+		return errUsage
+	}
+	if len(args) != expectedArgs {
+		return errUsage
+	}
+
+	switch strings.ToLower(opts.driver) {
+	case "secret", "secrets", "configmap", "configmaps":
+	default:
+		return fmt.Errorf("unsupported Helm storage driver '%s'", opts.driver)
+	}
+
+	cfg, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	namespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return fmt.Errorf("failed to determine namespace: %w", err)
+	}
+	clientSet, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	s := &store{client: clientSet, namespace: namespace, driver: strings.ToLower(opts.driver)}
+	switch args[0] {
+	case "list":
+		return list(ctx, os.Stdout, s, args[1])
+	case "get":
+		return get(ctx, os.Stdout, s, args[1], args[2], opts.output)
+	case "import":
+		return importReleases(ctx, os.Stdout, s, args[1:], importOptions{
+			interval:     opts.interval,
+			repositories: opts.repositories,
+		})
+	default:
+		return diff(ctx, os.Stdout, s, args[1], args[2], args[3])
+	}
+}
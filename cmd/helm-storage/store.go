@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	rspb "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/fluxcd/helm-controller/internal/release"
+)
+
+// record is a Helm storage record of a release revision.
+type record struct {
+	// name is the name of the Secret or ConfigMap.
+	name string
+	// releaseName is the name of the release, as labeled.
+	releaseName string
+	// revision is the revision of the release, as labeled.
+	revision int
+	// status is the status of the release, as labeled.
+	status string
+	// release is the decoded release, nil if decodeErr is set.
+	release *rspb.Release
+	// decodeErr is the error which occurred while decoding the release data.
+	decodeErr error
+}
+
+// store reads the Helm storage records from a namespace.
+type store struct {
+	client    kubernetes.Interface
+	namespace string
+	driver    string
+}
+
+// records returns the storage records of the named release, sorted by
+// revision. Records of which the data can not be decoded are returned with
+// the decoding error set, instead of failing.
+func (s *store) records(ctx context.Context, name string) ([]record, error) {
+	return s.list(ctx, labels.Set{"owner": "helm", "name": name})
+}
+
+// deployed returns the storage record of the latest deployed revision of
+// each release, sorted by release name.
+func (s *store) deployed(ctx context.Context) ([]record, error) {
+	records, err := s.list(ctx, labels.Set{"owner": "helm", "status": "deployed"})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].releaseName < records[j].releaseName
+	})
+
+	var latest []record
+	for _, r := range records {
+		if n := len(latest); n > 0 && latest[n-1].releaseName == r.releaseName {
+			latest[n-1] = r
+			continue
+		}
+		latest = append(latest, r)
+	}
+	return latest, nil
+}
+
+// list returns the storage records matching the given labels, sorted by
+// revision.
+func (s *store) list(ctx context.Context, set labels.Set) ([]record, error) {
+	selector := labels.SelectorFromSet(set).String()
+	listOpts := metav1.ListOptions{LabelSelector: selector}
+
+	var records []record
+	switch s.driver {
+	case "configmap", "configmaps":
+		list, err := s.client.CoreV1().ConfigMaps(s.namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ConfigMaps: %w", err)
+		}
+		for _, cm := range list.Items {
+			records = append(records, newRecord(cm.Name, cm.Labels, cm.Data["release"]))
+		}
+	default:
+		list, err := s.client.CoreV1().Secrets(s.namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Secrets: %w", err)
+		}
+		for _, secret := range list.Items {
+			records = append(records, newRecord(secret.Name, secret.Labels, string(secret.Data["release"])))
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].revision < records[j].revision
+	})
+	return records, nil
+}
+
+// record returns the storage record of the given revision of the named
+// release.
+func (s *store) record(ctx context.Context, name string, revision int) (*record, error) {
+	records, err := s.records(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if records[i].revision == revision {
+			return &records[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no storage record found for revision %d of release '%s/%s'", revision, s.namespace, name)
+}
+
+func newRecord(name string, l map[string]string, data string) record {
+	r := record{name: name, releaseName: l["name"], status: l["status"]}
+	r.revision, _ = strconv.Atoi(l["version"])
+	r.release, r.decodeErr = release.Decode(data)
+	return r
+}
@@ -17,22 +17,91 @@ limitations under the License.
 package action
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
 	helmkube "github.com/jessesimpson36/helm/v4/pkg/kube"
 	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
 	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/release"
 	"github.com/fluxcd/helm-controller/internal/storage"
 )
 
-const (
+var (
 	// DefaultStorageDriver is the default Helm storage driver.
 	DefaultStorageDriver = helmdriver.SecretsDriverName
+	// DefaultMaxHistory is the default number of Helm release versions to
+	// keep in the storage. Zero means no limit.
+	DefaultMaxHistory = 5
+	// DefaultArtifactVerification is the default strictness of the
+	// verification of chart artifacts.
+	DefaultArtifactVerification = v2.ArtifactVerificationEnforce
 )
 
+// ParseStorageDriver returns the Helm storage driver name for the given
+// (case-insensitive) name, e.g. 'secret', 'configmap' or 'sql'. It returns an
+// error when the driver is not supported.
+func ParseStorageDriver(name string) (string, error) {
+	for _, driver := range []string{
+		helmdriver.SecretsDriverName,
+		helmdriver.ConfigMapsDriverName,
+		helmdriver.SQLDriverName,
+		helmdriver.MemoryDriverName,
+	} {
+		if strings.EqualFold(name, driver) {
+			return driver, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported Helm storage driver '%s'", name)
+}
+
+// StorageDriver returns the Helm storage driver configured for the given
+// object, or DefaultStorageDriver. The returned name is in lower case, as
+// used in the API.
+func StorageDriver(obj *v2.HelmRelease) string {
+	if obj.Spec.StorageDriver != "" {
+		return strings.ToLower(obj.Spec.StorageDriver)
+	}
+	return strings.ToLower(DefaultStorageDriver)
+}
+
+// MaxHistory returns the number of Helm release versions to keep for the
+// given object, or DefaultMaxHistory.
+func MaxHistory(obj *v2.HelmRelease) int {
+	if obj.Spec.MaxHistory != nil {
+		return *obj.Spec.MaxHistory
+	}
+	return DefaultMaxHistory
+}
+
+// helmMaxHistory returns the maximum history Helm should enforce for the
+// given object. This is zero (no limit) when the object configures the
+// v2.PruneFailedHistoryPolicy, as the history is then pruned using
+// PruneHistory instead.
+func helmMaxHistory(obj *v2.HelmRelease) int {
+	if obj.GetHistoryPolicy() == v2.PruneFailedHistoryPolicy {
+		return 0
+	}
+	return MaxHistory(obj)
+}
+
+// ObservedStorageDriver returns the Helm storage driver of the current
+// release of the given object. Releases made before the storage driver was
+// recorded in the status are stored using the Secrets storage driver.
+func ObservedStorageDriver(obj *v2.HelmRelease) string {
+	if obj.Status.StorageDriver != "" {
+		return obj.Status.StorageDriver
+	}
+	return strings.ToLower(helmdriver.SecretsDriverName)
+}
+
 // ConfigFactory is a factory for the Helm action configuration of a (series
 // of) Helm action(s). It allows for sharing Kubernetes client(s) and the
 // Helm storage driver between actions, where possible.
@@ -50,6 +119,27 @@ type ConfigFactory struct {
 	Driver helmdriver.Driver
 	// StorageLog is the logger to use for the Helm storage driver.
 	StorageLog helmaction.DebugLog
+	// StorageAnnotations are added to the Secrets or ConfigMaps written by
+	// the Helm storage driver.
+	StorageAnnotations map[string]string
+	// StorageOwner is the owner of the releases written by the Helm storage
+	// driver, which is used to annotate the Secrets or ConfigMaps, and to
+	// refuse modifications to releases owned by another HelmRelease.
+	StorageOwner *storage.Owner
+	// OwnerVerifier verifies the ownership of the releases in the Helm
+	// storage. It is nil when the storage driver does not support it.
+	OwnerVerifier storage.OwnerVerifier
+	// StorageDSN is the connection string of the database used by the SQL
+	// storage driver.
+	StorageDSN string
+	// SQLDrivers is the cache of SQL storage drivers to get the driver of
+	// the SQL storage from. When nil, a new driver is constructed.
+	SQLDrivers *SQLDriverCache
+
+	// storageDriver and storageNamespace are the Helm storage driver name
+	// and namespace configured using WithStorage.
+	storageDriver    string
+	storageNamespace string
 }
 
 // ConfigFactoryOption is a function that configures a ConfigFactory.
@@ -68,18 +158,25 @@ func NewConfigFactory(getter genericclioptions.RESTClientGetter, opts ...ConfigF
 			return nil, err
 		}
 	}
+	if err := factory.buildStorage(); err != nil {
+		return nil, err
+	}
 	if err := factory.Valid(); err != nil {
 		return nil, err
 	}
 	return factory, nil
 }
 
-// WithStorage configures the ConfigFactory.Driver by constructing a new Helm
-// driver.Driver using the provided driver name and namespace.
-// It supports driver.ConfigMapsDriverName, driver.SecretsDriverName and
-// driver.MemoryDriverName.
-// It returns an error when the driver name is not supported, or the client
-// configuration for the storage fails.
+// WithStorage configures the ConfigFactory.Driver to be a new Helm
+// driver.Driver for the provided driver name and namespace.
+// It supports driver.ConfigMapsDriverName, driver.SecretsDriverName,
+// driver.SQLDriverName and driver.MemoryDriverName, matched
+// case-insensitively.
+// It returns an error when the driver name is not supported. The driver
+// itself is constructed by NewConfigFactory after all options have been
+// applied, so that any ConfigFactory.StorageAnnotations,
+// ConfigFactory.StorageOwner and ConfigFactory.StorageDSN are taken into
+// account regardless of the order of the options.
 func WithStorage(driver, namespace string) ConfigFactoryOption {
 	if driver == "" {
 		driver = DefaultStorageDriver
@@ -90,33 +187,112 @@ func WithStorage(driver, namespace string) ConfigFactoryOption {
 			return fmt.Errorf("no namespace provided for '%s' storage driver", driver)
 		}
 
-		switch driver {
-		case helmdriver.SecretsDriverName, helmdriver.ConfigMapsDriverName, "":
-			clientSet, err := f.KubeClient.Factory.KubernetesClientSet()
-			if err != nil {
-				return fmt.Errorf("could not get client set for '%s' storage driver: %w", driver, err)
+		driver, err := ParseStorageDriver(driver)
+		if err != nil {
+			return err
+		}
+		f.storageDriver, f.storageNamespace = driver, namespace
+		return nil
+	}
+}
+
+// buildStorage configures the ConfigFactory.Driver by constructing a new
+// Helm driver.Driver for the storage driver name and namespace configured
+// using WithStorage. It is a no-op if WithStorage has not been applied.
+// It returns an error when the client configuration for the storage fails.
+func (c *ConfigFactory) buildStorage() error {
+	driver, namespace := c.storageDriver, c.storageNamespace
+	switch driver {
+	case helmdriver.SecretsDriverName, helmdriver.ConfigMapsDriverName:
+		clientSet, err := c.KubeClient.Factory.KubernetesClientSet()
+		if err != nil {
+			return fmt.Errorf("could not get client set for '%s' storage driver: %w", driver, err)
+		}
+		if driver == helmdriver.ConfigMapsDriverName {
+			var client corev1client.ConfigMapInterface = clientSet.CoreV1().ConfigMaps(namespace)
+			if len(c.StorageAnnotations) > 0 || c.StorageOwner != nil {
+				annotated := storage.NewAnnotatedConfigMaps(client, c.StorageAnnotations, c.StorageOwner)
+				client, c.OwnerVerifier = annotated, annotated
 			}
-			if driver == helmdriver.ConfigMapsDriverName {
-				f.Driver = helmdriver.NewConfigMaps(clientSet.CoreV1().ConfigMaps(namespace))
+			c.Driver = helmdriver.NewConfigMaps(client)
+		}
+		if driver == helmdriver.SecretsDriverName {
+			var client corev1client.SecretInterface = clientSet.CoreV1().Secrets(namespace)
+			if len(c.StorageAnnotations) > 0 || c.StorageOwner != nil {
+				annotated := storage.NewAnnotatedSecrets(client, c.StorageAnnotations, c.StorageOwner)
+				client, c.OwnerVerifier = annotated, annotated
 			}
-			if driver == helmdriver.SecretsDriverName {
-				f.Driver = helmdriver.NewSecrets(clientSet.CoreV1().Secrets(namespace))
+			c.Driver = helmdriver.NewSecrets(client)
+		}
+	case helmdriver.SQLDriverName:
+		if c.StorageDSN == "" {
+			return fmt.Errorf("no connection string provided for '%s' storage driver", driver)
+		}
+		var d *helmdriver.SQL
+		var err error
+		if c.SQLDrivers != nil {
+			d, err = c.SQLDrivers.Get(c.StorageDSN, namespace)
+		} else {
+			log := c.StorageLog
+			if log == nil {
+				log = func(string, ...interface{}) {}
 			}
-		case helmdriver.MemoryDriverName:
-			driver := helmdriver.NewMemory()
-			driver.SetNamespace(namespace)
-			f.Driver = driver
-		default:
-			return fmt.Errorf("unsupported Helm storage driver '%s'", driver)
+			d, err = helmdriver.NewSQL(c.StorageDSN, log, namespace)
 		}
-		return nil
+		if err != nil {
+			return fmt.Errorf("could not initialize '%s' storage driver: %w", driver, err)
+		}
+		c.Driver = d
+	case helmdriver.MemoryDriverName:
+		driver := helmdriver.NewMemory()
+		driver.SetNamespace(namespace)
+		c.Driver = driver
 	}
+	return nil
 }
 
-// WithDriver sets the ConfigFactory.Driver.
+// WithDriver sets the ConfigFactory.Driver, overriding any previously
+// applied WithStorage.
 func WithDriver(driver helmdriver.Driver) ConfigFactoryOption {
 	return func(f *ConfigFactory) error {
 		f.Driver = driver
+		f.storageDriver, f.storageNamespace = "", ""
+		return nil
+	}
+}
+
+// WithStorageAnnotations sets the ConfigFactory.StorageAnnotations.
+func WithStorageAnnotations(annotations map[string]string) ConfigFactoryOption {
+	return func(f *ConfigFactory) error {
+		f.StorageAnnotations = annotations
+		return nil
+	}
+}
+
+// WithStorageOwner sets the ConfigFactory.StorageOwner to the given object.
+func WithStorageOwner(obj metav1.Object) ConfigFactoryOption {
+	return func(f *ConfigFactory) error {
+		f.StorageOwner = &storage.Owner{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			UID:       string(obj.GetUID()),
+		}
+		return nil
+	}
+}
+
+// WithStorageDSN sets the ConfigFactory.StorageDSN.
+func WithStorageDSN(dsn string) ConfigFactoryOption {
+	return func(f *ConfigFactory) error {
+		f.StorageDSN = dsn
+		return nil
+	}
+}
+
+// WithSQLDriverCache sets the ConfigFactory.SQLDrivers.
+func WithSQLDriverCache(cache *SQLDriverCache) ConfigFactoryOption {
+	return func(f *ConfigFactory) error {
+		f.SQLDrivers = cache
 		return nil
 	}
 }
@@ -143,6 +319,12 @@ func (c *ConfigFactory) NewStorage(observers ...storage.ObserveFunc) *helmstorag
 	return s
 }
 
+// Store returns a release.Store providing read access to the releases in
+// the Helm storage of the Driver configured on the ConfigFactory.
+func (c *ConfigFactory) Store() release.Store {
+	return release.NewStorageStore(c.NewStorage())
+}
+
 // Build returns a new Helm action.Configuration configured with the receiver
 // values, and the provided logger and observer(s).
 func (c *ConfigFactory) Build(log helmaction.DebugLog, observers ...storage.ObserveFunc) *helmaction.Configuration {
@@ -164,6 +346,17 @@ func (c *ConfigFactory) Build(log helmaction.DebugLog, observers ...storage.Obse
 	}
 }
 
+// VerifyStorageOwner returns a storage.OwnershipError if the Helm storage
+// objects of the release with the given name are owned by another
+// HelmRelease than the ConfigFactory.StorageOwner. It is a no-op for storage
+// drivers which do not support ownership annotations.
+func (c *ConfigFactory) VerifyStorageOwner(ctx context.Context, release string) error {
+	if c.OwnerVerifier == nil {
+		return nil
+	}
+	return c.OwnerVerifier.VerifyOwner(ctx, release)
+}
+
 // Valid returns an error if the ConfigFactory is missing configuration
 // required to run a Helm action.
 func (c *ConfigFactory) Valid() error {
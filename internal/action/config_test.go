@@ -17,6 +17,7 @@ limitations under the License.
 package action
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -25,9 +26,11 @@ import (
 	helmkube "github.com/jessesimpson36/helm/v4/pkg/kube"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdtest "k8s.io/kubectl/pkg/cmd/testing"
 
+	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/kube"
 	"github.com/fluxcd/helm-controller/internal/storage"
 )
@@ -123,6 +126,22 @@ func TestWithStorage(t *testing.T) {
 			factory:    ConfigFactory{},
 			wantDriver: helmdriver.MemoryDriverName,
 		},
+		{
+			name:       "lower case " + helmdriver.ConfigMapsDriverName,
+			driverName: "configmap",
+			namespace:  "default",
+			factory: ConfigFactory{
+				KubeClient: helmkube.New(cmdtest.NewTestFactory()),
+			},
+			wantDriver: helmdriver.ConfigMapsDriverName,
+		},
+		{
+			name:       helmdriver.SQLDriverName + " without connection string",
+			driverName: helmdriver.SQLDriverName,
+			namespace:  "default",
+			factory:    ConfigFactory{},
+			wantErr:    errors.New("no connection string provided for 'SQL' storage driver"),
+		},
 		{
 			name:       "invalid namespace",
 			driverName: helmdriver.SecretsDriverName,
@@ -144,6 +163,9 @@ func TestWithStorage(t *testing.T) {
 
 			factory := tt.factory
 			err := WithStorage(tt.driverName, tt.namespace)(&factory)
+			if err == nil {
+				err = factory.buildStorage()
+			}
 			if tt.wantErr != nil {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(factory.Driver).To(BeNil())
@@ -157,6 +179,109 @@ func TestWithStorage(t *testing.T) {
 	}
 }
 
+func TestParseStorageDriver(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "secret", want: helmdriver.SecretsDriverName},
+		{name: "Secret", want: helmdriver.SecretsDriverName},
+		{name: "configmap", want: helmdriver.ConfigMapsDriverName},
+		{name: "sql", want: helmdriver.SQLDriverName},
+		{name: "memory", want: helmdriver.MemoryDriverName},
+		{name: "invalid", wantErr: true},
+		{name: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ParseStorageDriver(tt.name)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMaxHistory(t *testing.T) {
+	t.Run("returns spec value", func(t *testing.T) {
+		g := NewWithT(t)
+
+		maxHistory := 0
+		obj := &v2.HelmRelease{Spec: v2.HelmReleaseSpec{MaxHistory: &maxHistory}}
+		g.Expect(MaxHistory(obj)).To(Equal(0))
+	})
+
+	t.Run("returns default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		defaultMaxHistory := DefaultMaxHistory
+		t.Cleanup(func() { DefaultMaxHistory = defaultMaxHistory })
+		DefaultMaxHistory = 20
+
+		g.Expect(MaxHistory(&v2.HelmRelease{})).To(Equal(20))
+	})
+}
+
+func TestWithStorageAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	annotations := map[string]string{"foo": "bar"}
+	factory := &ConfigFactory{
+		KubeClient: helmkube.New(cmdtest.NewTestFactory()),
+	}
+	g.Expect(WithStorageAnnotations(annotations)(factory)).To(Succeed())
+	g.Expect(factory.StorageAnnotations).To(Equal(annotations))
+
+	g.Expect(WithStorage(helmdriver.SecretsDriverName, "default")(factory)).To(Succeed())
+	g.Expect(factory.buildStorage()).To(Succeed())
+	g.Expect(factory.Driver).ToNot(BeNil())
+	g.Expect(factory.Driver.Name()).To(Equal(helmdriver.SecretsDriverName))
+	g.Expect(factory.OwnerVerifier).ToNot(BeNil())
+}
+
+func TestWithStorageAnnotations_afterWithStorage(t *testing.T) {
+	g := NewWithT(t)
+
+	factory, err := NewConfigFactory(cmdtest.NewTestFactory(),
+		WithStorage(helmdriver.SecretsDriverName, "default"),
+		WithStorageAnnotations(map[string]string{"foo": "bar"}),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(factory.Driver.Name()).To(Equal(helmdriver.SecretsDriverName))
+	g.Expect(factory.OwnerVerifier).ToNot(BeNil())
+}
+
+func TestWithStorageOwner(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default", UID: "uid"},
+	}
+	factory := &ConfigFactory{
+		KubeClient: helmkube.New(cmdtest.NewTestFactory()),
+	}
+	g.Expect(WithStorageOwner(obj)(factory)).To(Succeed())
+	g.Expect(factory.StorageOwner).To(Equal(&storage.Owner{Name: "podinfo", Namespace: "default", UID: "uid"}))
+
+	g.Expect(WithStorage(helmdriver.SecretsDriverName, "default")(factory)).To(Succeed())
+	g.Expect(factory.buildStorage()).To(Succeed())
+	g.Expect(factory.OwnerVerifier).ToNot(BeNil())
+
+	// Storage drivers which do not support annotations can not verify
+	// ownership.
+	factory.OwnerVerifier = nil
+	g.Expect(WithStorage(helmdriver.MemoryDriverName, "default")(factory)).To(Succeed())
+	g.Expect(factory.buildStorage()).To(Succeed())
+	g.Expect(factory.OwnerVerifier).To(BeNil())
+	g.Expect(factory.VerifyStorageOwner(context.TODO(), "podinfo")).To(Succeed())
+}
+
 func TestWithDriver(t *testing.T) {
 	g := NewWithT(t)
 
@@ -223,6 +348,28 @@ func TestConfigFactory_NewStorage(t *testing.T) {
 	})
 }
 
+func TestConfigFactory_Store(t *testing.T) {
+	g := NewWithT(t)
+
+	driver := helmdriver.NewMemory()
+	driver.SetNamespace("default")
+	factory := &ConfigFactory{
+		Driver: driver,
+	}
+
+	rls := &helmrelease.Release{
+		Name:      "release",
+		Namespace: "default",
+		Version:   1,
+		Info:      &helmrelease.Info{Status: helmrelease.StatusDeployed},
+	}
+	g.Expect(factory.NewStorage().Create(rls)).To(Succeed())
+
+	got, err := factory.Store().LastDeployed("release")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Version).To(Equal(1))
+}
+
 func TestConfigFactory_Build(t *testing.T) {
 	t.Run("build", func(t *testing.T) {
 		g := NewWithT(t)
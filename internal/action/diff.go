@@ -29,9 +29,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	apierrutil "k8s.io/apimachinery/pkg/util/errors"
-	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"github.com/fluxcd/cli-utils/pkg/object"
 	"github.com/fluxcd/pkg/ssa"
@@ -41,20 +39,18 @@ import (
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/diff"
+	"github.com/fluxcd/helm-controller/internal/kube"
 )
 
 // Diff returns a jsondiff.DiffSet of the changes between the state of the
 // cluster and the Helm release.Release manifest.
 func Diff(ctx context.Context, config *helmaction.Configuration, rls *helmrelease.Release, fieldOwner string, ignore ...v2.IgnoreRule) (jsondiff.DiffSet, error) {
 	// Create a dry-run only client to use solely for diffing.
-	cfg, err := config.RESTClientGetter.ToRESTConfig()
-	if err != nil {
-		return nil, err
-	}
-	c, err := client.New(cfg, client.Options{DryRun: ptr.To(true)})
+	c, err := kube.NewClient(config.RESTClientGetter)
 	if err != nil {
 		return nil, err
 	}
+	c = client.NewDryRunClient(c)
 
 	// Read the release manifest and normalize the objects.
 	objects, err := ssautil.ReadObjects(strings.NewReader(rls.Manifest))
@@ -65,34 +61,16 @@ func Diff(ctx context.Context, config *helmaction.Configuration, rls *helmreleas
 		return nil, fmt.Errorf("failed to normalize release objects: %w", err)
 	}
 
-	var (
-		isNamespacedGVK = map[string]bool{}
-		errs            []error
-	)
+	var errs []error
 	for _, obj := range objects {
 		// Set the Helm metadata on the object which is normally set by Helm
 		// during object creation.
 		setHelmMetadata(obj, rls)
 
-		// Set the namespace of the object if it is not set.
-		if obj.GetNamespace() == "" {
-			// Manifest does not contain the namespace of the release.
-			// Figure out if the object is namespaced if the namespace is not
-			// explicitly set, and configure the namespace accordingly.
-			objGVK := obj.GetObjectKind().GroupVersionKind().String()
-			if _, ok := isNamespacedGVK[objGVK]; !ok {
-				namespaced, err := apiutil.IsObjectNamespaced(obj, c.Scheme(), c.RESTMapper())
-				if err != nil {
-					errs = append(errs, fmt.Errorf("failed to determine if %s is namespace scoped: %w",
-						obj.GetObjectKind().GroupVersionKind().Kind, err))
-					continue
-				}
-				// Cache the result, so we don't have to do this for every object
-				isNamespacedGVK[objGVK] = namespaced
-			}
-			if isNamespacedGVK[objGVK] {
-				obj.SetNamespace(rls.Namespace)
-			}
+		// Manifest does not contain the namespace of the release, set it on
+		// the namespace scoped objects without an explicit namespace.
+		if err := kube.SetDefaultNamespace(c.RESTMapper(), obj, rls.Namespace); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
@@ -138,11 +116,7 @@ func Diff(ctx context.Context, config *helmaction.Configuration, rls *helmreleas
 // ApplyDiff applies the changes described in the provided jsondiff.DiffSet to
 // the Kubernetes cluster.
 func ApplyDiff(ctx context.Context, config *helmaction.Configuration, diffSet jsondiff.DiffSet, fieldOwner string) (*ssa.ChangeSet, error) {
-	cfg, err := config.RESTClientGetter.ToRESTConfig()
-	if err != nil {
-		return nil, err
-	}
-	c, err := client.New(cfg, client.Options{})
+	c, err := kube.NewClient(config.RESTClientGetter)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"context"
+	"errors"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmchart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	helmchartutil "github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/diff"
+	"github.com/fluxcd/helm-controller/internal/release"
+)
+
+// dryRunOption is the Helm dry-run option used by DryRun. It allows the
+// templates of the chart to look up objects in the cluster.
+const dryRunOption = "server"
+
+// DryRun renders the release of the given object by performing a server-side
+// dry-run of the Helm upgrade action, or of the Helm install action if there
+// is no release in the storage. It returns the rendered release, and the
+// latest release in the storage (which is nil if there is none).
+//
+// Contrary to Install and Upgrade, it does not apply the CRDs of the chart,
+// and does not write to the Helm storage.
+func DryRun(ctx context.Context, config *helmaction.Configuration, obj *v2.HelmRelease, chrt *helmchart.Chart,
+	vals helmchartutil.Values) (rendered *helmrelease.Release, current *helmrelease.Release, err error) {
+	current, err = LastRelease(release.NewStorageStore(config.Releases), obj.GetReleaseName())
+	if err != nil && !errors.Is(err, ErrReleaseNotFound) {
+		return nil, nil, err
+	}
+
+	if current == nil {
+		install := newInstall(ctx, config, obj, []InstallOption{installDryRun})
+		rendered, err = install.RunWithContext(ctx, chrt, vals.AsMap())
+		return rendered, nil, err
+	}
+
+	upgrade := newUpgrade(ctx, config, obj, []UpgradeOption{upgradeDryRun})
+	rendered, err = upgrade.RunWithContext(ctx, release.ShortenName(obj.GetReleaseName()), chrt, vals.AsMap())
+	return rendered, current, err
+}
+
+// installDryRun is an InstallOption which enables the server-side dry-run
+// setting.
+func installDryRun(install *helmaction.Install) {
+	install.DryRun = true
+	install.DryRunOption = dryRunOption
+}
+
+// upgradeDryRun is an UpgradeOption which enables the server-side dry-run
+// setting.
+func upgradeDryRun(upgrade *helmaction.Upgrade) {
+	upgrade.DryRun = true
+	upgrade.DryRunOption = dryRunOption
+}
+
+// DryRunSummary is a machine-readable summary of the changes a release
+// rendered by DryRun would make compared to the current release, e.g. to
+// comment the impact of a pending spec change on a pull request.
+type DryRunSummary struct {
+	// Action is the Helm action the release would be made with, either
+	// "install" or "upgrade".
+	Action string `json:"action"`
+	// Chart holds the chart of the rendered and current release.
+	Chart DryRunChange `json:"chart"`
+	// Target holds the release name and namespace of the rendered and
+	// current release.
+	Target DryRunChange `json:"target"`
+	// Values holds the keys of the values which would change.
+	Values diff.ValuesChanges `json:"values"`
+	// Resources holds the resources which would change.
+	Resources diff.ManifestChanges `json:"resources"`
+}
+
+// DryRunChange holds the rendered and current (previous) value of an
+// attribute of a release, in the format of `name@version` for charts and
+// `namespace/name` for targets.
+type DryRunChange struct {
+	Current  string `json:"current,omitempty"`
+	Rendered string `json:"rendered"`
+	Changed  bool   `json:"changed"`
+}
+
+// SummarizeDryRun returns a DryRunSummary of the changes the rendered release
+// would make compared to the current release, which is nil if there is none.
+func SummarizeDryRun(rendered, current *helmrelease.Release) (*DryRunSummary, error) {
+	summary := &DryRunSummary{
+		Action: "install",
+		Chart:  DryRunChange{Rendered: releaseChart(rendered), Changed: true},
+		Target: DryRunChange{Rendered: rendered.Namespace + "/" + rendered.Name, Changed: true},
+	}
+
+	var currentManifest string
+	var currentValues map[string]any
+	if current != nil {
+		summary.Action = "upgrade"
+		summary.Chart.Current = releaseChart(current)
+		summary.Chart.Changed = summary.Chart.Current != summary.Chart.Rendered
+		summary.Target.Current = current.Namespace + "/" + current.Name
+		summary.Target.Changed = summary.Target.Current != summary.Target.Rendered
+		currentManifest = current.Manifest
+		currentValues = current.Config
+	}
+
+	summary.Values = diff.Values(currentValues, rendered.Config)
+	resources, err := diff.Manifests(currentManifest, rendered.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	summary.Resources = resources
+	return summary, nil
+}
+
+// releaseChart returns the chart of the given release in the format of
+// `name@version`.
+func releaseChart(rls *helmrelease.Release) string {
+	if rls.Chart == nil || rls.Chart.Metadata == nil {
+		return ""
+	}
+	return rls.Chart.Metadata.Name + "@" + rls.Chart.Metadata.Version
+}
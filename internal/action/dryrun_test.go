@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"testing"
+
+	helmchart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	. "github.com/onsi/gomega"
+)
+
+func TestSummarizeDryRun(t *testing.T) {
+	newRelease := func(version string, values map[string]any, manifest string) *helmrelease.Release {
+		return &helmrelease.Release{
+			Name:      "podinfo",
+			Namespace: "default",
+			Chart: &helmchart.Chart{
+				Metadata: &helmchart.Metadata{Name: "podinfo", Version: version},
+			},
+			Config:   values,
+			Manifest: manifest,
+		}
+	}
+	manifest := "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: podinfo\n  namespace: default\ndata:\n  key: value\n"
+
+	t.Run("install", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got, err := SummarizeDryRun(newRelease("6.6.1", map[string]any{"replicaCount": 2}, manifest), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Action).To(Equal("install"))
+		g.Expect(got.Chart).To(Equal(DryRunChange{Rendered: "podinfo@6.6.1", Changed: true}))
+		g.Expect(got.Target).To(Equal(DryRunChange{Rendered: "default/podinfo", Changed: true}))
+		g.Expect(got.Values.Added).To(Equal([]string{"replicaCount"}))
+		g.Expect(got.Resources.Added).To(Equal([]string{"ConfigMap/default/podinfo"}))
+	})
+
+	t.Run("upgrade", func(t *testing.T) {
+		g := NewWithT(t)
+
+		current := newRelease("6.5.0", map[string]any{"replicaCount": 1}, manifest)
+		rendered := newRelease("6.6.1", map[string]any{"replicaCount": 2}, manifest)
+		got, err := SummarizeDryRun(rendered, current)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Action).To(Equal("upgrade"))
+		g.Expect(got.Chart).To(Equal(DryRunChange{Current: "podinfo@6.5.0", Rendered: "podinfo@6.6.1", Changed: true}))
+		g.Expect(got.Target.Changed).To(BeFalse())
+		g.Expect(got.Values.Changed).To(Equal([]string{"replicaCount"}))
+		g.Expect(got.Resources.Empty()).To(BeTrue())
+	})
+}
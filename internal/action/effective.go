@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"k8s.io/utils/ptr"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/release"
+)
+
+// EffectiveSpec returns a copy of the v2.HelmReleaseSpec of the given object,
+// in which the fields the object does not configure are set to the defaults
+// the controller acts on. This includes the defaults of the API, and the
+// defaults configured on the controller (e.g. DefaultMaxHistory and
+// DefaultStorageDriver).
+//
+// It allows users to inspect the exact configuration used for the Helm
+// actions of the object, and is not used to perform the actions.
+func EffectiveSpec(obj *v2.HelmRelease) v2.HelmReleaseSpec {
+	obj = obj.DeepCopy()
+	spec := &obj.Spec
+	timeout := obj.GetTimeout()
+	ignoreTestFailures := obj.GetTest().IgnoreFailures
+
+	spec.ReleaseName = release.ShortenName(obj.GetReleaseName())
+	spec.TargetNamespace = obj.GetReleaseNamespace()
+	spec.StorageNamespace = obj.GetStorageNamespace()
+	spec.StorageDriver = StorageDriver(obj)
+	spec.Timeout = &timeout
+	spec.MaxHistory = ptr.To(MaxHistory(obj))
+	spec.HistoryPolicy = obj.GetHistoryPolicy()
+	spec.ArtifactVerification = obj.GetArtifactVerification(DefaultArtifactVerification)
+	spec.PersistentClient = ptr.To(obj.UsePersistentClient())
+
+	driftDetection := obj.GetDriftDetection()
+	driftDetection.Mode = driftDetection.GetMode()
+	spec.DriftDetection = &driftDetection
+
+	install := obj.GetInstall()
+	install.Timeout = ptr.To(install.GetTimeout(timeout))
+	install.CRDs, _ = crdPolicyOrDefault(install.CRDs)
+	installRemediation := install.GetRemediation().(v2.InstallRemediation)
+	installRemediation.IgnoreTestFailures = ptr.To(installRemediation.MustIgnoreTestFailures(ignoreTestFailures))
+	installRemediation.RemediateLastFailure = ptr.To(installRemediation.MustRemediateLastFailure())
+	install.Remediation = &installRemediation
+	spec.Install = &install
+
+	upgrade := obj.GetUpgrade()
+	upgrade.Timeout = ptr.To(upgrade.GetTimeout(timeout))
+	upgrade.CRDs, _ = crdPolicyOrDefault(upgrade.CRDs)
+	upgradeRemediation := upgrade.GetRemediation().(v2.UpgradeRemediation)
+	upgradeRemediation.IgnoreTestFailures = ptr.To(upgradeRemediation.MustIgnoreTestFailures(ignoreTestFailures))
+	upgradeRemediation.RemediateLastFailure = ptr.To(upgradeRemediation.MustRemediateLastFailure())
+	upgradeRemediation.Strategy = ptr.To(upgradeRemediation.GetStrategy())
+	upgrade.Remediation = &upgradeRemediation
+	spec.Upgrade = &upgrade
+
+	test := obj.GetTest()
+	test.Timeout = ptr.To(test.GetTimeout(timeout))
+	spec.Test = &test
+
+	rollback := obj.GetRollback()
+	rollback.Timeout = ptr.To(rollback.GetTimeout(timeout))
+	spec.Rollback = &rollback
+
+	uninstall := obj.GetUninstall()
+	uninstall.Timeout = ptr.To(uninstall.GetTimeout(timeout))
+	uninstall.DeletionPropagation = ptr.To(uninstall.GetDeletionPropagation())
+	spec.Uninstall = &uninstall
+
+	return *spec
+}
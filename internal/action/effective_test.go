@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestEffectiveSpec(t *testing.T) {
+	t.Run("fills in defaults", func(t *testing.T) {
+		g := NewWithT(t)
+
+		defaultMaxHistory := DefaultMaxHistory
+		t.Cleanup(func() { DefaultMaxHistory = defaultMaxHistory })
+		DefaultMaxHistory = 7
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "podinfo",
+				Namespace: "apps",
+			},
+			Spec: v2.HelmReleaseSpec{
+				Interval: metav1.Duration{Duration: time.Minute},
+			},
+		}
+
+		spec := EffectiveSpec(obj)
+		g.Expect(spec.Interval).To(Equal(metav1.Duration{Duration: time.Minute}))
+		g.Expect(spec.ReleaseName).To(Equal("podinfo"))
+		g.Expect(spec.TargetNamespace).To(Equal("apps"))
+		g.Expect(spec.StorageNamespace).To(Equal("apps"))
+		g.Expect(spec.StorageDriver).To(Equal("secret"))
+		g.Expect(spec.MaxHistory).To(Equal(ptr.To(7)))
+		g.Expect(spec.HistoryPolicy).To(Equal(v2.AllHistoryPolicy))
+		g.Expect(spec.ArtifactVerification).To(Equal(DefaultArtifactVerification))
+		g.Expect(spec.PersistentClient).To(Equal(ptr.To(true)))
+		g.Expect(spec.DriftDetection.Mode).To(Equal(v2.DriftDetectionDisabled))
+		g.Expect(spec.Timeout).To(Equal(&metav1.Duration{Duration: 5 * time.Minute}))
+		g.Expect(spec.Install.Timeout).To(Equal(spec.Timeout))
+		g.Expect(spec.Install.CRDs).To(Equal(DefaultCRDPolicy))
+		g.Expect(spec.Install.Remediation.RemediateLastFailure).To(Equal(ptr.To(false)))
+		g.Expect(spec.Upgrade.Remediation.Strategy).To(Equal(ptr.To(v2.RollbackRemediationStrategy)))
+		g.Expect(spec.Upgrade.Remediation.IgnoreTestFailures).To(Equal(ptr.To(false)))
+		g.Expect(spec.Test.Timeout).To(Equal(spec.Timeout))
+		g.Expect(spec.Rollback.Timeout).To(Equal(spec.Timeout))
+		g.Expect(spec.Uninstall.Timeout).To(Equal(spec.Timeout))
+		g.Expect(spec.Uninstall.DeletionPropagation).To(Equal(ptr.To("background")))
+
+		// The object itself is not mutated.
+		g.Expect(obj.Spec.Install).To(BeNil())
+		g.Expect(obj.Spec.MaxHistory).To(BeNil())
+	})
+
+	t.Run("retains configured values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "podinfo",
+				Namespace: "apps",
+			},
+			Spec: v2.HelmReleaseSpec{
+				ReleaseName:     "frontend",
+				TargetNamespace: "web",
+				Timeout:         &metav1.Duration{Duration: 10 * time.Minute},
+				MaxHistory:      ptr.To(2),
+				Test:            &v2.Test{IgnoreFailures: true},
+				Install: &v2.Install{
+					Timeout: &metav1.Duration{Duration: time.Minute},
+				},
+				Upgrade: &v2.Upgrade{
+					Remediation: &v2.UpgradeRemediation{
+						Retries:  3,
+						Strategy: ptr.To(v2.UninstallRemediationStrategy),
+					},
+				},
+			},
+		}
+
+		spec := EffectiveSpec(obj)
+		g.Expect(spec.ReleaseName).To(Equal("frontend"))
+		g.Expect(spec.TargetNamespace).To(Equal("web"))
+		g.Expect(spec.StorageNamespace).To(Equal("apps"))
+		g.Expect(spec.MaxHistory).To(Equal(ptr.To(2)))
+		g.Expect(spec.Install.Timeout).To(Equal(&metav1.Duration{Duration: time.Minute}))
+		g.Expect(spec.Upgrade.Timeout).To(Equal(&metav1.Duration{Duration: 10 * time.Minute}))
+		g.Expect(spec.Upgrade.Remediation.Retries).To(Equal(3))
+		g.Expect(spec.Upgrade.Remediation.Strategy).To(Equal(ptr.To(v2.UninstallRemediationStrategy)))
+		g.Expect(spec.Upgrade.Remediation.RemediateLastFailure).To(Equal(ptr.To(true)))
+		g.Expect(spec.Upgrade.Remediation.IgnoreTestFailures).To(Equal(ptr.To(true)))
+	})
+}
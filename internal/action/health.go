@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	"github.com/fluxcd/helm-controller/internal/kube"
+)
+
+// healthCheckInterval is the interval at which the status of the resources
+// is polled during a health assessment.
+const healthCheckInterval = 2 * time.Second
+
+// CheckHealth waits for the resources in the manifest of the given release to
+// become healthy, as determined by kstatus, e.g. Deployments being Available
+// and custom resources being Ready. It returns an error listing the resources
+// which are not healthy once the timeout has expired.
+//
+// Resources the controller is not allowed to read are excluded from the
+// assessment instead of failing it, and their kinds are returned as skipped.
+//
+// Hooks are not part of the manifest of a release, and are therefore not
+// assessed.
+func CheckHealth(ctx context.Context, config *helmaction.Configuration, rls *helmrelease.Release, timeout time.Duration) (skipped []string, err error) {
+	objects, err := ssautil.ReadObjects(strings.NewReader(rls.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read objects from release manifest: %w", err)
+	}
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	c, err := kube.NewClient(config.RESTClientGetter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setDefaultNamespace(c.RESTMapper(), objects, rls.Namespace); err != nil {
+		return nil, err
+	}
+
+	objects, skipped = filterReadable(ctx, c, objects)
+	if len(objects) == 0 {
+		return skipped, nil
+	}
+
+	// The resource manager does not accept a context, so the assessment is
+	// bounded by the deadline of the context instead.
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline))
+	}
+
+	poller := polling.NewStatusPoller(c, c.RESTMapper(), polling.Options{})
+	rm := ssa.NewResourceManager(c, poller, ssa.Owner{})
+	return skipped, rm.WaitForSet(object.UnstructuredSetToObjMetadataSet(objects), ssa.WaitOptions{
+		Interval: healthCheckInterval,
+		Timeout:  timeout,
+	})
+}
+
+// filterReadable returns the objects the given client is allowed to read,
+// and the sorted kinds of the objects it is forbidden from reading. Any
+// other error is left for the status poller to surface.
+func filterReadable(ctx context.Context, c client.Client, objects []*unstructured.Unstructured) ([]*unstructured.Unstructured, []string) {
+	var readable []*unstructured.Unstructured
+	forbidden := make(map[string]struct{})
+	for _, obj := range objects {
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil &&
+			(apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err)) {
+			forbidden[obj.GroupVersionKind().GroupKind().String()] = struct{}{}
+			continue
+		}
+		readable = append(readable, obj)
+	}
+
+	var kinds []string
+	for k := range forbidden {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return readable, kinds
+}
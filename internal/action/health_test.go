@@ -0,0 +1,175 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/helm-controller/internal/kube"
+)
+
+func TestCheckHealth(t *testing.T) {
+	config, cleanup := newTestCluster(t)
+	t.Cleanup(func() {
+		if err := cleanup(); err != nil {
+			t.Logf("Failed to stop the test environment: %v", err)
+		}
+	})
+
+	getter := kube.NewMemoryRESTClientGetter(config)
+	c, err := client.New(config, client.Options{})
+	if err != nil {
+		t.Fatalf("Failed to create client for test environment: %v", err)
+	}
+
+	g := NewWithT(t)
+
+	ns, err := generateNamespace(context.TODO(), c, "health")
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(func() {
+		_ = c.Delete(context.TODO(), ns)
+	})
+
+	g.Expect(c.Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: ns.Name},
+	})).To(Succeed())
+
+	labels := map[string]string{"app": "podinfo"}
+	g.Expect(c.Create(context.TODO(), &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: ns.Name},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "podinfo", Image: "ghcr.io/stefanprodan/podinfo"}},
+				},
+			},
+		},
+	})).To(Succeed())
+
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{
+			name: "healthy resources",
+			manifest: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`,
+		},
+		{
+			name: "unhealthy resources",
+			manifest: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+`,
+			wantErr: "Deployment/" + ns.Name + "/podinfo",
+		},
+		{
+			name:     "empty manifest",
+			manifest: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			rls := &helmrelease.Release{
+				Name:      "release",
+				Namespace: ns.Name,
+				Manifest:  tt.manifest,
+			}
+			skipped, err := CheckHealth(context.TODO(), &helmaction.Configuration{RESTClientGetter: getter}, rls, time.Second)
+			g.Expect(skipped).To(BeEmpty())
+			if tt.wantErr == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+		})
+	}
+
+	t.Run("skips forbidden kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		const user = "health-check"
+		g.Expect(c.Create(context.TODO(), &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: user, Namespace: ns.Name},
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{""},
+				Resources: []string{"configmaps"},
+				Verbs:     []string{"get", "list", "watch"},
+			}},
+		})).To(Succeed())
+		g.Expect(c.Create(context.TODO(), &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: user, Namespace: ns.Name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: user},
+			Subjects:   []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: user}},
+		})).To(Succeed())
+
+		impersonated := rest.CopyConfig(config)
+		impersonated.Impersonate = rest.ImpersonationConfig{UserName: user}
+
+		rls := &helmrelease.Release{
+			Name:      "release",
+			Namespace: ns.Name,
+			Manifest: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+`,
+		}
+		skipped, err := CheckHealth(context.TODO(), &helmaction.Configuration{
+			RESTClientGetter: kube.NewMemoryRESTClientGetter(impersonated),
+		}, rls, time.Second)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(skipped).To(Equal([]string{"Deployment.apps"}))
+	})
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"errors"
+	"fmt"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	releaseutil "github.com/jessesimpson36/helm/v4/pkg/release/util"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	apierrutil "k8s.io/apimachinery/pkg/util/errors"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/release"
+)
+
+// PruneHistory prunes the revisions of the release of the given object from
+// the Helm storage according to the v2.PruneFailedHistoryPolicy, and returns
+// the pruned revisions. It is a no-op for any other history policy, as Helm
+// enforces the maximum history itself in that case.
+//
+// The latest revision is always retained. Of the other revisions, the most
+// recent deployed or superseded revisions are retained up to the maximum
+// history (including the latest revision), while all other revisions (e.g.
+// failed or pending upgrade attempts) are pruned. This keeps rollback
+// targets available, while preventing a remediation loop from growing the
+// storage.
+func PruneHistory(config *helmaction.Configuration, obj *v2.HelmRelease) ([]*helmrelease.Release, error) {
+	if obj.GetHistoryPolicy() != v2.PruneFailedHistoryPolicy {
+		return nil, nil
+	}
+
+	history, err := config.Releases.History(release.ShortenName(obj.GetReleaseName()))
+	if err != nil {
+		if errors.Is(err, helmdriver.ErrReleaseNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(history) < 2 {
+		return nil, nil
+	}
+	releaseutil.Reverse(history, releaseutil.SortByRevision)
+
+	var (
+		maxHistory = MaxHistory(obj)
+		retained   = 1
+		pruned     []*helmrelease.Release
+		errs       []error
+	)
+	for _, rls := range history[1:] {
+		if isRollbackTarget(rls) && (maxHistory == 0 || retained < maxHistory) {
+			retained++
+			continue
+		}
+		if _, err := config.Releases.Delete(rls.Name, rls.Version); err != nil {
+			errs = append(errs, fmt.Errorf("failed to prune release %s/%s.v%d: %w",
+				rls.Namespace, rls.Name, rls.Version, err))
+			continue
+		}
+		pruned = append(pruned, rls)
+	}
+	return pruned, apierrutil.NewAggregate(errs)
+}
+
+// isRollbackTarget returns true if the given release was successfully
+// deployed, and can therefore be rolled back to.
+func isRollbackTarget(rls *helmrelease.Release) bool {
+	if rls.Info == nil {
+		return false
+	}
+	switch rls.Info.Status {
+	case helmrelease.StatusDeployed, helmrelease.StatusSuperseded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"testing"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
+	"github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+func TestPruneHistory(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       v2.HistoryPolicy
+		maxHistory   int
+		statuses     []helmrelease.Status
+		wantRetained []int
+		wantPruned   []int
+	}{
+		{
+			name:       "no-op without prune failed policy",
+			policy:     v2.AllHistoryPolicy,
+			maxHistory: 2,
+			statuses: []helmrelease.Status{
+				helmrelease.StatusSuperseded,
+				helmrelease.StatusFailed,
+				helmrelease.StatusDeployed,
+			},
+			wantRetained: []int{1, 2, 3},
+		},
+		{
+			name:       "prunes failed revisions",
+			policy:     v2.PruneFailedHistoryPolicy,
+			maxHistory: 5,
+			statuses: []helmrelease.Status{
+				helmrelease.StatusSuperseded,
+				helmrelease.StatusFailed,
+				helmrelease.StatusFailed,
+				helmrelease.StatusSuperseded,
+				helmrelease.StatusFailed,
+				helmrelease.StatusDeployed,
+			},
+			wantRetained: []int{1, 4, 6},
+			wantPruned:   []int{2, 3, 5},
+		},
+		{
+			name:       "retains failed latest revision",
+			policy:     v2.PruneFailedHistoryPolicy,
+			maxHistory: 5,
+			statuses: []helmrelease.Status{
+				helmrelease.StatusDeployed,
+				helmrelease.StatusFailed,
+				helmrelease.StatusFailed,
+			},
+			wantRetained: []int{1, 3},
+			wantPruned:   []int{2},
+		},
+		{
+			name:       "retains successful revisions up to max history",
+			policy:     v2.PruneFailedHistoryPolicy,
+			maxHistory: 2,
+			statuses: []helmrelease.Status{
+				helmrelease.StatusSuperseded,
+				helmrelease.StatusSuperseded,
+				helmrelease.StatusFailed,
+				helmrelease.StatusPendingUpgrade,
+			},
+			wantRetained: []int{2, 4},
+			wantPruned:   []int{1, 3},
+		},
+		{
+			name:       "retains all successful revisions without max history",
+			policy:     v2.PruneFailedHistoryPolicy,
+			maxHistory: 0,
+			statuses: []helmrelease.Status{
+				helmrelease.StatusSuperseded,
+				helmrelease.StatusSuperseded,
+				helmrelease.StatusFailed,
+				helmrelease.StatusDeployed,
+			},
+			wantRetained: []int{1, 2, 4},
+			wantPruned:   []int{3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s := helmstorage.Init(driver.NewMemory())
+			for i, status := range tt.statuses {
+				g.Expect(s.Create(testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+					Name:      "release",
+					Namespace: "default",
+					Version:   i + 1,
+					Status:    status,
+				}))).To(Succeed())
+			}
+
+			obj := &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "release",
+					Namespace: "default",
+				},
+				Spec: v2.HelmReleaseSpec{
+					MaxHistory:    &tt.maxHistory,
+					HistoryPolicy: tt.policy,
+				},
+			}
+
+			pruned, err := PruneHistory(&helmaction.Configuration{Releases: s}, obj)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var prunedVersions []int
+			for _, rls := range pruned {
+				prunedVersions = append(prunedVersions, rls.Version)
+			}
+			g.Expect(prunedVersions).To(ConsistOf(tt.wantPruned))
+
+			history, err := s.History("release")
+			g.Expect(err).ToNot(HaveOccurred())
+			var retainedVersions []int
+			for _, rls := range history {
+				retainedVersions = append(retainedVersions, rls.Version)
+			}
+			g.Expect(retainedVersions).To(ConsistOf(tt.wantRetained))
+		})
+	}
+
+	t.Run("without release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "default"},
+			Spec:       v2.HelmReleaseSpec{HistoryPolicy: v2.PruneFailedHistoryPolicy},
+		}
+		pruned, err := PruneHistory(&helmaction.Configuration{Releases: helmstorage.Init(driver.NewMemory())}, obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pruned).To(BeEmpty())
+	})
+}
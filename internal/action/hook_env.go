@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"context"
+	"fmt"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/kube"
+	"github.com/fluxcd/helm-controller/internal/release"
+)
+
+// hookEnvSecretSuffix is the suffix of the name of the Secret holding the
+// environment variables for the hooks of a release.
+const hookEnvSecretSuffix = "-hook-env"
+
+// HookEnvSecretName returns the name of the Secret holding the environment
+// variables for the hooks of the release of the given object.
+func HookEnvSecretName(obj *v2.HelmRelease) string {
+	return release.ShortenName(obj.GetReleaseName()) + hookEnvSecretSuffix
+}
+
+// HookEnv manages the Secret named after HookEnvSecretName in the namespace
+// of the release, which exposes environment variables to the hooks of a Helm
+// action. It only modifies a Secret with the origin labels of the object, to
+// not overwrite or delete a Secret with the same name managed by others.
+type HookEnv struct {
+	client client.Client
+	obj    *v2.HelmRelease
+}
+
+// NewHookEnv returns a HookEnv for the release of the given object, using a
+// client for the cluster of the given Helm action configuration.
+func NewHookEnv(config *helmaction.Configuration, obj *v2.HelmRelease) (*HookEnv, error) {
+	c, err := kube.NewClient(config.RESTClientGetter)
+	if err != nil {
+		return nil, err
+	}
+	return &HookEnv{client: c, obj: obj}, nil
+}
+
+// Apply writes the given environment variables to the Secret, for the hooks
+// of the next Helm action to consume. The Secret is replaced on every call,
+// so it only holds the variables of the action which is about to run, and
+// should be deleted using Delete once the action has completed.
+//
+// It is a no-op if the object does not configure environment variables for
+// any action, to not require access to Secrets for other releases.
+func (e *HookEnv) Apply(ctx context.Context, env map[string][]byte) error {
+	if !e.obj.HasExtraEnv() {
+		return nil
+	}
+
+	// The Secret is written before the Helm install action creates the
+	// namespace of the release.
+	if e.obj.Spec.TargetNamespace != "" && e.obj.GetInstall().CreateNamespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: e.obj.GetReleaseNamespace()}}
+		if err := e.client.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create namespace '%s': %w", ns.Name, err)
+		}
+	}
+
+	secret := e.secret()
+	if err := e.client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get hook environment Secret '%s/%s': %w", secret.Namespace, secret.Name, err)
+		}
+		secret.Labels = originLabels(v2.GroupVersion.Group, e.obj.Namespace, e.obj.Name)
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = env
+		if err := e.client.Create(ctx, secret); err != nil {
+			return fmt.Errorf("failed to create hook environment Secret '%s/%s': %w", secret.Namespace, secret.Name, err)
+		}
+		return nil
+	}
+	if !e.owns(secret) {
+		return fmt.Errorf("hook environment Secret '%s/%s' already exists and is not managed by HelmRelease '%s/%s'",
+			secret.Namespace, secret.Name, e.obj.Namespace, e.obj.Name)
+	}
+	secret.Data = env
+	if err := e.client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to update hook environment Secret '%s/%s': %w", secret.Namespace, secret.Name, err)
+	}
+	return nil
+}
+
+// Delete deletes the Secret, if it exists and is managed by the object.
+// This includes a Secret left behind from before the environment variables
+// were removed from the object, in which case a lack of access to the
+// Secret is ignored. A Secret with the same name which is not managed by the
+// object is left untouched.
+func (e *HookEnv) Delete(ctx context.Context) error {
+	secret := e.secret()
+	if err := e.client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		if apierrors.IsNotFound(err) || (apierrors.IsForbidden(err) && !e.obj.HasExtraEnv()) {
+			return nil
+		}
+		return fmt.Errorf("failed to get hook environment Secret '%s/%s': %w", secret.Namespace, secret.Name, err)
+	}
+	if !e.owns(secret) {
+		return nil
+	}
+	if err := e.client.Delete(ctx, secret, client.Preconditions{UID: &secret.UID}); err != nil && !apierrors.IsNotFound(err) {
+		if apierrors.IsForbidden(err) && !e.obj.HasExtraEnv() {
+			return nil
+		}
+		return fmt.Errorf("failed to delete hook environment Secret '%s/%s': %w", secret.Namespace, secret.Name, err)
+	}
+	return nil
+}
+
+// owns returns true if the given Secret has the origin labels of the object.
+func (e *HookEnv) owns(secret *corev1.Secret) bool {
+	for k, v := range originLabels(v2.GroupVersion.Group, e.obj.Namespace, e.obj.Name) {
+		if secret.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// secret returns the Secret object with the name and namespace of the
+// hook environment Secret.
+func (e *HookEnv) secret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      HookEnvSecretName(e.obj),
+			Namespace: e.obj.GetReleaseNamespace(),
+		},
+	}
+}
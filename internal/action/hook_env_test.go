@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"context"
+	"testing"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/kube"
+)
+
+func TestHookEnv(t *testing.T) {
+	config, cleanup := newTestCluster(t)
+	t.Cleanup(func() {
+		if err := cleanup(); err != nil {
+			t.Logf("Failed to stop the test environment: %v", err)
+		}
+	})
+
+	getter := kube.NewMemoryRESTClientGetter(config)
+	c, err := client.New(config, client.Options{})
+	if err != nil {
+		t.Fatalf("Failed to create client for test environment: %v", err)
+	}
+
+	g := NewWithT(t)
+
+	ns, err := generateNamespace(context.TODO(), c, "hook-env")
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(func() {
+		_ = c.Delete(context.TODO(), ns)
+	})
+
+	cfg := &helmaction.Configuration{RESTClientGetter: getter}
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: ns.Name},
+		Spec: v2.HelmReleaseSpec{
+			Install: &v2.Install{
+				ExtraEnv: []v2.ExtraEnvVar{
+					{Name: "DB_PASSWORD", SecretKeyRef: meta.SecretKeyReference{Name: "credentials"}},
+				},
+			},
+		},
+	}
+	key := client.ObjectKey{Namespace: ns.Name, Name: "release-hook-env"}
+	g.Expect(HookEnvSecretName(obj)).To(Equal(key.Name))
+
+	hookEnv, err := NewHookEnv(cfg, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The Secret is created with the environment of the action.
+	g.Expect(hookEnv.Apply(context.TODO(), map[string][]byte{"DB_PASSWORD": []byte("secret")})).To(Succeed())
+	secret := &corev1.Secret{}
+	g.Expect(c.Get(context.TODO(), key, secret)).To(Succeed())
+	g.Expect(secret.Data).To(Equal(map[string][]byte{"DB_PASSWORD": []byte("secret")}))
+	g.Expect(secret.Labels).To(HaveKeyWithValue(v2.GroupVersion.Group+"/name", obj.Name))
+
+	// The Secret is replaced with the environment of the next action.
+	g.Expect(hookEnv.Apply(context.TODO(), map[string][]byte{})).To(Succeed())
+	g.Expect(c.Get(context.TODO(), key, secret)).To(Succeed())
+	g.Expect(secret.Data).To(BeEmpty())
+
+	g.Expect(hookEnv.Delete(context.TODO())).To(Succeed())
+	g.Expect(apierrors.IsNotFound(c.Get(context.TODO(), key, secret))).To(BeTrue())
+	g.Expect(hookEnv.Delete(context.TODO())).To(Succeed())
+
+	// A Secret left behind is deleted after the extra env is removed.
+	g.Expect(hookEnv.Apply(context.TODO(), map[string][]byte{"DB_PASSWORD": []byte("secret")})).To(Succeed())
+	obj.Spec.Install = nil
+	g.Expect(hookEnv.Delete(context.TODO())).To(Succeed())
+	g.Expect(apierrors.IsNotFound(c.Get(context.TODO(), key, secret))).To(BeTrue())
+
+	// Without extra env, the Secret is not written.
+	g.Expect(hookEnv.Apply(context.TODO(), nil)).To(Succeed())
+	g.Expect(apierrors.IsNotFound(c.Get(context.TODO(), key, secret))).To(BeTrue())
+
+	// A Secret with the same name which is not managed by the object is
+	// neither overwritten, nor deleted.
+	obj.Spec.Install = &v2.Install{
+		ExtraEnv: []v2.ExtraEnvVar{
+			{Name: "DB_PASSWORD", SecretKeyRef: meta.SecretKeyReference{Name: "credentials"}},
+		},
+	}
+	foreign := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		Data:       map[string][]byte{"foo": []byte("bar")},
+	}
+	g.Expect(c.Create(context.TODO(), foreign)).To(Succeed())
+	err = hookEnv.Apply(context.TODO(), map[string][]byte{"DB_PASSWORD": []byte("secret")})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("is not managed by HelmRelease"))
+	g.Expect(hookEnv.Delete(context.TODO())).To(Succeed())
+	g.Expect(c.Get(context.TODO(), key, secret)).To(Succeed())
+	g.Expect(secret.Data).To(Equal(foreign.Data))
+}
@@ -49,7 +49,7 @@ type InstallOption func(action *helmaction.Install)
 // storage.ObserveFunc, which provides superior access to Helm storage writes.
 func Install(ctx context.Context, config *helmaction.Configuration, obj *v2.HelmRelease,
 	chrt *helmchart.Chart, vals helmchartutil.Values, opts ...InstallOption) (*helmrelease.Release, error) {
-	install := newInstall(config, obj, opts)
+	install := newInstall(ctx, config, obj, opts)
 
 	policy, err := crdPolicyOrDefault(obj.GetInstall().CRDs)
 	if err != nil {
@@ -62,7 +62,7 @@ func Install(ctx context.Context, config *helmaction.Configuration, obj *v2.Helm
 	return install.RunWithContext(ctx, chrt, vals.AsMap())
 }
 
-func newInstall(config *helmaction.Configuration, obj *v2.HelmRelease, opts []InstallOption) *helmaction.Install {
+func newInstall(ctx context.Context, config *helmaction.Configuration, obj *v2.HelmRelease, opts []InstallOption) *helmaction.Install {
 	install := helmaction.NewInstall(config)
 
 	install.ReleaseName = release.ShortenName(obj.GetReleaseName())
@@ -74,8 +74,10 @@ func newInstall(config *helmaction.Configuration, obj *v2.HelmRelease, opts []In
 	install.DisableHooks = obj.GetInstall().DisableHooks
 	install.DisableOpenAPIValidation = obj.GetInstall().DisableOpenAPIValidation
 	install.SkipSchemaValidation = obj.GetInstall().DisableSchemaValidation
+	install.SubNotes = obj.GetInstall().SubNotes
 	install.Replace = obj.GetInstall().Replace
 	install.Devel = true
+	install.Description = obj.Spec.ReleaseDescription
 	install.SkipCRDs = true
 
 	if obj.Spec.TargetNamespace != "" {
@@ -83,15 +85,15 @@ func newInstall(config *helmaction.Configuration, obj *v2.HelmRelease, opts []In
 	}
 
 	// If the user opted-in to allow DNS lookups, enable it.
-	if allowDNS, _ := features.Enabled(features.AllowDNSLookups); allowDNS {
+	if allowDNS, _ := features.EnabledFor(obj, features.AllowDNSLookups); allowDNS {
 		install.EnableDNS = allowDNS
 	}
 
-	install.PostRenderer = postrender.BuildPostRenderers(obj)
-
 	for _, opt := range opts {
 		opt(install)
 	}
 
+	install.PostRenderer = postrender.BuildPostRenderers(ctx, obj, install.DryRun)
+
 	return install
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package action
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -37,7 +38,8 @@ func Test_newInstall(t *testing.T) {
 				Namespace: "install-ns",
 			},
 			Spec: v2.HelmReleaseSpec{
-				Timeout: &metav1.Duration{Duration: time.Minute},
+				ReleaseDescription: "install with description",
+				Timeout:            &metav1.Duration{Duration: time.Minute},
 				Install: &v2.Install{
 					Timeout: &metav1.Duration{Duration: 10 * time.Second},
 					Replace: true,
@@ -45,11 +47,12 @@ func Test_newInstall(t *testing.T) {
 			},
 		}
 
-		got := newInstall(&helmaction.Configuration{}, obj, nil)
+		got := newInstall(context.TODO(), &helmaction.Configuration{}, obj, nil)
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(got.Namespace).To(Equal(obj.Namespace))
 		g.Expect(got.Timeout).To(Equal(obj.Spec.Install.Timeout.Duration))
 		g.Expect(got.Replace).To(Equal(obj.Spec.Install.Replace))
+		g.Expect(got.Description).To(Equal(obj.Spec.ReleaseDescription))
 	})
 
 	t.Run("timeout fallback", func(t *testing.T) {
@@ -65,7 +68,7 @@ func Test_newInstall(t *testing.T) {
 			},
 		}
 
-		got := newInstall(&helmaction.Configuration{}, obj, nil)
+		got := newInstall(context.TODO(), &helmaction.Configuration{}, obj, nil)
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(got.Namespace).To(Equal(obj.Namespace))
 		g.Expect(got.Timeout).To(Equal(obj.Spec.Timeout.Duration))
@@ -82,7 +85,7 @@ func Test_newInstall(t *testing.T) {
 			Spec: v2.HelmReleaseSpec{},
 		}
 
-		got := newInstall(&helmaction.Configuration{}, obj, []InstallOption{
+		got := newInstall(context.TODO(), &helmaction.Configuration{}, obj, []InstallOption{
 			func(install *helmaction.Install) {
 				install.Atomic = true
 			},
@@ -110,8 +113,45 @@ func Test_newInstall(t *testing.T) {
 			},
 		}
 
-		got := newInstall(&helmaction.Configuration{}, obj, nil)
+		got := newInstall(context.TODO(), &helmaction.Configuration{}, obj, nil)
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(got.TakeOwnership).To(BeFalse())
 	})
+
+	t.Run("wait for jobs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "install",
+				Namespace: "install-ns",
+			},
+		}
+
+		got := newInstall(context.TODO(), &helmaction.Configuration{}, obj, nil)
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.Wait).To(BeTrue())
+		g.Expect(got.WaitForJobs).To(BeTrue())
+	})
+
+	t.Run("disable wait for jobs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "install",
+				Namespace: "install-ns",
+			},
+			Spec: v2.HelmReleaseSpec{
+				Install: &v2.Install{
+					DisableWaitForJobs: true,
+				},
+			},
+		}
+
+		got := newInstall(context.TODO(), &helmaction.Configuration{}, obj, nil)
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.Wait).To(BeTrue())
+		g.Expect(got.WaitForJobs).To(BeFalse())
+	})
 }
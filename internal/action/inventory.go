@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/kube"
+)
+
+// Inventory returns the v2.ResourceInventory of the resources in the manifest
+// of the given release. Resources without a namespace which are namespace
+// scoped are recorded in the namespace of the release. The entries are sorted
+// by their ID.
+//
+// Hooks are not part of the manifest of a release, and are therefore not
+// included.
+func Inventory(config *helmaction.Configuration, rls *helmrelease.Release) (*v2.ResourceInventory, error) {
+	inventory := &v2.ResourceInventory{Entries: []v2.ResourceRef{}}
+	if rls == nil {
+		return inventory, nil
+	}
+
+	objects, err := ssautil.ReadObjects(strings.NewReader(rls.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read objects from release manifest: %w", err)
+	}
+	if len(objects) == 0 {
+		return inventory, nil
+	}
+
+	mapper, err := config.RESTClientGetter.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	if err := setDefaultNamespace(mapper, objects, rls.Namespace); err != nil {
+		return nil, err
+	}
+
+	for _, obj := range objects {
+		inventory.Entries = append(inventory.Entries, v2.ResourceRef{
+			ID:      object.UnstructuredToObjMetadata(obj).String(),
+			Version: obj.GroupVersionKind().Version,
+		})
+	}
+	sort.Slice(inventory.Entries, func(i, j int) bool {
+		return inventory.Entries[i].ID < inventory.Entries[j].ID
+	})
+	return inventory, nil
+}
+
+// setDefaultNamespace sets the namespace of the namespace scoped objects
+// without a namespace to the given namespace using kube.SetDefaultNamespace.
+// It returns on the first object of which the scope can not be determined.
+func setDefaultNamespace(mapper apimeta.RESTMapper, objects []*unstructured.Unstructured, namespace string) error {
+	for _, obj := range objects {
+		if err := kube.SetDefaultNamespace(mapper, obj, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
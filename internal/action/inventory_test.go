@@ -0,0 +1,89 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"testing"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	. "github.com/onsi/gomega"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/kube"
+)
+
+func TestInventory(t *testing.T) {
+	config, cleanup := newTestCluster(t)
+	t.Cleanup(func() {
+		if err := cleanup(); err != nil {
+			t.Logf("Failed to stop the test environment: %v", err)
+		}
+	})
+
+	getter := kube.NewMemoryRESTClientGetter(config)
+
+	tests := []struct {
+		name     string
+		manifest string
+		want     []v2.ResourceRef
+	}{
+		{
+			name: "namespaced and cluster scoped resources",
+			manifest: `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: other
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: podinfo
+`,
+			want: []v2.ResourceRef{
+				{ID: "_podinfo_rbac.authorization.k8s.io_ClusterRole", Version: "v1"},
+				{ID: "other_config__ConfigMap", Version: "v1"},
+				{ID: "release-ns_podinfo_apps_Deployment", Version: "v1"},
+			},
+		},
+		{
+			name:     "empty manifest",
+			manifest: "",
+			want:     []v2.ResourceRef{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := Inventory(&helmaction.Configuration{RESTClientGetter: getter}, &helmrelease.Release{
+				Name:      "release",
+				Namespace: "release-ns",
+				Manifest:  tt.manifest,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Entries).To(Equal(tt.want))
+		})
+	}
+}
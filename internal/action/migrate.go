@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"errors"
+	"fmt"
+
+	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+
+	"github.com/fluxcd/helm-controller/internal/release"
+)
+
+// MigrateStorage moves the versions of the Helm release with the given name
+// from the storage of the first ConfigFactory to the storage of the second,
+// e.g. after the storage driver of the release has changed. Versions which
+// already exist in the target storage are not overwritten. It returns the
+// number of versions moved, which is zero when the release does not exist
+// in the source storage.
+//
+// Versions are only removed from the source storage after all of them have
+// been written to the target storage, which allows a failed migration to be
+// retried.
+func MigrateStorage(from, to *ConfigFactory, releaseName string) (int, error) {
+	if from == nil || to == nil {
+		return 0, errors.New("no storage configured for migration")
+	}
+
+	name := release.ShortenName(releaseName)
+	src, dst := from.NewStorage(), to.NewStorage()
+	history, err := src.History(name)
+	if err != nil {
+		if errors.Is(err, helmdriver.ErrReleaseNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read release history from '%s' storage: %w", from.Driver.Name(), err)
+	}
+
+	for _, rls := range history {
+		if err := dst.Create(rls); err != nil && !errors.Is(err, helmdriver.ErrReleaseExists) {
+			return 0, fmt.Errorf("failed to write release '%s' version %d to '%s' storage: %w",
+				rls.Name, rls.Version, to.Driver.Name(), err)
+		}
+	}
+	for _, rls := range history {
+		if _, err := src.Delete(rls.Name, rls.Version); err != nil && !errors.Is(err, helmdriver.ErrReleaseNotFound) {
+			return 0, fmt.Errorf("failed to remove release '%s' version %d from '%s' storage: %w",
+				rls.Name, rls.Version, from.Driver.Name(), err)
+		}
+	}
+	return len(history), nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"errors"
+	"testing"
+
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	"github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/helm-controller/internal/storage"
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+func TestMigrateStorage(t *testing.T) {
+	newRelease := func(version int, status helmrelease.Status) *helmrelease.Release {
+		return testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+			Name:      "release",
+			Namespace: "default",
+			Version:   version,
+			Status:    status,
+		})
+	}
+
+	t.Run("moves release history", func(t *testing.T) {
+		g := NewWithT(t)
+
+		from := &ConfigFactory{Driver: driver.NewMemory()}
+		to := &ConfigFactory{Driver: driver.NewMemory()}
+		src := from.NewStorage()
+		g.Expect(src.Create(newRelease(1, helmrelease.StatusSuperseded))).To(Succeed())
+		g.Expect(src.Create(newRelease(2, helmrelease.StatusDeployed))).To(Succeed())
+
+		// A version already present in the target is not overwritten.
+		existing := newRelease(1, helmrelease.StatusSuperseded)
+		existing.Info.Description = "existing"
+		g.Expect(to.NewStorage().Create(existing)).To(Succeed())
+
+		n, err := MigrateStorage(from, to, "release")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(n).To(Equal(2))
+
+		history, err := to.NewStorage().History("release")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(history).To(HaveLen(2))
+
+		first, err := to.NewStorage().Get("release", 1)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(first.Info.Description).To(Equal("existing"))
+
+		_, err = src.History("release")
+		g.Expect(err).To(MatchError(driver.ErrReleaseNotFound))
+	})
+
+	t.Run("no release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		n, err := MigrateStorage(&ConfigFactory{Driver: driver.NewMemory()}, &ConfigFactory{Driver: driver.NewMemory()}, "release")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(n).To(BeZero())
+	})
+
+	t.Run("retains source on write failure", func(t *testing.T) {
+		g := NewWithT(t)
+
+		from := &ConfigFactory{Driver: driver.NewMemory()}
+		g.Expect(from.NewStorage().Create(newRelease(1, helmrelease.StatusDeployed))).To(Succeed())
+
+		to := &ConfigFactory{Driver: &storage.Failing{Driver: driver.NewMemory(), CreateErr: errors.New("create error")}}
+		_, err := MigrateStorage(from, to, "release")
+		g.Expect(err).To(HaveOccurred())
+
+		history, err := from.NewStorage().History("release")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(history).To(HaveLen(1))
+	})
+}
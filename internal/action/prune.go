@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apierrutil "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	"github.com/fluxcd/helm-controller/internal/diff"
+	"github.com/fluxcd/helm-controller/internal/kube"
+)
+
+const (
+	// resourcePolicyAnnotation is the annotation Helm uses to exclude
+	// resources from being deleted.
+	resourcePolicyAnnotation = "helm.sh/resource-policy"
+	// resourcePolicyKeep is the value of resourcePolicyAnnotation which
+	// instructs Helm to keep the resource.
+	resourcePolicyKeep = "keep"
+)
+
+// Prune deletes the resources in the manifest of the previous release which
+// are absent from the manifest of the current release, but still exist in
+// the cluster. This covers resources Helm does not delete on upgrade, e.g.
+// because they were adopted by the release.
+//
+// Only resources which are annotated as belonging to the current release are
+// deleted, and resources annotated with `helm.sh/resource-policy: keep` are
+// left untouched. It returns a ssa.ChangeSet of the deleted resources.
+func Prune(ctx context.Context, config *helmaction.Configuration, previous, current *helmrelease.Release) (*ssa.ChangeSet, error) {
+	changeSet := ssa.NewChangeSet()
+	if previous == nil || current == nil {
+		return changeSet, nil
+	}
+
+	previousObjs, err := ssautil.ReadObjects(strings.NewReader(previous.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read objects from previous release manifest: %w", err)
+	}
+	currentObjs, err := ssautil.ReadObjects(strings.NewReader(current.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read objects from current release manifest: %w", err)
+	}
+
+	desired := make(map[string]struct{}, len(currentObjs))
+	for _, obj := range currentObjs {
+		desired[diff.ResourceName(obj)] = struct{}{}
+	}
+	var stale []*unstructured.Unstructured
+	for _, obj := range previousObjs {
+		if _, ok := desired[diff.ResourceName(obj)]; !ok {
+			stale = append(stale, obj)
+		}
+	}
+	if len(stale) == 0 {
+		return changeSet, nil
+	}
+
+	c, err := kube.NewClient(config.RESTClientGetter)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, obj := range stale {
+		if err := kube.SetDefaultNamespace(c.RESTMapper(), obj, current.Namespace); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("%s retrieval failure: %w", diff.ResourceName(obj), err))
+			}
+			continue
+		}
+		if !ownedByRelease(existing, current) || existing.GetAnnotations()[resourcePolicyAnnotation] == resourcePolicyKeep {
+			continue
+		}
+
+		if err := c.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("%s deletion failure: %w", diff.ResourceName(obj), err))
+			}
+			continue
+		}
+		changeSet.Add(objectToChangeSetEntry(existing, ssa.DeletedAction))
+	}
+	return changeSet, apierrutil.NewAggregate(errs)
+}
+
+// ownedByRelease returns true if the given object is annotated as belonging
+// to the given release.
+func ownedByRelease(obj client.Object, rls *helmrelease.Release) bool {
+	annotations := obj.GetAnnotations()
+	return annotations[helmReleaseNameAnnotation] == rls.Name &&
+		annotations[helmReleaseNamespaceAnnotation] == rls.Namespace
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/ssa"
+
+	"github.com/fluxcd/helm-controller/internal/kube"
+)
+
+func TestPrune(t *testing.T) {
+	config, cleanup := newTestCluster(t)
+	t.Cleanup(func() {
+		if err := cleanup(); err != nil {
+			t.Logf("Failed to stop the test environment: %v", err)
+		}
+	})
+
+	getter := kube.NewMemoryRESTClientGetter(config)
+	c, err := client.New(config, client.Options{})
+	if err != nil {
+		t.Fatalf("Failed to create client for test environment: %v", err)
+	}
+
+	g := NewWithT(t)
+
+	ns, err := generateNamespace(context.TODO(), c, "prune")
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(func() {
+		_ = c.Delete(context.TODO(), ns)
+	})
+
+	newConfigMap := func(name, release string, annotations map[string]string) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns.Name,
+				Annotations: map[string]string{
+					helmReleaseNameAnnotation:      release,
+					helmReleaseNamespaceAnnotation: ns.Name,
+				},
+			},
+		}
+		for k, v := range annotations {
+			cm.Annotations[k] = v
+		}
+		return cm
+	}
+	for _, cm := range []*corev1.ConfigMap{
+		newConfigMap("retained", "release", nil),
+		newConfigMap("removed", "release", nil),
+		newConfigMap("kept", "release", map[string]string{resourcePolicyAnnotation: resourcePolicyKeep}),
+		newConfigMap("adopted", "other-release", nil),
+	} {
+		g.Expect(c.Create(context.TODO(), cm)).To(Succeed())
+	}
+
+	manifest := func(names ...string) string {
+		var m string
+		for _, name := range names {
+			m += fmt.Sprintf("---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n", name)
+		}
+		return m
+	}
+	previous := &helmrelease.Release{
+		Name:      "release",
+		Namespace: ns.Name,
+		Manifest:  manifest("retained", "removed", "kept", "adopted", "missing"),
+	}
+	current := &helmrelease.Release{
+		Name:      "release",
+		Namespace: ns.Name,
+		Manifest:  manifest("retained"),
+	}
+
+	got, err := Prune(context.TODO(), &helmaction.Configuration{RESTClientGetter: getter}, previous, current)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Entries).To(HaveLen(1))
+	g.Expect(got.Entries[0].Subject).To(Equal(fmt.Sprintf("ConfigMap/%s/removed", ns.Name)))
+	g.Expect(got.Entries[0].Action).To(Equal(ssa.DeletedAction))
+
+	for name, exists := range map[string]bool{
+		"retained": true,
+		"removed":  false,
+		"kept":     true,
+		"adopted":  true,
+	} {
+		err := c.Get(context.TODO(), client.ObjectKey{Namespace: ns.Name, Name: name}, &corev1.ConfigMap{})
+		if exists {
+			g.Expect(err).ToNot(HaveOccurred(), name)
+		} else {
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), name)
+		}
+	}
+
+	// Pruning without a previous release is a no-op.
+	got, err = Prune(context.TODO(), &helmaction.Configuration{RESTClientGetter: getter}, nil, current)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Entries).To(BeEmpty())
+}
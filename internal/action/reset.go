@@ -22,7 +22,7 @@ import (
 	"helm.sh/helm/v3/pkg/chartutil"
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
-	intchartutil "github.com/fluxcd/pkg/chartutil"
+	intvalues "github.com/fluxcd/helm-controller/internal/values"
 )
 
 const (
@@ -56,7 +56,7 @@ func MustResetFailures(obj *v2.HelmRelease, chart *chart.Metadata, values chartu
 			// TODO: remove this when the deprecated field is removed.
 			d = "sha1:" + obj.Status.LastAttemptedValuesChecksum
 		}
-		if ok := intchartutil.VerifyValues(digest.Digest(d), values); !ok {
+		if ok := intvalues.Verify(digest.Digest(d), values); !ok {
 			return differentValuesReason, true
 		}
 	}
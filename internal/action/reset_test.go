@@ -19,9 +19,9 @@ package action
 import (
 	"testing"
 
+	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
 	. "github.com/onsi/gomega"
-	"github.com/jessesimpson36/helm/v4/pkg/chart/v2"
-	"github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v3/pkg/chartutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/fluxcd/pkg/apis/meta"
@@ -65,7 +65,7 @@ func newRollback(config *helmaction.Configuration, obj *v2.HelmRelease, opts []R
 	rollback.Force = obj.GetRollback().Force
 	rollback.Recreate = obj.GetRollback().Recreate
 	rollback.CleanupOnFail = obj.GetRollback().CleanupOnFail
-	rollback.MaxHistory = obj.GetMaxHistory()
+	rollback.MaxHistory = helmMaxHistory(obj)
 
 	for _, opt := range opts {
 		opt(rollback)
@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"fmt"
+
+	helmchart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	helmchartutil "github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// ValidateValuesSchema validates the given values, coalesced with the default
+// values of the chart, against the JSON schema of the chart and its
+// dependencies. This allows the violations to be reported before a Helm
+// action is run, instead of as a rendering error of the action.
+//
+// Like the Helm action, the dependencies of the chart are processed first,
+// which excludes the disabled dependencies from the validation and imports
+// the values of the enabled ones. This is done on a copy of the chart, as
+// processing modifies the chart.
+//
+// It returns nil without validating when the schema validation is disabled
+// for the Helm action which will be run for the given object, i.e. install
+// when the object does not have a release yet, and upgrade otherwise.
+func ValidateValuesSchema(obj *v2.HelmRelease, chrt *helmchart.Chart, vals helmchartutil.Values) error {
+	if schemaValidationDisabled(obj) {
+		return nil
+	}
+	chrt = copyChart(chrt)
+	if err := helmchartutil.ProcessDependencies(chrt, vals); err != nil {
+		return fmt.Errorf("failed to process chart dependencies: %w", err)
+	}
+	coalesced, err := helmchartutil.CoalesceValues(chrt, vals)
+	if err != nil {
+		return err
+	}
+	return helmchartutil.ValidateAgainstSchema(chrt, coalesced)
+}
+
+// copyChart returns a copy of the given chart and its dependencies, of which
+// the metadata, values and dependencies can be modified without affecting
+// the given chart. The files and templates are shared.
+func copyChart(chrt *helmchart.Chart) *helmchart.Chart {
+	cp := *chrt
+	if chrt.Metadata != nil {
+		md := *chrt.Metadata
+		md.Dependencies = make([]*helmchart.Dependency, 0, len(chrt.Metadata.Dependencies))
+		for _, d := range chrt.Metadata.Dependencies {
+			dep := *d
+			md.Dependencies = append(md.Dependencies, &dep)
+		}
+		cp.Metadata = &md
+	}
+	deps := make([]*helmchart.Chart, 0, len(chrt.Dependencies()))
+	for _, d := range chrt.Dependencies() {
+		deps = append(deps, copyChart(d))
+	}
+	cp.SetDependencies(deps...)
+	return &cp
+}
+
+// schemaValidationDisabled returns true if the schema validation is disabled
+// for the next Helm action to be run for the given object.
+func schemaValidationDisabled(obj *v2.HelmRelease) bool {
+	if obj.Status.History.Latest() == nil {
+		return obj.GetInstall().DisableSchemaValidation
+	}
+	return obj.GetUpgrade().DisableSchemaValidation
+}
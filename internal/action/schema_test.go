@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"testing"
+
+	helmchart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	helmchartutil "github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
+	. "github.com/onsi/gomega"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+func TestValidateValuesSchema(t *testing.T) {
+	const schema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "replicaCount": {"type": "integer", "minimum": 1}
+  },
+  "required": ["replicaCount"]
+}`
+
+	tests := []struct {
+		name    string
+		schema  string
+		obj     *v2.HelmRelease
+		values  helmchartutil.Values
+		wantErr string
+	}{
+		{
+			name:   "valid values",
+			schema: schema,
+			obj:    &v2.HelmRelease{},
+			values: helmchartutil.Values{"replicaCount": 2},
+		},
+		{
+			name:    "invalid values",
+			schema:  schema,
+			obj:     &v2.HelmRelease{},
+			values:  helmchartutil.Values{"replicaCount": "two"},
+			wantErr: "replicaCount",
+		},
+		{
+			name:    "missing required values",
+			schema:  schema,
+			obj:     &v2.HelmRelease{},
+			values:  helmchartutil.Values{},
+			wantErr: "replicaCount",
+		},
+		{
+			name:   "chart without schema",
+			obj:    &v2.HelmRelease{},
+			values: helmchartutil.Values{"replicaCount": "two"},
+		},
+		{
+			name:   "schema validation disabled for install",
+			schema: schema,
+			obj: &v2.HelmRelease{
+				Spec: v2.HelmReleaseSpec{
+					Install: &v2.Install{DisableSchemaValidation: true},
+				},
+			},
+			values: helmchartutil.Values{"replicaCount": "two"},
+		},
+		{
+			name:   "schema validation disabled for upgrade",
+			schema: schema,
+			obj: &v2.HelmRelease{
+				Spec: v2.HelmReleaseSpec{
+					Upgrade: &v2.Upgrade{DisableSchemaValidation: true},
+				},
+				Status: v2.HelmReleaseStatus{
+					History: v2.Snapshots{{Name: "release", Version: 1}},
+				},
+			},
+			values: helmchartutil.Values{"replicaCount": "two"},
+		},
+		{
+			name:   "schema validation disabled for install only",
+			schema: schema,
+			obj: &v2.HelmRelease{
+				Spec: v2.HelmReleaseSpec{
+					Install: &v2.Install{DisableSchemaValidation: true},
+				},
+				Status: v2.HelmReleaseStatus{
+					History: v2.Snapshots{{Name: "release", Version: 1}},
+				},
+			},
+			values:  helmchartutil.Values{"replicaCount": "two"},
+			wantErr: "replicaCount",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			chrt := testutil.BuildChart()
+			if tt.schema != "" {
+				chrt.Schema = []byte(tt.schema)
+			}
+
+			err := ValidateValuesSchema(tt.obj, chrt, tt.values)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestValidateValuesSchema_dependencies(t *testing.T) {
+	const schema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["host"]
+}`
+
+	newChart := func() *helmchart.Chart {
+		sub := testutil.BuildChart(testutil.ChartWithName("database"))
+		sub.Schema = []byte(schema)
+		return testutil.BuildChart(testutil.ChartWithDependency(&helmchart.Dependency{
+			Name:      "database",
+			Condition: "database.enabled",
+		}, sub))
+	}
+
+	tests := []struct {
+		name    string
+		values  helmchartutil.Values
+		wantErr string
+	}{
+		{
+			name:   "disabled dependency is not validated",
+			values: helmchartutil.Values{"database": map[string]any{"enabled": false}},
+		},
+		{
+			name:    "enabled dependency is validated",
+			values:  helmchartutil.Values{"database": map[string]any{"enabled": true}},
+			wantErr: "host",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			chrt := newChart()
+			err := ValidateValuesSchema(&v2.HelmRelease{}, chrt, tt.values)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			// The chart itself is not modified.
+			g.Expect(chrt.Dependencies()).To(HaveLen(1))
+			g.Expect(chrt.Metadata.Dependencies[0].Enabled).To(BeFalse())
+		})
+	}
+}
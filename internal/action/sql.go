@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"sync"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+)
+
+// SQLDriverCache holds a Helm SQL storage driver per connection string and
+// namespace, to share its database connection pool between the actions of
+// all the HelmReleases using the same database, instead of opening a new
+// pool (and running the schema migrations) for every reconciliation.
+//
+// The Helm SQL storage driver does not allow its connection pool to be
+// closed. Drivers are therefore kept for the lifetime of the cache, which
+// bounds the number of pools to the number of distinct connection strings
+// and namespaces in use.
+type SQLDriverCache struct {
+	mu      sync.Mutex
+	log     helmaction.DebugLog
+	drivers map[sqlDriverKey]*helmdriver.SQL
+
+	// newSQL can be used to stub out helmdriver.NewSQL in tests.
+	newSQL func(connectionString string, logger func(string, ...interface{}), namespace string) (*helmdriver.SQL, error)
+}
+
+type sqlDriverKey struct {
+	dsn       string
+	namespace string
+}
+
+// NewSQLDriverCache returns a new, empty SQLDriverCache. The given logger
+// is used by the drivers constructed by the cache, as they outlive the
+// reconciliation they are constructed for.
+func NewSQLDriverCache(log helmaction.DebugLog) *SQLDriverCache {
+	if log == nil {
+		log = func(string, ...interface{}) {}
+	}
+	return &SQLDriverCache{
+		log:     log,
+		drivers: make(map[sqlDriverKey]*helmdriver.SQL),
+		newSQL:  helmdriver.NewSQL,
+	}
+}
+
+// Get returns the Helm SQL storage driver cached for the given connection
+// string and namespace. If there is none, it constructs a new driver, which
+// is cached if it was constructed successfully.
+func (c *SQLDriverCache) Get(dsn, namespace string) (*helmdriver.SQL, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := sqlDriverKey{dsn: dsn, namespace: namespace}
+	if d, ok := c.drivers[key]; ok {
+		return d, nil
+	}
+	d, err := c.newSQL(dsn, c.log, namespace)
+	if err != nil {
+		return nil, err
+	}
+	c.drivers[key] = d
+	return d, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"errors"
+	"testing"
+
+	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	. "github.com/onsi/gomega"
+)
+
+func TestSQLDriverCache_Get(t *testing.T) {
+	g := NewWithT(t)
+
+	var constructed int
+	cache := NewSQLDriverCache(nil)
+	cache.newSQL = func(dsn string, _ func(string, ...interface{}), namespace string) (*helmdriver.SQL, error) {
+		if dsn == "invalid" {
+			return nil, errors.New("invalid connection string")
+		}
+		constructed++
+		return &helmdriver.SQL{}, nil
+	}
+
+	d1, err := cache.Get("postgres://db", "default")
+	g.Expect(err).ToNot(HaveOccurred())
+	d2, err := cache.Get("postgres://db", "default")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(d2).To(BeIdenticalTo(d1))
+	g.Expect(constructed).To(Equal(1))
+
+	d3, err := cache.Get("postgres://db", "other")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(d3).ToNot(BeIdenticalTo(d1))
+	d4, err := cache.Get("postgres://other-db", "default")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(d4).ToNot(BeIdenticalTo(d1))
+	g.Expect(constructed).To(Equal(3))
+
+	_, err = cache.Get("invalid", "default")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(cache.drivers).To(HaveLen(3))
+}
+
+func TestConfigFactory_buildStorage_SQLDriverCache(t *testing.T) {
+	g := NewWithT(t)
+
+	cache := NewSQLDriverCache(nil)
+	cache.newSQL = func(string, func(string, ...interface{}), string) (*helmdriver.SQL, error) {
+		return &helmdriver.SQL{}, nil
+	}
+
+	var drivers []helmdriver.Driver
+	for i := 0; i < 2; i++ {
+		factory := &ConfigFactory{}
+		g.Expect(WithStorageDSN("postgres://db")(factory)).To(Succeed())
+		g.Expect(WithSQLDriverCache(cache)(factory)).To(Succeed())
+		g.Expect(WithStorage(helmdriver.SQLDriverName, "default")(factory)).To(Succeed())
+		g.Expect(factory.buildStorage()).To(Succeed())
+		drivers = append(drivers, factory.Driver)
+	}
+	g.Expect(drivers[1]).To(BeIdenticalTo(drivers[0]))
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"github.com/opencontainers/go-digest"
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	intvalues "github.com/fluxcd/helm-controller/internal/values"
+)
+
+// ReleaseUpToDate returns true if the HelmRelease has been successfully
+// reconciled for its current generation, with the given source artifact
+// revision and values. When true, the reconciler does not have to load the
+// chart or query the Helm storage to confirm the state of the release.
+//
+// It always returns false when drift detection is enabled, or when a
+// reconcile request has not been handled yet, as both require the release
+// to be inspected.
+func ReleaseUpToDate(obj *v2.HelmRelease, artifactRevision string, values chartutil.Values) bool {
+	switch {
+	case !conditions.IsReady(obj) || conditions.IsStalled(obj):
+		return false
+	case obj.Status.ObservedGeneration != obj.Generation || obj.Status.LastAttemptedGeneration != obj.Generation:
+		return false
+	case obj.Status.ObservedSourceArtifactRevision == "" || obj.Status.ObservedSourceArtifactRevision != artifactRevision:
+		return false
+	case obj.Status.LastAttemptedConfigDigest == "" || obj.Status.History.Latest() == nil:
+		return false
+	case obj.GetDriftDetection().MustDetectChanges():
+		return false
+	}
+
+	// A pending reconcile request may carry a force or reset request, or
+	// simply be a request to confirm the release state.
+	if v, ok := meta.ReconcileAnnotationValue(obj.GetAnnotations()); ok && v != obj.Status.GetLastHandledReconcileRequest() {
+		return false
+	}
+
+	return intvalues.Verify(digest.Digest(obj.Status.LastAttemptedConfigDigest), values)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chartutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestReleaseUpToDate(t *testing.T) {
+	const (
+		revision     = "1.0.0@sha256:5f0a3fc4e3bc5a9ff1e8c3c1bd3bd2e3fd8ef5b7e4e7f3a1b2c3d4e5f6a7b8c9"
+		configDigest = "sha256:1dabc4e3cbbd6a0818bd460f3a6c9855bfe95d506c74726bc0f2edb0aecb1f4e"
+	)
+
+	upToDate := func() *v2.HelmRelease {
+		return &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Generation: 2,
+			},
+			Status: v2.HelmReleaseStatus{
+				ObservedGeneration:             2,
+				LastAttemptedGeneration:        2,
+				ObservedSourceArtifactRevision: revision,
+				LastAttemptedConfigDigest:      configDigest,
+				History: v2.Snapshots{
+					{Name: "release", Namespace: "default", Version: 1},
+				},
+				Conditions: []metav1.Condition{
+					{Type: meta.ReadyCondition, Status: metav1.ConditionTrue},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(obj *v2.HelmRelease)
+		revision string
+		values   chartutil.Values
+		want     bool
+	}{
+		{
+			name:     "unchanged",
+			revision: revision,
+			values:   chartutil.Values{"foo": "bar"},
+			want:     true,
+		},
+		{
+			name:     "handled reconcile request",
+			revision: revision,
+			values:   chartutil.Values{"foo": "bar"},
+			mutate: func(obj *v2.HelmRelease) {
+				obj.Annotations = map[string]string{meta.ReconcileRequestAnnotation: "a"}
+				obj.Status.LastHandledReconcileAt = "a"
+			},
+			want: true,
+		},
+		{
+			name:     "not ready",
+			revision: revision,
+			values:   chartutil.Values{"foo": "bar"},
+			mutate: func(obj *v2.HelmRelease) {
+				obj.Status.Conditions[0].Status = metav1.ConditionFalse
+			},
+			want: false,
+		},
+		{
+			name:     "generation changed",
+			revision: revision,
+			values:   chartutil.Values{"foo": "bar"},
+			mutate: func(obj *v2.HelmRelease) {
+				obj.Generation = 3
+			},
+			want: false,
+		},
+		{
+			name:     "artifact revision changed",
+			revision: "1.0.1@sha256:5f0a3fc4e3bc5a9ff1e8c3c1bd3bd2e3fd8ef5b7e4e7f3a1b2c3d4e5f6a7b8c9",
+			values:   chartutil.Values{"foo": "bar"},
+			want:     false,
+		},
+		{
+			name:     "no observed artifact revision",
+			revision: revision,
+			values:   chartutil.Values{"foo": "bar"},
+			mutate: func(obj *v2.HelmRelease) {
+				obj.Status.ObservedSourceArtifactRevision = ""
+			},
+			want: false,
+		},
+		{
+			name:     "values changed",
+			revision: revision,
+			values:   chartutil.Values{"foo": "baz"},
+			want:     false,
+		},
+		{
+			name:     "no release history",
+			revision: revision,
+			values:   chartutil.Values{"foo": "bar"},
+			mutate: func(obj *v2.HelmRelease) {
+				obj.Status.History = nil
+			},
+			want: false,
+		},
+		{
+			name:     "drift detection enabled",
+			revision: revision,
+			values:   chartutil.Values{"foo": "bar"},
+			mutate: func(obj *v2.HelmRelease) {
+				obj.Spec.DriftDetection = &v2.DriftDetection{Mode: v2.DriftDetectionEnabled}
+			},
+			want: false,
+		},
+		{
+			name:     "pending reconcile request",
+			revision: revision,
+			values:   chartutil.Values{"foo": "bar"},
+			mutate: func(obj *v2.HelmRelease) {
+				obj.Annotations = map[string]string{meta.ReconcileRequestAnnotation: "b"}
+				obj.Status.LastHandledReconcileAt = "a"
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := upToDate()
+			if tt.mutate != nil {
+				tt.mutate(obj)
+			}
+			g.Expect(ReleaseUpToDate(obj, tt.revision, tt.values)).To(Equal(tt.want))
+		})
+	}
+}
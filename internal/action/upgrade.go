@@ -49,7 +49,7 @@ type UpgradeOption func(upgrade *helmaction.Upgrade)
 // storage.ObserveFunc, which provides superior access to Helm storage writes.
 func Upgrade(ctx context.Context, config *helmaction.Configuration, obj *v2.HelmRelease, chrt *helmchart.Chart,
 	vals helmchartutil.Values, opts ...UpgradeOption) (*helmrelease.Release, error) {
-	upgrade := newUpgrade(config, obj, opts)
+	upgrade := newUpgrade(ctx, config, obj, opts)
 
 	policy, err := crdPolicyOrDefault(obj.GetUpgrade().CRDs)
 	if err != nil {
@@ -62,12 +62,12 @@ func Upgrade(ctx context.Context, config *helmaction.Configuration, obj *v2.Helm
 	return upgrade.RunWithContext(ctx, release.ShortenName(obj.GetReleaseName()), chrt, vals.AsMap())
 }
 
-func newUpgrade(config *helmaction.Configuration, obj *v2.HelmRelease, opts []UpgradeOption) *helmaction.Upgrade {
+func newUpgrade(ctx context.Context, config *helmaction.Configuration, obj *v2.HelmRelease, opts []UpgradeOption) *helmaction.Upgrade {
 	upgrade := helmaction.NewUpgrade(config)
 	upgrade.Namespace = obj.GetReleaseNamespace()
 	upgrade.ResetValues = !obj.GetUpgrade().PreserveValues
 	upgrade.ReuseValues = obj.GetUpgrade().PreserveValues
-	upgrade.MaxHistory = obj.GetMaxHistory()
+	upgrade.MaxHistory = helmMaxHistory(obj)
 	upgrade.Timeout = obj.GetUpgrade().GetTimeout(obj.GetTimeout()).Duration
 	upgrade.TakeOwnership = !obj.GetUpgrade().DisableTakeOwnership
 	upgrade.Wait = !obj.GetUpgrade().DisableWait
@@ -75,20 +75,22 @@ func newUpgrade(config *helmaction.Configuration, obj *v2.HelmRelease, opts []Up
 	upgrade.DisableHooks = obj.GetUpgrade().DisableHooks
 	upgrade.DisableOpenAPIValidation = obj.GetUpgrade().DisableOpenAPIValidation
 	upgrade.SkipSchemaValidation = obj.GetUpgrade().DisableSchemaValidation
+	upgrade.SubNotes = obj.GetUpgrade().SubNotes
 	upgrade.Force = obj.GetUpgrade().Force
 	upgrade.CleanupOnFail = obj.GetUpgrade().CleanupOnFail
 	upgrade.Devel = true
+	upgrade.Description = obj.Spec.ReleaseDescription
 
 	// If the user opted-in to allow DNS lookups, enable it.
-	if allowDNS, _ := features.Enabled(features.AllowDNSLookups); allowDNS {
+	if allowDNS, _ := features.EnabledFor(obj, features.AllowDNSLookups); allowDNS {
 		upgrade.EnableDNS = allowDNS
 	}
 
-	upgrade.PostRenderer = postrender.BuildPostRenderers(obj)
-
 	for _, opt := range opts {
 		opt(upgrade)
 	}
 
+	upgrade.PostRenderer = postrender.BuildPostRenderers(ctx, obj, upgrade.DryRun)
+
 	return upgrade
 }
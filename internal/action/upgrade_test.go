@@ -17,6 +17,7 @@ limitations under the License.
 package action
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -37,7 +38,8 @@ func Test_newUpgrade(t *testing.T) {
 				Namespace: "upgrade-ns",
 			},
 			Spec: v2.HelmReleaseSpec{
-				Timeout: &metav1.Duration{Duration: time.Minute},
+				ReleaseDescription: "upgrade with description",
+				Timeout:            &metav1.Duration{Duration: time.Minute},
 				Upgrade: &v2.Upgrade{
 					Timeout: &metav1.Duration{Duration: 10 * time.Second},
 					Force:   true,
@@ -45,11 +47,12 @@ func Test_newUpgrade(t *testing.T) {
 			},
 		}
 
-		got := newUpgrade(&helmaction.Configuration{}, obj, nil)
+		got := newUpgrade(context.TODO(), &helmaction.Configuration{}, obj, nil)
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(got.Namespace).To(Equal(obj.Namespace))
 		g.Expect(got.Timeout).To(Equal(obj.Spec.Upgrade.Timeout.Duration))
 		g.Expect(got.Force).To(Equal(obj.Spec.Upgrade.Force))
+		g.Expect(got.Description).To(Equal(obj.Spec.ReleaseDescription))
 	})
 
 	t.Run("timeout fallback", func(t *testing.T) {
@@ -65,7 +68,7 @@ func Test_newUpgrade(t *testing.T) {
 			},
 		}
 
-		got := newUpgrade(&helmaction.Configuration{}, obj, nil)
+		got := newUpgrade(context.TODO(), &helmaction.Configuration{}, obj, nil)
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(got.Namespace).To(Equal(obj.Namespace))
 		g.Expect(got.Timeout).To(Equal(obj.Spec.Timeout.Duration))
@@ -82,7 +85,7 @@ func Test_newUpgrade(t *testing.T) {
 			Spec: v2.HelmReleaseSpec{},
 		}
 
-		got := newUpgrade(&helmaction.Configuration{}, obj, []UpgradeOption{
+		got := newUpgrade(context.TODO(), &helmaction.Configuration{}, obj, []UpgradeOption{
 			func(upgrade *helmaction.Upgrade) {
 				upgrade.Install = true
 			},
@@ -110,8 +113,87 @@ func Test_newUpgrade(t *testing.T) {
 			},
 		}
 
-		got := newUpgrade(&helmaction.Configuration{}, obj, nil)
+		got := newUpgrade(context.TODO(), &helmaction.Configuration{}, obj, nil)
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(got.TakeOwnership).To(BeFalse())
 	})
+
+	t.Run("disable validation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "upgrade",
+				Namespace: "upgrade-ns",
+			},
+			Spec: v2.HelmReleaseSpec{
+				Upgrade: &v2.Upgrade{
+					DisableOpenAPIValidation: true,
+					DisableSchemaValidation:  true,
+				},
+			},
+		}
+
+		got := newUpgrade(context.TODO(), &helmaction.Configuration{}, obj, nil)
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.DisableOpenAPIValidation).To(BeTrue())
+		g.Expect(got.SkipSchemaValidation).To(BeTrue())
+	})
+
+	t.Run("sub notes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "upgrade",
+				Namespace: "upgrade-ns",
+			},
+			Spec: v2.HelmReleaseSpec{
+				Upgrade: &v2.Upgrade{
+					SubNotes: true,
+				},
+			},
+		}
+
+		got := newUpgrade(context.TODO(), &helmaction.Configuration{}, obj, nil)
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.SubNotes).To(BeTrue())
+	})
+
+	t.Run("wait for jobs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "upgrade",
+				Namespace: "upgrade-ns",
+			},
+		}
+
+		got := newUpgrade(context.TODO(), &helmaction.Configuration{}, obj, nil)
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.Wait).To(BeTrue())
+		g.Expect(got.WaitForJobs).To(BeTrue())
+	})
+
+	t.Run("disable wait for jobs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "upgrade",
+				Namespace: "upgrade-ns",
+			},
+			Spec: v2.HelmReleaseSpec{
+				Upgrade: &v2.Upgrade{
+					DisableWaitForJobs: true,
+				},
+			},
+		}
+
+		got := newUpgrade(context.TODO(), &helmaction.Configuration{}, obj, nil)
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.Wait).To(BeTrue())
+		g.Expect(got.WaitForJobs).To(BeFalse())
+	})
 }
@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmchart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	helmchartutil "github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	apierrutil "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ssanormalize "github.com/fluxcd/pkg/ssa/normalize"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/diff"
+	"github.com/fluxcd/helm-controller/internal/kube"
+	"github.com/fluxcd/helm-controller/internal/release"
+)
+
+// ValidateInstall renders the release of the given object with a dry-run of
+// the Helm install action, and validates the rendered objects against the
+// schema of the cluster using ValidateManifest. This allows the rejection of
+// an object by the API server of the cluster to be reported per object,
+// before the Helm install action is run.
+//
+// It returns nil without validating when the OpenAPI validation is disabled
+// for the install of the object.
+func ValidateInstall(ctx context.Context, config *helmaction.Configuration, obj *v2.HelmRelease, chrt *helmchart.Chart,
+	vals helmchartutil.Values, fieldOwner string) error {
+	if obj.GetInstall().DisableOpenAPIValidation {
+		return nil
+	}
+	rendered, err := newInstall(ctx, config, obj, []InstallOption{installDryRun}).RunWithContext(ctx, chrt, vals.AsMap())
+	if err != nil {
+		return err
+	}
+	return ValidateManifest(ctx, config, rendered, fieldOwner)
+}
+
+// ValidateUpgrade renders the release of the given object with a dry-run of
+// the Helm upgrade action, and validates the rendered objects against the
+// schema of the cluster using ValidateManifest.
+//
+// It returns nil without validating when the OpenAPI validation is disabled
+// for the upgrade of the object.
+func ValidateUpgrade(ctx context.Context, config *helmaction.Configuration, obj *v2.HelmRelease, chrt *helmchart.Chart,
+	vals helmchartutil.Values, fieldOwner string) error {
+	if obj.GetUpgrade().DisableOpenAPIValidation {
+		return nil
+	}
+	rendered, err := newUpgrade(ctx, config, obj, []UpgradeOption{upgradeDryRun}).RunWithContext(ctx,
+		release.ShortenName(obj.GetReleaseName()), chrt, vals.AsMap())
+	if err != nil {
+		return err
+	}
+	return ValidateManifest(ctx, config, rendered, fieldOwner)
+}
+
+// ValidateManifest performs a server-side dry-run apply of the objects in
+// the manifest of the given release, and returns an aggregate of the errors
+// of the objects rejected by the API server.
+//
+// Objects of a kind which is not known to the cluster, e.g. because it is
+// defined by a CustomResourceDefinition of the chart which has not been
+// applied yet, and objects in a namespace which does not exist yet, can not
+// be validated and are skipped.
+func ValidateManifest(ctx context.Context, config *helmaction.Configuration, rls *helmrelease.Release, fieldOwner string) error {
+	c, err := kube.NewClient(config.RESTClientGetter)
+	if err != nil {
+		return err
+	}
+	c = client.NewDryRunClient(c)
+
+	objects, err := ssautil.ReadObjects(strings.NewReader(rls.Manifest))
+	if err != nil {
+		return fmt.Errorf("failed to read objects from release manifest: %w", err)
+	}
+	if err = ssanormalize.UnstructuredListWithScheme(objects, c.Scheme()); err != nil {
+		return fmt.Errorf("failed to normalize release objects: %w", err)
+	}
+
+	var errs []error
+	for _, obj := range objects {
+		if err := kube.SetDefaultNamespace(c.RESTMapper(), obj, rls.Namespace); err != nil {
+			if apimeta.IsNoMatchError(err) {
+				continue
+			}
+			errs = append(errs, err)
+			continue
+		}
+		setHelmMetadata(obj, rls)
+
+		if err := c.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldOwner), client.ForceOwnership); err != nil {
+			if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+				continue
+			}
+			if obj.GetObjectKind().GroupVersionKind().Kind == "Secret" {
+				err = maskSensitiveErrData(err)
+			}
+			errs = append(errs, fmt.Errorf("%s dry-run failed: %w", diff.ResourceName(obj), err))
+		}
+	}
+	return apierrutil.NewAggregate(errs)
+}
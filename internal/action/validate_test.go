@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/helm-controller/internal/kube"
+)
+
+func TestValidateManifest(t *testing.T) {
+	config, cleanup := newTestCluster(t)
+	t.Cleanup(func() {
+		if err := cleanup(); err != nil {
+			t.Logf("Failed to stop the test environment: %v", err)
+		}
+	})
+
+	getter := kube.NewMemoryRESTClientGetter(config)
+	c, err := client.New(config, client.Options{})
+	if err != nil {
+		t.Fatalf("Failed to create client for test environment: %v", err)
+	}
+
+	g := NewWithT(t)
+
+	ns, err := generateNamespace(context.TODO(), c, "validate")
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(func() {
+		_ = c.Delete(context.TODO(), ns)
+	})
+
+	manifest := `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: valid
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: missing-namespace
+  namespace: does-not-exist
+---
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: unknown-kind
+`
+	rls := &helmrelease.Release{Name: "release", Namespace: ns.Name, Manifest: manifest}
+
+	t.Run("valid objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := ValidateManifest(context.TODO(), &helmaction.Configuration{RESTClientGetter: getter}, rls, "validate-test")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// The dry-run does not create any objects.
+		err = c.Get(context.TODO(), client.ObjectKey{Namespace: ns.Name, Name: "valid"}, &corev1.ConfigMap{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("rejected object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		invalid := &helmrelease.Release{Name: rls.Name, Namespace: rls.Namespace}
+		invalid.Manifest = manifest + fmt.Sprintf(`---
+apiVersion: v1
+kind: Service
+metadata:
+  name: invalid
+  namespace: %s
+spec:
+  ports:
+    - port: 700000
+`, ns.Name)
+
+		err := ValidateManifest(context.TODO(), &helmaction.Configuration{RESTClientGetter: getter}, invalid, "validate-test")
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("Service/%s/invalid dry-run failed", ns.Name)))
+		g.Expect(err.Error()).ToNot(ContainSubstring("ConfigMap"))
+	})
+}
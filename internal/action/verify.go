@@ -20,16 +20,12 @@ import (
 	"errors"
 
 	"github.com/opencontainers/go-digest"
-	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
-	helmchart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
-	helmchartutil "helm.sh/helm/v3/pkg/chartutil"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 
 	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/release"
-	"github.com/fluxcd/pkg/chartutil"
 )
 
 var (
@@ -37,8 +33,6 @@ var (
 	ErrReleaseNotFound    = errors.New("no release found")
 	ErrReleaseNotObserved = errors.New("release not observed to be made for object")
 	ErrReleaseDigest      = errors.New("release digest verification error")
-	ErrChartChanged       = errors.New("release chart changed")
-	ErrConfigDigest       = errors.New("release config values changed")
 )
 
 const (
@@ -52,6 +46,8 @@ const (
 // chart name have been mutated in such a way that it no longer has the same
 // release target as recorded in the Status.History of the object, by comparing
 // the (storage) namespace, and release and chart names.
+// A change of the storage driver is not a change of the release target, as
+// the release can be moved to the new storage using MigrateStorage.
 // This can be used to e.g. trigger a garbage collection of the old release
 // before installing the new one.
 // If no change is detected, an empty string is returned along with false.
@@ -73,14 +69,14 @@ func ReleaseTargetChanged(obj *v2.HelmRelease, chartName string) (string, bool)
 	}
 }
 
-// LastRelease returns the last release object in the Helm storage with the
-// given name.
+// LastRelease returns the last release object in the given release.Store with
+// the given name.
 // It returns an error of type ErrReleaseNotFound if there is no
 // release with the given name.
 // When the release name is too long, it will be shortened to the maximum
 // allowed length using the release.ShortenName function.
-func LastRelease(config *helmaction.Configuration, releaseName string) (*helmrelease.Release, error) {
-	rls, err := config.Releases.Last(release.ShortenName(releaseName))
+func LastRelease(store release.Store, releaseName string) (*helmrelease.Release, error) {
+	rls, err := store.Last(release.ShortenName(releaseName))
 	if err != nil {
 		if errors.Is(err, helmdriver.ErrReleaseNotFound) {
 			return nil, ErrReleaseNotFound
@@ -91,16 +87,16 @@ func LastRelease(config *helmaction.Configuration, releaseName string) (*helmrel
 }
 
 // VerifySnapshot verifies the data of the given v2.Snapshot
-// matches the release object in the Helm storage. It returns the verified
+// matches the release object in the given release.Store. It returns the verified
 // release, or an error of type ErrReleaseNotFound, ErrReleaseDisappeared,
 // ErrReleaseDigest or ErrReleaseNotObserved indicating the reason for the
 // verification failure.
-func VerifySnapshot(config *helmaction.Configuration, snapshot *v2.Snapshot) (rls *helmrelease.Release, err error) {
+func VerifySnapshot(store release.Store, snapshot *v2.Snapshot) (rls *helmrelease.Release, err error) {
 	if snapshot == nil {
 		return nil, ErrReleaseNotFound
 	}
 
-	rls, err = config.Releases.Get(snapshot.Name, snapshot.Version)
+	rls, err = store.Get(snapshot.Name, snapshot.Version)
 	if err != nil {
 		if errors.Is(err, helmdriver.ErrReleaseNotFound) {
 			return nil, ErrReleaseDisappeared
@@ -141,22 +137,3 @@ func VerifyReleaseObject(snapshot *v2.Snapshot, rls *helmrelease.Release) error
 	}
 	return nil
 }
-
-// VerifyRelease verifies that the data of the given release matches the given
-// chart metadata, and the provided values match the Snapshot.ConfigDigest.
-// It returns either an error of type ErrReleaseNotFound, ErrChartChanged or
-// ErrConfigDigest, or nil.
-func VerifyRelease(rls *helmrelease.Release, snapshot *v2.Snapshot, chrt *helmchart.Metadata, vals helmchartutil.Values) error {
-	if rls == nil {
-		return ErrReleaseNotFound
-	}
-
-	if chrt != nil && (rls.Chart.Metadata.Name != chrt.Name || rls.Chart.Metadata.Version != chrt.Version) {
-		return ErrChartChanged
-	}
-
-	if snapshot == nil || !chartutil.VerifyValues(digest.Digest(snapshot.ConfigDigest), vals) {
-		return ErrConfigDigest
-	}
-	return nil
-}
@@ -21,9 +21,6 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
-	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
-	helmchart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
-	"github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
 	"github.com/jessesimpson36/helm/v4/pkg/storage/driver"
@@ -114,6 +111,43 @@ func TestReleaseTargetChanged(t *testing.T) {
 			wantReason: targetStorageNamespace,
 			want:       true,
 		},
+		{
+			name:      "different storage driver",
+			chartName: defaultChartName,
+			spec: v2.HelmReleaseSpec{
+				StorageDriver: "configmap",
+			},
+			status: v2.HelmReleaseStatus{
+				History: v2.Snapshots{
+					{
+						Name:      defaultName,
+						Namespace: defaultNamespace,
+						ChartName: defaultChartName,
+					},
+				},
+				StorageNamespace: defaultNamespace,
+				StorageDriver:    "secret",
+			},
+			want: false,
+		},
+		{
+			name:      "unrecorded storage driver",
+			chartName: defaultChartName,
+			spec: v2.HelmReleaseSpec{
+				StorageDriver: "secret",
+			},
+			status: v2.HelmReleaseStatus{
+				History: v2.Snapshots{
+					{
+						Name:      defaultName,
+						Namespace: defaultNamespace,
+						ChartName: defaultChartName,
+					},
+				},
+				StorageNamespace: defaultNamespace,
+			},
+			want: false,
+		},
 		{
 			name:      "different release namespace",
 			chartName: defaultChartName,
@@ -294,7 +328,7 @@ func TestVerifySnapshot(t *testing.T) {
 				GetErr: tt.getError,
 			}
 
-			rls, err := VerifySnapshot(&helmaction.Configuration{Releases: s}, tt.snapshot)
+			rls, err := VerifySnapshot(release.NewStorageStore(s), tt.snapshot)
 			if tt.wantErr != nil {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(err).To(Equal(tt.wantErr))
@@ -363,82 +397,3 @@ func TestVerifyReleaseObject(t *testing.T) {
 		})
 	}
 }
-
-func TestVerifyRelease(t *testing.T) {
-	mockRls := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
-		Name:      "release",
-		Version:   1,
-		Status:    helmrelease.StatusSuperseded,
-		Namespace: "default",
-	})
-	mockSnapshot := release.ObservedToSnapshot(release.ObserveRelease(mockRls))
-
-	tests := []struct {
-		name     string
-		rls      *helmrelease.Release
-		snapshot *v2.Snapshot
-		chrt     *helmchart.Metadata
-		vals     chartutil.Values
-		wantErr  error
-	}{
-		{
-			name:     "equal",
-			rls:      mockRls,
-			snapshot: mockSnapshot,
-			chrt:     mockRls.Chart.Metadata,
-			vals:     mockRls.Config,
-		},
-		{
-			name:     "no release",
-			rls:      nil,
-			snapshot: mockSnapshot,
-			chrt:     mockRls.Chart.Metadata,
-			vals:     mockRls.Config,
-			wantErr:  ErrReleaseNotFound,
-		},
-		{
-			name:     "no release snapshot",
-			rls:      mockRls,
-			snapshot: nil,
-			chrt:     mockRls.Chart.Metadata,
-			vals:     mockRls.Config,
-			wantErr:  ErrConfigDigest,
-		},
-		{
-			name:     "chart meta diff",
-			rls:      mockRls,
-			snapshot: mockSnapshot,
-			chrt: &helmchart.Metadata{
-				Name:    "some-other-chart",
-				Version: "1.0.0",
-			},
-			vals:    mockRls.Config,
-			wantErr: ErrChartChanged,
-		},
-		{
-			name:     "chart values diff",
-			rls:      mockRls,
-			snapshot: mockSnapshot,
-			chrt:     mockRls.Chart.Metadata,
-			vals: chartutil.Values{
-				"some": "other",
-			},
-			wantErr: ErrConfigDigest,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			g := NewWithT(t)
-
-			got := VerifyRelease(tt.rls, tt.snapshot, tt.chrt, tt.vals)
-
-			if tt.wantErr != nil {
-				g.Expect(got).To(HaveOccurred())
-				g.Expect(got).To(Equal(tt.wantErr))
-				return
-			}
-
-			g.Expect(got).ToNot(HaveOccurred())
-		})
-	}
-}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// concurrencyLimiter limits the combined weight of the HelmReleases which
+// are reconciled concurrently, allowing heavyweight releases to occupy
+// multiple reconciliation slots.
+type concurrencyLimiter struct {
+	mu       sync.RWMutex
+	sem      *semaphore.Weighted
+	capacity int
+	// max is the capacity the limiter was created with, which can not be
+	// exceeded by setCapacity.
+	max int
+}
+
+// newConcurrencyLimiter returns a concurrencyLimiter with the given number
+// of slots, or nil if the capacity is not positive.
+func newConcurrencyLimiter(capacity int) *concurrencyLimiter {
+	if capacity < 1 {
+		return nil
+	}
+	return &concurrencyLimiter{
+		sem:      semaphore.NewWeighted(int64(capacity)),
+		capacity: capacity,
+		max:      capacity,
+	}
+}
+
+// setCapacity changes the number of slots of the limiter, capped to the
+// capacity it was created with. A capacity which is not positive restores
+// the capacity the limiter was created with. Slots held at the time of the
+// change are released to the previous slots, which means the new capacity
+// may be exceeded until they have been released. A nil limiter is left
+// unlimited.
+func (l *concurrencyLimiter) setCapacity(capacity int) {
+	if l == nil {
+		return
+	}
+	if capacity < 1 || capacity > l.max {
+		capacity = l.max
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if capacity == l.capacity {
+		return
+	}
+	l.sem = semaphore.NewWeighted(int64(capacity))
+	l.capacity = capacity
+}
+
+// acquire blocks until the given number of slots is available, or the
+// context is canceled. A weight exceeding the capacity of the limiter is
+// capped to the capacity, to ensure it can be acquired. The returned function
+// releases the slots, and must be called once the reconciliation finished.
+// A nil limiter does not limit.
+func (l *concurrencyLimiter) acquire(ctx context.Context, weight int) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	l.mu.RLock()
+	sem, capacity := l.sem, l.capacity
+	l.mu.RUnlock()
+
+	if weight > capacity {
+		weight = capacity
+	}
+	if weight < 1 {
+		weight = 1
+	}
+	if err := sem.Acquire(ctx, int64(weight)); err != nil {
+		return nil, err
+	}
+	return func() {
+		sem.Release(int64(weight))
+	}, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_concurrencyLimiter(t *testing.T) {
+	t.Run("nil limiter does not limit", func(t *testing.T) {
+		g := NewWithT(t)
+
+		l := newConcurrencyLimiter(0)
+		g.Expect(l).To(BeNil())
+
+		release, err := l.acquire(context.TODO(), 10)
+		g.Expect(err).ToNot(HaveOccurred())
+		release()
+	})
+
+	t.Run("heavyweight occupies multiple slots", func(t *testing.T) {
+		g := NewWithT(t)
+
+		l := newConcurrencyLimiter(4)
+		releaseHeavy, err := l.acquire(context.TODO(), 3)
+		g.Expect(err).ToNot(HaveOccurred())
+		releaseLight, err := l.acquire(context.TODO(), 1)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+		_, err = l.acquire(ctx, 1)
+		g.Expect(err).To(MatchError(context.DeadlineExceeded))
+
+		releaseHeavy()
+		release, err := l.acquire(context.TODO(), 3)
+		g.Expect(err).ToNot(HaveOccurred())
+		release()
+		releaseLight()
+	})
+
+	t.Run("weight is capped to capacity", func(t *testing.T) {
+		g := NewWithT(t)
+
+		l := newConcurrencyLimiter(2)
+		release, err := l.acquire(context.TODO(), 10)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+		_, err = l.acquire(ctx, 1)
+		g.Expect(err).To(HaveOccurred())
+
+		release()
+		release, err = l.acquire(context.TODO(), 2)
+		g.Expect(err).ToNot(HaveOccurred())
+		release()
+	})
+	t.Run("capacity can be lowered and restored", func(t *testing.T) {
+		g := NewWithT(t)
+
+		l := newConcurrencyLimiter(4)
+		l.setCapacity(1)
+		release, err := l.acquire(context.TODO(), 1)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+		_, err = l.acquire(ctx, 1)
+		g.Expect(err).To(HaveOccurred())
+		release()
+
+		// The capacity can not be raised beyond the initial capacity.
+		l.setCapacity(10)
+		g.Expect(l.capacity).To(Equal(4))
+		release, err = l.acquire(context.TODO(), 4)
+		g.Expect(err).ToNot(HaveOccurred())
+		release()
+
+		l.setCapacity(2)
+		l.setCapacity(0)
+		g.Expect(l.capacity).To(Equal(4))
+	})
+
+	t.Run("nil limiter ignores capacity", func(t *testing.T) {
+		var l *concurrencyLimiter
+		l.setCapacity(2)
+	})
+}
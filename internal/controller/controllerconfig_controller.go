@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	apierrutil "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/patch"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/features"
+)
+
+// +kubebuilder:rbac:groups=helm.toolkit.fluxcd.io,resources=controllerconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=helm.toolkit.fluxcd.io,resources=controllerconfigs/status,verbs=get;update;patch
+
+// ControllerConfigReconciler reconciles the v2.ControllerConfig with the
+// configured Name, by applying its spec to the HelmReleaseReconciler. Any
+// other ControllerConfig is ignored. When the ControllerConfig is deleted,
+// the defaults configured using the flags of the controller are restored.
+// The HelmReleaseReconciler does not reconcile any HelmRelease until the
+// ControllerConfig, or the absence of it, has been applied.
+type ControllerConfigReconciler struct {
+	client.Client
+
+	FieldManager string
+
+	// Name of the v2.ControllerConfig to apply.
+	Name string
+	// HelmReleaseReconciler the v2.ControllerConfig is applied to.
+	HelmReleaseReconciler *HelmReleaseReconciler
+}
+
+func (r *ControllerConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Hold the reconciliation of HelmReleases until the ControllerConfig
+	// has been applied for the first time.
+	r.HelmReleaseReconciler.controllerConfigRequired.Store(true)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v2.ControllerConfig{}, builder.WithPredicates(
+			predicate.GenerationChangedPredicate{},
+			predicate.NewPredicateFuncs(func(o client.Object) bool {
+				return o.GetName() == r.Name
+			}),
+		)).
+		// Always reconcile the ControllerConfig once on start, as no event
+		// is received when it does not exist.
+		WatchesRawSource(source.Func(func(_ context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: r.Name}})
+			return nil
+		})).
+		Complete(r)
+}
+
+func (r *ControllerConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	obj := &v2.ControllerConfig{}
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		if err := r.HelmReleaseReconciler.applyControllerConfig(nil); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("ControllerConfig deleted: restored the defaults of the controller flags")
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper := patch.NewSerialPatcher(obj, r.Client)
+	defer func() {
+		if err := patchHelper.Patch(ctx, obj,
+			patch.WithFieldOwner(r.FieldManager),
+			patch.WithOwnedConditions{Conditions: []string{meta.ReadyCondition}},
+			patch.WithStatusObservedGeneration{},
+		); err != nil {
+			retErr = apierrutil.Reduce(apierrutil.NewAggregate([]error{retErr, err}))
+		}
+	}()
+
+	if err := r.HelmReleaseReconciler.applyControllerConfig(&obj.Spec); err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason,
+			"Invalid configuration, the previous configuration remains applied: %s", err)
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	}
+
+	log.Info("ControllerConfig applied")
+	conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "Configuration applied")
+	return ctrl.Result{}, nil
+}
+
+// applyControllerConfig validates the given v2.ControllerConfigSpec, and
+// applies it to the reconciler. A nil spec restores the defaults configured
+// using the flags of the controller. When the spec is invalid, the
+// previously applied spec remains in effect.
+func (r *HelmReleaseReconciler) applyControllerConfig(spec *v2.ControllerConfigSpec) error {
+	if spec == nil {
+		spec = &v2.ControllerConfigSpec{}
+	}
+	if r.concurrency != nil && spec.MaxConcurrentReconciles != nil && *spec.MaxConcurrentReconciles > r.concurrency.max {
+		return fmt.Errorf("maxConcurrentReconciles %d exceeds the --concurrent flag of %d",
+			*spec.MaxConcurrentReconciles, r.concurrency.max)
+	}
+	if err := features.SetConfigured(spec.FeatureGates); err != nil {
+		return err
+	}
+
+	var capacity int
+	if spec.MaxConcurrentReconciles != nil {
+		capacity = *spec.MaxConcurrentReconciles
+	}
+	r.concurrency.setCapacity(capacity)
+	r.controllerConfig.Store(spec.DeepCopy())
+	return nil
+}
+
+// applyControllerConfigDefaults applies the defaults of the given
+// v2.ControllerConfigSpec to the fields of the given v2.HelmRelease which
+// are not set. As the result must never be persisted to the spec of the
+// object, this must be done before a patch helper is initialized.
+func applyControllerConfigDefaults(obj *v2.HelmRelease, spec *v2.ControllerConfigSpec) {
+	if spec == nil {
+		return
+	}
+	if obj.Spec.Timeout == nil && spec.Timeout != nil {
+		obj.Spec.Timeout = spec.Timeout.DeepCopy()
+	}
+	if obj.Spec.MaxHistory == nil && spec.MaxHistory != nil {
+		maxHistory := *spec.MaxHistory
+		obj.Spec.MaxHistory = &maxHistory
+	}
+	if obj.Spec.ArtifactVerification == "" {
+		obj.Spec.ArtifactVerification = spec.ArtifactVerification
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	feathelper "github.com/fluxcd/pkg/runtime/features"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/features"
+)
+
+func TestControllerConfigReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect((&feathelper.FeatureGates{}).SupportedFeatures(features.FeatureGates())).To(Succeed())
+	t.Cleanup(func() {
+		_ = features.SetConfigured(nil)
+	})
+
+	obj := &v2.ControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "helm-controller",
+			Generation: 1,
+		},
+		Spec: v2.ControllerConfigSpec{
+			MaxHistory:              ptr.To(10),
+			MaxConcurrentReconciles: ptr.To(2),
+			DeniedNamespaces:        []string{"kube-system"},
+			FeatureGates:            map[string]bool{features.AllowDNSLookups: true},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(NewTestScheme()).
+		WithStatusSubresource(&v2.ControllerConfig{}).
+		WithObjects(obj).
+		Build()
+
+	hr := &HelmReleaseReconciler{concurrency: newConcurrencyLimiter(4)}
+	r := &ControllerConfigReconciler{Client: c, Name: obj.Name, HelmReleaseReconciler: hr}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+
+	// A valid configuration is applied.
+	_, err := r.Reconcile(context.TODO(), req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(context.TODO(), req.NamespacedName, obj)).To(Succeed())
+	g.Expect(conditions.IsTrue(obj, meta.ReadyCondition)).To(BeTrue())
+	g.Expect(hr.controllerConfig.Load()).To(Equal(&obj.Spec))
+	g.Expect(hr.concurrency.capacity).To(Equal(2))
+	g.Expect(features.EnabledFor(&v2.HelmRelease{}, features.AllowDNSLookups)).To(BeTrue())
+
+	// An invalid configuration leaves the previous configuration applied.
+	applied := obj.Spec.DeepCopy()
+	obj.Spec.MaxConcurrentReconciles = ptr.To(8)
+	g.Expect(c.Update(context.TODO(), obj)).To(Succeed())
+	_, err = r.Reconcile(context.TODO(), req)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(c.Get(context.TODO(), req.NamespacedName, obj)).To(Succeed())
+	g.Expect(conditions.IsFalse(obj, meta.ReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetMessage(obj, meta.ReadyCondition)).To(ContainSubstring("exceeds the --concurrent flag"))
+	g.Expect(hr.controllerConfig.Load()).To(Equal(applied))
+	g.Expect(hr.concurrency.capacity).To(Equal(2))
+
+	// Deleting the configuration restores the defaults of the flags.
+	g.Expect(c.Delete(context.TODO(), obj)).To(Succeed())
+	_, err = r.Reconcile(context.TODO(), req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hr.controllerConfig.Load()).To(Equal(&v2.ControllerConfigSpec{}))
+	g.Expect(hr.concurrency.capacity).To(Equal(4))
+	g.Expect(features.EnabledFor(&v2.HelmRelease{}, features.AllowDNSLookups)).To(BeFalse())
+}
+
+func Test_applyControllerConfigDefaults(t *testing.T) {
+	spec := &v2.ControllerConfigSpec{
+		Timeout:              &metav1.Duration{Duration: 10 * time.Minute},
+		MaxHistory:           ptr.To(10),
+		ArtifactVerification: v2.ArtifactVerificationWarn,
+	}
+
+	t.Run("applies defaults to unset fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{}
+		applyControllerConfigDefaults(obj, spec)
+		g.Expect(obj.GetTimeout().Duration).To(Equal(10 * time.Minute))
+		g.Expect(obj.Spec.MaxHistory).To(Equal(ptr.To(10)))
+		g.Expect(obj.Spec.ArtifactVerification).To(Equal(v2.ArtifactVerificationWarn))
+
+		// The defaults are not shared with the object.
+		*obj.Spec.MaxHistory = 1
+		g.Expect(*spec.MaxHistory).To(Equal(10))
+	})
+
+	t.Run("retains fields of the object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			Spec: v2.HelmReleaseSpec{
+				Timeout:              &metav1.Duration{Duration: time.Minute},
+				MaxHistory:           ptr.To(0),
+				ArtifactVerification: v2.ArtifactVerificationEnforce,
+			},
+		}
+		applyControllerConfigDefaults(obj, spec)
+		g.Expect(obj.GetTimeout().Duration).To(Equal(time.Minute))
+		g.Expect(obj.Spec.MaxHistory).To(Equal(ptr.To(0)))
+		g.Expect(obj.Spec.ArtifactVerification).To(Equal(v2.ArtifactVerificationEnforce))
+	})
+
+	t.Run("without config", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{}
+		applyControllerConfigDefaults(obj, nil)
+		g.Expect(obj.Spec).To(Equal(v2.HelmReleaseSpec{}))
+	})
+}
+
+func TestHelmReleaseReconciler_Reconcile_waitsForControllerConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "release",
+			Namespace: "default",
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(NewTestScheme()).
+		WithObjects(obj).
+		Build()
+
+	r := &HelmReleaseReconciler{Client: c, concurrency: newConcurrencyLimiter(4)}
+	r.controllerConfigRequired.Store(true)
+
+	res, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(controllerConfigWaitInterval))
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+	g.Expect(obj.Status.Conditions).To(BeEmpty())
+}
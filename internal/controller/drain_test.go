@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_drainContext(t *testing.T) {
+	t.Run("outlives parent for grace period", func(t *testing.T) {
+		g := NewWithT(t)
+
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := drainContext(parent, 200*time.Millisecond)
+		defer cancel()
+
+		cancelParent()
+		g.Consistently(ctx.Done(), 100*time.Millisecond).ShouldNot(BeClosed())
+		g.Eventually(ctx.Done(), time.Second).Should(BeClosed())
+	})
+
+	t.Run("canceled immediately without grace period", func(t *testing.T) {
+		g := NewWithT(t)
+
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := drainContext(parent, 0)
+		defer cancel()
+
+		cancelParent()
+		g.Eventually(ctx.Done(), time.Second).Should(BeClosed())
+	})
+
+	t.Run("not canceled with negative grace period", func(t *testing.T) {
+		g := NewWithT(t)
+
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := drainContext(parent, -1)
+
+		cancelParent()
+		g.Consistently(ctx.Done(), 100*time.Millisecond).ShouldNot(BeClosed())
+
+		cancel()
+		g.Expect(ctx.Done()).To(BeClosed())
+	})
+
+	t.Run("canceled by cancel function", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx, cancel := drainContext(context.Background(), time.Hour)
+		cancel()
+		g.Expect(ctx.Done()).To(BeClosed())
+	})
+
+	t.Run("retains parent values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		type key struct{}
+		ctx, cancel := drainContext(context.WithValue(context.Background(), key{}, "value"), time.Hour)
+		defer cancel()
+		g.Expect(ctx.Value(key{})).To(Equal("value"))
+	})
+}
@@ -18,18 +18,37 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	helmchartutil "github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	celtypes "github.com/google/cel-go/common/types"
+	"github.com/google/go-cmp/cmp"
 	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	helmchartutil "github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	apierrutil "k8s.io/apimachinery/pkg/util/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/rest"
@@ -43,11 +62,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 
-	"github.com/Masterminds/semver"
+	"github.com/Masterminds/semver/v3"
+	kstatus "github.com/fluxcd/cli-utils/pkg/kstatus/status"
 	aclv1 "github.com/fluxcd/pkg/apis/acl"
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/acl"
+	"github.com/fluxcd/pkg/runtime/cel"
 	runtimeClient "github.com/fluxcd/pkg/runtime/client"
 	"github.com/fluxcd/pkg/runtime/conditions"
 	helper "github.com/fluxcd/pkg/runtime/controller"
@@ -58,6 +80,7 @@ import (
 	"github.com/fluxcd/pkg/runtime/predicates"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	sourcev1beta2 "github.com/fluxcd/source-controller/api/v1beta2"
+	"golang.org/x/net/http/httpproxy"
 
 	"github.com/fluxcd/pkg/chartutil"
 
@@ -67,12 +90,18 @@ import (
 	"github.com/fluxcd/helm-controller/internal/digest"
 	interrors "github.com/fluxcd/helm-controller/internal/errors"
 	"github.com/fluxcd/helm-controller/internal/features"
+	"github.com/fluxcd/helm-controller/internal/inflight"
 	"github.com/fluxcd/helm-controller/internal/kube"
 	"github.com/fluxcd/helm-controller/internal/loader"
 	"github.com/fluxcd/helm-controller/internal/postrender"
 	intpredicates "github.com/fluxcd/helm-controller/internal/predicates"
+	"github.com/fluxcd/helm-controller/internal/probe"
 	intreconcile "github.com/fluxcd/helm-controller/internal/reconcile"
 	"github.com/fluxcd/helm-controller/internal/release"
+	"github.com/fluxcd/helm-controller/internal/retention"
+	"github.com/fluxcd/helm-controller/internal/storage"
+	"github.com/fluxcd/helm-controller/internal/tracing"
+	intvalues "github.com/fluxcd/helm-controller/internal/values"
 )
 
 // +kubebuilder:rbac:groups=helm.toolkit.fluxcd.io,resources=helmreleases,verbs=get;list;watch;create;update;patch;delete
@@ -82,7 +111,8 @@ import (
 // +kubebuilder:rbac:groups=source.toolkit.fluxcd.io,resources=helmcharts/status,verbs=get
 // +kubebuilder:rbac:groups=source.toolkit.fluxcd.io,resources=ocirepositories,verbs=get;list;watch
 // +kubebuilder:rbac:groups=source.toolkit.fluxcd.io,resources=ocirepositories/status,verbs=get
-// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;list;patch
 
 // HelmReleaseReconciler reconciles a HelmRelease object.
 type HelmReleaseReconciler struct {
@@ -95,22 +125,114 @@ type HelmReleaseReconciler struct {
 	KubeConfigOpts   runtimeClient.KubeConfigOptions
 	APIReader        client.Reader
 
+	// RequireKubeConfigTLS requires the KubeConfig of a HelmRelease to
+	// connect to the remote cluster over verified TLS.
+	RequireKubeConfigTLS bool
+
 	FieldManager          string
 	DefaultServiceAccount string
+	ClusterAttributes     map[string]string
 
 	requeueDependency    time.Duration
+	resyncInterval       time.Duration
 	artifactFetchRetries int
+	artifactHTTPClient   *http.Client
+	proxyClients         *loader.ProxyClientCache
+	artifactCache        *loader.ArtifactCache
+	artifactCachePeer    string
+	artifactCacheToken   string
+	maxArtifactSize      int64
+	maxChartSize         int64
+	failedArtifacts      *retention.Store
+	concurrency          *concurrencyLimiter
+	inFlight             *inflight.Tracker
+	shutdownGracePeriod  time.Duration
+	clockSkewTolerance   time.Duration
+	sourceStaleThreshold time.Duration
+	sourceAvailability   *sourceAvailability
+	discoveryCache       *kube.DiscoveryCache
+	clientCache          *kube.ClientCache
+	sqlDrivers           *action.SQLDriverCache
+	probeAllowlist       *probe.Allowlist
+
+	// controllerConfig holds the spec of the v2.ControllerConfig applied
+	// by the ControllerConfigReconciler, if any.
+	controllerConfig atomic.Pointer[v2.ControllerConfigSpec]
+	// controllerConfigRequired is set when a ControllerConfigReconciler
+	// is configured, to hold the reconciliation of HelmReleases until the
+	// v2.ControllerConfig has been applied for the first time.
+	controllerConfigRequired atomic.Bool
+
+	// unavailableSourceKinds holds the source kinds of which the API was
+	// not installed in the cluster when the controller started.
+	unavailableSourceKinds map[string]bool
 }
 
 type HelmReleaseReconcilerOptions struct {
 	HTTPRetry                 int
+	HTTPClient                loader.HTTPClientOptions
+	ArtifactCacheSize         int
+	ArtifactCacheServerAddr   string
+	ArtifactCachePeer         string
+	ArtifactCacheToken        string
+	MaxArtifactSize           int64
+	MaxChartSize              int64
+	FailedArtifactRetention   retention.Options
+	MaxConcurrentReconciles   int
+	InFlight                  *inflight.Tracker
+	ShutdownGracePeriod       time.Duration
+	ClockSkewTolerance        time.Duration
+	SourceStaleThreshold      time.Duration
+	DiscoveryCacheTTL         time.Duration
+	PostDeployCheckAllowlist  *probe.Allowlist
 	DependencyRequeueInterval time.Duration
+	ResyncInterval            time.Duration
 	RateLimiter               workqueue.TypedRateLimiter[reconcile.Request]
 }
 
 var (
 	errWaitForDependency = errors.New("must wait for dependency")
 	errWaitForChart      = errors.New("must wait for chart")
+	errReleaseConflict   = errors.New("must wait for release name conflict to be resolved")
+)
+
+const (
+	// envStorageSQLConnectionString is the name of the environment variable
+	// holding the default connection string for the SQL storage driver.
+	envStorageSQLConnectionString = "HELM_DRIVER_SQL_CONNECTION_STRING"
+	// defaultStorageSQLSecretKey is the default key of the connection string
+	// in the Secret referenced by HelmReleaseSpec.StorageSQLSecretRef.
+	defaultStorageSQLSecretKey = "dsn"
+	// dryRunSucceededReason is the event reason for a successful dry-run.
+	dryRunSucceededReason = "DryRunSucceeded"
+	// dryRunFailedReason is the event reason for a failed dry-run.
+	dryRunFailedReason = "DryRunFailed"
+	// supportBundleSucceededReason is the event reason for a successfully
+	// collected support bundle.
+	supportBundleSucceededReason = "SupportBundleSucceeded"
+	// supportBundleFailedReason is the event reason for a failure to
+	// collect a support bundle.
+	supportBundleFailedReason = "SupportBundleFailed"
+	// supportBundleMaxEvents is the maximum number of the most recent events
+	// of the HelmRelease included in a support bundle.
+	supportBundleMaxEvents = 20
+	// effectiveConfigSucceededReason is the event reason for a successfully
+	// written effective configuration.
+	effectiveConfigSucceededReason = "EffectiveConfigSucceeded"
+	// effectiveConfigFailedReason is the event reason for a failure to
+	// write the effective configuration.
+	effectiveConfigFailedReason = "EffectiveConfigFailed"
+	// verificationWindowCheckTimeout is the timeout of a single health check
+	// of the resources of a release within its verification window.
+	verificationWindowCheckTimeout = 10 * time.Second
+	// verificationWindowInterval is the maximum interval at which the health
+	// of the resources of a release is checked within its verification
+	// window.
+	verificationWindowInterval = 30 * time.Second
+	// controllerConfigWaitInterval is the interval at which the
+	// reconciliation of a HelmRelease is retried while waiting for the
+	// v2.ControllerConfig to be applied.
+	controllerConfigWaitInterval = 5 * time.Second
 )
 
 func (r *HelmReleaseReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opts HelmReleaseReconcilerOptions) error {
@@ -118,6 +240,11 @@ func (r *HelmReleaseReconciler) SetupWithManager(ctx context.Context, mgr ctrl.M
 	if err := mgr.GetFieldIndexer().IndexField(ctx, &v2.HelmRelease{}, v2.SourceIndexKey,
 		func(o client.Object) []string {
 			obj := o.(*v2.HelmRelease)
+			if obj.GetChartOverride(r.ClusterAttributes) != nil || obj.HasChartTemplate() {
+				obj = obj.DeepCopy()
+				applyChartOverride(obj, r.ClusterAttributes)
+				_ = intreconcile.RenderChartTemplate(obj)
+			}
 			namespacedName, err := getNamespacedName(obj)
 			if err != nil {
 				return nil
@@ -130,23 +257,103 @@ func (r *HelmReleaseReconciler) SetupWithManager(ctx context.Context, mgr ctrl.M
 		return err
 	}
 
+	// Index the HelmRelease by the HelmReleases they depend on.
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v2.HelmRelease{}, v2.DependsOnIndexKey, indexDependsOn); err != nil {
+		return err
+	}
+
+	// Index the HelmRelease by the HelmReleases they import values from.
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v2.HelmRelease{}, v2.ValuesFromIndexKey, indexValuesFrom); err != nil {
+		return err
+	}
+
+	// Index the HelmRelease by the Helm release they target.
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName); err != nil {
+		return err
+	}
+
 	r.requeueDependency = opts.DependencyRequeueInterval
+	r.resyncInterval = opts.ResyncInterval
 	r.artifactFetchRetries = opts.HTTPRetry
+	httpClient, err := loader.NewHTTPClient(opts.HTTPClient)
+	if err != nil {
+		return fmt.Errorf("failed to configure artifact HTTP client: %w", err)
+	}
+	r.artifactHTTPClient = httpClient
+	r.proxyClients = loader.NewProxyClientCache(httpClient)
+	r.artifactCache = loader.NewArtifactCache(opts.ArtifactCacheSize)
+	r.artifactCachePeer = opts.ArtifactCachePeer
+	r.artifactCacheToken = opts.ArtifactCacheToken
+	r.maxArtifactSize = opts.MaxArtifactSize
+	r.maxChartSize = opts.MaxChartSize
+	failedArtifacts, err := retention.NewStore(opts.FailedArtifactRetention.Dir,
+		opts.FailedArtifactRetention.MaxCount, opts.FailedArtifactRetention.MaxSize)
+	if err != nil {
+		return fmt.Errorf("failed to configure failed artifact retention: %w", err)
+	}
+	r.failedArtifacts = failedArtifacts
+	r.concurrency = newConcurrencyLimiter(opts.MaxConcurrentReconciles)
+	r.inFlight = opts.InFlight
+	r.shutdownGracePeriod = opts.ShutdownGracePeriod
+	r.clockSkewTolerance = opts.ClockSkewTolerance
+	r.sourceStaleThreshold = opts.SourceStaleThreshold
+	r.sourceAvailability = newSourceAvailability()
+	r.discoveryCache = kube.NewDiscoveryCache(opts.DiscoveryCacheTTL)
+	r.clientCache = kube.NewClientCache()
+	r.probeAllowlist = opts.PostDeployCheckAllowlist
+	r.sqlDrivers = action.NewSQLDriverCache(action.NewDebugLog(ctrl.LoggerFrom(ctx).WithName("sql-storage").V(logger.TraceLevel)))
+
+	unavailable, err := detectUnavailableSourceKinds(mgr.GetRESTMapper())
+	if err != nil {
+		return err
+	}
+	r.unavailableSourceKinds = unavailable
+
+	if opts.ArtifactCacheServerAddr != "" {
+		if r.artifactCache == nil {
+			return errors.New("artifact cache server requires the artifact cache to be enabled")
+		}
+		if err := mgr.Add(loader.NewArtifactCacheServer(opts.ArtifactCacheServerAddr, r.artifactCache, opts.ArtifactCacheToken)); err != nil {
+			return err
+		}
+	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&v2.HelmRelease{}, builder.WithPredicates(
-			predicate.Or(predicate.GenerationChangedPredicate{}, predicates.ReconcileRequestedPredicate{}),
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicates.ReconcileRequestedPredicate{},
+				intpredicates.SuspendAnnotationChangedPredicate{}, intpredicates.ChartTemplateChangedPredicate{}),
+			intpredicates.SuspendedPredicate{},
 		)).
 		Watches(
+			&v2.HelmRelease{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForDependencyChange),
+			builder.WithPredicates(intpredicates.DependencyReadyPredicate{}),
+		).
+		Watches(
+			&v2.HelmRelease{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForValuesChange),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
+
+	// Only watch the source kinds of which the API is installed, as the
+	// informers of the others would fail to sync, preventing the manager
+	// from starting.
+	if r.sourceAPIAvailable(sourcev1.HelmChartKind) {
+		b = b.Watches(
 			&sourcev1.HelmChart{},
 			handler.EnqueueRequestsFromMapFunc(r.requestsForHelmChartChange),
 			builder.WithPredicates(intpredicates.SourceRevisionChangePredicate{}),
-		).
-		Watches(
+		)
+	}
+	if r.sourceAPIAvailable(sourcev1beta2.OCIRepositoryKind) {
+		b = b.Watches(
 			&sourcev1beta2.OCIRepository{},
 			handler.EnqueueRequestsFromMapFunc(r.requestsForOCIRrepositoryChange),
 			builder.WithPredicates(intpredicates.SourceRevisionChangePredicate{}),
-		).
+		)
+	}
+
+	return b.
 		WithOptions(controller.Options{
 			RateLimiter: opts.RateLimiter,
 		}).
@@ -157,16 +364,66 @@ func (r *HelmReleaseReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	start := time.Now()
 	log := ctrl.LoggerFrom(ctx)
 
-	// Fetch the HelmRelease
 	obj := &v2.HelmRelease{}
+
+	// Trace the reconciliation, and record its final result.
+	ctx, span := tracing.StartSpan(ctx, "reconcile",
+		attribute.String("helmrelease.namespace", req.Namespace),
+		attribute.String("helmrelease.name", req.Name))
+	defer func() {
+		tracing.EndSpan(span, retErr, intreconcile.SecretRedactor(obj))
+	}()
+
+	// Fetch the HelmRelease
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Hold the reconciliation until the v2.ControllerConfig has been
+	// applied, to ensure its namespace deny-list and feature gates are
+	// in effect before any release is acted on.
+	if r.controllerConfigRequired.Load() && r.controllerConfig.Load() == nil {
+		log.V(logger.DebugLevel).Info("waiting for the ControllerConfig to be applied")
+		return ctrl.Result{RequeueAfter: controllerConfigWaitInterval}, nil
+	}
+
+	// Keep track of the reconciliation while it is in progress, to allow
+	// diagnosing reconciliations which appear stuck.
+	ctx, untrack := r.inFlight.Start(ctx, req.String(), "waiting-for-slot")
+	defer untrack()
+
+	// Wait for the reconciliation slots the object occupies to become
+	// available, to prevent a few heavyweight releases from starving the
+	// others. This is done before draining, as a reconciliation which has
+	// not started yet should not delay the shutdown of the controller.
+	releaseSlots, err := r.concurrency.acquire(ctx, obj.GetConcurrencyWeight())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer releaseSlots()
+	inflight.SetPhase(ctx, "reconcile")
+
+	// Allow any in-flight Helm action to complete, and its result to be
+	// recorded, when the controller is shutting down.
+	ctx, cancel := drainContext(ctx, r.shutdownGracePeriod)
+	defer cancel()
+
 	if !isValidChartRef(obj) {
 		return ctrl.Result{}, reconcile.TerminalError(fmt.Errorf("invalid Chart reference"))
 	}
 
+	// Apply the chart override matching the cluster attributes, and render
+	// the chart name and version templates. This is done before initializing
+	// the patch helper, to ensure the result is never persisted to the spec
+	// of the object.
+	applyChartOverride(obj, r.ClusterAttributes)
+	chartTemplateErr := intreconcile.RenderChartTemplate(obj)
+
+	// Apply the defaults of the ControllerConfig to the object, for the
+	// same reason before initializing the patch helper.
+	controllerConfig := r.controllerConfig.Load()
+	applyControllerConfigDefaults(obj, controllerConfig)
+
 	// Initialize the patch helper with the current version of the object.
 	patchHelper := patch.NewSerialPatcher(obj, r.Client)
 
@@ -190,9 +447,13 @@ func (r *HelmReleaseReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		// However, not returning an error will cause the patch helper to
 		// patch the observed generation, which we do not want. So we ignore
 		// these errors here after patching.
-		retErr = interrors.Ignore(retErr, errWaitForDependency, errWaitForChart)
+		retErr = interrors.Ignore(retErr, errWaitForDependency, errWaitForChart, errReleaseConflict)
 
-		if err := patchHelper.Patch(ctx, obj, patchOpts...); err != nil {
+		inflight.SetPhase(ctx, "status-update")
+		patchCtx, patchSpan := tracing.StartSpan(ctx, "status-update")
+		err := intreconcile.PatchWithRetry(patchCtx, patchHelper, obj, patchOpts...)
+		tracing.EndSpan(patchSpan, err)
+		if err != nil {
 			if !obj.DeletionTimestamp.IsZero() {
 				err = apierrutil.FilterOut(err, func(e error) bool { return apierrors.IsNotFound(e) })
 			}
@@ -213,6 +474,7 @@ func (r *HelmReleaseReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Examine if the object is under deletion.
 	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		inflight.SetPhase(ctx, "delete")
 		return r.reconcileDelete(ctx, obj)
 	}
 
@@ -225,21 +487,148 @@ func (r *HelmReleaseReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Hold off the reconciliation of the object if its namespace is not
+	// allowed by the ControllerConfig. It is reconciled again at its
+	// interval, to pick up any change of the ControllerConfig.
+	if controllerConfig != nil && !controllerConfig.AllowsNamespace(obj.Namespace) {
+		msg := fmt.Sprintf("Reconciliation of HelmReleases in namespace '%s' is not allowed by the ControllerConfig", obj.Namespace)
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.NamespaceNotAllowedReason, "%s", msg)
+		conditions.Delete(obj, meta.ReconcilingCondition)
+		log.Info(msg)
+		return jitter.JitteredRequeueInterval(ctrl.Result{RequeueAfter: r.requeueAfter(obj)}), nil
+	}
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.NamespaceNotAllowedReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
+
+	// Stall if the chart name or version template of the object can not be
+	// rendered.
+	if chartTemplateErr != nil {
+		conditions.MarkStalled(obj, v2.ChartTemplateFailedReason, "%s", chartTemplateErr)
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.ChartTemplateFailedReason, "%s", chartTemplateErr)
+		conditions.Delete(obj, meta.ReconcilingCondition)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.ChartTemplateFailedReason, chartTemplateErr.Error())
+
+		// Recovering from this is not possible without a change of spec,
+		// labels or annotations, all triggering a new reconciliation.
+		return ctrl.Result{}, reconcile.TerminalError(chartTemplateErr)
+	}
+	// Remove any stale corresponding Stalled and Ready=False conditions.
+	if conditions.HasAnyReason(obj, meta.StalledCondition, v2.ChartTemplateFailedReason) {
+		conditions.Delete(obj, meta.StalledCondition)
+	}
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.ChartTemplateFailedReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
+
+	// Render the release in dry-run mode if requested. This is done before
+	// checking if the object is suspended, to allow changes to be previewed
+	// before resuming the object.
+	if v2.ShouldHandleDryRunRequest(obj) {
+		r.reconcileDryRun(ctx, obj)
+	}
+
+	// Collect a support bundle if requested, which is also done when the
+	// object is suspended.
+	if v2.ShouldHandleSupportBundleRequest(obj) {
+		r.reconcileSupportBundle(ctx, obj)
+	}
+
+	// Write the effective configuration if requested, which is also done
+	// when the object is suspended.
+	if v2.ShouldHandleEffectiveConfigRequest(obj) {
+		r.reconcileEffectiveConfig(ctx, obj)
+	}
+
 	// Return early if the object is suspended.
-	if obj.Spec.Suspend {
+	if obj.IsSuspended() {
 		log.Info("reconciliation is suspended for this object")
+		r.reconcileSuspended(obj)
+		return ctrl.Result{}, nil
+	}
+
+	// Hold off the release of changes accumulated during a long suspension
+	// until the pending changes have been reported, and confirmed.
+	if !r.reconcileResume(ctx, obj) {
 		return ctrl.Result{}, nil
 	}
+	conditions.Delete(obj, v2.SuspendedCondition)
+
+	// Stall if the object is stamped for a preview environment with an
+	// identifier which can not be used as a suffix of the release name and
+	// target namespace.
+	if id := obj.GetPreviewID(); id != "" {
+		if errs := validation.IsDNS1123Label(id); len(errs) > 0 {
+			err := fmt.Errorf("invalid preview identifier '%s' in '%s' annotation: %s",
+				id, v2.PreviewAnnotation, strings.Join(errs, ", "))
+			conditions.MarkStalled(obj, v2.InvalidPreviewReason, "%s", err)
+			conditions.MarkFalse(obj, meta.ReadyCondition, v2.InvalidPreviewReason, "%s", err)
+			conditions.Delete(obj, meta.ReconcilingCondition)
+			r.Eventf(obj, corev1.EventTypeWarning, v2.InvalidPreviewReason, err.Error())
+
+			// Recovering from this is not possible without a change of
+			// annotations, triggering a new reconciliation.
+			return ctrl.Result{}, reconcile.TerminalError(err)
+		}
+	}
+
+	// Uninstall the Helm release of the object if it is stamped for a
+	// preview environment which has outlived its TTL. The object itself is
+	// kept, so it is not recreated with a new TTL by a GitOps tool applying
+	// it again.
+	expiry, isPreview := obj.GetPreviewExpiry()
+	if isPreview && release.IsNewer(time.Now(), expiry, r.clockSkewTolerance) {
+		return ctrl.Result{}, r.reconcilePreviewExpiry(ctx, obj, expiry)
+	}
+	// Remove any stale corresponding Stalled and Ready=False conditions,
+	// e.g. after the TTL was extended.
+	for _, reason := range []string{v2.InvalidPreviewReason, v2.PreviewExpiredReason} {
+		if conditions.HasAnyReason(obj, meta.StalledCondition, reason) {
+			conditions.Delete(obj, meta.StalledCondition)
+		}
+		if conditions.HasAnyReason(obj, meta.ReadyCondition, reason) {
+			conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+		}
+	}
+
+	// Stall if the API of the source kind the object refers to is not
+	// installed in the cluster.
+	if kind := requiredSourceKind(obj); !r.sourceAPIAvailable(kind) {
+		err := fmt.Errorf("the %s API is not installed in the cluster: install the source-controller CRDs and restart the controller", kind)
+		conditions.MarkStalled(obj, v2.SourceAPIUnavailableReason, "%s", err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.SourceAPIUnavailableReason, "%s", err)
+		conditions.Delete(obj, meta.ReconcilingCondition)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.SourceAPIUnavailableReason, err.Error())
+
+		// Recovering from this is not possible without a restart of the
+		// controller, or a change of spec to another source kind.
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	}
+	// Remove any stale corresponding Stalled and Ready=False conditions.
+	if conditions.HasAnyReason(obj, meta.StalledCondition, v2.SourceAPIUnavailableReason) {
+		conditions.Delete(obj, meta.StalledCondition)
+	}
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.SourceAPIUnavailableReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
 
 	// Reconcile the HelmChart template.
 	if err := r.reconcileChartTemplate(ctx, obj); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return r.reconcileRelease(ctx, patchHelper, obj)
+	result, err = r.reconcileRelease(ctx, patchHelper, obj)
+
+	// Ensure the object is reconciled again when the preview expires.
+	if isPreview && err == nil && !result.Requeue {
+		if until := release.Until(expiry, time.Now(), r.clockSkewTolerance); result.RequeueAfter == 0 || until < result.RequeueAfter {
+			result.RequeueAfter = until
+		}
+	}
+	return result, err
 }
 
-func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelper *patch.SerialPatcher, obj *v2.HelmRelease) (ctrl.Result, error) {
+func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelper *patch.SerialPatcher, obj *v2.HelmRelease) (_ ctrl.Result, retErr error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Mark the resource as under reconciliation.
@@ -248,10 +637,46 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 		return ctrl.Result{}, err
 	}
 
+	// Refuse to manage a Helm release which is managed by another HelmRelease.
+	owner, err := r.findReleaseNameConflict(ctx, obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if owner != nil {
+		msg := fmt.Sprintf("Helm release '%s/%s' is managed by HelmRelease '%s': retrying in %s",
+			obj.GetStorageNamespace(), obj.GetReleaseName(), client.ObjectKeyFromObject(owner), r.requeueDependency.String())
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.ReleaseNameConflictReason, "%s", msg)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.ReleaseNameConflictReason, msg)
+		log.Info(msg)
+		return ctrl.Result{RequeueAfter: r.requeueDependency}, errReleaseConflict
+	}
+	// Remove any stale corresponding Ready=False condition with Unknown.
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.ReleaseNameConflictReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
+
 	// Confirm dependencies are Ready before proceeding.
 	if c := len(obj.Spec.DependsOn); c > 0 {
 		log.Info(fmt.Sprintf("checking %d dependencies", c))
 
+		cycle, err := r.findDependencyCycle(ctx, obj)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, v2.DependencyNotReadyReason, "%s", err)
+			return ctrl.Result{}, err
+		}
+		if cycle != nil {
+			msg := fmt.Sprintf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+			conditions.MarkStalled(obj, v2.DependencyCycleReason, "%s", msg)
+			conditions.MarkFalse(obj, meta.ReadyCondition, v2.DependencyCycleReason, "%s", msg)
+			conditions.Delete(obj, meta.ReconcilingCondition)
+			r.Eventf(obj, corev1.EventTypeWarning, v2.DependencyCycleReason, msg)
+
+			// Recovering from this is not possible without a change of spec
+			// of one of the HelmReleases in the cycle, after which the
+			// dependencies becoming Ready trigger a new reconciliation.
+			return ctrl.Result{}, reconcile.TerminalError(errors.New(msg))
+		}
+
 		if err := r.checkDependencies(ctx, obj); err != nil {
 			msg := fmt.Sprintf("dependencies do not meet ready condition (%s): retrying in %s",
 				err.Error(), r.requeueDependency.String())
@@ -266,13 +691,28 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 
 		log.Info("all dependencies are ready")
 	}
-	// Remove any stale corresponding Ready=False condition with Unknown.
-	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.DependencyNotReadyReason) {
+
+	// Confirm the HelmReleases of the preceding phases of any
+	// HelmReleaseGroup the object is part of are Ready.
+	if err := r.checkGroupBarriers(ctx, obj); err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.DependencyNotReadyReason, "%s", err)
+		r.Eventf(obj, corev1.EventTypeNormal, v2.DependencyNotReadyReason, err.Error())
+		log.Info(fmt.Sprintf("%s: retrying in %s", err.Error(), r.requeueDependency.String()))
+		return ctrl.Result{RequeueAfter: r.requeueDependency}, errWaitForDependency
+	}
+	// Remove any stale corresponding Stalled and Ready=False conditions.
+	if conditions.HasAnyReason(obj, meta.StalledCondition, v2.DependencyCycleReason) {
+		conditions.Delete(obj, meta.StalledCondition)
+	}
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.DependencyNotReadyReason, v2.DependencyCycleReason) {
 		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
 	}
 
 	// Get the source object containing the HelmChart.
-	source, err := r.getSource(ctx, obj)
+	inflight.SetPhase(ctx, "resolve-chart")
+	sourceCtx, sourceSpan := tracing.StartSpan(ctx, "resolve-chart")
+	source, err := r.getSource(sourceCtx, obj)
+	tracing.EndSpan(sourceSpan, err)
 	if err != nil {
 		if acl.IsAccessDenied(err) {
 			conditions.MarkStalled(obj, aclv1.AccessDeniedReason, "%s", err)
@@ -295,13 +735,19 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
 	}
 
+	// Surface a failed verification of the chart by the source.
+	r.reconcileSourceVerified(ctx, obj, source)
+
+	// Surface a decreased chart version or a stale source.
+	r.reconcileSourceDrift(ctx, obj, source)
+
 	// Check if the source is ready.
 	if ready, msg := isSourceReady(source); !ready {
 		log.Info(msg)
 		conditions.MarkFalse(obj, meta.ReadyCondition, "SourceNotReady", "%s", msg)
 		// Do not requeue immediately, when the artifact is created
 		// the watcher should trigger a reconciliation.
-		return jitter.JitteredRequeueInterval(ctrl.Result{RequeueAfter: obj.GetRequeueAfter()}), errWaitForChart
+		return jitter.JitteredRequeueInterval(ctrl.Result{RequeueAfter: r.requeueAfter(obj)}), errWaitForChart
 	}
 	// Remove any stale corresponding Ready=False condition with Unknown.
 	if conditions.HasAnyReason(obj, meta.ReadyCondition, "SourceNotReady") {
@@ -309,12 +755,7 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 	}
 
 	// Compose values based from the spec and references.
-	values, err := chartutil.ChartValuesFromReferences(ctx,
-		log,
-		r.Client,
-		obj.Namespace,
-		obj.GetValues(),
-		obj.Spec.ValuesFrom...)
+	values, err := r.composeValues(ctx, obj)
 	if err != nil {
 		conditions.MarkFalse(obj, meta.ReadyCondition, "ValuesError", "%s", err)
 		r.Eventf(obj, corev1.EventTypeWarning, "ValuesError", err.Error())
@@ -325,8 +766,50 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
 	}
 
+	// Compose the environment of the hooks of the Helm actions.
+	installEnv, upgradeEnv, err := r.composeHookEnv(ctx, obj)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.HookEnvErrorReason, "%s", err)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.HookEnvErrorReason, err.Error())
+		return ctrl.Result{}, err
+	}
+	// Remove any stale corresponding Ready=False condition with Unknown.
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.HookEnvErrorReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
+
+	// Skip the release if nothing changed since the last successful
+	// reconciliation.
+	if action.ReleaseUpToDate(obj, source.GetArtifact().Revision, values) {
+		log.V(logger.DebugLevel).Info("no changes since last successful reconciliation: skipping release")
+		conditions.Delete(obj, meta.ReconcilingCondition)
+		return jitter.JitteredRequeueInterval(ctrl.Result{RequeueAfter: r.requeueAfter(obj)}), nil
+	}
+
 	// Load chart from artifact.
-	loadedChart, err := loader.SecureLoadChartFromURL(loader.NewRetryableHTTPClient(ctx, r.artifactFetchRetries), source.GetArtifact().URL, source.GetArtifact().Digest)
+	httpClient, err := r.buildArtifactHTTPClient(ctx, obj)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.ArtifactFailedReason, "%s", err)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.ArtifactFailedReason, err.Error())
+		return ctrl.Result{}, err
+	}
+	var (
+		artifact []byte
+		cfg      *action.ConfigFactory
+	)
+	loadOpts := r.artifactLoadOptions(ctx, obj, source)
+	if r.failedArtifacts != nil {
+		// Retain the artifact of a failed reconciliation for postmortem
+		// debugging, or clean it up after a successful one.
+		defer func() {
+			r.retainFailedArtifact(ctx, obj, cfg, artifact, values, retErr)
+		}()
+		loadOpts = append(loadOpts, loader.WithArtifactHandler(func(b []byte) {
+			artifact = b
+		}))
+	}
+	loadedChart, err := loader.SecureLoadChartFromURL(ctx, loader.NewRetryableHTTPClient(ctx, r.artifactFetchRetries, httpClient), source.GetArtifact().URL, source.GetArtifact().Digest,
+		loadOpts...)
 	if err != nil {
 		if errors.Is(err, loader.ErrFileNotFound) {
 			msg := fmt.Sprintf("Source not ready: artifact not found. Retrying in %s", r.requeueDependency.String())
@@ -335,12 +818,77 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 			return ctrl.Result{RequeueAfter: r.requeueDependency}, errWaitForDependency
 		}
 
+		if errors.Is(err, loader.ErrIntegrity) {
+			msg := fmt.Sprintf("Artifact verification failed for revision '%s': %s", source.GetArtifact().Revision, err)
+			conditions.MarkFalse(obj, meta.ReadyCondition, v2.ArtifactFailedReason, "%s", msg)
+			r.Eventf(obj, corev1.EventTypeWarning, v2.ArtifactFailedReason, msg)
+			return ctrl.Result{}, err
+		}
+
+		if errors.Is(err, loader.ErrTooLarge) {
+			msg := fmt.Sprintf("Chart of revision '%s' is too large: %s", source.GetArtifact().Revision, err)
+			conditions.MarkStalled(obj, v2.ChartTooLargeReason, "%s", msg)
+			conditions.MarkFalse(obj, meta.ReadyCondition, v2.ChartTooLargeReason, "%s", msg)
+			conditions.Delete(obj, meta.ReconcilingCondition)
+			r.Eventf(obj, corev1.EventTypeWarning, v2.ChartTooLargeReason, msg)
+
+			// Downloading the same revision again is bound to fail, and
+			// wastes resources of the controller.
+			return ctrl.Result{}, reconcile.TerminalError(err)
+		}
+
 		conditions.MarkFalse(obj, meta.ReadyCondition, v2.ArtifactFailedReason, "Could not load chart: %s", err)
 		r.Eventf(obj, corev1.EventTypeWarning, v2.ArtifactFailedReason, err.Error())
 		return ctrl.Result{}, err
 	}
+	// Remove any stale corresponding Stalled and Ready=False conditions.
+	if conditions.HasAnyReason(obj, meta.StalledCondition, v2.ChartTooLargeReason) {
+		conditions.Delete(obj, meta.StalledCondition)
+	}
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.ArtifactFailedReason, v2.ChartTooLargeReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
+
+	// Check if the chart can be released with the Helm SDK of the controller,
+	// before running any Helm action on it. Dependencies missing from the
+	// chart are only reported, as they may be disabled by the values.
+	compatWarnings, err := loader.CheckCompatibility(loadedChart)
+	if len(compatWarnings) > 0 {
+		msg := fmt.Sprintf("Chart of revision '%s' may fail to release: %s", source.GetArtifact().Revision, strings.Join(compatWarnings, "; "))
+		log.Info(msg)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.IncompatibleChartReason, msg)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Chart of revision '%s' is incompatible: %s", source.GetArtifact().Revision, err)
+		conditions.MarkStalled(obj, v2.IncompatibleChartReason, "%s", msg)
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.IncompatibleChartReason, "%s", msg)
+		conditions.Delete(obj, meta.ReconcilingCondition)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.IncompatibleChartReason, msg)
+
+		// Recovering from this is not possible without a new revision of
+		// the chart or a change of spec, both triggering a new
+		// reconciliation.
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	}
+	// Remove any stale corresponding Stalled and Ready=False conditions.
+	if conditions.HasAnyReason(obj, meta.StalledCondition, v2.IncompatibleChartReason) {
+		conditions.Delete(obj, meta.StalledCondition)
+	}
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.IncompatibleChartReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
+
+	// Validate the values against the JSON schema of the chart, to report
+	// any violations before running a Helm action with them.
+	if err := action.ValidateValuesSchema(obj, loadedChart, helmchartutil.Values(values)); err != nil {
+		msg := fmt.Sprintf("Values do not validate against the schema of chart '%s': %s",
+			loadedChart.Name(), strings.TrimSpace(err.Error()))
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.ValuesValidationFailedReason, "%s", msg)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.ValuesValidationFailedReason, msg)
+		return ctrl.Result{}, err
+	}
 	// Remove any stale corresponding Ready=False condition with Unknown.
-	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.ArtifactFailedReason) {
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.ValuesValidationFailedReason) {
 		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
 	}
 
@@ -350,8 +898,10 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 		return ctrl.Result{}, err
 	}
 
-	// Build the REST client getter.
-	getter, err := r.buildRESTClientGetter(ctx, obj)
+	// Build the REST client getter, recording any warnings returned by the
+	// Kubernetes API server.
+	warnings := kube.NewWarningRecorder()
+	getter, err := r.buildRESTClientGetter(ctx, obj, kube.WithWarningHandler(warnings))
 	if err != nil {
 		conditions.MarkFalse(obj, meta.ReadyCondition, "RESTClientError", "%s", err)
 		return ctrl.Result{}, err
@@ -361,9 +911,74 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
 	}
 
+	// Confirm the resources to wait for are ready before proceeding.
+	if obj.Spec.Wait != nil && len(obj.Spec.Wait.For) > 0 {
+		log.Info(fmt.Sprintf("checking %d resources to wait for", len(obj.Spec.Wait.For)))
+
+		if err := r.checkWaitFor(ctx, getter, obj); err != nil {
+			// Recovering from a denied reference is not possible without a
+			// change of spec or a restart of the controller.
+			if errors.Is(err, reconcile.TerminalError(nil)) {
+				cause := errors.Unwrap(err)
+				conditions.MarkStalled(obj, v2.DependencyNotReadyReason, "%s", cause)
+				conditions.MarkFalse(obj, meta.ReadyCondition, v2.DependencyNotReadyReason, "%s", cause)
+				r.Eventf(obj, corev1.EventTypeWarning, v2.DependencyNotReadyReason, cause.Error())
+				return ctrl.Result{}, err
+			}
+
+			msg := fmt.Sprintf("resources do not meet ready condition (%s): retrying in %s",
+				err.Error(), r.requeueDependency.String())
+			conditions.MarkFalse(obj, meta.ReadyCondition, v2.DependencyNotReadyReason, "%s", err)
+			r.Eventf(obj, corev1.EventTypeNormal, v2.DependencyNotReadyReason, err.Error())
+			log.Info(msg)
+
+			return ctrl.Result{RequeueAfter: r.requeueDependency}, errWaitForDependency
+		}
+
+		log.Info("all resources to wait for are ready")
+	}
+	// Remove any stale corresponding Stalled and Ready=False conditions.
+	if conditions.HasAnyReason(obj, meta.StalledCondition, v2.DependencyNotReadyReason) {
+		conditions.Delete(obj, meta.StalledCondition)
+	}
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.DependencyNotReadyReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
+
+	// Confirm the nodes of the target cluster provide the capabilities
+	// required by the release, instead of deploying pods which can never be
+	// scheduled.
+	if req := obj.GetNodeRequirements(); !req.IsEmpty() {
+		if err := r.checkNodeRequirements(ctx, getter, obj, req); err != nil {
+			// Recovering from an invalid requirement or a lack of
+			// permissions is not possible without a change of spec or of
+			// the permissions of the controller.
+			if errors.Is(err, reconcile.TerminalError(nil)) {
+				cause := errors.Unwrap(err)
+				conditions.MarkStalled(obj, v2.NodeRequirementsNotMetReason, "%s", cause)
+				conditions.MarkFalse(obj, meta.ReadyCondition, v2.NodeRequirementsNotMetReason, "%s", cause)
+				r.Eventf(obj, corev1.EventTypeWarning, v2.NodeRequirementsNotMetReason, cause.Error())
+				return ctrl.Result{}, err
+			}
+
+			conditions.MarkFalse(obj, meta.ReadyCondition, v2.NodeRequirementsNotMetReason, "%s", err)
+			r.Eventf(obj, corev1.EventTypeWarning, v2.NodeRequirementsNotMetReason, err.Error())
+			log.Info(fmt.Sprintf("%s: retrying in %s", err.Error(), r.requeueDependency.String()))
+
+			return ctrl.Result{RequeueAfter: r.requeueDependency}, errWaitForDependency
+		}
+	}
+	// Remove any stale corresponding Stalled and Ready=False conditions.
+	if conditions.HasAnyReason(obj, meta.StalledCondition, v2.NodeRequirementsNotMetReason) {
+		conditions.Delete(obj, meta.StalledCondition)
+	}
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.NodeRequirementsNotMetReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
+
 	// Keep feature flagged code paths separate from the main reconciliation
 	// logic to ensure easy removal when the feature flag is removed.
-	if ok, _ := features.Enabled(features.AdoptLegacyReleases); ok {
+	if ok, _ := features.EnabledFor(obj, features.AdoptLegacyReleases); ok {
 		// Attempt to adopt "legacy" v2beta1 release state on a best-effort basis.
 		// If this fails, the controller will fall back to performing an upgrade
 		// to settle on the desired state.
@@ -374,22 +989,31 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 		r.adoptPostRenderersStatus(obj)
 	}
 
+	// If the storage driver has changed, e.g. because the default storage
+	// driver of the controller was changed, move the release to the new
+	// storage instead of uninstalling it.
+	if err := r.reconcileStorageDriver(ctx, getter, obj); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// If the release target configuration has changed, we need to uninstall the
 	// previous release target first. If we did not do this, the installation would
 	// fail due to resources already existing.
 	if reason, changed := action.ReleaseTargetChanged(obj, loadedChart.Name()); changed {
 		log.Info(fmt.Sprintf("release target configuration changed (%s): running uninstall for current release", reason))
-		if err = r.reconcileUninstall(ctx, getter, obj); err != nil && !errors.Is(err, intreconcile.ErrNoLatest) {
+		if err = r.reconcileUninstall(ctx, getter, obj); err != nil && !errors.Is(err, intreconcile.ErrNoLatest) && !isOwnershipError(err) {
 			return ctrl.Result{}, err
 		}
 		obj.Status.ClearHistory()
 		obj.Status.ClearFailures()
 		obj.Status.StorageNamespace = ""
+		obj.Status.StorageDriver = ""
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// Set current storage namespace.
+	// Set current storage namespace and driver.
 	obj.Status.StorageNamespace = obj.GetStorageNamespace()
+	obj.Status.StorageDriver = action.StorageDriver(obj)
 
 	// Reset the failure count if the chart or values have changed.
 	if reason, ok := action.MustResetFailures(obj, loadedChart.Metadata, values); ok {
@@ -401,15 +1025,17 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 	obj.Status.LastAttemptedGeneration = obj.Generation
 	obj.Status.LastAttemptedRevision = loadedChart.Metadata.Version
 	obj.Status.LastAttemptedRevisionDigest = ociDigest
-	obj.Status.LastAttemptedConfigDigest = chartutil.DigestValues(digest.Canonical, values).String()
+	obj.Status.LastAttemptedConfigDigest = intvalues.Digest(digest.Canonical, values).String()
 	obj.Status.LastAttemptedValuesChecksum = ""
 	obj.Status.LastReleaseRevision = 0
 
 	// Construct config factory for any further Helm actions.
-	cfg, err := action.NewConfigFactory(getter,
-		action.WithStorage(action.DefaultStorageDriver, obj.Status.StorageNamespace),
-		action.WithStorageLog(action.NewDebugLog(ctrl.LoggerFrom(ctx).V(logger.TraceLevel))),
-	)
+	storageOpts, err := r.buildStorageOptions(ctx, obj, obj.Status.StorageDriver, obj.Status.StorageNamespace)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, "FactoryError", "%s", err)
+		return ctrl.Result{}, err
+	}
+	cfg, err = action.NewConfigFactory(getter, storageOpts...)
 	if err != nil {
 		conditions.MarkFalse(obj, meta.ReadyCondition, "FactoryError", "%s", err)
 		return ctrl.Result{}, err
@@ -419,21 +1045,161 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
 	}
 
+	// Refuse to modify a Helm release which is owned by another HelmRelease.
+	if err := cfg.VerifyStorageOwner(ctx, obj.GetReleaseName()); err != nil {
+		if !isOwnershipError(err) {
+			conditions.MarkFalse(obj, meta.ReadyCondition, "FactoryError", "%s", err)
+			return ctrl.Result{}, err
+		}
+		msg := fmt.Sprintf("Helm release '%s/%s' is not owned by this HelmRelease: %s: retrying in %s",
+			obj.GetStorageNamespace(), obj.GetReleaseName(), err, r.requeueDependency.String())
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.ReleaseNotOwnedReason, "%s", msg)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.ReleaseNotOwnedReason, msg)
+		log.Info(msg)
+		return ctrl.Result{RequeueAfter: r.requeueDependency}, errReleaseConflict
+	}
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.ReleaseNotOwnedReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
+
 	// Off we go!
-	if err = intreconcile.NewAtomicRelease(patchHelper, cfg, r.EventRecorder, r.FieldManager).Reconcile(ctx, &intreconcile.Request{
-		Object: obj,
-		Chart:  loadedChart,
-		Values: helmchartutil.Values(values),
-	}); err != nil {
+	prevVersion := latestReleaseVersion(obj)
+	req := &intreconcile.Request{
+		Object:     obj,
+		Chart:      loadedChart,
+		Values:     helmchartutil.Values(values),
+		InstallEnv: installEnv,
+		UpgradeEnv: upgradeEnv,
+	}
+	err = intreconcile.NewAtomicRelease(patchHelper, cfg, r.EventRecorder, r.FieldManager).Reconcile(ctx, req)
+	r.reconcileDeprecationWarnings(obj, warnings.Deprecations(), latestReleaseVersion(obj) != prevVersion)
+	r.reconcileSecurityScanWarnings(obj, latestReleaseVersion(obj) != prevVersion)
+	if err != nil {
+		if errors.Is(err, intreconcile.ErrMustRequeue) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		if interrors.IsOneOf(err, intreconcile.ErrExceededMaxRetries, intreconcile.ErrMissingRollbackTarget,
+			intreconcile.ErrRemediationExhausted) {
+			err = reconcile.TerminalError(err)
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Record the resources of the latest release.
+	if err := r.reconcileInventory(cfg, obj, latestReleaseVersion(obj) != prevVersion); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Assess the health of the resources of the release.
+	inflight.SetPhase(ctx, "health-check")
+	if err := r.reconcileHealth(ctx, patchHelper, cfg, obj, latestReleaseVersion(obj) != prevVersion); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Run the post-deploy checks against the endpoints of the release.
+	inflight.SetPhase(ctx, "post-deploy-checks")
+	retryChecks, err := r.reconcilePostDeployChecks(ctx, obj, latestReleaseVersion(obj) != prevVersion)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if retryChecks > 0 {
+		return ctrl.Result{RequeueAfter: retryChecks}, nil
+	}
+
+	// Keep monitoring the health of the resources of an upgrade within
+	// its verification window.
+	inflight.SetPhase(ctx, "verification-window")
+	remaining, err := r.reconcileVerificationWindow(ctx, patchHelper, cfg, req)
+	if err != nil {
 		if errors.Is(err, intreconcile.ErrMustRequeue) {
 			return ctrl.Result{Requeue: true}, nil
 		}
-		if interrors.IsOneOf(err, intreconcile.ErrExceededMaxRetries, intreconcile.ErrMissingRollbackTarget) {
+		if interrors.IsOneOf(err, intreconcile.ErrExceededMaxRetries, intreconcile.ErrMissingRollbackTarget,
+			intreconcile.ErrRemediationExhausted) {
 			err = reconcile.TerminalError(err)
 		}
 		return ctrl.Result{}, err
 	}
-	return jitter.JitteredRequeueInterval(ctrl.Result{RequeueAfter: obj.GetRequeueAfter()}), nil
+
+	// Record the source artifact of the successful reconciliation.
+	if conditions.IsReady(obj) {
+		artifact := source.GetArtifact()
+		obj.Status.ObservedSourceArtifactRevision = artifact.Revision
+		obj.Status.SourceArtifact = &v2.SourceArtifact{
+			URL:            artifact.URL,
+			Revision:       artifact.Revision,
+			Digest:         artifact.Digest,
+			LastUpdateTime: artifact.LastUpdateTime,
+		}
+	}
+	if remaining > 0 && remaining < r.requeueAfter(obj) {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	return jitter.JitteredRequeueInterval(ctrl.Result{RequeueAfter: r.requeueAfter(obj)}), nil
+}
+
+// reconcileSuspended marks the v2.SuspendedCondition and emits an event when
+// the given v2.HelmRelease has become suspended. Other conditions are left
+// untouched, to retain the status of the last reconciliation.
+func (r *HelmReleaseReconciler) reconcileSuspended(obj *v2.HelmRelease) {
+	if conditions.IsTrue(obj, v2.SuspendedCondition) {
+		return
+	}
+	conditions.MarkTrue(obj, v2.SuspendedCondition, meta.SuspendedReason, "Reconciliation is suspended")
+	r.Eventf(obj, corev1.EventTypeNormal, meta.SuspendedReason, "Reconciliation is suspended")
+}
+
+// reconcileResume performs the verification pass of a v2.HelmRelease which
+// is resumed after having been suspended for longer than the threshold of
+// its ResumeVerification. The pending changes are rendered with a dry-run,
+// and reported in an event. Unless the ResumeVerification allows to proceed
+// automatically, the v2.ResumePendingCondition is marked, and the release is
+// held off until confirmed using the v2.ConfirmResumeRequestAnnotation.
+//
+// It returns true if the reconciliation of the release may proceed.
+func (r *HelmReleaseReconciler) reconcileResume(ctx context.Context, obj *v2.HelmRelease) bool {
+	// Always handle the request, to prevent a request made before the
+	// changes were reported from confirming them.
+	confirmed := v2.ShouldHandleConfirmResumeRequest(obj)
+
+	if conditions.IsTrue(obj, v2.ResumePendingCondition) {
+		if !confirmed && obj.Spec.ResumeVerification != nil && !obj.Spec.ResumeVerification.AutoProceed {
+			ctrl.LoggerFrom(ctx).Info("release of pending changes awaits confirmation")
+			return false
+		}
+		conditions.Delete(obj, v2.ResumePendingCondition)
+		r.Eventf(obj, corev1.EventTypeNormal, v2.ResumeConfirmedReason, "Release of pending changes confirmed")
+		return true
+	}
+
+	verification := obj.Spec.ResumeVerification
+	if verification == nil || !conditions.IsTrue(obj, v2.SuspendedCondition) {
+		return true
+	}
+	suspendedFor := release.Elapsed(conditions.Get(obj, v2.SuspendedCondition).LastTransitionTime.Time, time.Now(), r.clockSkewTolerance).Round(time.Second)
+	if suspendedFor < verification.Threshold.Duration {
+		return true
+	}
+	conditions.Delete(obj, v2.SuspendedCondition)
+
+	msg := fmt.Sprintf("Resumed after a suspension of %s", suspendedFor)
+	if cm, err := r.dryRun(ctx, obj); err != nil {
+		msg = fmt.Sprintf("%s: failed to render pending changes: %s", msg, err)
+	} else {
+		msg = fmt.Sprintf("%s: pending changes written to ConfigMap '%s/%s'", msg, cm.Namespace, cm.Name)
+	}
+
+	if verification.AutoProceed {
+		ctrl.LoggerFrom(ctx).Info(msg)
+		r.Eventf(obj, corev1.EventTypeNormal, v2.ResumeConfirmedReason, msg)
+		return true
+	}
+
+	msg = fmt.Sprintf("%s, awaiting confirmation using the '%s' annotation", msg, v2.ConfirmResumeRequestAnnotation)
+	ctrl.LoggerFrom(ctx).Info(msg)
+	conditions.MarkTrue(obj, v2.ResumePendingCondition, v2.AwaitingConfirmationReason, "%s", msg)
+	r.Eventf(obj, corev1.EventTypeWarning, v2.AwaitingConfirmationReason, msg)
+	return false
 }
 
 // reconcileDelete deletes the v1beta2.HelmChart of the v2.HelmRelease,
@@ -441,7 +1207,7 @@ func (r *HelmReleaseReconciler) reconcileRelease(ctx context.Context, patchHelpe
 func (r *HelmReleaseReconciler) reconcileDelete(ctx context.Context, obj *v2.HelmRelease) (ctrl.Result, error) {
 	// Only uninstall the release and delete the HelmChart resource if the
 	// resource is not suspended.
-	if !obj.Spec.Suspend {
+	if !obj.IsSuspended() {
 		if err := r.reconcileReleaseDeletion(ctx, obj); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -452,6 +1218,14 @@ func (r *HelmReleaseReconciler) reconcileDelete(ctx context.Context, obj *v2.Hel
 	}
 
 	if !obj.DeletionTimestamp.IsZero() {
+		// Remove any retained artifact of a failed reconciliation.
+		if err := r.failedArtifacts.Remove(obj.GetNamespace(), obj.GetName()); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to remove retained artifact")
+		}
+
+		// Release the clients shared with other releases.
+		r.clientCache.Delete(client.ObjectKeyFromObject(obj).String())
+
 		// Remove our finalizer from the list.
 		controllerutil.RemoveFinalizer(obj, v2.HelmReleaseFinalizer)
 
@@ -500,7 +1274,7 @@ func (r *HelmReleaseReconciler) reconcileReleaseDeletion(ctx context.Context, ob
 
 		conditions.MarkFalse(obj, meta.ReadyCondition, v2.UninstallFailedReason,
 			"failed to build REST client getter to uninstall release: %s", err)
-		return err
+		return r.orphanReleaseOnTimeout(ctx, obj, err)
 	}
 
 	// Confirm any ServiceAccount used for impersonation exists before
@@ -540,8 +1314,8 @@ func (r *HelmReleaseReconciler) reconcileReleaseDeletion(ctx context.Context, ob
 	}
 
 	// Attempt to uninstall the release.
-	if err = r.reconcileUninstall(ctx, getter, obj); err != nil && !errors.Is(err, intreconcile.ErrNoLatest) {
-		return err
+	if err = r.reconcileUninstall(ctx, getter, obj); err != nil && !errors.Is(err, intreconcile.ErrNoLatest) && !isOwnershipError(err) {
+		return r.orphanReleaseOnTimeout(ctx, obj, err)
 	}
 	if err == nil {
 		ctrl.LoggerFrom(ctx).Info("uninstalled Helm release for deleted resource")
@@ -550,208 +1324,1701 @@ func (r *HelmReleaseReconciler) reconcileReleaseDeletion(ctx context.Context, ob
 	// Truncate the current release details in the status.
 	obj.Status.ClearHistory()
 	obj.Status.StorageNamespace = ""
+	obj.Status.StorageDriver = ""
 
 	return nil
 }
 
-// reconcileChartTemplate reconciles the HelmChart template from the HelmRelease.
-// Effectively, this means that the HelmChart resource is created, updated or
-// deleted based on the state of the HelmRelease.
-func (r *HelmReleaseReconciler) reconcileChartTemplate(ctx context.Context, obj *v2.HelmRelease) error {
-	return intreconcile.NewHelmChartTemplate(r.Client, r.EventRecorder, r.FieldManager).Reconcile(ctx, &intreconcile.Request{
-		Object: obj,
-	})
-}
-
-func (r *HelmReleaseReconciler) reconcileUninstall(ctx context.Context, getter genericclioptions.RESTClientGetter, obj *v2.HelmRelease) error {
-	// Construct config factory for current release.
+// reconcilePreviewExpiry uninstalls the Helm release of the given
+// v2.HelmRelease stamped for a preview environment which expired at the
+// given time, and marks the object as stalled. The object is not deleted,
+// and no new release is made until it is, or the TTL is extended.
+//
+// Any returned error signals that the release could not be uninstalled, and
+// the reconciliation should be retried.
+func (r *HelmReleaseReconciler) reconcilePreviewExpiry(ctx context.Context, obj *v2.HelmRelease, expiry time.Time) error {
+	if obj.Status.StorageNamespace != "" {
+		getter, err := r.buildRESTClientGetter(ctx, obj)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, v2.UninstallFailedReason,
+				"failed to build REST client getter to uninstall release: %s", err)
+			return err
+		}
+		if err = r.reconcileUninstall(ctx, getter, obj); err != nil && !errors.Is(err, intreconcile.ErrNoLatest) && !isOwnershipError(err) {
+			return err
+		}
+		if err == nil {
+			ctrl.LoggerFrom(ctx).Info("uninstalled Helm release of expired preview")
+		}
+
+		// Truncate the current release details in the status.
+		obj.Status.ClearHistory()
+		obj.Status.StorageNamespace = ""
+		obj.Status.StorageDriver = ""
+	}
+
+	msg := fmt.Sprintf("preview '%s' expired at %s: the Helm release is uninstalled, "+
+		"delete the HelmRelease or extend its TTL", obj.GetPreviewID(), expiry.Format(time.RFC3339))
+	if !conditions.HasAnyReason(obj, meta.StalledCondition, v2.PreviewExpiredReason) {
+		r.Eventf(obj, corev1.EventTypeNormal, v2.PreviewExpiredReason, msg)
+	}
+	conditions.MarkStalled(obj, v2.PreviewExpiredReason, "%s", msg)
+	conditions.MarkFalse(obj, meta.ReadyCondition, v2.PreviewExpiredReason, "%s", msg)
+	conditions.Delete(obj, meta.ReconcilingCondition)
+	return nil
+}
+
+// orphanReleaseOnTimeout orphans the Helm release of a HelmRelease targeting
+// a remote cluster once the uninstall has failed to reach the cluster for
+// longer than the configured orphan timeout, for example because the cluster
+// no longer exists. It returns nil after the release has been orphaned, or
+// the given error otherwise. Errors other than connectivity failures, e.g.
+// the rejection of a request by the API server of the cluster, never cause
+// the release to be orphaned, as the release is then likely to still exist.
+func (r *HelmReleaseReconciler) orphanReleaseOnTimeout(ctx context.Context, obj *v2.HelmRelease, err error) error {
+	timeout := obj.GetUninstall().OrphanTimeout
+	if obj.Spec.KubeConfig == nil || timeout == nil {
+		return err
+	}
+	if release.Elapsed(obj.DeletionTimestamp.Time, time.Now(), r.clockSkewTolerance) < timeout.Duration {
+		return err
+	}
+
+	if !isUnreachableError(err) {
+		msg := fmt.Sprintf("refusing to orphan Helm release after failing to uninstall for more than %s, "+
+			"as the failure is not caused by the cluster being unreachable: %s", timeout.Duration, err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.UninstallFailedReason, "%s", msg)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.UninstallFailedReason, msg)
+		return err
+	}
+
+	msg := fmt.Sprintf("orphaned Helm release after failing to reach the cluster for more than %s: %s", timeout.Duration, err)
+	ctrl.LoggerFrom(ctx).Error(err, "orphaned Helm release for deleted resource")
+	r.Eventf(obj, corev1.EventTypeWarning, v2.OrphanedReason, msg)
+
+	// Truncate the current release details in the status.
+	obj.Status.ClearHistory()
+	obj.Status.StorageNamespace = ""
+	obj.Status.StorageDriver = ""
+
+	return nil
+}
+
+// isUnreachableError returns true if the given error is (or wraps) an error
+// indicating the API server of a cluster could not be reached, e.g. a failed
+// DNS lookup, a refused connection or a timeout.
+func isUnreachableError(err error) bool {
+	var (
+		opErr  *net.OpError
+		dnsErr *net.DNSError
+		netErr net.Error
+	)
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err) ||
+		apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || apierrors.IsServiceUnavailable(err)
+}
+
+// reconcileChartTemplate reconciles the HelmChart template from the HelmRelease.
+// Effectively, this means that the HelmChart resource is created, updated or
+// deleted based on the state of the HelmRelease.
+func (r *HelmReleaseReconciler) reconcileChartTemplate(ctx context.Context, obj *v2.HelmRelease) error {
+	// Without the HelmChart API, there are no HelmCharts to manage or
+	// garbage collect.
+	if !r.sourceAPIAvailable(sourcev1.HelmChartKind) {
+		return nil
+	}
+	return intreconcile.NewHelmChartTemplate(r.Client, r.EventRecorder, r.FieldManager).Reconcile(ctx, &intreconcile.Request{
+		Object: obj,
+	})
+}
+
+func (r *HelmReleaseReconciler) reconcileUninstall(ctx context.Context, getter genericclioptions.RESTClientGetter, obj *v2.HelmRelease) error {
+	// Construct config factory for current release.
+	storageOpts, err := r.buildStorageOptions(ctx, obj, action.ObservedStorageDriver(obj), obj.Status.StorageNamespace)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, "ConfigFactoryErr", "%s", err)
+		return err
+	}
+	cfg, err := action.NewConfigFactory(getter, storageOpts...)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, "ConfigFactoryErr", "%s", err)
+		return err
+	}
+
+	// Skip the uninstall of a release which is owned by another HelmRelease.
+	if err := cfg.VerifyStorageOwner(ctx, obj.GetReleaseName()); err != nil {
+		if isOwnershipError(err) {
+			msg := fmt.Sprintf("skipped uninstall of Helm release '%s/%s': %s",
+				obj.Status.StorageNamespace, obj.GetReleaseName(), err)
+			r.Eventf(obj, corev1.EventTypeWarning, v2.ReleaseNotOwnedReason, msg)
+			ctrl.LoggerFrom(ctx).Info(msg)
+		}
+		return err
+	}
+
+	// Run uninstall.
+	return intreconcile.NewUninstall(cfg, r.EventRecorder).Reconcile(ctx, &intreconcile.Request{Object: obj})
+}
+
+// isOwnershipError returns true if the given error is (or wraps) a
+// storage.OwnershipError.
+func isOwnershipError(err error) bool {
+	var ownershipErr *storage.OwnershipError
+	return errors.As(err, &ownershipErr)
+}
+
+// checkDependencies checks if the dependencies of the given v2.HelmRelease
+// are Ready, or satisfy their readiness expression if specified.
+// It returns an error if a dependency can not be retrieved or is not Ready,
+// otherwise nil.
+func (r *HelmReleaseReconciler) checkDependencies(ctx context.Context, obj *v2.HelmRelease) error {
+	for _, d := range obj.Spec.DependsOn {
+		ref := dependencyKey(obj, d)
+
+		dHr := &v2.HelmRelease{}
+		if err := r.APIReader.Get(ctx, ref, dHr); err != nil {
+			return fmt.Errorf("unable to get '%s' dependency: %w", ref, err)
+		}
+
+		if d.ReadyExpr != "" {
+			dep, err := runtime.DefaultUnstructuredConverter.ToUnstructured(dHr)
+			if err != nil {
+				return fmt.Errorf("unable to convert '%s' dependency: %w", ref, err)
+			}
+			ready, err := evaluateReadyExpr(ctx, obj, dep, d.ReadyExpr)
+			if err != nil {
+				return fmt.Errorf("unable to evaluate readiness of '%s' dependency: %w", ref, err)
+			}
+			if !ready {
+				return fmt.Errorf("dependency '%s' is not ready according to expression '%s'", ref, d.ReadyExpr)
+			}
+		} else if err := checkDependencyReady(ref, dHr); err != nil {
+			return err
+		}
+
+		if d.Version != "" {
+			if err := checkDependencyVersion(dHr, d.Version); err != nil {
+				return fmt.Errorf("dependency '%s' does not meet version constraint: %w", ref, err)
+			}
+		}
+	}
+	return nil
+}
+
+// findDependencyCycle returns the path of namespaced names from the given
+// v2.HelmRelease through its (transitive) dependencies back to itself, if it
+// is part of a dependency cycle. Dependencies which do not exist (yet) are
+// ignored.
+func (r *HelmReleaseReconciler) findDependencyCycle(ctx context.Context, obj *v2.HelmRelease) ([]string, error) {
+	self := client.ObjectKeyFromObject(obj)
+	visited := map[types.NamespacedName]bool{}
+
+	var find func(hr *v2.HelmRelease, path []string) ([]string, error)
+	find = func(hr *v2.HelmRelease, path []string) ([]string, error) {
+		for _, d := range hr.Spec.DependsOn {
+			ref := dependencyKey(hr, d)
+			if ref == self {
+				return append(path, ref.String()), nil
+			}
+			if visited[ref] {
+				continue
+			}
+			visited[ref] = true
+
+			dHr := &v2.HelmRelease{}
+			if err := r.Client.Get(ctx, ref, dHr); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, fmt.Errorf("unable to get '%s' dependency: %w", ref, err)
+			}
+			if cycle, err := find(dHr, append(path, ref.String())); cycle != nil || err != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+	return find(obj, []string{self.String()})
+}
+
+// checkGroupBarriers checks if the HelmReleases of the phases preceding the
+// phase of the given v2.HelmRelease are Ready, for every v2.HelmReleaseGroup
+// in the namespace of the object it is part of. Groups with the
+// v2.BarrierPolicyFirstRelease policy are skipped once the object has been
+// released successfully.
+func (r *HelmReleaseReconciler) checkGroupBarriers(ctx context.Context, obj *v2.HelmRelease) error {
+	groups, err := listHelmReleaseGroups(ctx, r.Client, obj.Namespace)
+	if err != nil {
+		return fmt.Errorf("unable to list HelmReleaseGroups: %w", err)
+	}
+	released := hasBeenReleased(obj)
+	for i := range groups {
+		group := &groups[i]
+		if released && group.GetBarrierPolicy() == v2.BarrierPolicyFirstRelease {
+			continue
+		}
+		phase, err := group.PhaseOf(obj)
+		if err != nil {
+			return fmt.Errorf("invalid HelmReleaseGroup '%s': %w", group.Name, err)
+		}
+		for p := 0; p < phase; p++ {
+			releases, err := listPhaseReleases(ctx, r.Client, group, p)
+			if err != nil {
+				return fmt.Errorf("invalid HelmReleaseGroup '%s': %w", group.Name, err)
+			}
+			for j := range releases {
+				if err := checkDependencyReady(client.ObjectKeyFromObject(&releases[j]), &releases[j]); err != nil {
+					return fmt.Errorf("phase '%s' of HelmReleaseGroup '%s' is waiting for phase '%s': %w",
+						group.Spec.Phases[phase].Name, group.Name, group.Spec.Phases[p].Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hasBeenReleased returns true if the release history of the given
+// v2.HelmRelease contains a release which has been deployed successfully.
+func hasBeenReleased(obj *v2.HelmRelease) bool {
+	for _, snap := range obj.Status.History {
+		switch snap.Status {
+		case helmrelease.StatusDeployed.String(), helmrelease.StatusSuperseded.String():
+			return true
+		}
+	}
+	return false
+}
+
+// dependencyKey returns the namespaced name of the HelmRelease the given
+// object depends on, defaulting to the namespace of the object.
+func dependencyKey(obj *v2.HelmRelease, d v2.DependencyReference) types.NamespacedName {
+	ref := types.NamespacedName{
+		Namespace: d.Namespace,
+		Name:      d.Name,
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = obj.GetNamespace()
+	}
+	return ref
+}
+
+// indexDependsOn returns the namespaced names of the HelmReleases the given
+// HelmRelease depends on, to be used as index values for v2.DependsOnIndexKey.
+func indexDependsOn(o client.Object) []string {
+	obj, ok := o.(*v2.HelmRelease)
+	if !ok {
+		return nil
+	}
+	deps := make([]string, 0, len(obj.Spec.DependsOn))
+	for _, d := range obj.Spec.DependsOn {
+		deps = append(deps, dependencyKey(obj, d).String())
+	}
+	return deps
+}
+
+// indexValuesFrom returns the namespaced names of the HelmReleases the given
+// HelmRelease imports values from, to be used as index values for
+// v2.ValuesFromIndexKey.
+func indexValuesFrom(o client.Object) []string {
+	obj, ok := o.(*v2.HelmRelease)
+	if !ok {
+		return nil
+	}
+	var refs []string
+	for _, ref := range obj.Spec.ValuesFrom {
+		if ref.Kind == v2.HelmReleaseKind {
+			refs = append(refs, types.NamespacedName{Namespace: obj.Namespace, Name: ref.Name}.String())
+		}
+	}
+	return refs
+}
+
+// releaseNameIndexValue returns the identifier of the Helm release targeted
+// by the given HelmRelease, composed of the storage namespace and release
+// name, and prefixed with the KubeConfig Secret for a remote cluster.
+func releaseNameIndexValue(obj *v2.HelmRelease) string {
+	key := types.NamespacedName{Namespace: obj.GetStorageNamespace(), Name: obj.GetReleaseName()}.String()
+	if obj.Spec.KubeConfig != nil {
+		key = obj.GetNamespace() + "/" + obj.Spec.KubeConfig.SecretRef.Name + "/" + key
+	}
+	return key
+}
+
+// indexReleaseName returns the identifier of the Helm release the given
+// HelmRelease targets, to be used as index value for v2.ReleaseNameIndexKey.
+func indexReleaseName(o client.Object) []string {
+	obj, ok := o.(*v2.HelmRelease)
+	if !ok {
+		return nil
+	}
+	return []string{releaseNameIndexValue(obj)}
+}
+
+// findReleaseNameConflict returns the other v2.HelmRelease which manages the
+// Helm release targeted by the given object, or nil if there is none.
+func (r *HelmReleaseReconciler) findReleaseNameConflict(ctx context.Context, obj *v2.HelmRelease) (*v2.HelmRelease, error) {
+	var list v2.HelmReleaseList
+	if err := r.List(ctx, &list, client.MatchingFields{
+		v2.ReleaseNameIndexKey: releaseNameIndexValue(obj),
+	}); err != nil {
+		return nil, fmt.Errorf("unable to list HelmReleases targeting the same Helm release: %w", err)
+	}
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.Namespace == obj.Namespace && other.Name == obj.Name {
+			continue
+		}
+		if managesReleaseBefore(other, obj) {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+// managesReleaseBefore returns true if HelmRelease a takes precedence over
+// HelmRelease b in managing the Helm release they both target. A HelmRelease
+// which has already released takes precedence over one which has not, after
+// which the oldest HelmRelease takes precedence.
+func managesReleaseBefore(a, b *v2.HelmRelease) bool {
+	released := func(obj *v2.HelmRelease) bool {
+		latest := obj.Status.History.Latest()
+		return latest != nil && latest.Name == obj.GetReleaseName() && latest.Namespace == obj.GetReleaseNamespace()
+	}
+	if aReleased, bReleased := released(a), released(b); aReleased != bReleased {
+		return aReleased
+	}
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return client.ObjectKeyFromObject(a).String() < client.ObjectKeyFromObject(b).String()
+}
+
+// checkDependencyReady checks if the given dependency is Ready for its latest
+// generation. A Ready condition observed for a previous generation is not
+// taken into account, as the dependency may still have to act on a change to
+// its spec. In addition, if the dependency has attempted to release a chart
+// version, this version must have been released.
+func checkDependencyReady(ref types.NamespacedName, dHr *v2.HelmRelease) error {
+	if dHr.Generation != dHr.Status.ObservedGeneration {
+		return fmt.Errorf("dependency '%s' is not ready: generation %d has not been observed yet", ref, dHr.Generation)
+	}
+	if !conditions.IsTrue(dHr, meta.ReadyCondition) {
+		return fmt.Errorf("dependency '%s' is not ready", ref)
+	}
+	cur := dHr.Status.History.Latest()
+	if cur != nil && dHr.Status.LastAttemptedRevision != "" && cur.ChartVersion != dHr.Status.LastAttemptedRevision {
+		return fmt.Errorf("dependency '%s' is not ready: chart version '%s' has not been released yet",
+			ref, dHr.Status.LastAttemptedRevision)
+	}
+	return nil
+}
+
+// evaluateReadyExpr evaluates the CEL readiness expression of a dependency,
+// with the unstructured content of the dependency available as `dep` and
+// the given object as `self`.
+func evaluateReadyExpr(ctx context.Context, obj *v2.HelmRelease, dep map[string]any, expr string) (bool, error) {
+	e, err := cel.NewExpression(expr,
+		cel.WithCompile(),
+		cel.WithOutputType(celtypes.BoolType),
+		cel.WithStructVariables("self", "dep"))
+	if err != nil {
+		return false, fmt.Errorf("invalid expression '%s': %w", expr, err)
+	}
+	self, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return false, err
+	}
+	return e.EvaluateBoolean(ctx, map[string]any{"self": self, "dep": dep})
+}
+
+// checkWaitFor checks if the resources the given v2.HelmRelease waits for
+// exist in the cluster of the release, and are ready according to their
+// readiness expression, or to kstatus when no expression is specified.
+// It returns an error if a resource can not be retrieved or is not ready,
+// otherwise nil.
+//
+// As the readiness expressions have access to the data of the resources,
+// e.g. of a Secret, the resources in the cluster the controller runs in are
+// only read while impersonating a service account, and a reference to
+// another namespace than that of the object or the release is subject to
+// the cross-namespace reference restrictions of the controller. Violating
+// either results in a terminal error.
+func (r *HelmReleaseReconciler) checkWaitFor(ctx context.Context, getter genericclioptions.RESTClientGetter, obj *v2.HelmRelease) error {
+	if obj.Spec.KubeConfig == nil && obj.Spec.ServiceAccountName == "" && kube.DefaultServiceAccountName == "" {
+		return reconcile.TerminalError(errors.New("waiting for resources requires a service account to be impersonated: set .spec.serviceAccountName"))
+	}
+	for _, w := range obj.Spec.Wait.For {
+		if w.Namespace == "" || w.Namespace == obj.GetReleaseNamespace() {
+			continue
+		}
+		if err := intacl.AllowsAccessTo(obj, w.Kind, types.NamespacedName{Namespace: w.Namespace, Name: w.Name}); err != nil {
+			return reconcile.TerminalError(err)
+		}
+	}
+
+	c, err := kube.NewClient(getter)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range obj.Spec.Wait.For {
+		res := &unstructured.Unstructured{}
+		res.SetAPIVersion(w.APIVersion)
+		res.SetKind(w.Kind)
+		res.SetName(w.Name)
+		res.SetNamespace(w.Namespace)
+		if err := kube.SetDefaultNamespace(c.RESTMapper(), res, obj.GetReleaseNamespace()); err != nil {
+			return err
+		}
+		ref := fmt.Sprintf("%s/%s", w.Kind, client.ObjectKeyFromObject(res))
+
+		if err := c.Get(ctx, client.ObjectKeyFromObject(res), res); err != nil {
+			return fmt.Errorf("unable to get '%s': %w", ref, err)
+		}
+
+		if w.ReadyExpr != "" {
+			ready, err := evaluateReadyExpr(ctx, obj, res.UnstructuredContent(), w.ReadyExpr)
+			if err != nil {
+				return fmt.Errorf("unable to evaluate readiness of '%s': %w", ref, err)
+			}
+			if !ready {
+				return fmt.Errorf("'%s' is not ready according to expression '%s'", ref, w.ReadyExpr)
+			}
+			continue
+		}
+
+		result, err := kstatus.Compute(res)
+		if err != nil {
+			return fmt.Errorf("unable to compute status of '%s': %w", ref, err)
+		}
+		if result.Status != kstatus.CurrentStatus {
+			return fmt.Errorf("'%s' is not ready: status is %s: %s", ref, result.Status, result.Message)
+		}
+	}
+	return nil
+}
+
+// checkDependencyVersion checks if the chart version of the latest release
+// of the given dependency matches the semver range constraint.
+func checkDependencyVersion(dHr *v2.HelmRelease, constraint string) error {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return fmt.Errorf("invalid version constraint '%s': %w", constraint, err)
+	}
+	cur := dHr.Status.History.Latest()
+	if cur == nil {
+		return errors.New("no release found")
+	}
+	ver, err := semver.NewVersion(cur.ChartVersion)
+	if err != nil {
+		return fmt.Errorf("invalid chart version '%s': %w", cur.ChartVersion, err)
+	}
+	if !c.Check(ver) {
+		return fmt.Errorf("chart version '%s' does not match '%s'", cur.ChartVersion, constraint)
+	}
+	return nil
+}
+
+// adoptLegacyRelease attempts to adopt a v2beta1 release into a v2
+// release.
+// This is done by retrieving the last successful release from the Helm storage
+// and converting it to a v2 release snapshot.
+// If the v2beta1 release has already been adopted, this function is a no-op.
+func (r *HelmReleaseReconciler) adoptLegacyRelease(ctx context.Context, getter genericclioptions.RESTClientGetter, obj *v2.HelmRelease) error {
+	if obj.Status.LastReleaseRevision < 1 || len(obj.Status.History) > 0 {
+		return nil
+	}
+
+	var (
+		log              = ctrl.LoggerFrom(ctx).V(logger.DebugLevel)
+		storageNamespace = obj.GetStorageNamespace()
+		releaseNamespace = obj.GetReleaseNamespace()
+		releaseName      = obj.GetReleaseName()
+		version          = obj.Status.LastReleaseRevision
+	)
+
+	log.Info("adopting %s/%s.v%d release from v2beta1 state", releaseNamespace, releaseName, version)
+
+	// Construct config factory for current release. The v2beta1 API only
+	// supported the Secrets storage driver.
 	cfg, err := action.NewConfigFactory(getter,
-		action.WithStorage(action.DefaultStorageDriver, obj.Status.StorageNamespace),
+		action.WithStorage(helmdriver.SecretsDriverName, storageNamespace),
 		action.WithStorageLog(action.NewDebugLog(ctrl.LoggerFrom(ctx).V(logger.TraceLevel))),
 	)
 	if err != nil {
-		conditions.MarkFalse(obj, meta.ReadyCondition, "ConfigFactoryErr", "%s", err)
 		return err
 	}
 
-	// Run uninstall.
-	return intreconcile.NewUninstall(cfg, r.EventRecorder).Reconcile(ctx, &intreconcile.Request{Object: obj})
-}
+	// Get the last successful release based on the observation for the v2beta1
+	// object.
+	rls, err := cfg.Store().Get(releaseName, version)
+	if err != nil {
+		return err
+	}
+
+	// Convert it to a v2 release snapshot.
+	snap := release.ObservedToSnapshot(release.ObserveRelease(rls))
+
+	// If tests are enabled, include them as well.
+	if obj.GetTest().Enable {
+		snap.SetTestHooks(release.TestHooksFromRelease(rls))
+	}
+
+	// Adopt it as the current release in the history.
+	obj.Status.History = append(obj.Status.History, snap)
+	obj.Status.StorageNamespace = storageNamespace
+
+	// Erase the last release revision from the status.
+	obj.Status.LastReleaseRevision = 0
+
+	return nil
+}
+
+// adoptPostRenderersStatus attempts to set obj.Status.ObservedPostRenderersDigest
+// for v2beta1 and v2beta2 HelmReleases.
+func (*HelmReleaseReconciler) adoptPostRenderersStatus(obj *v2.HelmRelease) {
+	if obj.GetGeneration() != obj.Status.ObservedGeneration {
+		return
+	}
+
+	// if we have a reconciled object with PostRenderers not reflected in the
+	// status, we need to update the status.
+	if obj.Spec.PostRenderers != nil && obj.Status.ObservedPostRenderersDigest == "" {
+		obj.Status.ObservedPostRenderersDigest = postrender.Digest(digest.Canonical, obj.Spec.PostRenderers).String()
+	}
+}
+
+func (r *HelmReleaseReconciler) buildRESTClientGetter(ctx context.Context, obj *v2.HelmRelease, extraOpts ...kube.Option) (genericclioptions.RESTClientGetter, error) {
+	opts := []kube.Option{
+		kube.WithNamespace(obj.GetReleaseNamespace()),
+		kube.WithClientOptions(r.ClientOpts),
+		// When ServiceAccountName is empty, it will fall back to the configured
+		// default. If this is not configured either, this option will result in
+		// a no-op.
+		kube.WithImpersonate(obj.Spec.ServiceAccountName, obj.GetNamespace()),
+		kube.WithPersistent(obj.UsePersistentClient()),
+		kube.WithDiscoveryCache(r.discoveryCache),
+	}
+	opts = append(opts, extraOpts...)
+	if obj.Spec.KubeConfig != nil {
+		secretName := types.NamespacedName{
+			Namespace: obj.GetNamespace(),
+			Name:      obj.Spec.KubeConfig.SecretRef.Name,
+		}
+		var secret corev1.Secret
+		if err := r.Get(ctx, secretName, &secret); err != nil {
+			return nil, fmt.Errorf("could not get KubeConfig secret '%s': %w", secretName, err)
+		}
+		kubeConfig, err := kube.ConfigFromSecret(&secret, obj.Spec.KubeConfig.SecretRef.Key, r.KubeConfigOpts)
+		if err != nil {
+			return nil, err
+		}
+		if r.RequireKubeConfigTLS {
+			if err := kube.RequireTLS(kubeConfig); err != nil {
+				return nil, fmt.Errorf("KubeConfig secret '%s' is not allowed: %w", secretName, err)
+			}
+		}
+		return r.clientCache.Get(client.ObjectKeyFromObject(obj).String(), kubeConfig, opts...), nil
+	}
+
+	cfg, err := r.GetClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("could not get in-cluster REST config: %w", err)
+	}
+	return r.clientCache.Get(client.ObjectKeyFromObject(obj).String(), cfg, opts...), nil
+}
+
+// buildStorageOptions returns the action.ConfigFactoryOption(s) to configure
+// the Helm storage of the HelmRelease with, for the given driver and
+// namespace. For the SQL storage driver, the connection string is read from
+// the Secret referenced by the HelmRelease, or from the
+// HELM_DRIVER_SQL_CONNECTION_STRING environment variable of the controller.
+func (r *HelmReleaseReconciler) buildStorageOptions(ctx context.Context, obj *v2.HelmRelease, driver, namespace string) ([]action.ConfigFactoryOption, error) {
+	opts := []action.ConfigFactoryOption{
+		action.WithStorageAnnotations(obj.Spec.StorageAnnotations),
+		action.WithStorageOwner(obj),
+		action.WithStorageLog(action.NewDebugLog(ctrl.LoggerFrom(ctx).V(logger.TraceLevel))),
+	}
+
+	if strings.EqualFold(driver, helmdriver.SQLDriverName) {
+		dsn := os.Getenv(envStorageSQLConnectionString)
+		if ref := obj.Spec.StorageSQLSecretRef; ref != nil {
+			secretName := types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      ref.Name,
+			}
+			var secret corev1.Secret
+			if err := r.Get(ctx, secretName, &secret); err != nil {
+				return nil, fmt.Errorf("could not get storage SQL secret '%s': %w", secretName, err)
+			}
+			key := ref.Key
+			if key == "" {
+				key = defaultStorageSQLSecretKey
+			}
+			v, ok := secret.Data[key]
+			if !ok {
+				return nil, fmt.Errorf("key '%s' not found in storage SQL secret '%s'", key, secretName)
+			}
+			dsn = string(v)
+		}
+		opts = append(opts, action.WithStorageDSN(dsn), action.WithSQLDriverCache(r.sqlDrivers))
+	}
+
+	return append(opts, action.WithStorage(driver, namespace)), nil
+}
+
+// reconcileStorageDriver moves the Helm release of the given
+// v2.HelmRelease from the storage of the observed storage driver to the
+// storage of the configured storage driver, if these differ. Releases with
+// a changed storage namespace are left to the uninstallation of the
+// changed release target. It marks the object as not ready, and returns an
+// error if the release could not be moved.
+func (r *HelmReleaseReconciler) reconcileStorageDriver(ctx context.Context, getter genericclioptions.RESTClientGetter, obj *v2.HelmRelease) error {
+	observed, desired := action.ObservedStorageDriver(obj), action.StorageDriver(obj)
+	if strings.EqualFold(observed, desired) || obj.Status.History.Latest() == nil ||
+		obj.Status.StorageNamespace == "" || obj.Status.StorageNamespace != obj.GetStorageNamespace() {
+		return nil
+	}
+
+	migrate := func() (int, error) {
+		fromOpts, err := r.buildStorageOptions(ctx, obj, observed, obj.Status.StorageNamespace)
+		if err != nil {
+			return 0, err
+		}
+		from, err := action.NewConfigFactory(getter, fromOpts...)
+		if err != nil {
+			return 0, err
+		}
+		toOpts, err := r.buildStorageOptions(ctx, obj, desired, obj.Status.StorageNamespace)
+		if err != nil {
+			return 0, err
+		}
+		to, err := action.NewConfigFactory(getter, toOpts...)
+		if err != nil {
+			return 0, err
+		}
+		return action.MigrateStorage(from, to, obj.GetReleaseName())
+	}
+
+	n, err := migrate()
+	if err != nil {
+		err = fmt.Errorf("failed to move release from '%s' to '%s' storage: %w", observed, desired, err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.StorageMigrationFailedReason, "%s", err)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.StorageMigrationFailedReason, err.Error())
+		return err
+	}
+	if conditions.HasAnyReason(obj, meta.ReadyCondition, v2.StorageMigrationFailedReason) {
+		conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "reconciliation in progress")
+	}
+
+	obj.Status.StorageDriver = desired
+	r.Eventf(obj, corev1.EventTypeNormal, meta.ProgressingReason,
+		"moved %d release version(s) from '%s' to '%s' storage", n, observed, desired)
+	return nil
+}
+
+// composeHookEnv returns the environment of the hooks of the Helm install
+// and upgrade actions of the v2.HelmRelease, with the values read from the
+// Secrets referenced by the respective ExtraEnv.
+func (r *HelmReleaseReconciler) composeHookEnv(ctx context.Context, obj *v2.HelmRelease) (map[string][]byte, map[string][]byte, error) {
+	secrets := map[string]*corev1.Secret{}
+	compose := func(vars []v2.ExtraEnvVar) (map[string][]byte, error) {
+		env := make(map[string][]byte, len(vars))
+		for _, v := range vars {
+			secretName := types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      v.SecretKeyRef.Name,
+			}
+			secret, ok := secrets[secretName.Name]
+			if !ok {
+				secret = &corev1.Secret{}
+				if err := r.Get(ctx, secretName, secret); err != nil {
+					return nil, fmt.Errorf("could not get hook environment secret '%s': %w", secretName, err)
+				}
+				secrets[secretName.Name] = secret
+			}
+			key := v.SecretKeyRef.Key
+			if key == "" {
+				key = v.Name
+			}
+			value, ok := secret.Data[key]
+			if !ok {
+				return nil, fmt.Errorf("key '%s' not found in hook environment secret '%s'", key, secretName)
+			}
+			env[v.Name] = value
+		}
+		return env, nil
+	}
+
+	installEnv, err := compose(obj.GetInstall().ExtraEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+	upgradeEnv, err := compose(obj.GetUpgrade().ExtraEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return installEnv, upgradeEnv, nil
+}
+
+// buildArtifactHTTPClient returns the HTTP client used to download the chart
+// artifact of the v2.HelmRelease. If the object references a proxy Secret,
+// this is a copy of the shared client configured with the proxy of the
+// Secret, which is reused until the Secret changes.
+func (r *HelmReleaseReconciler) buildArtifactHTTPClient(ctx context.Context, obj *v2.HelmRelease) (*http.Client, error) {
+	ref := obj.Spec.ProxySecretRef
+	if ref == nil {
+		return r.artifactHTTPClient, nil
+	}
+
+	secretName := types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      ref.Name,
+	}
+	var secret corev1.Secret
+	if err := r.Get(ctx, secretName, &secret); err != nil {
+		return nil, fmt.Errorf("could not get proxy secret '%s': %w", secretName, err)
+	}
+	cfg := &httpproxy.Config{
+		HTTPProxy:  string(secret.Data["HTTP_PROXY"]),
+		HTTPSProxy: string(secret.Data["HTTPS_PROXY"]),
+		NoProxy:    string(secret.Data["NO_PROXY"]),
+	}
+	if cfg.HTTPProxy == "" && cfg.HTTPSProxy == "" {
+		return nil, fmt.Errorf("neither key 'HTTP_PROXY' nor 'HTTPS_PROXY' found in proxy secret '%s'", secretName)
+	}
+	return r.proxyClients.Get(string(secret.UID), secret.ResourceVersion, cfg), nil
+}
+
+// getSource returns the source object containing the HelmChart, either by
+// using the chartRef in the spec, or by looking up the HelmChart
+// referenced in the status object.
+// It returns the source object or an error.
+func (r *HelmReleaseReconciler) getSource(ctx context.Context, obj *v2.HelmRelease) (sourcev1.Source, error) {
+	var name, namespace string
+	if obj.HasChartRef() {
+		if obj.Spec.ChartRef.Kind == sourcev1beta2.OCIRepositoryKind {
+			return r.getSourceFromOCIRef(ctx, obj)
+		}
+		name, namespace = obj.Spec.ChartRef.Name, obj.Spec.ChartRef.Namespace
+		if namespace == "" {
+			namespace = obj.GetNamespace()
+		}
+	} else {
+		namespace, name = obj.Status.GetHelmChart()
+	}
+
+	chartRef := types.NamespacedName{Namespace: namespace, Name: name}
+
+	if err := intacl.AllowsAccessTo(obj, sourcev1.HelmChartKind, chartRef); err != nil {
+		return nil, err
+	}
+
+	hc := sourcev1.HelmChart{}
+	if err := r.Client.Get(ctx, chartRef, &hc); err != nil {
+		return nil, err
+	}
+	return &hc, nil
+}
+
+// sourceKinds are the source kinds a v2.HelmRelease can refer to, with the
+// API group version they are served by.
+var sourceKinds = map[string]schema.GroupVersion{
+	sourcev1.HelmChartKind:          sourcev1.GroupVersion,
+	sourcev1beta2.OCIRepositoryKind: sourcev1beta2.GroupVersion,
+}
+
+// detectUnavailableSourceKinds returns the source kinds of which the API is
+// not installed in the cluster, e.g. because the controller is deployed
+// without the source-controller for HelmReleases referring to an
+// OCIRepository only.
+func detectUnavailableSourceKinds(mapper apimeta.RESTMapper) (map[string]bool, error) {
+	unavailable := map[string]bool{}
+	for kind, gv := range sourceKinds {
+		if _, err := mapper.RESTMapping(gv.WithKind(kind).GroupKind(), gv.Version); err != nil {
+			if !apimeta.IsNoMatchError(err) {
+				return nil, fmt.Errorf("failed to detect the %s API: %w", kind, err)
+			}
+			ctrl.Log.Info(fmt.Sprintf("the %s API is not installed in the cluster: HelmReleases referring to it will not be reconciled", kind))
+			unavailable[kind] = true
+		}
+	}
+	return unavailable, nil
+}
+
+// sourceAPIAvailable returns if the API of the given source kind was
+// installed in the cluster when the controller started.
+func (r *HelmReleaseReconciler) sourceAPIAvailable(kind string) bool {
+	return !r.unavailableSourceKinds[kind]
+}
+
+// requiredSourceKind returns the source kind the controller fetches the
+// chart of the given v2.HelmRelease from.
+func requiredSourceKind(obj *v2.HelmRelease) string {
+	if obj.HasChartRef() {
+		return obj.Spec.ChartRef.Kind
+	}
+	return sourcev1.HelmChartKind
+}
+
+func (r *HelmReleaseReconciler) getSourceFromOCIRef(ctx context.Context, obj *v2.HelmRelease) (sourcev1.Source, error) {
+	name, namespace := obj.Spec.ChartRef.Name, obj.Spec.ChartRef.Namespace
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+	ociRepoRef := types.NamespacedName{Namespace: namespace, Name: name}
+
+	if err := intacl.AllowsAccessTo(obj, sourcev1beta2.OCIRepositoryKind, ociRepoRef); err != nil {
+		return nil, err
+	}
+
+	or := sourcev1beta2.OCIRepository{}
+	if err := r.Client.Get(ctx, ociRepoRef, &or); err != nil {
+		return nil, err
+	}
+	return &or, nil
+}
+
+// reconcileDryRun renders the release of the v2.HelmRelease with a
+// server-side dry-run, and writes the result to a ConfigMap. The outcome is
+// recorded as an event, as a failure must not affect the reconciliation of
+// the release itself.
+func (r *HelmReleaseReconciler) reconcileDryRun(ctx context.Context, obj *v2.HelmRelease) {
+	cm, err := r.dryRun(ctx, obj)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "dry-run failed")
+		r.Eventf(obj, corev1.EventTypeWarning, dryRunFailedReason, "Dry-run failed: %s", err)
+		return
+	}
+	r.Eventf(obj, corev1.EventTypeNormal, dryRunSucceededReason,
+		"Dry-run succeeded: result written to ConfigMap '%s/%s'", cm.Namespace, cm.Name)
+}
+
+// dryRun renders the release of the v2.HelmRelease using the current chart
+// artifact and values, and writes the rendered manifest and the diff against
+// the current release to a ConfigMap. It returns the written ConfigMap.
+func (r *HelmReleaseReconciler) dryRun(ctx context.Context, obj *v2.HelmRelease) (*corev1.ConfigMap, error) {
+	source, err := r.getSource(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("could not get Source object: %w", err)
+	}
+	if ready, msg := isSourceReady(source); !ready {
+		return nil, errors.New(msg)
+	}
+
+	values, err := r.composeValues(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := r.buildArtifactHTTPClient(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	loadedChart, err := loader.SecureLoadChartFromURL(ctx, loader.NewRetryableHTTPClient(ctx, r.artifactFetchRetries, httpClient), source.GetArtifact().URL, source.GetArtifact().Digest,
+		r.artifactLoadOptions(ctx, obj, source)...)
+	if err != nil {
+		return nil, fmt.Errorf("could not load chart: %w", err)
+	}
+	if _, err = mutateChartWithSourceRevision(loadedChart, source); err != nil {
+		return nil, err
+	}
+
+	getter, err := r.buildRESTClientGetter(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	storageOpts, err := r.buildStorageOptions(ctx, obj, action.StorageDriver(obj), obj.GetStorageNamespace())
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := action.NewConfigFactory(getter, storageOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, current, err := action.DryRun(ctx, cfg.Build(nil), obj, loadedChart, helmchartutil.Values(values))
+	if err != nil {
+		return nil, err
+	}
+	summary, err := action.SummarizeDryRun(rendered, current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize dry-run result: %w", err)
+	}
+	summaryJSON, err := json.Marshal(summary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dry-run summary: %w", err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dryRunConfigMapName(obj),
+			Namespace: obj.Namespace,
+		},
+	}
+	if _, err = controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.Data = dryRunData(rendered, current, intreconcile.HideSecrets(obj))
+		cm.Data["summary"] = string(summaryJSON)
+		return controllerutil.SetControllerReference(obj, cm, r.Client.Scheme())
+	}); err != nil {
+		return nil, fmt.Errorf("failed to write dry-run result: %w", err)
+	}
+	return cm, nil
+}
+
+// retainFailedArtifact retains the chart artifact, the composed values and
+// the manifest of the latest release of the v2.HelmRelease when the
+// reconciliation failed with the given error, for postmortem debugging. The
+// retained files are removed once the object is Ready again. Failures to
+// retain the files are logged, as they must not affect the reconciliation of
+// the release itself.
+func (r *HelmReleaseReconciler) retainFailedArtifact(ctx context.Context, obj *v2.HelmRelease, cfg *action.ConfigFactory,
+	artifact []byte, values map[string]any, err error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if err == nil || interrors.IsOneOf(err, errWaitForDependency, errWaitForChart, errReleaseConflict) {
+		if conditions.IsReady(obj) {
+			if err := r.failedArtifacts.Remove(obj.GetNamespace(), obj.GetName()); err != nil {
+				log.Error(err, "failed to remove retained artifact")
+			}
+		}
+		return
+	}
+	if artifact == nil {
+		return
+	}
+
+	files := map[string][]byte{
+		"chart.tgz": artifact,
+		"error.txt": []byte(err.Error()),
+	}
+	if b, err := yaml.Marshal(values); err == nil {
+		files["values.yaml"] = b
+	}
+	if cfg != nil {
+		if rls, err := action.LastRelease(cfg.Store(), obj.GetReleaseName()); err == nil && rls != nil {
+			manifest := rls.Manifest
+			if intreconcile.HideSecrets(obj) {
+				manifest = release.HideSecretsInManifest(manifest)
+			}
+			files["manifest.yaml"] = []byte(manifest)
+		}
+	}
+	if err := r.failedArtifacts.Retain(obj.GetNamespace(), obj.GetName(), files); err != nil {
+		log.Error(err, "failed to retain artifact of failed reconciliation")
+		return
+	}
+	log.V(logger.DebugLevel).Info("retained artifact of failed reconciliation",
+		"path", r.failedArtifacts.Dir(obj.GetNamespace(), obj.GetName()))
+}
+
+// artifactLoadOptions returns the options for loading the chart artifact of
+// the given source, according to the artifact verification of the
+// v2.HelmRelease.
+func (r *HelmReleaseReconciler) artifactLoadOptions(ctx context.Context, obj *v2.HelmRelease, source sourcev1.Source) []loader.LoadOption {
+	opts := []loader.LoadOption{
+		loader.WithCache(r.artifactCache),
+		loader.WithPeer(r.artifactCachePeer, r.artifactCacheToken),
+		loader.WithMaxArtifactSize(r.maxArtifactSize),
+		loader.WithMaxChartSize(r.maxChartSize),
+	}
+	switch obj.GetArtifactVerification(action.DefaultArtifactVerification) {
+	case v2.ArtifactVerificationWarn:
+		opts = append(opts, loader.WithIntegrityFailureHandler(func(err error) {
+			msg := fmt.Sprintf("Loading unverified artifact of revision '%s': %s", source.GetArtifact().Revision, err)
+			ctrl.LoggerFrom(ctx).Info(msg)
+			r.Eventf(obj, corev1.EventTypeWarning, v2.UnverifiedArtifactReason, msg)
+		}))
+	case v2.ArtifactVerificationDisabled:
+		opts = append(opts, loader.WithIntegrityFailureHandler(func(error) {}))
+	}
+	return opts
+}
+
+// dryRunConfigMapName returns the name of the ConfigMap the result of a
+// dry-run of the v2.HelmRelease is written to.
+func dryRunConfigMapName(obj *v2.HelmRelease) string {
+	return obj.GetName() + "-dry-run"
+}
+
+// dryRunData returns the ConfigMap data for the rendered release, and the
+// current release (if any). When hideSecrets is true, Secrets are removed from
+// both manifests before they are written and compared, as the ConfigMap may
+// be readable by more users than the Helm storage.
+func dryRunData(rendered, current *helmrelease.Release, hideSecrets bool) map[string]string {
+	renderedManifest := rendered.Manifest
+	var currentManifest string
+	if current != nil {
+		currentManifest = current.Manifest
+	}
+	if hideSecrets {
+		renderedManifest = release.HideSecretsInManifest(renderedManifest)
+		currentManifest = release.HideSecretsInManifest(currentManifest)
+	}
+
+	data := map[string]string{
+		"manifest":     renderedManifest,
+		"chartVersion": rendered.Chart.Metadata.Version,
+	}
+	if rendered.Info != nil {
+		data["notes"] = rendered.Info.Notes
+	}
+	if current != nil {
+		data["currentRevision"] = strconv.Itoa(current.Version)
+	}
+	data["diff"] = cmp.Diff(currentManifest, renderedManifest)
+	return data
+}
+
+// reconcileSupportBundle collects a support bundle of the v2.HelmRelease,
+// and writes it to a ConfigMap. The outcome is recorded as an event, as a
+// failure must not affect the reconciliation of the release itself.
+func (r *HelmReleaseReconciler) reconcileSupportBundle(ctx context.Context, obj *v2.HelmRelease) {
+	cm, err := r.supportBundle(ctx, obj)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "support bundle collection failed")
+		r.Eventf(obj, corev1.EventTypeWarning, supportBundleFailedReason, "Support bundle collection failed: %s", err)
+		return
+	}
+	r.Eventf(obj, corev1.EventTypeNormal, supportBundleSucceededReason,
+		"Support bundle written to ConfigMap '%s/%s'", cm.Namespace, cm.Name)
+}
+
+// supportBundle collects the spec and status of the v2.HelmRelease, the
+// current release in the Helm storage, and the most recent events of the
+// object, and writes them to a ConfigMap. It returns the written ConfigMap.
+func (r *HelmReleaseReconciler) supportBundle(ctx context.Context, obj *v2.HelmRelease) (*corev1.ConfigMap, error) {
+	getter, err := r.buildRESTClientGetter(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	storageOpts, err := r.buildStorageOptions(ctx, obj, action.StorageDriver(obj), obj.GetStorageNamespace())
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := action.NewConfigFactory(getter, storageOpts...)
+	if err != nil {
+		return nil, err
+	}
+	current, err := action.LastRelease(cfg.Store(), obj.GetReleaseName())
+	if err != nil && !errors.Is(err, action.ErrReleaseNotFound) {
+		return nil, fmt.Errorf("failed to get current release: %w", err)
+	}
+
+	// Use the API reader to avoid setting up an informer for all events.
+	var events corev1.EventList
+	if err := r.APIReader.List(ctx, &events, client.InNamespace(obj.Namespace),
+		client.MatchingFields{"involvedObject.uid": string(obj.UID)}); err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	data, err := supportBundleData(obj, current, events.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      supportBundleConfigMapName(obj),
+			Namespace: obj.Namespace,
+		},
+	}
+	if _, err = controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.Data = data
+		return controllerutil.SetControllerReference(obj, cm, r.Client.Scheme())
+	}); err != nil {
+		return nil, fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	return cm, nil
+}
+
+// supportBundleConfigMapName returns the name of the ConfigMap the support
+// bundle of the v2.HelmRelease is written to.
+func supportBundleConfigMapName(obj *v2.HelmRelease) string {
+	return obj.GetName() + "-support-bundle"
+}
+
+// supportBundleEvent is the representation of an event in a support bundle.
+type supportBundleEvent struct {
+	Type          string      `json:"type"`
+	Reason        string      `json:"reason"`
+	Message       string      `json:"message"`
+	Count         int32       `json:"count,omitempty"`
+	LastTimestamp metav1.Time `json:"lastTimestamp"`
+}
+
+// supportBundleData returns the ConfigMap data for the support bundle of the
+// given v2.HelmRelease, its current release (if any), and its events. The
+// values and manifests of the release are omitted, as they may contain
+// sensitive data. Instead, the digest of the manifest is included.
+func supportBundleData(obj *v2.HelmRelease, current *helmrelease.Release, events []corev1.Event) (map[string]string, error) {
+	data := make(map[string]string)
+
+	// The inline values, and the copy of them in the last applied
+	// configuration of kubectl, may contain sensitive information.
+	hr := obj.DeepCopy()
+	hr.APIVersion = v2.GroupVersion.String()
+	hr.Kind = v2.HelmReleaseKind
+	hr.ManagedFields = nil
+	hr.Spec.Values = nil
+	delete(hr.Annotations, corev1.LastAppliedConfigAnnotation)
+	b, err := yaml.Marshal(hr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode HelmRelease: %w", err)
+	}
+	data["helmrelease.yaml"] = string(b)
+
+	if current != nil {
+		observation := release.ObserveRelease(current, []release.DataFilter{func(rel *release.Observation) {
+			rel.Config = nil
+			rel.Manifest = ""
+			for i := range rel.Hooks {
+				rel.Hooks[i].Manifest = ""
+			}
+		}}...)
+		b, err = yaml.Marshal(observation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode release: %w", err)
+		}
+		data["release.yaml"] = string(b)
+		data["manifestDigest"] = digest.Canonical.FromString(current.Manifest).String()
+	}
+
+	recent := make([]supportBundleEvent, 0, len(events))
+	for _, e := range events {
+		ts := e.LastTimestamp
+		if ts.IsZero() {
+			ts = metav1.NewTime(e.EventTime.Time)
+		}
+		recent = append(recent, supportBundleEvent{
+			Type:          e.Type,
+			Reason:        e.Reason,
+			Message:       e.Message,
+			Count:         e.Count,
+			LastTimestamp: ts,
+		})
+	}
+	slices.SortStableFunc(recent, func(a, b supportBundleEvent) int {
+		return a.LastTimestamp.Time.Compare(b.LastTimestamp.Time)
+	})
+	if len(recent) > supportBundleMaxEvents {
+		recent = recent[len(recent)-supportBundleMaxEvents:]
+	}
+	b, err = yaml.Marshal(recent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode events: %w", err)
+	}
+	data["events.yaml"] = string(b)
+
+	return data, nil
+}
+
+// reconcileEffectiveConfig writes the effective configuration of the
+// v2.HelmRelease to a ConfigMap. The outcome is recorded as an event, as a
+// failure must not affect the reconciliation of the release itself.
+func (r *HelmReleaseReconciler) reconcileEffectiveConfig(ctx context.Context, obj *v2.HelmRelease) {
+	cm, err := r.effectiveConfig(ctx, obj)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "effective configuration dump failed")
+		r.Eventf(obj, corev1.EventTypeWarning, effectiveConfigFailedReason, "Effective configuration dump failed: %s", err)
+		return
+	}
+	r.Eventf(obj, corev1.EventTypeNormal, effectiveConfigSucceededReason,
+		"Effective configuration written to ConfigMap '%s/%s'", cm.Namespace, cm.Name)
+}
+
+// effectiveConfig writes the effective configuration of the v2.HelmRelease
+// to a ConfigMap. It returns the written ConfigMap.
+func (r *HelmReleaseReconciler) effectiveConfig(ctx context.Context, obj *v2.HelmRelease) (*corev1.ConfigMap, error) {
+	data, err := effectiveConfigData(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      effectiveConfigConfigMapName(obj),
+			Namespace: obj.Namespace,
+		},
+	}
+	if _, err = controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.Data = data
+		return controllerutil.SetControllerReference(obj, cm, r.Client.Scheme())
+	}); err != nil {
+		return nil, fmt.Errorf("failed to write effective configuration: %w", err)
+	}
+	return cm, nil
+}
+
+// effectiveConfigConfigMapName returns the name of the ConfigMap the
+// effective configuration of the v2.HelmRelease is written to.
+func effectiveConfigConfigMapName(obj *v2.HelmRelease) string {
+	return obj.GetName() + "-effective-config"
+}
+
+// effectiveConfigData returns the ConfigMap data for the effective
+// configuration of the given v2.HelmRelease. This consists of its spec with
+// the defaults of the API and the controller filled in, and the state of the
+// feature gates which apply to it.
+func effectiveConfigData(obj *v2.HelmRelease) (map[string]string, error) {
+	data := make(map[string]string)
+
+	b, err := yaml.Marshal(action.EffectiveSpec(obj))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode effective spec: %w", err)
+	}
+	data["spec.yaml"] = string(b)
+
+	gates := make(map[string]bool)
+	for _, gate := range features.ObjectFeatureGates() {
+		enabled, err := features.EnabledFor(obj, gate)
+		if err != nil {
+			return nil, err
+		}
+		gates[gate] = enabled
+	}
+	b, err = yaml.Marshal(gates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feature gates: %w", err)
+	}
+	data["featureGates.yaml"] = string(b)
+
+	return data, nil
+}
+
+// composeValues composes the values of the v2.HelmRelease from the spec and
+// the references to ConfigMaps, Secrets, HelmReleases and fields of other
+// objects.
+func (r *HelmReleaseReconciler) composeValues(ctx context.Context, obj *v2.HelmRelease) (map[string]interface{}, error) {
+	return r.composeLayeredValues(ctx, obj, nil)
+}
+
+// composeLayeredValues composes the values of the v2.HelmRelease. The values
+// of a referenced HelmRelease are composed recursively, and merged as a layer
+// in the order of the references. The chain holds the namespaced names of the
+// HelmReleases whose values are being composed, and is used to detect cycles.
+func (r *HelmReleaseReconciler) composeLayeredValues(ctx context.Context, obj *v2.HelmRelease, chain []string) (map[string]interface{}, error) {
+	chain = append(chain, client.ObjectKeyFromObject(obj).String())
+
+	// Consecutive ConfigMap and Secret references are merged in a single
+	// pass, with the inline values passed along with the last references.
+	values := map[string]interface{}{}
+	var refs []meta.ValuesReference
+	mergeRefs := func(inline map[string]interface{}) error {
+		if len(refs) == 0 && inline == nil {
+			return nil
+		}
+		v, err := chartutil.ChartValuesFromReferences(ctx, ctrl.LoggerFrom(ctx), r.Client, obj.Namespace, inline, refs...)
+		if err != nil {
+			return err
+		}
+		values = chartutil.MergeMaps(values, v)
+		refs = nil
+		return nil
+	}
+
+	for _, ref := range obj.Spec.ValuesFrom {
+		if ref.Kind != v2.HelmReleaseKind {
+			refs = append(refs, ref.MetaValuesReference())
+			continue
+		}
+		if err := mergeRefs(nil); err != nil {
+			return nil, err
+		}
+
+		key := types.NamespacedName{Namespace: obj.Namespace, Name: ref.Name}
+		if slices.Contains(chain, key.String()) {
+			return nil, fmt.Errorf("circular HelmRelease values reference: %s -> %s",
+				strings.Join(chain, " -> "), key.String())
+		}
+		base := &v2.HelmRelease{}
+		if err := r.Get(ctx, key, base); err != nil {
+			if apierrors.IsNotFound(err) && ref.Optional {
+				ctrl.LoggerFrom(ctx).Info(fmt.Sprintf("could not find optional HelmRelease values reference '%s'", key))
+				continue
+			}
+			return nil, fmt.Errorf("could not get HelmRelease values reference '%s': %w", key, err)
+		}
+		layer, err := r.composeLayeredValues(ctx, base, chain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compose values of HelmRelease '%s': %w", key, err)
+		}
+		if values, err = intvalues.MergeAtPath(values, ref.TargetPath, layer); err != nil {
+			return nil, fmt.Errorf("failed to merge values of HelmRelease '%s': %w", key, err)
+		}
+	}
+	if err := mergeRefs(obj.GetValues()); err != nil {
+		return nil, err
+	}
+
+	if len(obj.Spec.ValuesFromFields) > 0 {
+		reader, err := r.fieldReferenceReader(obj)
+		if err != nil {
+			return nil, err
+		}
+		if values, err = intvalues.MergeFieldReferences(ctx, reader, obj.Namespace, values, obj.Spec.ValuesFromFields...); err != nil {
+			return nil, err
+		}
+	}
 
-// checkDependencies checks if the dependencies of the given v2.HelmRelease
-// are Ready.
-// It returns an error if a dependency can not be retrieved or is not Ready,
-// otherwise nil.
-func (r *HelmReleaseReconciler) checkDependencies(ctx context.Context, obj *v2.HelmRelease) error {
+	// Merge the outputs of the dependencies.
 	for _, d := range obj.Spec.DependsOn {
-		ref := types.NamespacedName{
-			Namespace: d.Namespace,
-			Name:      d.Name,
+		if len(d.Outputs) == 0 {
+			continue
 		}
-		if ref.Namespace == "" {
-			ref.Namespace = obj.GetNamespace()
+		ref := dependencyKey(obj, d)
+		if err := intacl.AllowsAccessTo(obj, v2.HelmReleaseKind, ref); err != nil {
+			return nil, err
 		}
-
-		dHr := &v2.HelmRelease{}
-		if err := r.APIReader.Get(ctx, ref, dHr); err != nil {
-			return fmt.Errorf("unable to get '%s' dependency: %w", ref, err)
+		var err error
+		if values, err = intvalues.MergeFieldReferences(ctx, r.APIReader, ref.Namespace, values, d.FieldValuesReferences()...); err != nil {
+			return nil, fmt.Errorf("failed to merge outputs of dependency '%s': %w", ref, err)
 		}
+	}
+	return values, nil
+}
 
-		if dHr.Generation != dHr.Status.ObservedGeneration || !conditions.IsTrue(dHr, meta.ReadyCondition) {
-			return fmt.Errorf("dependency '%s' is not ready", ref)
-		}
+// fieldReferenceReader returns the client.Reader to read the referents of
+// the field references of the v2.HelmRelease with. When a service account is
+// impersonated for the HelmRelease, the referents are read while
+// impersonating it, which restricts them to the objects the service account
+// has access to. Otherwise, the API reader of the controller is returned.
+// Both read directly from the API server, to avoid setting up informers for
+// arbitrary kinds.
+func (r *HelmReleaseReconciler) fieldReferenceReader(obj *v2.HelmRelease) (client.Reader, error) {
+	if obj.Spec.ServiceAccountName == "" && kube.DefaultServiceAccountName == "" {
+		return r.APIReader, nil
 	}
-	return nil
+
+	cfg, err := r.GetClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("could not get in-cluster REST config: %w", err)
+	}
+	cfg = rest.CopyConfig(cfg)
+	kube.SetImpersonationConfig(cfg, obj.GetNamespace(), obj.Spec.ServiceAccountName)
+	c, err := client.New(cfg, client.Options{Scheme: r.Client.Scheme(), Mapper: r.Client.RESTMapper()})
+	if err != nil {
+		return nil, fmt.Errorf("could not create client for field references: %w", err)
+	}
+	return c, nil
 }
 
-// adoptLegacyRelease attempts to adopt a v2beta1 release into a v2
-// release.
-// This is done by retrieving the last successful release from the Helm storage
-// and converting it to a v2 release snapshot.
-// If the v2beta1 release has already been adopted, this function is a no-op.
-func (r *HelmReleaseReconciler) adoptLegacyRelease(ctx context.Context, getter genericclioptions.RESTClientGetter, obj *v2.HelmRelease) error {
-	if obj.Status.LastReleaseRevision < 1 || len(obj.Status.History) > 0 {
-		return nil
+// reconcileDeprecationWarnings marks the v2.DeprecatedAPIsCondition and
+// emits an event for the given deprecation warnings. Without any warnings,
+// the condition is removed once a new release has been made, as the
+// warnings are only returned while the release is applied.
+func (r *HelmReleaseReconciler) reconcileDeprecationWarnings(obj *v2.HelmRelease, deprecations []string, released bool) {
+	if len(deprecations) == 0 {
+		if released {
+			conditions.Delete(obj, v2.DeprecatedAPIsCondition)
+		}
+		return
 	}
 
-	var (
-		log              = ctrl.LoggerFrom(ctx).V(logger.DebugLevel)
-		storageNamespace = obj.GetStorageNamespace()
-		releaseNamespace = obj.GetReleaseNamespace()
-		releaseName      = obj.GetReleaseName()
-		version          = obj.Status.LastReleaseRevision
-	)
+	msg := fmt.Sprintf("Kubernetes API server returned deprecation warnings: %s", strings.Join(deprecations, "; "))
+	conditions.MarkTrue(obj, v2.DeprecatedAPIsCondition, v2.DeprecationWarningsReason, "%s", msg)
+	r.Eventf(obj, corev1.EventTypeWarning, v2.DeprecationWarningsReason, msg)
+}
 
-	log.Info("adopting %s/%s.v%d release from v2beta1 state", releaseNamespace, releaseName, version)
+// reconcileSecurityScanWarnings emits an event when a new release has been
+// made despite a failed security scan, because the HelmRelease is configured
+// to only warn. The v2.SecurityScanCondition itself is marked by the
+// post-renderer performing the scan, and is removed when the HelmRelease no
+// longer configures a security scan.
+func (r *HelmReleaseReconciler) reconcileSecurityScanWarnings(obj *v2.HelmRelease, released bool) {
+	if obj.Spec.SecurityScan == nil {
+		conditions.Delete(obj, v2.SecurityScanCondition)
+		return
+	}
+	if !released || !conditions.IsFalse(obj, v2.SecurityScanCondition) {
+		return
+	}
+	r.Eventf(obj, corev1.EventTypeWarning, conditions.GetReason(obj, v2.SecurityScanCondition),
+		conditions.GetMessage(obj, v2.SecurityScanCondition))
+}
 
-	// Construct config factory for current release.
-	cfg, err := action.NewConfigFactory(getter,
-		action.WithStorage(action.DefaultStorageDriver, storageNamespace),
-		action.WithStorageLog(action.NewDebugLog(ctrl.LoggerFrom(ctx).V(logger.TraceLevel))),
-	)
+// reconcileHealth assesses the health of the resources of the latest release
+// of the given v2.HelmRelease when the health check is enabled, and reflects
+// the result in the meta.HealthyCondition. The assessment is performed after
+// a new release has been made, and until the resources have been assessed to
+// be healthy. When the health check is disabled, the condition is removed.
+func (r *HelmReleaseReconciler) reconcileHealth(ctx context.Context, patchHelper *patch.SerialPatcher, cfg *action.ConfigFactory, obj *v2.HelmRelease, released bool) error {
+	if !obj.GetHealthCheck().Enable {
+		conditions.Delete(obj, meta.HealthyCondition)
+		conditions.Delete(obj, v2.HealthCheckIncompleteCondition)
+		return nil
+	}
+	cur := obj.Status.History.Latest()
+	if !conditions.IsReady(obj) || cur == nil {
+		return nil
+	}
+	if !released && conditions.IsTrue(obj, meta.HealthyCondition) {
+		return nil
+	}
+
+	rls, err := action.VerifySnapshot(cfg.Store(), cur)
 	if err != nil {
+		return fmt.Errorf("could not get release for health check: %w", err)
+	}
+
+	// Mark the object as reconciling while the health check is running, as
+	// this can take up to the timeout.
+	timeout := obj.GetHealthCheck().GetTimeout(obj.GetTimeout()).Duration
+	conditions.MarkReconciling(obj, meta.ProgressingReason, "Running health check with timeout of %s", timeout)
+	if err := intreconcile.PatchWithRetry(ctx, patchHelper, obj, patch.WithOwnedConditions{Conditions: intreconcile.OwnedConditions}, patch.WithFieldOwner(r.FieldManager)); err != nil {
 		return err
 	}
 
-	// Get the last successful release based on the observation for the v2beta1
-	// object.
-	rls, err := cfg.NewStorage().Get(releaseName, version)
+	skipped, err := action.CheckHealth(ctx, cfg.Build(nil), rls, timeout)
+	r.reconcileHealthCheckSkipped(ctx, obj, skipped)
 	if err != nil {
+		msg := fmt.Sprintf("Health check failed for release %s with chart %s: %s",
+			cur.FullReleaseName(), cur.VersionedChartName(), err.Error())
+		conditions.MarkFalse(obj, meta.HealthyCondition, meta.HealthCheckFailedReason, "%s", msg)
+		conditions.MarkFalse(obj, meta.ReadyCondition, meta.HealthCheckFailedReason, "%s", msg)
+		conditions.MarkReconciling(obj, meta.ProgressingWithRetryReason, "%s", msg)
+		r.Eventf(obj, corev1.EventTypeWarning, meta.HealthCheckFailedReason, msg)
 		return err
 	}
 
-	// Convert it to a v2 release snapshot.
-	snap := release.ObservedToSnapshot(release.ObserveRelease(rls))
+	conditions.MarkTrue(obj, meta.HealthyCondition, meta.SucceededReason, "Health check passed for release %s with chart %s",
+		cur.FullReleaseName(), cur.VersionedChartName())
+	conditions.Delete(obj, meta.ReconcilingCondition)
+	return nil
+}
 
-	// If tests are enabled, include them as well.
-	if obj.GetTest().Enable {
-		snap.SetTestHooks(release.TestHooksFromRelease(rls))
+// reconcilePostDeployChecks runs the post-deploy checks of the given
+// v2.HelmRelease after a new release has been made, and until they have
+// passed. The result is reflected in the v2.PostDeployChecksPassedCondition,
+// and the Ready condition is only marked True once the checks have passed.
+// Without any checks configured, the condition is removed.
+//
+// The checks are run once per reconciliation. While they fail within their
+// timeout since the release was deployed, it returns the duration after
+// which they should be retried, instead of blocking the reconciliation.
+func (r *HelmReleaseReconciler) reconcilePostDeployChecks(ctx context.Context, obj *v2.HelmRelease, released bool) (time.Duration, error) {
+	checks := obj.GetPostDeployChecks()
+	if checks.IsEmpty() {
+		conditions.Delete(obj, v2.PostDeployChecksPassedCondition)
+		return 0, nil
+	}
+	cur := obj.Status.History.Latest()
+	if !conditions.IsReady(obj) || cur == nil {
+		return 0, nil
+	}
+	if !released && conditions.IsTrue(obj, v2.PostDeployChecksPassedCondition) {
+		return 0, nil
+	}
+
+	timeout := checks.GetTimeout(obj.GetTimeout()).Duration
+	if err := probe.Run(ctx, checks, r.probeAllowlist); err != nil {
+		msg := fmt.Sprintf("Post-deploy checks failed for release %s with chart %s: %s",
+			cur.FullReleaseName(), cur.VersionedChartName(), err.Error())
+
+		// Hold back the Ready condition while the checks are retried.
+		remaining := timeout - release.Elapsed(cur.LastDeployed.Time, time.Now(), r.clockSkewTolerance)
+		if remaining > 0 && !probe.IsNotAllowed(err) {
+			conditions.MarkUnknown(obj, v2.PostDeployChecksPassedCondition, meta.ProgressingReason, "%s", msg)
+			conditions.MarkUnknown(obj, meta.ReadyCondition, meta.ProgressingReason, "Running post-deploy checks with timeout of %s", timeout)
+			conditions.MarkReconciling(obj, meta.ProgressingReason, "Running post-deploy checks with timeout of %s", timeout)
+			return min(remaining, probe.RetryInterval), nil
+		}
+
+		conditions.MarkFalse(obj, v2.PostDeployChecksPassedCondition, v2.PostDeployChecksFailedReason, "%s", msg)
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.PostDeployChecksFailedReason, "%s", msg)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.PostDeployChecksFailedReason, msg)
+		if probe.IsNotAllowed(err) {
+			conditions.MarkStalled(obj, v2.PostDeployChecksFailedReason, "%s", msg)
+			return 0, reconcile.TerminalError(err)
+		}
+		conditions.MarkReconciling(obj, meta.ProgressingWithRetryReason, "%s", msg)
+		return 0, err
 	}
 
-	// Adopt it as the current release in the history.
-	obj.Status.History = append(obj.Status.History, snap)
-	obj.Status.StorageNamespace = storageNamespace
+	conditions.MarkTrue(obj, v2.PostDeployChecksPassedCondition, meta.SucceededReason, "Post-deploy checks passed for release %s with chart %s",
+		cur.FullReleaseName(), cur.VersionedChartName())
+	conditions.Delete(obj, meta.ReconcilingCondition)
+	return 0, nil
+}
 
-	// Erase the last release revision from the status.
-	obj.Status.LastReleaseRevision = 0
+// reconcileVerificationWindow checks the health of the resources of the
+// latest release of the given v2.HelmRelease while it is within the
+// verification window of its upgrade. When the resources have become
+// unhealthy, the upgrade is marked as failed and remediated according to the
+// upgrade remediation of the object, even though the Helm upgrade action
+// itself succeeded.
+//
+// It returns the duration after which the health should be checked again,
+// which is zero once the window has passed or the failure has been remediated.
+func (r *HelmReleaseReconciler) reconcileVerificationWindow(ctx context.Context, patchHelper *patch.SerialPatcher, cfg *action.ConfigFactory, req *intreconcile.Request) (time.Duration, error) {
+	obj := req.Object
+	window := obj.GetUpgrade().VerificationWindow
+	if window == nil || window.Duration <= 0 {
+		return 0, nil
+	}
+	cur := obj.Status.History.Latest()
+	if cur == nil || cur.Status != helmrelease.StatusDeployed.String() || !conditions.IsReady(obj) ||
+		!conditions.HasAnyReason(obj, v2.ReleasedCondition, v2.UpgradeSucceededReason) {
+		return 0, nil
+	}
+	ignoreTestFailures := obj.GetUpgrade().GetRemediation().MustIgnoreTestFailures(obj.GetTest().IgnoreFailures)
+	if obj.Status.History.Previous(ignoreTestFailures) == nil {
+		return 0, nil
+	}
+	remaining := window.Duration - release.Elapsed(cur.LastDeployed.Time, time.Now(), r.clockSkewTolerance)
+	if remaining <= 0 {
+		return 0, nil
+	}
 
-	return nil
+	rls, err := action.VerifySnapshot(cfg.Store(), cur)
+	if err != nil {
+		return 0, fmt.Errorf("could not get release for verification window: %w", err)
+	}
+	if _, err := action.CheckHealth(ctx, cfg.Build(nil), rls, verificationWindowCheckTimeout); err != nil {
+		msg := fmt.Sprintf("Health check failed within verification window of %s for release %s with chart %s: %s",
+			window.Duration, cur.FullReleaseName(), cur.VersionedChartName(), err.Error())
+		ctrl.LoggerFrom(ctx).Info(msg)
+		conditions.MarkFalse(obj, v2.ReleasedCondition, v2.VerificationWindowFailedReason, "%s", msg)
+		obj.Status.Failures++
+		obj.GetUpgrade().GetRemediation().IncrementFailureCount(obj)
+		r.Eventf(obj, corev1.EventTypeWarning, v2.VerificationWindowFailedReason, msg)
+
+		return 0, intreconcile.NewAtomicRelease(patchHelper, cfg, r.EventRecorder, r.FieldManager).Remediate(ctx, req)
+	}
+
+	return min(remaining, verificationWindowInterval), nil
 }
 
-// adoptPostRenderersStatus attempts to set obj.Status.ObservedPostRenderersDigest
-// for v2beta1 and v2beta2 HelmReleases.
-func (*HelmReleaseReconciler) adoptPostRenderersStatus(obj *v2.HelmRelease) {
-	if obj.GetGeneration() != obj.Status.ObservedGeneration {
+// reconcileHealthCheckSkipped marks the v2.HealthCheckIncompleteCondition and
+// emits an event for the given kinds skipped during the health check, due to
+// the controller lacking the permissions to read them. Without any skipped
+// kinds, the condition is removed.
+func (r *HelmReleaseReconciler) reconcileHealthCheckSkipped(ctx context.Context, obj *v2.HelmRelease, skipped []string) {
+	if len(skipped) == 0 {
+		conditions.Delete(obj, v2.HealthCheckIncompleteCondition)
 		return
 	}
 
-	// if we have a reconciled object with PostRenderers not reflected in the
-	// status, we need to update the status.
-	if obj.Spec.PostRenderers != nil && obj.Status.ObservedPostRenderersDigest == "" {
-		obj.Status.ObservedPostRenderersDigest = postrender.Digest(digest.Canonical, obj.Spec.PostRenderers).String()
-	}
+	msg := fmt.Sprintf("Health check skipped resources of kinds the controller is not allowed to read: %s",
+		strings.Join(skipped, ", "))
+	ctrl.LoggerFrom(ctx).Info(msg)
+	conditions.MarkTrue(obj, v2.HealthCheckIncompleteCondition, v2.InsufficientPermissionsReason, "%s", msg)
+	r.Eventf(obj, corev1.EventTypeWarning, v2.InsufficientPermissionsReason, msg)
 }
 
-func (r *HelmReleaseReconciler) buildRESTClientGetter(ctx context.Context, obj *v2.HelmRelease) (genericclioptions.RESTClientGetter, error) {
-	opts := []kube.Option{
-		kube.WithNamespace(obj.GetReleaseNamespace()),
-		kube.WithClientOptions(r.ClientOpts),
-		// When ServiceAccountName is empty, it will fall back to the configured
-		// default. If this is not configured either, this option will result in
-		// a no-op.
-		kube.WithImpersonate(obj.Spec.ServiceAccountName, obj.GetNamespace()),
-		kube.WithPersistent(obj.UsePersistentClient()),
-	}
-	if obj.Spec.KubeConfig != nil {
-		secretName := types.NamespacedName{
-			Namespace: obj.GetNamespace(),
-			Name:      obj.Spec.KubeConfig.SecretRef.Name,
-		}
-		var secret corev1.Secret
-		if err := r.Get(ctx, secretName, &secret); err != nil {
-			return nil, fmt.Errorf("could not get KubeConfig secret '%s': %w", secretName, err)
-		}
-		kubeConfig, err := kube.ConfigFromSecret(&secret, obj.Spec.KubeConfig.SecretRef.Key, r.KubeConfigOpts)
-		if err != nil {
-			return nil, err
-		}
-		return kube.NewMemoryRESTClientGetter(kubeConfig, opts...), nil
+// reconcileSourceVerified marks the v2.SourceVerifiedFailedCondition and
+// emits a warning event if the source of the chart reports the chart artifact
+// failed verification. The condition is removed once the source no longer
+// reports a failed verification.
+func (r *HelmReleaseReconciler) reconcileSourceVerified(ctx context.Context, obj *v2.HelmRelease, source sourcev1.Source) {
+	o, ok := source.(conditions.Getter)
+	if !ok || !conditions.IsFalse(o, sourcev1.SourceVerifiedCondition) {
+		conditions.Delete(obj, v2.SourceVerifiedFailedCondition)
+		return
 	}
 
-	cfg, err := r.GetClusterConfig()
-	if err != nil {
-		return nil, fmt.Errorf("could not get in-cluster REST config: %w", err)
+	msg := fmt.Sprintf("%s '%s' failed to verify the chart: %s",
+		source.GetObjectKind().GroupVersionKind().Kind, sourceKey(source),
+		conditions.GetMessage(o, sourcev1.SourceVerifiedCondition))
+	ctrl.LoggerFrom(ctx).Info(msg)
+	conditions.MarkTrue(obj, v2.SourceVerifiedFailedCondition, v2.VerificationFailedReason, "%s", msg)
+	r.Eventf(obj, corev1.EventTypeWarning, v2.VerificationFailedReason, msg)
+}
+
+// sourceKey returns the '<namespace>/<name>' of the given source, or an
+// empty string if the source is not a client.Object.
+func sourceKey(source sourcev1.Source) string {
+	o, ok := source.(client.Object)
+	if !ok {
+		return ""
 	}
-	return kube.NewMemoryRESTClientGetter(cfg, opts...), nil
+	return client.ObjectKeyFromObject(o).String()
 }
 
-// getSource returns the source object containing the HelmChart, either by
-// using the chartRef in the spec, or by looking up the HelmChart
-// referenced in the status object.
-// It returns the source object or an error.
-func (r *HelmReleaseReconciler) getSource(ctx context.Context, obj *v2.HelmRelease) (sourcev1.Source, error) {
-	var name, namespace string
-	if obj.HasChartRef() {
-		if obj.Spec.ChartRef.Kind == sourcev1beta2.OCIRepositoryKind {
-			return r.getSourceFromOCIRef(ctx, obj)
+// reconcileSourceDrift marks the v2.SourceDriftCondition and emits a warning
+// event when the source of the chart resolved a lower chart version than
+// previously released for the same version constraint, or when the source
+// has not been ready for longer than the configured threshold. Otherwise,
+// the condition is removed.
+func (r *HelmReleaseReconciler) reconcileSourceDrift(ctx context.Context, obj *v2.HelmRelease, source sourcev1.Source) {
+	kind := source.GetObjectKind().GroupVersionKind().Kind
+	key := sourceKey(source)
+
+	var reason, msg string
+	if artifact := source.GetArtifact(); obj.HasChartTemplate() && artifact != nil {
+		if prev := chartVersionDecreased(obj.Status.History, obj.Spec.Chart.Spec.Version, artifact.Revision); prev != "" {
+			reason = v2.ChartVersionDecreasedReason
+			msg = fmt.Sprintf("%s '%s' resolved chart version '%s', which is lower than the previously released version '%s'",
+				kind, key, artifact.Revision, prev)
 		}
-		name, namespace = obj.Spec.ChartRef.Name, obj.Spec.ChartRef.Namespace
-		if namespace == "" {
-			namespace = obj.GetNamespace()
+	}
+	if o, ok := source.(conditions.Getter); ok && reason == "" && r.sourceStaleThreshold > 0 && r.sourceAvailability != nil {
+		since := r.sourceAvailability.observe(kind+"/"+key, conditions.IsReady(o))
+		if !since.IsZero() && time.Since(since) > r.sourceStaleThreshold {
+			reason = v2.SourceStaleReason
+			msg = fmt.Sprintf("%s '%s' has not been ready for %s: %s",
+				kind, key, time.Since(since).Round(time.Second),
+				conditions.GetMessage(o, meta.ReadyCondition))
 		}
-	} else {
-		namespace, name = obj.Status.GetHelmChart()
 	}
 
-	chartRef := types.NamespacedName{Namespace: namespace, Name: name}
-
-	if err := intacl.AllowsAccessTo(obj, sourcev1.HelmChartKind, chartRef); err != nil {
-		return nil, err
+	if reason == "" {
+		conditions.Delete(obj, v2.SourceDriftCondition)
+		return
 	}
+	ctrl.LoggerFrom(ctx).Info(msg)
+	conditions.MarkTrue(obj, v2.SourceDriftCondition, reason, "%s", msg)
+	r.Eventf(obj, corev1.EventTypeWarning, reason, msg)
+}
 
-	hc := sourcev1.HelmChart{}
-	if err := r.Client.Get(ctx, chartRef, &hc); err != nil {
-		return nil, err
+// reconcileInventory records the resources in the manifest of the latest
+// release of the given v2.HelmRelease in the Inventory of the status. The
+// Inventory is only computed when a new release has been made, or when it
+// has not been recorded yet.
+func (r *HelmReleaseReconciler) reconcileInventory(cfg *action.ConfigFactory, obj *v2.HelmRelease, released bool) error {
+	cur := obj.Status.History.Latest()
+	if cur == nil {
+		obj.Status.Inventory = nil
+		return nil
+	}
+	if !released && obj.Status.Inventory != nil {
+		return nil
 	}
-	return &hc, nil
-}
 
-func (r *HelmReleaseReconciler) getSourceFromOCIRef(ctx context.Context, obj *v2.HelmRelease) (sourcev1.Source, error) {
-	name, namespace := obj.Spec.ChartRef.Name, obj.Spec.ChartRef.Namespace
-	if namespace == "" {
-		namespace = obj.GetNamespace()
+	rls, err := action.VerifySnapshot(cfg.Store(), cur)
+	if err != nil {
+		return fmt.Errorf("could not get release for inventory: %w", err)
 	}
-	ociRepoRef := types.NamespacedName{Namespace: namespace, Name: name}
+	inventory, err := action.Inventory(cfg.Build(nil), rls)
+	if err != nil {
+		return fmt.Errorf("failed to compute inventory of release %s: %w", cur.FullReleaseName(), err)
+	}
+	obj.Status.Inventory = inventory
+	return nil
+}
 
-	if err := intacl.AllowsAccessTo(obj, sourcev1beta2.OCIRepositoryKind, ociRepoRef); err != nil {
-		return nil, err
+// latestReleaseVersion returns the version of the latest release in the
+// history of the v2.HelmRelease, or zero.
+func latestReleaseVersion(obj *v2.HelmRelease) int {
+	if cur := obj.Status.History.Latest(); cur != nil {
+		return cur.Version
 	}
+	return 0
+}
 
-	or := sourcev1beta2.OCIRepository{}
-	if err := r.Client.Get(ctx, ociRepoRef, &or); err != nil {
-		return nil, err
+// requeueAfter returns the duration after which the v2.HelmRelease must be
+// reconciled again. For an event-driven HelmRelease, which has an interval
+// of zero, this is the resync interval of the reconciler.
+func (r *HelmReleaseReconciler) requeueAfter(obj *v2.HelmRelease) time.Duration {
+	if obj.IsEventDriven() {
+		return r.resyncInterval
 	}
-	return &or, nil
+	return obj.GetRequeueAfter()
 }
 
 // waitForHistoryCacheSync returns a function that can be used to wait for the
@@ -772,6 +3039,95 @@ func (r *HelmReleaseReconciler) waitForHistoryCacheSync(obj *v2.HelmRelease) wai
 	}
 }
 
+// requestsForDependencyChange returns the requests for the HelmReleases
+// which depend on the given HelmRelease and are waiting for their
+// dependencies or stalled on a dependency cycle, so they can proceed without waiting for the dependency
+// requeue interval.
+func (r *HelmReleaseReconciler) requestsForDependencyChange(ctx context.Context, o client.Object) []reconcile.Request {
+	dHr, ok := o.(*v2.HelmRelease)
+	if !ok {
+		err := fmt.Errorf("expected a HelmRelease, got %T", o)
+		ctrl.LoggerFrom(ctx).Error(err, "failed to get requests for dependency change")
+		return nil
+	}
+
+	var list v2.HelmReleaseList
+	if err := r.List(ctx, &list, client.MatchingFields{
+		v2.DependsOnIndexKey: client.ObjectKeyFromObject(dHr).String(),
+	}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list HelmReleases for dependency change")
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for i := range list.Items {
+		if list.Items[i].IsSuspended() ||
+			!conditions.HasAnyReason(&list.Items[i], meta.ReadyCondition, v2.DependencyNotReadyReason, v2.DependencyCycleReason) {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+	}
+	return append(reqs, r.requestsForGroupBarrier(ctx, dHr)...)
+}
+
+// requestsForGroupBarrier returns the requests for the HelmReleases in the
+// phases following the phase of the given HelmRelease in any
+// HelmReleaseGroup, which are waiting for the preceding phases.
+func (r *HelmReleaseReconciler) requestsForGroupBarrier(ctx context.Context, hr *v2.HelmRelease) []reconcile.Request {
+	groups, err := listHelmReleaseGroups(ctx, r.Client, hr.Namespace)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list HelmReleaseGroups for dependency change")
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for i := range groups {
+		phase, err := groups[i].PhaseOf(hr)
+		if err != nil || phase < 0 {
+			continue
+		}
+		for p := phase + 1; p < len(groups[i].Spec.Phases); p++ {
+			releases, err := listPhaseReleases(ctx, r.Client, &groups[i], p)
+			if err != nil {
+				continue
+			}
+			for j := range releases {
+				if releases[j].IsSuspended() || !conditions.HasAnyReason(&releases[j], meta.ReadyCondition, v2.DependencyNotReadyReason) {
+					continue
+				}
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&releases[j])})
+			}
+		}
+	}
+	return reqs
+}
+
+func (r *HelmReleaseReconciler) requestsForValuesChange(ctx context.Context, o client.Object) []reconcile.Request {
+	hr, ok := o.(*v2.HelmRelease)
+	if !ok {
+		err := fmt.Errorf("expected a HelmRelease, got %T", o)
+		ctrl.LoggerFrom(ctx).Error(err, "failed to get requests for values change")
+		return nil
+	}
+
+	var list v2.HelmReleaseList
+	if err := r.List(ctx, &list, client.MatchingFields{
+		v2.ValuesFromIndexKey: client.ObjectKeyFromObject(hr).String(),
+	}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list HelmReleases for values change")
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for i := range list.Items {
+		if list.Items[i].IsSuspended() {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+	}
+	return reqs
+}
+
 func (r *HelmReleaseReconciler) requestsForHelmChartChange(ctx context.Context, o client.Object) []reconcile.Request {
 	hc, ok := o.(*sourcev1.HelmChart)
 	if !ok {
@@ -794,6 +3150,9 @@ func (r *HelmReleaseReconciler) requestsForHelmChartChange(ctx context.Context,
 
 	var reqs []reconcile.Request
 	for i, hr := range list.Items {
+		if hr.IsSuspended() {
+			continue
+		}
 		// If the HelmRelease is ready and the revision of the artifact equals to the
 		// last attempted revision, we should not make a request for this HelmRelease
 		if conditions.IsReady(&list.Items[i]) && hc.GetArtifact().HasRevision(hr.Status.GetLastAttemptedRevision()) {
@@ -826,6 +3185,9 @@ func (r *HelmReleaseReconciler) requestsForOCIRrepositoryChange(ctx context.Cont
 
 	var reqs []reconcile.Request
 	for i, hr := range list.Items {
+		if hr.IsSuspended() {
+			continue
+		}
 		// If the HelmRelease is ready and the digest of the artifact equals to the
 		// last attempted revision digest, we should not make a request for this HelmRelease,
 		// likewise if we cannot retrieve the artifact digest.
@@ -895,7 +3257,7 @@ func getNamespacedName(obj *v2.HelmRelease) (types.NamespacedName, error) {
 		namespacedName.Name = obj.Spec.ChartRef.Name
 	case !obj.HasChartRef() && obj.HasChartTemplate():
 		namespacedName.Namespace = obj.Spec.Chart.GetNamespace(obj.GetNamespace())
-		namespacedName.Name = obj.GetHelmChartName()
+		namespacedName.Name = intreconcile.HelmChartName(obj)
 	default:
 		return namespacedName, fmt.Errorf("one of chartRef or chart must be present")
 	}
@@ -903,6 +3265,31 @@ func getNamespacedName(obj *v2.HelmRelease) (types.NamespacedName, error) {
 	return namespacedName, nil
 }
 
+// applyChartOverride applies the first v2.ChartOverride of the HelmRelease
+// matching the given cluster attributes to the Chart or ChartRef of the
+// object. It returns true if an override was applied.
+func applyChartOverride(obj *v2.HelmRelease, attributes map[string]string) bool {
+	override := obj.GetChartOverride(attributes)
+	if override == nil {
+		return false
+	}
+
+	switch {
+	case obj.HasChartTemplate():
+		if override.Version != "" {
+			obj.Spec.Chart.Spec.Version = override.Version
+		}
+		if override.SourceRef != nil {
+			obj.Spec.Chart.Spec.SourceRef = *override.SourceRef
+		}
+	case obj.HasChartRef():
+		if override.ChartRef != nil {
+			obj.Spec.ChartRef = override.ChartRef.DeepCopy()
+		}
+	}
+	return true
+}
+
 func mutateChartWithSourceRevision(chart *chart.Chart, source sourcev1.Source) (string, error) {
 	// If the source is an OCIRepository, we can try to mutate the chart version
 	// with the artifact revision. The revision is either a <tag>@<digest> or
@@ -0,0 +1,274 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	feathelper "github.com/fluxcd/pkg/runtime/features"
+	"github.com/fluxcd/pkg/runtime/patch"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/features"
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+// writeCounter counts the write requests made to the Kubernetes API, both
+// through the controller-runtime client and through the REST config used
+// for Helm actions.
+type writeCounter struct {
+	client atomic.Int64
+	kube   atomic.Int64
+}
+
+// interceptorFuncs returns interceptor.Funcs which count the write requests
+// made through a controller-runtime client.
+func (c *writeCounter) interceptorFuncs() interceptor.Funcs {
+	return interceptor.Funcs{
+		Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			c.client.Add(1)
+			return cl.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			c.client.Add(1)
+			return cl.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, p client.Patch, opts ...client.PatchOption) error {
+			c.client.Add(1)
+			return cl.Patch(ctx, obj, p, opts...)
+		},
+		Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			c.client.Add(1)
+			return cl.Delete(ctx, obj, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, cl client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			c.client.Add(1)
+			return cl.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, cl client.Client, subResourceName string, obj client.Object, p client.Patch, opts ...client.SubResourcePatchOption) error {
+			c.client.Add(1)
+			return cl.SubResource(subResourceName).Patch(ctx, obj, p, opts...)
+		},
+	}
+}
+
+// clusterConfig returns a copy of the test cluster config which counts the
+// write requests made with it.
+func (c *writeCounter) clusterConfig() (*rest.Config, error) {
+	cfg, err := GetTestClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			switch req.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+				c.kube.Add(1)
+			}
+			return rt.RoundTrip(req)
+		})
+	})
+	return cfg, nil
+}
+
+// roundTripperFunc is a function implementing http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// reportWrites reports the average number of write requests per operation.
+func (c *writeCounter) reportWrites(b *testing.B) {
+	b.ReportMetric(float64(c.client.Load())/float64(b.N), "writes/op")
+	b.ReportMetric(float64(c.kube.Load())/float64(b.N), "kube-writes/op")
+}
+
+// newBenchmarkRelease returns a synthetic HelmRelease with the given name,
+// targeting a dedicated namespace in the test environment to prevent the
+// resources of the releases from conflicting with each other.
+func newBenchmarkRelease(b *testing.B, namespace, name string) *v2.HelmRelease {
+	b.Helper()
+
+	ns, err := testEnv.CreateNamespace(context.TODO(), name)
+	if err != nil {
+		b.Fatalf("failed to create namespace: %v", err)
+	}
+	b.Cleanup(func() {
+		_ = testEnv.Delete(context.TODO(), ns)
+	})
+
+	return &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  namespace,
+			Finalizers: []string{v2.HelmReleaseFinalizer},
+		},
+		Spec: v2.HelmReleaseSpec{
+			ChartRef: &v2.CrossNamespaceSourceReference{
+				Kind: sourcev1.HelmChartKind,
+				Name: "bench",
+			},
+			Interval:         metav1.Duration{Duration: time.Minute},
+			TargetNamespace:  ns.Name,
+			StorageNamespace: ns.Name,
+			Install: &v2.Install{
+				DisableWait: true,
+			},
+			Upgrade: &v2.Upgrade{
+				DisableWait: true,
+			},
+		},
+	}
+}
+
+// newBenchmarkReconciler returns a HelmReleaseReconciler for the given
+// objects, with a HelmChart named "bench" in the given namespace.
+func newBenchmarkReconciler(b *testing.B, namespace string, counter *writeCounter,
+	objs ...client.Object) *HelmReleaseReconciler {
+	b.Helper()
+
+	if err := (&feathelper.FeatureGates{}).SupportedFeatures(features.FeatureGates()); err != nil {
+		b.Fatalf("failed to initialize feature gates: %v", err)
+	}
+
+	chartArtifact, err := testutil.SaveChartAsArtifact(testutil.BuildChart(), digest.SHA256, testServer.URL(), testServer.Root())
+	if err != nil {
+		b.Fatalf("failed to save chart artifact: %v", err)
+	}
+	chart := &sourcev1.HelmChart{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "bench",
+			Namespace:  namespace,
+			Generation: 1,
+		},
+		Status: sourcev1.HelmChartStatus{
+			ObservedGeneration: 1,
+			Artifact:           chartArtifact,
+			Conditions: []metav1.Condition{
+				{
+					Type:   meta.ReadyCondition,
+					Status: metav1.ConditionTrue,
+				},
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(NewTestScheme()).
+		WithStatusSubresource(&v2.HelmRelease{}).
+		WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
+		WithObjects(append(objs, chart)...).
+		WithInterceptorFuncs(counter.interceptorFuncs()).
+		Build()
+
+	return &HelmReleaseReconciler{
+		Client:           c,
+		APIReader:        c,
+		GetClusterConfig: counter.clusterConfig,
+		// Discard the events, as the number of events depends on b.N.
+		EventRecorder: &record.FakeRecorder{},
+	}
+}
+
+// reconcileBenchmarkRelease reconciles the release of the given object, and
+// fails the benchmark if the release did not become ready.
+func reconcileBenchmarkRelease(b *testing.B, r *HelmReleaseReconciler, obj *v2.HelmRelease) {
+	b.Helper()
+
+	if _, err := r.reconcileRelease(context.TODO(), patch.NewSerialPatcher(obj, r.Client), obj); err != nil {
+		b.Fatalf("failed to reconcile release: %v", err)
+	}
+	if !conditions.IsReady(obj) {
+		b.Fatalf("release is not ready: %s", conditions.GetMessage(obj, meta.ReadyCondition))
+	}
+}
+
+// BenchmarkHelmReleaseReconciler_install measures the installation of N
+// synthetic HelmReleases, reporting the throughput, the number of API writes
+// and the memory allocations per release.
+//
+// Run with:
+//
+//	go test ./internal/controller/ -run=^$ -bench=BenchmarkHelmReleaseReconciler -benchmem
+func BenchmarkHelmReleaseReconciler_install(b *testing.B) {
+	const namespace = "bench"
+
+	objs := make([]*v2.HelmRelease, b.N)
+	clientObjs := make([]client.Object, b.N)
+	for i := range objs {
+		objs[i] = newBenchmarkRelease(b, namespace, fmt.Sprintf("bench-install-%d", i))
+		clientObjs[i] = objs[i]
+	}
+
+	counter := &writeCounter{}
+	r := newBenchmarkReconciler(b, namespace, counter, clientObjs...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for _, obj := range objs {
+		reconcileBenchmarkRelease(b, r, obj)
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "releases/s")
+	counter.reportWrites(b)
+}
+
+// BenchmarkHelmReleaseReconciler_noop measures the reconciliation of an
+// installed HelmRelease which does not require any Helm action, which is the
+// most common reconciliation in a steady-state cluster.
+func BenchmarkHelmReleaseReconciler_noop(b *testing.B) {
+	const namespace = "bench"
+
+	obj := newBenchmarkRelease(b, namespace, "bench-noop")
+	counter := &writeCounter{}
+	r := newBenchmarkReconciler(b, namespace, counter, obj)
+
+	// Install the release before measuring.
+	reconcileBenchmarkRelease(b, r, obj)
+	counter.client.Store(0)
+	counter.kube.Store(0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		reconcileBenchmarkRelease(b, r, obj)
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "reconciles/s")
+	counter.reportWrites(b)
+}
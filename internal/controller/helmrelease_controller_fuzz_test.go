@@ -91,7 +91,8 @@ other: values
 			&hc,
 		}
 
-		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v2.HelmRelease{}).WithObjects(resources...).Build()
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).WithObjects(resources...).Build()
 		r := &HelmReleaseReconciler{
 			Client:        c,
 			EventRecorder: &DummyRecorder{},
@@ -20,25 +20,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
-	. "github.com/onsi/gomega"
-	"github.com/opencontainers/go-digest"
-	"github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
 	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	helmtime "github.com/jessesimpson36/helm/v4/pkg/time"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
@@ -61,9 +71,11 @@ import (
 	"github.com/fluxcd/helm-controller/internal/action"
 	"github.com/fluxcd/helm-controller/internal/features"
 	"github.com/fluxcd/helm-controller/internal/kube"
+	"github.com/fluxcd/helm-controller/internal/loader"
 	"github.com/fluxcd/helm-controller/internal/postrender"
 	intreconcile "github.com/fluxcd/helm-controller/internal/reconcile"
 	"github.com/fluxcd/helm-controller/internal/release"
+	"github.com/fluxcd/helm-controller/internal/retention"
 	"github.com/fluxcd/helm-controller/internal/testutil"
 )
 
@@ -94,7 +106,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 				Namespace: "mock",
 			},
 			Spec: v2.HelmReleaseSpec{
-				DependsOn: []meta.NamespacedObjectReference{
+				DependsOn: []v2.DependencyReference{
 					{
 						Name: "dependency",
 					},
@@ -106,6 +118,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(dependency, obj).
 				Build(),
 			EventRecorder:     record.NewFakeRecorder(32),
@@ -140,6 +153,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(obj).
 				Build(),
 		}
@@ -171,6 +185,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(obj).
 				Build(),
 			EventRecorder: record.NewFakeRecorder(32),
@@ -230,6 +245,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(chart, obj).
 				Build(),
 		}
@@ -286,6 +302,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(chart, obj).
 				Build(),
 		}
@@ -331,7 +348,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 				Namespace: "mock",
 			},
 			Spec: v2.HelmReleaseSpec{
-				ValuesFrom: []meta.ValuesReference{
+				ValuesFrom: []v2.ValuesReference{
 					{
 						Kind: "Secret",
 						Name: "missing",
@@ -347,6 +364,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(chart, obj).
 				Build(),
 			EventRecorder: record.NewFakeRecorder(32),
@@ -399,6 +417,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(chart, obj).
 				Build(),
 			requeueDependency: 10 * time.Second,
@@ -415,6 +434,296 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 		}))
 	})
 
+	t.Run("reports incompatible chart", func(t *testing.T) {
+		g := NewWithT(t)
+
+		chartMock := testutil.BuildChart()
+		chartMock.Metadata.Type = "library"
+		chartArtifact, err := testutil.SaveChartAsArtifact(chartMock, digest.SHA256, testServer.URL(), testServer.Root())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		chart := &sourcev1.HelmChart{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "chart",
+				Namespace:  "mock",
+				Generation: 1,
+			},
+			Status: sourcev1.HelmChartStatus{
+				ObservedGeneration: 1,
+				Artifact:           chartArtifact,
+				Conditions: []metav1.Condition{
+					{
+						Type:   meta.ReadyCondition,
+						Status: metav1.ConditionTrue,
+					},
+				},
+			},
+		}
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "release",
+				Namespace: "mock",
+			},
+			Status: v2.HelmReleaseStatus{
+				HelmChart: "mock/chart",
+			},
+		}
+
+		c := fake.NewClientBuilder().
+			WithScheme(NewTestScheme()).
+			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
+			WithObjects(chart, obj).
+			Build()
+
+		r := &HelmReleaseReconciler{
+			Client:        c,
+			APIReader:     c,
+			EventRecorder: record.NewFakeRecorder(32),
+		}
+
+		res, err := r.reconcileRelease(context.TODO(), patch.NewSerialPatcher(obj, r.Client), obj)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(errors.Is(err, reconcile.TerminalError(nil))).To(BeTrue())
+		g.Expect(res.IsZero()).To(BeTrue())
+
+		g.Expect(obj.Status.Conditions).To(conditions.MatchConditions([]metav1.Condition{
+			*conditions.TrueCondition(meta.StalledCondition, v2.IncompatibleChartReason, "is a library chart"),
+			*conditions.FalseCondition(meta.ReadyCondition, v2.IncompatibleChartReason, "is a library chart"),
+		}))
+	})
+
+	t.Run("reports chart exceeding size limits", func(t *testing.T) {
+		g := NewWithT(t)
+
+		chartMock := testutil.BuildChart()
+		chartArtifact, err := testutil.SaveChartAsArtifact(chartMock, digest.SHA256, testServer.URL(), testServer.Root())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		chart := &sourcev1.HelmChart{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "chart",
+				Namespace:  "mock",
+				Generation: 1,
+			},
+			Status: sourcev1.HelmChartStatus{
+				ObservedGeneration: 1,
+				Artifact:           chartArtifact,
+				Conditions: []metav1.Condition{
+					{
+						Type:   meta.ReadyCondition,
+						Status: metav1.ConditionTrue,
+					},
+				},
+			},
+		}
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "release",
+				Namespace: "mock",
+			},
+			Status: v2.HelmReleaseStatus{
+				HelmChart: "mock/chart",
+			},
+		}
+
+		c := fake.NewClientBuilder().
+			WithScheme(NewTestScheme()).
+			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
+			WithObjects(chart, obj).
+			Build()
+
+		r := &HelmReleaseReconciler{
+			Client:          c,
+			APIReader:       c,
+			EventRecorder:   record.NewFakeRecorder(32),
+			maxArtifactSize: 1,
+		}
+
+		res, err := r.reconcileRelease(context.TODO(), patch.NewSerialPatcher(obj, r.Client), obj)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(errors.Is(err, reconcile.TerminalError(nil))).To(BeTrue())
+		g.Expect(res.IsZero()).To(BeTrue())
+
+		g.Expect(obj.Status.Conditions).To(conditions.MatchConditions([]metav1.Condition{
+			*conditions.TrueCondition(meta.StalledCondition, v2.ChartTooLargeReason, "maximum artifact size"),
+			*conditions.FalseCondition(meta.ReadyCondition, v2.ChartTooLargeReason, "maximum artifact size"),
+		}))
+	})
+
+	t.Run("waits for resources to be ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ns, err := testEnv.CreateNamespace(context.TODO(), "wait-for")
+		g.Expect(err).ToNot(HaveOccurred())
+		t.Cleanup(func() {
+			_ = testEnv.Delete(context.TODO(), ns)
+		})
+
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "exists",
+				Namespace: ns.Name,
+			},
+			Data: map[string]string{"ready": "false"},
+		}
+		g.Expect(testEnv.Create(context.TODO(), cm)).To(Succeed())
+
+		// Allow the impersonated ServiceAccount to read the ConfigMaps.
+		const serviceAccount = "wait-for"
+		g.Expect(testEnv.Create(context.TODO(), &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: serviceAccount, Namespace: ns.Name},
+		})).To(Succeed())
+		g.Expect(testEnv.Create(context.TODO(), &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: serviceAccount, Namespace: ns.Name},
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{""},
+				Resources: []string{"configmaps"},
+				Verbs:     []string{"get"},
+			}},
+		})).To(Succeed())
+		g.Expect(testEnv.Create(context.TODO(), &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: serviceAccount, Namespace: ns.Name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: serviceAccount},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: serviceAccount, Namespace: ns.Name}},
+		})).To(Succeed())
+
+		chartMock := testutil.BuildChart()
+		chartArtifact, err := testutil.SaveChartAsArtifact(chartMock, digest.SHA256, testServer.URL(), testServer.Root())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		chart := &sourcev1.HelmChart{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "chart",
+				Namespace:  ns.Name,
+				Generation: 1,
+			},
+			Status: sourcev1.HelmChartStatus{
+				ObservedGeneration: 1,
+				Artifact:           chartArtifact,
+				Conditions: []metav1.Condition{
+					{
+						Type:   meta.ReadyCondition,
+						Status: metav1.ConditionTrue,
+					},
+				},
+			},
+		}
+
+		tests := []struct {
+			name             string
+			waitFor          v2.WaitForReference
+			noServiceAccount bool
+			wantMsg          string
+			wantStalled      bool
+		}{
+			{
+				name: "missing resource",
+				waitFor: v2.WaitForReference{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       "missing",
+				},
+				wantMsg: fmt.Sprintf("unable to get 'ConfigMap/%s/missing'", ns.Name),
+			},
+			{
+				name: "resource not ready according to expression",
+				waitFor: v2.WaitForReference{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       "exists",
+					Namespace:  ns.Name,
+					ReadyExpr:  "dep.data.ready == 'true'",
+				},
+				wantMsg: fmt.Sprintf("'ConfigMap/%s/exists' is not ready according to expression", ns.Name),
+			},
+			{
+				name: "without service account",
+				waitFor: v2.WaitForReference{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       "exists",
+				},
+				noServiceAccount: true,
+				wantMsg:          "waiting for resources requires a service account to be impersonated",
+				wantStalled:      true,
+			},
+			{
+				name: "cross-namespace reference",
+				waitFor: v2.WaitForReference{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       "exists",
+					Namespace:  "other",
+				},
+				wantMsg:     "cross-namespace references are not allowed",
+				wantStalled: true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				g := NewWithT(t)
+
+				obj := &v2.HelmRelease{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "release",
+						Namespace: ns.Name,
+					},
+					Spec: v2.HelmReleaseSpec{
+						ServiceAccountName: serviceAccount,
+						Wait: &v2.Wait{
+							For: []v2.WaitForReference{tt.waitFor},
+						},
+					},
+					Status: v2.HelmReleaseStatus{
+						HelmChart: ns.Name + "/chart",
+					},
+				}
+				if tt.noServiceAccount {
+					obj.Spec.ServiceAccountName = ""
+				}
+
+				c := fake.NewClientBuilder().
+					WithScheme(NewTestScheme()).
+					WithStatusSubresource(&v2.HelmRelease{}).
+					WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
+					WithObjects(chart.DeepCopy(), obj).
+					Build()
+
+				r := &HelmReleaseReconciler{
+					Client:            c,
+					APIReader:         c,
+					GetClusterConfig:  GetTestClusterConfig,
+					EventRecorder:     record.NewFakeRecorder(32),
+					requeueDependency: 10 * time.Second,
+				}
+
+				res, err := r.reconcileRelease(context.TODO(), patch.NewSerialPatcher(obj, c), obj)
+				if tt.wantStalled {
+					g.Expect(errors.Is(err, reconcile.TerminalError(nil))).To(BeTrue())
+					g.Expect(res.IsZero()).To(BeTrue())
+
+					g.Expect(obj.Status.Conditions).To(conditions.MatchConditions([]metav1.Condition{
+						*conditions.TrueCondition(meta.ReconcilingCondition, meta.ProgressingReason, ""),
+						*conditions.TrueCondition(meta.StalledCondition, v2.DependencyNotReadyReason, tt.wantMsg),
+						*conditions.FalseCondition(meta.ReadyCondition, v2.DependencyNotReadyReason, tt.wantMsg),
+					}))
+					return
+				}
+				g.Expect(err).To(Equal(errWaitForDependency))
+				g.Expect(res.RequeueAfter).To(Equal(r.requeueDependency))
+
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions([]metav1.Condition{
+					*conditions.TrueCondition(meta.ReconcilingCondition, meta.ProgressingReason, ""),
+					*conditions.FalseCondition(meta.ReadyCondition, v2.DependencyNotReadyReason, tt.wantMsg),
+				}))
+			})
+		}
+	})
+
 	t.Run("attempts to adopt v2beta1 release state", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -487,6 +796,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 		c := fake.NewClientBuilder().
 			WithScheme(NewTestScheme()).
 			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 			WithObjects(chart, obj).
 			Build()
 
@@ -568,6 +878,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 		c := fake.NewClientBuilder().
 			WithScheme(NewTestScheme()).
 			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 			WithObjects(chart, obj).
 			Build()
 
@@ -644,6 +955,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 		c := fake.NewClientBuilder().
 			WithScheme(NewTestScheme()).
 			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 			WithObjects(chart, obj).
 			Build()
 
@@ -716,6 +1028,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 		c := fake.NewClientBuilder().
 			WithScheme(NewTestScheme()).
 			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 			WithObjects(chart, obj).
 			Build()
 
@@ -806,6 +1119,7 @@ func TestHelmReleaseReconciler_reconcileRelease(t *testing.T) {
 		c := fake.NewClientBuilder().
 			WithScheme(NewTestScheme()).
 			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 			WithObjects(chart, obj).
 			Build()
 
@@ -890,6 +1204,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromHelmChartSource(t *testing.T)
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(obj).
 				Build(),
 		}
@@ -927,6 +1242,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromHelmChartSource(t *testing.T)
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(obj).
 				Build(),
 			EventRecorder: record.NewFakeRecorder(32),
@@ -962,6 +1278,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromHelmChartSource(t *testing.T)
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(obj).
 				Build(),
 			EventRecorder: record.NewFakeRecorder(32),
@@ -1021,6 +1338,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromHelmChartSource(t *testing.T)
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(chart, obj).
 				Build(),
 		}
@@ -1079,6 +1397,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromHelmChartSource(t *testing.T)
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(chart, obj).
 				Build(),
 			requeueDependency: 10 * time.Second,
@@ -1159,6 +1478,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromHelmChartSource(t *testing.T)
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(chart, sharedChart, obj).
 				Build(),
 			requeueDependency: 10 * time.Second,
@@ -1283,6 +1603,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromHelmChartSource(t *testing.T)
 		c := fake.NewClientBuilder().
 			WithScheme(NewTestScheme()).
 			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 			WithObjects(hc, obj).
 			Build()
 
@@ -1358,6 +1679,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(obj).
 				Build(),
 		}
@@ -1394,6 +1716,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(obj).
 				Build(),
 			EventRecorder: record.NewFakeRecorder(32),
@@ -1429,6 +1752,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(obj).
 				Build(),
 			EventRecorder: record.NewFakeRecorder(32),
@@ -1488,6 +1812,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(ocirepo, obj).
 				Build(),
 		}
@@ -1537,7 +1862,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 					Name:      "ocirepo",
 					Namespace: "mock",
 				},
-				ValuesFrom: []meta.ValuesReference{
+				ValuesFrom: []v2.ValuesReference{
 					{
 						Kind: "Secret",
 						Name: "missing",
@@ -1550,6 +1875,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(ocirepo, obj).
 				Build(),
 			EventRecorder: record.NewFakeRecorder(32),
@@ -1608,6 +1934,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(ocirepo, obj).
 				Build(),
 			requeueDependency: 10 * time.Second,
@@ -1688,6 +2015,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(chart, ocirepo, obj).
 				Build(),
 			requeueDependency: 10 * time.Second,
@@ -1752,6 +2080,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 			Client: fake.NewClientBuilder().
 				WithScheme(NewTestScheme()).
 				WithStatusSubresource(&v2.HelmRelease{}).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 				WithObjects(ocirepo, obj).
 				Build(),
 			GetClusterConfig: GetTestClusterConfig,
@@ -1843,6 +2172,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 		c := fake.NewClientBuilder().
 			WithScheme(NewTestScheme()).
 			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 			WithObjects(ocirepo, obj).
 			Build()
 
@@ -1936,6 +2266,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 		c := fake.NewClientBuilder().
 			WithScheme(NewTestScheme()).
 			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 			WithObjects(ocirepo, obj).
 			Build()
 
@@ -2013,6 +2344,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 		c := fake.NewClientBuilder().
 			WithScheme(NewTestScheme()).
 			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 			WithObjects(ocirepo, obj).
 			Build()
 
@@ -2132,6 +2464,7 @@ func TestHelmReleaseReconciler_reconcileReleaseFromOCIRepositorySource(t *testin
 		c := fake.NewClientBuilder().
 			WithScheme(NewTestScheme()).
 			WithStatusSubresource(&v2.HelmRelease{}).
+			WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
 			WithObjects(hc, ocirepo, obj).
 			Build()
 
@@ -2471,112 +2804,262 @@ func TestHelmReleaseReconciler_reconcileReleaseDeletion(t *testing.T) {
 		g.Expect(obj.Status.StorageNamespace).ToNot(BeEmpty())
 	})
 
-	t.Run("skip uninstalling Helm release when ServiceAccount is missing", func(t *testing.T) {
+	t.Run("orphans Helm release when remote cluster is gone after timeout", func(t *testing.T) {
 		g := NewWithT(t)
 
-		// Create a test namespace for storing the Helm release mock.
-		ns, err := testEnv.CreateNamespace(context.TODO(), "reconcile-release-deletion")
-		g.Expect(err).ToNot(HaveOccurred())
-		t.Cleanup(func() {
-			_ = testEnv.Delete(context.TODO(), ns)
+		mockErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}
+		c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*corev1.Secret); ok {
+					return mockErr
+				}
+				return client.Get(ctx, key, obj, opts...)
+			},
 		})
 
-		// Create a test Helm release storage mock.
-		rls := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
-			Name:      "reconcile-delete",
-			Namespace: ns.Name,
-			Version:   1,
-			Chart:     testutil.BuildChart(testutil.ChartWithTestHook()),
-			Status:    helmrelease.StatusDeployed,
-		})
+		recorder := record.NewFakeRecorder(32)
+		r := &HelmReleaseReconciler{
+			Client:        c.Build(),
+			EventRecorder: recorder,
+		}
 
 		obj := &v2.HelmRelease{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:              "reconcile-delete",
-				Namespace:         ns.Name,
-				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				Namespace:         "mock",
+				DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			},
+			Spec: v2.HelmReleaseSpec{
+				KubeConfig: &meta.KubeConfigReference{
+					SecretRef: meta.SecretKeyReference{
+						Name: "kubeconfig",
+					},
+				},
+				Uninstall: &v2.Uninstall{
+					OrphanTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+				},
 			},
 			Status: v2.HelmReleaseStatus{
-				StorageNamespace: ns.Name,
+				StorageNamespace: "mock",
 				History: v2.Snapshots{
-					release.ObservedToSnapshot(release.ObserveRelease(rls)),
+					{Name: "reconcile-delete", Namespace: "mock", Version: 1},
 				},
 			},
 		}
 
-		r := &HelmReleaseReconciler{
-			Client:           testEnv.Client,
-			APIReader:        testEnv.Client,
-			GetClusterConfig: GetTestClusterConfig,
-		}
-
-		// Store the Helm release mock in the test namespace.
-		getter, err := r.buildRESTClientGetter(context.TODO(), obj)
-		g.Expect(err).ToNot(HaveOccurred())
-
-		cfg, err := action.NewConfigFactory(getter, action.WithStorage(helmdriver.SecretsDriverName, obj.Status.StorageNamespace))
-		g.Expect(err).ToNot(HaveOccurred())
-
-		store := helmstorage.Init(cfg.Driver)
-		g.Expect(store.Create(rls)).To(Succeed())
-
-		// Reconcile the actual deletion of the Helm release.
-		obj.Spec.ServiceAccountName = "missing-sa"
-		err = r.reconcileReleaseDeletion(context.TODO(), obj)
+		err := r.reconcileReleaseDeletion(context.TODO(), obj)
 		g.Expect(err).ToNot(HaveOccurred())
 
-		// Verify status of Helm release has not been updated.
-		g.Expect(obj.Status.StorageNamespace).ToNot(BeEmpty())
-		g.Expect(obj.Status.History.Latest()).ToNot(BeNil())
+		// Verify status of Helm release has been truncated.
+		g.Expect(obj.Status.StorageNamespace).To(BeEmpty())
+		g.Expect(obj.Status.History).To(BeNil())
 
-		// Verify Helm release has not been uninstalled.
-		_, err = store.History(rls.Name)
-		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(v2.OrphanedReason)))
 	})
 
-	t.Run("error when ServiceAccount existence check fails", func(t *testing.T) {
+	t.Run("does not orphan Helm release after timeout when remote cluster is reachable", func(t *testing.T) {
 		g := NewWithT(t)
 
-		var (
-			serviceAccount = "missing-sa"
-			namespace      = "mock"
-			mockErr        = errors.New("mock error")
-		)
-
+		mockErr := errors.New("mock error")
 		c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
 			Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-				if key.Name == serviceAccount && key.Namespace == namespace {
+				if _, ok := obj.(*corev1.Secret); ok {
 					return mockErr
 				}
 				return client.Get(ctx, key, obj, opts...)
 			},
 		})
 
+		recorder := record.NewFakeRecorder(32)
 		r := &HelmReleaseReconciler{
-			Client:           c.Build(),
-			GetClusterConfig: GetTestClusterConfig,
+			Client:        c.Build(),
+			EventRecorder: recorder,
 		}
 
 		obj := &v2.HelmRelease{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:              "reconcile-delete",
-				Namespace:         namespace,
-				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				Namespace:         "mock",
+				DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-time.Hour)},
 			},
 			Spec: v2.HelmReleaseSpec{
-				ServiceAccountName: serviceAccount,
+				KubeConfig: &meta.KubeConfigReference{
+					SecretRef: meta.SecretKeyReference{
+						Name: "kubeconfig",
+					},
+				},
+				Uninstall: &v2.Uninstall{
+					OrphanTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+				},
 			},
 			Status: v2.HelmReleaseStatus{
-				StorageNamespace: namespace,
+				StorageNamespace: "mock",
 			},
 		}
 
-		// Reconcile the actual deletion of the Helm release.
 		err := r.reconcileReleaseDeletion(context.TODO(), obj)
 		g.Expect(errors.Is(err, mockErr)).To(BeTrue())
-		g.Expect(obj.Status.Conditions).To(conditions.MatchConditions([]metav1.Condition{
-			*conditions.FalseCondition(meta.ReadyCondition, v2.UninstallFailedReason,
-				"failed to confirm ServiceAccount '%s' can be used to uninstall release", serviceAccount),
+
+		// Verify status of Helm release has not been truncated.
+		g.Expect(obj.Status.StorageNamespace).ToNot(BeEmpty())
+		g.Expect(conditions.GetReason(obj, meta.ReadyCondition)).To(Equal(v2.UninstallFailedReason))
+		g.Expect(conditions.GetMessage(obj, meta.ReadyCondition)).To(ContainSubstring("refusing to orphan"))
+
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(v2.UninstallFailedReason)))
+	})
+
+	t.Run("error when remote cluster is gone within timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mockErr := errors.New("mock error")
+		c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*corev1.Secret); ok {
+					return mockErr
+				}
+				return client.Get(ctx, key, obj, opts...)
+			},
+		})
+
+		r := &HelmReleaseReconciler{
+			Client:        c.Build(),
+			EventRecorder: record.NewFakeRecorder(32),
+		}
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "reconcile-delete",
+				Namespace:         "mock",
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			},
+			Spec: v2.HelmReleaseSpec{
+				KubeConfig: &meta.KubeConfigReference{
+					SecretRef: meta.SecretKeyReference{
+						Name: "kubeconfig",
+					},
+				},
+				Uninstall: &v2.Uninstall{
+					OrphanTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+			Status: v2.HelmReleaseStatus{
+				StorageNamespace: "mock",
+			},
+		}
+
+		err := r.reconcileReleaseDeletion(context.TODO(), obj)
+		g.Expect(errors.Is(err, mockErr)).To(BeTrue())
+
+		// Verify status of Helm release has not been updated.
+		g.Expect(obj.Status.StorageNamespace).ToNot(BeEmpty())
+	})
+
+	t.Run("skip uninstalling Helm release when ServiceAccount is missing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// Create a test namespace for storing the Helm release mock.
+		ns, err := testEnv.CreateNamespace(context.TODO(), "reconcile-release-deletion")
+		g.Expect(err).ToNot(HaveOccurred())
+		t.Cleanup(func() {
+			_ = testEnv.Delete(context.TODO(), ns)
+		})
+
+		// Create a test Helm release storage mock.
+		rls := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+			Name:      "reconcile-delete",
+			Namespace: ns.Name,
+			Version:   1,
+			Chart:     testutil.BuildChart(testutil.ChartWithTestHook()),
+			Status:    helmrelease.StatusDeployed,
+		})
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "reconcile-delete",
+				Namespace:         ns.Name,
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			},
+			Status: v2.HelmReleaseStatus{
+				StorageNamespace: ns.Name,
+				History: v2.Snapshots{
+					release.ObservedToSnapshot(release.ObserveRelease(rls)),
+				},
+			},
+		}
+
+		r := &HelmReleaseReconciler{
+			Client:           testEnv.Client,
+			APIReader:        testEnv.Client,
+			GetClusterConfig: GetTestClusterConfig,
+		}
+
+		// Store the Helm release mock in the test namespace.
+		getter, err := r.buildRESTClientGetter(context.TODO(), obj)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		cfg, err := action.NewConfigFactory(getter, action.WithStorage(helmdriver.SecretsDriverName, obj.Status.StorageNamespace))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		store := helmstorage.Init(cfg.Driver)
+		g.Expect(store.Create(rls)).To(Succeed())
+
+		// Reconcile the actual deletion of the Helm release.
+		obj.Spec.ServiceAccountName = "missing-sa"
+		err = r.reconcileReleaseDeletion(context.TODO(), obj)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// Verify status of Helm release has not been updated.
+		g.Expect(obj.Status.StorageNamespace).ToNot(BeEmpty())
+		g.Expect(obj.Status.History.Latest()).ToNot(BeNil())
+
+		// Verify Helm release has not been uninstalled.
+		_, err = store.History(rls.Name)
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("error when ServiceAccount existence check fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var (
+			serviceAccount = "missing-sa"
+			namespace      = "mock"
+			mockErr        = errors.New("mock error")
+		)
+
+		c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if key.Name == serviceAccount && key.Namespace == namespace {
+					return mockErr
+				}
+				return client.Get(ctx, key, obj, opts...)
+			},
+		})
+
+		r := &HelmReleaseReconciler{
+			Client:           c.Build(),
+			GetClusterConfig: GetTestClusterConfig,
+		}
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "reconcile-delete",
+				Namespace:         namespace,
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			},
+			Spec: v2.HelmReleaseSpec{
+				ServiceAccountName: serviceAccount,
+			},
+			Status: v2.HelmReleaseStatus{
+				StorageNamespace: namespace,
+			},
+		}
+
+		// Reconcile the actual deletion of the Helm release.
+		err := r.reconcileReleaseDeletion(context.TODO(), obj)
+		g.Expect(errors.Is(err, mockErr)).To(BeTrue())
+		g.Expect(obj.Status.Conditions).To(conditions.MatchConditions([]metav1.Condition{
+			*conditions.FalseCondition(meta.ReadyCondition, v2.UninstallFailedReason,
+				"failed to confirm ServiceAccount '%s' can be used to uninstall release", serviceAccount),
 		}))
 
 		// Verify status of Helm release has not been updated.
@@ -2677,6 +3160,51 @@ func TestHelmReleaseReconciler_reconcileReleaseDeletion(t *testing.T) {
 	})
 }
 
+func Test_isUnreachableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "connection refused",
+			err:  fmt.Errorf("uninstall failed: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}),
+			want: true,
+		},
+		{
+			name: "DNS lookup failure",
+			err:  &url.Error{Op: "Get", URL: "https://cluster.example.com", Err: &net.DNSError{Err: "no such host", Name: "cluster.example.com"}},
+			want: true,
+		},
+		{
+			name: "deadline exceeded",
+			err:  fmt.Errorf("uninstall failed: %w", context.DeadlineExceeded),
+			want: true,
+		},
+		{
+			name: "service unavailable",
+			err:  apierrors.NewServiceUnavailable("mock"),
+			want: true,
+		},
+		{
+			name: "forbidden",
+			err:  apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "mock", errors.New("mock")),
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  errors.New("mock error"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isUnreachableError(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestHelmReleaseReconciler_reconcileChartTemplate(t *testing.T) {
 	t.Run("attempts to reconcile chart template", func(t *testing.T) {
 		g := NewWithT(t)
@@ -2819,7 +3347,7 @@ func TestHelmReleaseReconciler_checkDependencies(t *testing.T) {
 					Namespace: "some-namespace",
 				},
 				Spec: v2.HelmReleaseSpec{
-					DependsOn: []meta.NamespacedObjectReference{
+					DependsOn: []v2.DependencyReference{
 						{
 							Name: "dependency-1",
 						},
@@ -2870,7 +3398,7 @@ func TestHelmReleaseReconciler_checkDependencies(t *testing.T) {
 					Namespace: "some-namespace",
 				},
 				Spec: v2.HelmReleaseSpec{
-					DependsOn: []meta.NamespacedObjectReference{
+					DependsOn: []v2.DependencyReference{
 						{
 							Name: "dependency-1",
 						},
@@ -2894,7 +3422,46 @@ func TestHelmReleaseReconciler_checkDependencies(t *testing.T) {
 			},
 			expect: func(g *WithT, err error) {
 				g.Expect(err).To(HaveOccurred())
-				g.Expect(err.Error()).To(ContainSubstring("is not ready"))
+				g.Expect(err.Error()).To(ContainSubstring("generation 2 has not been observed yet"))
+			},
+		},
+		{
+			name: "error on dependency which has not released the last attempted chart version",
+			obj: &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dependant",
+					Namespace: "some-namespace",
+				},
+				Spec: v2.HelmReleaseSpec{
+					DependsOn: []v2.DependencyReference{
+						{
+							Name: "dependency-1",
+						},
+					},
+				},
+			},
+			objects: []client.Object{
+				&v2.HelmRelease{
+					ObjectMeta: metav1.ObjectMeta{
+						Generation: 1,
+						Name:       "dependency-1",
+						Namespace:  "some-namespace",
+					},
+					Status: v2.HelmReleaseStatus{
+						ObservedGeneration:    1,
+						LastAttemptedRevision: "4.1.0",
+						Conditions: []metav1.Condition{
+							{Type: meta.ReadyCondition, Status: metav1.ConditionTrue},
+						},
+						History: v2.Snapshots{
+							{ChartVersion: "4.0.0"},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("chart version '4.1.0' has not been released yet"))
 			},
 		},
 		{
@@ -2905,7 +3472,7 @@ func TestHelmReleaseReconciler_checkDependencies(t *testing.T) {
 					Namespace: "some-namespace",
 				},
 				Spec: v2.HelmReleaseSpec{
-					DependsOn: []meta.NamespacedObjectReference{
+					DependsOn: []v2.DependencyReference{
 						{
 							Name: "dependency-1",
 						},
@@ -2940,7 +3507,7 @@ func TestHelmReleaseReconciler_checkDependencies(t *testing.T) {
 					Namespace: "some-namespace",
 				},
 				Spec: v2.HelmReleaseSpec{
-					DependsOn: []meta.NamespacedObjectReference{
+					DependsOn: []v2.DependencyReference{
 						{
 							Name: "dependency-1",
 						},
@@ -2965,154 +3532,251 @@ func TestHelmReleaseReconciler_checkDependencies(t *testing.T) {
 			},
 		},
 		{
-			name: "error on missing dependency",
+			name: "dependency matching version constraint",
 			obj: &v2.HelmRelease{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "dependant",
 					Namespace: "some-namespace",
 				},
 				Spec: v2.HelmReleaseSpec{
-					DependsOn: []meta.NamespacedObjectReference{
+					DependsOn: []v2.DependencyReference{
 						{
-							Name: "dependency-1",
+							Name:      "dependency-1",
+							Namespace: "some-other-namespace",
+							Version:   ">=4.0.0 <5.0.0",
+						},
+					},
+				},
+			},
+			objects: []client.Object{
+				&v2.HelmRelease{
+					ObjectMeta: metav1.ObjectMeta{
+						Generation: 1,
+						Name:       "dependency-1",
+						Namespace:  "some-other-namespace",
+					},
+					Status: v2.HelmReleaseStatus{
+						ObservedGeneration: 1,
+						Conditions: []metav1.Condition{
+							{Type: meta.ReadyCondition, Status: metav1.ConditionTrue},
+						},
+						History: v2.Snapshots{
+							{ChartVersion: "4.10.1"},
 						},
 					},
 				},
 			},
 			expect: func(g *WithT, err error) {
-				g.Expect(err).To(HaveOccurred())
-				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				g.Expect(err).ToNot(HaveOccurred())
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			g := NewWithT(t)
-
-			c := fake.NewClientBuilder().WithScheme(NewTestScheme())
-			if len(tt.objects) > 0 {
-				c.WithObjects(tt.objects...)
-			}
-
-			r := &HelmReleaseReconciler{
-				Client:    c.Build(),
-				APIReader: c.Build(),
-			}
-
-			err := r.checkDependencies(context.TODO(), tt.obj)
-			tt.expect(g, err)
-		})
-	}
-}
-
-func TestHelmReleaseReconciler_adoptLegacyRelease(t *testing.T) {
-	tests := []struct {
-		name                      string
-		releases                  func(namespace string) []*helmrelease.Release
-		spec                      func(spec *v2.HelmReleaseSpec)
-		status                    v2.HelmReleaseStatus
-		expectHistory             func(releases []*helmrelease.Release) v2.Snapshots
-		expectLastReleaseRevision int
-		wantErr                   bool
-	}{
 		{
-			name: "adopts last release revision",
-			releases: func(namespace string) []*helmrelease.Release {
-				return []*helmrelease.Release{
-					testutil.BuildRelease(&helmrelease.MockReleaseOptions{
-						Name:      "orphaned",
-						Namespace: namespace,
-						Version:   6,
-						Chart:     testutil.BuildChart(),
-						Status:    helmrelease.StatusDeployed,
-					}, testutil.ReleaseWithTestHook()),
-				}
-			},
-			spec: func(spec *v2.HelmReleaseSpec) {
-				spec.ReleaseName = "orphaned"
+			name: "error on dependency not matching version constraint",
+			obj: &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dependant",
+					Namespace: "some-namespace",
+				},
+				Spec: v2.HelmReleaseSpec{
+					DependsOn: []v2.DependencyReference{
+						{
+							Name:    "dependency-1",
+							Version: ">=4.0.0 <5.0.0",
+						},
+					},
+				},
 			},
-			status: v2.HelmReleaseStatus{
-				LastReleaseRevision: 6,
+			objects: []client.Object{
+				&v2.HelmRelease{
+					ObjectMeta: metav1.ObjectMeta{
+						Generation: 1,
+						Name:       "dependency-1",
+						Namespace:  "some-namespace",
+					},
+					Status: v2.HelmReleaseStatus{
+						ObservedGeneration: 1,
+						Conditions: []metav1.Condition{
+							{Type: meta.ReadyCondition, Status: metav1.ConditionTrue},
+						},
+						History: v2.Snapshots{
+							{ChartVersion: "3.2.0"},
+						},
+					},
+				},
 			},
-			expectHistory: func(releases []*helmrelease.Release) v2.Snapshots {
-				return v2.Snapshots{
-					release.ObservedToSnapshot(release.ObserveRelease(releases[0])),
-				}
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("chart version '3.2.0' does not match"))
 			},
-			expectLastReleaseRevision: 0,
 		},
 		{
-			name: "includes test hooks if enabled",
-			releases: func(namespace string) []*helmrelease.Release {
-				return []*helmrelease.Release{
-					testutil.BuildRelease(&helmrelease.MockReleaseOptions{
-						Name:      "orphaned-with-hooks",
-						Namespace: namespace,
-						Version:   3,
-						Chart:     testutil.BuildChart(testutil.ChartWithTestHook()),
-						Status:    helmrelease.StatusDeployed,
-					}, testutil.ReleaseWithTestHook()),
-				}
-			},
-			spec: func(spec *v2.HelmReleaseSpec) {
-				spec.ReleaseName = "orphaned-with-hooks"
-				spec.Test = &v2.Test{
-					Enable: true,
-				}
+			name: "error on invalid version constraint",
+			obj: &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dependant",
+					Namespace: "some-namespace",
+				},
+				Spec: v2.HelmReleaseSpec{
+					DependsOn: []v2.DependencyReference{
+						{
+							Name:    "dependency-1",
+							Version: "invalid",
+						},
+					},
+				},
 			},
-			status: v2.HelmReleaseStatus{
-				LastReleaseRevision: 3,
+			objects: []client.Object{
+				&v2.HelmRelease{
+					ObjectMeta: metav1.ObjectMeta{
+						Generation: 1,
+						Name:       "dependency-1",
+						Namespace:  "some-namespace",
+					},
+					Status: v2.HelmReleaseStatus{
+						ObservedGeneration: 1,
+						Conditions: []metav1.Condition{
+							{Type: meta.ReadyCondition, Status: metav1.ConditionTrue},
+						},
+						History: v2.Snapshots{
+							{ChartVersion: "4.0.0"},
+						},
+					},
+				},
 			},
-			expectHistory: func(releases []*helmrelease.Release) v2.Snapshots {
-				snap := release.ObservedToSnapshot(release.ObserveRelease(releases[0]))
-				snap.SetTestHooks(release.TestHooksFromRelease(releases[0]))
-
-				return v2.Snapshots{
-					snap,
-				}
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("invalid version constraint"))
 			},
-			expectLastReleaseRevision: 0,
 		},
 		{
-			name: "non-existing release",
-			spec: func(spec *v2.HelmReleaseSpec) {
-				spec.ReleaseName = "non-existing"
+			name: "dependency matching ready expression",
+			obj: &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dependant",
+					Namespace: "some-namespace",
+				},
+				Spec: v2.HelmReleaseSpec{
+					DependsOn: []v2.DependencyReference{
+						{
+							Name:      "dependency-1",
+							ReadyExpr: `dep.metadata.namespace == self.metadata.namespace && dep.status.conditions.exists(c, c.type == 'Released' && c.status == 'True')`,
+						},
+					},
+				},
 			},
-			status: v2.HelmReleaseStatus{
-				LastReleaseRevision: 2,
+			objects: []client.Object{
+				&v2.HelmRelease{
+					ObjectMeta: metav1.ObjectMeta{
+						Generation: 2,
+						Name:       "dependency-1",
+						Namespace:  "some-namespace",
+					},
+					Status: v2.HelmReleaseStatus{
+						ObservedGeneration: 1,
+						Conditions: []metav1.Condition{
+							{Type: meta.ReadyCondition, Status: metav1.ConditionFalse},
+							{Type: v2.ReleasedCondition, Status: metav1.ConditionTrue},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, err error) {
+				g.Expect(err).ToNot(HaveOccurred())
 			},
-			expectLastReleaseRevision: 2,
-			wantErr:                   true,
 		},
 		{
-			name: "without last release revision",
-			status: v2.HelmReleaseStatus{
-				LastReleaseRevision: 0,
+			name: "error on dependency not matching ready expression",
+			obj: &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dependant",
+					Namespace: "some-namespace",
+				},
+				Spec: v2.HelmReleaseSpec{
+					DependsOn: []v2.DependencyReference{
+						{
+							Name:      "dependency-1",
+							ReadyExpr: `dep.metadata.namespace == self.metadata.namespace && dep.status.conditions.exists(c, c.type == 'Released' && c.status == 'True')`,
+						},
+					},
+				},
 			},
-			expectHistory: func(releases []*helmrelease.Release) v2.Snapshots {
-				return nil
+			objects: []client.Object{
+				&v2.HelmRelease{
+					ObjectMeta: metav1.ObjectMeta{
+						Generation: 2,
+						Name:       "dependency-1",
+						Namespace:  "some-namespace",
+					},
+					Status: v2.HelmReleaseStatus{
+						ObservedGeneration: 1,
+						Conditions: []metav1.Condition{
+							{Type: meta.ReadyCondition, Status: metav1.ConditionFalse},
+							{Type: v2.ReleasedCondition, Status: metav1.ConditionFalse},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("is not ready according to expression"))
 			},
-			expectLastReleaseRevision: 0,
 		},
 		{
-			name: "with existing history",
-			status: v2.HelmReleaseStatus{
-				History: v2.Snapshots{
-					{
-						Name: "something",
+			name: "error on invalid ready expression",
+			obj: &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dependant",
+					Namespace: "some-namespace",
+				},
+				Spec: v2.HelmReleaseSpec{
+					DependsOn: []v2.DependencyReference{
+						{
+							Name:      "dependency-1",
+							ReadyExpr: "dep.status.observedGeneration",
+						},
 					},
 				},
-				LastReleaseRevision: 5,
 			},
-			expectHistory: func(releases []*helmrelease.Release) v2.Snapshots {
-				return v2.Snapshots{
-					{
-						Name: "something",
+			objects: []client.Object{
+				&v2.HelmRelease{
+					ObjectMeta: metav1.ObjectMeta{
+						Generation: 2,
+						Name:       "dependency-1",
+						Namespace:  "some-namespace",
 					},
-				}
+					Status: v2.HelmReleaseStatus{
+						ObservedGeneration: 1,
+						Conditions: []metav1.Condition{
+							{Type: meta.ReadyCondition, Status: metav1.ConditionFalse},
+							{Type: v2.ReleasedCondition, Status: metav1.ConditionTrue},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("invalid expression"))
+			},
+		},
+		{
+			name: "error on missing dependency",
+			obj: &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dependant",
+					Namespace: "some-namespace",
+				},
+				Spec: v2.HelmReleaseSpec{
+					DependsOn: []v2.DependencyReference{
+						{
+							Name: "dependency-1",
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
 			},
-			expectLastReleaseRevision: 5,
 		},
 	}
 
@@ -3120,297 +3784,1750 @@ func TestHelmReleaseReconciler_adoptLegacyRelease(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			// Create a test namespace for storing the Helm release mock.
-			ns, err := testEnv.CreateNamespace(context.TODO(), "adopt-release")
-			g.Expect(err).ToNot(HaveOccurred())
-			t.Cleanup(func() {
-				_ = testEnv.Delete(context.TODO(), ns)
-			})
+			c := fake.NewClientBuilder().WithScheme(NewTestScheme())
+			if len(tt.objects) > 0 {
+				c.WithObjects(tt.objects...)
+			}
 
-			// Mock a HelmRelease object.
-			obj := &v2.HelmRelease{
-				Spec: v2.HelmReleaseSpec{
-					StorageNamespace: ns.Name,
+			r := &HelmReleaseReconciler{
+				Client:    c.Build(),
+				APIReader: c.Build(),
+			}
+
+			err := r.checkDependencies(context.TODO(), tt.obj)
+			tt.expect(g, err)
+		})
+	}
+}
+
+func TestHelmReleaseReconciler_findDependencyCycle(t *testing.T) {
+	newRelease := func(name string, dependsOn ...string) *v2.HelmRelease {
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+		for _, d := range dependsOn {
+			obj.Spec.DependsOn = append(obj.Spec.DependsOn, v2.DependencyReference{Name: d})
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name    string
+		obj     *v2.HelmRelease
+		objects []client.Object
+		want    []string
+	}{
+		{
+			name: "no cycle",
+			obj:  newRelease("a", "b", "c"),
+			objects: []client.Object{
+				newRelease("b", "c"),
+				newRelease("c"),
+			},
+		},
+		{
+			name: "missing dependency",
+			obj:  newRelease("a", "b"),
+		},
+		{
+			name: "self dependency",
+			obj:  newRelease("a", "a"),
+			want: []string{"default/a", "default/a"},
+		},
+		{
+			name: "transitive cycle",
+			obj:  newRelease("a", "b"),
+			objects: []client.Object{
+				newRelease("b", "c"),
+				newRelease("c", "a"),
+			},
+			want: []string{"default/a", "default/b", "default/c", "default/a"},
+		},
+		{
+			name: "cycle not involving the object",
+			obj:  newRelease("a", "b"),
+			objects: []client.Object{
+				newRelease("b", "c"),
+				newRelease("c", "b"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().WithScheme(NewTestScheme())
+			if len(tt.objects) > 0 {
+				c.WithObjects(tt.objects...)
+			}
+
+			r := &HelmReleaseReconciler{
+				Client: c.Build(),
+			}
+
+			got, err := r.findDependencyCycle(context.TODO(), tt.obj)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestHelmReleaseReconciler_findReleaseNameConflict(t *testing.T) {
+	now := metav1.Now()
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+
+	newRelease := func(name, namespace string, created metav1.Time, released bool) *v2.HelmRelease {
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				CreationTimestamp: created,
+			},
+			Spec: v2.HelmReleaseSpec{
+				ReleaseName:      "podinfo",
+				StorageNamespace: "storage",
+			},
+		}
+		if released {
+			obj.Status.History = v2.Snapshots{{Name: "podinfo", Namespace: namespace, Version: 1}}
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name    string
+		obj     *v2.HelmRelease
+		objects []client.Object
+		want    *types.NamespacedName
+	}{
+		{
+			name: "no other HelmRelease",
+			obj:  newRelease("a", "default", now, false),
+		},
+		{
+			name: "other HelmRelease targeting another release",
+			obj:  newRelease("a", "default", now, false),
+			objects: []client.Object{
+				func() *v2.HelmRelease {
+					obj := newRelease("b", "default", earlier, true)
+					obj.Spec.ReleaseName = "other"
+					return obj
+				}(),
+			},
+		},
+		{
+			name: "older HelmRelease targeting the same release",
+			obj:  newRelease("a", "default", now, false),
+			objects: []client.Object{
+				newRelease("b", "other", earlier, false),
+			},
+			want: &types.NamespacedName{Namespace: "other", Name: "b"},
+		},
+		{
+			name: "newer HelmRelease targeting the same release",
+			obj:  newRelease("a", "default", earlier, false),
+			objects: []client.Object{
+				newRelease("b", "other", now, false),
+			},
+		},
+		{
+			name: "newer HelmRelease which has released",
+			obj:  newRelease("a", "default", earlier, false),
+			objects: []client.Object{
+				newRelease("b", "other", now, true),
+			},
+			want: &types.NamespacedName{Namespace: "other", Name: "b"},
+		},
+		{
+			name: "same release name on another cluster",
+			obj:  newRelease("a", "default", now, false),
+			objects: []client.Object{
+				func() *v2.HelmRelease {
+					obj := newRelease("b", "default", earlier, true)
+					obj.Spec.KubeConfig = &meta.KubeConfigReference{SecretRef: meta.SecretKeyReference{Name: "remote"}}
+					return obj
+				}(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().
+				WithScheme(NewTestScheme()).
+				WithIndex(&v2.HelmRelease{}, v2.ReleaseNameIndexKey, indexReleaseName).
+				WithObjects(append(tt.objects, tt.obj)...).
+				Build()
+
+			r := &HelmReleaseReconciler{
+				Client: c,
+			}
+
+			got, err := r.findReleaseNameConflict(context.TODO(), tt.obj)
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.want == nil {
+				g.Expect(got).To(BeNil())
+				return
+			}
+			g.Expect(got).ToNot(BeNil())
+			g.Expect(client.ObjectKeyFromObject(got)).To(Equal(*tt.want))
+		})
+	}
+}
+
+func Test_detectUnavailableSourceKinds(t *testing.T) {
+	g := NewWithT(t)
+
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	mapper.Add(sourcev1.GroupVersion.WithKind(sourcev1.HelmChartKind), apimeta.RESTScopeNamespace)
+
+	got, err := detectUnavailableSourceKinds(mapper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(map[string]bool{sourcev1beta2.OCIRepositoryKind: true}))
+
+	r := &HelmReleaseReconciler{unavailableSourceKinds: got}
+	g.Expect(r.sourceAPIAvailable(sourcev1.HelmChartKind)).To(BeTrue())
+	g.Expect(r.sourceAPIAvailable(sourcev1beta2.OCIRepositoryKind)).To(BeFalse())
+	g.Expect(requiredSourceKind(&v2.HelmRelease{
+		Spec: v2.HelmReleaseSpec{
+			ChartRef: &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo"},
+		},
+	})).To(Equal(sourcev1beta2.OCIRepositoryKind))
+	g.Expect(requiredSourceKind(&v2.HelmRelease{
+		Spec: v2.HelmReleaseSpec{
+			Chart: &v2.HelmChartTemplate{},
+		},
+	})).To(Equal(sourcev1.HelmChartKind))
+}
+
+func TestHelmReleaseReconciler_requestsForDependencyChange(t *testing.T) {
+	g := NewWithT(t)
+
+	dependency := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dependency",
+			Namespace: "some-namespace",
+		},
+	}
+	newDependant := func(name, namespace, reason string) *v2.HelmRelease {
+		return &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: v2.HelmReleaseSpec{
+				DependsOn: []v2.DependencyReference{
+					{
+						Name:      "dependency",
+						Namespace: "some-namespace",
+					},
+				},
+			},
+			Status: v2.HelmReleaseStatus{
+				Conditions: []metav1.Condition{
+					{Type: meta.ReadyCondition, Status: metav1.ConditionFalse, Reason: reason},
+				},
+			},
+		}
+	}
+	unrelated := newDependant("unrelated", "some-namespace", v2.DependencyNotReadyReason)
+	unrelated.Spec.DependsOn[0].Name = "other"
+	suspended := newDependant("suspended", "some-namespace", v2.DependencyNotReadyReason)
+	suspended.Spec.Suspend = true
+
+	c := fake.NewClientBuilder().
+		WithScheme(NewTestScheme()).
+		WithIndex(&v2.HelmRelease{}, v2.DependsOnIndexKey, indexDependsOn).
+		WithObjects(
+			dependency,
+			newDependant("waiting", "some-namespace", v2.DependencyNotReadyReason),
+			newDependant("waiting", "other-namespace", v2.DependencyNotReadyReason),
+			newDependant("failed", "some-namespace", v2.UpgradeFailedReason),
+			unrelated,
+			suspended,
+		).
+		Build()
+
+	r := &HelmReleaseReconciler{
+		Client: c,
+	}
+
+	got := r.requestsForDependencyChange(context.TODO(), dependency)
+	g.Expect(got).To(ConsistOf(
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "some-namespace", Name: "waiting"}},
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "other-namespace", Name: "waiting"}},
+	))
+}
+
+func TestHelmReleaseReconciler_adoptLegacyRelease(t *testing.T) {
+	tests := []struct {
+		name                      string
+		releases                  func(namespace string) []*helmrelease.Release
+		spec                      func(spec *v2.HelmReleaseSpec)
+		status                    v2.HelmReleaseStatus
+		expectHistory             func(releases []*helmrelease.Release) v2.Snapshots
+		expectLastReleaseRevision int
+		wantErr                   bool
+	}{
+		{
+			name: "adopts last release revision",
+			releases: func(namespace string) []*helmrelease.Release {
+				return []*helmrelease.Release{
+					testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+						Name:      "orphaned",
+						Namespace: namespace,
+						Version:   6,
+						Chart:     testutil.BuildChart(),
+						Status:    helmrelease.StatusDeployed,
+					}, testutil.ReleaseWithTestHook()),
+				}
+			},
+			spec: func(spec *v2.HelmReleaseSpec) {
+				spec.ReleaseName = "orphaned"
+			},
+			status: v2.HelmReleaseStatus{
+				LastReleaseRevision: 6,
+			},
+			expectHistory: func(releases []*helmrelease.Release) v2.Snapshots {
+				return v2.Snapshots{
+					release.ObservedToSnapshot(release.ObserveRelease(releases[0])),
+				}
+			},
+			expectLastReleaseRevision: 0,
+		},
+		{
+			name: "includes test hooks if enabled",
+			releases: func(namespace string) []*helmrelease.Release {
+				return []*helmrelease.Release{
+					testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+						Name:      "orphaned-with-hooks",
+						Namespace: namespace,
+						Version:   3,
+						Chart:     testutil.BuildChart(testutil.ChartWithTestHook()),
+						Status:    helmrelease.StatusDeployed,
+					}, testutil.ReleaseWithTestHook()),
+				}
+			},
+			spec: func(spec *v2.HelmReleaseSpec) {
+				spec.ReleaseName = "orphaned-with-hooks"
+				spec.Test = &v2.Test{
+					Enable: true,
+				}
+			},
+			status: v2.HelmReleaseStatus{
+				LastReleaseRevision: 3,
+			},
+			expectHistory: func(releases []*helmrelease.Release) v2.Snapshots {
+				snap := release.ObservedToSnapshot(release.ObserveRelease(releases[0]))
+				snap.SetTestHooks(release.TestHooksFromRelease(releases[0]))
+
+				return v2.Snapshots{
+					snap,
+				}
+			},
+			expectLastReleaseRevision: 0,
+		},
+		{
+			name: "non-existing release",
+			spec: func(spec *v2.HelmReleaseSpec) {
+				spec.ReleaseName = "non-existing"
+			},
+			status: v2.HelmReleaseStatus{
+				LastReleaseRevision: 2,
+			},
+			expectLastReleaseRevision: 2,
+			wantErr:                   true,
+		},
+		{
+			name: "without last release revision",
+			status: v2.HelmReleaseStatus{
+				LastReleaseRevision: 0,
+			},
+			expectHistory: func(releases []*helmrelease.Release) v2.Snapshots {
+				return nil
+			},
+			expectLastReleaseRevision: 0,
+		},
+		{
+			name: "with existing history",
+			status: v2.HelmReleaseStatus{
+				History: v2.Snapshots{
+					{
+						Name: "something",
+					},
+				},
+				LastReleaseRevision: 5,
+			},
+			expectHistory: func(releases []*helmrelease.Release) v2.Snapshots {
+				return v2.Snapshots{
+					{
+						Name: "something",
+					},
+				}
+			},
+			expectLastReleaseRevision: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Create a test namespace for storing the Helm release mock.
+			ns, err := testEnv.CreateNamespace(context.TODO(), "adopt-release")
+			g.Expect(err).ToNot(HaveOccurred())
+			t.Cleanup(func() {
+				_ = testEnv.Delete(context.TODO(), ns)
+			})
+
+			// Mock a HelmRelease object.
+			obj := &v2.HelmRelease{
+				Spec: v2.HelmReleaseSpec{
+					StorageNamespace: ns.Name,
+				},
+				Status: tt.status,
+			}
+			if tt.spec != nil {
+				tt.spec(&obj.Spec)
+			}
+
+			r := &HelmReleaseReconciler{
+				Client:           testEnv.Client,
+				APIReader:        testEnv.Client,
+				GetClusterConfig: GetTestClusterConfig,
+			}
+
+			// Store the Helm release mock in the test namespace.
+			getter, err := r.buildRESTClientGetter(context.TODO(), obj)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			cfg, err := action.NewConfigFactory(getter, action.WithStorage(helmdriver.SecretsDriverName, obj.GetStorageNamespace()))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var releases []*helmrelease.Release
+			if tt.releases != nil {
+				releases = tt.releases(ns.Name)
+			}
+			store := helmstorage.Init(cfg.Driver)
+			for _, rls := range releases {
+				g.Expect(store.Create(rls)).To(Succeed())
+			}
+
+			// Adopt the Helm release mock.
+			err = r.adoptLegacyRelease(context.TODO(), getter, obj)
+			g.Expect(err != nil).To(Equal(tt.wantErr), "unexpected error: %s", err)
+
+			// Verify the Helm release mock has been adopted.
+			var expectHistory v2.Snapshots
+			if tt.expectHistory != nil {
+				expectHistory = tt.expectHistory(releases)
+			}
+			g.Expect(obj.Status.History).To(Equal(expectHistory))
+			g.Expect(obj.Status.LastReleaseRevision).To(Equal(tt.expectLastReleaseRevision))
+		})
+	}
+}
+
+func TestHelmReleaseReconciler_buildRESTClientGetter(t *testing.T) {
+	const (
+		namespace = "some-namespace"
+		kubeCfg   = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    insecure-skip-tls-verify: true
+    server: https://1.2.3.4
+  name: development
+contexts:
+- context:
+    cluster: development
+    namespace: frontend
+    user: developer
+  name: dev-frontend
+current-context: dev-frontend
+preferences: {}
+users:
+- name: developer
+  user:
+    password: some-password
+    username: exp`
+	)
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		getConfig  func() (*rest.Config, error)
+		spec       v2.HelmReleaseSpec
+		secret     *corev1.Secret
+		requireTLS bool
+		want       genericclioptions.RESTClientGetter
+		wantErr    string
+	}{
+		{
+			name: "builds in-cluster RESTClientGetter for HelmRelease",
+			getConfig: func() (*rest.Config, error) {
+				return clientcmd.RESTConfigFromKubeConfig([]byte(kubeCfg))
+			},
+			spec: v2.HelmReleaseSpec{},
+			want: &kube.MemoryRESTClientGetter{},
+		},
+		{
+			name: "returns error when in-cluster GetClusterConfig fails",
+			getConfig: func() (*rest.Config, error) {
+				return nil, errors.New("some-error")
+			},
+			wantErr: "some-error",
+		},
+		{
+			name: "builds RESTClientGetter from HelmRelease with KubeConfig",
+			spec: v2.HelmReleaseSpec{
+				KubeConfig: &meta.KubeConfigReference{
+					SecretRef: meta.SecretKeyReference{
+						Name: "kubeconfig",
+					},
+				},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kubeconfig",
+					Namespace: namespace,
+				},
+				Data: map[string][]byte{
+					kube.DefaultKubeConfigSecretKey: []byte(kubeCfg),
+				},
+			},
+			want: &kube.MemoryRESTClientGetter{},
+		},
+		{
+			name: "error on missing KubeConfig secret",
+			spec: v2.HelmReleaseSpec{
+				KubeConfig: &meta.KubeConfigReference{
+					SecretRef: meta.SecretKeyReference{
+						Name: "kubeconfig",
+					},
+				},
+			},
+			wantErr: "could not get KubeConfig secret",
+		},
+		{
+			name: "error on invalid KubeConfig secret",
+			spec: v2.HelmReleaseSpec{
+				KubeConfig: &meta.KubeConfigReference{
+					SecretRef: meta.SecretKeyReference{
+						Name: "kubeconfig",
+						Key:  "invalid-key",
+					},
+				},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kubeconfig",
+					Namespace: namespace,
+				},
+			},
+			wantErr: "does not contain a 'invalid-key' key",
+		},
+		{
+			name: "builds RESTClientGetter from KubeConfig with TLS when TLS is required",
+			spec: v2.HelmReleaseSpec{
+				KubeConfig: &meta.KubeConfigReference{
+					SecretRef: meta.SecretKeyReference{
+						Name: "kubeconfig",
+					},
+				},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kubeconfig",
+					Namespace: namespace,
+				},
+				Data: map[string][]byte{
+					kube.DefaultKubeConfigSecretKey: []byte(kubeCfg),
+				},
+			},
+			requireTLS: true,
+			want:       &kube.MemoryRESTClientGetter{},
+		},
+		{
+			name: "error on KubeConfig without TLS when TLS is required",
+			spec: v2.HelmReleaseSpec{
+				KubeConfig: &meta.KubeConfigReference{
+					SecretRef: meta.SecretKeyReference{
+						Name: "kubeconfig",
+					},
+				},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kubeconfig",
+					Namespace: namespace,
+				},
+				Data: map[string][]byte{
+					kube.DefaultKubeConfigSecretKey: []byte(strings.Replace(kubeCfg, "https://", "http://", 1)),
+				},
+			},
+			requireTLS: true,
+			wantErr:    "does not use TLS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			c := fake.NewClientBuilder()
+			if tt.secret != nil {
+				c.WithObjects(tt.secret)
+			}
+
+			r := &HelmReleaseReconciler{
+				Client:               c.Build(),
+				GetClusterConfig:     tt.getConfig,
+				RequireKubeConfigTLS: tt.requireTLS,
+			}
+
+			getter, err := r.buildRESTClientGetter(context.Background(), &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-name",
+					Namespace: namespace,
+				},
+				Spec: tt.spec,
+			})
+			if len(tt.wantErr) > 0 {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(getter).To(BeAssignableToTypeOf(tt.want))
+			}
+		})
+	}
+}
+
+func TestHelmReleaseReconciler_getHelmChart(t *testing.T) {
+	g := NewWithT(t)
+
+	chart := &sourcev1.HelmChart{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "some-namespace",
+			Name:      "some-chart-name",
+		},
+	}
+
+	tests := []struct {
+		name            string
+		rel             *v2.HelmRelease
+		chart           *sourcev1.HelmChart
+		expectChart     bool
+		wantErr         bool
+		disallowCrossNS bool
+	}{
+		{
+			name: "retrieves HelmChart object from Status",
+			rel: &v2.HelmRelease{
+				Status: v2.HelmReleaseStatus{
+					HelmChart: "some-namespace/some-chart-name",
+				},
+			},
+			chart:       chart,
+			expectChart: true,
+		},
+		{
+			name: "no HelmChart found",
+			rel: &v2.HelmRelease{
+				Status: v2.HelmReleaseStatus{
+					HelmChart: "some-namespace/some-chart-name",
+				},
+			},
+			chart:       nil,
+			expectChart: false,
+			wantErr:     true,
+		},
+		{
+			name: "no HelmChart in Status",
+			rel: &v2.HelmRelease{
+				Status: v2.HelmReleaseStatus{
+					HelmChart: "",
+				},
+			},
+			chart:       chart,
+			expectChart: false,
+			wantErr:     true,
+		},
+		{
+			name: "ACL disallows cross namespace",
+			rel: &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+				},
+				Status: v2.HelmReleaseStatus{
+					HelmChart: "some-namespace/some-chart-name",
+				},
+			},
+			chart:           chart,
+			expectChart:     false,
+			wantErr:         true,
+			disallowCrossNS: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder()
+			c.WithScheme(NewTestScheme())
+			if tt.chart != nil {
+				c.WithObjects(tt.chart)
+			}
+
+			r := &HelmReleaseReconciler{
+				Client:        c.Build(),
+				EventRecorder: record.NewFakeRecorder(32),
+			}
+
+			curAllow := intacl.AllowCrossNamespaceRef
+			intacl.AllowCrossNamespaceRef = !tt.disallowCrossNS
+			t.Cleanup(func() { intacl.AllowCrossNamespaceRef = !curAllow })
+
+			got, err := r.getSource(context.TODO(), tt.rel)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(got).To(BeNil())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			hc, ok := got.(*sourcev1.HelmChart)
+			g.Expect(ok).To(BeTrue())
+			expect := g.Expect(hc.ObjectMeta)
+			if tt.expectChart {
+				expect.To(BeEquivalentTo(tt.chart.ObjectMeta))
+			} else {
+				expect.To(BeNil())
+			}
+		})
+	}
+}
+
+func TestHelmReleaseReconciler_reconcileDeprecationWarnings(t *testing.T) {
+	deprecation := "policy/v1beta1 PodDisruptionBudget is deprecated in v1.21+, unavailable in v1.25+; use policy/v1 PodDisruptionBudget"
+
+	t.Run("marks condition and emits event", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(1)
+		r := &HelmReleaseReconciler{EventRecorder: recorder}
+		obj := &v2.HelmRelease{}
+
+		r.reconcileDeprecationWarnings(obj, []string{deprecation}, true)
+		g.Expect(conditions.IsTrue(obj, v2.DeprecatedAPIsCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(obj, v2.DeprecatedAPIsCondition)).To(Equal(v2.DeprecationWarningsReason))
+		g.Expect(conditions.GetMessage(obj, v2.DeprecatedAPIsCondition)).To(ContainSubstring(deprecation))
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(deprecation)))
+	})
+
+	t.Run("retains condition without new release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &HelmReleaseReconciler{EventRecorder: record.NewFakeRecorder(1)}
+		obj := &v2.HelmRelease{}
+		conditions.MarkTrue(obj, v2.DeprecatedAPIsCondition, v2.DeprecationWarningsReason, "%s", deprecation)
+
+		r.reconcileDeprecationWarnings(obj, nil, false)
+		g.Expect(conditions.Has(obj, v2.DeprecatedAPIsCondition)).To(BeTrue())
+	})
+
+	t.Run("removes condition after new release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &HelmReleaseReconciler{EventRecorder: record.NewFakeRecorder(1)}
+		obj := &v2.HelmRelease{}
+		conditions.MarkTrue(obj, v2.DeprecatedAPIsCondition, v2.DeprecationWarningsReason, "%s", deprecation)
+
+		r.reconcileDeprecationWarnings(obj, nil, true)
+		g.Expect(conditions.Has(obj, v2.DeprecatedAPIsCondition)).To(BeFalse())
+	})
+}
+
+func TestHelmReleaseReconciler_reconcileSuspended(t *testing.T) {
+	t.Run("marks condition and emits event once", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(2)
+		r := &HelmReleaseReconciler{EventRecorder: recorder}
+		obj := &v2.HelmRelease{}
+		conditions.MarkTrue(obj, meta.ReadyCondition, v2.UpgradeSucceededReason, "upgraded")
+
+		r.reconcileSuspended(obj)
+		g.Expect(conditions.IsTrue(obj, v2.SuspendedCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(obj, v2.SuspendedCondition)).To(Equal(meta.SuspendedReason))
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(meta.SuspendedReason)))
+
+		r.reconcileSuspended(obj)
+		g.Expect(recorder.Events).ToNot(Receive())
+
+		g.Expect(conditions.IsTrue(obj, meta.ReadyCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(obj, meta.ReadyCondition)).To(Equal(v2.UpgradeSucceededReason))
+	})
+}
+
+func TestHelmReleaseReconciler_reconcileResume(t *testing.T) {
+	newRelease := func(suspendedFor time.Duration, autoProceed bool) *v2.HelmRelease {
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "release",
+				Namespace: "mock",
+			},
+			Spec: v2.HelmReleaseSpec{
+				ResumeVerification: &v2.ResumeVerification{
+					Threshold:   metav1.Duration{Duration: time.Hour},
+					AutoProceed: autoProceed,
+				},
+			},
+			Status: v2.HelmReleaseStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:               v2.SuspendedCondition,
+						Status:             metav1.ConditionTrue,
+						Reason:             meta.SuspendedReason,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-suspendedFor)),
+					},
+				},
+			},
+		}
+		return obj
+	}
+	newReconciler := func(recorder *record.FakeRecorder) *HelmReleaseReconciler {
+		return &HelmReleaseReconciler{
+			Client:        fake.NewClientBuilder().WithScheme(NewTestScheme()).Build(),
+			EventRecorder: recorder,
+		}
+	}
+
+	t.Run("proceeds after short suspension", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(1)
+		obj := newRelease(time.Minute, false)
+
+		g.Expect(newReconciler(recorder).reconcileResume(context.TODO(), obj)).To(BeTrue())
+		g.Expect(conditions.Has(obj, v2.ResumePendingCondition)).To(BeFalse())
+		g.Expect(recorder.Events).ToNot(Receive())
+	})
+
+	t.Run("awaits confirmation after long suspension", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(2)
+		r := newReconciler(recorder)
+		obj := newRelease(2*time.Hour, false)
+
+		g.Expect(r.reconcileResume(context.TODO(), obj)).To(BeFalse())
+		g.Expect(conditions.IsTrue(obj, v2.ResumePendingCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(obj, v2.ResumePendingCondition)).To(Equal(v2.AwaitingConfirmationReason))
+		g.Expect(conditions.Has(obj, v2.SuspendedCondition)).To(BeFalse())
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(v2.ConfirmResumeRequestAnnotation)))
+
+		g.Expect(r.reconcileResume(context.TODO(), obj)).To(BeFalse())
+
+		obj.Annotations = map[string]string{
+			meta.ReconcileRequestAnnotation:   "confirm",
+			v2.ConfirmResumeRequestAnnotation: "confirm",
+		}
+		g.Expect(r.reconcileResume(context.TODO(), obj)).To(BeTrue())
+		g.Expect(conditions.Has(obj, v2.ResumePendingCondition)).To(BeFalse())
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(v2.ResumeConfirmedReason)))
+	})
+
+	t.Run("ignores confirmation made before the report", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := newReconciler(record.NewFakeRecorder(2))
+		obj := newRelease(2*time.Hour, false)
+		obj.Annotations = map[string]string{
+			meta.ReconcileRequestAnnotation:   "early",
+			v2.ConfirmResumeRequestAnnotation: "early",
+		}
+
+		g.Expect(r.reconcileResume(context.TODO(), obj)).To(BeFalse())
+		g.Expect(r.reconcileResume(context.TODO(), obj)).To(BeFalse())
+		g.Expect(conditions.IsTrue(obj, v2.ResumePendingCondition)).To(BeTrue())
+	})
+
+	t.Run("proceeds automatically after report", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(1)
+		obj := newRelease(2*time.Hour, true)
+
+		g.Expect(newReconciler(recorder).reconcileResume(context.TODO(), obj)).To(BeTrue())
+		g.Expect(conditions.Has(obj, v2.ResumePendingCondition)).To(BeFalse())
+		g.Expect(recorder.Events).To(Receive(ContainSubstring("Resumed after a suspension of 2h0m0s")))
+	})
+}
+
+func TestHelmReleaseReconciler_reconcileHealthCheckSkipped(t *testing.T) {
+	t.Run("marks condition and emits event", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(1)
+		r := &HelmReleaseReconciler{EventRecorder: recorder}
+		obj := &v2.HelmRelease{}
+
+		r.reconcileHealthCheckSkipped(context.TODO(), obj, []string{"Deployment.apps", "Widget.example.com"})
+		g.Expect(conditions.IsTrue(obj, v2.HealthCheckIncompleteCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(obj, v2.HealthCheckIncompleteCondition)).To(Equal(v2.InsufficientPermissionsReason))
+		g.Expect(conditions.GetMessage(obj, v2.HealthCheckIncompleteCondition)).To(ContainSubstring("Deployment.apps, Widget.example.com"))
+		g.Expect(recorder.Events).To(Receive(ContainSubstring("Deployment.apps")))
+	})
+
+	t.Run("removes condition without skipped kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &HelmReleaseReconciler{EventRecorder: record.NewFakeRecorder(1)}
+		obj := &v2.HelmRelease{}
+		conditions.MarkTrue(obj, v2.HealthCheckIncompleteCondition, v2.InsufficientPermissionsReason, "skipped")
+
+		r.reconcileHealthCheckSkipped(context.TODO(), obj, nil)
+		g.Expect(conditions.Has(obj, v2.HealthCheckIncompleteCondition)).To(BeFalse())
+	})
+}
+
+func TestHelmReleaseReconciler_reconcileSourceVerified(t *testing.T) {
+	newChart := func(status metav1.ConditionStatus) *sourcev1.HelmChart {
+		return &sourcev1.HelmChart{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: sourcev1.GroupVersion.String(),
+				Kind:       sourcev1.HelmChartKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "chart",
+				Namespace: "mock",
+			},
+			Status: sourcev1.HelmChartStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    sourcev1.SourceVerifiedCondition,
+						Status:  status,
+						Message: "no matching signatures",
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("marks condition and emits event on failed verification", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(1)
+		r := &HelmReleaseReconciler{EventRecorder: recorder}
+		obj := &v2.HelmRelease{}
+
+		r.reconcileSourceVerified(context.TODO(), obj, newChart(metav1.ConditionFalse))
+		g.Expect(conditions.IsTrue(obj, v2.SourceVerifiedFailedCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(obj, v2.SourceVerifiedFailedCondition)).To(Equal(v2.VerificationFailedReason))
+		g.Expect(conditions.GetMessage(obj, v2.SourceVerifiedFailedCondition)).To(Equal(
+			"HelmChart 'mock/chart' failed to verify the chart: no matching signatures"))
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(v2.VerificationFailedReason)))
+	})
+
+	t.Run("removes condition on successful verification", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(1)
+		r := &HelmReleaseReconciler{EventRecorder: recorder}
+		obj := &v2.HelmRelease{}
+		conditions.MarkTrue(obj, v2.SourceVerifiedFailedCondition, v2.VerificationFailedReason, "failed")
+
+		r.reconcileSourceVerified(context.TODO(), obj, newChart(metav1.ConditionTrue))
+		g.Expect(conditions.Has(obj, v2.SourceVerifiedFailedCondition)).To(BeFalse())
+		g.Expect(recorder.Events).ToNot(Receive())
+	})
+}
+
+func TestHelmReleaseReconciler_reconcileSourceDrift(t *testing.T) {
+	newChart := func(revision string, ready metav1.ConditionStatus) *sourcev1.HelmChart {
+		return &sourcev1.HelmChart{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: sourcev1.GroupVersion.String(),
+				Kind:       sourcev1.HelmChartKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "chart",
+				Namespace: "mock",
+			},
+			Status: sourcev1.HelmChartStatus{
+				Artifact: &sourcev1.Artifact{Revision: revision},
+				Conditions: []metav1.Condition{
+					{
+						Type:    meta.ReadyCondition,
+						Status:  ready,
+						Message: "failed to fetch index",
+					},
+				},
+			},
+		}
+	}
+	newRelease := func() *v2.HelmRelease {
+		return &v2.HelmRelease{
+			Spec: v2.HelmReleaseSpec{
+				Chart: &v2.HelmChartTemplate{
+					Spec: v2.HelmChartTemplateSpec{
+						Chart:   "podinfo",
+						Version: ">=1.0.0",
+					},
+				},
+			},
+			Status: v2.HelmReleaseStatus{
+				History: v2.Snapshots{
+					{Version: 1, ChartVersion: "2.0.0"},
+				},
+			},
+		}
+	}
+
+	t.Run("marks condition on decreased chart version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(1)
+		r := &HelmReleaseReconciler{EventRecorder: recorder}
+		obj := newRelease()
+
+		r.reconcileSourceDrift(context.TODO(), obj, newChart("1.0.0", metav1.ConditionTrue))
+		g.Expect(conditions.IsTrue(obj, v2.SourceDriftCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(obj, v2.SourceDriftCondition)).To(Equal(v2.ChartVersionDecreasedReason))
+		g.Expect(conditions.GetMessage(obj, v2.SourceDriftCondition)).To(Equal(
+			"HelmChart 'mock/chart' resolved chart version '1.0.0', which is lower than the previously released version '2.0.0'"))
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(v2.ChartVersionDecreasedReason)))
+	})
+
+	t.Run("marks condition on stale source", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(1)
+		availability := newSourceAvailability()
+		availability.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+		r := &HelmReleaseReconciler{
+			EventRecorder:        recorder,
+			sourceStaleThreshold: time.Hour,
+			sourceAvailability:   availability,
+		}
+		obj := newRelease()
+
+		r.reconcileSourceDrift(context.TODO(), obj, newChart("2.0.0", metav1.ConditionFalse))
+		g.Expect(conditions.IsTrue(obj, v2.SourceDriftCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(obj, v2.SourceDriftCondition)).To(Equal(v2.SourceStaleReason))
+		g.Expect(conditions.GetMessage(obj, v2.SourceDriftCondition)).To(ContainSubstring("failed to fetch index"))
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(v2.SourceStaleReason)))
+	})
+
+	t.Run("does not mark stale source within threshold", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(1)
+		r := &HelmReleaseReconciler{
+			EventRecorder:        recorder,
+			sourceStaleThreshold: time.Hour,
+			sourceAvailability:   newSourceAvailability(),
+		}
+		obj := newRelease()
+
+		r.reconcileSourceDrift(context.TODO(), obj, newChart("2.0.0", metav1.ConditionFalse))
+		g.Expect(conditions.Has(obj, v2.SourceDriftCondition)).To(BeFalse())
+		g.Expect(recorder.Events).ToNot(Receive())
+	})
+
+	t.Run("removes condition without drift", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(1)
+		r := &HelmReleaseReconciler{EventRecorder: recorder}
+		obj := newRelease()
+		conditions.MarkTrue(obj, v2.SourceDriftCondition, v2.ChartVersionDecreasedReason, "decreased")
+
+		r.reconcileSourceDrift(context.TODO(), obj, newChart("2.1.0", metav1.ConditionTrue))
+		g.Expect(conditions.Has(obj, v2.SourceDriftCondition)).To(BeFalse())
+		g.Expect(recorder.Events).ToNot(Receive())
+	})
+}
+
+func TestHelmReleaseReconciler_reconcileHealth(t *testing.T) {
+	snapshot := &v2.Snapshot{
+		Name:      "release",
+		Namespace: "default",
+		Version:   1,
+		Status:    "deployed",
+	}
+
+	t.Run("removes condition when disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &HelmReleaseReconciler{EventRecorder: record.NewFakeRecorder(1)}
+		obj := &v2.HelmRelease{}
+		conditions.MarkTrue(obj, meta.HealthyCondition, meta.SucceededReason, "healthy")
+
+		g.Expect(r.reconcileHealth(context.TODO(), nil, nil, obj, true)).To(Succeed())
+		g.Expect(conditions.Has(obj, meta.HealthyCondition)).To(BeFalse())
+	})
+
+	t.Run("skips assessment when not ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &HelmReleaseReconciler{EventRecorder: record.NewFakeRecorder(1)}
+		obj := &v2.HelmRelease{
+			Spec: v2.HelmReleaseSpec{
+				HealthCheck: &v2.HealthCheck{Enable: true},
+			},
+			Status: v2.HelmReleaseStatus{
+				History: v2.Snapshots{snapshot},
+			},
+		}
+		conditions.MarkFalse(obj, meta.ReadyCondition, v2.UpgradeFailedReason, "failed")
+
+		g.Expect(r.reconcileHealth(context.TODO(), nil, nil, obj, true)).To(Succeed())
+		g.Expect(conditions.Has(obj, meta.HealthyCondition)).To(BeFalse())
+	})
+
+	t.Run("skips assessment of healthy resources without new release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &HelmReleaseReconciler{EventRecorder: record.NewFakeRecorder(1)}
+		obj := &v2.HelmRelease{
+			Spec: v2.HelmReleaseSpec{
+				HealthCheck: &v2.HealthCheck{Enable: true},
+			},
+			Status: v2.HelmReleaseStatus{
+				History: v2.Snapshots{snapshot},
+			},
+		}
+		conditions.MarkTrue(obj, meta.ReadyCondition, v2.UpgradeSucceededReason, "succeeded")
+		conditions.MarkTrue(obj, meta.HealthyCondition, meta.SucceededReason, "healthy")
+
+		g.Expect(r.reconcileHealth(context.TODO(), nil, nil, obj, false)).To(Succeed())
+		g.Expect(conditions.IsTrue(obj, meta.HealthyCondition)).To(BeTrue())
+	})
+}
+
+func TestHelmReleaseReconciler_reconcileVerificationWindow(t *testing.T) {
+	previous := &v2.Snapshot{
+		Name:      "release",
+		Namespace: "default",
+		Version:   1,
+		Status:    "superseded",
+	}
+	latest := func(deployed time.Time) *v2.Snapshot {
+		return &v2.Snapshot{
+			Name:         "release",
+			Namespace:    "default",
+			Version:      2,
+			Status:       "deployed",
+			LastDeployed: metav1.NewTime(deployed),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		window   *metav1.Duration
+		history  v2.Snapshots
+		reason   string
+		notReady bool
+	}{
+		{
+			name:    "without window",
+			history: v2.Snapshots{latest(time.Now()), previous},
+			reason:  v2.UpgradeSucceededReason,
+		},
+		{
+			name:    "after window",
+			window:  &metav1.Duration{Duration: time.Minute},
+			history: v2.Snapshots{latest(time.Now().Add(-2 * time.Minute)), previous},
+			reason:  v2.UpgradeSucceededReason,
+		},
+		{
+			name:    "after install",
+			window:  &metav1.Duration{Duration: time.Minute},
+			history: v2.Snapshots{latest(time.Now())},
+			reason:  v2.InstallSucceededReason,
+		},
+		{
+			name:    "without previous release",
+			window:  &metav1.Duration{Duration: time.Minute},
+			history: v2.Snapshots{latest(time.Now())},
+			reason:  v2.UpgradeSucceededReason,
+		},
+		{
+			name:     "when not ready",
+			window:   &metav1.Duration{Duration: time.Minute},
+			history:  v2.Snapshots{latest(time.Now()), previous},
+			reason:   v2.UpgradeSucceededReason,
+			notReady: true,
+		},
+		{
+			name:    "after failed verification",
+			window:  &metav1.Duration{Duration: time.Minute},
+			history: v2.Snapshots{latest(time.Now()), previous},
+			reason:  v2.VerificationWindowFailedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run("skips check "+tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &HelmReleaseReconciler{EventRecorder: record.NewFakeRecorder(1)}
+			obj := &v2.HelmRelease{
+				Spec: v2.HelmReleaseSpec{
+					Upgrade: &v2.Upgrade{VerificationWindow: tt.window},
+				},
+				Status: v2.HelmReleaseStatus{
+					History: tt.history,
+				},
+			}
+			conditions.MarkTrue(obj, v2.ReleasedCondition, tt.reason, "released")
+			if tt.notReady {
+				conditions.MarkFalse(obj, meta.ReadyCondition, meta.HealthCheckFailedReason, "unhealthy")
+			} else {
+				conditions.MarkTrue(obj, meta.ReadyCondition, tt.reason, "released")
+			}
+
+			remaining, err := r.reconcileVerificationWindow(context.TODO(), nil, nil, &intreconcile.Request{Object: obj})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(remaining).To(BeZero())
+			g.Expect(obj.Status.Failures).To(BeZero())
+		})
+	}
+
+	remediationTests := []struct {
+		name           string
+		remediation    *v2.UpgradeRemediation
+		wantErr        error
+		wantRolledBack bool
+	}{
+		{
+			name:           "rolls back with retries left",
+			remediation:    &v2.UpgradeRemediation{Retries: 1},
+			wantErr:        intreconcile.ErrMustRequeue,
+			wantRolledBack: true,
+		},
+		{
+			name:           "rolls back last failure",
+			remediation:    &v2.UpgradeRemediation{RemediateLastFailure: ptr.To(true)},
+			wantErr:        intreconcile.ErrExceededMaxRetries,
+			wantRolledBack: true,
+		},
+		{
+			name:    "does not roll back without retries",
+			wantErr: intreconcile.ErrExceededMaxRetries,
+		},
+	}
+	for _, tt := range remediationTests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ns, err := testEnv.CreateNamespace(context.TODO(), "verification-window")
+			g.Expect(err).ToNot(HaveOccurred())
+			t.Cleanup(func() {
+				_ = testEnv.Delete(context.TODO(), ns)
+			})
+
+			// The resources of the releases do not exist in the cluster,
+			// which makes the health check fail.
+			mockChart := testutil.BuildChart()
+			prev := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+				Name:      "release",
+				Namespace: ns.Name,
+				Version:   1,
+				Chart:     mockChart,
+				Status:    helmrelease.StatusSuperseded,
+			})
+			cur := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+				Name:      "release",
+				Namespace: ns.Name,
+				Version:   2,
+				Chart:     mockChart,
+				Status:    helmrelease.StatusDeployed,
+			})
+			cur.Info.LastDeployed = helmtime.Now()
+
+			obj := &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "release",
+					Namespace: ns.Name,
+				},
+				Spec: v2.HelmReleaseSpec{
+					Upgrade: &v2.Upgrade{
+						VerificationWindow: &metav1.Duration{Duration: time.Minute},
+						Remediation:        tt.remediation,
+					},
+				},
+				Status: v2.HelmReleaseStatus{
+					StorageNamespace:           ns.Name,
+					LastAttemptedReleaseAction: v2.ReleaseActionUpgrade,
+					History: v2.Snapshots{
+						release.ObservedToSnapshot(release.ObserveRelease(cur)),
+						release.ObservedToSnapshot(release.ObserveRelease(prev)),
+					},
 				},
-				Status: tt.status,
-			}
-			if tt.spec != nil {
-				tt.spec(&obj.Spec)
 			}
+			conditions.MarkTrue(obj, v2.ReleasedCondition, v2.UpgradeSucceededReason, "released")
+			conditions.MarkTrue(obj, meta.ReadyCondition, v2.UpgradeSucceededReason, "released")
 
+			c := fake.NewClientBuilder().
+				WithScheme(NewTestScheme()).
+				WithStatusSubresource(&v2.HelmRelease{}).
+				WithObjects(obj).
+				Build()
 			r := &HelmReleaseReconciler{
-				Client:           testEnv.Client,
-				APIReader:        testEnv.Client,
+				Client:           c,
 				GetClusterConfig: GetTestClusterConfig,
+				EventRecorder:    record.NewFakeRecorder(32),
 			}
 
-			// Store the Helm release mock in the test namespace.
 			getter, err := r.buildRESTClientGetter(context.TODO(), obj)
 			g.Expect(err).ToNot(HaveOccurred())
-
-			cfg, err := action.NewConfigFactory(getter, action.WithStorage(helmdriver.SecretsDriverName, obj.GetStorageNamespace()))
+			cfg, err := action.NewConfigFactory(getter, action.WithStorage(helmdriver.SecretsDriverName, ns.Name))
 			g.Expect(err).ToNot(HaveOccurred())
-
-			var releases []*helmrelease.Release
-			if tt.releases != nil {
-				releases = tt.releases(ns.Name)
-			}
 			store := helmstorage.Init(cfg.Driver)
-			for _, rls := range releases {
-				g.Expect(store.Create(rls)).To(Succeed())
+			g.Expect(store.Create(prev)).To(Succeed())
+			g.Expect(store.Create(cur)).To(Succeed())
+
+			req := &intreconcile.Request{Object: obj, Chart: mockChart, Values: mockChart.Values}
+			remaining, err := r.reconcileVerificationWindow(context.TODO(), patch.NewSerialPatcher(obj, r.Client), cfg, req)
+			g.Expect(err).To(MatchError(tt.wantErr))
+			g.Expect(remaining).To(BeZero())
+			g.Expect(obj.Status.Failures).To(Equal(int64(1)))
+			g.Expect(obj.Status.UpgradeFailures).To(Equal(int64(1)))
+			g.Expect(conditions.GetReason(obj, v2.ReleasedCondition)).To(Equal(v2.VerificationWindowFailedReason))
+
+			last, err := store.Last("release")
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.wantRolledBack {
+				g.Expect(last.Version).To(Equal(3))
+				g.Expect(conditions.IsTrue(obj, v2.RemediatedCondition)).To(BeTrue())
+				g.Expect(obj.Status.History.Latest().Version).To(Equal(3))
+			} else {
+				g.Expect(last.Version).To(Equal(cur.Version))
+				g.Expect(conditions.Has(obj, v2.RemediatedCondition)).To(BeFalse())
+				g.Expect(conditions.IsStalled(obj)).To(BeTrue())
 			}
+		})
+	}
+}
 
-			// Adopt the Helm release mock.
-			err = r.adoptLegacyRelease(context.TODO(), getter, obj)
-			g.Expect(err != nil).To(Equal(tt.wantErr), "unexpected error: %s", err)
+func TestHelmReleaseReconciler_requeueAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		resync   time.Duration
+		want     time.Duration
+	}{
+		{
+			name:     "interval",
+			interval: 10 * time.Minute,
+			resync:   24 * time.Hour,
+			want:     10 * time.Minute,
+		},
+		{
+			name:     "event-driven",
+			interval: 0,
+			resync:   24 * time.Hour,
+			want:     24 * time.Hour,
+		},
+		{
+			name:     "event-driven without resync",
+			interval: 0,
+			resync:   0,
+			want:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
 
-			// Verify the Helm release mock has been adopted.
-			var expectHistory v2.Snapshots
-			if tt.expectHistory != nil {
-				expectHistory = tt.expectHistory(releases)
+			r := &HelmReleaseReconciler{resyncInterval: tt.resync}
+			obj := &v2.HelmRelease{
+				Spec: v2.HelmReleaseSpec{
+					Interval: metav1.Duration{Duration: tt.interval},
+				},
 			}
-			g.Expect(obj.Status.History).To(Equal(expectHistory))
-			g.Expect(obj.Status.LastReleaseRevision).To(Equal(tt.expectLastReleaseRevision))
+			g.Expect(r.requeueAfter(obj)).To(Equal(tt.want))
 		})
 	}
 }
 
-func TestHelmReleaseReconciler_buildRESTClientGetter(t *testing.T) {
-	const (
-		namespace = "some-namespace"
-		kubeCfg   = `apiVersion: v1
-kind: Config
-clusters:
-- cluster:
-    insecure-skip-tls-verify: true
-    server: https://1.2.3.4
-  name: development
-contexts:
-- context:
-    cluster: development
-    namespace: frontend
-    user: developer
-  name: dev-frontend
-current-context: dev-frontend
-preferences: {}
-users:
-- name: developer
-  user:
-    password: some-password
-    username: exp`
-	)
+func TestHelmReleaseReconciler_buildArtifactHTTPClient(t *testing.T) {
+	proxySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxy", Namespace: "mock"},
+		Data: map[string][]byte{
+			"HTTPS_PROXY": []byte("http://egress.example.com:8080"),
+			"NO_PROXY":    []byte("mirror.svc.cluster.local"),
+		},
+	}
+	emptySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "mock"},
+	}
 
 	tests := []struct {
 		name      string
-		env       map[string]string
-		getConfig func() (*rest.Config, error)
-		spec      v2.HelmReleaseSpec
-		secret    *corev1.Secret
-		want      genericclioptions.RESTClientGetter
+		ref       *meta.LocalObjectReference
+		wantProxy *url.URL
 		wantErr   string
 	}{
 		{
-			name: "builds in-cluster RESTClientGetter for HelmRelease",
-			getConfig: func() (*rest.Config, error) {
-				return clientcmd.RESTConfigFromKubeConfig([]byte(kubeCfg))
-			},
-			spec: v2.HelmReleaseSpec{},
-			want: &kube.MemoryRESTClientGetter{},
+			name: "without proxy secret",
 		},
 		{
-			name: "returns error when in-cluster GetClusterConfig fails",
-			getConfig: func() (*rest.Config, error) {
-				return nil, errors.New("some-error")
-			},
-			wantErr: "some-error",
+			name:      "with proxy secret",
+			ref:       &meta.LocalObjectReference{Name: "proxy"},
+			wantProxy: &url.URL{Scheme: "http", Host: "egress.example.com:8080"},
 		},
 		{
-			name: "builds RESTClientGetter from HelmRelease with KubeConfig",
-			spec: v2.HelmReleaseSpec{
-				KubeConfig: &meta.KubeConfigReference{
-					SecretRef: meta.SecretKeyReference{
-						Name: "kubeconfig",
-					},
-				},
+			name:    "with proxy secret without proxy keys",
+			ref:     &meta.LocalObjectReference{Name: "empty"},
+			wantErr: "neither key 'HTTP_PROXY' nor 'HTTPS_PROXY' found",
+		},
+		{
+			name:    "with missing proxy secret",
+			ref:     &meta.LocalObjectReference{Name: "missing"},
+			wantErr: "could not get proxy secret 'mock/missing'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			base := &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+			r := &HelmReleaseReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(NewTestScheme()).
+					WithObjects(proxySecret, emptySecret).
+					Build(),
+				artifactHTTPClient: base,
+				proxyClients:       loader.NewProxyClientCache(base),
+			}
+			obj := &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "mock"},
+				Spec:       v2.HelmReleaseSpec{ProxySecretRef: tt.ref},
+			}
+
+			got, err := r.buildArtifactHTTPClient(context.TODO(), obj)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.ref == nil {
+				g.Expect(got).To(BeIdenticalTo(base))
+				return
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "https://charts.example.com/podinfo.tgz", nil)
+			proxy, err := got.Transport.(*http.Transport).Proxy(req)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(proxy).To(Equal(tt.wantProxy))
+
+			// The proxied client is reused for the same Secret.
+			again, err := r.buildArtifactHTTPClient(context.TODO(), obj)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(again).To(BeIdenticalTo(got))
+		})
+	}
+}
+
+func TestHelmReleaseReconciler_composeHookEnv(t *testing.T) {
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "mock"},
+		Data: map[string][]byte{
+			"DB_PASSWORD": []byte("secret"),
+			"username":    []byte("admin"),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		install     []v2.ExtraEnvVar
+		upgrade     []v2.ExtraEnvVar
+		wantInstall map[string][]byte
+		wantUpgrade map[string][]byte
+		wantErr     string
+	}{
+		{
+			name:        "without extra env",
+			wantInstall: map[string][]byte{},
+			wantUpgrade: map[string][]byte{},
+		},
+		{
+			name: "with extra env per action",
+			install: []v2.ExtraEnvVar{
+				{Name: "DB_PASSWORD", SecretKeyRef: meta.SecretKeyReference{Name: "credentials"}},
+				{Name: "DB_USER", SecretKeyRef: meta.SecretKeyReference{Name: "credentials", Key: "username"}},
 			},
-			secret: &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "kubeconfig",
-					Namespace: namespace,
-				},
-				Data: map[string][]byte{
-					kube.DefaultKubeConfigSecretKey: []byte(kubeCfg),
-				},
+			upgrade: []v2.ExtraEnvVar{
+				{Name: "DB_PASSWORD", SecretKeyRef: meta.SecretKeyReference{Name: "credentials"}},
 			},
-			want: &kube.MemoryRESTClientGetter{},
+			wantInstall: map[string][]byte{"DB_PASSWORD": []byte("secret"), "DB_USER": []byte("admin")},
+			wantUpgrade: map[string][]byte{"DB_PASSWORD": []byte("secret")},
 		},
 		{
-			name: "error on missing KubeConfig secret",
-			spec: v2.HelmReleaseSpec{
-				KubeConfig: &meta.KubeConfigReference{
-					SecretRef: meta.SecretKeyReference{
-						Name: "kubeconfig",
-					},
-				},
+			name: "with missing key",
+			upgrade: []v2.ExtraEnvVar{
+				{Name: "DB_HOST", SecretKeyRef: meta.SecretKeyReference{Name: "credentials"}},
 			},
-			wantErr: "could not get KubeConfig secret",
+			wantErr: "key 'DB_HOST' not found in hook environment secret 'mock/credentials'",
 		},
 		{
-			name: "error on invalid KubeConfig secret",
-			spec: v2.HelmReleaseSpec{
-				KubeConfig: &meta.KubeConfigReference{
-					SecretRef: meta.SecretKeyReference{
-						Name: "kubeconfig",
-						Key:  "invalid-key",
-					},
-				},
-			},
-			secret: &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "kubeconfig",
-					Namespace: namespace,
-				},
+			name: "with missing secret",
+			install: []v2.ExtraEnvVar{
+				{Name: "DB_PASSWORD", SecretKeyRef: meta.SecretKeyReference{Name: "missing"}},
 			},
-			wantErr: "does not contain a 'invalid-key' key",
+			wantErr: "could not get hook environment secret 'mock/missing'",
 		},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			for k, v := range tt.env {
-				t.Setenv(k, v)
+			r := &HelmReleaseReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(NewTestScheme()).
+					WithObjects(credentials).
+					Build(),
 			}
-
-			c := fake.NewClientBuilder()
-			if tt.secret != nil {
-				c.WithObjects(tt.secret)
+			obj := &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "mock"},
+				Spec: v2.HelmReleaseSpec{
+					Install: &v2.Install{ExtraEnv: tt.install},
+					Upgrade: &v2.Upgrade{ExtraEnv: tt.upgrade},
+				},
 			}
 
-			r := &HelmReleaseReconciler{
-				Client:           c.Build(),
-				GetClusterConfig: tt.getConfig,
+			install, upgrade, err := r.composeHookEnv(context.TODO(), obj)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
 			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(install).To(Equal(tt.wantInstall))
+			g.Expect(upgrade).To(Equal(tt.wantUpgrade))
+		})
+	}
+}
 
-			getter, err := r.buildRESTClientGetter(context.Background(), &v2.HelmRelease{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "some-name",
-					Namespace: namespace,
-				},
-				Spec: tt.spec,
-			})
-			if len(tt.wantErr) > 0 {
-				g.Expect(err).To(HaveOccurred())
-				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
-			} else {
-				g.Expect(err).ToNot(HaveOccurred())
-				g.Expect(getter).To(BeAssignableToTypeOf(tt.want))
-			}
+func Test_dryRunData(t *testing.T) {
+	rendered := &helmrelease.Release{
+		Version:  2,
+		Chart:    &chart.Chart{Metadata: &chart.Metadata{Version: "6.0.1"}},
+		Info:     &helmrelease.Info{Notes: "notes"},
+		Manifest: "kind: Deployment\nreplicas: 2\n",
+	}
+
+	t.Run("without current release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := dryRunData(rendered, nil, true)
+		g.Expect(data).To(HaveKeyWithValue("manifest", rendered.Manifest))
+		g.Expect(data).To(HaveKeyWithValue("chartVersion", "6.0.1"))
+		g.Expect(data).To(HaveKeyWithValue("notes", "notes"))
+		g.Expect(data).ToNot(HaveKey("currentRevision"))
+		g.Expect(data["diff"]).To(ContainSubstring("replicas: 2"))
+	})
+
+	t.Run("with current release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		current := &helmrelease.Release{
+			Version:  1,
+			Manifest: "kind: Deployment\nreplicas: 1\n",
+		}
+		data := dryRunData(rendered, current, true)
+		g.Expect(data).To(HaveKeyWithValue("currentRevision", "1"))
+		g.Expect(data["diff"]).To(ContainSubstring("replicas: 1"))
+		g.Expect(data["diff"]).To(ContainSubstring("replicas: 2"))
+
+		g.Expect(dryRunData(rendered, rendered, true)).To(HaveKeyWithValue("diff", ""))
+	})
+
+	t.Run("hides Secrets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		secret := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: credentials\nstringData:\n  password: %s\n"
+		rendered := &helmrelease.Release{
+			Version:  2,
+			Chart:    &chart.Chart{Metadata: &chart.Metadata{Version: "6.0.1"}},
+			Manifest: "kind: Deployment\nreplicas: 2\n---\n" + fmt.Sprintf(secret, "new-password"),
+		}
+		current := &helmrelease.Release{
+			Version:  1,
+			Manifest: "kind: Deployment\nreplicas: 1\n---\n" + fmt.Sprintf(secret, "old-password"),
+		}
+
+		data := dryRunData(rendered, current, true)
+		for k, v := range data {
+			g.Expect(v).ToNot(ContainSubstring("password"), "key %q", k)
+		}
+		g.Expect(data["manifest"]).To(ContainSubstring("replicas: 2"))
+		g.Expect(data["diff"]).To(ContainSubstring("replicas: 1"))
+
+		data = dryRunData(rendered, current, false)
+		g.Expect(data["manifest"]).To(ContainSubstring("new-password"))
+		g.Expect(data["diff"]).To(ContainSubstring("old-password"))
+	})
+}
+
+func TestHelmReleaseReconciler_retainFailedArtifact(t *testing.T) {
+	const (
+		configMapManifest = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n"
+		secretManifest    = "apiVersion: v1\nkind: Secret\nmetadata:\n  name: credentials\nstringData:\n  password: hunter2\n"
+	)
+
+	retainManifest := func(g *WithT, obj *v2.HelmRelease) string {
+		store, err := retention.NewStore(t.TempDir(), 1, 1024*1024)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		driver := helmdriver.NewMemory()
+		driver.SetNamespace(obj.Namespace)
+		cfg, err := action.NewConfigFactory(&kube.MemoryRESTClientGetter{}, action.WithDriver(driver))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		rls := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+			Name:      obj.GetReleaseName(),
+			Namespace: obj.Namespace,
+			Version:   1,
+			Chart:     testutil.BuildChart(),
+			Status:    helmrelease.StatusFailed,
 		})
+		rls.Manifest = configMapManifest + "---\n" + secretManifest
+		g.Expect(cfg.NewStorage().Create(rls)).To(Succeed())
+
+		r := &HelmReleaseReconciler{failedArtifacts: store}
+		r.retainFailedArtifact(context.TODO(), obj, cfg, []byte("chart"), nil, errors.New("upgrade failed"))
+
+		b, err := os.ReadFile(filepath.Join(store.Dir(obj.Namespace, obj.Name), "manifest.yaml"))
+		g.Expect(err).ToNot(HaveOccurred())
+		return string(b)
+	}
+
+	t.Run("masks Secrets in retained manifest", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"}}
+		manifest := retainManifest(g, obj)
+		g.Expect(manifest).To(ContainSubstring("kind: ConfigMap"))
+		g.Expect(manifest).ToNot(ContainSubstring("kind: Secret"))
+		g.Expect(manifest).ToNot(ContainSubstring("hunter2"))
+	})
+
+	t.Run("retains Secrets with HideSecrets disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		t.Cleanup(func() { _ = features.SetConfigured(nil) })
+		g.Expect(features.SetConfigured(map[string]bool{features.HideSecrets: false})).To(Succeed())
+
+		obj := &v2.HelmRelease{ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"}}
+		g.Expect(retainManifest(g, obj)).To(ContainSubstring("hunter2"))
+	})
+}
+
+func Test_supportBundleData(t *testing.T) {
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "release",
+			Namespace:     "default",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			Annotations: map[string]string{
+				corev1.LastAppliedConfigAnnotation: `{"spec":{"values":{"password":"inline-secret"}}}`,
+				v2.CriticalAnnotation:              "true",
+			},
+		},
+		Spec: v2.HelmReleaseSpec{
+			ReleaseName: "podinfo",
+			Values:      &apiextensionsv1.JSON{Raw: []byte(`{"password":"inline-secret"}`)},
+		},
 	}
+
+	t.Run("without current release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data, err := supportBundleData(obj, nil, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(data["helmrelease.yaml"]).To(ContainSubstring("kind: HelmRelease"))
+		g.Expect(data["helmrelease.yaml"]).To(ContainSubstring("releaseName: podinfo"))
+		g.Expect(data["helmrelease.yaml"]).ToNot(ContainSubstring("managedFields"))
+		g.Expect(data["helmrelease.yaml"]).ToNot(ContainSubstring("inline-secret"))
+		g.Expect(data["helmrelease.yaml"]).ToNot(ContainSubstring(corev1.LastAppliedConfigAnnotation))
+		g.Expect(data["helmrelease.yaml"]).To(ContainSubstring(v2.CriticalAnnotation))
+		g.Expect(obj.Spec.Values).ToNot(BeNil())
+		g.Expect(obj.Annotations).To(HaveKey(corev1.LastAppliedConfigAnnotation))
+		g.Expect(data).ToNot(HaveKey("release.yaml"))
+		g.Expect(data).ToNot(HaveKey("manifestDigest"))
+		g.Expect(data).To(HaveKeyWithValue("events.yaml", "[]\n"))
+	})
+
+	t.Run("with current release and events", func(t *testing.T) {
+		g := NewWithT(t)
+
+		current := &helmrelease.Release{
+			Name:     "podinfo",
+			Version:  3,
+			Chart:    &chart.Chart{Metadata: &chart.Metadata{Name: "podinfo", Version: "6.0.1"}},
+			Info:     &helmrelease.Info{Status: helmrelease.StatusDeployed},
+			Config:   map[string]interface{}{"password": "secret"},
+			Manifest: "kind: Secret\ndata:\n  password: c2VjcmV0\n",
+		}
+		now := time.Now()
+		var events []corev1.Event
+		for i := 0; i < supportBundleMaxEvents+5; i++ {
+			events = append(events, corev1.Event{
+				Type:          corev1.EventTypeNormal,
+				Reason:        v2.UpgradeSucceededReason,
+				Message:       fmt.Sprintf("event-%02d", i),
+				LastTimestamp: metav1.NewTime(now.Add(time.Duration(i) * time.Second)),
+			})
+		}
+
+		data, err := supportBundleData(obj, current, events)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(data["release.yaml"]).To(ContainSubstring("version: 3"))
+		g.Expect(data["release.yaml"]).To(ContainSubstring("status: deployed"))
+		g.Expect(data["release.yaml"]).ToNot(ContainSubstring("secret"))
+		g.Expect(data["release.yaml"]).ToNot(ContainSubstring("c2VjcmV0"))
+		g.Expect(data).To(HaveKeyWithValue("manifestDigest", digest.Canonical.FromString(current.Manifest).String()))
+		g.Expect(data["events.yaml"]).ToNot(ContainSubstring("event-04"))
+		g.Expect(data["events.yaml"]).To(ContainSubstring("event-05"))
+		g.Expect(data["events.yaml"]).To(ContainSubstring("event-24"))
+	})
 }
 
-func TestHelmReleaseReconciler_getHelmChart(t *testing.T) {
+func Test_effectiveConfigData(t *testing.T) {
 	g := NewWithT(t)
 
-	chart := &sourcev1.HelmChart{
+	obj := &v2.HelmRelease{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "some-namespace",
-			Name:      "some-chart-name",
-		},
-	}
-
-	tests := []struct {
-		name            string
-		rel             *v2.HelmRelease
-		chart           *sourcev1.HelmChart
-		expectChart     bool
-		wantErr         bool
-		disallowCrossNS bool
-	}{
-		{
-			name: "retrieves HelmChart object from Status",
-			rel: &v2.HelmRelease{
-				Status: v2.HelmReleaseStatus{
-					HelmChart: "some-namespace/some-chart-name",
-				},
-			},
-			chart:       chart,
-			expectChart: true,
-		},
-		{
-			name: "no HelmChart found",
-			rel: &v2.HelmRelease{
-				Status: v2.HelmReleaseStatus{
-					HelmChart: "some-namespace/some-chart-name",
-				},
-			},
-			chart:       nil,
-			expectChart: false,
-			wantErr:     true,
-		},
-		{
-			name: "no HelmChart in Status",
-			rel: &v2.HelmRelease{
-				Status: v2.HelmReleaseStatus{
-					HelmChart: "",
-				},
-			},
-			chart:       chart,
-			expectChart: false,
-			wantErr:     true,
+			Name:      "release",
+			Namespace: "default",
 		},
-		{
-			name: "ACL disallows cross namespace",
-			rel: &v2.HelmRelease{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "default",
-				},
-				Status: v2.HelmReleaseStatus{
-					HelmChart: "some-namespace/some-chart-name",
-				},
-			},
-			chart:           chart,
-			expectChart:     false,
-			wantErr:         true,
-			disallowCrossNS: true,
+		Spec: v2.HelmReleaseSpec{
+			ReleaseName: "podinfo",
+			Install:     &v2.Install{CreateNamespace: true},
 		},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			c := fake.NewClientBuilder()
-			c.WithScheme(NewTestScheme())
-			if tt.chart != nil {
-				c.WithObjects(tt.chart)
-			}
-
-			r := &HelmReleaseReconciler{
-				Client:        c.Build(),
-				EventRecorder: record.NewFakeRecorder(32),
-			}
-
-			curAllow := intacl.AllowCrossNamespaceRef
-			intacl.AllowCrossNamespaceRef = !tt.disallowCrossNS
-			t.Cleanup(func() { intacl.AllowCrossNamespaceRef = !curAllow })
 
-			got, err := r.getSource(context.TODO(), tt.rel)
-			if tt.wantErr {
-				g.Expect(err).To(HaveOccurred())
-				g.Expect(got).To(BeNil())
-				return
-			}
-			g.Expect(err).ToNot(HaveOccurred())
-			hc, ok := got.(*sourcev1.HelmChart)
-			g.Expect(ok).To(BeTrue())
-			expect := g.Expect(hc.ObjectMeta)
-			if tt.expectChart {
-				expect.To(BeEquivalentTo(tt.chart.ObjectMeta))
-			} else {
-				expect.To(BeNil())
-			}
-		})
-	}
+	data, err := effectiveConfigData(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data["spec.yaml"]).To(ContainSubstring("releaseName: podinfo"))
+	g.Expect(data["spec.yaml"]).To(ContainSubstring("createNamespace: true"))
+	g.Expect(data["spec.yaml"]).To(ContainSubstring("storageNamespace: default"))
+	g.Expect(data["spec.yaml"]).To(ContainSubstring("timeout: 5m0s"))
+	g.Expect(data["spec.yaml"]).To(ContainSubstring("strategy: rollback"))
+	g.Expect(data["featureGates.yaml"]).To(ContainSubstring(features.AllowDNSLookups + ": false"))
+
+	// The object itself is not mutated.
+	g.Expect(obj.Spec.Timeout).To(BeNil())
+	g.Expect(obj.Spec.Upgrade).To(BeNil())
 }
 
 func Test_waitForHistoryCacheSync(t *testing.T) {
@@ -3523,15 +5640,271 @@ func Test_waitForHistoryCacheSync(t *testing.T) {
 	}
 }
 
+func TestHelmReleaseReconciler_composeValues(t *testing.T) {
+	newHelmRelease := func(name, values string, refs ...v2.ValuesReference) *v2.HelmRelease {
+		return &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "mock",
+			},
+			Spec: v2.HelmReleaseSpec{
+				ValuesFrom: refs,
+				Values:     &apiextensionsv1.JSON{Raw: []byte(values)},
+			},
+		}
+	}
+	hrRef := func(name, targetPath string) v2.ValuesReference {
+		return v2.ValuesReference{Kind: v2.HelmReleaseKind, Name: name, TargetPath: targetPath}
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "team",
+			Namespace: "mock",
+		},
+		Data: map[string]string{
+			"values.yaml": "replicas: 2\nimage:\n  tag: team\n",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		objects []client.Object
+		obj     *v2.HelmRelease
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "layers HelmRelease values in order",
+			objects: []client.Object{
+				cm,
+				newHelmRelease("org", `{"replicas":1,"image":{"repository":"podinfo","tag":"org"}}`),
+			},
+			obj: newHelmRelease("app", `{"image":{"tag":"app"}}`,
+				hrRef("org", ""),
+				v2.ValuesReference{Kind: "ConfigMap", Name: "team"},
+			),
+			want: map[string]interface{}{
+				"replicas": float64(2),
+				"image": map[string]interface{}{
+					"repository": "podinfo",
+					"tag":        "app",
+				},
+			},
+		},
+		{
+			name: "composes values of HelmRelease recursively",
+			objects: []client.Object{
+				newHelmRelease("org", `{"replicas":1,"region":"eu"}`),
+				newHelmRelease("team", `{"replicas":3}`, hrRef("org", "")),
+			},
+			obj: newHelmRelease("app", `{}`, hrRef("team", "")),
+			want: map[string]interface{}{
+				"replicas": float64(3),
+				"region":   "eu",
+			},
+		},
+		{
+			name: "merges HelmRelease values at target path",
+			objects: []client.Object{
+				newHelmRelease("org", `{"region":"eu"}`),
+			},
+			obj: newHelmRelease("app", `{"replicas":1}`, hrRef("org", "global")),
+			want: map[string]interface{}{
+				"replicas": float64(1),
+				"global": map[string]interface{}{
+					"region": "eu",
+				},
+			},
+		},
+		{
+			name: "ignores optional missing HelmRelease",
+			obj: newHelmRelease("app", `{"replicas":1}`, v2.ValuesReference{
+				Kind:     v2.HelmReleaseKind,
+				Name:     "missing",
+				Optional: true,
+			}),
+			want: map[string]interface{}{
+				"replicas": float64(1),
+			},
+		},
+		{
+			name:    "missing HelmRelease",
+			obj:     newHelmRelease("app", `{}`, hrRef("missing", "")),
+			wantErr: "could not get HelmRelease values reference 'mock/missing'",
+		},
+		{
+			name: "circular reference",
+			objects: []client.Object{
+				newHelmRelease("team", `{}`, hrRef("app", "")),
+			},
+			obj:     newHelmRelease("app", `{}`, hrRef("team", "")),
+			wantErr: "circular HelmRelease values reference: mock/app -> mock/team -> mock/app",
+		},
+		{
+			name: "merges outputs of dependencies",
+			objects: []client.Object{
+				func() *v2.HelmRelease {
+					obj := newHelmRelease("database", `{"auth":{"secretName":"database-admin"}}`)
+					obj.Status.History = v2.Snapshots{{Name: "prod-database", Namespace: "mock"}}
+					return obj
+				}(),
+			},
+			obj: func() *v2.HelmRelease {
+				obj := newHelmRelease("app", `{"database":{"port":5432}}`)
+				obj.Spec.DependsOn = []v2.DependencyReference{{
+					Name: "database",
+					Outputs: []v2.DependencyOutput{
+						{FieldPath: "{.status.history[0].name}", TargetPath: "database.host"},
+						{FieldPath: ".spec.values.auth.secretName", TargetPath: "database.secretName"},
+					},
+				}}
+				return obj
+			}(),
+			want: map[string]interface{}{
+				"database": map[string]interface{}{
+					"port":       int64(5432),
+					"host":       "prod-database",
+					"secretName": "database-admin",
+				},
+			},
+		},
+		{
+			name: "denies outputs of dependency in other namespace",
+			obj: func() *v2.HelmRelease {
+				obj := newHelmRelease("app", `{}`)
+				obj.Spec.DependsOn = []v2.DependencyReference{{
+					Name:      "database",
+					Namespace: "other",
+					Outputs:   []v2.DependencyOutput{{FieldPath: "{.status.history[0].name}", TargetPath: "database.host"}},
+				}}
+				return obj
+			}(),
+			wantErr: "cross-namespace references are not allowed",
+		},
+		{
+			name: "missing output field",
+			objects: []client.Object{
+				newHelmRelease("database", `{}`),
+			},
+			obj: func() *v2.HelmRelease {
+				obj := newHelmRelease("app", `{}`)
+				obj.Spec.DependsOn = []v2.DependencyReference{{
+					Name:    "database",
+					Outputs: []v2.DependencyOutput{{FieldPath: "{.status.history[0].name}", TargetPath: "database.host"}},
+				}}
+				return obj
+			}(),
+			wantErr: "failed to merge outputs of dependency 'mock/database'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &HelmReleaseReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(NewTestScheme()).
+					WithObjects(tt.objects...).
+					Build(),
+			}
+			r.APIReader = r.Client
+
+			got, err := r.composeValues(context.TODO(), tt.obj)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestHelmReleaseReconciler_fieldReferenceReader(t *testing.T) {
+	newReconciler := func(calls *int) *HelmReleaseReconciler {
+		r := &HelmReleaseReconciler{
+			Client: fake.NewClientBuilder().WithScheme(NewTestScheme()).Build(),
+			GetClusterConfig: func() (*rest.Config, error) {
+				*calls++
+				return &rest.Config{Host: "https://example.com"}, nil
+			},
+		}
+		r.APIReader = r.Client
+		return r
+	}
+
+	t.Run("uses API reader without service account", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls int
+		r := newReconciler(&calls)
+		reader, err := r.fieldReferenceReader(&v2.HelmRelease{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(reader).To(BeIdenticalTo(r.APIReader))
+		g.Expect(calls).To(Equal(0))
+	})
+
+	t.Run("impersonates service account", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls int
+		r := newReconciler(&calls)
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "mock"},
+			Spec:       v2.HelmReleaseSpec{ServiceAccountName: "reconciler"},
+		}
+		reader, err := r.fieldReferenceReader(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(reader).ToNot(BeIdenticalTo(r.APIReader))
+		g.Expect(calls).To(Equal(1))
+	})
+
+	t.Run("impersonates default service account", func(t *testing.T) {
+		g := NewWithT(t)
+
+		defaultServiceAccount := kube.DefaultServiceAccountName
+		t.Cleanup(func() { kube.DefaultServiceAccountName = defaultServiceAccount })
+		kube.DefaultServiceAccountName = "default"
+
+		var calls int
+		r := newReconciler(&calls)
+		reader, err := r.fieldReferenceReader(&v2.HelmRelease{ObjectMeta: metav1.ObjectMeta{Namespace: "mock"}})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(reader).ToNot(BeIdenticalTo(r.APIReader))
+		g.Expect(calls).To(Equal(1))
+	})
+}
+
+func Test_indexValuesFrom(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "mock",
+		},
+		Spec: v2.HelmReleaseSpec{
+			ValuesFrom: []v2.ValuesReference{
+				{Kind: "ConfigMap", Name: "values"},
+				{Kind: v2.HelmReleaseKind, Name: "org"},
+				{Kind: v2.HelmReleaseKind, Name: "team"},
+			},
+		},
+	}
+	g.Expect(indexValuesFrom(obj)).To(Equal([]string{"mock/org", "mock/team"}))
+}
+
 func TestValuesReferenceValidation(t *testing.T) {
 	tests := []struct {
 		name       string
-		references []meta.ValuesReference
+		references []v2.ValuesReference
 		wantErr    bool
 	}{
 		{
 			name: "valid ValuesKey",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:      "Secret",
 					Name:      "values",
@@ -3542,7 +5915,7 @@ func TestValuesReferenceValidation(t *testing.T) {
 		},
 		{
 			name: "valid ValuesKey: empty",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:      "Secret",
 					Name:      "values",
@@ -3553,7 +5926,7 @@ func TestValuesReferenceValidation(t *testing.T) {
 		},
 		{
 			name: "valid ValuesKey: long",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:      "Secret",
 					Name:      "values",
@@ -3564,7 +5937,7 @@ func TestValuesReferenceValidation(t *testing.T) {
 		},
 		{
 			name: "invalid ValuesKey",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:      "Secret",
 					Name:      "values",
@@ -3575,7 +5948,7 @@ func TestValuesReferenceValidation(t *testing.T) {
 		},
 		{
 			name: "invalid ValuesKey: too long",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:      "Secret",
 					Name:      "values",
@@ -3586,7 +5959,7 @@ func TestValuesReferenceValidation(t *testing.T) {
 		},
 		{
 			name: "valid target path: empty",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:       "Secret",
 					Name:       "values",
@@ -3597,7 +5970,7 @@ func TestValuesReferenceValidation(t *testing.T) {
 		},
 		{
 			name: "valid target path",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:       "Secret",
 					Name:       "values",
@@ -3608,7 +5981,7 @@ func TestValuesReferenceValidation(t *testing.T) {
 		},
 		{
 			name: "valid target path: long",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:       "Secret",
 					Name:       "values",
@@ -3619,7 +5992,7 @@ func TestValuesReferenceValidation(t *testing.T) {
 		},
 		{
 			name: "invalid target path: too long",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:       "Secret",
 					Name:       "values",
@@ -3630,7 +6003,7 @@ func TestValuesReferenceValidation(t *testing.T) {
 		},
 		{
 			name: "invalid target path: opened index",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:       "Secret",
 					Name:       "values",
@@ -3642,7 +6015,7 @@ func TestValuesReferenceValidation(t *testing.T) {
 		},
 		{
 			name: "invalid target path: incorrect index syntax",
-			references: []meta.ValuesReference{
+			references: []v2.ValuesReference{
 				{
 					Kind:       "Secret",
 					Name:       "values",
@@ -3937,3 +6310,98 @@ func Test_TryMutateChartWithSourceRevision(t *testing.T) {
 	}
 
 }
+
+func Test_applyChartOverride(t *testing.T) {
+	attributes := map[string]string{"region": "eu-west-1", "tier": "production"}
+
+	tests := []struct {
+		name      string
+		spec      v2.HelmReleaseSpec
+		want      bool
+		wantChart *v2.HelmChartTemplate
+		wantRef   *v2.CrossNamespaceSourceReference
+	}{
+		{
+			name: "no overrides",
+			spec: v2.HelmReleaseSpec{
+				ChartRef: &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo"},
+			},
+			want:    false,
+			wantRef: &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo"},
+		},
+		{
+			name: "no matching override",
+			spec: v2.HelmReleaseSpec{
+				ChartRef: &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo"},
+				ChartOverrides: []v2.ChartOverride{
+					{
+						MatchAttributes: map[string]string{"region": "us-east-1"},
+						ChartRef:        &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo-us"},
+					},
+					{
+						MatchAttributes: map[string]string{"region": "eu-west-1", "zone": "a"},
+						ChartRef:        &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo-eu-a"},
+					},
+				},
+			},
+			want:    false,
+			wantRef: &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo"},
+		},
+		{
+			name: "first matching override for chart reference",
+			spec: v2.HelmReleaseSpec{
+				ChartRef: &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo"},
+				ChartOverrides: []v2.ChartOverride{
+					{
+						MatchAttributes: map[string]string{"region": "eu-west-1"},
+						ChartRef:        &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo-eu"},
+					},
+					{
+						MatchAttributes: map[string]string{"tier": "production"},
+						ChartRef:        &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo-lts"},
+					},
+				},
+			},
+			want:    true,
+			wantRef: &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "podinfo-eu"},
+		},
+		{
+			name: "matching override for chart template",
+			spec: v2.HelmReleaseSpec{
+				Chart: &v2.HelmChartTemplate{
+					Spec: v2.HelmChartTemplateSpec{
+						Chart:     "podinfo",
+						Version:   "6.x",
+						SourceRef: v2.CrossNamespaceObjectReference{Kind: sourcev1.HelmRepositoryKind, Name: "podinfo"},
+					},
+				},
+				ChartOverrides: []v2.ChartOverride{
+					{
+						MatchAttributes: map[string]string{"tier": "production"},
+						Version:         "6.5.x",
+						SourceRef:       &v2.CrossNamespaceObjectReference{Kind: sourcev1.HelmRepositoryKind, Name: "podinfo-mirror"},
+						ChartRef:        &v2.CrossNamespaceSourceReference{Kind: sourcev1beta2.OCIRepositoryKind, Name: "ignored"},
+					},
+				},
+			},
+			want: true,
+			wantChart: &v2.HelmChartTemplate{
+				Spec: v2.HelmChartTemplateSpec{
+					Chart:     "podinfo",
+					Version:   "6.5.x",
+					SourceRef: v2.CrossNamespaceObjectReference{Kind: sourcev1.HelmRepositoryKind, Name: "podinfo-mirror"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &v2.HelmRelease{Spec: tt.spec}
+			g.Expect(applyChartOverride(obj, attributes)).To(Equal(tt.want))
+			g.Expect(obj.Spec.Chart).To(Equal(tt.wantChart))
+			g.Expect(obj.Spec.ChartRef).To(Equal(tt.wantRef))
+		})
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apierrutil "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/patch"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// +kubebuilder:rbac:groups=helm.toolkit.fluxcd.io,resources=helmreleasegroups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=helm.toolkit.fluxcd.io,resources=helmreleasegroups/status,verbs=get;update;patch
+
+// HelmReleaseGroupReconciler reconciles a HelmReleaseGroup object, by
+// reporting the phase of which the HelmReleases are not all ready yet. The
+// barriers between the phases are enforced by the HelmReleaseReconciler.
+type HelmReleaseGroupReconciler struct {
+	client.Client
+
+	FieldManager string
+}
+
+func (r *HelmReleaseGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v2.HelmReleaseGroup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&v2.HelmRelease{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForHelmReleaseChange),
+		).
+		Complete(r)
+}
+
+func (r *HelmReleaseGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
+	obj := &v2.HelmReleaseGroup{}
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	patchHelper := patch.NewSerialPatcher(obj, r.Client)
+	defer func() {
+		if err := patchHelper.Patch(ctx, obj,
+			patch.WithFieldOwner(r.FieldManager),
+			patch.WithOwnedConditions{Conditions: []string{meta.ReadyCondition}},
+			patch.WithStatusObservedGeneration{},
+		); err != nil {
+			retErr = apierrutil.Reduce(apierrutil.NewAggregate([]error{retErr, err}))
+		}
+	}()
+
+	for i, phase := range obj.Spec.Phases {
+		releases, err := listPhaseReleases(ctx, r.Client, obj, i)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, "%s", err)
+			return ctrl.Result{}, reconcile.TerminalError(err)
+		}
+
+		var ready int
+		for j := range releases {
+			if checkDependencyReady(client.ObjectKeyFromObject(&releases[j]), &releases[j]) == nil {
+				ready++
+			}
+		}
+		if ready < len(releases) {
+			obj.Status.CurrentPhase = phase.Name
+			conditions.MarkFalse(obj, meta.ReadyCondition, v2.PhaseNotReadyReason,
+				"Phase '%s' has %d/%d HelmReleases ready", phase.Name, ready, len(releases))
+			return ctrl.Result{}, nil
+		}
+	}
+
+	obj.Status.CurrentPhase = ""
+	conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason,
+		"All %d phases are ready", len(obj.Spec.Phases))
+	return ctrl.Result{}, nil
+}
+
+// requestsForHelmReleaseChange returns the requests for the
+// HelmReleaseGroups in the namespace of the given HelmRelease. All groups
+// are requested, as a change of the labels of the HelmRelease may have
+// moved it out of a phase.
+func (r *HelmReleaseGroupReconciler) requestsForHelmReleaseChange(ctx context.Context, o client.Object) []reconcile.Request {
+	groups, err := listHelmReleaseGroups(ctx, r.Client, o.GetNamespace())
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list HelmReleaseGroups for HelmRelease change")
+		return nil
+	}
+
+	reqs := make([]reconcile.Request, len(groups))
+	for i := range groups {
+		reqs[i].NamespacedName = client.ObjectKeyFromObject(&groups[i])
+	}
+	return reqs
+}
+
+// listPhaseReleases returns the HelmReleases selected by the phase with the
+// given index of the v2.HelmReleaseGroup.
+func listPhaseReleases(ctx context.Context, c client.Reader, group *v2.HelmReleaseGroup, phase int) ([]v2.HelmRelease, error) {
+	p := group.Spec.Phases[phase]
+	selector, err := metav1.LabelSelectorAsSelector(&p.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of phase '%s': %w", p.Name, err)
+	}
+	if selector.Empty() {
+		return nil, nil
+	}
+
+	var list v2.HelmReleaseList
+	if err := c.List(ctx, &list, client.InNamespace(group.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list HelmReleases of phase '%s': %w", p.Name, err)
+	}
+	return list.Items, nil
+}
+
+// listHelmReleaseGroups returns the v2.HelmReleaseGroups in the given
+// namespace. It returns an empty list if the HelmReleaseGroup API is not
+// installed in the cluster.
+func listHelmReleaseGroups(ctx context.Context, c client.Reader, namespace string) ([]v2.HelmReleaseGroup, error) {
+	var list v2.HelmReleaseGroupList
+	if err := c.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+		if apimeta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return list.Items, nil
+}
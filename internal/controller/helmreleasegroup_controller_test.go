@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func newTestHelmReleaseGroup() *v2.HelmReleaseGroup {
+	return &v2.HelmReleaseGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "bootstrap",
+			Namespace:  "flux-system",
+			Generation: 1,
+		},
+		Spec: v2.HelmReleaseGroupSpec{
+			Phases: []v2.HelmReleaseGroupPhase{
+				{Name: "crds", Selector: metav1.LabelSelector{MatchLabels: map[string]string{"phase": "crds"}}},
+				{Name: "platform", Selector: metav1.LabelSelector{MatchLabels: map[string]string{"phase": "platform"}}},
+				{Name: "apps", Selector: metav1.LabelSelector{MatchLabels: map[string]string{"phase": "apps"}}},
+			},
+		},
+	}
+}
+
+func newTestGroupMember(name, phase, readyReason string) *v2.HelmRelease {
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "flux-system",
+			Generation: 1,
+			Labels:     map[string]string{"phase": phase},
+		},
+		Status: v2.HelmReleaseStatus{
+			ObservedGeneration: 1,
+		},
+	}
+	if readyReason == meta.SucceededReason {
+		conditions.MarkTrue(obj, meta.ReadyCondition, readyReason, "ready")
+	} else {
+		conditions.MarkFalse(obj, meta.ReadyCondition, readyReason, "not ready")
+	}
+	return obj
+}
+
+func TestHelmReleaseGroupReconciler_Reconcile(t *testing.T) {
+	tests := []struct {
+		name             string
+		releases         []client.Object
+		wantReady        bool
+		wantCurrentPhase string
+		wantMessage      string
+	}{
+		{
+			name: "all phases ready",
+			releases: []client.Object{
+				newTestGroupMember("cert-manager", "crds", meta.SucceededReason),
+				newTestGroupMember("ingress", "platform", meta.SucceededReason),
+				newTestGroupMember("podinfo", "apps", meta.SucceededReason),
+			},
+			wantReady:   true,
+			wantMessage: "All 3 phases are ready",
+		},
+		{
+			name: "phase not ready",
+			releases: []client.Object{
+				newTestGroupMember("cert-manager", "crds", meta.SucceededReason),
+				newTestGroupMember("ingress", "platform", meta.SucceededReason),
+				newTestGroupMember("monitoring", "platform", v2.UpgradeFailedReason),
+				newTestGroupMember("podinfo", "apps", v2.DependencyNotReadyReason),
+			},
+			wantCurrentPhase: "platform",
+			wantMessage:      "Phase 'platform' has 1/2 HelmReleases ready",
+		},
+		{
+			name:        "phases without releases",
+			wantReady:   true,
+			wantMessage: "All 3 phases are ready",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			group := newTestHelmReleaseGroup()
+			c := fake.NewClientBuilder().
+				WithScheme(NewTestScheme()).
+				WithStatusSubresource(&v2.HelmReleaseGroup{}).
+				WithObjects(append(tt.releases, group)...).
+				Build()
+
+			r := &HelmReleaseGroupReconciler{Client: c}
+			_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(group)})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got := &v2.HelmReleaseGroup{}
+			g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(group), got)).To(Succeed())
+			g.Expect(conditions.IsTrue(got, meta.ReadyCondition)).To(Equal(tt.wantReady))
+			g.Expect(conditions.GetMessage(got, meta.ReadyCondition)).To(Equal(tt.wantMessage))
+			g.Expect(got.Status.CurrentPhase).To(Equal(tt.wantCurrentPhase))
+			g.Expect(got.Status.ObservedGeneration).To(Equal(got.Generation))
+		})
+	}
+}
+
+func TestHelmReleaseReconciler_checkGroupBarriers(t *testing.T) {
+	g := NewWithT(t)
+
+	crds := newTestGroupMember("cert-manager", "crds", meta.SucceededReason)
+	platform := newTestGroupMember("ingress", "platform", v2.UpgradeFailedReason)
+	apps := newTestGroupMember("podinfo", "apps", v2.DependencyNotReadyReason)
+	unrelated := newTestGroupMember("unrelated", "", v2.DependencyNotReadyReason)
+	unrelated.Labels = nil
+
+	c := fake.NewClientBuilder().
+		WithScheme(NewTestScheme()).
+		WithObjects(newTestHelmReleaseGroup(), crds, platform, apps, unrelated).
+		Build()
+	r := &HelmReleaseReconciler{Client: c}
+
+	g.Expect(r.checkGroupBarriers(context.TODO(), crds)).To(Succeed())
+	g.Expect(r.checkGroupBarriers(context.TODO(), platform)).To(Succeed())
+	g.Expect(r.checkGroupBarriers(context.TODO(), unrelated)).To(Succeed())
+
+	err := r.checkGroupBarriers(context.TODO(), apps)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("phase 'apps' of HelmReleaseGroup 'bootstrap' is waiting for phase 'platform'"))
+	g.Expect(err.Error()).To(ContainSubstring("flux-system/ingress"))
+
+	// The barrier is no longer enforced once released successfully.
+	released := apps.DeepCopy()
+	released.Status.History = v2.Snapshots{
+		{Name: "podinfo", Namespace: "flux-system", Version: 2, Status: helmrelease.StatusFailed.String()},
+		{Name: "podinfo", Namespace: "flux-system", Version: 1, Status: helmrelease.StatusSuperseded.String()},
+	}
+	g.Expect(r.checkGroupBarriers(context.TODO(), released)).To(Succeed())
+
+	// Unless the group always enforces the barrier.
+	group := &v2.HelmReleaseGroup{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(newTestHelmReleaseGroup()), group)).To(Succeed())
+	group.Spec.BarrierPolicy = v2.BarrierPolicyAlways
+	g.Expect(c.Update(context.TODO(), group)).To(Succeed())
+	g.Expect(r.checkGroupBarriers(context.TODO(), released)).ToNot(Succeed())
+}
+
+func TestHelmReleaseReconciler_requestsForGroupBarrier(t *testing.T) {
+	g := NewWithT(t)
+
+	crds := newTestGroupMember("cert-manager", "crds", meta.SucceededReason)
+	platform := newTestGroupMember("ingress", "platform", v2.DependencyNotReadyReason)
+	apps := newTestGroupMember("podinfo", "apps", v2.DependencyNotReadyReason)
+	failed := newTestGroupMember("failed", "apps", v2.UpgradeFailedReason)
+
+	c := fake.NewClientBuilder().
+		WithScheme(NewTestScheme()).
+		WithObjects(newTestHelmReleaseGroup(), crds, platform, apps, failed).
+		Build()
+	r := &HelmReleaseReconciler{Client: c}
+
+	g.Expect(r.requestsForGroupBarrier(context.TODO(), crds)).To(ConsistOf(
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "flux-system", Name: "ingress"}},
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "flux-system", Name: "podinfo"}},
+	))
+	g.Expect(r.requestsForGroupBarrier(context.TODO(), apps)).To(BeEmpty())
+}
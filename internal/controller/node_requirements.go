@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/kube"
+)
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=list
+
+// checkNodeRequirements confirms the nodes of the cluster of the given
+// v2.HelmRelease provide the capabilities required by the given
+// v2.NodeRequirements. The nodes are listed using the identity of the
+// controller, as the service account impersonated for the release is
+// generally not allowed to list the cluster-scoped nodes: for the cluster
+// the controller runs in using its API reader, and for a remote cluster
+// using the identity of the KubeConfig without impersonation. It returns a
+// terminal error when the requirements are invalid, or when the nodes can
+// not be listed due to a lack of permissions.
+func (r *HelmReleaseReconciler) checkNodeRequirements(ctx context.Context, getter genericclioptions.RESTClientGetter, obj *v2.HelmRelease, req v2.NodeRequirements) error {
+	if err := validateNodeRequirements(req); err != nil {
+		return reconcile.TerminalError(err)
+	}
+
+	reader := r.APIReader
+	if obj.Spec.KubeConfig != nil {
+		cfg, err := getter.ToRESTConfig()
+		if err != nil {
+			return err
+		}
+		mapper, err := getter.ToRESTMapper()
+		if err != nil {
+			return err
+		}
+		cfg = rest.CopyConfig(cfg)
+		cfg.Impersonate = rest.ImpersonationConfig{}
+		if reader, err = kube.NewClientForConfig(cfg, mapper); err != nil {
+			return err
+		}
+	}
+
+	var nodes corev1.NodeList
+	if err := reader.List(ctx, &nodes); err != nil {
+		if apierrors.IsForbidden(err) {
+			return reconcile.TerminalError(fmt.Errorf("the controller is not allowed to list the nodes of the cluster: %w", err))
+		}
+		return fmt.Errorf("unable to list nodes: %w", err)
+	}
+	return nodeRequirementsMet(nodes.Items, req)
+}
+
+// validateNodeRequirements returns an error if any of the node selectors of
+// the given v2.NodeRequirements is invalid.
+func validateNodeRequirements(req v2.NodeRequirements) error {
+	for i := range req.NodeSelectors {
+		if _, err := metav1.LabelSelectorAsSelector(&req.NodeSelectors[i]); err != nil {
+			return fmt.Errorf("invalid node selector: %w", err)
+		}
+	}
+	return nil
+}
+
+// nodeRequirementsMet returns an error listing the requirements of the given
+// v2.NodeRequirements which are not provided by any of the schedulable nodes
+// of the given list.
+func nodeRequirementsMet(nodes []corev1.Node, req v2.NodeRequirements) error {
+	schedulable := make([]corev1.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if !n.Spec.Unschedulable {
+			schedulable = append(schedulable, n)
+		}
+	}
+
+	var unmet []string
+	for _, arch := range req.Architectures {
+		if !anyNodeMatches(schedulable, labels.SelectorFromSet(labels.Set{corev1.LabelArchStable: arch})) {
+			unmet = append(unmet, fmt.Sprintf("no schedulable node with architecture '%s'", arch))
+		}
+	}
+	for i := range req.NodeSelectors {
+		selector, err := metav1.LabelSelectorAsSelector(&req.NodeSelectors[i])
+		if err != nil {
+			return fmt.Errorf("invalid node selector: %w", err)
+		}
+		if !anyNodeMatches(schedulable, selector) {
+			unmet = append(unmet, fmt.Sprintf("no schedulable node matching '%s'", selector.String()))
+		}
+	}
+	if len(unmet) > 0 {
+		return fmt.Errorf("node requirements not met: %s", strings.Join(unmet, ", "))
+	}
+	return nil
+}
+
+// anyNodeMatches returns true if any of the given nodes matches the given
+// selector.
+func anyNodeMatches(nodes []corev1.Node, selector labels.Selector) bool {
+	for _, n := range nodes {
+		if selector.Matches(labels.Set(n.GetLabels())) {
+			return true
+		}
+	}
+	return false
+}
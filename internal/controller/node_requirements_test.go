@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func Test_nodeRequirementsMet(t *testing.T) {
+	newNode := func(name string, unschedulable bool, labels map[string]string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		}
+	}
+	nodes := []corev1.Node{
+		newNode("amd64", false, map[string]string{corev1.LabelArchStable: "amd64"}),
+		newNode("arm64-cordoned", true, map[string]string{corev1.LabelArchStable: "arm64"}),
+		newNode("gpu", false, map[string]string{corev1.LabelArchStable: "amd64", "nvidia.com/gpu.present": "true"}),
+	}
+
+	tests := []struct {
+		name    string
+		req     v2.NodeRequirements
+		wantErr string
+	}{
+		{
+			name: "architecture provided",
+			req:  v2.NodeRequirements{Architectures: []string{"amd64"}},
+		},
+		{
+			name:    "architecture only provided by unschedulable node",
+			req:     v2.NodeRequirements{Architectures: []string{"amd64", "arm64"}},
+			wantErr: "node requirements not met: no schedulable node with architecture 'arm64'",
+		},
+		{
+			name: "node selector matched",
+			req: v2.NodeRequirements{NodeSelectors: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{"nvidia.com/gpu.present": "true"}},
+			}},
+		},
+		{
+			name: "node selector not matched",
+			req: v2.NodeRequirements{NodeSelectors: []metav1.LabelSelector{
+				{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "amd.com/gpu", Operator: metav1.LabelSelectorOpExists},
+				}},
+			}},
+			wantErr: "node requirements not met: no schedulable node matching 'amd.com/gpu'",
+		},
+		{
+			name: "invalid node selector",
+			req: v2.NodeRequirements{NodeSelectors: []metav1.LabelSelector{
+				{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "amd.com/gpu", Operator: "Invalid"},
+				}},
+			}},
+			wantErr: "invalid node selector",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := nodeRequirementsMet(nodes, tt.req)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestHelmReleaseReconciler_checkNodeRequirements(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node",
+			Labels: map[string]string{corev1.LabelArchStable: "amd64"},
+		},
+	}
+	forbidden := interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			return apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", errors.New("forbidden"))
+		},
+	}
+
+	tests := []struct {
+		name         string
+		req          v2.NodeRequirements
+		interceptor  interceptor.Funcs
+		wantErr      string
+		wantTerminal bool
+	}{
+		{
+			name: "requirements met",
+			req:  v2.NodeRequirements{Architectures: []string{"amd64"}},
+		},
+		{
+			name:    "requirements not met",
+			req:     v2.NodeRequirements{Architectures: []string{"arm64"}},
+			wantErr: "no schedulable node with architecture 'arm64'",
+		},
+		{
+			name: "invalid node selector",
+			req: v2.NodeRequirements{NodeSelectors: []metav1.LabelSelector{
+				{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "amd.com/gpu", Operator: "Invalid"},
+				}},
+			}},
+			wantErr:      "invalid node selector",
+			wantTerminal: true,
+		},
+		{
+			name:         "not allowed to list nodes",
+			req:          v2.NodeRequirements{Architectures: []string{"amd64"}},
+			interceptor:  forbidden,
+			wantErr:      "the controller is not allowed to list the nodes of the cluster",
+			wantTerminal: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().
+				WithScheme(NewTestScheme()).
+				WithObjects(node).
+				WithInterceptorFuncs(tt.interceptor).
+				Build()
+			r := &HelmReleaseReconciler{Client: c, APIReader: c}
+
+			err := r.checkNodeRequirements(context.TODO(), nil, &v2.HelmRelease{}, tt.req)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				g.Expect(errors.Is(err, reconcile.TerminalError(nil))).To(Equal(tt.wantTerminal))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
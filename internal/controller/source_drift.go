@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// sourceAvailability keeps track of the time since which sources have been
+// observed to not be ready. This is kept in memory rather than derived from
+// the Ready condition of the source, as its last transition time is reset
+// every time the source controller marks it as reconciling.
+type sourceAvailability struct {
+	mu    sync.Mutex
+	since map[string]time.Time
+
+	// now returns the current time, and can be overwritten in tests.
+	now func() time.Time
+}
+
+// newSourceAvailability returns a new sourceAvailability.
+func newSourceAvailability() *sourceAvailability {
+	return &sourceAvailability{
+		since: make(map[string]time.Time),
+		now:   time.Now,
+	}
+}
+
+// observe records whether the source with the given key is ready, and
+// returns the time since which it has not been ready. The returned time is
+// zero when the source is ready.
+func (s *sourceAvailability) observe(key string, ready bool) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ready {
+		delete(s.since, key)
+		return time.Time{}
+	}
+	since, ok := s.since[key]
+	if !ok {
+		since = s.now()
+		s.since[key] = since
+	}
+	return since
+}
+
+// chartVersionDecreased returns the highest chart version in the given
+// release history which is higher than the given chart version, while
+// matching the given version constraint. This indicates the version
+// has been deleted from the chart repository, as the source would otherwise
+// still resolve to it. An empty string is returned if there is no such
+// version, or if the versions or constraint can not be parsed.
+func chartVersionDecreased(history v2.Snapshots, constraint, version string) string {
+	if constraint == "" {
+		constraint = "*"
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return ""
+	}
+	cur, err := semver.NewVersion(version)
+	if err != nil {
+		return ""
+	}
+
+	var highest *semver.Version
+	for _, snap := range history {
+		v, err := semver.NewVersion(snap.ChartVersion)
+		if err != nil || !v.GreaterThan(cur) || !c.Check(v) {
+			continue
+		}
+		if highest == nil || v.GreaterThan(highest) {
+			highest = v
+		}
+	}
+	if highest == nil {
+		return ""
+	}
+	return highest.Original()
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func Test_sourceAvailability(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	s := newSourceAvailability()
+	s.now = func() time.Time { return now }
+
+	g.Expect(s.observe("HelmChart/default/podinfo", true)).To(BeZero())
+
+	// The first observation of a source which is not ready is retained.
+	g.Expect(s.observe("HelmChart/default/podinfo", false)).To(Equal(now))
+	first := now
+	now = now.Add(time.Hour)
+	g.Expect(s.observe("HelmChart/default/podinfo", false)).To(Equal(first))
+	g.Expect(s.observe("HelmChart/default/redis", false)).To(Equal(now))
+
+	// Becoming ready resets the observation.
+	g.Expect(s.observe("HelmChart/default/podinfo", true)).To(BeZero())
+	g.Expect(s.observe("HelmChart/default/podinfo", false)).To(Equal(now))
+}
+
+func Test_chartVersionDecreased(t *testing.T) {
+	history := v2.Snapshots{
+		{Version: 3, ChartVersion: "1.2.0"},
+		{Version: 2, ChartVersion: "2.0.0"},
+		{Version: 1, ChartVersion: "1.1.0"},
+	}
+
+	tests := []struct {
+		name       string
+		constraint string
+		version    string
+		want       string
+	}{
+		{
+			name:       "higher version",
+			constraint: ">=1.0.0",
+			version:    "2.1.0",
+			want:       "",
+		},
+		{
+			name:       "same version",
+			constraint: ">=1.0.0",
+			version:    "2.0.0",
+			want:       "",
+		},
+		{
+			name:       "decreased version within range",
+			constraint: ">=1.0.0",
+			version:    "1.2.0",
+			want:       "2.0.0",
+		},
+		{
+			name:       "decreased version without constraint",
+			constraint: "",
+			version:    "1.0.0",
+			want:       "2.0.0",
+		},
+		{
+			name:       "previous versions outside of range",
+			constraint: "<2.0.0",
+			version:    "1.2.0",
+			want:       "",
+		},
+		{
+			name:       "decreased version within narrowed range",
+			constraint: "1.x",
+			version:    "1.1.0",
+			want:       "1.2.0",
+		},
+		{
+			name:       "decreased version within space-separated range",
+			constraint: ">=1.0.0 <3.0.0",
+			version:    "1.2.0",
+			want:       "2.0.0",
+		},
+		{
+			name:       "invalid constraint",
+			constraint: "invalid",
+			version:    "1.0.0",
+			want:       "",
+		},
+		{
+			name:       "invalid version",
+			constraint: ">=1.0.0",
+			version:    "sha256:foo",
+			want:       "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(chartVersionDecreased(history, tt.constraint, tt.version)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+)
+
+// ManifestChanges holds the names of the resources which are added, changed
+// and removed between two (multi-document) YAML manifests, in the format
+// `kind/namespace/name`.
+type ManifestChanges struct {
+	Added   []string `json:"added,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Empty returns true if there are no changes.
+func (c ManifestChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Removed) == 0
+}
+
+// Summarize returns a summary of the changes, with one line per resource in
+// the format `kind/namespace/name <added|changed|removed>`. At most limit
+// resources are included, followed by a line with the number of omitted
+// resources. A limit of zero or less includes all resources.
+//
+// For example:
+//
+//	3 added, 1 changed, 1 removed
+//	Deployment/default/hello-world changed
+//	Service/default/hello-world added
+//	... and 3 more
+func (c ManifestChanges) Summarize(limit int) string {
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("%d added, %d changed, %d removed", len(c.Added), len(c.Changed), len(c.Removed)))
+
+	var n int
+	for _, set := range []struct {
+		names  []string
+		change string
+	}{
+		{c.Changed, "changed"},
+		{c.Added, "added"},
+		{c.Removed, "removed"},
+	} {
+		for _, name := range set.names {
+			if limit > 0 && n == limit {
+				total := len(c.Added) + len(c.Changed) + len(c.Removed)
+				summary.WriteString(fmt.Sprintf("\n... and %d more", total-limit))
+				return summary.String()
+			}
+			summary.WriteString(fmt.Sprintf("\n%s %s", name, set.change))
+			n++
+		}
+	}
+	return summary.String()
+}
+
+// Manifests compares the resources in the current and desired (multi-document)
+// YAML manifests, and returns the resources which are added, changed and
+// removed in the desired manifest.
+func Manifests(current, desired string) (ManifestChanges, error) {
+	currentObjs, err := readManifest(current)
+	if err != nil {
+		return ManifestChanges{}, fmt.Errorf("failed to read current manifest: %w", err)
+	}
+	desiredObjs, err := readManifest(desired)
+	if err != nil {
+		return ManifestChanges{}, fmt.Errorf("failed to read desired manifest: %w", err)
+	}
+
+	var changes ManifestChanges
+	for name, obj := range desiredObjs {
+		cur, ok := currentObjs[name]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, name)
+		case !apiequality.Semantic.DeepEqual(cur.Object, obj.Object):
+			changes.Changed = append(changes.Changed, name)
+		}
+	}
+	for name := range currentObjs {
+		if _, ok := desiredObjs[name]; !ok {
+			changes.Removed = append(changes.Removed, name)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Changed)
+	sort.Strings(changes.Removed)
+	return changes, nil
+}
+
+// readManifest returns the objects in the given manifest, indexed by their
+// resource name.
+func readManifest(manifest string) (map[string]*unstructured.Unstructured, error) {
+	objs, err := ssautil.ReadObjects(strings.NewReader(manifest))
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]*unstructured.Unstructured, len(objs))
+	for _, obj := range objs {
+		index[ResourceName(obj)] = obj
+	}
+	return index, nil
+}
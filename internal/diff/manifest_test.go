@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	currentManifest = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: changed
+  namespace: default
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: removed
+  namespace: default
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unchanged
+  namespace: default
+data:
+  key: value
+`
+	desiredManifest = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: changed
+  namespace: default
+data:
+  key: changed
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unchanged
+  namespace: default
+data:
+  key: value
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: added
+  namespace: default
+`
+)
+
+func TestManifests(t *testing.T) {
+	g := NewWithT(t)
+
+	got, err := Manifests(currentManifest, desiredManifest)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(ManifestChanges{
+		Added:   []string{"Service/default/added"},
+		Changed: []string{"ConfigMap/default/changed"},
+		Removed: []string{"ConfigMap/default/removed"},
+	}))
+	g.Expect(got.Empty()).To(BeFalse())
+
+	got, err = Manifests(desiredManifest, desiredManifest)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Empty()).To(BeTrue())
+
+	got, err = Manifests("", desiredManifest)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Added).To(HaveLen(3))
+
+	_, err = Manifests("invalid: [", desiredManifest)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestManifestChanges_Summarize(t *testing.T) {
+	changes := ManifestChanges{
+		Added:   []string{"Service/default/added"},
+		Changed: []string{"ConfigMap/default/changed"},
+		Removed: []string{"ConfigMap/default/removed"},
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  string
+	}{
+		{
+			name: "all resources",
+			want: `1 added, 1 changed, 1 removed
+ConfigMap/default/changed changed
+Service/default/added added
+ConfigMap/default/removed removed`,
+		},
+		{
+			name:  "limited resources",
+			limit: 1,
+			want: `1 added, 1 changed, 1 removed
+ConfigMap/default/changed changed
+... and 2 more`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(changes.Summarize(tt.limit)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ValuesChanges holds the keys of the values which are added, changed and
+// removed between two sets of Helm values, in the format of a dot-separated
+// path, e.g. `image.tag`.
+type ValuesChanges struct {
+	Added   []string `json:"added,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Empty returns true if there are no changes.
+func (c ValuesChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Removed) == 0
+}
+
+// Values compares the current and desired values, and returns the keys which
+// are added, changed and removed in the desired values. Nested maps are
+// compared key by key, while any other value (including lists) is compared
+// as a whole.
+func Values(current, desired map[string]any) ValuesChanges {
+	var changes ValuesChanges
+	compareValues("", current, desired, &changes)
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Changed)
+	sort.Strings(changes.Removed)
+	return changes
+}
+
+func compareValues(prefix string, current, desired map[string]any, changes *ValuesChanges) {
+	for k, d := range desired {
+		path := prefix + k
+		c, ok := current[k]
+		if !ok {
+			changes.Added = append(changes.Added, path)
+			continue
+		}
+		cm, cIsMap := c.(map[string]any)
+		dm, dIsMap := d.(map[string]any)
+		switch {
+		case cIsMap && dIsMap:
+			compareValues(path+".", cm, dm, changes)
+		case !reflect.DeepEqual(c, d):
+			changes.Changed = append(changes.Changed, path)
+		}
+	}
+	for k := range current {
+		if _, ok := desired[k]; !ok {
+			changes.Removed = append(changes.Removed, prefix+k)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestValues(t *testing.T) {
+	g := NewWithT(t)
+
+	current := map[string]any{
+		"replicaCount": 1,
+		"image": map[string]any{
+			"repository": "ghcr.io/stefanprodan/podinfo",
+			"tag":        "6.5.0",
+		},
+		"ingress": map[string]any{"enabled": true},
+		"args":    []any{"--level=info"},
+	}
+	desired := map[string]any{
+		"replicaCount": 1,
+		"image": map[string]any{
+			"repository": "ghcr.io/stefanprodan/podinfo",
+			"tag":        "6.6.1",
+			"pullPolicy": "Always",
+		},
+		"ingress": false,
+		"args":    []any{"--level=debug"},
+	}
+
+	got := Values(current, desired)
+	g.Expect(got.Added).To(Equal([]string{"image.pullPolicy"}))
+	g.Expect(got.Changed).To(Equal([]string{"args", "image.tag", "ingress"}))
+	g.Expect(got.Removed).To(BeEmpty())
+	g.Expect(got.Empty()).To(BeFalse())
+
+	got = Values(desired, current)
+	g.Expect(got.Removed).To(Equal([]string{"image.pullPolicy"}))
+
+	g.Expect(Values(current, current).Empty()).To(BeTrue())
+	g.Expect(Values(nil, map[string]any{"foo": "bar"}).Added).To(Equal([]string{"foo"}))
+}
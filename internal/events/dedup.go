@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// dedupPruneThreshold is the number of entries in the deduplicator above
+// which expired entries are pruned.
+const dedupPruneThreshold = 1024
+
+// deduplicator keeps track of the events recorded for an object, to suppress
+// identical events which are recorded again within a window, e.g. because a
+// persistent failure is reported on every reconciliation.
+type deduplicator struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]dedupEntry
+}
+
+type dedupEntry struct {
+	hash     [sha256.Size]byte
+	recorded time.Time
+}
+
+func newDeduplicator(window time.Duration) *deduplicator {
+	return &deduplicator{
+		window:  window,
+		now:     time.Now,
+		entries: make(map[string]dedupEntry),
+	}
+}
+
+// shouldRecord returns true if the event with the given type, reason and
+// message must be recorded for the given object, i.e. if it differs from the
+// last event with the same reason, or if the window has passed since that
+// event was recorded. Objects which can not be identified are never
+// deduplicated.
+func (d *deduplicator) shouldRecord(object runtime.Object, eventtype, reason, message string) bool {
+	obj, err := apimeta.Accessor(object)
+	if err != nil {
+		return true
+	}
+	key := fmt.Sprintf("%s/%s/%s/%s", obj.GetUID(), obj.GetNamespace(), obj.GetName(), reason)
+	hash := sha256.Sum256([]byte(eventtype + "\x00" + message))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	if e, ok := d.entries[key]; ok && e.hash == hash && now.Sub(e.recorded) < d.window {
+		return false
+	}
+	d.entries[key] = dedupEntry{hash: hash, recorded: now}
+
+	if len(d.entries) > dedupPruneThreshold {
+		for k, e := range d.entries {
+			if now.Sub(e.recorded) >= d.window {
+				delete(d.entries, k)
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+func TestRecorder_WithDedupWindow(t *testing.T) {
+	g := NewWithT(t)
+
+	fake := testutil.NewFakeRecorder(10, false)
+	r := NewRecorder(fake, v2.EventVerbosityInfo, WithDedupWindow(time.Minute))
+	now := time.Now()
+	r.dedup.now = func() time.Time { return now }
+
+	obj := &v2.HelmRelease{ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default", UID: "uid"}}
+	other := &v2.HelmRelease{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"}}
+
+	record := func(obj *v2.HelmRelease, reason, message string) {
+		r.Eventf(obj, corev1.EventTypeWarning, reason, "%s", message)
+	}
+	messages := func() (got []string) {
+		for _, e := range fake.GetEvents() {
+			got = append(got, e.Reason+": "+e.Message)
+		}
+		return got
+	}
+
+	// Identical events are recorded once.
+	record(obj, v2.UpgradeFailedReason, "timed out")
+	record(obj, v2.UpgradeFailedReason, "timed out")
+	g.Expect(messages()).To(Equal([]string{"UpgradeFailed: timed out"}))
+
+	// A change of message, a different reason or object is recorded.
+	record(obj, v2.UpgradeFailedReason, "context deadline exceeded")
+	record(obj, "DriftDetected", "drifted")
+	record(other, v2.UpgradeFailedReason, "context deadline exceeded")
+	g.Expect(messages()).To(Equal([]string{
+		"UpgradeFailed: context deadline exceeded",
+		"DriftDetected: drifted",
+		"UpgradeFailed: context deadline exceeded",
+	}))
+
+	// Interleaved events do not defeat the deduplication.
+	record(obj, v2.UpgradeFailedReason, "context deadline exceeded")
+	g.Expect(messages()).To(BeEmpty())
+
+	// Identical events are recorded again after the window.
+	now = now.Add(time.Minute)
+	record(obj, v2.UpgradeFailedReason, "context deadline exceeded")
+	g.Expect(messages()).To(Equal([]string{"UpgradeFailed: context deadline exceeded"}))
+}
+
+func TestWithDedupWindow(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(NewRecorder(nil, v2.EventVerbosityInfo, WithDedupWindow(0)).dedup).To(BeNil())
+	g.Expect(NewRecorder(nil, v2.EventVerbosityInfo, WithDedupWindow(time.Second)).dedup).ToNot(BeNil())
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	kuberecorder "k8s.io/client-go/tools/record"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// failureReasonSuffixes are the suffixes of the event reasons which signal
+// a failure, and must therefore be recorded as Warning events regardless of
+// the event type they were emitted with.
+var failureReasonSuffixes = []string{"Failed", "Failure", "Error", "Err"}
+
+// ParseVerbosity returns the verbosity for the given (case-insensitive)
+// name, i.e. v2.EventVerbosityInfo or v2.EventVerbosityWarning. It returns
+// an error when the verbosity is not supported.
+func ParseVerbosity(name string) (string, error) {
+	switch v := strings.ToLower(name); v {
+	case v2.EventVerbosityInfo, v2.EventVerbosityWarning:
+		return v, nil
+	default:
+		return "", fmt.Errorf("invalid event verbosity '%s': must be one of '%s' or '%s'",
+			name, v2.EventVerbosityInfo, v2.EventVerbosityWarning)
+	}
+}
+
+// Recorder is a kuberecorder.EventRecorder which records events with the
+// severity matching their reason, and suppresses info-level events based on
+// the configured verbosity, or the v2.EventVerbosityAnnotation of the
+// object the event is about. When configured WithDedupWindow, identical
+// events are recorded at most once per window.
+type Recorder struct {
+	kuberecorder.EventRecorder
+
+	verbosity string
+	dedup     *deduplicator
+}
+
+// RecorderOption configures a Recorder.
+type RecorderOption func(*Recorder)
+
+// WithDedupWindow configures the Recorder to suppress an event for an object
+// which is identical (in type, reason and message) to the last event with
+// the same reason recorded for the object within the given window. This
+// prevents persistent failures from producing an event on every
+// reconciliation, while a change of message is recorded immediately. A
+// window of zero or less disables the deduplication.
+func WithDedupWindow(window time.Duration) RecorderOption {
+	return func(r *Recorder) {
+		if window > 0 {
+			r.dedup = newDeduplicator(window)
+		}
+	}
+}
+
+// NewRecorder returns a new Recorder for the given recorder and default
+// verbosity, configured with the given options.
+func NewRecorder(recorder kuberecorder.EventRecorder, verbosity string, opts ...RecorderOption) *Recorder {
+	r := &Recorder{EventRecorder: recorder, verbosity: verbosity}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Event records the given event, unless it is suppressed.
+func (r *Recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, "%s", message)
+}
+
+// Eventf records the given event, unless it is suppressed.
+func (r *Recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...any) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, messageFmt, args...)
+}
+
+// AnnotatedEventf records the given event with the given annotations, unless
+// it is suppressed.
+func (r *Recorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...any) {
+	eventtype = Severity(eventtype, reason)
+	if eventtype != corev1.EventTypeWarning && r.suppressInfo(object) {
+		return
+	}
+	if r.dedup != nil && !r.dedup.shouldRecord(object, eventtype, reason, fmt.Sprintf(messageFmt, args...)) {
+		return
+	}
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
+
+// suppressInfo returns true if info-level events must be suppressed for the
+// given object.
+func (r *Recorder) suppressInfo(object runtime.Object) bool {
+	verbosity := r.verbosity
+	if obj, err := apimeta.Accessor(object); err == nil {
+		if v, err := ParseVerbosity(obj.GetAnnotations()[v2.EventVerbosityAnnotation]); err == nil {
+			verbosity = v
+		}
+	}
+	return verbosity == v2.EventVerbosityWarning
+}
+
+// Severity returns the event type to record an event with the given type
+// and reason with. Normal and trace events of which the reason signals a
+// failure, e.g. "InstallFailed" or "HelmChartSyncErr", are recorded as
+// Warning events.
+func Severity(eventtype, reason string) string {
+	if eventtype != corev1.EventTypeNormal && eventtype != eventv1.EventTypeTrace {
+		return eventtype
+	}
+	for _, suffix := range failureReasonSuffixes {
+		if strings.HasSuffix(reason, suffix) {
+			return corev1.EventTypeWarning
+		}
+	}
+	return eventtype
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+func TestParseVerbosity(t *testing.T) {
+	g := NewWithT(t)
+
+	got, err := ParseVerbosity("Warning")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(v2.EventVerbosityWarning))
+
+	_, err = ParseVerbosity("debug")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		eventtype string
+		reason    string
+		want      string
+	}{
+		{eventtype: corev1.EventTypeNormal, reason: v2.InstallSucceededReason, want: corev1.EventTypeNormal},
+		{eventtype: corev1.EventTypeNormal, reason: v2.InstallFailedReason, want: corev1.EventTypeWarning},
+		{eventtype: eventv1.EventTypeTrace, reason: "HelmChartSyncErr", want: corev1.EventTypeWarning},
+		{eventtype: eventv1.EventTypeTrace, reason: "HelmChartCreated", want: eventv1.EventTypeTrace},
+		{eventtype: corev1.EventTypeWarning, reason: "DriftDetected", want: corev1.EventTypeWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.eventtype+"/"+tt.reason, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Severity(tt.eventtype, tt.reason)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	newObject := func(verbosity string) *v2.HelmRelease {
+		obj := &v2.HelmRelease{ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"}}
+		if verbosity != "" {
+			obj.Annotations = map[string]string{v2.EventVerbosityAnnotation: verbosity}
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name      string
+		verbosity string
+		obj       *v2.HelmRelease
+		want      []string
+	}{
+		{
+			name:      "records all events",
+			verbosity: v2.EventVerbosityInfo,
+			obj:       newObject(""),
+			want:      []string{v2.InstallSucceededReason, v2.InstallFailedReason, "DriftDetected"},
+		},
+		{
+			name:      "suppresses info events",
+			verbosity: v2.EventVerbosityWarning,
+			obj:       newObject(""),
+			want:      []string{v2.InstallFailedReason, "DriftDetected"},
+		},
+		{
+			name:      "suppresses info events of annotated object",
+			verbosity: v2.EventVerbosityInfo,
+			obj:       newObject(v2.EventVerbosityWarning),
+			want:      []string{v2.InstallFailedReason, "DriftDetected"},
+		},
+		{
+			name:      "records all events of annotated object",
+			verbosity: v2.EventVerbosityWarning,
+			obj:       newObject(v2.EventVerbosityInfo),
+			want:      []string{v2.InstallSucceededReason, v2.InstallFailedReason, "DriftDetected"},
+		},
+		{
+			name:      "ignores invalid annotation",
+			verbosity: v2.EventVerbosityWarning,
+			obj:       newObject("invalid"),
+			want:      []string{v2.InstallFailedReason, "DriftDetected"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fake := testutil.NewFakeRecorder(10, false)
+			r := NewRecorder(fake, tt.verbosity)
+			r.Event(tt.obj, corev1.EventTypeNormal, v2.InstallSucceededReason, "succeeded")
+			r.Eventf(tt.obj, corev1.EventTypeNormal, v2.InstallFailedReason, "failed: %s", "error")
+			r.AnnotatedEventf(tt.obj, nil, corev1.EventTypeWarning, "DriftDetected", "drifted")
+
+			var got []string
+			for _, e := range fake.GetEvents() {
+				got = append(got, e.Reason)
+				if e.Reason == v2.InstallFailedReason {
+					g.Expect(e.Type).To(Equal(corev1.EventTypeWarning))
+					g.Expect(e.Message).To(Equal("failed: error"))
+				}
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -18,7 +18,19 @@ limitations under the License.
 // helm-controller supports, and their default states.
 package features
 
-import feathelper "github.com/fluxcd/pkg/runtime/features"
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	feathelper "github.com/fluxcd/pkg/runtime/features"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
 
 const (
 	// CacheSecretsAndConfigMaps configures the caching of Secrets and ConfigMaps
@@ -58,6 +70,19 @@ const (
 	// without the need to upgrade the Helm release. But it can be disabled to
 	// avoid potential abuse of the adoption mechanism.
 	AdoptLegacyReleases = "AdoptLegacyReleases"
+
+	// HideSecrets masks the data of Secrets wherever the rendered content of
+	// a release is surfaced by the controller, for example in the changes
+	// logged on drift detection. This is enabled by default, and can be
+	// disabled to debug the content of Secrets.
+	HideSecrets = "HideSecrets"
+
+	// SharedHelmCharts configures the controller to generate a single
+	// HelmChart for the HelmReleases with an identical chart template in the
+	// same namespace, instead of a HelmChart per HelmRelease. This reduces
+	// the number of charts the source-controller has to build and store when
+	// the same chart is released many times.
+	SharedHelmCharts = "SharedHelmCharts"
 )
 
 var features = map[string]bool{
@@ -79,14 +104,53 @@ var features = map[string]bool{
 	// AdoptLegacyReleases
 	// opt-out from v0.37
 	AdoptLegacyReleases: true,
+	// HideSecrets
+	// opt-out from v1.3
+	HideSecrets: true,
+	// SharedHelmCharts
+	// opt-in from v1.4
+	SharedHelmCharts: false,
+}
+
+// objectFeatures is the set of feature gates which apply to the
+// reconciliation of an individual object, and can therefore be overridden
+// using the v2.FeatureGatesAnnotation on the object.
+var objectFeatures = map[string]bool{
+	AllowDNSLookups:     true,
+	AdoptLegacyReleases: true,
+	HideSecrets:         true,
+	SharedHelmCharts:    true,
 }
 
+// overridable is the set of feature gates the operator allows to be
+// overridden on an object, as configured using SetOverridable.
+var overridable = map[string]bool{}
+
+// configured holds the state of the feature gates which apply to individual
+// objects as configured at runtime using SetConfigured, e.g. from a
+// v2.ControllerConfig.
+var (
+	configured   = map[string]bool{}
+	configuredMu sync.RWMutex
+)
+
 // FeatureGates contains a list of all supported feature gates and
 // their default values.
 func FeatureGates() map[string]bool {
 	return features
 }
 
+// ObjectFeatureGates returns the sorted names of the feature gates which
+// apply to the reconciliation of an individual object.
+func ObjectFeatureGates() []string {
+	gates := make([]string, 0, len(objectFeatures))
+	for gate := range objectFeatures {
+		gates = append(gates, gate)
+	}
+	slices.Sort(gates)
+	return gates
+}
+
 // Enabled verifies whether the feature is enabled or not.
 //
 // This is only a wrapper around the Enabled func in
@@ -103,3 +167,94 @@ func Disable(feature string) {
 		features[feature] = false
 	}
 }
+
+// SetOverridable configures the feature gates which can be overridden on an
+// object using the v2.FeatureGatesAnnotation. It returns an error if a
+// feature gate is unknown, or does not apply to individual objects.
+func SetOverridable(gates []string) error {
+	allowed := make(map[string]bool, len(gates))
+	for _, gate := range gates {
+		if _, ok := features[gate]; !ok {
+			return fmt.Errorf("unknown feature gate '%s'", gate)
+		}
+		if !objectFeatures[gate] {
+			return fmt.Errorf("feature gate '%s' can not be overridden on an object", gate)
+		}
+		allowed[gate] = true
+	}
+	overridable = allowed
+	return nil
+}
+
+// SetConfigured configures the state of the feature gates which apply to
+// individual objects at runtime, replacing any previously configured state.
+// It returns an error if a feature gate is unknown, or does not apply to
+// individual objects, in which case the configured state is left untouched.
+func SetConfigured(gates map[string]bool) error {
+	states := make(map[string]bool, len(gates))
+	for gate, enabled := range gates {
+		if _, ok := features[gate]; !ok {
+			return fmt.Errorf("unknown feature gate '%s'", gate)
+		}
+		if !objectFeatures[gate] {
+			return fmt.Errorf("feature gate '%s' can not be configured at runtime", gate)
+		}
+		states[gate] = enabled
+	}
+
+	configuredMu.Lock()
+	defer configuredMu.Unlock()
+	configured = states
+	return nil
+}
+
+// EnabledFor verifies whether the feature is enabled for the given object.
+//
+// If the feature gate is allowed to be overridden (see SetOverridable) and
+// the object has a v2.FeatureGatesAnnotation configuring the feature gate,
+// the state from the annotation is returned. Otherwise, it returns the state
+// configured at runtime using SetConfigured, or the state of the feature gate
+// as configured for the controller.
+func EnabledFor(obj metav1.Object, feature string) (bool, error) {
+	if overridable[feature] && obj != nil {
+		enabled, ok, err := annotationOverride(obj.GetAnnotations(), feature)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return enabled, nil
+		}
+	}
+
+	configuredMu.RLock()
+	enabled, ok := configured[feature]
+	configuredMu.RUnlock()
+	if ok {
+		return enabled, nil
+	}
+	return Enabled(feature)
+}
+
+// annotationOverride returns the state of the feature gate as configured in
+// the v2.FeatureGatesAnnotation, in the format of the --feature-gates flag
+// (e.g. "AllowDNSLookups=true,HideSecrets=false"). The second return value
+// is false if the annotation does not configure the feature gate.
+func annotationOverride(annotations map[string]string, feature string) (bool, bool, error) {
+	value, ok := annotations[v2.FeatureGatesAnnotation]
+	if !ok {
+		return false, false, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		k, v, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || strings.TrimSpace(k) != feature {
+			continue
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, false, fmt.Errorf("invalid value '%s' for feature gate '%s' in annotation '%s': %w",
+				v, feature, v2.FeatureGatesAnnotation, err)
+		}
+		return enabled, true, nil
+	}
+	return false, false, nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	feathelper "github.com/fluxcd/pkg/runtime/features"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestSetOverridable(t *testing.T) {
+	g := NewWithT(t)
+	t.Cleanup(func() {
+		overridable = map[string]bool{}
+	})
+
+	g.Expect(SetOverridable([]string{AllowDNSLookups, HideSecrets})).To(Succeed())
+	g.Expect(overridable).To(Equal(map[string]bool{AllowDNSLookups: true, HideSecrets: true}))
+
+	g.Expect(SetOverridable([]string{"Unknown"})).To(MatchError(ContainSubstring("unknown feature gate")))
+	g.Expect(SetOverridable([]string{OOMWatch})).To(MatchError(ContainSubstring("can not be overridden")))
+}
+
+func TestEnabledFor(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect((&feathelper.FeatureGates{}).SupportedFeatures(FeatureGates())).To(Succeed())
+
+	t.Cleanup(func() {
+		overridable = map[string]bool{}
+	})
+	g.Expect(SetOverridable([]string{AllowDNSLookups})).To(Succeed())
+
+	tests := []struct {
+		name       string
+		annotation string
+		feature    string
+		want       bool
+		wantErr    bool
+	}{
+		{
+			name:    "without annotation",
+			feature: AllowDNSLookups,
+			want:    false,
+		},
+		{
+			name:       "with override",
+			annotation: "AllowDNSLookups=true",
+			feature:    AllowDNSLookups,
+			want:       true,
+		},
+		{
+			name:       "with override of other feature gates",
+			annotation: "HideSecrets=false, AllowDNSLookups=true",
+			feature:    AllowDNSLookups,
+			want:       true,
+		},
+		{
+			name:       "ignores override of feature gate which is not overridable",
+			annotation: "HideSecrets=false",
+			feature:    HideSecrets,
+			want:       true,
+		},
+		{
+			name:       "with invalid override",
+			annotation: "AllowDNSLookups=yes",
+			feature:    AllowDNSLookups,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &v2.HelmRelease{}
+			if tt.annotation != "" {
+				obj.ObjectMeta = metav1.ObjectMeta{
+					Annotations: map[string]string{v2.FeatureGatesAnnotation: tt.annotation},
+				}
+			}
+
+			got, err := EnabledFor(obj, tt.feature)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestSetConfigured(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect((&feathelper.FeatureGates{}).SupportedFeatures(FeatureGates())).To(Succeed())
+
+	t.Cleanup(func() {
+		overridable = map[string]bool{}
+		_ = SetConfigured(nil)
+	})
+	g.Expect(SetOverridable([]string{AllowDNSLookups})).To(Succeed())
+
+	g.Expect(SetConfigured(map[string]bool{AllowDNSLookups: true, HideSecrets: false})).To(Succeed())
+	g.Expect(EnabledFor(&v2.HelmRelease{}, AllowDNSLookups)).To(BeTrue())
+	g.Expect(EnabledFor(&v2.HelmRelease{}, HideSecrets)).To(BeFalse())
+
+	// The annotation on the object takes precedence.
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{v2.FeatureGatesAnnotation: "AllowDNSLookups=false"},
+		},
+	}
+	g.Expect(EnabledFor(obj, AllowDNSLookups)).To(BeFalse())
+
+	// Invalid configurations leave the configured state untouched.
+	g.Expect(SetConfigured(map[string]bool{"Unknown": true})).To(MatchError(ContainSubstring("unknown feature gate")))
+	g.Expect(SetConfigured(map[string]bool{OOMWatch: true})).To(MatchError(ContainSubstring("can not be configured")))
+	g.Expect(EnabledFor(&v2.HelmRelease{}, AllowDNSLookups)).To(BeTrue())
+
+	// Resetting falls back to the state configured for the controller.
+	g.Expect(SetConfigured(nil)).To(Succeed())
+	g.Expect(EnabledFor(&v2.HelmRelease{}, AllowDNSLookups)).To(BeFalse())
+	g.Expect(EnabledFor(&v2.HelmRelease{}, HideSecrets)).To(BeTrue())
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inflight keeps track of the reconciliations in progress, and the
+// phase they are in, to help diagnose reconciliations which appear stuck
+// (e.g. in a Helm wait).
+package inflight
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HandlerPath is the path the Handler of a Tracker is conventionally
+// served at.
+const HandlerPath = "/debug/reconcilers"
+
+// Reconcile describes a reconciliation in progress.
+type Reconcile struct {
+	// Release is the namespace/name of the object being reconciled.
+	Release string `json:"release"`
+	// Phase is the phase the reconciliation is in.
+	Phase string `json:"phase"`
+	// Started is the time the reconciliation started.
+	Started time.Time `json:"started"`
+	// Elapsed is the time elapsed since the reconciliation started.
+	Elapsed string `json:"elapsed"`
+}
+
+// entry is the state of a tracked reconciliation.
+type entry struct {
+	mu      sync.Mutex
+	release string
+	phase   string
+	started time.Time
+}
+
+// Tracker keeps track of the reconciliations in progress. A nil Tracker is
+// valid, and does not track anything.
+type Tracker struct {
+	mu      sync.Mutex
+	entries map[*entry]struct{}
+
+	// now returns the current time, and can be overwritten in tests.
+	now func() time.Time
+}
+
+// NewTracker returns a new Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		entries: make(map[*entry]struct{}),
+		now:     time.Now,
+	}
+}
+
+type contextKey struct{}
+
+// Start starts tracking a reconciliation of the given release in the given
+// phase. It returns a context which can be passed to SetPhase to update the
+// phase of the reconciliation, and a function which must be called once the
+// reconciliation is done.
+func (t *Tracker) Start(ctx context.Context, release, phase string) (context.Context, func()) {
+	if t == nil {
+		return ctx, func() {}
+	}
+
+	e := &entry{release: release, phase: phase, started: t.now()}
+	t.mu.Lock()
+	t.entries[e] = struct{}{}
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, contextKey{}, e), func() {
+		t.mu.Lock()
+		delete(t.entries, e)
+		t.mu.Unlock()
+	}
+}
+
+// SetPhase updates the phase of the reconciliation tracked in the given
+// context. It is a no-op if the context does not belong to a tracked
+// reconciliation.
+func SetPhase(ctx context.Context, phase string) {
+	e, ok := ctx.Value(contextKey{}).(*entry)
+	if !ok {
+		return
+	}
+	e.mu.Lock()
+	e.phase = phase
+	e.mu.Unlock()
+}
+
+// List returns the reconciliations in progress, the longest running first.
+func (t *Tracker) List() []Reconcile {
+	if t == nil {
+		return []Reconcile{}
+	}
+
+	now := t.now()
+	t.mu.Lock()
+	list := make([]Reconcile, 0, len(t.entries))
+	for e := range t.entries {
+		e.mu.Lock()
+		list = append(list, Reconcile{
+			Release: e.release,
+			Phase:   e.phase,
+			Started: e.started,
+			Elapsed: now.Sub(e.started).Round(time.Millisecond).String(),
+		})
+		e.mu.Unlock()
+	}
+	t.mu.Unlock()
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if !list[i].Started.Equal(list[j].Started) {
+			return list[i].Started.Before(list[j].Started)
+		}
+		return list[i].Release < list[j].Release
+	})
+	return list
+}
+
+// Handler returns an http.Handler which writes the reconciliations in
+// progress as a JSON list.
+func (t *Tracker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(t.List())
+	})
+}
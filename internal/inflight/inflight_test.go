@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inflight
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTracker(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	tr := NewTracker()
+	tr.now = func() time.Time { return now }
+
+	ctx1, done1 := tr.Start(context.TODO(), "default/podinfo", "queued")
+	now = now.Add(time.Minute)
+	_, done2 := tr.Start(context.TODO(), "default/redis", "queued")
+	now = now.Add(time.Minute)
+
+	SetPhase(ctx1, "upgrade")
+	g.Expect(tr.List()).To(Equal([]Reconcile{
+		{Release: "default/podinfo", Phase: "upgrade", Started: now.Add(-2 * time.Minute), Elapsed: "2m0s"},
+		{Release: "default/redis", Phase: "queued", Started: now.Add(-time.Minute), Elapsed: "1m0s"},
+	}))
+
+	done1()
+	list := tr.List()
+	g.Expect(list).To(HaveLen(1))
+	g.Expect(list[0].Release).To(Equal("default/redis"))
+
+	done2()
+	g.Expect(tr.List()).To(BeEmpty())
+
+	// Setting the phase of an untracked context is a no-op.
+	SetPhase(context.TODO(), "upgrade")
+}
+
+func TestTracker_Nil(t *testing.T) {
+	g := NewWithT(t)
+
+	var tr *Tracker
+	ctx, done := tr.Start(context.TODO(), "default/podinfo", "queued")
+	SetPhase(ctx, "upgrade")
+	done()
+	g.Expect(tr.List()).To(BeEmpty())
+}
+
+func TestTracker_Handler(t *testing.T) {
+	g := NewWithT(t)
+
+	tr := NewTracker()
+	_, done := tr.Start(context.TODO(), "default/podinfo", "install")
+	defer done()
+
+	rec := httptest.NewRecorder()
+	tr.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, HandlerPath, nil))
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+
+	var got []Reconcile
+	g.Expect(json.Unmarshal(rec.Body.Bytes(), &got)).To(Succeed())
+	g.Expect(got).To(HaveLen(1))
+	g.Expect(got[0].Release).To(Equal("default/podinfo"))
+	g.Expect(got[0].Phase).To(Equal("install"))
+
+	rec = httptest.NewRecorder()
+	tr.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, HandlerPath, nil))
+	g.Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+
+	"k8s.io/client-go/rest"
+)
+
+// ClientCache shares the REST mapper, discovery client and client config
+// of persistent MemoryRESTClientGetters between owners, e.g. HelmReleases,
+// which target the same cluster with the same credentials, impersonation
+// identity, namespace and client options, instead of constructing them from
+// scratch for every reconciliation.
+//
+// The getters returned by the cache are specific to the call, and only
+// share the clients of the cache entry. This allows the REST config of a
+// getter, including its warning handler, to remain specific to the
+// reconciliation it was requested for.
+type ClientCache struct {
+	mu      sync.Mutex
+	entries map[string]*clientCacheEntry
+	owners  map[string]string
+}
+
+type clientCacheEntry struct {
+	getter *MemoryRESTClientGetter
+	owners map[string]struct{}
+}
+
+// NewClientCache returns a new, empty ClientCache.
+func NewClientCache() *ClientCache {
+	return &ClientCache{
+		entries: make(map[string]*clientCacheEntry),
+		owners:  make(map[string]string),
+	}
+}
+
+// Get returns a new MemoryRESTClientGetter for the given REST config and
+// options. If the getter is persistent, it shares its clients with the
+// getters of other owners with the same configuration, and the given owner
+// is registered as a user of them until it is deleted, or requests a getter
+// with a different configuration.
+//
+// A nil ClientCache always returns a getter which does not share clients.
+func (c *ClientCache) Get(owner string, cfg *rest.Config, opts ...Option) *MemoryRESTClientGetter {
+	g := NewMemoryRESTClientGetter(cfg, opts...)
+	if c == nil {
+		return g
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !g.persistent || g.cfg == nil {
+		c.release(owner)
+		return g
+	}
+
+	key := clientCacheKey(g)
+	if c.owners[owner] != key {
+		c.release(owner)
+	}
+	e, ok := c.entries[key]
+	if !ok {
+		e = &clientCacheEntry{getter: g.sharedCopy(), owners: make(map[string]struct{})}
+		c.entries[key] = e
+	}
+	e.owners[owner] = struct{}{}
+	c.owners[owner] = key
+	g.shared = e.getter
+	return g
+}
+
+// Delete releases the clients used by the given owner. Clients which are
+// no longer used by any owner are removed from the cache.
+func (c *ClientCache) Delete(owner string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.release(owner)
+}
+
+// release removes the given owner from the entry it uses, and removes the
+// entry if it is no longer used by any owner. It must be called with the
+// lock held.
+func (c *ClientCache) release(owner string) {
+	key, ok := c.owners[owner]
+	if !ok {
+		return
+	}
+	delete(c.owners, owner)
+	if e, ok := c.entries[key]; ok {
+		delete(e.owners, owner)
+		if len(e.owners) == 0 {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// clientCacheKey returns a digest of the configuration of the given getter
+// which determines the clients it returns.
+func clientCacheKey(g *MemoryRESTClientGetter) string {
+	h := sha256.New()
+	if cfg := g.cfg; cfg != nil {
+		writeConfigIdentity(h, cfg)
+		fmt.Fprintln(h, cfg.QPS, cfg.Burst)
+	}
+	fmt.Fprintln(h, g.namespace, g.impersonate, g.discoveryCache != nil)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeConfigIdentity writes the target cluster and the credentials of the
+// given REST config, including the impersonated identity, to w.
+func writeConfigIdentity(w io.Writer, cfg *rest.Config) {
+	fmt.Fprintln(w, cfg.Host, cfg.APIPath)
+	fmt.Fprintln(w, cfg.Username, cfg.Password, cfg.BearerToken, cfg.BearerTokenFile)
+	fmt.Fprintln(w, cfg.Impersonate.UserName, cfg.Impersonate.UID, cfg.Impersonate.Groups, cfg.Impersonate.Extra)
+	fmt.Fprintln(w, cfg.TLSClientConfig.Insecure, cfg.TLSClientConfig.ServerName,
+		cfg.TLSClientConfig.CertFile, cfg.TLSClientConfig.KeyFile, cfg.TLSClientConfig.CAFile)
+	_, _ = w.Write(cfg.TLSClientConfig.CertData)
+	_, _ = w.Write(cfg.TLSClientConfig.KeyData)
+	_, _ = w.Write(cfg.TLSClientConfig.CAData)
+	if cfg.AuthProvider != nil {
+		fmt.Fprintln(w, cfg.AuthProvider.Name, cfg.AuthProvider.Config)
+	}
+	if cfg.ExecProvider != nil {
+		fmt.Fprintln(w, cfg.ExecProvider.Command, cfg.ExecProvider.Args, cfg.ExecProvider.Env)
+	}
+}
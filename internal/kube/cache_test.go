@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+func TestClientCache_Get(t *testing.T) {
+	newConfig := func() *rest.Config {
+		return &rest.Config{Host: "https://example.com", BearerToken: "token"}
+	}
+
+	t.Run("shares clients between owners with same configuration", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache := NewClientCache()
+		a := cache.Get("default/app", newConfig(), WithNamespace("app"), WithPersistent(true))
+		b := cache.Get("default/other", newConfig(), WithNamespace("app"), WithPersistent(true))
+		g.Expect(b).ToNot(BeIdenticalTo(a))
+		g.Expect(b.shared).ToNot(BeNil())
+		g.Expect(b.shared).To(BeIdenticalTo(a.shared))
+		g.Expect(b.ToRawKubeConfigLoader()).To(BeIdenticalTo(a.ToRawKubeConfigLoader()))
+		g.Expect(cache.entries).To(HaveLen(1))
+
+		c := cache.Get("default/app", newConfig(), WithNamespace("app"), WithPersistent(true))
+		g.Expect(c.shared).To(BeIdenticalTo(a.shared))
+		g.Expect(cache.entries).To(HaveLen(1))
+	})
+
+	t.Run("keeps warning handler specific to getter", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache := NewClientCache()
+		first := NewWarningRecorder()
+		a := cache.Get("default/app", newConfig(), WithPersistent(true), WithWarningHandler(first))
+		second := NewWarningRecorder()
+		b := cache.Get("default/other", newConfig(), WithPersistent(true), WithWarningHandler(second))
+		g.Expect(b.shared).To(BeIdenticalTo(a.shared))
+
+		cfg, err := b.ToRESTConfig()
+		g.Expect(err).ToNot(HaveOccurred())
+		cfg.WarningHandler.HandleWarningHeader(299, "", "v1beta1 Foo is deprecated")
+		g.Expect(first.Warnings()).To(BeEmpty())
+		g.Expect(second.Warnings()).To(ConsistOf("v1beta1 Foo is deprecated"))
+		g.Expect(b.shared.cfg.WarningHandler).To(BeNil())
+	})
+
+	t.Run("separates clients on configuration change", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache := NewClientCache()
+		a := cache.Get("default/app", newConfig(), WithNamespace("app"), WithPersistent(true))
+
+		b := cache.Get("default/app", newConfig(), WithNamespace("other"), WithPersistent(true))
+		g.Expect(b.shared).ToNot(BeIdenticalTo(a.shared))
+
+		cfg := newConfig()
+		cfg.BearerToken = "rotated"
+		c := cache.Get("default/app", cfg, WithNamespace("other"), WithPersistent(true))
+		g.Expect(c.shared).ToNot(BeIdenticalTo(b.shared))
+
+		d := cache.Get("default/app", newConfig(), WithNamespace("other"), WithImpersonate("sa", "default"), WithPersistent(true))
+		g.Expect(d.shared).ToNot(BeIdenticalTo(c.shared))
+
+		// The entries which are no longer used by the owner are released.
+		g.Expect(cache.entries).To(HaveLen(1))
+	})
+
+	t.Run("does not cache non-persistent getter", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache := NewClientCache()
+		cache.Get("default/app", newConfig(), WithPersistent(true))
+		a := cache.Get("default/app", newConfig(), WithPersistent(false))
+		g.Expect(a.shared).To(BeNil())
+		g.Expect(cache.entries).To(BeEmpty())
+		g.Expect(cache.owners).To(BeEmpty())
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var cache *ClientCache
+		a := cache.Get("default/app", newConfig(), WithPersistent(true))
+		g.Expect(a.shared).To(BeNil())
+		cache.Delete("default/app")
+	})
+}
+
+func TestClientCache_Delete(t *testing.T) {
+	g := NewWithT(t)
+
+	cache := NewClientCache()
+	a := cache.Get("default/app", &rest.Config{Host: "https://example.com"}, WithPersistent(true))
+	b := cache.Get("default/other", &rest.Config{Host: "https://example.com"}, WithPersistent(true))
+
+	cache.Delete("default/app")
+	g.Expect(cache.entries).To(HaveLen(1))
+
+	cache.Delete("default/other")
+	g.Expect(cache.entries).To(BeEmpty())
+	g.Expect(cache.owners).To(BeEmpty())
+
+	c := cache.Get("default/app", &rest.Config{Host: "https://example.com"}, WithPersistent(true))
+	g.Expect(c.shared).ToNot(BeIdenticalTo(a.shared))
+	g.Expect(c.shared).ToNot(BeIdenticalTo(b.shared))
+}
@@ -28,6 +28,7 @@ import (
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/fluxcd/pkg/runtime/client"
 )
@@ -70,6 +71,15 @@ func WithPersistent(persist bool) Option {
 	}
 }
 
+// WithDiscoveryCache sets the DiscoveryCache to share the discovery client
+// and REST mapper of the target cluster with other clients. A nil cache is
+// ignored.
+func WithDiscoveryCache(cache *DiscoveryCache) Option {
+	return func(c *MemoryRESTClientGetter) {
+		c.discoveryCache = cache
+	}
+}
+
 // MemoryRESTClientGetter is a resource.RESTClientGetter that uses an
 // in-memory REST config, REST mapper, and discovery client.
 // If configured, the client config, REST mapper, and discovery client are
@@ -83,8 +93,19 @@ type MemoryRESTClientGetter struct {
 	// clientCfg, and discoveryClient. Rather than re-initializing them on
 	// every call, they will be cached and reused.
 	persistent bool
-
-	cfg *rest.Config
+	// discoveryCache is the cache of the discovery client and REST mapper
+	// shared with other clients targeting the same cluster. When set, it
+	// takes precedence over persistent.
+	discoveryCache *DiscoveryCache
+
+	cfg      *rest.Config
+	wrapOnce sync.Once
+	// shared is the getter of a ClientCache of which the discovery client,
+	// REST mapper and client config are used instead of persisting them in
+	// this getter. It allows them to be shared with other getters for the
+	// same target cluster and identity, while the REST config (including
+	// its warning handler) remains specific to this getter.
+	shared *MemoryRESTClientGetter
 
 	restMapper   meta.RESTMapper
 	restMapperMu sync.Mutex
@@ -94,6 +115,11 @@ type MemoryRESTClientGetter struct {
 
 	clientCfg   clientcmd.ClientConfig
 	clientCfgMu sync.Mutex
+
+	// client is created on the first call to ToClient. It is not shared
+	// with other getters, as it uses the REST config of this getter.
+	client   ctrlclient.Client
+	clientMu sync.Mutex
 }
 
 // setDefaults sets the default values for the MemoryRESTClientGetter.
@@ -115,6 +141,22 @@ func NewMemoryRESTClientGetter(cfg *rest.Config, opts ...Option) *MemoryRESTClie
 	return g
 }
 
+// sharedCopy returns a persistent copy of the getter to be shared by a
+// ClientCache. The warning handler of the REST config is not copied, as
+// the warnings of the shared clients can not be attributed to a single
+// getter.
+func (c *MemoryRESTClientGetter) sharedCopy() *MemoryRESTClientGetter {
+	cfg := rest.CopyConfig(c.cfg)
+	cfg.WarningHandler = nil
+	return &MemoryRESTClientGetter{
+		namespace:      c.namespace,
+		impersonate:    c.impersonate,
+		persistent:     true,
+		discoveryCache: c.discoveryCache,
+		cfg:            cfg,
+	}
+}
+
 // NewInClusterMemoryRESTClientGetter returns a new MemoryRESTClientGetter
 // that uses the in-cluster REST config. It returns an error if the in-cluster
 // REST config cannot be obtained.
@@ -132,8 +174,11 @@ func (c *MemoryRESTClientGetter) ToRESTConfig() (*rest.Config, error) {
 		return nil, fmt.Errorf("MemoryRESTClientGetter has no REST config")
 	}
 	// add retries to fix temporary "etcdserver: leader changed" errors from kube-apiserver
-	c.cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
-		return &retryingRoundTripper{wrapped: rt}
+	// only once, as the getter may be reused across reconciliations
+	c.wrapOnce.Do(func() {
+		c.cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &retryingRoundTripper{wrapped: rt}
+		})
 	})
 	return c.cfg, nil
 }
@@ -141,6 +186,16 @@ func (c *MemoryRESTClientGetter) ToRESTConfig() (*rest.Config, error) {
 // ToDiscoveryClient returns a memory cached discovery client. Calling it
 // multiple times will return the same instance.
 func (c *MemoryRESTClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	if c.shared != nil {
+		return c.shared.ToDiscoveryClient()
+	}
+	if c.discoveryCache != nil {
+		e, err := c.toCachedDiscovery()
+		if err != nil {
+			return nil, err
+		}
+		return e.discoveryClient, nil
+	}
 	if c.persistent {
 		return c.toPersistentDiscoveryClient()
 	}
@@ -166,7 +221,29 @@ func (c *MemoryRESTClientGetter) toDiscoveryClient() (discovery.CachedDiscoveryI
 	if err != nil {
 		return nil, err
 	}
+	return newMemCacheClient(config)
+}
+
+// toCachedDiscovery returns the entry of the target cluster in the
+// DiscoveryCache.
+func (c *MemoryRESTClientGetter) toCachedDiscovery() (*discoveryCacheEntry, error) {
+	if c.cfg == nil {
+		return nil, fmt.Errorf("MemoryRESTClientGetter has no REST config")
+	}
+	return c.discoveryCache.get(c.cfg, func() (discovery.CachedDiscoveryInterface, error) {
+		config, err := c.ToRESTConfig()
+		if err != nil {
+			return nil, err
+		}
+		// The discovery client is shared with the getters of other owners,
+		// which must not receive each other's warnings.
+		config = rest.CopyConfig(config)
+		config.WarningHandler = nil
+		return newMemCacheClient(config)
+	})
+}
 
+func newMemCacheClient(config *rest.Config) (discovery.CachedDiscoveryInterface, error) {
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
 	if err != nil {
 		return nil, err
@@ -177,6 +254,16 @@ func (c *MemoryRESTClientGetter) toDiscoveryClient() (discovery.CachedDiscoveryI
 // ToRESTMapper returns a meta.RESTMapper using the discovery client. Calling
 // it multiple times will return the same instance.
 func (c *MemoryRESTClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	if c.shared != nil {
+		return c.shared.ToRESTMapper()
+	}
+	if c.discoveryCache != nil {
+		e, err := c.toCachedDiscovery()
+		if err != nil {
+			return nil, err
+		}
+		return e.restMapper, nil
+	}
 	if c.persistent {
 		return c.toPersistentRESTMapper()
 	}
@@ -206,10 +293,35 @@ func (c *MemoryRESTClientGetter) toRESTMapper() (meta.RESTMapper, error) {
 	return restmapper.NewShortcutExpander(mapper, discoveryClient, nil), nil
 }
 
+// ToClient returns a client.Client using the REST config and REST mapper of
+// the getter. Calling it multiple times will return the same instance.
+func (c *MemoryRESTClientGetter) ToClient() (ctrlclient.Client, error) {
+	c.clientMu.Lock()
+	defer c.clientMu.Unlock()
+
+	if c.client == nil {
+		cfg, err := c.ToRESTConfig()
+		if err != nil {
+			return nil, err
+		}
+		mapper, err := c.ToRESTMapper()
+		if err != nil {
+			return nil, err
+		}
+		if c.client, err = NewClientForConfig(cfg, mapper); err != nil {
+			return nil, err
+		}
+	}
+	return c.client, nil
+}
+
 // ToRawKubeConfigLoader returns a clientcmd.ClientConfig using
 // clientcmd.DefaultClientConfig. With clientcmd.ClusterDefaults, namespace, and
 // impersonate configured as overwrites.
 func (c *MemoryRESTClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	if c.shared != nil {
+		return c.shared.ToRawKubeConfigLoader()
+	}
 	if c.persistent {
 		return c.toPersistentRawKubeConfigLoader()
 	}
@@ -192,6 +192,20 @@ func TestMemoryRESTClientGetter_ToRESTConfig(t *testing.T) {
 		g.Expect(err).To(HaveOccurred())
 		g.Expect(cfg).To(BeNil())
 	})
+
+	t.Run("wraps transport with retries once", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := NewMemoryRESTClientGetter(&rest.Config{Host: "https://example.com"})
+		cfg, err := c.ToRESTConfig()
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = c.ToRESTConfig()
+		g.Expect(err).ToNot(HaveOccurred())
+
+		rt := cfg.WrapTransport(nil)
+		g.Expect(rt).To(BeAssignableToTypeOf(&retryingRoundTripper{}))
+		g.Expect(rt.(*retryingRoundTripper).wrapped).To(BeNil())
+	})
 }
 
 func TestMemoryRESTClientGetter_ToDiscoveryClient(t *testing.T) {
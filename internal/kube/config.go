@@ -71,3 +71,18 @@ func ConfigFromSecret(secret *corev1.Secret, key string, opts client.KubeConfigO
 	cfg = client.KubeConfig(cfg, opts)
 	return cfg, nil
 }
+
+// RequireTLS returns an error if the given config does not result in a
+// connection to the API server which is encrypted with TLS, and of which the
+// certificate is verified. This ensures the values and manifests of a release
+// are never sent to a (remote) cluster in plain text, or to a server which
+// can not be trusted.
+func RequireTLS(cfg *rest.Config) error {
+	if !rest.IsConfigTransportTLS(*cfg) {
+		return fmt.Errorf("KubeConfig for server '%s' does not use TLS", cfg.Host)
+	}
+	if cfg.TLSClientConfig.Insecure {
+		return fmt.Errorf("KubeConfig for server '%s' disables TLS certificate verification", cfg.Host)
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 )
 
 const (
@@ -204,3 +205,45 @@ func TestConfigFromSecret(t *testing.T) {
 		g.Expect(got.UserAgent).To(Equal("test"))
 	})
 }
+
+func TestRequireTLS(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *rest.Config
+		wantErr string
+	}{
+		{
+			name: "TLS with verification",
+			cfg: &rest.Config{
+				Host: "https://1.2.3.4",
+			},
+		},
+		{
+			name: "plain HTTP",
+			cfg: &rest.Config{
+				Host: "http://1.2.3.4",
+			},
+			wantErr: "does not use TLS",
+		},
+		{
+			name: "TLS without verification",
+			cfg: &rest.Config{
+				Host:            "https://1.2.3.4",
+				TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+			},
+			wantErr: "disables TLS certificate verification",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := RequireTLS(tt.cfg)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// minRefreshInterval is the minimum interval between two invalidations of
+// the discovery cache of a cluster caused by a resource which can not be
+// mapped.
+const minRefreshInterval = 30 * time.Second
+
+// DiscoveryCache holds a memory cached discovery client and REST mapper per
+// target cluster, which are shared by the MemoryRESTClientGetters configured
+// with it using WithDiscoveryCache. This prevents the API resources of a
+// cluster from being discovered on every reconciliation of every release.
+//
+// The cache of a cluster is invalidated when:
+//   - Its REST mapper is reset, e.g. after the CRDs of a chart were applied.
+//   - A resource can not be mapped, e.g. of a CRD which was created by
+//     others, at most once per minRefreshInterval.
+//   - It is older than the TTL of the DiscoveryCache, in which case the
+//     discovery client is recreated using the REST config of the next getter.
+type DiscoveryCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*discoveryCacheEntry
+}
+
+type discoveryCacheEntry struct {
+	discoveryClient discovery.CachedDiscoveryInterface
+	restMapper      meta.RESTMapper
+	expiresAt       time.Time
+}
+
+// NewDiscoveryCache returns a new DiscoveryCache of which the entries expire
+// after the given TTL. It returns nil if the TTL is not positive, which is a
+// valid (disabled) cache.
+func NewDiscoveryCache(ttl time.Duration) *DiscoveryCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &DiscoveryCache{
+		ttl:     ttl,
+		entries: make(map[string]*discoveryCacheEntry),
+	}
+}
+
+// get returns the cache entry for the cluster of the given REST config. If
+// there is no entry, or it has expired, a new entry is created with the
+// discovery client returned by newClient.
+func (c *DiscoveryCache) get(cfg *rest.Config, newClient func() (discovery.CachedDiscoveryInterface, error)) (*discoveryCacheEntry, error) {
+	key := discoveryCacheKey(cfg)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok && time.Now().Before(e.expiresAt) {
+		return e, nil
+	}
+
+	cl, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	refreshing := newRefreshingDiscoveryClient(cl)
+	e := &discoveryCacheEntry{
+		discoveryClient: refreshing,
+		restMapper: restmapper.NewShortcutExpander(
+			restmapper.NewDeferredDiscoveryRESTMapper(refreshing), refreshing, nil),
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	c.entries[key] = e
+	return e, nil
+}
+
+// discoveryCacheKey returns the key of the cluster of the given REST config
+// in the DiscoveryCache. The credentials and the impersonated identity are
+// part of the key, as the API resources which are discovered may depend on
+// the permissions of the user.
+func discoveryCacheKey(cfg *rest.Config) string {
+	h := sha256.New()
+	writeConfigIdentity(h, cfg)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// refreshingDiscoveryClient is a discovery.CachedDiscoveryInterface which
+// reports its cache as stale once it was last invalidated longer than
+// minRefreshInterval ago. This causes a restmapper.DeferredDiscoveryRESTMapper
+// to invalidate the cache and retry when it fails to map a resource, while
+// limiting the number of invalidations caused by resources which can not be
+// mapped at all.
+type refreshingDiscoveryClient struct {
+	discovery.CachedDiscoveryInterface
+
+	mu            sync.Mutex
+	invalidatedAt time.Time
+}
+
+func newRefreshingDiscoveryClient(cl discovery.CachedDiscoveryInterface) *refreshingDiscoveryClient {
+	return &refreshingDiscoveryClient{CachedDiscoveryInterface: cl, invalidatedAt: time.Now()}
+}
+
+// Fresh returns false if the cache was last invalidated longer than
+// minRefreshInterval ago, and otherwise the freshness of the underlying
+// cache.
+func (c *refreshingDiscoveryClient) Fresh() bool {
+	c.mu.Lock()
+	stale := time.Since(c.invalidatedAt) >= minRefreshInterval
+	c.mu.Unlock()
+	return !stale && c.CachedDiscoveryInterface.Fresh()
+}
+
+// Invalidate invalidates the underlying cache.
+func (c *refreshingDiscoveryClient) Invalidate() {
+	c.mu.Lock()
+	c.invalidatedAt = time.Now()
+	c.mu.Unlock()
+	c.CachedDiscoveryInterface.Invalidate()
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+func TestNewDiscoveryCache(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(NewDiscoveryCache(0)).To(BeNil())
+	g.Expect(NewDiscoveryCache(time.Minute)).ToNot(BeNil())
+}
+
+func TestMemoryRESTClientGetter_WithDiscoveryCache(t *testing.T) {
+	t.Run("shares discovery per cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache := NewDiscoveryCache(time.Minute)
+		newGetter := func(host, serviceAccount string) *MemoryRESTClientGetter {
+			return NewMemoryRESTClientGetter(&rest.Config{Host: host},
+				WithImpersonate(serviceAccount, "default"), WithDiscoveryCache(cache))
+		}
+
+		a, err := newGetter("https://a.example.com", "").toCachedDiscovery()
+		g.Expect(err).ToNot(HaveOccurred())
+		b, err := newGetter("https://a.example.com", "").toCachedDiscovery()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(b).To(BeIdenticalTo(a))
+
+		mapper, err := newGetter("https://a.example.com", "").ToRESTMapper()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(mapper).To(Equal(a.restMapper))
+
+		c, err := newGetter("https://b.example.com", "").toCachedDiscovery()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c).ToNot(BeIdenticalTo(a))
+
+		d, err := newGetter("https://a.example.com", "tenant").toCachedDiscovery()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(d).ToNot(BeIdenticalTo(a))
+
+		g.Expect(cache.entries).To(HaveLen(3))
+	})
+
+	t.Run("does not share discovery between credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache := NewDiscoveryCache(time.Minute)
+		newGetter := func(cfg *rest.Config) *MemoryRESTClientGetter {
+			return NewMemoryRESTClientGetter(cfg, WithDiscoveryCache(cache))
+		}
+
+		a, err := newGetter(&rest.Config{Host: "https://example.com", BearerToken: "a"}).toCachedDiscovery()
+		g.Expect(err).ToNot(HaveOccurred())
+		b, err := newGetter(&rest.Config{Host: "https://example.com", BearerToken: "b"}).toCachedDiscovery()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(b).ToNot(BeIdenticalTo(a))
+
+		cfg := &rest.Config{Host: "https://example.com", BearerToken: "a"}
+		cfg.Impersonate.UserName = "tenant"
+		cfg.Impersonate.Groups = []string{"tenants"}
+		c, err := newGetter(cfg).toCachedDiscovery()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c).ToNot(BeIdenticalTo(a))
+
+		cfg = rest.CopyConfig(cfg)
+		cfg.Impersonate.Groups = []string{"admins"}
+		d, err := newGetter(cfg).toCachedDiscovery()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(d).ToNot(BeIdenticalTo(c))
+
+		g.Expect(cache.entries).To(HaveLen(4))
+	})
+
+	t.Run("recreates expired entries", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache := NewDiscoveryCache(time.Minute)
+		getter := NewMemoryRESTClientGetter(&rest.Config{Host: "https://example.com"}, WithDiscoveryCache(cache))
+
+		a, err := getter.ToDiscoveryClient()
+		g.Expect(err).ToNot(HaveOccurred())
+		for _, e := range cache.entries {
+			e.expiresAt = time.Now().Add(-time.Second)
+		}
+		b, err := getter.ToDiscoveryClient()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(b).ToNot(BeIdenticalTo(a))
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := NewMemoryRESTClientGetter(&rest.Config{Host: "https://example.com"}, WithDiscoveryCache(nil))
+		g.Expect(getter.discoveryCache).To(BeNil())
+		_, err := getter.ToRESTMapper()
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+}
+
+// staticDiscoveryClient is a discovery.CachedDiscoveryInterface which
+// records the invalidations of its cache.
+type staticDiscoveryClient struct {
+	discovery.CachedDiscoveryInterface
+
+	fresh         bool
+	invalidations int
+}
+
+func (c *staticDiscoveryClient) Fresh() bool {
+	return c.fresh
+}
+
+func (c *staticDiscoveryClient) Invalidate() {
+	c.invalidations++
+}
+
+func TestRefreshingDiscoveryClient(t *testing.T) {
+	g := NewWithT(t)
+
+	static := &staticDiscoveryClient{fresh: true}
+	cl := newRefreshingDiscoveryClient(static)
+	g.Expect(cl.Fresh()).To(BeTrue())
+
+	// The cache is reported stale once it was last invalidated longer than
+	// the minimum refresh interval ago.
+	cl.invalidatedAt = time.Now().Add(-minRefreshInterval)
+	g.Expect(cl.Fresh()).To(BeFalse())
+
+	cl.Invalidate()
+	g.Expect(static.invalidations).To(Equal(1))
+	g.Expect(cl.Fresh()).To(BeTrue())
+
+	// The freshness of the underlying cache is respected.
+	static.fresh = false
+	g.Expect(cl.Fresh()).To(BeFalse())
+}
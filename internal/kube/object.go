@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// ClientGetter is the subset of a RESTClientGetter required to construct a
+// client.Client for a cluster.
+type ClientGetter interface {
+	ToRESTConfig() (*rest.Config, error)
+	ToRESTMapper() (meta.RESTMapper, error)
+}
+
+// NewClient returns a client.Client for the cluster of the given
+// ClientGetter, which uses the (cached) REST mapper of the getter instead of
+// discovering the API resources of the cluster again. For a
+// MemoryRESTClientGetter, the client is created once and reused for
+// subsequent calls.
+func NewClient(getter ClientGetter) (client.Client, error) {
+	if g, ok := getter.(*MemoryRESTClientGetter); ok {
+		return g.ToClient()
+	}
+	cfg, err := getter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := getter.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	return NewClientForConfig(cfg, mapper)
+}
+
+// NewClientForConfig returns a client.Client for the given REST config,
+// which uses the given REST mapper. It allows a client to be created for a
+// derivative of the REST config of a getter, e.g. without impersonation,
+// while still sharing the REST mapper of the getter.
+func NewClientForConfig(cfg *rest.Config, mapper meta.RESTMapper) (client.Client, error) {
+	return client.New(cfg, client.Options{Mapper: mapper})
+}
+
+// SetDefaultNamespace sets the namespace of the given object to the given
+// namespace if it does not have one and is namespace scoped according to the
+// given REST mapper, as Helm does when it creates the resources of a
+// release. The returned error wraps the error of the REST mapper, e.g. a
+// meta.NoKindMatchError for a kind which is not known to the cluster.
+func SetDefaultNamespace(mapper meta.RESTMapper, obj *unstructured.Unstructured, namespace string) error {
+	if obj.GetNamespace() != "" {
+		return nil
+	}
+	gvk := obj.GroupVersionKind()
+	namespaced, err := apiutil.IsGVKNamespaced(gvk, mapper)
+	if err != nil {
+		return fmt.Errorf("failed to determine if %s is namespace scoped: %w", gvk.Kind, err)
+	}
+	if namespaced {
+		obj.SetNamespace(namespace)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSetDefaultNamespace(t *testing.T) {
+	configMap := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	namespace := schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+	unknown := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(configMap, meta.RESTScopeNamespace)
+	mapper.Add(namespace, meta.RESTScopeRoot)
+
+	tests := []struct {
+		name      string
+		gvk       schema.GroupVersionKind
+		namespace string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "sets namespace of namespace scoped object",
+			gvk:  configMap,
+			want: "release",
+		},
+		{
+			name:      "keeps explicit namespace",
+			gvk:       configMap,
+			namespace: "other",
+			want:      "other",
+		},
+		{
+			name: "does not set namespace of cluster scoped object",
+			gvk:  namespace,
+			want: "",
+		},
+		{
+			name:    "returns error for unknown kind",
+			gvk:     unknown,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(tt.gvk)
+			obj.SetNamespace(tt.namespace)
+
+			err := SetDefaultNamespace(mapper, obj, "release")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(meta.IsNoMatchError(err)).To(BeTrue())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(obj.GetNamespace()).To(Equal(tt.want))
+		})
+	}
+}
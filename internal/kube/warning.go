@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/rest"
+)
+
+// WarningRecorder is a rest.WarningHandler which records the unique
+// warnings returned by the Kubernetes API server, e.g. about the use of
+// deprecated APIs. It is safe for concurrent use.
+type WarningRecorder struct {
+	mu       sync.Mutex
+	warnings []string
+	seen     map[string]struct{}
+}
+
+// NewWarningRecorder returns a new WarningRecorder.
+func NewWarningRecorder() *WarningRecorder {
+	return &WarningRecorder{seen: map[string]struct{}{}}
+}
+
+// HandleWarningHeader records the warning text if the code is 299, as
+// documented for the rest.WarningHandler interface. Other warnings are
+// ignored.
+func (r *WarningRecorder) HandleWarningHeader(code int, _ string, text string) {
+	if code != 299 || len(text) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.seen[text]; ok {
+		return
+	}
+	r.seen[text] = struct{}{}
+	r.warnings = append(r.warnings, text)
+}
+
+// Warnings returns the recorded warnings, in the order they were received.
+func (r *WarningRecorder) Warnings() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.warnings...)
+}
+
+// Deprecations returns the recorded warnings about the use of deprecated
+// APIs, in the order they were received.
+func (r *WarningRecorder) Deprecations() []string {
+	var deprecations []string
+	for _, w := range r.Warnings() {
+		if strings.Contains(w, "deprecated") {
+			deprecations = append(deprecations, w)
+		}
+	}
+	return deprecations
+}
+
+// WithWarningHandler sets the handler for the warnings returned by the
+// Kubernetes API server to the requests made by the client.
+func WithWarningHandler(handler rest.WarningHandler) Option {
+	return func(c *MemoryRESTClientGetter) {
+		c.cfg.WarningHandler = handler
+	}
+}
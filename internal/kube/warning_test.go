@@ -0,0 +1,50 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+func TestWarningRecorder(t *testing.T) {
+	g := NewWithT(t)
+
+	deprecation := "policy/v1beta1 PodDisruptionBudget is deprecated in v1.21+, unavailable in v1.25+; use policy/v1 PodDisruptionBudget"
+	unknownField := `unknown field "spec.foo"`
+
+	r := NewWarningRecorder()
+	r.HandleWarningHeader(299, "", deprecation)
+	r.HandleWarningHeader(299, "", unknownField)
+	r.HandleWarningHeader(299, "", deprecation)
+	r.HandleWarningHeader(199, "", "miscellaneous warning")
+	r.HandleWarningHeader(299, "", "")
+
+	g.Expect(r.Warnings()).To(Equal([]string{deprecation, unknownField}))
+	g.Expect(r.Deprecations()).To(Equal([]string{deprecation}))
+}
+
+func TestWithWarningHandler(t *testing.T) {
+	g := NewWithT(t)
+
+	r := NewWarningRecorder()
+	c := &MemoryRESTClientGetter{cfg: &rest.Config{}}
+	WithWarningHandler(r)(c)
+	g.Expect(c.cfg.WarningHandler).To(Equal(r))
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package liveness provides health probes reflecting the liveness of the
+// reconcile loop of a controller, allowing Kubernetes to restart a wedged
+// controller.
+package liveness
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// workqueueDepthMetric is the name of the controller-runtime metric
+	// holding the current depth of the workqueue of a controller.
+	workqueueDepthMetric = "workqueue_depth"
+	// reconcileTotalMetric is the name of the controller-runtime metric
+	// counting the reconciliations of a controller.
+	reconcileTotalMetric = "controller_runtime_reconcile_total"
+	// activeWorkersMetric is the name of the controller-runtime metric
+	// holding the number of workers of a controller currently reconciling
+	// an item.
+	activeWorkersMetric = "controller_runtime_active_workers"
+
+	// apiServerCheckTimeout is the timeout of the API server connectivity
+	// check.
+	apiServerCheckTimeout = 5 * time.Second
+)
+
+// Checker determines the liveness of the reconcile loop of a controller from
+// the metrics recorded by controller-runtime, and the connectivity of the
+// controller to the Kubernetes API server. Its checks only apply while the
+// controller is the elected leader, as a standby instance does not reconcile.
+type Checker struct {
+	// controller is the name of the controller.
+	controller string
+	// threshold is the duration after which the reconcile loop is considered
+	// stuck when no item has been processed, nor is being processed, despite
+	// a nonzero queue depth.
+	threshold time.Duration
+	// gatherer gathers the controller-runtime metrics.
+	gatherer prometheus.Gatherer
+	// elected is closed when the controller is the elected leader.
+	elected <-chan struct{}
+	// client is used to check the connectivity to the API server.
+	client rest.Interface
+	// now returns the current time.
+	now func() time.Time
+
+	mu sync.Mutex
+	// lastReconciles is the reconcile count observed at lastProgress.
+	lastReconciles float64
+	// lastProgress is the time at which the reconcile loop was last observed
+	// to make progress, to be reconciling an item, or to be idle.
+	lastProgress time.Time
+}
+
+// New returns a new Checker for the controller with the given name. The
+// client is used to query the readiness endpoint of the API server.
+func New(controller string, threshold time.Duration, gatherer prometheus.Gatherer, elected <-chan struct{}, client rest.Interface) *Checker {
+	return &Checker{
+		controller: controller,
+		threshold:  threshold,
+		gatherer:   gatherer,
+		elected:    elected,
+		client:     client,
+		now:        time.Now,
+	}
+}
+
+// ReconcileLoop is a healthz.Checker which returns an error when the
+// workqueue of the controller has not been empty, and no item has been
+// reconciled, for longer than the threshold. A reconciliation in progress,
+// e.g. of a Helm release waiting for its resources to become ready for
+// longer than the threshold, is considered progress.
+func (c *Checker) ReconcileLoop(_ *http.Request) error {
+	if !c.isLeader() {
+		return nil
+	}
+
+	depth, reconciles, active, err := c.sample()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if depth == 0 || active > 0 || reconciles != c.lastReconciles || c.lastProgress.IsZero() {
+		c.lastReconciles = reconciles
+		c.lastProgress = now
+		return nil
+	}
+	if stalled := now.Sub(c.lastProgress); stalled > c.threshold {
+		return fmt.Errorf("no item reconciled by %s controller for %s with %.0f items queued",
+			c.controller, stalled.Round(time.Second), depth)
+	}
+	return nil
+}
+
+// APIServer is a healthz.Checker which returns an error when the readiness
+// endpoint of the API server can not be reached.
+func (c *Checker) APIServer(req *http.Request) error {
+	if !c.isLeader() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), apiServerCheckTimeout)
+	defer cancel()
+	if err := c.client.Get().AbsPath("/readyz").Do(ctx).Error(); err != nil {
+		return fmt.Errorf("unable to reach API server: %w", err)
+	}
+	return nil
+}
+
+// isLeader returns true if the controller is the elected leader.
+func (c *Checker) isLeader() bool {
+	select {
+	case <-c.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// sample returns the current workqueue depth, the total number of
+// reconciliations and the number of active workers of the controller.
+func (c *Checker) sample() (depth float64, reconciles float64, active float64, err error) {
+	families, err := c.gatherer.Gather()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	for _, f := range families {
+		switch f.GetName() {
+		case workqueueDepthMetric:
+			for _, m := range f.GetMetric() {
+				if hasLabel(m, "name", c.controller) || hasLabel(m, "controller", c.controller) {
+					depth = m.GetGauge().GetValue()
+					break
+				}
+			}
+		case reconcileTotalMetric:
+			for _, m := range f.GetMetric() {
+				if hasLabel(m, "controller", c.controller) {
+					reconciles += m.GetCounter().GetValue()
+				}
+			}
+		case activeWorkersMetric:
+			for _, m := range f.GetMetric() {
+				if hasLabel(m, "controller", c.controller) {
+					active = m.GetGauge().GetValue()
+					break
+				}
+			}
+		}
+	}
+	return depth, reconciles, active, nil
+}
+
+// hasLabel returns true if the metric has a label with the given name and
+// value.
+func hasLabel(m *dto.Metric, name, value string) bool {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name && l.GetValue() == value {
+			return true
+		}
+	}
+	return false
+}
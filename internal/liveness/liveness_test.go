@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package liveness
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+func TestChecker_ReconcileLoop(t *testing.T) {
+	g := NewWithT(t)
+
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: workqueueDepthMetric}, []string{"name", "controller"})
+	reconciles := prometheus.NewCounterVec(prometheus.CounterOpts{Name: reconcileTotalMetric}, []string{"controller", "result"})
+	active := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: activeWorkersMetric}, []string{"controller"})
+	registry.MustRegister(depth, reconciles, active)
+
+	elected := make(chan struct{})
+	now := time.Now()
+	c := New("helmrelease", time.Minute, registry, elected, nil)
+	c.now = func() time.Time { return now }
+
+	// Not checked when not the leader.
+	depth.WithLabelValues("helmrelease", "helmrelease").Set(5)
+	g.Expect(c.ReconcileLoop(nil)).To(Succeed())
+	now = now.Add(time.Hour)
+	g.Expect(c.ReconcileLoop(nil)).To(Succeed())
+
+	close(elected)
+
+	// The first observation is taken as progress.
+	g.Expect(c.ReconcileLoop(nil)).To(Succeed())
+
+	// Items are queued, but within the threshold.
+	now = now.Add(30 * time.Second)
+	g.Expect(c.ReconcileLoop(nil)).To(Succeed())
+
+	// Items are queued, and nothing has been reconciled beyond the threshold.
+	now = now.Add(time.Minute)
+	err := c.ReconcileLoop(nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("no item reconciled by helmrelease controller for 1m30s with 5 items queued"))
+
+	// Progress has been made.
+	reconciles.WithLabelValues("helmrelease", "success").Inc()
+	g.Expect(c.ReconcileLoop(nil)).To(Succeed())
+
+	// An item being reconciled for longer than the threshold is considered
+	// progress.
+	active.WithLabelValues("helmrelease").Set(1)
+	now = now.Add(time.Hour)
+	g.Expect(c.ReconcileLoop(nil)).To(Succeed())
+	now = now.Add(time.Hour)
+	g.Expect(c.ReconcileLoop(nil)).To(Succeed())
+
+	// Nothing has been reconciled beyond the threshold since the last
+	// reconciliation finished.
+	active.WithLabelValues("helmrelease").Set(0)
+	now = now.Add(30 * time.Second)
+	g.Expect(c.ReconcileLoop(nil)).To(Succeed())
+	now = now.Add(time.Minute)
+	g.Expect(c.ReconcileLoop(nil)).To(HaveOccurred())
+
+	// An empty queue is not considered stuck.
+	depth.WithLabelValues("helmrelease", "helmrelease").Set(0)
+	now = now.Add(time.Hour)
+	g.Expect(c.ReconcileLoop(nil)).To(Succeed())
+
+	// Metrics of other controllers are ignored.
+	depth.WithLabelValues("other", "other").Set(10)
+	now = now.Add(time.Hour)
+	g.Expect(c.ReconcileLoop(nil)).To(Succeed())
+}
+
+func TestChecker_APIServer(t *testing.T) {
+	g := NewWithT(t)
+
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" || !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := discovery.NewDiscoveryClientForConfigOrDie(&rest.Config{Host: srv.URL}).RESTClient()
+	elected := make(chan struct{})
+	c := New("helmrelease", time.Minute, prometheus.NewRegistry(), elected, client)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+
+	healthy.Store(false)
+	g.Expect(c.APIServer(req)).To(Succeed())
+
+	close(elected)
+	err := c.APIServer(req)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("unable to reach API server"))
+
+	healthy.Store(true)
+	g.Expect(c.APIServer(req)).To(Succeed())
+}
@@ -18,6 +18,8 @@ package loader
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	_ "crypto/sha256"
 	_ "crypto/sha512"
 	"errors"
@@ -32,6 +34,12 @@ import (
 	_ "github.com/opencontainers/go-digest/blake3"
 	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
 	"github.com/jessesimpson36/helm/v4/pkg/chart/v2/loader"
+	"go.opentelemetry.io/otel/attribute"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/fluxcd/pkg/runtime/logger"
+
+	"github.com/fluxcd/helm-controller/internal/tracing"
 )
 
 const (
@@ -47,19 +55,248 @@ var (
 	// ErrIntegrity signals a chart loader failed to verify the integrity of
 	// a chart, for example due to a digest mismatch.
 	ErrIntegrity = errors.New("integrity failure")
+	// ErrTooLarge signals a chart artifact or the chart decompressed from it
+	// exceeds the configured maximum size.
+	ErrTooLarge = errors.New("size limit exceeded")
 )
 
+// LoadOption configures the loading of a chart from an artifact URL.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	cache              *ArtifactCache
+	peer               string
+	peerToken          string
+	onIntegrityFailure func(error)
+	onArtifact         func([]byte)
+	maxArtifactSize    int64
+	maxChartSize       int64
+}
+
+// WithCache configures the ArtifactCache used to look up the artifact by
+// digest before downloading it, and to store it after it has been verified.
+func WithCache(cache *ArtifactCache) LoadOption {
+	return func(o *loadOptions) {
+		o.cache = cache
+	}
+}
+
+// WithPeer configures the URL of an ArtifactCacheServer from which the
+// artifact is pulled by digest before falling back to downloading it from
+// the artifact URL, and the bearer token presented to it.
+func WithPeer(peerURL, token string) LoadOption {
+	return func(o *loadOptions) {
+		o.peer = peerURL
+		o.peerToken = token
+	}
+}
+
+// WithIntegrityFailureHandler configures a handler which is called with
+// the ErrIntegrity error when the integrity of the artifact can not be
+// verified, after which the chart is loaded regardless instead of failing.
+// An artifact which failed verification is never stored in the cache.
+func WithIntegrityFailureHandler(fn func(error)) LoadOption {
+	return func(o *loadOptions) {
+		o.onIntegrityFailure = fn
+	}
+}
+
+// WithArtifactHandler configures a handler which is called with the raw
+// artifact data before the chart is loaded from it, e.g. to retain the
+// artifact when the chart fails to load or release.
+func WithArtifactHandler(fn func([]byte)) LoadOption {
+	return func(o *loadOptions) {
+		o.onArtifact = fn
+	}
+}
+
+// WithMaxArtifactSize configures the maximum size in bytes of the artifact.
+// The download of a larger artifact is aborted with an error wrapping
+// ErrTooLarge. A value of 0 or less disables the limit.
+func WithMaxArtifactSize(n int64) LoadOption {
+	return func(o *loadOptions) {
+		o.maxArtifactSize = n
+	}
+}
+
+// WithMaxChartSize configures the maximum size in bytes of the chart
+// decompressed from the artifact. The loading of a larger chart is aborted
+// with an error wrapping ErrTooLarge. A value of 0 or less disables the
+// limit.
+func WithMaxChartSize(n int64) LoadOption {
+	return func(o *loadOptions) {
+		o.maxChartSize = n
+	}
+}
+
 // SecureLoadChartFromURL attempts to download a Helm chart from the given URL
 // using the provided client. The retrieved data is verified against the given
 // digest before loading the chart. It returns the loaded chart.Chart, or an
 // error. The error may be of type ErrIntegrity if the integrity check fails.
-func SecureLoadChartFromURL(client *retryablehttp.Client, URL, digest string) (*chart.Chart, error) {
+// The download, including any retries, is aborted when the context is
+// canceled.
+//
+// When configured WithCache, an artifact with the same digest is loaded from
+// the cache instead of being downloaded again. When configured WithPeer, the
+// artifact is first pulled from the peer, and any failure to do so results in
+// a fallback to the artifact URL. When configured WithIntegrityFailureHandler,
+// integrity failures are passed to the handler instead of being returned.
+// When configured WithMaxArtifactSize or WithMaxChartSize, an artifact or
+// chart exceeding the limit results in an error wrapping ErrTooLarge, before
+// it is read into memory entirely.
+//
+// Without a cache, peer or artifact handler to hold on to the artifact data,
+// the download is streamed through the integrity check into the chart loader,
+// without buffering the artifact.
+func SecureLoadChartFromURL(ctx context.Context, client *retryablehttp.Client, URL, digest string, opts ...LoadOption) (*chart.Chart, error) {
+	o := &loadOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if digest == "" {
+		err := fmt.Errorf("%w: no digest advertised for artifact '%s'", ErrIntegrity, URL)
+		if o.onIntegrityFailure == nil {
+			return nil, err
+		}
+		o.onIntegrityFailure(err)
+	}
+
+	if !o.buffered() {
+		streamCtx, span := tracing.StartSpan(ctx, "stream-artifact", attribute.String("artifact.digest", digest))
+		c, err := o.stream(streamCtx, client, URL, digest)
+		tracing.EndSpan(span, err)
+		return c, err
+	}
+
+	fetchCtx, span := tracing.StartSpan(ctx, "download-artifact", attribute.String("artifact.digest", digest))
+	b, err := o.fetch(fetchCtx, client, URL, digest)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+
+	_, span = tracing.StartSpan(ctx, "load-chart")
+	c, err := o.load(b)
+	tracing.EndSpan(span, err)
+	return c, err
+}
+
+// fetch returns the artifact data with the given digest from the cache or
+// peer if configured, or downloads it from the given URL.
+func (o *loadOptions) fetch(ctx context.Context, client *retryablehttp.Client, URL, digest string) ([]byte, error) {
+	if b, ok := o.cache.Get(digest); ok && digest != "" {
+		return b, nil
+	}
+
+	if o.peer != "" && digest != "" {
+		b, err := fetchFromPeer(ctx, client.HTTPClient, o.peer, o.peerToken, digest, o.maxArtifactSize)
+		if err == nil {
+			o.cache.Set(digest, b)
+			return b, nil
+		}
+		log := ctrl.LoggerFrom(ctx)
+		if errors.Is(err, ErrFileNotFound) {
+			log.V(logger.DebugLevel).Info("artifact not found in cache of peer, downloading from source", "digest", digest)
+		} else {
+			log.Error(err, "failed to fetch artifact from cache peer, downloading from source", "digest", digest)
+		}
+	}
+
+	body, err := download(ctx, client, URL, o.maxArtifactSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		c        bytes.Buffer
+		verified = true
+	)
+	if digest == "" {
+		if _, err := io.Copy(&c, body); err != nil {
+			_ = body.Close()
+			return nil, fmt.Errorf("failed to copy chart artifact: %w", err)
+		}
+		verified = false
+	} else if err := copyAndVerify(digest, body, &c); err != nil {
+		if !errors.Is(err, ErrIntegrity) || o.onIntegrityFailure == nil {
+			_ = body.Close()
+			return nil, err
+		}
+		o.onIntegrityFailure(err)
+		verified = false
+	}
+
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	if verified {
+		o.cache.Set(digest, c.Bytes())
+	}
+	return c.Bytes(), nil
+}
+
+// buffered returns if the artifact data must be held in memory, because it
+// is stored in the cache, possibly pulled from a peer, or passed to the
+// artifact handler.
+func (o *loadOptions) buffered() bool {
+	return o.cache != nil || o.peer != "" || o.onArtifact != nil
+}
+
+// stream downloads the artifact from the given URL, and loads the chart
+// while the data is read from the response body. The data is verified
+// against the given digest as it passes through, and any data not consumed
+// by the chart loader is read to complete the verification. A chart which
+// fails verification is never returned, unless the integrity failure is
+// passed to the handler.
+func (o *loadOptions) stream(ctx context.Context, client *retryablehttp.Client, URL, digest string) (*chart.Chart, error) {
+	body, err := download(ctx, client, URL, o.maxArtifactSize)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if digest == "" {
+		return o.loadArchive(body)
+	}
+
+	dig, err := digestlib.Parse(digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse digest '%s': %w", digest, err)
+	}
+	verifier := dig.Verifier()
+	c, loadErr := o.loadArchive(io.TeeReader(body, verifier))
+	if errors.Is(loadErr, ErrTooLarge) {
+		return nil, loadErr
+	}
+	if _, err := io.Copy(verifier, body); err != nil {
+		return nil, fmt.Errorf("failed to copy and verify chart artifact: %w", err)
+	}
+	if !verifier.Verified() {
+		err := fmt.Errorf("%w: computed digest doesn't match '%s'", ErrIntegrity, dig)
+		if o.onIntegrityFailure == nil {
+			return nil, err
+		}
+		o.onIntegrityFailure(err)
+	}
+	return c, loadErr
+}
+
+// download performs a GET request for the given URL, and returns the body
+// of the response. It returns an error wrapping ErrFileNotFound if the
+// server responds with a 404 status code. When maxSize is greater than 0,
+// it returns an error wrapping ErrTooLarge if the advertised length of the
+// response exceeds it, and reading more than maxSize bytes from the body
+// fails with such an error. The caller is responsible for closing the
+// returned body.
+func download(ctx context.Context, client *retryablehttp.Client, URL string, maxSize int64) (io.ReadCloser, error) {
 	URL, err := overwriteHostname(URL, os.Getenv(envSourceControllerLocalhost))
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := retryablehttp.NewRequest(http.MethodGet, URL, nil)
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -75,17 +312,111 @@ func SecureLoadChartFromURL(client *retryablehttp.Client, URL, digest string) (*
 		}
 		return nil, fmt.Errorf("failed to download chart from '%s' (status: %s)", URL, resp.Status)
 	}
+	if maxSize > 0 {
+		err := fmt.Errorf("%w: artifact '%s' exceeds the maximum artifact size of %d bytes", ErrTooLarge, URL, maxSize)
+		if resp.ContentLength > maxSize {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		return &limitedReadCloser{ReadCloser: resp.Body, n: maxSize, err: err}, nil
+	}
+	return resp.Body, nil
+}
 
-	var c bytes.Buffer
-	if err := copyAndVerify(digest, resp.Body, &c); err != nil {
-		_ = resp.Body.Close()
-		return nil, err
+// load passes the given artifact data to the artifact handler (if any), and
+// loads the chart from it.
+func (o *loadOptions) load(b []byte) (*chart.Chart, error) {
+	if o.onArtifact != nil {
+		o.onArtifact(b)
 	}
+	return o.loadArchive(bytes.NewReader(b))
+}
 
-	if err := resp.Body.Close(); err != nil {
-		return nil, err
+// loadArchive loads the chart from the given archive. When a maximum chart
+// size is configured, the archive is decompressed in parallel to the chart
+// loader to abort the load with an error wrapping ErrTooLarge as soon as the
+// decompressed size exceeds the limit.
+func (o *loadOptions) loadArchive(r io.Reader) (*chart.Chart, error) {
+	if o.maxChartSize <= 0 {
+		return loader.LoadArchive(r)
+	}
+
+	l := newChartSizeLimiter(r, o.maxChartSize)
+	c, err := loader.LoadArchive(l)
+	if limitErr := l.Close(); limitErr != nil {
+		return nil, limitErr
+	}
+	return c, err
+}
+
+// limitedReadCloser is an io.ReadCloser which fails with err once more than
+// n bytes have been read from it.
+type limitedReadCloser struct {
+	io.ReadCloser
+	n   int64
+	err error
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, l.err
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
 	}
-	return loader.LoadArchive(&c)
+	n, err := l.ReadCloser.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, l.err
+	}
+	return n, err
+}
+
+// chartSizeLimiter is an io.Reader which decompresses the gzip data read
+// through it in a separate goroutine, and fails once the decompressed size
+// exceeds the limit. Malformed data is left for the reader of the
+// chartSizeLimiter to report.
+type chartSizeLimiter struct {
+	r    io.Reader
+	pw   *io.PipeWriter
+	done chan struct{}
+	err  error
+}
+
+// newChartSizeLimiter returns a chartSizeLimiter reading from r, which fails
+// once the data decompressed from it exceeds maxSize bytes. The caller must
+// call Close to release the goroutine.
+func newChartSizeLimiter(r io.Reader, maxSize int64) *chartSizeLimiter {
+	pr, pw := io.Pipe()
+	l := &chartSizeLimiter{
+		r:    io.TeeReader(r, pw),
+		pw:   pw,
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(l.done)
+		if zr, err := gzip.NewReader(pr); err == nil {
+			if n, _ := io.Copy(io.Discard, io.LimitReader(zr, maxSize+1)); n > maxSize {
+				l.err = fmt.Errorf("%w: chart exceeds the maximum chart size of %d bytes when decompressed", ErrTooLarge, maxSize)
+				_ = pr.CloseWithError(l.err)
+				return
+			}
+		}
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+	return l
+}
+
+func (l *chartSizeLimiter) Read(p []byte) (int, error) {
+	return l.r.Read(p)
+}
+
+// Close stops the decompression, and returns an error wrapping ErrTooLarge
+// if the decompressed size exceeded the limit.
+func (l *chartSizeLimiter) Close() error {
+	_ = l.pw.Close()
+	<-l.done
+	return l.err
 }
 
 // copyAndVerify copies the contents of reader to writer, and verifies the
@@ -18,6 +18,7 @@ package loader
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -25,6 +26,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	. "github.com/onsi/gomega"
@@ -41,6 +43,7 @@ func TestSecureLoadChartFromURL(t *testing.T) {
 
 	const chartPath = "/chart.tgz"
 	const notFoundPath = "/not-found.tgz"
+	const trailingPath = "/trailing.tgz"
 	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 		if req.URL.Path == chartPath {
 			res.WriteHeader(http.StatusOK)
@@ -51,6 +54,11 @@ func TestSecureLoadChartFromURL(t *testing.T) {
 			res.WriteHeader(http.StatusNotFound)
 			return
 		}
+		if req.URL.Path == trailingPath {
+			res.WriteHeader(http.StatusOK)
+			_, _ = res.Write(append(append([]byte{}, b...), "trailing"...))
+			return
+		}
 		res.WriteHeader(http.StatusInternalServerError)
 	}))
 	t.Cleanup(func() {
@@ -66,7 +74,7 @@ func TestSecureLoadChartFromURL(t *testing.T) {
 	t.Run("loads Helm chart from URL", func(t *testing.T) {
 		g := NewWithT(t)
 
-		got, err := SecureLoadChartFromURL(client, chartURL, digest.String())
+		got, err := SecureLoadChartFromURL(context.TODO(), client, chartURL, digest.String())
 		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(got.Name()).To(Equal("chart"))
@@ -79,7 +87,7 @@ func TestSecureLoadChartFromURL(t *testing.T) {
 		t.Setenv(envSourceControllerLocalhost, strings.TrimPrefix(server.URL, "http://"))
 		wrongHostnameURL := "http://invalid.com" + chartPath
 
-		got, err := SecureLoadChartFromURL(client, wrongHostnameURL, digest.String())
+		got, err := SecureLoadChartFromURL(context.TODO(), client, wrongHostnameURL, digest.String())
 		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(got.Name()).To(Equal("chart"))
@@ -89,16 +97,124 @@ func TestSecureLoadChartFromURL(t *testing.T) {
 	t.Run("error on chart data digest mismatch", func(t *testing.T) {
 		g := NewWithT(t)
 
-		got, err := SecureLoadChartFromURL(client, chartURL, digestlib.SHA256.FromString("invalid").String())
+		got, err := SecureLoadChartFromURL(context.TODO(), client, chartURL, digestlib.SHA256.FromString("invalid").String())
 		g.Expect(err).To(HaveOccurred())
 		g.Expect(errors.Is(err, ErrIntegrity)).To(BeTrue())
 		g.Expect(got).To(BeNil())
 	})
 
+	t.Run("error on trailing data not read by chart loader", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got, err := SecureLoadChartFromURL(context.TODO(), client, server.URL+trailingPath, digest.String())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(errors.Is(err, ErrIntegrity)).To(BeTrue())
+		g.Expect(got).To(BeNil())
+	})
+
+	t.Run("error on missing digest", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got, err := SecureLoadChartFromURL(context.TODO(), client, chartURL, "")
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(errors.Is(err, ErrIntegrity)).To(BeTrue())
+		g.Expect(got).To(BeNil())
+	})
+
+	t.Run("loads chart on digest mismatch with integrity failure handler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache := NewArtifactCache(1)
+		invalid := digestlib.SHA256.FromString("invalid").String()
+		var failures []error
+		got, err := SecureLoadChartFromURL(context.TODO(), client, chartURL, invalid,
+			WithCache(cache), WithIntegrityFailureHandler(func(err error) {
+				failures = append(failures, err)
+			}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.Name()).To(Equal("chart"))
+		g.Expect(failures).To(HaveLen(1))
+		g.Expect(errors.Is(failures[0], ErrIntegrity)).To(BeTrue())
+
+		_, ok := cache.Get(invalid)
+		g.Expect(ok).To(BeFalse())
+	})
+
+	t.Run("loads chart without digest with integrity failure handler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var failures []error
+		got, err := SecureLoadChartFromURL(context.TODO(), client, chartURL, "",
+			WithIntegrityFailureHandler(func(err error) {
+				failures = append(failures, err)
+			}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(failures).To(HaveLen(1))
+		g.Expect(failures[0].Error()).To(ContainSubstring("no digest advertised"))
+	})
+
+	t.Run("passes artifact to handler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cache := NewArtifactCache(1)
+		for range 2 {
+			var artifact []byte
+			got, err := SecureLoadChartFromURL(context.TODO(), client, chartURL, digest.String(),
+				WithCache(cache), WithArtifactHandler(func(b []byte) {
+					artifact = b
+				}))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).ToNot(BeNil())
+			g.Expect(artifact).To(Equal(b))
+		}
+	})
+
+	t.Run("loads chart within size limits", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got, err := SecureLoadChartFromURL(context.TODO(), client, chartURL, digest.String(),
+			WithMaxArtifactSize(int64(len(b))), WithMaxChartSize(1<<20))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.Name()).To(Equal("chart"))
+	})
+
+	t.Run("error on artifact exceeding max artifact size", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, opts := range [][]LoadOption{
+			{WithMaxArtifactSize(int64(len(b) - 1))},
+			{WithMaxArtifactSize(int64(len(b) - 1)), WithCache(NewArtifactCache(1))},
+		} {
+			got, err := SecureLoadChartFromURL(context.TODO(), client, chartURL, digest.String(), opts...)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(errors.Is(err, ErrTooLarge)).To(BeTrue())
+			g.Expect(err.Error()).To(ContainSubstring("maximum artifact size"))
+			g.Expect(got).To(BeNil())
+		}
+	})
+
+	t.Run("error on chart exceeding max chart size", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, opts := range [][]LoadOption{
+			{WithMaxChartSize(1024)},
+			{WithMaxChartSize(1024), WithCache(NewArtifactCache(1))},
+		} {
+			got, err := SecureLoadChartFromURL(context.TODO(), client, chartURL, digest.String(), opts...)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(errors.Is(err, ErrTooLarge)).To(BeTrue())
+			g.Expect(err.Error()).To(ContainSubstring("maximum chart size"))
+			g.Expect(got).To(BeNil())
+		}
+	})
+
 	t.Run("file not found error on 404", func(t *testing.T) {
 		g := NewWithT(t)
 
-		got, err := SecureLoadChartFromURL(client, server.URL+notFoundPath, digest.String())
+		got, err := SecureLoadChartFromURL(context.TODO(), client, server.URL+notFoundPath, digest.String())
 		g.Expect(errors.Is(err, ErrFileNotFound)).To(BeTrue())
 		g.Expect(got).To(BeNil())
 	})
@@ -106,13 +222,77 @@ func TestSecureLoadChartFromURL(t *testing.T) {
 	t.Run("error on HTTP request failure", func(t *testing.T) {
 		g := NewWithT(t)
 
-		got, err := SecureLoadChartFromURL(client, server.URL+"/invalid.tgz", digest.String())
+		got, err := SecureLoadChartFromURL(context.TODO(), client, server.URL+"/invalid.tgz", digest.String())
 		g.Expect(err).To(HaveOccurred())
 		g.Expect(errors.Is(err, ErrFileNotFound)).To(BeFalse())
 		g.Expect(got).To(BeNil())
 	})
 }
 
+func TestSecureLoadChartFromURL_WithCache(t *testing.T) {
+	g := NewWithT(t)
+
+	b, err := os.ReadFile("testdata/chart-0.1.0.tgz")
+	g.Expect(err).ToNot(HaveOccurred())
+	digest := digestlib.SHA256.FromBytes(b)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requests++
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write(b)
+	}))
+	t.Cleanup(func() {
+		server.Close()
+	})
+
+	client := retryablehttp.NewClient()
+	client.Logger = nil
+	client.RetryMax = 2
+
+	cache := NewArtifactCache(1)
+	for i := 0; i < 3; i++ {
+		got, err := SecureLoadChartFromURL(context.TODO(), client, server.URL+"/chart.tgz", digest.String(), WithCache(cache))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.Name()).To(Equal("chart"))
+	}
+	g.Expect(requests).To(Equal(1))
+	g.Expect(cache.Len()).To(Equal(1))
+
+	// Artifacts failing verification are not cached.
+	_, err = SecureLoadChartFromURL(context.TODO(), client, server.URL+"/chart.tgz", digestlib.SHA256.FromString("invalid").String(), WithCache(cache))
+	g.Expect(errors.Is(err, ErrIntegrity)).To(BeTrue())
+	_, ok := cache.Get(digestlib.SHA256.FromString("invalid").String())
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestSecureLoadChartFromURL_ContextCanceled(t *testing.T) {
+	g := NewWithT(t)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requests++
+		res.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(func() {
+		server.Close()
+	})
+
+	httpClient, err := NewHTTPClient(HTTPClientOptions{Timeout: time.Second})
+	g.Expect(err).ToNot(HaveOccurred())
+	client := NewRetryableHTTPClient(context.TODO(), 5, httpClient)
+	client.Logger = nil
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	got, err := SecureLoadChartFromURL(ctx, client, server.URL+"/chart.tgz", digestlib.SHA256.FromString("foo").String())
+	g.Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+	g.Expect(got).To(BeNil())
+	g.Expect(requests).To(BeZero())
+}
+
 func Test_copyAndVerify(t *testing.T) {
 	g := NewWithT(t)
 
@@ -177,6 +357,21 @@ func Test_copyAndVerify(t *testing.T) {
 	}
 }
 
+func Test_limitedReadCloser(t *testing.T) {
+	g := NewWithT(t)
+
+	errLimit := errors.New("limit")
+	l := &limitedReadCloser{ReadCloser: io.NopCloser(strings.NewReader("foobar")), n: 3, err: errLimit}
+	b, err := io.ReadAll(l)
+	g.Expect(err).To(MatchError(errLimit))
+	g.Expect(string(b)).To(HavePrefix("foo"))
+
+	l = &limitedReadCloser{ReadCloser: io.NopCloser(strings.NewReader("foo")), n: 3, err: errLimit}
+	b, err = io.ReadAll(l)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(b)).To(Equal("foo"))
+}
+
 func Test_overwriteHostname(t *testing.T) {
 	tests := []struct {
 		name     string
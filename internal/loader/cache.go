@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"container/list"
+	"sync"
+)
+
+// ArtifactCache is an in-memory least-recently-used cache for verified chart
+// artifacts, keyed by the digest of the artifact. It allows multiple
+// HelmReleases referring to the same chart artifact, or repeated
+// reconciliations of the same revision, to share a single download.
+//
+// The cache stores the raw artifact data rather than the loaded chart, as
+// Helm actions mutate the chart they operate on.
+type ArtifactCache struct {
+	maxItems int
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type artifactCacheEntry struct {
+	digest string
+	data   []byte
+}
+
+// NewArtifactCache returns a new ArtifactCache which holds at most maxItems
+// artifacts. It returns nil if maxItems is less than one, which is a valid
+// (disabled) cache.
+func NewArtifactCache(maxItems int) *ArtifactCache {
+	if maxItems < 1 {
+		return nil
+	}
+	return &ArtifactCache{
+		maxItems: maxItems,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element, maxItems),
+	}
+}
+
+// Get returns the artifact data for the given digest, and true if it was
+// found in the cache.
+func (c *ArtifactCache) Get(digest string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[digest]; ok {
+		c.ll.MoveToFront(e)
+		return e.Value.(*artifactCacheEntry).data, true
+	}
+	return nil, false
+}
+
+// Set adds the artifact data for the given digest to the cache. If the cache
+// is full, the least recently used artifact is evicted.
+func (c *ArtifactCache) Set(digest string, data []byte) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[digest]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*artifactCacheEntry).data = data
+		return
+	}
+
+	c.items[digest] = c.ll.PushFront(&artifactCacheEntry{digest: digest, data: data})
+	for c.ll.Len() > c.maxItems {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*artifactCacheEntry).digest)
+	}
+}
+
+// Len returns the number of artifacts in the cache.
+func (c *ArtifactCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
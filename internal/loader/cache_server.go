@@ -0,0 +1,202 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// artifactCachePath is the path prefix under which the ArtifactCacheServer
+// serves the artifacts, followed by the digest of the artifact.
+const artifactCachePath = "/artifacts/"
+
+// ArtifactCacheServer serves the artifacts of an ArtifactCache over HTTP, by
+// digest. This allows peers (e.g. other replicas or shards of the controller)
+// to pull a chart artifact from the cache instead of downloading it again
+// from the source-controller.
+//
+// As the artifacts may contain confidential charts, the server requires the
+// peers to present a bearer token. Without a token, the server can only be
+// bound to a loopback address.
+type ArtifactCacheServer struct {
+	addr  string
+	token string
+	cache *ArtifactCache
+}
+
+// NewArtifactCacheServer returns a new ArtifactCacheServer which serves the
+// artifacts of the given cache on the given address, to peers presenting
+// the given bearer token.
+func NewArtifactCacheServer(addr string, cache *ArtifactCache, token string) *ArtifactCacheServer {
+	return &ArtifactCacheServer{addr: addr, token: token, cache: cache}
+}
+
+// Start starts the server and blocks until the context is canceled, after
+// which the server is gracefully shut down. It implements the
+// manager.Runnable interface.
+func (s *ArtifactCacheServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(artifactCachePath, s)
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	if s.token == "" && !isLoopbackAddr(s.addr) {
+		return fmt.Errorf("artifact cache server requires a token to bind to the non-loopback address '%s'", s.addr)
+	}
+
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on '%s': %w", s.addr, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// NeedLeaderElection returns false, as the cache of every replica is
+// served regardless of whether it is the leader.
+func (s *ArtifactCacheServer) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP serves the artifact for the digest in the request path, or
+// responds with 404 if the artifact is not in the cache. Requests without
+// the configured bearer token are responded to with 401.
+func (s *ArtifactCacheServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if s.token != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	digest := strings.TrimPrefix(r.URL.Path, artifactCachePath)
+	b, ok := s.cache.Get(digest)
+	if digest == "" || !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(b)
+}
+
+// isLoopbackAddr returns true if the host of the given address is localhost
+// or a loopback IP address.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// fetchFromPeer attempts to retrieve the artifact with the given digest from
+// the ArtifactCacheServer at the peer URL, presenting the given bearer token
+// if not empty. The retrieved data is verified against the digest. When
+// maxSize is greater than 0, an artifact exceeding it results in an error
+// wrapping ErrTooLarge.
+func fetchFromPeer(ctx context.Context, client *http.Client, peer, token, digest string, maxSize int64) ([]byte, error) {
+	u, err := url.JoinPath(peer, artifactCachePath, url.PathEscape(digest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct artifact cache peer URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("artifact not found in cache of peer '%s': %w", peer, ErrFileNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch artifact from peer '%s' (status: %s)", peer, resp.Status)
+	}
+
+	var body io.Reader = resp.Body
+	if maxSize > 0 {
+		err := fmt.Errorf("%w: artifact '%s' of peer '%s' exceeds the maximum artifact size of %d bytes", ErrTooLarge, digest, peer, maxSize)
+		if resp.ContentLength > maxSize {
+			return nil, err
+		}
+		body = &limitedReadCloser{ReadCloser: resp.Body, n: maxSize, err: err}
+	}
+
+	var b bytes.Buffer
+	if err := copyAndVerify(digest, body, &b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	. "github.com/onsi/gomega"
+	digestlib "github.com/opencontainers/go-digest"
+)
+
+func TestArtifactCacheServer_ServeHTTP(t *testing.T) {
+	digest := digestlib.SHA256.FromString("foo").String()
+
+	cache := NewArtifactCache(1)
+	cache.Set(digest, []byte("foo"))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "cached artifact",
+			method:     http.MethodGet,
+			path:       artifactCachePath + digest,
+			wantStatus: http.StatusOK,
+			wantBody:   "foo",
+		},
+		{
+			name:       "cached artifact head",
+			method:     http.MethodHead,
+			path:       artifactCachePath + digest,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unknown artifact",
+			method:     http.MethodGet,
+			path:       artifactCachePath + digestlib.SHA256.FromString("bar").String(),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "no digest",
+			method:     http.MethodGet,
+			path:       artifactCachePath,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			path:       artifactCachePath + digest,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			rec := httptest.NewRecorder()
+			NewArtifactCacheServer("", cache, "").ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			g.Expect(rec.Code).To(Equal(tt.wantStatus))
+			if tt.wantBody != "" {
+				g.Expect(rec.Body.String()).To(Equal(tt.wantBody))
+			}
+		})
+	}
+}
+
+func TestSecureLoadChartFromURL_WithPeer(t *testing.T) {
+	b, err := os.ReadFile("testdata/chart-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := digestlib.SHA256.FromBytes(b).String()
+
+	var sourceRequests atomic.Int32
+	source := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		sourceRequests.Add(1)
+		_, _ = res.Write(b)
+	}))
+	t.Cleanup(source.Close)
+
+	client := retryablehttp.NewClient()
+	client.Logger = nil
+	client.RetryMax = 0
+
+	t.Run("pulls artifact from peer", func(t *testing.T) {
+		g := NewWithT(t)
+		sourceRequests.Store(0)
+
+		peerCache := NewArtifactCache(1)
+		peerCache.Set(digest, b)
+		peer := httptest.NewServer(NewArtifactCacheServer("", peerCache, ""))
+		t.Cleanup(peer.Close)
+
+		cache := NewArtifactCache(1)
+		got, err := SecureLoadChartFromURL(context.TODO(), client, source.URL+"/chart.tgz", digest, WithCache(cache), WithPeer(peer.URL, ""))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Name()).To(Equal("chart"))
+		g.Expect(sourceRequests.Load()).To(BeZero())
+		g.Expect(cache.Len()).To(Equal(1))
+	})
+
+	t.Run("falls back to source on peer cache miss", func(t *testing.T) {
+		g := NewWithT(t)
+		sourceRequests.Store(0)
+
+		peer := httptest.NewServer(NewArtifactCacheServer("", NewArtifactCache(1), ""))
+		t.Cleanup(peer.Close)
+
+		got, err := SecureLoadChartFromURL(context.TODO(), client, source.URL+"/chart.tgz", digest, WithPeer(peer.URL, ""))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Name()).To(Equal("chart"))
+		g.Expect(sourceRequests.Load()).To(Equal(int32(1)))
+	})
+
+	t.Run("falls back to source on peer integrity failure", func(t *testing.T) {
+		g := NewWithT(t)
+		sourceRequests.Store(0)
+
+		peerCache := NewArtifactCache(1)
+		peerCache.Set(digest, []byte("invalid"))
+		peer := httptest.NewServer(NewArtifactCacheServer("", peerCache, ""))
+		t.Cleanup(peer.Close)
+
+		got, err := SecureLoadChartFromURL(context.TODO(), client, source.URL+"/chart.tgz", digest, WithPeer(peer.URL, ""))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Name()).To(Equal("chart"))
+		g.Expect(sourceRequests.Load()).To(Equal(int32(1)))
+	})
+}
+
+func Test_fetchFromPeer(t *testing.T) {
+	g := NewWithT(t)
+
+	peer := httptest.NewServer(NewArtifactCacheServer("", NewArtifactCache(1), ""))
+	t.Cleanup(peer.Close)
+
+	_, err := fetchFromPeer(context.TODO(), http.DefaultClient, peer.URL, "", digestlib.SHA256.FromString("foo").String(), 0)
+	g.Expect(errors.Is(err, ErrFileNotFound)).To(BeTrue())
+}
+
+func TestArtifactCacheServer_token(t *testing.T) {
+	digest := digestlib.SHA256.FromString("foo").String()
+
+	cache := NewArtifactCache(1)
+	cache.Set(digest, []byte("foo"))
+	peer := httptest.NewServer(NewArtifactCacheServer("", cache, "secret"))
+	t.Cleanup(peer.Close)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{
+			name:  "valid token",
+			token: "secret",
+		},
+		{
+			name:    "invalid token",
+			token:   "invalid",
+			wantErr: "401 Unauthorized",
+		},
+		{
+			name:    "no token",
+			wantErr: "401 Unauthorized",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			b, err := fetchFromPeer(context.TODO(), http.DefaultClient, peer.URL, tt.token, digest, 0)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(b).To(Equal([]byte("foo")))
+		})
+	}
+}
+
+func TestArtifactCacheServer_Start(t *testing.T) {
+	g := NewWithT(t)
+
+	err := NewArtifactCacheServer(":0", NewArtifactCache(1), "").Start(context.TODO())
+	g.Expect(err).To(MatchError(ContainSubstring("requires a token")))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	g.Expect(NewArtifactCacheServer("127.0.0.1:0", NewArtifactCache(1), "").Start(ctx)).To(Succeed())
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNewArtifactCache(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(NewArtifactCache(0)).To(BeNil())
+	g.Expect(NewArtifactCache(-1)).To(BeNil())
+	g.Expect(NewArtifactCache(1)).ToNot(BeNil())
+}
+
+func TestArtifactCache(t *testing.T) {
+	t.Run("evicts least recently used", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := NewArtifactCache(2)
+		c.Set("a", []byte("a"))
+		c.Set("b", []byte("b"))
+
+		// Mark "a" as recently used.
+		got, ok := c.Get("a")
+		g.Expect(ok).To(BeTrue())
+		g.Expect(got).To(Equal([]byte("a")))
+
+		c.Set("c", []byte("c"))
+		g.Expect(c.Len()).To(Equal(2))
+
+		_, ok = c.Get("b")
+		g.Expect(ok).To(BeFalse())
+		_, ok = c.Get("a")
+		g.Expect(ok).To(BeTrue())
+		_, ok = c.Get("c")
+		g.Expect(ok).To(BeTrue())
+	})
+
+	t.Run("overwrites existing entry", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := NewArtifactCache(2)
+		c.Set("a", []byte("a"))
+		c.Set("a", []byte("b"))
+		g.Expect(c.Len()).To(Equal(1))
+
+		got, ok := c.Get("a")
+		g.Expect(ok).To(BeTrue())
+		g.Expect(got).To(Equal([]byte("b")))
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var c *ArtifactCache
+		c.Set("a", []byte("a"))
+		_, ok := c.Get("a")
+		g.Expect(ok).To(BeFalse())
+		g.Expect(c.Len()).To(BeZero())
+	})
+}
@@ -17,24 +17,221 @@ limitations under the License.
 package loader
 
 import (
+	"container/list"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-retryablehttp"
+	"golang.org/x/net/http/httpproxy"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+// HTTPClientOptions holds the configuration of the HTTP client used to load
+// artifacts.
+type HTTPClientOptions struct {
+	// Timeout limits the time spent on a single request attempt, including
+	// reading the response body. A timeout of zero means no timeout.
+	Timeout time.Duration
+	// CAFile is the path to a PEM encoded CA bundle used to verify the
+	// certificate of the artifact server, in addition to the system roots.
+	CAFile string
+	// CertFile and KeyFile are the paths to a PEM encoded client certificate
+	// and key, used to authenticate with the artifact server.
+	CertFile string
+	KeyFile  string
+	// ProxyURL is the URL of the proxy used to reach the artifact server.
+	// When empty, the proxy is configured from the HTTP_PROXY, HTTPS_PROXY
+	// and NO_PROXY environment variables.
+	ProxyURL string
+}
+
+// NewHTTPClient returns a new HTTP client with a pooled transport for loading
+// artifacts, which is safe to share between reconciliations.
+func NewHTTPClient(opts HTTPClientOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.ProxyURL != "" {
+		u, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL '%s': %w", opts.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	if opts.CAFile != "" || opts.CertFile != "" || opts.KeyFile != "" {
+		tlsConfig, err := newTLSConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   opts.Timeout,
+	}, nil
+}
+
+// WithProxy returns a copy of the given HTTP client which uses the given
+// proxy configuration instead of the proxy of the client. As the copy does
+// not share the connection pool of the client, it should only be used for
+// the requests which require the proxy configuration, and be reused for them
+// (e.g. using a ProxyClientCache).
+func WithProxy(httpClient *http.Client, cfg *httpproxy.Config) *http.Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+
+	proxyFunc := cfg.ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   httpClient.Timeout,
+	}
+}
+
+// DefaultProxyClientCacheSize is the maximum number of HTTP clients held by a
+// ProxyClientCache created using NewProxyClientCache.
+const DefaultProxyClientCacheSize = 100
+
+// ProxyClientCache holds the HTTP clients created using WithProxy for a
+// base client, keyed by the source of their proxy configuration (e.g. the
+// UID of a proxy Secret). This allows the connection pool of a proxied client
+// to be reused across requests, instead of creating a new one for every
+// request.
+//
+// The number of cached clients is bounded, evicting the least recently used
+// client first, so that clients of proxy configurations which are no longer
+// referenced (e.g. of a deleted Secret) do not accumulate.
+type ProxyClientCache struct {
+	mu         sync.Mutex
+	base       *http.Client
+	maxEntries int
+	entries    map[string]*list.Element
+	// lru holds the proxyClientEntry values, ordered from the most to the
+	// least recently used.
+	lru *list.List
+}
+
+type proxyClientEntry struct {
+	key     string
+	version string
+	client  *http.Client
+}
+
+// NewProxyClientCache returns a new, empty ProxyClientCache for the given
+// base HTTP client, which holds up to DefaultProxyClientCacheSize clients.
+func NewProxyClientCache(base *http.Client) *ProxyClientCache {
+	return &ProxyClientCache{
+		base:       base,
+		maxEntries: DefaultProxyClientCacheSize,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Get returns the HTTP client cached for the given key if it was created for
+// the same version (e.g. the resource version of a proxy Secret). Otherwise,
+// it creates a new client using WithProxy with the given proxy configuration,
+// and replaces any cached client. The idle connections of a replaced or
+// evicted client are closed.
+func (c *ProxyClientCache) Get(key, version string, cfg *httpproxy.Config) *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*proxyClientEntry)
+		if e.version == version {
+			c.lru.MoveToFront(el)
+			return e.client
+		}
+		c.remove(el)
+	}
+
+	client := WithProxy(c.base, cfg)
+	c.entries[key] = c.lru.PushFront(&proxyClientEntry{key: key, version: version, client: client})
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+	return client
+}
+
+// remove removes the given element from the cache, and closes the idle
+// connections of its client. The caller must hold the lock.
+func (c *ProxyClientCache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*proxyClientEntry)
+	delete(c.entries, e.key)
+	e.client.CloseIdleConnections()
+}
+
+// newTLSConfig returns a TLS configuration with the CA bundle and client
+// certificate of the given options.
+func newTLSConfig(opts HTTPClientOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if opts.CAFile != "" {
+		caPEM, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid certificates found in CA file '%s'", opts.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		if opts.CertFile == "" || opts.KeyFile == "" {
+			return nil, errors.New("both a client certificate and key file must be provided")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
 // NewRetryableHTTPClient returns a new retrying HTTP client for loading
-// artifacts. The client will retry up to the given number of times before
-// giving up. The context is used to log errors.
-func NewRetryableHTTPClient(ctx context.Context, retries int) *retryablehttp.Client {
-	httpClient := retryablehttp.NewClient()
-	httpClient.RetryWaitMin = 5 * time.Second
-	httpClient.RetryWaitMax = 30 * time.Second
-	httpClient.RetryMax = retries
-	httpClient.Logger = newLoggerForContext(ctx)
-	return httpClient
+// artifacts. The client will retry up to the given number of times with an
+// exponential backoff on connection errors and 5xx responses, before giving
+// up. The context is used to log errors.
+// If httpClient is not nil, it is used to perform the requests.
+func NewRetryableHTTPClient(ctx context.Context, retries int, httpClient *http.Client) *retryablehttp.Client {
+	client := retryablehttp.NewClient()
+	if httpClient != nil {
+		client.HTTPClient = httpClient
+	}
+	client.RetryWaitMin = 5 * time.Second
+	client.RetryWaitMax = 30 * time.Second
+	client.RetryMax = retries
+	client.Logger = newLoggerForContext(ctx)
+	return client
 }
 
 func newLoggerForContext(ctx context.Context) retryablehttp.LeveledLogger {
@@ -0,0 +1,186 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/http/httpproxy"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("applies timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c, err := NewHTTPClient(HTTPClientOptions{Timeout: time.Minute})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c.Timeout).To(Equal(time.Minute))
+	})
+
+	t.Run("configures proxy URL", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c, err := NewHTTPClient(HTTPClientOptions{ProxyURL: "http://proxy.example.com:3128"})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		req, _ := http.NewRequest(http.MethodGet, "http://source-controller.flux-system.svc", nil)
+		got, err := c.Transport.(*http.Transport).Proxy(req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal(&url.URL{Scheme: "http", Host: "proxy.example.com:3128"}))
+	})
+
+	t.Run("rejects invalid proxy URL", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := NewHTTPClient(HTTPClientOptions{ProxyURL: "://invalid"})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("invalid proxy URL"))
+	})
+
+	t.Run("trusts CA file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+
+		c, err := NewHTTPClient(HTTPClientOptions{})
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = c.Get(server.URL)
+		g.Expect(err).To(HaveOccurred())
+
+		caFile := filepath.Join(t.TempDir(), "ca.crt")
+		g.Expect(os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: server.Certificate().Raw,
+		}), 0o600)).To(Succeed())
+
+		c, err = NewHTTPClient(HTTPClientOptions{CAFile: caFile})
+		g.Expect(err).ToNot(HaveOccurred())
+		resp, err := c.Get(server.URL)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(resp.Body.Close()).To(Succeed())
+		g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	t.Run("rejects CA file without certificates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		caFile := filepath.Join(t.TempDir(), "ca.crt")
+		g.Expect(os.WriteFile(caFile, []byte("invalid"), 0o600)).To(Succeed())
+
+		_, err := NewHTTPClient(HTTPClientOptions{CAFile: caFile})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("no valid certificates"))
+	})
+
+	t.Run("requires both client certificate and key", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := NewHTTPClient(HTTPClientOptions{CertFile: "tls.crt"})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("both a client certificate and key file"))
+	})
+}
+
+func TestWithProxy(t *testing.T) {
+	g := NewWithT(t)
+
+	base, err := NewHTTPClient(HTTPClientOptions{Timeout: time.Minute, ProxyURL: "http://proxy.example.com:3128"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	c := WithProxy(base, &httpproxy.Config{
+		HTTPProxy: "http://egress.example.com:8080",
+		NoProxy:   "mirror.svc.cluster.local",
+	})
+	g.Expect(c).ToNot(BeIdenticalTo(base))
+	g.Expect(c.Timeout).To(Equal(time.Minute))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://charts.example.com/podinfo.tgz", nil)
+	got, err := c.Transport.(*http.Transport).Proxy(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(&url.URL{Scheme: "http", Host: "egress.example.com:8080"}))
+
+	req, _ = http.NewRequest(http.MethodGet, "http://mirror.svc.cluster.local/podinfo.tgz", nil)
+	got, err = c.Transport.(*http.Transport).Proxy(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(BeNil())
+
+	// The proxy of the base client must not be modified.
+	got, err = base.Transport.(*http.Transport).Proxy(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(&url.URL{Scheme: "http", Host: "proxy.example.com:3128"}))
+}
+
+func TestProxyClientCache(t *testing.T) {
+	g := NewWithT(t)
+
+	base, err := NewHTTPClient(HTTPClientOptions{Timeout: time.Minute})
+	g.Expect(err).ToNot(HaveOccurred())
+	cache := NewProxyClientCache(base)
+
+	cfg := &httpproxy.Config{HTTPProxy: "http://egress.example.com:8080"}
+	c1 := cache.Get("uid", "1", cfg)
+	g.Expect(c1).ToNot(BeIdenticalTo(base))
+	g.Expect(c1.Timeout).To(Equal(time.Minute))
+	g.Expect(cache.Get("uid", "1", cfg)).To(BeIdenticalTo(c1))
+
+	// A new version of the proxy configuration replaces the client.
+	c2 := cache.Get("uid", "2", &httpproxy.Config{HTTPProxy: "http://egress.example.com:8081"})
+	g.Expect(c2).ToNot(BeIdenticalTo(c1))
+	req, _ := http.NewRequest(http.MethodGet, "http://charts.example.com/podinfo.tgz", nil)
+	got, err := c2.Transport.(*http.Transport).Proxy(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(&url.URL{Scheme: "http", Host: "egress.example.com:8081"}))
+
+	// Clients are kept per key.
+	c3 := cache.Get("other-uid", "2", cfg)
+	g.Expect(c3).ToNot(BeIdenticalTo(c2))
+	g.Expect(cache.lru.Len()).To(Equal(2))
+}
+
+func TestProxyClientCache_evictsLeastRecentlyUsed(t *testing.T) {
+	g := NewWithT(t)
+
+	cache := NewProxyClientCache(&http.Client{})
+	cache.maxEntries = 2
+
+	cfg := &httpproxy.Config{HTTPProxy: "http://egress.example.com:8080"}
+	a := cache.Get("a", "1", cfg)
+	b := cache.Get("b", "1", cfg)
+	// Using a makes b the least recently used client.
+	g.Expect(cache.Get("a", "1", cfg)).To(BeIdenticalTo(a))
+
+	cache.Get("c", "1", cfg)
+	g.Expect(cache.lru.Len()).To(Equal(2))
+	g.Expect(cache.entries).To(HaveKey("a"))
+	g.Expect(cache.entries).To(HaveKey("c"))
+	g.Expect(cache.entries).ToNot(HaveKey("b"))
+
+	// An evicted client is recreated on the next request.
+	g.Expect(cache.Get("b", "1", cfg)).ToNot(BeIdenticalTo(b))
+	g.Expect(cache.entries).ToNot(HaveKey("a"))
+}
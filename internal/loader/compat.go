@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+)
+
+// ErrIncompatibleChart signals a chart can not be released with the Helm SDK
+// of the controller.
+var ErrIncompatibleChart = errors.New("incompatible chart")
+
+// libraryChartType is the type of Helm library charts.
+const libraryChartType = "library"
+
+// CheckCompatibility checks if the given chart can be released with the Helm
+// SDK of the controller, to report any incompatibility before a Helm action
+// fails on it. It checks that:
+//
+//   - the apiVersion of the chart and its dependencies is supported
+//   - the chart is an application chart, as library charts can not be
+//     installed
+//
+// The returned error wraps ErrIncompatibleChart, and lists all the detected
+// incompatibilities.
+//
+// Dependencies declared in the chart metadata which are not included in the
+// chart, taking aliases into account, are returned as warnings. They do not
+// necessarily make the release fail, e.g. when they are disabled by a
+// condition or tag.
+func CheckCompatibility(chrt *chart.Chart) (warnings []string, err error) {
+	var problems []string
+	if chrt.Metadata != nil && chrt.Metadata.Type == libraryChartType {
+		problems = append(problems, fmt.Sprintf("chart '%s' is a library chart, which can not be installed", chrt.Name()))
+	}
+	p, warnings := checkChartCompatibility(chrt, chrt.Name())
+	problems = append(problems, p...)
+	if len(problems) == 0 {
+		return warnings, nil
+	}
+	return warnings, fmt.Errorf("%w: %s", ErrIncompatibleChart, strings.Join(problems, "; "))
+}
+
+// checkChartCompatibility returns the incompatibilities of the given chart
+// and its dependencies, and the dependencies missing from them, identifying
+// the chart by the given path.
+func checkChartCompatibility(chrt *chart.Chart, path string) (problems []string, warnings []string) {
+	if chrt.Metadata == nil {
+		return []string{fmt.Sprintf("chart '%s' has no metadata", path)}, nil
+	}
+
+	switch chrt.Metadata.APIVersion {
+	case chart.APIVersionV1, chart.APIVersionV2:
+	default:
+		problems = append(problems, fmt.Sprintf("chart '%s' has apiVersion '%s', which is not supported by the Helm SDK (supported: '%s', '%s')",
+			path, chrt.Metadata.APIVersion, chart.APIVersionV1, chart.APIVersionV2))
+	}
+
+	included := make(map[string]struct{}, len(chrt.Dependencies()))
+	for _, dep := range chrt.Dependencies() {
+		included[dep.Name()] = struct{}{}
+	}
+	for _, dep := range chrt.Metadata.Dependencies {
+		if dep == nil {
+			continue
+		}
+		if _, ok := included[dep.Name]; !ok {
+			name := fmt.Sprintf("'%s'", dep.Name)
+			if dep.Alias != "" {
+				name = fmt.Sprintf("'%s' (alias '%s')", dep.Name, dep.Alias)
+			}
+			warnings = append(warnings, fmt.Sprintf("dependency %s of chart '%s' is declared in the chart metadata, but missing from the charts/ directory",
+				name, path))
+		}
+	}
+
+	for _, dep := range chrt.Dependencies() {
+		p, w := checkChartCompatibility(dep, path+"/"+dep.Name())
+		problems = append(problems, p...)
+		warnings = append(warnings, w...)
+	}
+	return problems, warnings
+}
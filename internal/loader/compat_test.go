@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"testing"
+
+	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	newChart := func(name, apiVersion string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{
+				APIVersion: apiVersion,
+				Name:       name,
+				Version:    "0.1.0",
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		chart        func() *chart.Chart
+		wantErr      []string
+		wantWarnings []string
+	}{
+		{
+			name: "compatible chart",
+			chart: func() *chart.Chart {
+				return newChart("app", chart.APIVersionV1)
+			},
+		},
+		{
+			name: "compatible chart with aliased dependency",
+			chart: func() *chart.Chart {
+				c := newChart("app", chart.APIVersionV2)
+				c.Metadata.Dependencies = []*chart.Dependency{{Name: "lib", Alias: "common"}}
+				lib := newChart("lib", chart.APIVersionV2)
+				lib.Metadata.Type = libraryChartType
+				c.AddDependency(lib)
+				return c
+			},
+		},
+		{
+			name: "unsupported apiVersion",
+			chart: func() *chart.Chart {
+				return newChart("app", "v3")
+			},
+			wantErr: []string{"chart 'app' has apiVersion 'v3', which is not supported"},
+		},
+		{
+			name: "library chart",
+			chart: func() *chart.Chart {
+				c := newChart("app", chart.APIVersionV2)
+				c.Metadata.Type = libraryChartType
+				return c
+			},
+			wantErr: []string{"chart 'app' is a library chart"},
+		},
+		{
+			name: "missing dependency",
+			chart: func() *chart.Chart {
+				c := newChart("app", chart.APIVersionV2)
+				c.Metadata.Dependencies = []*chart.Dependency{{Name: "missing", Alias: "other"}}
+				return c
+			},
+			wantWarnings: []string{
+				"dependency 'missing' (alias 'other') of chart 'app' is declared in the chart metadata, but missing from the charts/ directory",
+			},
+		},
+		{
+			name: "missing and incompatible dependencies",
+			chart: func() *chart.Chart {
+				c := newChart("app", chart.APIVersionV2)
+				c.Metadata.Dependencies = []*chart.Dependency{
+					{Name: "missing", Alias: "other"},
+					{Name: "sub"},
+				}
+				sub := newChart("sub", "v3")
+				sub.Metadata.Dependencies = []*chart.Dependency{{Name: "nested"}}
+				c.AddDependency(sub)
+				return c
+			},
+			wantErr: []string{
+				"chart 'app/sub' has apiVersion 'v3'",
+			},
+			wantWarnings: []string{
+				"dependency 'missing' (alias 'other') of chart 'app' is declared in the chart metadata, but missing from the charts/ directory",
+				"dependency 'nested' of chart 'app/sub' is declared in the chart metadata, but missing from the charts/ directory",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			warnings, err := CheckCompatibility(tt.chart())
+			g.Expect(warnings).To(Equal(tt.wantWarnings))
+			if len(tt.wantErr) == 0 {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(ErrIncompatibleChart))
+			for _, want := range tt.wantErr {
+				g.Expect(err.Error()).To(ContainSubstring(want))
+			}
+		})
+	}
+}
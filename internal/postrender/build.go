@@ -17,6 +17,7 @@ limitations under the License.
 package postrender
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/opencontainers/go-digest"
@@ -26,8 +27,13 @@ import (
 )
 
 // BuildPostRenderers creates the post-renderer instances from a HelmRelease
-// and combines them into a single Combined post renderer.
-func BuildPostRenderers(rel *v2.HelmRelease) helmpostrender.PostRenderer {
+// and combines them into a single Combined post renderer. The given context
+// is used by the post-renderers making requests, e.g. SecurityScan.
+//
+// When dryRun is true, the rendered manifests are not applied and are
+// therefore not scanned, to not overwrite the result of the scan of the
+// manifests which are applied.
+func BuildPostRenderers(ctx context.Context, rel *v2.HelmRelease, dryRun bool) helmpostrender.PostRenderer {
 	if rel == nil {
 		return nil
 	}
@@ -40,13 +46,47 @@ func BuildPostRenderers(rel *v2.HelmRelease) helmpostrender.PostRenderer {
 			})
 		}
 	}
+	if m := rel.Spec.CommonMetadata; m != nil && (len(m.Labels) > 0 || len(m.Annotations) > 0) {
+		renderers = append(renderers, NewCommonMetadata(m.Labels, m.Annotations))
+	}
+	if EnforceNamespace {
+		renderers = append(renderers, NewNamespace(rel.GetReleaseNamespace()))
+	}
 	renderers = append(renderers, NewOriginLabels(v2.GroupVersion.Group, rel.Namespace, rel.Name))
+	// Scan the manifests last, as they are applied.
+	if rel.Spec.SecurityScan != nil && !dryRun {
+		renderers = append(renderers, NewSecurityScan(ctx, Scanner, rel))
+	}
 	if len(renderers) == 0 {
 		return nil
 	}
 	return NewCombined(renderers...)
 }
 
+// ObjectDigest returns the digest of the post-renderers of the given
+// HelmRelease, including the CommonMetadata. It returns an empty string when
+// the HelmRelease has neither PostRenderers nor CommonMetadata. When only
+// PostRenderers are defined, the result equals Digest, so that the digest
+// observed for existing HelmReleases remains stable.
+func ObjectDigest(algo digest.Algorithm, rel *v2.HelmRelease) string {
+	if rel.Spec.CommonMetadata == nil {
+		if rel.Spec.PostRenderers == nil {
+			return ""
+		}
+		return Digest(algo, rel.Spec.PostRenderers).String()
+	}
+
+	digester := algo.Digester()
+	enc := json.NewEncoder(digester.Hash())
+	if err := enc.Encode(struct {
+		PostRenderers  []v2.PostRenderer  `json:"postRenderers,omitempty"`
+		CommonMetadata *v2.CommonMetadata `json:"commonMetadata"`
+	}{rel.Spec.PostRenderers, rel.Spec.CommonMetadata}); err != nil {
+		return ""
+	}
+	return digester.Digest().String()
+}
+
 func Digest(algo digest.Algorithm, postrenders []v2.PostRenderer) digest.Digest {
 	digester := algo.Digester()
 	enc := json.NewEncoder(digester.Hash())
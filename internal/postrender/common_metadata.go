@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postrender
+
+import (
+	"bytes"
+
+	"sigs.k8s.io/kustomize/api/builtins"
+	"sigs.k8s.io/kustomize/api/provider"
+	"sigs.k8s.io/kustomize/api/resmap"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+)
+
+// NewCommonMetadata returns a CommonMetadata post-renderer which adds the
+// given labels and annotations to the metadata of all rendered resources.
+func NewCommonMetadata(labels, annotations map[string]string) *CommonMetadata {
+	return &CommonMetadata{
+		labels:      labels,
+		annotations: annotations,
+	}
+}
+
+// CommonMetadata is a Helm post-renderer which adds labels and annotations
+// to the metadata of all rendered resources. Contrary to a Kustomize
+// commonLabels transformation, selectors and templates are left untouched.
+type CommonMetadata struct {
+	labels      map[string]string
+	annotations map[string]string
+}
+
+func (k *CommonMetadata) Run(renderedManifests *bytes.Buffer) (modifiedManifests *bytes.Buffer, err error) {
+	resFactory := provider.NewDefaultDepProvider().GetResourceFactory()
+	resMapFactory := resmap.NewFactory(resFactory)
+
+	resMap, err := resMapFactory.NewResMapFromBytes(renderedManifests.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(k.labels) > 0 {
+		labelTransformer := builtins.LabelTransformerPlugin{
+			Labels: k.labels,
+			FieldSpecs: []kustypes.FieldSpec{
+				{Path: "metadata/labels", CreateIfNotPresent: true},
+			},
+		}
+		if err := labelTransformer.Transform(resMap); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(k.annotations) > 0 {
+		annotationTransformer := builtins.AnnotationsTransformerPlugin{
+			Annotations: k.annotations,
+			FieldSpecs: []kustypes.FieldSpec{
+				{Path: "metadata/annotations", CreateIfNotPresent: true},
+			},
+		}
+		if err := annotationTransformer.Transform(resMap); err != nil {
+			return nil, err
+		}
+	}
+
+	yaml, err := resMap.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(yaml), nil
+}
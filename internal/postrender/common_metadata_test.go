@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postrender
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func Test_CommonMetadata_Run(t *testing.T) {
+	tests := []struct {
+		name              string
+		labels            map[string]string
+		annotations       map[string]string
+		renderedManifests string
+		expectManifests   string
+	}{
+		{
+			name:              "labels and annotations",
+			labels:            map[string]string{"team": "platform", "existing": "override"},
+			annotations:       map[string]string{"cost-center": "1234"},
+			renderedManifests: mixedResourceMock,
+			expectManifests: `apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    cost-center: "1234"
+  labels:
+    existing: override
+    team: platform
+  name: pod-without-labels
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    cost-center: "1234"
+  labels:
+    existing: override
+    team: platform
+  name: service-with-labels
+`,
+		},
+		{
+			name:   "does not modify selectors",
+			labels: map[string]string{"team": "platform"},
+			renderedManifests: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+spec:
+  selector:
+    matchLabels:
+      app: podinfo
+  template:
+    metadata:
+      labels:
+        app: podinfo
+`,
+			expectManifests: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    team: platform
+  name: podinfo
+spec:
+  selector:
+    matchLabels:
+      app: podinfo
+  template:
+    metadata:
+      labels:
+        app: podinfo
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			k := NewCommonMetadata(tt.labels, tt.annotations)
+			gotModifiedManifests, err := k.Run(bytes.NewBufferString(tt.renderedManifests))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(gotModifiedManifests).To(Equal(bytes.NewBufferString(tt.expectManifests)))
+		})
+	}
+}
+
+func Test_ObjectDigest(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &v2.HelmRelease{}
+	g.Expect(ObjectDigest(digest.Canonical, obj)).To(BeEmpty())
+
+	obj.Spec.PostRenderers = []v2.PostRenderer{{Kustomize: &v2.Kustomize{}}}
+	withPostRenderers := ObjectDigest(digest.Canonical, obj)
+	g.Expect(withPostRenderers).To(Equal(Digest(digest.Canonical, obj.Spec.PostRenderers).String()))
+
+	obj.Spec.CommonMetadata = &v2.CommonMetadata{Labels: map[string]string{"team": "platform"}}
+	withCommonMetadata := ObjectDigest(digest.Canonical, obj)
+	g.Expect(withCommonMetadata).ToNot(BeEmpty())
+	g.Expect(withCommonMetadata).ToNot(Equal(withPostRenderers))
+
+	obj.Spec.CommonMetadata.Labels["team"] = "apps"
+	g.Expect(ObjectDigest(digest.Canonical, obj)).ToNot(Equal(withCommonMetadata))
+}
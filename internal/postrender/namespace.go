@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postrender
+
+import (
+	"bytes"
+
+	"sigs.k8s.io/kustomize/api/provider"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// EnforceNamespace can be set at runtime to enforce the release namespace on
+// all rendered resources using the Namespace post-renderer.
+var EnforceNamespace bool
+
+// NewNamespace returns a Namespace post-renderer which enforces the given
+// namespace on the rendered resources.
+func NewNamespace(namespace string) *Namespace {
+	return &Namespace{
+		namespace: namespace,
+	}
+}
+
+// Namespace is a Helm post-renderer which rewrites the namespace of all
+// rendered resources with a namespace set in their metadata to the release
+// namespace. This prevents charts from creating resources in other namespaces
+// by hard-coding the namespace. Resources without a namespace are left
+// untouched, as Helm creates the namespaced ones in the release namespace.
+type Namespace struct {
+	namespace string
+}
+
+func (k *Namespace) Run(renderedManifests *bytes.Buffer) (modifiedManifests *bytes.Buffer, err error) {
+	resFactory := provider.NewDefaultDepProvider().GetResourceFactory()
+	resMapFactory := resmap.NewFactory(resFactory)
+
+	resMap, err := resMapFactory.NewResMapFromBytes(renderedManifests.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range resMap.Resources() {
+		if ns := res.GetNamespace(); ns == "" || ns == k.namespace {
+			continue
+		}
+		if err := res.SetNamespace(k.namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	yaml, err := resMap.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(yaml), nil
+}
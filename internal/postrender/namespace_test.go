@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postrender
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_Namespace_Run(t *testing.T) {
+	g := NewWithT(t)
+
+	renderedManifests := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: without-namespace
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: in-release-namespace
+  namespace: tenant
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: in-other-namespace
+  namespace: kube-system
+`
+	expectManifests := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: without-namespace
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: in-release-namespace
+  namespace: tenant
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: in-other-namespace
+  namespace: tenant
+`
+
+	k := NewNamespace("tenant")
+	gotModifiedManifests, err := k.Run(bytes.NewBufferString(renderedManifests))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gotModifiedManifests).To(Equal(bytes.NewBufferString(expectManifests)))
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postrender
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/features"
+	"github.com/fluxcd/helm-controller/internal/release"
+	"github.com/fluxcd/helm-controller/internal/scan"
+)
+
+// maxReportedFindings is the maximum number of findings listed in the
+// message of the SecurityScan condition.
+const maxReportedFindings = 5
+
+// Scanner can be set at runtime to scan the rendered manifests of the
+// HelmReleases which configure a SecurityScan, using the SecurityScan
+// post-renderer.
+var Scanner *scan.Client
+
+// NewSecurityScan returns a SecurityScan post-renderer which scans the
+// rendered manifests of the given HelmRelease using the given scan.Client.
+// The scan request is canceled when the given context is done.
+func NewSecurityScan(ctx context.Context, client *scan.Client, obj *v2.HelmRelease) *SecurityScan {
+	return &SecurityScan{
+		ctx:    ctx,
+		client: client,
+		obj:    obj,
+	}
+}
+
+// SecurityScan is a Helm post-renderer which submits the rendered manifests,
+// and the container images referenced by them, to a security scanner before
+// they are applied. The result is recorded in the v2.SecurityScanCondition
+// of the HelmRelease. When the scan fails, or the manifests can not be
+// scanned, it returns an error to prevent the release from being applied,
+// unless the HelmRelease configures v2.SecurityScanActionWarn.
+//
+// Unless the HideSecrets feature gate is disabled for the HelmRelease, the
+// Secrets are removed from the manifests submitted to the scanner. It does
+// not modify the rendered manifests.
+type SecurityScan struct {
+	ctx    context.Context
+	client *scan.Client
+	obj    *v2.HelmRelease
+}
+
+func (k *SecurityScan) Run(renderedManifests *bytes.Buffer) (modifiedManifests *bytes.Buffer, err error) {
+	spec := k.obj.Spec.SecurityScan
+	if spec == nil {
+		return renderedManifests, nil
+	}
+
+	var (
+		revision = k.obj.Status.LastAttemptedRevision
+		severity = spec.GetSeverity()
+		block    = spec.GetAction() == v2.SecurityScanActionBlock
+	)
+
+	findings, err := k.scan(renderedManifests.String(), revision, severity)
+	if err != nil {
+		msg := fmt.Sprintf("Security scan of revision %s could not be completed: %s", revision, err)
+		conditions.MarkFalse(k.obj, v2.SecurityScanCondition, v2.SecurityScanErrorReason, "%s", msg)
+		if block {
+			return nil, errors.New(msg)
+		}
+		return renderedManifests, nil
+	}
+
+	if len(findings) > 0 {
+		msg := fmt.Sprintf("Security scan of revision %s reported %d finding(s) of severity %s or above: %s",
+			revision, len(findings), severity, formatFindings(findings))
+		conditions.MarkFalse(k.obj, v2.SecurityScanCondition, v2.SecurityScanFailedReason, "%s", msg)
+		if block {
+			return nil, errors.New(msg)
+		}
+		return renderedManifests, nil
+	}
+
+	conditions.MarkTrue(k.obj, v2.SecurityScanCondition, meta.SucceededReason,
+		"Security scan of revision %s reported no findings of severity %s or above", revision, severity)
+	return renderedManifests, nil
+}
+
+// scan submits the given manifest to the scanner, and returns the findings
+// at or above the given severity.
+func (k *SecurityScan) scan(manifest, revision string, severity v2.SecuritySeverity) ([]scan.Finding, error) {
+	if k.client == nil {
+		return nil, errors.New("no security scanner is configured for the controller")
+	}
+
+	images, err := scan.Images(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if hide, err := features.EnabledFor(k.obj, features.HideSecrets); hide || err != nil {
+		manifest = release.HideSecretsInManifest(manifest)
+	}
+	result, err := k.client.Scan(k.ctx, scan.Request{
+		Name:      k.obj.GetReleaseName(),
+		Namespace: k.obj.GetReleaseNamespace(),
+		Revision:  revision,
+		Manifests: manifest,
+		Images:    images,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.AtOrAbove(severity), nil
+}
+
+// formatFindings returns a comma-separated list of the given findings,
+// truncated to maxReportedFindings.
+func formatFindings(findings []scan.Finding) string {
+	n := min(len(findings), maxReportedFindings)
+	s := make([]string, 0, n)
+	for _, f := range findings[:n] {
+		s = append(s, f.String())
+	}
+	if len(findings) > n {
+		s = append(s, fmt.Sprintf("and %d more", len(findings)-n))
+	}
+	return strings.Join(s, ", ")
+}
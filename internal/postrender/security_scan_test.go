@@ -0,0 +1,235 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postrender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	helmpostrender "github.com/jessesimpson36/helm/v4/pkg/postrender"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/features"
+	"github.com/fluxcd/helm-controller/internal/scan"
+)
+
+const securityScanManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: podinfo
+  namespace: default
+spec:
+  containers:
+    - name: podinfo
+      image: ghcr.io/stefanprodan/podinfo:6.5.0
+`
+
+func TestSecurityScan_Run(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(scan.Result{
+			Findings: []scan.Finding{
+				{ID: "CVE-1", Severity: "MEDIUM", Target: "ghcr.io/stefanprodan/podinfo:6.5.0"},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name       string
+		path       string
+		noScanner  bool
+		spec       v2.SecurityScan
+		wantErr    bool
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "no findings at or above severity",
+			spec:       v2.SecurityScan{Severity: v2.SecuritySeverityHigh},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: meta.SucceededReason,
+		},
+		{
+			name:       "findings block release",
+			spec:       v2.SecurityScan{Severity: v2.SecuritySeverityMedium},
+			wantErr:    true,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: v2.SecurityScanFailedReason,
+		},
+		{
+			name:       "findings warn",
+			spec:       v2.SecurityScan{Severity: v2.SecuritySeverityLow, Action: v2.SecurityScanActionWarn},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: v2.SecurityScanFailedReason,
+		},
+		{
+			name:       "scanner error blocks release",
+			path:       "/error",
+			wantErr:    true,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: v2.SecurityScanErrorReason,
+		},
+		{
+			name:       "no scanner warns",
+			noScanner:  true,
+			spec:       v2.SecurityScan{Action: v2.SecurityScanActionWarn},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: v2.SecurityScanErrorReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+				Spec:       v2.HelmReleaseSpec{SecurityScan: &tt.spec},
+				Status:     v2.HelmReleaseStatus{LastAttemptedRevision: "6.5.0"},
+			}
+			var client *scan.Client
+			if !tt.noScanner {
+				client = &scan.Client{URL: srv.URL + tt.path}
+			}
+
+			in := bytes.NewBufferString(securityScanManifest)
+			out, err := NewSecurityScan(context.TODO(), client, obj).Run(in)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(out).To(BeNil())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(out.String()).To(Equal(securityScanManifest))
+			}
+
+			c := conditions.Get(obj, v2.SecurityScanCondition)
+			g.Expect(c).ToNot(BeNil())
+			g.Expect(c.Status).To(Equal(tt.wantStatus))
+			g.Expect(c.Reason).To(Equal(tt.wantReason))
+			g.Expect(c.Message).To(ContainSubstring("revision 6.5.0"))
+		})
+	}
+}
+
+func TestSecurityScan_Run_hidesSecrets(t *testing.T) {
+	const secret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: podinfo
+  namespace: default
+stringData:
+  password: hunter2
+`
+
+	var received scan.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_ = json.NewEncoder(w).Encode(scan.Result{})
+	}))
+	t.Cleanup(srv.Close)
+
+	for _, hide := range []bool{true, false} {
+		t.Run(fmt.Sprintf("HideSecrets=%t", hide), func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(features.SetConfigured(map[string]bool{features.HideSecrets: hide})).To(Succeed())
+			t.Cleanup(func() { _ = features.SetConfigured(nil) })
+
+			obj := &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+				Spec:       v2.HelmReleaseSpec{SecurityScan: &v2.SecurityScan{}},
+			}
+			manifest := securityScanManifest + "---\n" + secret
+			out, err := NewSecurityScan(context.TODO(), &scan.Client{URL: srv.URL}, obj).Run(bytes.NewBufferString(manifest))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(out.String()).To(Equal(manifest))
+
+			g.Expect(received.Images).To(ConsistOf("ghcr.io/stefanprodan/podinfo:6.5.0"))
+			if hide {
+				g.Expect(received.Manifests).ToNot(ContainSubstring("hunter2"))
+			} else {
+				g.Expect(received.Manifests).To(ContainSubstring("hunter2"))
+			}
+		})
+	}
+}
+
+func TestSecurityScan_Run_canceledContext(t *testing.T) {
+	g := NewWithT(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(scan.Result{})
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+		Spec:       v2.HelmReleaseSpec{SecurityScan: &v2.SecurityScan{}},
+	}
+	_, err := NewSecurityScan(ctx, &scan.Client{URL: srv.URL}, obj).Run(bytes.NewBufferString(securityScanManifest))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring(context.Canceled.Error()))
+	g.Expect(conditions.GetReason(obj, v2.SecurityScanCondition)).To(Equal(v2.SecurityScanErrorReason))
+}
+
+func TestBuildPostRenderers_securityScan(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+		Spec:       v2.HelmReleaseSpec{SecurityScan: &v2.SecurityScan{}},
+	}
+	hasSecurityScan := func(pr helmpostrender.PostRenderer) bool {
+		for _, r := range pr.(*Combined).renderers {
+			if _, ok := r.(*SecurityScan); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	g.Expect(hasSecurityScan(BuildPostRenderers(context.TODO(), obj, false))).To(BeTrue())
+	// The manifests rendered by a dry-run are not applied, and not scanned.
+	g.Expect(hasSecurityScan(BuildPostRenderers(context.TODO(), obj, true))).To(BeFalse())
+}
+
+func Test_formatFindings(t *testing.T) {
+	g := NewWithT(t)
+
+	var findings []scan.Finding
+	for _, id := range []string{"CVE-1", "CVE-2", "CVE-3", "CVE-4", "CVE-5", "CVE-6", "CVE-7"} {
+		findings = append(findings, scan.Finding{ID: id, Severity: v2.SecuritySeverityHigh})
+	}
+	g.Expect(formatFindings(findings[:1])).To(Equal("CVE-1 (HIGH)"))
+	g.Expect(formatFindings(findings)).To(Equal(
+		"CVE-1 (HIGH), CVE-2 (HIGH), CVE-3 (HIGH), CVE-4 (HIGH), CVE-5 (HIGH), and 2 more"))
+}
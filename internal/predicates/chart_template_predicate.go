@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/reconcile"
+)
+
+// ChartTemplateChangedPredicate detects a change to the rendered chart name
+// or version of a HelmRelease with Go template expressions in its chart
+// template, as the label and annotation changes the expressions refer to do
+// not result in a new generation.
+type ChartTemplateChangedPredicate struct {
+	predicate.Funcs
+}
+
+func (ChartTemplateChangedPredicate) Update(e event.UpdateEvent) bool {
+	oldObj, ok := e.ObjectOld.(*v2.HelmRelease)
+	if !ok {
+		return false
+	}
+	newObj, ok := e.ObjectNew.(*v2.HelmRelease)
+	if !ok || !newObj.HasChartTemplate() {
+		return false
+	}
+	spec := newObj.Spec.Chart.Spec
+	if !reconcile.IsChartTemplateExpression(spec.Chart) && !reconcile.IsChartTemplateExpression(spec.Version) {
+		return false
+	}
+
+	oldObj, newObj = oldObj.DeepCopy(), newObj.DeepCopy()
+	oldErr, newErr := reconcile.RenderChartTemplate(oldObj), reconcile.RenderChartTemplate(newObj)
+	if oldErr != nil || newErr != nil {
+		return (oldErr == nil) != (newErr == nil)
+	}
+	return !oldObj.HasChartTemplate() ||
+		oldObj.Spec.Chart.Spec.Chart != newObj.Spec.Chart.Spec.Chart ||
+		oldObj.Spec.Chart.Spec.Version != newObj.Spec.Chart.Spec.Version
+}
+
+func (ChartTemplateChangedPredicate) Create(e event.CreateEvent) bool {
+	return false
+}
+
+func (ChartTemplateChangedPredicate) Delete(e event.DeleteEvent) bool {
+	return false
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestChartTemplateChangedPredicate_Update(t *testing.T) {
+	newRelease := func(chart, version string, labels map[string]string) *v2.HelmRelease {
+		return &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: labels,
+			},
+			Spec: v2.HelmReleaseSpec{
+				Chart: &v2.HelmChartTemplate{
+					Spec: v2.HelmChartTemplateSpec{
+						Chart:   chart,
+						Version: version,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		old  *v2.HelmRelease
+		new  *v2.HelmRelease
+		want bool
+	}{
+		{
+			name: "rendered version changed",
+			old:  newRelease("podinfo", "{{ .Labels.version }}", map[string]string{"version": "6.4.x"}),
+			new:  newRelease("podinfo", "{{ .Labels.version }}", map[string]string{"version": "6.5.x"}),
+			want: true,
+		},
+		{
+			name: "rendered chart name changed",
+			old:  newRelease("{{ .Labels.app }}", "*", map[string]string{"app": "podinfo"}),
+			new:  newRelease("{{ .Labels.app }}", "*", map[string]string{"app": "nginx"}),
+			want: true,
+		},
+		{
+			name: "label referenced by template added",
+			old:  newRelease("podinfo", "{{ .Labels.version }}", nil),
+			new:  newRelease("podinfo", "{{ .Labels.version }}", map[string]string{"version": "6.5.x"}),
+			want: true,
+		},
+		{
+			name: "unrelated label changed",
+			old:  newRelease("podinfo", "{{ .Labels.version }}", map[string]string{"version": "6.5.x"}),
+			new:  newRelease("podinfo", "{{ .Labels.version }}", map[string]string{"version": "6.5.x", "other": "label"}),
+			want: false,
+		},
+		{
+			name: "without template expressions",
+			old:  newRelease("podinfo", "6.5.x", nil),
+			new:  newRelease("podinfo", "6.5.x", map[string]string{"version": "6.5.x"}),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			so := ChartTemplateChangedPredicate{}
+			e := event.UpdateEvent{
+				ObjectOld: tt.old,
+				ObjectNew: tt.new,
+			}
+			g.Expect(so.Update(e)).To(gomega.Equal(tt.want))
+		})
+	}
+}
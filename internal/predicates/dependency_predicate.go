@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// DependencyReadyPredicate detects a v2.HelmRelease becoming ready for its
+// latest generation, or making a new release while ready, which may allow
+// the HelmReleases depending on it to proceed.
+type DependencyReadyPredicate struct {
+	predicate.Funcs
+}
+
+func (DependencyReadyPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+
+	oldObj, ok := e.ObjectOld.(*v2.HelmRelease)
+	if !ok {
+		return false
+	}
+
+	newObj, ok := e.ObjectNew.(*v2.HelmRelease)
+	if !ok {
+		return false
+	}
+
+	if !isReadyForGeneration(newObj) {
+		return false
+	}
+	if !isReadyForGeneration(oldObj) {
+		return true
+	}
+
+	oldLatest, newLatest := oldObj.Status.History.Latest(), newObj.Status.History.Latest()
+	if oldLatest == nil || newLatest == nil {
+		return oldLatest != newLatest
+	}
+	return oldLatest.Version != newLatest.Version
+}
+
+func (DependencyReadyPredicate) Create(e event.CreateEvent) bool {
+	return false
+}
+
+func (DependencyReadyPredicate) Delete(e event.DeleteEvent) bool {
+	return false
+}
+
+// isReadyForGeneration returns true if the given object is ready, and the
+// readiness applies to its latest generation.
+func isReadyForGeneration(obj *v2.HelmRelease) bool {
+	return obj.Status.ObservedGeneration == obj.Generation && conditions.IsReady(obj)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestDependencyReadyPredicate_Update(t *testing.T) {
+	newRelease := func(generation, observedGeneration int64, ready metav1.ConditionStatus, version int) *v2.HelmRelease {
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Generation: generation,
+			},
+			Status: v2.HelmReleaseStatus{
+				ObservedGeneration: observedGeneration,
+				Conditions:         []metav1.Condition{{Type: meta.ReadyCondition, Status: ready}},
+			},
+		}
+		if version > 0 {
+			obj.Status.History = v2.Snapshots{{Version: version}}
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name string
+		old  client.Object
+		new  client.Object
+		want bool
+	}{
+		{
+			name: "old not ready and new ready",
+			old:  newRelease(1, 1, metav1.ConditionFalse, 1),
+			new:  newRelease(1, 1, metav1.ConditionTrue, 1),
+			want: true,
+		},
+		{
+			name: "old ready for previous generation and new ready",
+			old:  newRelease(2, 1, metav1.ConditionTrue, 1),
+			new:  newRelease(2, 2, metav1.ConditionTrue, 1),
+			want: true,
+		},
+		{
+			name: "new ready for previous generation",
+			old:  newRelease(1, 1, metav1.ConditionFalse, 1),
+			new:  newRelease(2, 1, metav1.ConditionTrue, 1),
+			want: false,
+		},
+		{
+			name: "new not ready",
+			old:  newRelease(1, 1, metav1.ConditionTrue, 1),
+			new:  newRelease(1, 1, metav1.ConditionFalse, 2),
+			want: false,
+		},
+		{
+			name: "ready with new release",
+			old:  newRelease(1, 1, metav1.ConditionTrue, 1),
+			new:  newRelease(1, 1, metav1.ConditionTrue, 2),
+			want: true,
+		},
+		{
+			name: "ready with first release",
+			old:  newRelease(1, 1, metav1.ConditionTrue, 0),
+			new:  newRelease(1, 1, metav1.ConditionTrue, 1),
+			want: true,
+		},
+		{
+			name: "ready with same release",
+			old:  newRelease(1, 1, metav1.ConditionTrue, 1),
+			new:  newRelease(1, 1, metav1.ConditionTrue, 1),
+			want: false,
+		},
+		{
+			name: "not a HelmRelease",
+			old:  &unstructured.Unstructured{},
+			new:  newRelease(1, 1, metav1.ConditionTrue, 1),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			so := DependencyReadyPredicate{}
+			e := event.UpdateEvent{
+				ObjectOld: tt.old,
+				ObjectNew: tt.new,
+			}
+			g.Expect(so.Update(e)).To(gomega.Equal(tt.want))
+		})
+	}
+}
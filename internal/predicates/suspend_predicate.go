@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// SuspendAnnotationChangedPredicate detects a change to the value of the
+// v2.SuspendAnnotation of an object, as annotation changes do not result in
+// a new generation.
+type SuspendAnnotationChangedPredicate struct {
+	predicate.Funcs
+}
+
+func (SuspendAnnotationChangedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+	return e.ObjectOld.GetAnnotations()[v2.SuspendAnnotation] != e.ObjectNew.GetAnnotations()[v2.SuspendAnnotation]
+}
+
+func (SuspendAnnotationChangedPredicate) Create(e event.CreateEvent) bool {
+	return false
+}
+
+func (SuspendAnnotationChangedPredicate) Delete(e event.DeleteEvent) bool {
+	return false
+}
+
+// SuspendedPredicate filters out events for suspended HelmReleases, to
+// exclude them from the work queue while they are suspended. Events which
+// change the suspension of the object, mark it for deletion, or request a
+// reconciliation are let through.
+type SuspendedPredicate struct {
+	predicate.Funcs
+}
+
+func (SuspendedPredicate) Create(e event.CreateEvent) bool {
+	if !isSuspended(e.Object) {
+		return true
+	}
+	// Allow a suspended object to be reconciled once to record its
+	// suspension, e.g. when it is created in a suspended state.
+	getter, ok := e.Object.(conditions.Getter)
+	return !ok || !conditions.IsTrue(getter, v2.SuspendedCondition)
+}
+
+func (SuspendedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+	if !isSuspended(e.ObjectNew) || isSuspended(e.ObjectOld) != isSuspended(e.ObjectNew) {
+		return true
+	}
+	if !e.ObjectNew.GetDeletionTimestamp().IsZero() {
+		return true
+	}
+	return e.ObjectOld.GetAnnotations()[meta.ReconcileRequestAnnotation] != e.ObjectNew.GetAnnotations()[meta.ReconcileRequestAnnotation]
+}
+
+func (SuspendedPredicate) Delete(e event.DeleteEvent) bool {
+	return true
+}
+
+func (SuspendedPredicate) Generic(e event.GenericEvent) bool {
+	return !isSuspended(e.Object)
+}
+
+// isSuspended returns true if the given object is a suspended HelmRelease.
+func isSuspended(obj client.Object) bool {
+	hr, ok := obj.(*v2.HelmRelease)
+	return ok && hr.IsSuspended()
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestSuspendAnnotationChangedPredicate_Update(t *testing.T) {
+	newRelease := func(value string) *v2.HelmRelease {
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{"other": "annotation"},
+			},
+		}
+		if value != "" {
+			obj.Annotations[v2.SuspendAnnotation] = value
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name string
+		old  *v2.HelmRelease
+		new  *v2.HelmRelease
+		want bool
+	}{
+		{
+			name: "annotation added",
+			old:  newRelease(""),
+			new:  newRelease(v2.SuspendAnnotationAll),
+			want: true,
+		},
+		{
+			name: "annotation removed",
+			old:  newRelease(v2.SuspendAnnotationRemediation),
+			new:  newRelease(""),
+			want: true,
+		},
+		{
+			name: "annotation value changed",
+			old:  newRelease(v2.SuspendAnnotationAll),
+			new:  newRelease(v2.SuspendAnnotationRemediation),
+			want: true,
+		},
+		{
+			name: "annotation unchanged",
+			old:  newRelease(v2.SuspendAnnotationAll),
+			new:  newRelease(v2.SuspendAnnotationAll),
+			want: false,
+		},
+		{
+			name: "no annotation",
+			old:  newRelease(""),
+			new:  newRelease(""),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			so := SuspendAnnotationChangedPredicate{}
+			e := event.UpdateEvent{
+				ObjectOld: tt.old,
+				ObjectNew: tt.new,
+			}
+			g.Expect(so.Update(e)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestSuspendedPredicate_Create(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	so := SuspendedPredicate{}
+	g.Expect(so.Create(event.CreateEvent{Object: &v2.HelmRelease{}})).To(gomega.BeTrue())
+
+	suspended := &v2.HelmRelease{Spec: v2.HelmReleaseSpec{Suspend: true}}
+	g.Expect(so.Create(event.CreateEvent{Object: suspended})).To(gomega.BeTrue())
+
+	conditions.MarkTrue(suspended, v2.SuspendedCondition, meta.SuspendedReason, "suspended")
+	g.Expect(so.Create(event.CreateEvent{Object: suspended})).To(gomega.BeFalse())
+}
+
+func TestSuspendedPredicate_Update(t *testing.T) {
+	newRelease := func(suspend bool, mutate ...func(obj *v2.HelmRelease)) *v2.HelmRelease {
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{},
+			},
+			Spec: v2.HelmReleaseSpec{Suspend: suspend},
+		}
+		for _, m := range mutate {
+			m(obj)
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name string
+		old  *v2.HelmRelease
+		new  *v2.HelmRelease
+		want bool
+	}{
+		{
+			name: "not suspended",
+			old:  newRelease(false),
+			new:  newRelease(false),
+			want: true,
+		},
+		{
+			name: "suspended",
+			old:  newRelease(false),
+			new:  newRelease(true),
+			want: true,
+		},
+		{
+			name: "resumed",
+			old:  newRelease(true),
+			new:  newRelease(false),
+			want: true,
+		},
+		{
+			name: "suspended using annotation",
+			old:  newRelease(false),
+			new: newRelease(false, func(obj *v2.HelmRelease) {
+				obj.Annotations[v2.SuspendAnnotation] = v2.SuspendAnnotationAll
+			}),
+			want: true,
+		},
+		{
+			name: "remains suspended",
+			old:  newRelease(true),
+			new: newRelease(true, func(obj *v2.HelmRelease) {
+				obj.Generation = 2
+			}),
+			want: false,
+		},
+		{
+			name: "reconcile requested while suspended",
+			old:  newRelease(true),
+			new: newRelease(true, func(obj *v2.HelmRelease) {
+				obj.Annotations[meta.ReconcileRequestAnnotation] = "now"
+			}),
+			want: true,
+		},
+		{
+			name: "deleted while suspended",
+			old:  newRelease(true),
+			new: newRelease(true, func(obj *v2.HelmRelease) {
+				now := metav1.Now()
+				obj.DeletionTimestamp = &now
+			}),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			so := SuspendedPredicate{}
+			e := event.UpdateEvent{
+				ObjectOld: tt.old,
+				ObjectNew: tt.new,
+			}
+			g.Expect(so.Update(e)).To(gomega.Equal(tt.want))
+		})
+	}
+}
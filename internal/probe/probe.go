@@ -0,0 +1,249 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package probe runs the post-deploy checks of a HelmRelease against the
+// endpoints of the release.
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apierrutil "k8s.io/apimachinery/pkg/util/errors"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+const (
+	// DefaultCheckTimeout is the timeout of a single check attempt when the
+	// check does not configure one.
+	DefaultCheckTimeout = 5 * time.Second
+
+	// RetryInterval is the interval at which failing checks are retried.
+	RetryInterval = 2 * time.Second
+)
+
+// ErrNotAllowed is returned when the target of a check is not allowed by
+// the Allowlist.
+var ErrNotAllowed = errors.New("target is not allowed by the post-deploy check allowlist")
+
+// Allowlist holds the hosts and networks the checks are allowed to connect
+// to. As the checks are made from within the controller, this prevents them
+// from being used to reach endpoints the authors of a HelmRelease should
+// not have access to, e.g. the metadata service of a cloud provider.
+// A nil Allowlist allows no targets.
+type Allowlist struct {
+	hosts    []string
+	networks []*net.IPNet
+}
+
+// ParseAllowlist returns an Allowlist for the given entries, which are
+// either a host name (e.g. 'podinfo.default.svc.cluster.local'), a wildcard
+// host name matching any subdomain (e.g. '*.svc.cluster.local'), an IP
+// address or a CIDR (e.g. '10.0.0.0/8'). It returns nil if no entries are
+// given.
+func ParseAllowlist(entries []string) (*Allowlist, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	a := &Allowlist{}
+	for _, e := range entries {
+		e = strings.ToLower(strings.TrimSpace(e))
+		switch {
+		case e == "":
+			continue
+		case strings.Contains(e, "/"):
+			_, network, err := net.ParseCIDR(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowlist entry '%s': %w", e, err)
+			}
+			a.networks = append(a.networks, network)
+		case net.ParseIP(e) != nil:
+			ip := net.ParseIP(e)
+			bits := 8 * len(ip.To4())
+			if bits == 0 {
+				bits = 8 * net.IPv6len
+			}
+			a.networks = append(a.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		default:
+			a.hosts = append(a.hosts, strings.TrimSuffix(e, "."))
+		}
+	}
+	return a, nil
+}
+
+// allowsHost returns true if the given host name matches one of the hosts
+// of the Allowlist.
+func (a *Allowlist) allowsHost(host string) bool {
+	if a == nil {
+		return false
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, h := range a.hosts {
+		if suffix, ok := strings.CutPrefix(h, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+			continue
+		}
+		if host == h {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsIP returns true if the given IP address is within one of the
+// networks of the Allowlist.
+func (a *Allowlist) allowsIP(ip net.IP) bool {
+	if a == nil || ip == nil {
+		return false
+	}
+	for _, n := range a.networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// dialContext dials the given address if its host is allowed by name, or
+// if the IP address the host resolves to is allowed. The IP address is
+// verified after resolving it, so that a host name can not be used to
+// reach a network which is not allowed.
+func (a *Allowlist) dialContext(ctx context.Context, d *net.Dialer, network, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if !a.allowsHost(host) {
+		d.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !a.allowsIP(net.ParseIP(host)) {
+				return fmt.Errorf("%w: %s", ErrNotAllowed, host)
+			}
+			return nil
+		}
+	}
+	return d.DialContext(ctx, network, address)
+}
+
+// Run runs all the given checks once, and returns the aggregated errors of
+// the checks which failed. Each check is bounded by its own timeout, and
+// only connects to targets allowed by the given Allowlist.
+func Run(ctx context.Context, checks v2.PostDeployChecks, allowlist *Allowlist) error {
+	var errs []error
+	for _, c := range checks.HTTP {
+		if err := checkHTTP(ctx, c, allowlist); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, c := range checks.TCP {
+		if err := checkTCP(ctx, c, allowlist); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return apierrutil.NewAggregate(errs)
+}
+
+// IsNotAllowed returns true if the given error, or any of the aggregated
+// errors, is caused by a target which is not allowed.
+func IsNotAllowed(err error) bool {
+	var agg apierrutil.Aggregate
+	if errors.As(err, &agg) {
+		for _, err := range agg.Errors() {
+			if errors.Is(err, ErrNotAllowed) {
+				return true
+			}
+		}
+		return false
+	}
+	return errors.Is(err, ErrNotAllowed)
+}
+
+// checkHTTP makes a GET request to the URL of the given check, and confirms
+// the response has the expected status code.
+// Redirects are not followed, as they could point to a target which is not
+// allowed, and are subject to the expected status code instead.
+func checkHTTP(ctx context.Context, c v2.HTTPCheck, allowlist *Allowlist) error {
+	timeout := timeoutOrDefault(c.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return fmt.Errorf("HTTP check '%s' failed: %w", c.URL, err)
+	}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return allowlist.dialContext(ctx, &net.Dialer{Timeout: timeout}, network, address)
+		},
+		DisableKeepAlives: true,
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP check '%s' failed: %w", c.URL, err)
+	}
+	_ = resp.Body.Close()
+
+	if c.ExpectedStatus != 0 {
+		if resp.StatusCode != c.ExpectedStatus {
+			return fmt.Errorf("HTTP check '%s' failed: expected status %d, got %d", c.URL, c.ExpectedStatus, resp.StatusCode)
+		}
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP check '%s' failed: expected a 2xx status, got %d", c.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// checkTCP confirms a TCP connection can be established to the address of
+// the given check.
+func checkTCP(ctx context.Context, c v2.TCPCheck, allowlist *Allowlist) error {
+	conn, err := allowlist.dialContext(ctx, &net.Dialer{Timeout: timeoutOrDefault(c.Timeout)}, "tcp", c.Address)
+	if err != nil {
+		return fmt.Errorf("TCP check '%s' failed: %w", c.Address, err)
+	}
+	_ = conn.Close()
+	return nil
+}
+
+// timeoutOrDefault returns the duration of the given timeout, or
+// DefaultCheckTimeout when it is not set.
+func timeoutOrDefault(timeout *metav1.Duration) time.Duration {
+	if timeout == nil || timeout.Duration <= 0 {
+		return DefaultCheckTimeout
+	}
+	return timeout.Duration
+}
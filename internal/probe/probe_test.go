@@ -0,0 +1,201 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/teapot":
+			w.WriteHeader(http.StatusTeapot)
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	// Reserve an address nothing listens on.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closedAddr := closed.Addr().String()
+	_ = closed.Close()
+
+	loopback, err := ParseAllowlist([]string{"127.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("Failed to parse allowlist: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		checks      v2.PostDeployChecks
+		allowlist   *Allowlist
+		noAllowlist bool
+		wantErr     string
+	}{
+		{
+			name: "passing checks",
+			checks: v2.PostDeployChecks{
+				HTTP: []v2.HTTPCheck{
+					{URL: srv.URL + "/ok"},
+					{URL: srv.URL + "/teapot", ExpectedStatus: http.StatusTeapot},
+				},
+				TCP: []v2.TCPCheck{
+					{Address: l.Addr().String()},
+				},
+			},
+		},
+		{
+			name: "unexpected 2xx status",
+			checks: v2.PostDeployChecks{
+				HTTP: []v2.HTTPCheck{
+					{URL: srv.URL + "/ok", ExpectedStatus: http.StatusNoContent},
+				},
+			},
+			wantErr: "expected status 204, got 200",
+		},
+		{
+			name: "non-2xx status",
+			checks: v2.PostDeployChecks{
+				HTTP: []v2.HTTPCheck{
+					{URL: srv.URL + "/unavailable"},
+				},
+			},
+			wantErr: "expected a 2xx status, got 503",
+		},
+		{
+			name: "refused TCP connection",
+			checks: v2.PostDeployChecks{
+				TCP: []v2.TCPCheck{
+					{Address: closedAddr, Timeout: &metav1.Duration{Duration: time.Second}},
+				},
+			},
+			wantErr: "TCP check '" + closedAddr + "' failed",
+		},
+		{
+			name: "does not follow redirects",
+			checks: v2.PostDeployChecks{
+				HTTP: []v2.HTTPCheck{
+					{URL: srv.URL + "/redirect"},
+				},
+			},
+			wantErr: "expected a 2xx status, got 302",
+		},
+		{
+			name: "target not allowed",
+			checks: v2.PostDeployChecks{
+				HTTP: []v2.HTTPCheck{
+					{URL: srv.URL + "/ok"},
+				},
+				TCP: []v2.TCPCheck{
+					{Address: l.Addr().String()},
+				},
+			},
+			allowlist: &Allowlist{},
+			wantErr:   ErrNotAllowed.Error(),
+		},
+		{
+			name: "no allowlist",
+			checks: v2.PostDeployChecks{
+				TCP: []v2.TCPCheck{
+					{Address: l.Addr().String()},
+				},
+			},
+			noAllowlist: true,
+			wantErr:     ErrNotAllowed.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			allowlist := loopback
+			if tt.allowlist != nil || tt.noAllowlist {
+				allowlist = tt.allowlist
+			}
+
+			err := Run(context.TODO(), tt.checks, allowlist)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestParseAllowlist(t *testing.T) {
+	g := NewWithT(t)
+
+	a, err := ParseAllowlist([]string{"podinfo.default.svc", "*.example.com", "10.0.0.0/8", "192.168.1.1", "::1"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(a.allowsHost("podinfo.default.svc")).To(BeTrue())
+	g.Expect(a.allowsHost("PODINFO.default.svc.")).To(BeTrue())
+	g.Expect(a.allowsHost("other.default.svc")).To(BeFalse())
+	g.Expect(a.allowsHost("api.example.com")).To(BeTrue())
+	g.Expect(a.allowsHost("example.com")).To(BeFalse())
+	g.Expect(a.allowsHost("api.example.com.evil.io")).To(BeFalse())
+
+	g.Expect(a.allowsIP(net.ParseIP("10.1.2.3"))).To(BeTrue())
+	g.Expect(a.allowsIP(net.ParseIP("192.168.1.1"))).To(BeTrue())
+	g.Expect(a.allowsIP(net.ParseIP("192.168.1.2"))).To(BeFalse())
+	g.Expect(a.allowsIP(net.ParseIP("::1"))).To(BeTrue())
+	g.Expect(a.allowsIP(net.ParseIP("169.254.169.254"))).To(BeFalse())
+
+	_, err = ParseAllowlist([]string{"10.0.0.0/33"})
+	g.Expect(err).To(HaveOccurred())
+
+	a, err = ParseAllowlist(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(a).To(BeNil())
+	g.Expect(a.allowsHost("localhost")).To(BeFalse())
+}
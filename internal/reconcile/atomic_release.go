@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/jessesimpson36/helm/v4/pkg/kube"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
@@ -40,7 +41,11 @@ import (
 	"github.com/fluxcd/helm-controller/internal/diff"
 	"github.com/fluxcd/helm-controller/internal/digest"
 	interrors "github.com/fluxcd/helm-controller/internal/errors"
+	"github.com/fluxcd/helm-controller/internal/features"
+	"github.com/fluxcd/helm-controller/internal/inflight"
 	"github.com/fluxcd/helm-controller/internal/postrender"
+	"github.com/fluxcd/helm-controller/internal/release"
+	"github.com/fluxcd/helm-controller/internal/tracing"
 )
 
 // OwnedConditions is a list of Condition types owned by the HelmRelease object.
@@ -48,6 +53,15 @@ var OwnedConditions = []string{
 	v2.ReleasedCondition,
 	v2.RemediatedCondition,
 	v2.TestSuccessCondition,
+	v2.DeprecatedAPIsCondition,
+	v2.HealthCheckIncompleteCondition,
+	v2.SuspendedCondition,
+	v2.SourceVerifiedFailedCondition,
+	v2.ResumePendingCondition,
+	v2.PostDeployChecksPassedCondition,
+	v2.SourceDriftCondition,
+	v2.SecurityScanCondition,
+	meta.HealthyCondition,
 	meta.ReconcilingCondition,
 	meta.ReadyCondition,
 	meta.StalledCondition,
@@ -65,6 +79,16 @@ var (
 	// ErrMissingRollbackTarget is returned when the rollback target is missing.
 	ErrMissingRollbackTarget = errors.New("missing target release for rollback")
 
+	// ErrRemediationExhausted is returned when the rollback remediation
+	// failed the maximum number of consecutive times, and this has not been
+	// acknowledged.
+	ErrRemediationExhausted = errors.New("remediation exhausted")
+
+	// ErrRemediationSuspended is returned when a failed release must be
+	// remediated, but the remediation is suspended using the
+	// v2.SuspendAnnotation.
+	ErrRemediationSuspended = errors.New("remediation suspended")
+
 	// ErrUnknownReleaseStatus is returned when the release status is unknown
 	// and cannot be acted upon.
 	ErrUnknownReleaseStatus = errors.New("unknown release status")
@@ -165,6 +189,20 @@ func (r *AtomicRelease) Reconcile(ctx context.Context, req *Request) error {
 		previous ReconcilerTypeSet
 		next     ActionReconciler
 	)
+
+	// Recover from an action which was interrupted during an earlier
+	// reconciliation, before determining the current state.
+	if err := r.recoverInterruptedAction(ctx, req); err != nil {
+		conditions.MarkFalse(req.Object, meta.ReadyCondition, "StateError", "Could not recover interrupted action: %s", err)
+		return err
+	}
+
+	// Stop releasing and remediating if the rollback remediation failed the
+	// maximum number of consecutive times, until this is acknowledged.
+	if r.remediationExhausted(req) {
+		return r.markRemediationExhausted(req)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -201,6 +239,13 @@ func (r *AtomicRelease) Reconcile(ctx context.Context, req *Request) error {
 					conditions.MarkStalled(req.Object, "MissingRollbackTarget", "Failed to perform remediation: %s", err)
 					return err
 				}
+				if errors.Is(err, ErrRemediationSuspended) {
+					// Leave the failed release as is until the remediation
+					// is resumed, or the configuration changes.
+					log.Info(err.Error())
+					conditions.Delete(req.Object, meta.ReconcilingCondition)
+					return nil
+				}
 				return err
 			}
 
@@ -214,11 +259,9 @@ func (r *AtomicRelease) Reconcile(ctx context.Context, req *Request) error {
 
 				// remove stale post-renderers digest on successful reconciliation.
 				if conditions.IsReady(req.Object) {
-					req.Object.Status.ObservedPostRenderersDigest = ""
-					if req.Object.Spec.PostRenderers != nil {
-						// Update the post-renderers digest if the post-renderers exist.
-						req.Object.Status.ObservedPostRenderersDigest = postrender.Digest(digest.Canonical, req.Object.Spec.PostRenderers).String()
-					}
+					// Update the post-renderers digest if the post-renderers
+					// or common metadata exist.
+					req.Object.Status.ObservedPostRenderersDigest = postrender.ObjectDigest(digest.Canonical, req.Object)
 				}
 
 				return nil
@@ -239,31 +282,7 @@ func (r *AtomicRelease) Reconcile(ctx context.Context, req *Request) error {
 				return nil
 			}
 
-			// Mark the release as reconciling before we attempt to run the action.
-			// This to show continuous progress, as Helm actions can be long-running.
-			reconcilingMsg := fmt.Sprintf("Running '%s' action with timeout of %s",
-				next.Name(), timeoutForAction(next, req.Object).String())
-			conditions.MarkReconciling(req.Object, meta.ProgressingReason, "%s", reconcilingMsg)
-
-			// If the next action is a release action, we can mark the release
-			// as progressing in terms of readiness as well. Doing this for any
-			// other action type is not useful, as it would potentially
-			// overwrite more important failure state from an earlier action.
-			if next.Type() == ReconcilerTypeRelease {
-				conditions.MarkUnknown(req.Object, meta.ReadyCondition, meta.ProgressingReason, "%s", reconcilingMsg)
-			}
-
-			// Patch the object to reflect the new condition.
-			if err = r.patchHelper.Patch(ctx, req.Object, patch.WithOwnedConditions{Conditions: OwnedConditions}, patch.WithFieldOwner(r.fieldManager)); err != nil {
-				return err
-			}
-
-			// Run the action sub-reconciler.
-			log.Info(fmt.Sprintf("running '%s' action with timeout of %s", next.Name(), timeoutForAction(next, req.Object).String()))
-			if err = next.Reconcile(ctx, req); err != nil {
-				if conditions.IsReady(req.Object) {
-					conditions.MarkFalse(req.Object, meta.ReadyCondition, "ReconcileError", "%s", err)
-				}
+			if err = r.runAction(ctx, req, next); err != nil {
 				return err
 			}
 
@@ -273,34 +292,63 @@ func (r *AtomicRelease) Reconcile(ctx context.Context, req *Request) error {
 					"instructed to stop after running %s action reconciler %s", next.Type(), next.Name()),
 				)
 
-				remediation := req.Object.GetActiveRemediation()
-				if remediation == nil || !remediation.RetriesExhausted(req.Object) {
-					conditions.MarkReconciling(req.Object, meta.ProgressingWithRetryReason, "%s", conditions.GetMessage(req.Object, meta.ReadyCondition))
-					return ErrMustRequeue
-				}
-				// Check if retries have exhausted after remediation for early
-				// stall condition detection.
-				if remediation != nil && remediation.RetriesExhausted(req.Object) {
-					conditions.MarkStalled(req.Object, "RetriesExceeded", "Failed to %s after %d attempt(s)",
-						req.Object.Status.LastAttemptedReleaseAction, req.Object.GetActiveRemediation().GetFailureCount(req.Object))
-					return ErrExceededMaxRetries
-				}
-
-				conditions.Delete(req.Object, meta.ReconcilingCondition)
-				return nil
+				return r.stopAfterAction(req)
 			}
 
 			// Append the type to the set of action types we have performed.
 			previous = append(previous, next.Type())
 
 			// Patch the release to reflect progress.
-			if err = r.patchHelper.Patch(ctx, req.Object, patch.WithOwnedConditions{Conditions: OwnedConditions}, patch.WithFieldOwner(r.fieldManager)); err != nil {
+			if err = PatchWithRetry(ctx, r.patchHelper, req.Object, patch.WithOwnedConditions{Conditions: OwnedConditions}, patch.WithFieldOwner(r.fieldManager)); err != nil {
 				return err
 			}
 		}
 	}
 }
 
+const (
+	// remediationAcknowledgedReason is the event reason for acknowledged
+	// rollback remediation failures.
+	remediationAcknowledgedReason = "RemediationAcknowledged"
+
+	// fmtRemediationExhausted is the message format for exhausted rollback
+	// remediation.
+	fmtRemediationExhausted = "Helm rollback failed %d consecutive time(s): set the '%s' annotation to acknowledge and retry"
+)
+
+// remediationExhausted returns true if the rollback remediation failed the
+// maximum number of consecutive times. If the failures are acknowledged using
+// the v2.AcknowledgeRemediationRequestAnnotation, the rollback failures and
+// the other failure counts are reset so that releasing is attempted again.
+func (r *AtomicRelease) remediationExhausted(req *Request) bool {
+	if v2.ShouldHandleAcknowledgeRemediationRequest(req.Object) && req.Object.Status.RollbackFailures > 0 {
+		r.eventRecorder.Eventf(req.Object, corev1.EventTypeNormal, remediationAcknowledgedReason,
+			"Acknowledged %d rollback failure(s): resuming release", req.Object.Status.RollbackFailures)
+		req.Object.Status.RollbackFailures = 0
+		req.Object.Status.ClearFailures()
+	}
+	return rollbackFailuresExhausted(req.Object)
+}
+
+// rollbackFailuresExhausted returns true if the rollback remediation of the
+// upgrade of the given object failed the maximum number of consecutive times.
+func rollbackFailuresExhausted(obj *v2.HelmRelease) bool {
+	remediation := obj.GetUpgrade().Remediation
+	return remediation != nil && remediation.RollbackFailuresExhausted(obj)
+}
+
+// markRemediationExhausted marks the Request.Object as Stalled with
+// v2.RemediationExhaustedReason, and returns ErrRemediationExhausted.
+func (r *AtomicRelease) markRemediationExhausted(req *Request) error {
+	msg := fmt.Sprintf(fmtRemediationExhausted, req.Object.Status.RollbackFailures,
+		v2.AcknowledgeRemediationRequestAnnotation)
+	conditions.Delete(req.Object, meta.ReconcilingCondition)
+	conditions.MarkStalled(req.Object, v2.RemediationExhaustedReason, "%s", msg)
+	conditions.MarkFalse(req.Object, meta.ReadyCondition, v2.RemediationExhaustedReason, "%s", msg)
+	r.eventRecorder.Eventf(req.Object, corev1.EventTypeWarning, v2.RemediationExhaustedReason, "%s", msg)
+	return ErrRemediationExhausted
+}
+
 // actionForState determines the next action to run based on the current state.
 func (r *AtomicRelease) actionForState(ctx context.Context, req *Request, state ReleaseState) (ActionReconciler, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -389,7 +437,7 @@ func (r *AtomicRelease) actionForState(ctx context.Context, req *Request, state
 					"resource", diff.ResourceName(change.DesiredObject))
 			case jsondiff.DiffTypeUpdate:
 				patch := change.Patch
-				if change.DesiredObject.GetObjectKind().GroupVersionKind().Kind == "Secret" {
+				if change.DesiredObject.GetObjectKind().GroupVersionKind().Kind == "Secret" && HideSecrets(req.Object) {
 					patch = jsondiff.MaskSecretPatchData(change.Patch)
 				}
 				log.V(logger.DebugLevel).Info("resource modified",
@@ -428,73 +476,206 @@ func (r *AtomicRelease) actionForState(ctx context.Context, req *Request, state
 		return NewTest(r.configFactory, r.eventRecorder), nil
 	case ReleaseStatusFailed:
 		log.Info(msgWithReason("release is in a failed state", state.Reason))
+		return r.remediationForFailure(ctx, req, forceRequested)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownReleaseStatus, state.Status)
+	}
+}
 
-		remediation := req.Object.GetActiveRemediation()
+// Remediate runs the remediation of the active remediation strategy of the
+// Request.Object for a release which failed after the Helm action itself
+// succeeded, e.g. because it became unhealthy within its verification
+// window. The failure is expected to be recorded in the failure counts of
+// the object by the caller.
+//
+// It applies the same retry, suspension and rollback target checks as for a
+// release which failed during the Helm action, and stops after running the
+// remediation. When the remediation strategy instructs to attempt the
+// release again instead, no action is run, and the release is upgraded by
+// the next Reconcile once the desired state no longer matches.
+func (r *AtomicRelease) Remediate(ctx context.Context, req *Request) error {
+	log := ctrl.LoggerFrom(ctx).V(logger.InfoLevel)
 
-		// If there is no active remediation strategy, we can only attempt to
-		// upgrade the release to see if that fixes the problem.
-		if remediation == nil {
-			log.V(logger.DebugLevel).Info("no active remediation strategy")
-			return NewUpgrade(r.configFactory, r.eventRecorder), nil
-		}
+	if r.remediationExhausted(req) {
+		return r.markRemediationExhausted(req)
+	}
 
-		// If there is no failure count, the conditions under which the failure
-		// occurred must have changed.
-		// Attempt to upgrade the release to see if the problem is resolved.
-		// This ensures that after a configuration change, the release is
-		// attempted again.
-		if remediation.GetFailureCount(req.Object) <= 0 {
-			log.Info("release conditions have changed since last failure")
-			return NewUpgrade(r.configFactory, r.eventRecorder), nil
+	next, err := r.remediationForFailure(ctx, req, false)
+	if err != nil {
+		if errors.Is(err, ErrExceededMaxRetries) {
+			conditions.MarkStalled(req.Object, "RetriesExceeded", "Failed to %s after %d attempt(s)",
+				req.Object.Status.LastAttemptedReleaseAction, req.Object.GetActiveRemediation().GetFailureCount(req.Object))
+			return err
 		}
-
-		// If the force annotation is set, we can attempt to upgrade the release
-		// without any further checks.
-		if forceRequested {
-			log.Info(msgWithReason("forcing upgrade for failed release", "force requested through annotation"))
-			return NewUpgrade(r.configFactory, r.eventRecorder), nil
+		if errors.Is(err, ErrMissingRollbackTarget) {
+			conditions.MarkStalled(req.Object, "MissingRollbackTarget", "Failed to perform remediation: %s", err)
+			return err
 		}
+		if errors.Is(err, ErrRemediationSuspended) {
+			log.Info(err.Error())
+			conditions.Delete(req.Object, meta.ReconcilingCondition)
+			return nil
+		}
+		return err
+	}
+	if next == nil || next.Type() != ReconcilerTypeRemediate {
+		return nil
+	}
 
-		// We have exhausted the number of retries for the remediation
-		// strategy.
-		if remediation.RetriesExhausted(req.Object) && !remediation.MustRemediateLastFailure() {
-			return nil, fmt.Errorf("%w: cannot remediate failed release", ErrExceededMaxRetries)
+	if err = r.runAction(ctx, req, next); err != nil {
+		return err
+	}
+	return r.stopAfterAction(req)
+}
+
+// runAction runs the given action for the Request.Object, after marking the
+// object as reconciling and recording the action as in-flight in the
+// status.
+func (r *AtomicRelease) runAction(ctx context.Context, req *Request, next ActionReconciler) error {
+	log := ctrl.LoggerFrom(ctx).V(logger.InfoLevel)
+
+	// Mark the release as reconciling before we attempt to run the action.
+	// This to show continuous progress, as Helm actions can be long-running.
+	reconcilingMsg := fmt.Sprintf("Running '%s' action with timeout of %s",
+		next.Name(), timeoutForAction(next, req.Object).String())
+	conditions.MarkReconciling(req.Object, meta.ProgressingReason, "%s", reconcilingMsg)
+
+	// If the next action is a release action, we can mark the release
+	// as progressing in terms of readiness as well. Doing this for any
+	// other action type is not useful, as it would potentially
+	// overwrite more important failure state from an earlier action.
+	if next.Type() == ReconcilerTypeRelease {
+		conditions.MarkUnknown(req.Object, meta.ReadyCondition, meta.ProgressingReason, "%s", reconcilingMsg)
+	}
+
+	// Record the action in the status before running it, so that it
+	// can be recovered from if it is interrupted.
+	req.Object.Status.InFlightAction = newInFlightAction(req.Object, next.Name())
+
+	// Patch the object to reflect the new condition.
+	if err := PatchWithRetry(ctx, r.patchHelper, req.Object, patch.WithOwnedConditions{Conditions: OwnedConditions}, patch.WithFieldOwner(r.fieldManager)); err != nil {
+		return err
+	}
+
+	// Run the action sub-reconciler.
+	log.Info(fmt.Sprintf("running '%s' action with timeout of %s", next.Name(), timeoutForAction(next, req.Object).String()))
+	inflight.SetPhase(ctx, next.Name())
+	actionCtx, span := tracing.StartSpan(ctx, next.Name(), attribute.String("helm.action.type", string(next.Type())))
+	err := next.Reconcile(actionCtx, req)
+	tracing.EndSpan(span, err, SecretRedactor(req.Object))
+	req.Object.Status.InFlightAction = nil
+	if err != nil {
+		if conditions.IsReady(req.Object) {
+			conditions.MarkFalse(req.Object, meta.ReadyCondition, "ReconcileError", "%s", err)
 		}
+		return err
+	}
+	return nil
+}
 
-		// Reset the history up to the point where the failure occurred.
-		// This ensures we do not accumulate a long history of failures.
-		req.Object.Status.History.Truncate(remediation.MustIgnoreTestFailures(req.Object.GetTest().IgnoreFailures))
-
-		switch remediation.GetStrategy() {
-		case v2.RollbackRemediationStrategy:
-			// Verify the previous release is still in storage and unmodified
-			// before instructing to roll back to it.
-			prev := req.Object.Status.History.Previous(remediation.MustIgnoreTestFailures(req.Object.GetTest().IgnoreFailures))
-			if _, err := action.VerifySnapshot(r.configFactory.Build(nil), prev); err != nil {
-				if errors.Is(err, action.ErrReleaseNotFound) {
-					// If the rollback target is missing, we cannot roll back
-					// to it and must fail.
-					return nil, fmt.Errorf("%w: cannot remediate failed release", ErrMissingRollbackTarget)
-				}
+// stopAfterAction determines the result after running a remediation
+// action, which stops the reconciliation for now. It returns ErrMustRequeue
+// if the release should be attempted again, or marks the object as stalled
+// if the remediation is exhausted.
+func (r *AtomicRelease) stopAfterAction(req *Request) error {
+	if rollbackFailuresExhausted(req.Object) {
+		return r.markRemediationExhausted(req)
+	}
 
-				if interrors.IsOneOf(err, action.ErrReleaseDisappeared, action.ErrReleaseNotObserved, action.ErrReleaseDigest) {
-					// If the rollback target is in any way corrupt,
-					// the most correct remediation is to reattempt the upgrade.
-					log.Info(msgWithReason("unable to verify previous release in storage to roll back to", err.Error()))
-					return NewUpgrade(r.configFactory, r.eventRecorder), nil
-				}
+	remediation := req.Object.GetActiveRemediation()
+	if remediation == nil || !remediation.RetriesExhausted(req.Object) {
+		conditions.MarkReconciling(req.Object, meta.ProgressingWithRetryReason, "%s", conditions.GetMessage(req.Object, meta.ReadyCondition))
+		return ErrMustRequeue
+	}
+	// Check if retries have exhausted after remediation for early
+	// stall condition detection.
+	if remediation != nil && remediation.RetriesExhausted(req.Object) {
+		conditions.MarkStalled(req.Object, "RetriesExceeded", "Failed to %s after %d attempt(s)",
+			req.Object.Status.LastAttemptedReleaseAction, req.Object.GetActiveRemediation().GetFailureCount(req.Object))
+		return ErrExceededMaxRetries
+	}
+
+	conditions.Delete(req.Object, meta.ReconcilingCondition)
+	return nil
+}
+
+// remediationForFailure determines the next action to run for a failed
+// release, based on the active remediation strategy of the Request.Object
+// and its failure counts. It returns an Upgrade if the release should be
+// attempted again, or the remediation action of the strategy.
+func (r *AtomicRelease) remediationForFailure(ctx context.Context, req *Request, forceRequested bool) (ActionReconciler, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	remediation := req.Object.GetActiveRemediation()
+
+	// If there is no active remediation strategy, we can only attempt to
+	// upgrade the release to see if that fixes the problem.
+	if remediation == nil {
+		log.V(logger.DebugLevel).Info("no active remediation strategy")
+		return NewUpgrade(r.configFactory, r.eventRecorder), nil
+	}
 
-				// This may be a temporary error, return it to retry.
-				return nil, fmt.Errorf("cannot verify previous release to roll back to: %w", err)
+	// If there is no failure count, the conditions under which the failure
+	// occurred must have changed.
+	// Attempt to upgrade the release to see if the problem is resolved.
+	// This ensures that after a configuration change, the release is
+	// attempted again.
+	if remediation.GetFailureCount(req.Object) <= 0 {
+		log.Info("release conditions have changed since last failure")
+		return NewUpgrade(r.configFactory, r.eventRecorder), nil
+	}
+
+	// If the force annotation is set, we can attempt to upgrade the release
+	// without any further checks.
+	if forceRequested {
+		log.Info(msgWithReason("forcing upgrade for failed release", "force requested through annotation"))
+		return NewUpgrade(r.configFactory, r.eventRecorder), nil
+	}
+
+	// We have exhausted the number of retries for the remediation
+	// strategy.
+	if remediation.RetriesExhausted(req.Object) && !remediation.MustRemediateLastFailure() {
+		return nil, fmt.Errorf("%w: cannot remediate failed release", ErrExceededMaxRetries)
+	}
+
+	// The remediation has been suspended, e.g. to investigate the
+	// failure during an incident.
+	if req.Object.IsRemediationSuspended() {
+		return nil, fmt.Errorf("%w: not performing %s remediation of failed release",
+			ErrRemediationSuspended, remediation.GetStrategy())
+	}
+
+	// Reset the history up to the point where the failure occurred.
+	// This ensures we do not accumulate a long history of failures.
+	req.Object.Status.History.Truncate(remediation.MustIgnoreTestFailures(req.Object.GetTest().IgnoreFailures))
+
+	switch remediation.GetStrategy() {
+	case v2.RollbackRemediationStrategy:
+		// Verify the previous release is still in storage and unmodified
+		// before instructing to roll back to it.
+		prev := req.Object.Status.History.Previous(remediation.MustIgnoreTestFailures(req.Object.GetTest().IgnoreFailures))
+		if _, err := action.VerifySnapshot(r.configFactory.Store(), prev); err != nil {
+			if errors.Is(err, action.ErrReleaseNotFound) {
+				// If the rollback target is missing, we cannot roll back
+				// to it and must fail.
+				return nil, fmt.Errorf("%w: cannot remediate failed release", ErrMissingRollbackTarget)
 			}
-			return NewRollbackRemediation(r.configFactory, r.eventRecorder), nil
-		case v2.UninstallRemediationStrategy:
-			return NewUninstallRemediation(r.configFactory, r.eventRecorder), nil
-		default:
-			return nil, fmt.Errorf("%w: %s", ErrUnknownRemediationStrategy, remediation.GetStrategy())
+
+			if interrors.IsOneOf(err, action.ErrReleaseDisappeared, action.ErrReleaseNotObserved, action.ErrReleaseDigest) {
+				// If the rollback target is in any way corrupt,
+				// the most correct remediation is to reattempt the upgrade.
+				log.Info(msgWithReason("unable to verify previous release in storage to roll back to", err.Error()))
+				return NewUpgrade(r.configFactory, r.eventRecorder), nil
+			}
+
+			// This may be a temporary error, return it to retry.
+			return nil, fmt.Errorf("cannot verify previous release to roll back to: %w", err)
 		}
+		return NewRollbackRemediation(r.configFactory, r.eventRecorder), nil
+	case v2.UninstallRemediationStrategy:
+		return NewUninstallRemediation(r.configFactory, r.eventRecorder), nil
 	default:
-		return nil, fmt.Errorf("%w: %s", ErrUnknownReleaseStatus, state.Status)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRemediationStrategy, remediation.GetStrategy())
 	}
 }
 
@@ -559,3 +740,37 @@ func replaceCondition(obj *v2.HelmRelease, target string, replacement string, re
 		conditions.Delete(obj, target)
 	}
 }
+
+// HideSecrets returns true if the content of Secrets must be masked when
+// surfaced for the given object, as configured by the HideSecrets feature
+// gate.
+func HideSecrets(obj *v2.HelmRelease) bool {
+	hide, err := features.EnabledFor(obj, features.HideSecrets)
+	return hide || err != nil
+}
+
+// redactedSecretMessage replaces a message referring to a Secret when the
+// content of Secrets must be masked.
+const redactedSecretMessage = "message redacted, as it refers to a Secret which may be quoted in it"
+
+// SecretRedactor returns a function which masks the content of Secrets in a
+// message about the given object, e.g. an error recorded on a trace span, if
+// the content of Secrets must be masked for the object. Otherwise, the
+// message is returned as is.
+//
+// Any Secret manifests are removed from the message. If the message still
+// refers to a Secret afterwards, e.g. an error of the API server quoting an
+// invalid value of a Secret, the message is replaced as a whole, as the data
+// of the Secret can not be reliably told apart from the rest of it.
+func SecretRedactor(obj *v2.HelmRelease) func(string) string {
+	if !HideSecrets(obj) {
+		return func(msg string) string { return msg }
+	}
+	return func(msg string) string {
+		msg = release.HideSecretsInManifest(msg)
+		if strings.Contains(strings.ToLower(msg), "secret") {
+			return redactedSecretMessage
+		}
+		return msg
+	}
+}
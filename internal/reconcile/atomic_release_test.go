@@ -18,17 +18,21 @@ package reconcile
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
-	. "github.com/onsi/gomega"
-	extjsondiff "github.com/wI2L/jsondiff"
 	helmchart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	releaseutil "github.com/jessesimpson36/helm/v4/pkg/release/util"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
-	"github.com/jessesimpson36/helm/v4/pkg/release/v1util"
 	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
 	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	. "github.com/onsi/gomega"
+	extjsondiff "github.com/wI2L/jsondiff"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -44,10 +48,12 @@ import (
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/action"
 	"github.com/fluxcd/helm-controller/internal/digest"
+	"github.com/fluxcd/helm-controller/internal/features"
 	"github.com/fluxcd/helm-controller/internal/kube"
 	"github.com/fluxcd/helm-controller/internal/postrender"
 	"github.com/fluxcd/helm-controller/internal/release"
 	"github.com/fluxcd/helm-controller/internal/testutil"
+	"github.com/fluxcd/helm-controller/internal/tracing"
 )
 
 func TestReleaseStrategy_CleanRelease_MustContinue(t *testing.T) {
@@ -1667,6 +1673,47 @@ func TestAtomicRelease_actionForState(t *testing.T) {
 			},
 			want: &RollbackRemediation{},
 		},
+		{
+			name:  "failed release with suspended remediation triggers error",
+			state: ReleaseState{Status: ReleaseStatusFailed},
+			annotations: map[string]string{
+				v2.SuspendAnnotation: v2.SuspendAnnotationRemediation,
+			},
+			releases: []*helmrelease.Release{
+				testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+					Name:      mockReleaseName,
+					Namespace: mockReleaseNamespace,
+					Version:   1,
+					Status:    helmrelease.StatusSuperseded,
+					Chart:     testutil.BuildChart(),
+				}),
+				testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+					Name:      mockReleaseName,
+					Namespace: mockReleaseNamespace,
+					Version:   2,
+					Status:    helmrelease.StatusFailed,
+					Chart:     testutil.BuildChart(),
+				}),
+			},
+			spec: func(spec *v2.HelmReleaseSpec) {
+				spec.Upgrade = &v2.Upgrade{
+					Remediation: &v2.UpgradeRemediation{
+						Retries: 2,
+					},
+				}
+			},
+			status: func(releases []*helmrelease.Release) v2.HelmReleaseStatus {
+				return v2.HelmReleaseStatus{
+					History: v2.Snapshots{
+						release.ObservedToSnapshot(release.ObserveRelease(releases[1])),
+						release.ObservedToSnapshot(release.ObserveRelease(releases[0])),
+					},
+					LastAttemptedReleaseAction: v2.ReleaseActionUpgrade,
+					UpgradeFailures:            1,
+				}
+			},
+			wantErr: ErrRemediationSuspended,
+		},
 		{
 			name:  "failed release with active upgrade remediation and no previous release triggers error",
 			state: ReleaseState{Status: ReleaseStatusFailed},
@@ -1837,6 +1884,89 @@ func TestAtomicRelease_actionForState(t *testing.T) {
 	}
 }
 
+func TestAtomicRelease_remediationExhausted(t *testing.T) {
+	newObj := func(maxRollbackFailures int, rollbackFailures int64, annotations map[string]string) *v2.HelmRelease {
+		return &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: annotations,
+			},
+			Spec: v2.HelmReleaseSpec{
+				Upgrade: &v2.Upgrade{
+					Remediation: &v2.UpgradeRemediation{
+						MaxRollbackFailures: maxRollbackFailures,
+					},
+				},
+			},
+			Status: v2.HelmReleaseStatus{
+				Failures:         4,
+				UpgradeFailures:  2,
+				RollbackFailures: rollbackFailures,
+			},
+		}
+	}
+
+	t.Run("without limit", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := testutil.NewFakeRecorder(10, false)
+		r := &AtomicRelease{eventRecorder: recorder}
+		req := &Request{Object: newObj(0, 5, nil)}
+
+		g.Expect(r.remediationExhausted(req)).To(BeFalse())
+		g.Expect(recorder.GetEvents()).To(BeEmpty())
+	})
+
+	t.Run("below limit", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &AtomicRelease{eventRecorder: testutil.NewFakeRecorder(10, false)}
+		req := &Request{Object: newObj(3, 2, nil)}
+
+		g.Expect(r.remediationExhausted(req)).To(BeFalse())
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := testutil.NewFakeRecorder(10, false)
+		r := &AtomicRelease{eventRecorder: recorder}
+		req := &Request{Object: newObj(3, 3, nil)}
+
+		g.Expect(r.remediationExhausted(req)).To(BeTrue())
+		g.Expect(r.markRemediationExhausted(req)).To(MatchError(ErrRemediationExhausted))
+
+		expectMsg := fmt.Sprintf(fmtRemediationExhausted, 3, v2.AcknowledgeRemediationRequestAnnotation)
+		g.Expect(req.Object.Status.Conditions).To(conditions.MatchConditions([]metav1.Condition{
+			*conditions.TrueCondition(meta.StalledCondition, v2.RemediationExhaustedReason, expectMsg),
+			*conditions.FalseCondition(meta.ReadyCondition, v2.RemediationExhaustedReason, expectMsg),
+		}))
+		events := recorder.GetEvents()
+		g.Expect(events).To(HaveLen(1))
+		g.Expect(events[0].Reason).To(Equal(v2.RemediationExhaustedReason))
+	})
+
+	t.Run("limit reached and acknowledged", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := testutil.NewFakeRecorder(10, false)
+		r := &AtomicRelease{eventRecorder: recorder}
+		req := &Request{Object: newObj(3, 3, map[string]string{
+			meta.ReconcileRequestAnnotation:            "now",
+			v2.AcknowledgeRemediationRequestAnnotation: "now",
+		})}
+
+		g.Expect(r.remediationExhausted(req)).To(BeFalse())
+		g.Expect(req.Object.Status.LastHandledAcknowledgeRemediationAt).To(Equal("now"))
+		g.Expect(req.Object.Status.RollbackFailures).To(BeZero())
+		g.Expect(req.Object.Status.Failures).To(BeZero())
+		g.Expect(req.Object.Status.UpgradeFailures).To(BeZero())
+
+		events := recorder.GetEvents()
+		g.Expect(events).To(HaveLen(1))
+		g.Expect(events[0].Reason).To(Equal(remediationAcknowledgedReason))
+	})
+}
+
 func Test_replaceCondition(t *testing.T) {
 	g := NewWithT(t)
 	timestamp, err := time.Parse(time.UnixDate, "Wed Feb 25 11:06:39 GMT 2015")
@@ -1950,3 +2080,75 @@ func Test_replaceCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretRedactor(t *testing.T) {
+	const secret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+stringData:
+  password: hunter2
+`
+	err := errors.New("failed to apply manifest:\n---\n" + secret)
+
+	endSpan := func(obj *v2.HelmRelease) tracetest.SpanStub {
+		exporter := tracetest.NewInMemoryExporter()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		prev := otel.GetTracerProvider()
+		otel.SetTracerProvider(tp)
+		defer otel.SetTracerProvider(prev)
+
+		_, span := tracing.StartSpan(context.TODO(), "install")
+		tracing.EndSpan(span, err, SecretRedactor(obj))
+		return exporter.GetSpans()[0]
+	}
+
+	t.Run("masks Secrets recorded on spans", func(t *testing.T) {
+		g := NewWithT(t)
+
+		span := endSpan(&v2.HelmRelease{})
+		g.Expect(span.Status.Description).To(ContainSubstring("failed to apply manifest"))
+		g.Expect(span.Status.Description).ToNot(ContainSubstring("hunter2"))
+		g.Expect(span.Events).To(HaveLen(1))
+		for _, attr := range span.Events[0].Attributes {
+			g.Expect(attr.Value.Emit()).ToNot(ContainSubstring("hunter2"))
+		}
+	})
+
+	t.Run("masks errors referring to Secrets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		exporter := tracetest.NewInMemoryExporter()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		prev := otel.GetTracerProvider()
+		otel.SetTracerProvider(tp)
+		t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+		_, span := tracing.StartSpan(context.TODO(), "install")
+		tracing.EndSpan(span, errors.New(`Secret "credentials" is invalid: data[password]: Invalid value: "hunter2"`),
+			SecretRedactor(&v2.HelmRelease{}))
+
+		stub := exporter.GetSpans()[0]
+		g.Expect(stub.Status.Description).To(Equal(redactedSecretMessage))
+		for _, attr := range stub.Events[0].Attributes {
+			g.Expect(attr.Value.Emit()).ToNot(ContainSubstring("hunter2"))
+		}
+	})
+
+	t.Run("keeps errors not referring to Secrets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(SecretRedactor(&v2.HelmRelease{})("timed out waiting for the condition")).
+			To(Equal("timed out waiting for the condition"))
+	})
+
+	t.Run("keeps Secrets with HideSecrets disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		t.Cleanup(func() { _ = features.SetConfigured(nil) })
+		g.Expect(features.SetConfigured(map[string]bool{features.HideSecrets: false})).To(Succeed())
+
+		span := endSpan(&v2.HelmRelease{})
+		g.Expect(span.Status.Description).To(ContainSubstring("hunter2"))
+	})
+}
@@ -18,7 +18,12 @@ package reconcile
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	gostrings "strings"
+	"text/template"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,9 +41,34 @@ import (
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/acl"
+	"github.com/fluxcd/helm-controller/internal/action"
+	"github.com/fluxcd/helm-controller/internal/features"
 	"github.com/fluxcd/helm-controller/internal/strings"
 )
 
+var (
+	// helmChartOwnerNameLabel is the label holding the name of the
+	// HelmRelease which generated a HelmChart.
+	helmChartOwnerNameLabel = v2.GroupVersion.Group + "/name"
+	// helmChartOwnerNamespaceLabel is the label holding the namespace of the
+	// HelmRelease which generated a HelmChart.
+	helmChartOwnerNamespaceLabel = v2.GroupVersion.Group + "/namespace"
+	// helmChartSharedLabel is the label marking a HelmChart as shared by the
+	// HelmReleases with an identical chart template.
+	helmChartSharedLabel = v2.GroupVersion.Group + "/shared"
+)
+
+const (
+	// sharedHelmChartPrefix is the prefix of the name of a shared HelmChart.
+	sharedHelmChartPrefix = "shared-"
+
+	// DefaultHelmChartInterval is the interval of a HelmChart built from a
+	// template without an interval, for a HelmRelease with a zero interval.
+	// The source-controller requires a nonzero interval, which is also
+	// needed to pick up new chart versions matching a version range.
+	DefaultHelmChartInterval = 10 * time.Minute
+)
+
 // HelmChartTemplate attempts to create, update or delete a v1beta2.HelmChart
 // based on the given Request data.
 //
@@ -83,17 +113,20 @@ func (r *HelmChartTemplate) Reconcile(ctx context.Context, req *Request) error {
 	)
 
 	if obj.Spec.Chart != nil {
-		chartRef.Name = obj.GetHelmChartName()
+		chartRef.Name = HelmChartName(obj)
 		chartRef.Namespace = obj.Spec.Chart.GetNamespace(obj.Namespace)
 	}
 
 	// The HelmChart name and/or namespace diverges or the HelmRelease is
 	// being deleted, delete the HelmChart.
 	if (obj.Status.HelmChart != "" && obj.Status.HelmChart != chartRef.String()) || !obj.DeletionTimestamp.IsZero() {
+		if err := r.reconcileDelete(ctx, req.Object); err != nil {
+			return err
+		}
 		// If the HelmRelease is being deleted, we need to short-circuit to
 		// avoid recreating the HelmChart.
-		if err := r.reconcileDelete(ctx, req.Object); err != nil || !obj.DeletionTimestamp.IsZero() {
-			return err
+		if !obj.DeletionTimestamp.IsZero() {
+			return r.garbageCollect(ctx, req.Object, types.NamespacedName{})
 		}
 	}
 
@@ -103,7 +136,7 @@ func (r *HelmChartTemplate) Reconcile(ctx context.Context, req *Request) error {
 		if err := r.reconcileDelete(ctx, req.Object); err != nil {
 			return err
 		}
-		return nil
+		return r.garbageCollect(ctx, req.Object, types.NamespacedName{})
 	}
 
 	if obj.HasChartRef() {
@@ -116,6 +149,12 @@ func (r *HelmChartTemplate) Reconcile(ctx context.Context, req *Request) error {
 		return err
 	}
 
+	// Confirm the HelmChart is not owned by another HelmRelease, e.g.
+	// because the generated names of the HelmCharts collide.
+	if err := r.confirmOwnership(ctx, req.Object, chartRef); err != nil {
+		return err
+	}
+
 	// Build new HelmChart based on the declared template.
 	newChart := buildHelmChartFromTemplate(req.Object)
 
@@ -138,8 +177,11 @@ func (r *HelmChartTemplate) Reconcile(ctx context.Context, req *Request) error {
 		Field: r.fieldManager,
 	})
 
-	// Mark the object as owned by the HelmRelease.
-	rm.SetOwnerLabels([]*unstructured.Unstructured{u}, obj.GetName(), obj.GetNamespace())
+	// Mark the object as owned by the HelmRelease, unless it is shared with
+	// other HelmReleases.
+	if !isSharedHelmChart(newChart) {
+		rm.SetOwnerLabels([]*unstructured.Unstructured{u}, obj.GetName(), obj.GetNamespace())
+	}
 
 	// Run using server-side apply.
 	entry, err := rm.Apply(ctx, u, ssa.DefaultApplyOptions())
@@ -173,13 +215,105 @@ func (r *HelmChartTemplate) Reconcile(ctx context.Context, req *Request) error {
 	// From this moment on, we know the HelmChart spec is up-to-date.
 	obj.Status.HelmChart = chartRef.String()
 
+	// Collect any HelmChart generated for a previous template which is no
+	// longer referenced by the status, e.g. due to a lost status update.
+	return r.garbageCollect(ctx, req.Object, chartRef)
+}
+
+// confirmOwnership returns an error if the HelmChart with the given
+// namespaced name exists, and is owned by another HelmRelease than the given
+// one.
+func (r *HelmChartTemplate) confirmOwnership(ctx context.Context, obj *v2.HelmRelease, chartRef types.NamespacedName) error {
+	var chart sourcev1.HelmChart
+	if err := r.client.Get(ctx, chartRef, &chart); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get HelmChart '%s': %w", chartRef, err)
+	}
+	if !isHelmChartOwner(&chart, obj) {
+		return fmt.Errorf("HelmChart '%s' is owned by HelmRelease '%s/%s'", chartRef,
+			chart.GetLabels()[helmChartOwnerNamespaceLabel], chart.GetLabels()[helmChartOwnerNameLabel])
+	}
+	return nil
+}
+
+// garbageCollect deletes the HelmCharts labeled as owned by the given
+// HelmRelease, except for the HelmChart with the given namespaced name. This
+// collects HelmCharts generated for a previous template of which the
+// reference was lost, which would otherwise be orphaned.
+func (r *HelmChartTemplate) garbageCollect(ctx context.Context, obj *v2.HelmRelease, keep types.NamespacedName) error {
+	if obj.IsSuspended() {
+		return nil
+	}
+
+	var list sourcev1.HelmChartList
+	if err := r.client.List(ctx, &list, client.MatchingLabels{
+		helmChartOwnerNameLabel:      obj.GetName(),
+		helmChartOwnerNamespaceLabel: obj.GetNamespace(),
+	}); err != nil {
+		return fmt.Errorf("failed to list HelmCharts for garbage collection: %w", err)
+	}
+
+	for i := range list.Items {
+		chart := &list.Items[i]
+		namespacedName := client.ObjectKeyFromObject(chart)
+		if namespacedName == keep || !chart.DeletionTimestamp.IsZero() {
+			continue
+		}
+		// Skip HelmCharts we are not allowed to access.
+		if err := acl.AllowsAccessTo(obj, sourcev1.HelmChartKind, namespacedName); err != nil {
+			continue
+		}
+		if err := r.client.Delete(ctx, chart); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete HelmChart '%s': %w", namespacedName, err)
+		}
+		r.eventRecorder.Eventf(obj, eventv1.EventTypeTrace, "HelmChartDeleted", "deleted HelmChart '%s'", namespacedName)
+	}
 	return nil
 }
 
+// isHelmChartOwner returns true if the given HelmChart is labeled as owned by
+// the given HelmRelease. HelmCharts without owner labels are assumed to be
+// owned by the HelmRelease, as they were generated before the labels were
+// introduced.
+func isHelmChartOwner(chart *sourcev1.HelmChart, obj *v2.HelmRelease) bool {
+	labels := chart.GetLabels()
+	name, ok := labels[helmChartOwnerNameLabel]
+	if !ok {
+		return true
+	}
+	return name == obj.GetName() && labels[helmChartOwnerNamespaceLabel] == obj.GetNamespace()
+}
+
+// isSharedHelmChart returns true if the given HelmChart is labeled as shared
+// by the HelmReleases with an identical chart template.
+func isSharedHelmChart(chart *sourcev1.HelmChart) bool {
+	return chart.GetLabels()[helmChartSharedLabel] == "true"
+}
+
+// isHelmChartInUse returns true if any other HelmRelease than the given one
+// generates the HelmChart with the given namespaced name, as determined
+// using the v2.SourceIndexKey index.
+func (r *HelmChartTemplate) isHelmChartInUse(ctx context.Context, obj *v2.HelmRelease, chartRef types.NamespacedName) (bool, error) {
+	var list v2.HelmReleaseList
+	if err := r.client.List(ctx, &list, client.MatchingFields{
+		v2.SourceIndexKey: chartRef.String(),
+	}); err != nil {
+		return false, fmt.Errorf("failed to list HelmReleases sharing HelmChart '%s': %w", chartRef, err)
+	}
+	for _, hr := range list.Items {
+		if hr.GetName() != obj.GetName() || hr.GetNamespace() != obj.GetNamespace() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // reconcileDelete handles the garbage collection of the current HelmChart in
 // the Status object of the given HelmRelease.
 func (r *HelmChartTemplate) reconcileDelete(ctx context.Context, obj *v2.HelmRelease) error {
-	if !obj.Spec.Suspend && obj.Status.HelmChart != "" {
+	if !obj.IsSuspended() && obj.Status.HelmChart != "" {
 		ns, name := obj.Status.GetHelmChart()
 		namespacedName := types.NamespacedName{Namespace: ns, Name: name}
 
@@ -196,7 +330,21 @@ func (r *HelmChartTemplate) reconcileDelete(ctx context.Context, obj *v2.HelmRel
 			err = fmt.Errorf("failed to delete HelmChart '%s': %w", obj.Status.HelmChart, err)
 			return err
 		}
-		if err == nil {
+		var inUse bool
+		if err == nil && isSharedHelmChart(&chart) {
+			if inUse, err = r.isHelmChartInUse(ctx, obj, namespacedName); err != nil {
+				return err
+			}
+		}
+		if err == nil && !isHelmChartOwner(&chart, obj) {
+			// The HelmChart has been taken over by another HelmRelease,
+			// only release our reference to it.
+			ctrl.LoggerFrom(ctx).Info(fmt.Sprintf("skipping deletion of HelmChart '%s' owned by another HelmRelease", obj.Status.HelmChart))
+		} else if inUse {
+			// The HelmChart is shared with other HelmReleases, only release
+			// our reference to it.
+			ctrl.LoggerFrom(ctx).Info(fmt.Sprintf("skipping deletion of HelmChart '%s' shared with other HelmReleases", obj.Status.HelmChart))
+		} else if err == nil {
 			// Delete the HelmChart.
 			if err = r.client.Delete(ctx, &chart); err != nil {
 				err = fmt.Errorf("failed to delete HelmChart '%s': %w", obj.Status.HelmChart, err)
@@ -212,6 +360,101 @@ func (r *HelmChartTemplate) reconcileDelete(ctx context.Context, obj *v2.HelmRel
 	return nil
 }
 
+// HelmChartName returns the name of the HelmChart generated from the chart
+// template of the given HelmRelease. With the SharedHelmCharts feature
+// enabled, this is a name derived from the generated HelmChart, which equals
+// for all HelmReleases with an identical chart template.
+func HelmChartName(obj *v2.HelmRelease) string {
+	if shared, _ := features.EnabledFor(obj, features.SharedHelmCharts); !shared {
+		return obj.GetHelmChartName()
+	}
+	return buildHelmChartFromTemplate(obj).GetName()
+}
+
+// chartTemplateData is the data the Go templates in the chart name and
+// version of a HelmRelease are executed with.
+type chartTemplateData struct {
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// chartTemplateFuncs are the functions available to the Go templates in the
+// chart name and version of a HelmRelease.
+var chartTemplateFuncs = template.FuncMap{
+	"default": func(def, v string) string {
+		if v == "" {
+			return def
+		}
+		return v
+	},
+}
+
+// IsChartTemplateExpression returns true if the given chart name or version
+// contains a Go template action.
+func IsChartTemplateExpression(s string) bool {
+	return gostrings.Contains(s, "{{")
+}
+
+// ParseChartTemplateExpression parses the given chart name or version as a
+// Go template with the functions available during rendering.
+func ParseChartTemplateExpression(s string) (*template.Template, error) {
+	return template.New("").Funcs(chartTemplateFuncs).Option("missingkey=error").Parse(s)
+}
+
+// RenderChartTemplate renders the Go template expressions in the chart name
+// and version of the chart template of the given HelmRelease, using the
+// name, namespace, labels and annotations of the object. The object is
+// mutated in place, and should not be persisted after rendering.
+func RenderChartTemplate(obj *v2.HelmRelease) error {
+	if !obj.HasChartTemplate() {
+		return nil
+	}
+	data := chartTemplateData{
+		Name:        obj.GetName(),
+		Namespace:   obj.GetNamespace(),
+		Labels:      obj.GetLabels(),
+		Annotations: obj.GetAnnotations(),
+	}
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{
+		{"chart", &obj.Spec.Chart.Spec.Chart},
+		{"version", &obj.Spec.Chart.Spec.Version},
+	} {
+		if !IsChartTemplateExpression(*f.value) {
+			continue
+		}
+		tpl, err := ParseChartTemplateExpression(*f.value)
+		if err != nil {
+			return fmt.Errorf("failed to parse chart %s template: %w", f.name, err)
+		}
+		var b gostrings.Builder
+		if err := tpl.Execute(&b, data); err != nil {
+			return fmt.Errorf("failed to render chart %s template: %w", f.name, err)
+		}
+		if f.name == "chart" && b.Len() == 0 {
+			return fmt.Errorf("chart name template %q rendered to an empty string", *f.value)
+		}
+		*f.value = b.String()
+	}
+	return nil
+}
+
+// sharedHelmChartName returns the name of the shared HelmChart with the
+// spec and metadata of the given HelmChart.
+func sharedHelmChartName(chart *sourcev1.HelmChart) string {
+	// Marshalling of these types does not fail, and sorts the map keys.
+	b, _ := json.Marshal(struct {
+		Spec        sourcev1.HelmChartSpec `json:"spec"`
+		Labels      map[string]string      `json:"labels,omitempty"`
+		Annotations map[string]string      `json:"annotations,omitempty"`
+	}{chart.Spec, chart.GetLabels(), chart.GetAnnotations()})
+	return fmt.Sprintf("%s%x", sharedHelmChartPrefix, sha256.Sum256(b))[:len(sharedHelmChartPrefix)+16]
+}
+
 // buildHelmChartFromTemplate builds a v1beta2.HelmChart from the
 // v2beta1.HelmChartTemplate of the given v2beta1.HelmRelease.
 func buildHelmChartFromTemplate(obj *v2.HelmRelease) *sourcev1.HelmChart {
@@ -234,16 +477,38 @@ func buildHelmChartFromTemplate(obj *v2.HelmRelease) *sourcev1.HelmChart {
 			IgnoreMissingValuesFiles: template.Spec.IgnoreMissingValuesFiles,
 		},
 	}
-	if verifyTpl := template.Spec.Verify; verifyTpl != nil {
+	if result.Spec.Interval.Duration == 0 {
+		result.Spec.Interval = metav1.Duration{Duration: DefaultHelmChartInterval}
+	}
+	verifyTpl := template.Spec.Verify
+	if obj.GetArtifactVerification(action.DefaultArtifactVerification) == v2.ArtifactVerificationDisabled {
+		verifyTpl = nil
+	}
+	if verifyTpl != nil {
 		result.Spec.Verify = &sourcev1.OCIRepositoryVerification{
 			Provider:  verifyTpl.Provider,
 			SecretRef: verifyTpl.SecretRef,
 		}
+		for _, match := range verifyTpl.MatchOIDCIdentity {
+			result.Spec.Verify.MatchOIDCIdentity = append(result.Spec.Verify.MatchOIDCIdentity, sourcev1.OIDCIdentityMatch{
+				Issuer:  match.Issuer,
+				Subject: match.Subject,
+			})
+		}
 	}
 	if metaTpl := template.ObjectMeta; metaTpl != nil {
 		result.SetAnnotations(metaTpl.Annotations)
 		result.SetLabels(metaTpl.Labels)
 	}
+	if shared, _ := features.EnabledFor(obj, features.SharedHelmCharts); shared {
+		result.SetName(sharedHelmChartName(result))
+		labels := result.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[helmChartSharedLabel] = "true"
+		result.SetLabels(labels)
+	}
 	return result
 }
 
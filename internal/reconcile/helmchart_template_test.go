@@ -28,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/fluxcd/pkg/apis/meta"
@@ -36,6 +37,8 @@ import (
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/acl"
+	"github.com/fluxcd/helm-controller/internal/action"
+	"github.com/fluxcd/helm-controller/internal/features"
 )
 
 func TestHelmChartTemplate_Reconcile(t *testing.T) {
@@ -651,6 +654,227 @@ func TestHelmChartTemplate_reconcileDelete(t *testing.T) {
 		err := r.reconcileDelete(context.TODO(), obj)
 		g.Expect(err).ToNot(HaveOccurred())
 	})
+
+	t.Run("HelmChart owned by other HelmRelease is not deleted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		builder := fake.NewClientBuilder().
+			WithScheme(NewTestScheme()).
+			WithObjects(&sourcev1.HelmChart{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "chart",
+					Labels: map[string]string{
+						helmChartOwnerNameLabel:      "other",
+						helmChartOwnerNamespaceLabel: "default",
+					},
+				},
+			})
+
+		r := &HelmChartTemplate{
+			client:        builder.Build(),
+			eventRecorder: record.NewFakeRecorder(32),
+		}
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "release",
+				Namespace: "default",
+			},
+			Status: v2.HelmReleaseStatus{
+				HelmChart: "default/chart",
+			},
+		}
+		err := r.reconcileDelete(context.TODO(), obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj.Status.HelmChart).To(BeEmpty())
+
+		g.Expect(r.client.Get(context.TODO(),
+			types.NamespacedName{Namespace: "default", Name: "chart"},
+			&sourcev1.HelmChart{}),
+		).To(Succeed())
+	})
+}
+
+func TestHelmChartTemplate_garbageCollect(t *testing.T) {
+	newChart := func(namespace, name, owner string) *sourcev1.HelmChart {
+		return &sourcev1.HelmChart{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					helmChartOwnerNameLabel:      owner,
+					helmChartOwnerNamespaceLabel: "default",
+				},
+			},
+		}
+	}
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "release",
+			Namespace: "default",
+		},
+	}
+
+	t.Run("deletes orphaned HelmCharts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(32)
+		r := &HelmChartTemplate{
+			client: fake.NewClientBuilder().
+				WithScheme(NewTestScheme()).
+				WithObjects(
+					newChart("default", "current", "release"),
+					newChart("other", "orphan", "release"),
+					newChart("default", "foreign", "other"),
+				).
+				Build(),
+			eventRecorder: recorder,
+		}
+
+		err := r.garbageCollect(context.TODO(), obj, types.NamespacedName{Namespace: "default", Name: "current"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(recorder.Events).To(Receive(ContainSubstring("other/orphan")))
+
+		for key, exists := range map[types.NamespacedName]bool{
+			{Namespace: "default", Name: "current"}: true,
+			{Namespace: "other", Name: "orphan"}:    false,
+			{Namespace: "default", Name: "foreign"}: true,
+		} {
+			err := r.client.Get(context.TODO(), key, &sourcev1.HelmChart{})
+			if exists {
+				g.Expect(err).ToNot(HaveOccurred(), key.String())
+			} else {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), key.String())
+			}
+		}
+	})
+
+	t.Run("Spec.Suspend is respected", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &HelmChartTemplate{
+			client: fake.NewClientBuilder().
+				WithScheme(NewTestScheme()).
+				WithObjects(newChart("other", "orphan", "release")).
+				Build(),
+			eventRecorder: record.NewFakeRecorder(32),
+		}
+
+		suspended := obj.DeepCopy()
+		suspended.Spec.Suspend = true
+		g.Expect(r.garbageCollect(context.TODO(), suspended, types.NamespacedName{})).To(Succeed())
+		g.Expect(r.client.Get(context.TODO(),
+			types.NamespacedName{Namespace: "other", Name: "orphan"},
+			&sourcev1.HelmChart{}),
+		).To(Succeed())
+	})
+}
+
+func TestHelmChartTemplate_confirmOwnership(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &HelmChartTemplate{
+		client: fake.NewClientBuilder().
+			WithScheme(NewTestScheme()).
+			WithObjects(&sourcev1.HelmChart{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "team-a-app",
+					Labels: map[string]string{
+						helmChartOwnerNameLabel:      "app",
+						helmChartOwnerNamespaceLabel: "team-a",
+					},
+				},
+			}).
+			Build(),
+	}
+
+	chartRef := types.NamespacedName{Namespace: "default", Name: "team-a-app"}
+	owner := &v2.HelmRelease{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "app"}}
+	g.Expect(r.confirmOwnership(context.TODO(), owner, chartRef)).To(Succeed())
+
+	other := &v2.HelmRelease{ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "a-app"}}
+	g.Expect(r.confirmOwnership(context.TODO(), other, chartRef)).To(MatchError(
+		"HelmChart 'default/team-a-app' is owned by HelmRelease 'team-a/app'"))
+
+	g.Expect(r.confirmOwnership(context.TODO(), other, types.NamespacedName{Namespace: "default", Name: "missing"})).To(Succeed())
+}
+
+func TestHelmChartTemplate_sharedHelmChart(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(features.SetOverridable([]string{features.SharedHelmCharts})).To(Succeed())
+	t.Cleanup(func() {
+		_ = features.SetOverridable(nil)
+	})
+
+	newRelease := func(name, version string) *v2.HelmRelease {
+		return &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				Annotations: map[string]string{v2.FeatureGatesAnnotation: features.SharedHelmCharts + "=true"},
+			},
+			Spec: v2.HelmReleaseSpec{
+				Interval: metav1.Duration{Duration: time.Minute},
+				Chart: &v2.HelmChartTemplate{
+					Spec: v2.HelmChartTemplateSpec{
+						Chart:   "podinfo",
+						Version: version,
+						SourceRef: v2.CrossNamespaceObjectReference{
+							Kind: sourcev1.HelmRepositoryKind,
+							Name: "podinfo",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	a := newRelease("podinfo", "6.5.0")
+	b := newRelease("frontend", "6.5.0")
+	c := newRelease("canary", "6.6.0")
+
+	// HelmReleases with an identical chart template share a HelmChart.
+	g.Expect(HelmChartName(a)).To(HavePrefix(sharedHelmChartPrefix))
+	g.Expect(HelmChartName(a)).To(Equal(HelmChartName(b)))
+	g.Expect(HelmChartName(a)).ToNot(Equal(HelmChartName(c)))
+
+	chart := buildHelmChartFromTemplate(a)
+	g.Expect(chart.GetName()).To(Equal(HelmChartName(a)))
+	g.Expect(isSharedHelmChart(chart)).To(BeTrue())
+
+	// Without the feature, the name is derived from the HelmRelease.
+	a.Annotations = nil
+	g.Expect(HelmChartName(a)).To(Equal("default-podinfo"))
+	g.Expect(isSharedHelmChart(buildHelmChartFromTemplate(a))).To(BeFalse())
+	a.Annotations = b.Annotations
+
+	// A shared HelmChart is only deleted with its last reference.
+	chartRef := types.NamespacedName{Namespace: "default", Name: HelmChartName(a)}
+	a.Status.HelmChart = chartRef.String()
+	b.Status.HelmChart = chartRef.String()
+	r := &HelmChartTemplate{
+		client: fake.NewClientBuilder().
+			WithScheme(NewTestScheme()).
+			WithObjects(chart, a, b).
+			WithIndex(&v2.HelmRelease{}, v2.SourceIndexKey, func(o client.Object) []string {
+				obj := o.(*v2.HelmRelease)
+				return []string{obj.Spec.Chart.GetNamespace(obj.Namespace) + "/" + HelmChartName(obj)}
+			}).
+			Build(),
+		eventRecorder: record.NewFakeRecorder(32),
+	}
+
+	g.Expect(r.reconcileDelete(context.TODO(), a)).To(Succeed())
+	g.Expect(a.Status.HelmChart).To(BeEmpty())
+	g.Expect(r.client.Get(context.TODO(), chartRef, &sourcev1.HelmChart{})).To(Succeed())
+
+	g.Expect(r.client.Delete(context.TODO(), a)).To(Succeed())
+	g.Expect(r.reconcileDelete(context.TODO(), b)).To(Succeed())
+	g.Expect(b.Status.HelmChart).To(BeEmpty())
+	g.Expect(apierrors.IsNotFound(r.client.Get(context.TODO(), chartRef, &sourcev1.HelmChart{}))).To(BeTrue())
 }
 
 func Test_buildHelmChartFromTemplate(t *testing.T) {
@@ -745,6 +969,108 @@ func Test_buildHelmChartFromTemplate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "defaults interval of event-driven HelmRelease",
+			modify: func(hr *v2.HelmRelease) {
+				hr.Spec.Interval = metav1.Duration{}
+				hr.Spec.Chart.Spec.Interval = nil
+			},
+			want: &sourcev1.HelmChart{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-test-release",
+					Namespace: "default",
+				},
+				Spec: sourcev1.HelmChartSpec{
+					Chart:   "chart",
+					Version: "1.0.0",
+					SourceRef: sourcev1.LocalHelmChartSourceReference{
+						Name: "test-repository",
+						Kind: "HelmRepository",
+					},
+					Interval:    metav1.Duration{Duration: DefaultHelmChartInterval},
+					ValuesFiles: []string{"values.yaml"},
+				},
+			},
+		},
+		{
+			name: "passes through reconcile strategy and values files",
+			modify: func(hr *v2.HelmRelease) {
+				hr.Spec.Chart.Spec.ReconcileStrategy = "Revision"
+				hr.Spec.Chart.Spec.ValuesFiles = []string{"values.yaml", "values-prod.yaml"}
+				hr.Spec.Chart.Spec.IgnoreMissingValuesFiles = true
+			},
+			want: &sourcev1.HelmChart{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-test-release",
+					Namespace: "default",
+				},
+				Spec: sourcev1.HelmChartSpec{
+					Chart:   "chart",
+					Version: "1.0.0",
+					SourceRef: sourcev1.LocalHelmChartSourceReference{
+						Name: "test-repository",
+						Kind: "HelmRepository",
+					},
+					Interval:                 metav1.Duration{Duration: 2 * time.Minute},
+					ReconcileStrategy:        "Revision",
+					ValuesFiles:              []string{"values.yaml", "values-prod.yaml"},
+					IgnoreMissingValuesFiles: true,
+				},
+			},
+		},
+		{
+			name: "builds HelmChart from GitRepository source",
+			modify: func(hr *v2.HelmRelease) {
+				hr.Spec.Chart.Spec.Chart = "./charts/podinfo"
+				hr.Spec.Chart.Spec.Version = ""
+				hr.Spec.Chart.Spec.SourceRef = v2.CrossNamespaceObjectReference{
+					Name: "monorepo",
+					Kind: "GitRepository",
+				}
+			},
+			want: &sourcev1.HelmChart{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-test-release",
+					Namespace: "default",
+				},
+				Spec: sourcev1.HelmChartSpec{
+					Chart: "./charts/podinfo",
+					SourceRef: sourcev1.LocalHelmChartSourceReference{
+						Name: "monorepo",
+						Kind: "GitRepository",
+					},
+					Interval:    metav1.Duration{Duration: 2 * time.Minute},
+					ValuesFiles: []string{"values.yaml"},
+				},
+			},
+		},
+		{
+			name: "builds HelmChart from Bucket source",
+			modify: func(hr *v2.HelmRelease) {
+				hr.Spec.Chart.Spec.Chart = "./podinfo"
+				hr.Spec.Chart.Spec.Version = ""
+				hr.Spec.Chart.Spec.SourceRef = v2.CrossNamespaceObjectReference{
+					Name:      "charts",
+					Namespace: "cross",
+					Kind:      "Bucket",
+				}
+			},
+			want: &sourcev1.HelmChart{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-test-release",
+					Namespace: "cross",
+				},
+				Spec: sourcev1.HelmChartSpec{
+					Chart: "./podinfo",
+					SourceRef: sourcev1.LocalHelmChartSourceReference{
+						Name: "charts",
+						Kind: "Bucket",
+					},
+					Interval:    metav1.Duration{Duration: 2 * time.Minute},
+					ValuesFiles: []string{"values.yaml"},
+				},
+			},
+		},
 		{
 			name: "take cosign verification into account",
 			modify: func(hr *v2.HelmRelease) {
@@ -778,6 +1104,98 @@ func Test_buildHelmChartFromTemplate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "take cosign keyless verification into account",
+			modify: func(hr *v2.HelmRelease) {
+				hr.Spec.Chart.Spec.Verify = &v2.HelmChartTemplateVerification{
+					Provider: "cosign",
+					MatchOIDCIdentity: []v2.OIDCIdentityMatch{
+						{
+							Issuer:  "^https://token.actions.githubusercontent.com$",
+							Subject: "^https://github.com/stefanprodan/podinfo.*$",
+						},
+					},
+				}
+			},
+			want: &sourcev1.HelmChart{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-test-release",
+					Namespace: "default",
+				},
+				Spec: sourcev1.HelmChartSpec{
+					Chart:   "chart",
+					Version: "1.0.0",
+					SourceRef: sourcev1.LocalHelmChartSourceReference{
+						Name: "test-repository",
+						Kind: "HelmRepository",
+					},
+					Interval:    metav1.Duration{Duration: 2 * time.Minute},
+					ValuesFiles: []string{"values.yaml"},
+					Verify: &sourcev1.OCIRepositoryVerification{
+						Provider: "cosign",
+						MatchOIDCIdentity: []sourcev1.OIDCIdentityMatch{
+							{
+								Issuer:  "^https://token.actions.githubusercontent.com$",
+								Subject: "^https://github.com/stefanprodan/podinfo.*$",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "keeps verification when only the object disables artifact verification",
+			modify: func(hr *v2.HelmRelease) {
+				hr.Spec.ArtifactVerification = v2.ArtifactVerificationDisabled
+				hr.Spec.Chart.Spec.Verify = &v2.HelmChartTemplateVerification{
+					Provider: "cosign",
+				}
+			},
+			want: &sourcev1.HelmChart{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-test-release",
+					Namespace: "default",
+				},
+				Spec: sourcev1.HelmChartSpec{
+					Chart:   "chart",
+					Version: "1.0.0",
+					SourceRef: sourcev1.LocalHelmChartSourceReference{
+						Name: "test-repository",
+						Kind: "HelmRepository",
+					},
+					Interval:    metav1.Duration{Duration: 2 * time.Minute},
+					ValuesFiles: []string{"values.yaml"},
+					Verify: &sourcev1.OCIRepositoryVerification{
+						Provider: "cosign",
+					},
+				},
+			},
+		},
+		{
+			name: "omits verification when artifact verification is disabled",
+			modify: func(hr *v2.HelmRelease) {
+				action.DefaultArtifactVerification = v2.ArtifactVerificationDisabled
+				hr.Spec.Chart.Spec.Verify = &v2.HelmChartTemplateVerification{
+					Provider: "cosign",
+				}
+			},
+			want: &sourcev1.HelmChart{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-test-release",
+					Namespace: "default",
+				},
+				Spec: sourcev1.HelmChartSpec{
+					Chart:   "chart",
+					Version: "1.0.0",
+					SourceRef: sourcev1.LocalHelmChartSourceReference{
+						Name: "test-repository",
+						Kind: "HelmRepository",
+					},
+					Interval:    metav1.Duration{Duration: 2 * time.Minute},
+					ValuesFiles: []string{"values.yaml"},
+				},
+			},
+		},
 		{
 			name: "takes object meta into account",
 			modify: func(hr *v2.HelmRelease) {
@@ -818,6 +1236,9 @@ func Test_buildHelmChartFromTemplate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
+			defaultVerification := action.DefaultArtifactVerification
+			t.Cleanup(func() { action.DefaultArtifactVerification = defaultVerification })
+
 			hr := hrWithChartTemplate.DeepCopy()
 			tt.modify(hr)
 
@@ -825,3 +1246,83 @@ func Test_buildHelmChartFromTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderChartTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		chart       string
+		version     string
+		wantChart   string
+		wantVersion string
+		wantErr     string
+	}{
+		{
+			name:        "without template expressions",
+			chart:       "podinfo",
+			version:     "6.x",
+			wantChart:   "podinfo",
+			wantVersion: "6.x",
+		},
+		{
+			name:        "renders labels and annotations",
+			chart:       "{{ .Labels.app }}-{{ .Namespace }}",
+			version:     `{{ index .Annotations "example.com/version" }}`,
+			wantChart:   "podinfo-default",
+			wantVersion: "6.5.x",
+		},
+		{
+			name:        "renders default for empty value",
+			chart:       "podinfo",
+			version:     `{{ index .Labels "version" | default "*" }}`,
+			wantChart:   "podinfo",
+			wantVersion: "*",
+		},
+		{
+			name:    "missing label",
+			chart:   "{{ .Labels.missing }}",
+			wantErr: "failed to render chart chart template",
+		},
+		{
+			name:    "invalid template",
+			chart:   "podinfo",
+			version: "{{ .Labels.app",
+			wantErr: "failed to parse chart version template",
+		},
+		{
+			name:    "empty chart name",
+			chart:   `{{ index .Labels "missing" }}`,
+			wantErr: "rendered to an empty string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "release",
+					Namespace:   "default",
+					Labels:      map[string]string{"app": "podinfo"},
+					Annotations: map[string]string{"example.com/version": "6.5.x"},
+				},
+				Spec: v2.HelmReleaseSpec{
+					Chart: &v2.HelmChartTemplate{
+						Spec: v2.HelmChartTemplateSpec{
+							Chart:   tt.chart,
+							Version: tt.version,
+						},
+					},
+				},
+			}
+
+			err := RenderChartTemplate(obj)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(obj.Spec.Chart.Spec.Chart).To(Equal(tt.wantChart))
+			g.Expect(obj.Spec.Chart.Spec.Version).To(Equal(tt.wantVersion))
+		})
+	}
+}
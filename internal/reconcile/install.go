@@ -19,20 +19,18 @@ package reconcile
 import (
 	"context"
 	"fmt"
-	helmchartutil "helm.sh/helm/v3/pkg/chartutil"
 	"strings"
 
-	"github.com/fluxcd/pkg/runtime/logger"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
-	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/jessesimpson36/helm/v4/pkg/kube"
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/action"
 	"github.com/fluxcd/helm-controller/internal/digest"
-	"github.com/fluxcd/pkg/chartutil"
+	intvalues "github.com/fluxcd/helm-controller/internal/values"
 )
 
 // Install is an ActionReconciler which attempts to install a Helm release
@@ -47,7 +45,8 @@ import (
 //
 // On installation success, the object is marked with Released=True and emits
 // an event. In addition, the object is marked with TestSuccess=False if tests
-// are enabled to indicate we are awaiting the results.
+// are enabled to indicate we are awaiting the results, and the rendered
+// notes of the release are recorded in Status.LastReleaseNotes.
 // On failure, the object is marked with Released=False and emits a warning
 // event. Only an error which resulted in a modification to the Helm storage
 // counts towards a failure for the active remediation strategy.
@@ -70,9 +69,10 @@ func NewInstall(cfg *action.ConfigFactory, recorder record.EventRecorder) *Insta
 
 func (r *Install) Reconcile(ctx context.Context, req *Request) error {
 	var (
-		logBuf      = action.NewLogBuffer(action.NewDebugLog(ctrl.LoggerFrom(ctx).V(logger.DebugLevel)), 10)
+		logBuf      = newActionLogBuffer(ctx, req.Object, r.Name())
 		obsReleases = make(observedReleases)
-		cfg         = r.configFactory.Build(logBuf.Log, observeRelease(obsReleases))
+		progress    = newWaitProgress(r.eventRecorder, req.Object, progressEventInterval, logBuf.Log)
+		cfg         = r.configFactory.Build(progress.Log, observeRelease(obsReleases))
 	)
 
 	defer summarize(req)
@@ -89,8 +89,32 @@ func (r *Install) Reconcile(ctx context.Context, req *Request) error {
 	conditions.Delete(req.Object, v2.TestSuccessCondition)
 	conditions.Delete(req.Object, v2.RemediatedCondition)
 
+	// Expose the environment of the hooks of the install, until it has
+	// completed.
+	hookEnv, err := action.NewHookEnv(cfg, req.Object)
+	if err != nil {
+		r.failure(req, logBuf, err)
+		return err
+	}
+	if err := hookEnv.Apply(ctx, req.InstallEnv); err != nil {
+		r.failure(req, logBuf, err)
+		return err
+	}
+	defer deleteHookEnv(ctx, hookEnv)
+
+	// Validate the rendered objects against the schema of a remote cluster,
+	// which may run a different Kubernetes version than the cluster of the
+	// controller, before running the Helm install action.
+	if req.Object.Spec.KubeConfig != nil {
+		if err := action.ValidateInstall(ctx, cfg, req.Object, req.Chart, req.Values, kube.ManagedFieldsManager); err != nil {
+			err = fmt.Errorf("dry-run on target cluster failed: %w", err)
+			r.failure(req, logBuf, err)
+			return err
+		}
+	}
+
 	// Run the Helm install action.
-	_, err := action.Install(ctx, cfg, req.Object, req.Chart, req.Values)
+	rls, err := action.Install(ctx, cfg, req.Object, req.Chart, req.Values)
 
 	// Record the history of releases observed during the install.
 	obsReleases.recordOnObject(req.Object, mutateOCIDigest)
@@ -115,6 +139,7 @@ func (r *Install) Reconcile(ctx context.Context, req *Request) error {
 	}
 
 	r.success(req)
+	recordReleaseNotes(r.eventRecorder, req.Object, rls)
 	return nil
 }
 
@@ -149,14 +174,15 @@ func (r *Install) failure(req *Request, buffer *action.LogBuffer, err error) {
 
 	// Mark install failure on object.
 	req.Object.Status.Failures++
-	conditions.MarkFalse(req.Object, v2.ReleasedCondition, v2.InstallFailedReason, "%s", msg)
+	conditions.MarkFalse(req.Object, v2.ReleasedCondition, v2.InstallFailedReason, "%s", conditionMessageWithLog(msg, buffer))
 
 	// Record warning event, this message contains more data than the
 	// Condition summary.
 	r.eventRecorder.AnnotatedEventf(
 		req.Object,
-		eventMeta(req.Chart.Metadata.Version, chartutil.DigestValues(digest.Canonical, helmchartutil.Values(req.Values)).String(),
-			addAppVersion(req.Chart.AppVersion()), addOCIDigest(req.Object.Status.LastAttemptedRevisionDigest)),
+		eventMeta(req.Chart.Metadata.Version, intvalues.Digest(digest.Canonical, req.Values).String(),
+			addAppVersion(req.Chart.AppVersion()), addOCIDigest(req.Object.Status.LastAttemptedRevisionDigest),
+			addChart(req.Chart.Name(), req.Chart.Metadata.Version), addTargetNamespace(req.Object.GetReleaseNamespace())),
 		corev1.EventTypeWarning,
 		v2.InstallFailedReason,
 		eventMessageWithLog(msg, buffer),
@@ -183,7 +209,7 @@ func (r *Install) success(req *Request) {
 	// Record event.
 	r.eventRecorder.AnnotatedEventf(
 		req.Object,
-		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest)),
+		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest), addSnapshot(cur)),
 		corev1.EventTypeNormal,
 		v2.InstallSucceededReason,
 		msg,
@@ -20,16 +20,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
-	. "github.com/onsi/gomega"
-	"github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
 	helmchartutil "github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
+	releaseutil "github.com/jessesimpson36/helm/v4/pkg/release/util"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
-	"github.com/jessesimpson36/helm/v4/pkg/release/v1util"
 	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
 	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -483,7 +484,10 @@ func TestInstall_failure(t *testing.T) {
 						eventMetaGroupKey(metaOCIDigestKey):        obj.Status.LastAttemptedRevisionDigest,
 						eventMetaGroupKey(eventv1.MetaRevisionKey): chrt.Metadata.Version,
 						eventMetaGroupKey(metaAppVersionKey):       chrt.Metadata.AppVersion,
-						eventMetaGroupKey(eventv1.MetaTokenKey):    chartutil.DigestValues(digest.Canonical, req.Values).String(),
+						eventMetaGroupKey(eventv1.MetaTokenKey):    chartutil.DigestValues(digest.Canonical, req.Values.AsMap()).String(),
+						eventMetaGroupKey(metaChartNameKey):        chrt.Name(),
+						eventMetaGroupKey(metaChartVersionKey):     chrt.Metadata.Version,
+						eventMetaGroupKey(metaTargetNamespaceKey):  obj.GetReleaseNamespace(),
 					},
 				},
 			},
@@ -556,6 +560,10 @@ func TestInstall_success(t *testing.T) {
 						eventMetaGroupKey(eventv1.MetaRevisionKey): obj.Status.History.Latest().ChartVersion,
 						eventMetaGroupKey(metaAppVersionKey):       obj.Status.History.Latest().AppVersion,
 						eventMetaGroupKey(eventv1.MetaTokenKey):    obj.Status.History.Latest().ConfigDigest,
+						eventMetaGroupKey(metaChartNameKey):        obj.Status.History.Latest().ChartName,
+						eventMetaGroupKey(metaChartVersionKey):     obj.Status.History.Latest().ChartVersion,
+						eventMetaGroupKey(metaTargetNamespaceKey):  obj.Status.History.Latest().Namespace,
+						eventMetaGroupKey(metaReleaseRevisionKey):  strconv.Itoa(obj.Status.History.Latest().Version),
 					},
 				},
 			},
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/action"
+)
+
+const (
+	// interruptedActionReason is the event reason for an interrupted action.
+	interruptedActionReason = "InterruptedAction"
+
+	// fmtInterruptedAction is the message format for an interrupted action.
+	fmtInterruptedAction = "Helm %s action for release %s/%s started at %s was interrupted"
+	// fmtInterruptedActionHolder is the message format for an interrupted
+	// action of which the controller instance which started it is known.
+	fmtInterruptedActionHolder = "Helm %s action for release %s/%s started at %s by %s was interrupted"
+)
+
+// Holder can be set at runtime to the identity of the controller instance
+// (e.g. the name of its Pod), to record it in the journal entries of the
+// actions it starts. When the controller runs with multiple replicas, this
+// tells which replica was interrupted while performing an action.
+var Holder string
+
+// newInFlightAction returns a journal entry for the named action, to be
+// recorded in the status of the given object before the action is started.
+func newInFlightAction(obj *v2.HelmRelease, name string) *v2.InFlightAction {
+	entry := &v2.InFlightAction{
+		Name:             name,
+		ReleaseName:      obj.GetReleaseName(),
+		ReleaseNamespace: obj.GetReleaseNamespace(),
+		StartedAt:        metav1.Now(),
+		Holder:           Holder,
+	}
+	if cur := obj.Status.History.Latest(); cur != nil {
+		entry.Revision = cur.Version
+	}
+	return entry
+}
+
+// recoverInterruptedAction handles the journal entry of an action which was
+// interrupted before it could finish, e.g. due to a restart of the
+// controller, and removes it from the status of the Request.Object.
+//
+// An interrupted install or upgrade which has written a new release to the
+// Helm storage is recorded as a failure, similar to a failed action. This
+// ensures the configured remediation strategy is applied to the (likely
+// incomplete) release, after it has been unlocked. An interrupted install or
+// upgrade which did not modify the Helm storage, or any other interrupted
+// action, is retried based on the state of the release as determined by the
+// caller.
+func (r *AtomicRelease) recoverInterruptedAction(ctx context.Context, req *Request) error {
+	entry := req.Object.Status.InFlightAction
+	if entry == nil {
+		return nil
+	}
+
+	msg := fmt.Sprintf(fmtInterruptedAction, entry.Name, entry.ReleaseNamespace, entry.ReleaseName,
+		entry.StartedAt.Format(metav1.RFC3339Micro))
+	if entry.Holder != "" {
+		msg = fmt.Sprintf(fmtInterruptedActionHolder, entry.Name, entry.ReleaseNamespace, entry.ReleaseName,
+			entry.StartedAt.Format(metav1.RFC3339Micro), entry.Holder)
+	}
+	ctrl.LoggerFrom(ctx).Info(msg)
+
+	var (
+		remediation v2.Remediation
+		reason      string
+	)
+	switch entry.Name {
+	case (&Install{}).Name():
+		remediation, reason = req.Object.GetInstall().GetRemediation(), v2.InstallFailedReason
+	case (&Upgrade{}).Name():
+		remediation, reason = req.Object.GetUpgrade().GetRemediation(), v2.UpgradeFailedReason
+	default:
+		req.Object.Status.InFlightAction = nil
+		r.eventRecorder.Eventf(req.Object, corev1.EventTypeWarning, interruptedActionReason,
+			"%s: determining state of release to continue", msg)
+		return nil
+	}
+
+	rls, err := action.LastRelease(r.configFactory.Store(), entry.ReleaseName)
+	if err != nil && !errors.Is(err, action.ErrReleaseNotFound) {
+		return fmt.Errorf("cannot determine state of interrupted %s action: %w", entry.Name, err)
+	}
+	req.Object.Status.InFlightAction = nil
+
+	if rls == nil || rls.Version <= entry.Revision {
+		r.eventRecorder.Eventf(req.Object, corev1.EventTypeWarning, interruptedActionReason,
+			"%s before modifying the Helm storage: retrying action", msg)
+		return nil
+	}
+
+	req.Object.Status.Failures++
+	remediation.IncrementFailureCount(req.Object)
+	conditions.MarkFalse(req.Object, v2.ReleasedCondition, reason,
+		"%s after writing revision %d to the Helm storage", msg, rls.Version)
+	r.eventRecorder.Eventf(req.Object, corev1.EventTypeWarning, interruptedActionReason,
+		"%s after writing revision %d to the Helm storage: remediating release", msg, rls.Version)
+	return nil
+}
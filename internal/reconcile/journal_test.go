@@ -0,0 +1,202 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
+	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/action"
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+func Test_newInFlightAction(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+		Spec: v2.HelmReleaseSpec{
+			ReleaseName:     mockReleaseName,
+			TargetNamespace: "other",
+		},
+	}
+
+	entry := newInFlightAction(obj, "install")
+	g.Expect(entry.Name).To(Equal("install"))
+	g.Expect(entry.ReleaseName).To(Equal(mockReleaseName))
+	g.Expect(entry.ReleaseNamespace).To(Equal("other"))
+	g.Expect(entry.Revision).To(BeZero())
+	g.Expect(entry.StartedAt.IsZero()).To(BeFalse())
+
+	g.Expect(entry.Holder).To(BeEmpty())
+
+	obj.Status.History = v2.Snapshots{{Version: 3}}
+	g.Expect(newInFlightAction(obj, "upgrade").Revision).To(Equal(3))
+
+	Holder = "helm-controller-0"
+	t.Cleanup(func() { Holder = "" })
+	g.Expect(newInFlightAction(obj, "upgrade").Holder).To(Equal("helm-controller-0"))
+}
+
+func TestAtomicRelease_recoverInterruptedAction(t *testing.T) {
+	tests := []struct {
+		name string
+		// entry is the in-flight action recorded in the status.
+		entry *v2.InFlightAction
+		// releases is the list of releases in the Helm storage.
+		releases []*helmrelease.Release
+		// wantFailures is the expected Failures count.
+		wantFailures int64
+		// wantInstallFailures is the expected InstallFailures count.
+		wantInstallFailures int64
+		// wantUpgradeFailures is the expected UpgradeFailures count.
+		wantUpgradeFailures int64
+		// wantReleasedReason is the expected reason of the Released
+		// condition, or empty if the condition is not expected to be set.
+		wantReleasedReason string
+		// wantEvent is whether an event is expected to be emitted.
+		wantEvent bool
+		// wantEventMessage is a substring the message of the emitted event
+		// is expected to contain.
+		wantEventMessage string
+	}{
+		{
+			name: "no in-flight action",
+		},
+		{
+			name:      "interrupted install without release",
+			entry:     &v2.InFlightAction{Name: "install", ReleaseName: mockReleaseName, ReleaseNamespace: "default"},
+			wantEvent: true,
+		},
+		{
+			name:  "interrupted install with pending release",
+			entry: &v2.InFlightAction{Name: "install", ReleaseName: mockReleaseName, ReleaseNamespace: "default"},
+			releases: []*helmrelease.Release{
+				testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+					Name:      mockReleaseName,
+					Namespace: "default",
+					Version:   1,
+					Chart:     testutil.BuildChart(),
+					Status:    helmrelease.StatusPendingInstall,
+				}),
+			},
+			wantFailures:        1,
+			wantInstallFailures: 1,
+			wantReleasedReason:  v2.InstallFailedReason,
+			wantEvent:           true,
+		},
+		{
+			name:  "interrupted upgrade with pending release",
+			entry: &v2.InFlightAction{Name: "upgrade", ReleaseName: mockReleaseName, ReleaseNamespace: "default", Revision: 1},
+			releases: []*helmrelease.Release{
+				testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+					Name:      mockReleaseName,
+					Namespace: "default",
+					Version:   1,
+					Chart:     testutil.BuildChart(),
+					Status:    helmrelease.StatusSuperseded,
+				}),
+				testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+					Name:      mockReleaseName,
+					Namespace: "default",
+					Version:   2,
+					Chart:     testutil.BuildChart(),
+					Status:    helmrelease.StatusPendingUpgrade,
+				}),
+			},
+			wantFailures:        1,
+			wantUpgradeFailures: 1,
+			wantReleasedReason:  v2.UpgradeFailedReason,
+			wantEvent:           true,
+		},
+		{
+			name:  "interrupted upgrade without new release",
+			entry: &v2.InFlightAction{Name: "upgrade", ReleaseName: mockReleaseName, ReleaseNamespace: "default", Revision: 1},
+			releases: []*helmrelease.Release{
+				testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+					Name:      mockReleaseName,
+					Namespace: "default",
+					Version:   1,
+					Chart:     testutil.BuildChart(),
+					Status:    helmrelease.StatusDeployed,
+				}),
+			},
+			wantEvent: true,
+		},
+		{
+			name:      "interrupted test",
+			entry:     &v2.InFlightAction{Name: "test", ReleaseName: mockReleaseName, ReleaseNamespace: "default", Revision: 1},
+			wantEvent: true,
+		},
+		{
+			name: "interrupted action of other replica",
+			entry: &v2.InFlightAction{Name: "test", ReleaseName: mockReleaseName, ReleaseNamespace: "default", Revision: 1,
+				Holder: "helm-controller-1"},
+			wantEvent:        true,
+			wantEventMessage: "by helm-controller-1 was interrupted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cfg := &action.ConfigFactory{Driver: helmdriver.NewMemory()}
+			store := helmstorage.Init(cfg.Driver)
+			for _, rls := range tt.releases {
+				g.Expect(store.Create(rls)).To(Succeed())
+			}
+
+			obj := &v2.HelmRelease{
+				Spec: v2.HelmReleaseSpec{ReleaseName: mockReleaseName},
+			}
+			obj.Status.InFlightAction = tt.entry
+
+			recorder := record.NewFakeRecorder(10)
+			r := &AtomicRelease{configFactory: cfg, eventRecorder: recorder}
+			g.Expect(r.recoverInterruptedAction(context.TODO(), &Request{Object: obj})).To(Succeed())
+
+			g.Expect(obj.Status.InFlightAction).To(BeNil())
+			g.Expect(obj.Status.Failures).To(Equal(tt.wantFailures))
+			g.Expect(obj.Status.InstallFailures).To(Equal(tt.wantInstallFailures))
+			g.Expect(obj.Status.UpgradeFailures).To(Equal(tt.wantUpgradeFailures))
+			if tt.wantReleasedReason != "" {
+				g.Expect(conditions.IsFalse(obj, v2.ReleasedCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(obj, v2.ReleasedCondition)).To(Equal(tt.wantReleasedReason))
+			} else {
+				g.Expect(conditions.Has(obj, v2.ReleasedCondition)).To(BeFalse())
+			}
+			if tt.wantEvent {
+				g.Expect(recorder.Events).To(HaveLen(1))
+				if tt.wantEventMessage != "" {
+					g.Expect(<-recorder.Events).To(ContainSubstring(tt.wantEventMessage))
+				}
+			} else {
+				g.Expect(recorder.Events).To(BeEmpty())
+			}
+		})
+	}
+}
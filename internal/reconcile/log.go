@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+
+	"github.com/fluxcd/pkg/runtime/logger"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/action"
+)
+
+const (
+	// actionLogBufferSize is the number of Helm log lines retained for a
+	// single action.
+	actionLogBufferSize = 10
+
+	// maxConditionMessageLength is the maximum length of the message of a
+	// Condition, as enforced by the API server.
+	maxConditionMessageLength = 32768
+)
+
+// AttachLogsToConditions can be set at runtime to append the last Helm logs
+// of a failed action to the message of the Condition marking the failure, in
+// addition to the message of the event.
+var AttachLogsToConditions bool
+
+// newActionLogBuffer returns a LogBuffer for the Helm logs of the named
+// action on the release of the given object. The logs are written at debug
+// level, with the release and action as structured keys.
+func newActionLogBuffer(ctx context.Context, obj *v2.HelmRelease, name string) *action.LogBuffer {
+	log := ctrl.LoggerFrom(ctx).V(logger.DebugLevel).WithValues(
+		"release", obj.GetReleaseNamespace()+"/"+obj.GetReleaseName(),
+		"action", name,
+	)
+	return action.NewLogBuffer(action.NewDebugLog(log), actionLogBufferSize)
+}
+
+// conditionMessageWithLog returns a Condition message composed out of the
+// given message and, when AttachLogsToConditions is set, any log messages.
+// The message is truncated to the maximum length of a Condition message.
+func conditionMessageWithLog(msg string, log *action.LogBuffer) string {
+	if !AttachLogsToConditions {
+		return msg
+	}
+	msg = eventMessageWithLog(msg, log)
+	if len(msg) > maxConditionMessageLength {
+		msg = msg[:maxConditionMessageLength-3] + "..."
+	}
+	return msg
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/helm-controller/internal/action"
+)
+
+func Test_conditionMessageWithLog(t *testing.T) {
+	newBuffer := func(lines ...string) *action.LogBuffer {
+		buf := action.NewLogBuffer(func(string, ...interface{}) {}, actionLogBufferSize)
+		for _, l := range lines {
+			buf.Log("%s", l)
+		}
+		return buf
+	}
+
+	t.Run("without attaching logs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		AttachLogsToConditions = false
+		g.Expect(conditionMessageWithLog("upgrade failed", newBuffer("waiting for resources"))).To(Equal("upgrade failed"))
+	})
+
+	t.Run("attaches logs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		AttachLogsToConditions = true
+		t.Cleanup(func() { AttachLogsToConditions = false })
+
+		msg := conditionMessageWithLog("upgrade failed", newBuffer("waiting for resources"))
+		g.Expect(msg).To(HavePrefix("upgrade failed\n\nLast Helm logs:\n\n"))
+		g.Expect(msg).To(HaveSuffix("waiting for resources"))
+
+		g.Expect(conditionMessageWithLog("upgrade failed", newBuffer())).To(Equal("upgrade failed"))
+		g.Expect(conditionMessageWithLog("upgrade failed", nil)).To(Equal("upgrade failed"))
+	})
+
+	t.Run("truncates long messages", func(t *testing.T) {
+		g := NewWithT(t)
+
+		AttachLogsToConditions = true
+		t.Cleanup(func() { AttachLogsToConditions = false })
+
+		msg := conditionMessageWithLog("upgrade failed", newBuffer(strings.Repeat("a", maxConditionMessageLength)))
+		g.Expect(msg).To(HaveLen(maxConditionMessageLength))
+		g.Expect(msg).To(HaveSuffix("..."))
+	})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apierrutil "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/runtime/patch"
+)
+
+// patchBackoff is the backoff used by PatchWithRetry.
+var patchBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: 200 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// PatchWithRetry patches the object using the given patch.SerialPatcher, and
+// retries a bounded number of times when the patch fails due to a conflict.
+//
+// As the patcher calculates the patch from the changes made since its last
+// successful patch, every retry merges only the fields changed by the current
+// reconciliation onto the latest version of the object. This prevents a
+// conflict from failing the reconciliation, and the Helm actions performed
+// during it from being repeated.
+func PatchWithRetry(ctx context.Context, patcher *patch.SerialPatcher, obj client.Object, opts ...patch.Option) error {
+	return retry.OnError(patchBackoff, isConflict, func() error {
+		return patcher.Patch(ctx, obj, opts...)
+	})
+}
+
+// isConflict returns true if the (aggregate) error is caused by a conflict,
+// or the conflict retries of the patch helper for conditions being exhausted.
+func isConflict(err error) bool {
+	var agg apierrutil.Aggregate
+	if errors.As(err, &agg) {
+		for _, e := range agg.Errors() {
+			if isConflict(e) {
+				return true
+			}
+		}
+		return false
+	}
+	return apierrors.IsConflict(err) || wait.Interrupted(err)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apierrutil "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/pkg/runtime/patch"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestPatchWithRetry(t *testing.T) {
+	conflictErr := apierrors.NewConflict(schema.GroupResource{Group: v2.GroupVersion.Group, Resource: "helmreleases"}, "release", errors.New("object has been modified"))
+
+	t.Run("retries on conflict", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "release",
+				Namespace: "default",
+			},
+		}
+
+		var calls int
+		c := fake.NewClientBuilder().
+			WithScheme(NewTestScheme()).
+			WithObjects(obj).
+			WithStatusSubresource(&v2.HelmRelease{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					calls++
+					if calls < 3 {
+						return conflictErr
+					}
+					return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+				},
+			}).
+			Build()
+
+		patchHelper := patch.NewSerialPatcher(obj, c)
+		obj.Status.Failures = 2
+		g.Expect(PatchWithRetry(context.TODO(), patchHelper, obj)).To(Succeed())
+		g.Expect(calls).To(Equal(3))
+
+		got := &v2.HelmRelease{}
+		g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(obj), got)).To(Succeed())
+		g.Expect(got.Status.Failures).To(Equal(int64(2)))
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "release",
+				Namespace: "default",
+			},
+		}
+
+		var calls int
+		c := fake.NewClientBuilder().
+			WithScheme(NewTestScheme()).
+			WithObjects(obj).
+			WithStatusSubresource(&v2.HelmRelease{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					calls++
+					return apierrors.NewBadRequest("invalid")
+				},
+			}).
+			Build()
+
+		patchHelper := patch.NewSerialPatcher(obj, c)
+		obj.Status.Failures = 2
+		g.Expect(PatchWithRetry(context.TODO(), patchHelper, obj)).ToNot(Succeed())
+		g.Expect(calls).To(Equal(1))
+	})
+}
+
+func Test_isConflict(t *testing.T) {
+	conflictErr := apierrors.NewConflict(schema.GroupResource{Resource: "helmreleases"}, "release", errors.New("modified"))
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "conflict", err: conflictErr, want: true},
+		{name: "aggregate with conflict", err: apierrutil.NewAggregate([]error{errors.New("other"), conflictErr}), want: true},
+		{name: "interrupted", err: wait.ErrorInterrupted(errors.New("timeout")), want: true},
+		{name: "other", err: errors.New("other"), want: false},
+		{name: "aggregate without conflict", err: apierrutil.NewAggregate([]error{errors.New("other")}), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isConflict(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// progressEventInterval is the minimum interval between the progress events
+// emitted for a HelmRelease while Helm is waiting for its resources.
+const progressEventInterval = 30 * time.Second
+
+// waitProgress aggregates the log lines of the Helm waiter into progress
+// events for a HelmRelease, emitting at most one event per interval. This
+// prevents event storms while Helm waits for large rollouts, during which it
+// logs the state of every resource it waits for on every poll.
+//
+// Log lines not originating from the Helm waiter are passed on to the wrapped
+// log function only. Log lines received within the interval after the last
+// event are aggregated into the next event, with duplicate lines counted.
+type waitProgress struct {
+	recorder record.EventRecorder
+	obj      *v2.HelmRelease
+	interval time.Duration
+	log      func(format string, v ...interface{})
+	now      func() time.Time
+
+	mu      sync.Mutex
+	last    time.Time
+	pending []string
+	counts  map[string]int
+}
+
+// newWaitProgress returns a new waitProgress for the given object, which
+// passes all log lines on to the given log function.
+func newWaitProgress(recorder record.EventRecorder, obj *v2.HelmRelease, interval time.Duration, log func(format string, v ...interface{})) *waitProgress {
+	return &waitProgress{
+		recorder: recorder,
+		obj:      obj,
+		interval: interval,
+		log:      log,
+		now:      time.Now,
+		counts:   make(map[string]int),
+	}
+}
+
+// Log passes the log line on to the wrapped log function, and records it for
+// the next progress event if it originates from the Helm waiter. It is safe
+// to call this function from multiple goroutines.
+func (p *waitProgress) Log(format string, v ...interface{}) {
+	p.log(format, v...)
+
+	msg := fmt.Sprintf(format, v...)
+	if !isWaitLog(msg) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.counts[msg]; !ok {
+		p.pending = append(p.pending, msg)
+	}
+	p.counts[msg]++
+
+	now := p.now()
+	if !p.last.IsZero() && now.Sub(p.last) < p.interval {
+		return
+	}
+	p.last = now
+
+	p.recorder.Eventf(p.obj, corev1.EventTypeNormal, meta.ProgressingReason, "%s", p.message())
+	p.pending = nil
+	p.counts = make(map[string]int)
+}
+
+// message composes the progress event message out of the pending log lines.
+// It must be called with the lock held.
+func (p *waitProgress) message() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Waiting for resources of release %s/%s to become ready:",
+		p.obj.GetReleaseNamespace(), p.obj.GetReleaseName())
+	for _, msg := range p.pending {
+		b.WriteString("\n- " + msg)
+		if c := p.counts[msg]; c > 1 {
+			fmt.Fprintf(&b, " (%d times)", c)
+		}
+	}
+	return b.String()
+}
+
+// isWaitLog returns true if the given log line originates from the Helm
+// waiter.
+func isWaitLog(msg string) bool {
+	return strings.HasPrefix(msg, "beginning wait for") || strings.Contains(msg, "is not ready")
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func Test_waitProgress_Log(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := record.NewFakeRecorder(10)
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "podinfo",
+			Namespace: "default",
+		},
+	}
+
+	var logged int
+	p := newWaitProgress(recorder, obj, time.Minute, func(string, ...interface{}) {
+		logged++
+	})
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	notReady := "Deployment is not ready: default/podinfo. 0 out of 1 expected pods are ready"
+
+	// The first line of the waiter results in an event.
+	p.Log("beginning wait for %d resources with timeout of %s", 2, "5m0s")
+	g.Expect(recorder.Events).To(Receive(And(
+		ContainSubstring("Waiting for resources of release default/podinfo to become ready"),
+		ContainSubstring("beginning wait for 2 resources"),
+	)))
+
+	// Lines within the interval are aggregated, and lines not originating
+	// from the waiter are ignored.
+	p.Log("creating %d resource(s)", 2)
+	p.Log("%s", notReady)
+	p.Log("%s", notReady)
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	// The next line after the interval results in an aggregated event.
+	now = now.Add(time.Minute)
+	p.Log("%s", notReady)
+	g.Expect(recorder.Events).To(Receive(And(
+		ContainSubstring(notReady+" (3 times)"),
+		Not(ContainSubstring("creating")),
+	)))
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	// All lines are passed on to the wrapped log function.
+	g.Expect(logged).To(Equal(5))
+}
@@ -83,6 +83,12 @@ type Request struct {
 	// Values is the Helm chart values to be used for the installation or
 	// upgrade.
 	Values helmchartutil.Values
+	// InstallEnv is the environment to expose to the hooks of the Helm
+	// install action, as composed from v2.Install.ExtraEnv.
+	InstallEnv map[string][]byte
+	// UpgradeEnv is the environment to expose to the hooks of the Helm
+	// upgrade action, as composed from v2.Upgrade.ExtraEnv.
+	UpgradeEnv map[string][]byte
 }
 
 // ActionReconciler is an interface which defines the methods that a reconciler
@@ -17,14 +17,20 @@ limitations under the License.
 package reconcile
 
 import (
+	"context"
 	"errors"
 	"sort"
+	"strconv"
+	"strings"
 
 	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/conditions"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/action"
@@ -208,35 +214,103 @@ const (
 
 	// metaAppVersionKey is the key for the app version found in chart metadata.
 	metaAppVersionKey = "app-version"
+
+	// metaChartNameKey is the key for the name of the chart.
+	metaChartNameKey = "chart-name"
+
+	// metaChartVersionKey is the key for the version of the chart.
+	metaChartVersionKey = "chart-version"
+
+	// metaTargetNamespaceKey is the key for the namespace the release is
+	// made in.
+	metaTargetNamespaceKey = "target-namespace"
+
+	// metaReleaseRevisionKey is the key for the revision of the release.
+	metaReleaseRevisionKey = "release-revision"
+)
+
+const (
+	// releaseNotesReason is the event reason for the notes of a release.
+	releaseNotesReason = "ReleaseNotes"
+	// maxReleaseNotesLength is the maximum length of the release notes
+	// recorded in the Status of the HelmRelease.
+	maxReleaseNotesLength = 1024
 )
 
+// recordReleaseNotes records the rendered notes of the given release in the
+// Status.LastReleaseNotes field of the object, truncated to
+// maxReleaseNotesLength. If the release has notes, it emits an event with
+// the notes.
+func recordReleaseNotes(recorder record.EventRecorder, obj *v2.HelmRelease, rls *helmrelease.Release) {
+	var notes string
+	if rls != nil && rls.Info != nil {
+		notes = strings.TrimSpace(rls.Info.Notes)
+	}
+	if len(notes) > maxReleaseNotesLength {
+		notes = strings.ToValidUTF8(notes[:maxReleaseNotesLength], "") + "\n..."
+	}
+
+	obj.Status.LastReleaseNotes = notes
+	if notes == "" {
+		return
+	}
+	recorder.Eventf(obj, corev1.EventTypeNormal, releaseNotesReason, "%s", notes)
+}
+
+// pruneHistory prunes the revisions of the release of the given object from
+// the Helm storage according to its history policy. The storage is accessed
+// without observers, as the pruned revisions must not be recorded in the
+// Status.History. A failure to prune is reported using a warning event, but
+// does not fail the reconciliation.
+func pruneHistory(cfgFactory *action.ConfigFactory, recorder record.EventRecorder, obj *v2.HelmRelease) {
+	pruned, err := action.PruneHistory(cfgFactory.Build(nil), obj)
+	switch {
+	case err != nil:
+		recorder.Eventf(obj, corev1.EventTypeWarning, "PruneHistoryFailed",
+			"Failed to prune release history: %s", err)
+	case len(pruned) > 0:
+		versions := make([]string, 0, len(pruned))
+		for _, rls := range pruned {
+			versions = append(versions, strconv.Itoa(rls.Version))
+		}
+		recorder.Eventf(obj, corev1.EventTypeNormal, "PrunedHistory",
+			"Pruned failed and superseded release revisions from history: %s", strings.Join(versions, ", "))
+	}
+}
+
+// deleteHookEnv deletes the hook environment Secret of a release, once the
+// Helm action exposing it to its hooks has completed. Failures are logged, as
+// they must not affect the outcome of the action.
+func deleteHookEnv(ctx context.Context, hookEnv *action.HookEnv) {
+	if err := hookEnv.Delete(context.WithoutCancel(ctx)); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to delete hook environment")
+	}
+}
+
 // eventMeta returns the event (annotation) metadata based on the given
 // parameters.
 func eventMeta(revision, token string, metas ...addMeta) map[string]string {
-	var metadata map[string]string
-	if revision != "" || token != "" {
-		metadata = make(map[string]string)
-		if revision != "" {
-			metadata[eventMetaGroupKey(eventv1.MetaRevisionKey)] = revision
-		}
-		if token != "" {
-			metadata[eventMetaGroupKey(eventv1.MetaTokenKey)] = token
-		}
+	metadata := make(map[string]string)
+	if revision != "" {
+		metadata[eventMetaGroupKey(eventv1.MetaRevisionKey)] = revision
+	}
+	if token != "" {
+		metadata[eventMetaGroupKey(eventv1.MetaTokenKey)] = token
 	}
 
 	for _, add := range metas {
 		add(metadata)
 	}
 
+	if len(metadata) == 0 {
+		return nil
+	}
 	return metadata
 }
 
 func addOCIDigest(digest string) addMeta {
 	return func(m map[string]string) {
 		if digest != "" {
-			if m == nil {
-				m = make(map[string]string)
-			}
 			m[eventMetaGroupKey(metaOCIDigestKey)] = digest
 		}
 	}
@@ -245,14 +319,53 @@ func addOCIDigest(digest string) addMeta {
 func addAppVersion(appVersion string) addMeta {
 	return func(m map[string]string) {
 		if appVersion != "" {
-			if m == nil {
-				m = make(map[string]string)
-			}
 			m[eventMetaGroupKey(metaAppVersionKey)] = appVersion
 		}
 	}
 }
 
+// addChart adds the name and version of the chart, which allows alerts to
+// be routed and acted upon without looking up the release.
+func addChart(name, version string) addMeta {
+	return func(m map[string]string) {
+		if name != "" {
+			m[eventMetaGroupKey(metaChartNameKey)] = name
+		}
+		if version != "" {
+			m[eventMetaGroupKey(metaChartVersionKey)] = version
+		}
+	}
+}
+
+func addTargetNamespace(namespace string) addMeta {
+	return func(m map[string]string) {
+		if namespace != "" {
+			m[eventMetaGroupKey(metaTargetNamespaceKey)] = namespace
+		}
+	}
+}
+
+func addReleaseRevision(version int) addMeta {
+	return func(m map[string]string) {
+		if version > 0 {
+			m[eventMetaGroupKey(metaReleaseRevisionKey)] = strconv.Itoa(version)
+		}
+	}
+}
+
+// addSnapshot adds the chart, target namespace and revision of the release
+// of the given snapshot.
+func addSnapshot(snapshot *v2.Snapshot) addMeta {
+	return func(m map[string]string) {
+		if snapshot == nil {
+			return
+		}
+		addChart(snapshot.ChartName, snapshot.ChartVersion)(m)
+		addTargetNamespace(snapshot.Namespace)(m)
+		addReleaseRevision(snapshot.Version)(m)
+	}
+}
+
 // eventMetaGroupKey returns the event (annotation) metadata key prefixed with
 // the group.
 func eventMetaGroupKey(key string) string {
@@ -18,12 +18,15 @@ package reconcile
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr"
+	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 	. "github.com/onsi/gomega"
-	"github.com/jessesimpson36/helm/v4/pkg/chart/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/fluxcd/pkg/apis/kustomize"
 	"github.com/fluxcd/pkg/apis/meta"
@@ -713,3 +716,50 @@ func Test_RecordOnObject(t *testing.T) {
 	}
 
 }
+
+func Test_recordReleaseNotes(t *testing.T) {
+	tests := []struct {
+		name      string
+		rls       *helmrelease.Release
+		wantNotes string
+		wantEvent bool
+	}{
+		{
+			name:      "release with notes",
+			rls:       &helmrelease.Release{Info: &helmrelease.Info{Notes: "\nVisit http://podinfo.local\n"}},
+			wantNotes: "Visit http://podinfo.local",
+			wantEvent: true,
+		},
+		{
+			name:      "release with long notes",
+			rls:       &helmrelease.Release{Info: &helmrelease.Info{Notes: strings.Repeat("a", maxReleaseNotesLength+1)}},
+			wantNotes: strings.Repeat("a", maxReleaseNotesLength) + "\n...",
+			wantEvent: true,
+		},
+		{
+			name: "release without notes",
+			rls:  &helmrelease.Release{Info: &helmrelease.Info{}},
+		},
+		{
+			name: "nil release",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &v2.HelmRelease{
+				Status: v2.HelmReleaseStatus{LastReleaseNotes: "previous"},
+			}
+			recorder := record.NewFakeRecorder(1)
+			recordReleaseNotes(recorder, obj, tt.rls)
+
+			g.Expect(obj.Status.LastReleaseNotes).To(Equal(tt.wantNotes))
+			if tt.wantEvent {
+				g.Expect(recorder.Events).To(Receive(ContainSubstring(releaseNotesReason)))
+			} else {
+				g.Expect(recorder.Events).To(BeEmpty())
+			}
+		})
+	}
+}
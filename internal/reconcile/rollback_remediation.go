@@ -19,23 +19,20 @@ package reconcile
 import (
 	"context"
 	"fmt"
-	helmchartutil "helm.sh/helm/v3/pkg/chartutil"
 	"strings"
 
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
-	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/fluxcd/pkg/runtime/conditions"
-	"github.com/fluxcd/pkg/runtime/logger"
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/action"
 	"github.com/fluxcd/helm-controller/internal/digest"
 	"github.com/fluxcd/helm-controller/internal/release"
 	"github.com/fluxcd/helm-controller/internal/storage"
-	"github.com/fluxcd/pkg/chartutil"
+	intvalues "github.com/fluxcd/helm-controller/internal/values"
 )
 
 // RollbackRemediation is an ActionReconciler which attempts to roll back
@@ -76,9 +73,10 @@ func NewRollbackRemediation(configFactory *action.ConfigFactory, eventRecorder r
 
 func (r *RollbackRemediation) Reconcile(ctx context.Context, req *Request) error {
 	var (
-		cur    = req.Object.Status.History.Latest().DeepCopy()
-		logBuf = action.NewLogBuffer(action.NewDebugLog(ctrl.LoggerFrom(ctx).V(logger.DebugLevel)), 10)
-		cfg    = r.configFactory.Build(logBuf.Log, observeRollback(req.Object))
+		cur      = req.Object.Status.History.Latest().DeepCopy()
+		logBuf   = newActionLogBuffer(ctx, req.Object, r.Name())
+		progress = newWaitProgress(r.eventRecorder, req.Object, progressEventInterval, logBuf.Log)
+		cfg      = r.configFactory.Build(progress.Log, observeRollback(req.Object))
 	)
 
 	defer summarize(req)
@@ -96,7 +94,13 @@ func (r *RollbackRemediation) Reconcile(ctx context.Context, req *Request) error
 	}
 
 	// Run the Helm rollback action.
-	if err := action.Rollback(cfg, req.Object, prev.Name, action.RollbackToVersion(prev.Version)); err != nil {
+	err := action.Rollback(cfg, req.Object, prev.Name, action.RollbackToVersion(prev.Version))
+
+	// Prune the history of the release, dropping the remediated release
+	// when it failed.
+	pruneHistory(r.configFactory, r.eventRecorder, req.Object)
+
+	if err != nil {
 		r.failure(req, prev, logBuf, err)
 
 		// Return error if we did not store a release, as this does not
@@ -138,14 +142,15 @@ func (r *RollbackRemediation) failure(req *Request, prev *v2.Snapshot, buffer *a
 
 	// Mark remediation failure on object.
 	req.Object.Status.Failures++
-	conditions.MarkFalse(req.Object, v2.RemediatedCondition, v2.RollbackFailedReason, "%s", msg)
+	req.Object.Status.RollbackFailures++
+	conditions.MarkFalse(req.Object, v2.RemediatedCondition, v2.RollbackFailedReason, "%s", conditionMessageWithLog(msg, buffer))
 
 	// Record warning event, this message contains more data than the
 	// Condition summary.
 	r.eventRecorder.AnnotatedEventf(
 		req.Object,
-		eventMeta(prev.ChartVersion, chartutil.DigestValues(digest.Canonical, helmchartutil.Values(req.Values)).String(),
-			addAppVersion(prev.AppVersion), addOCIDigest(prev.OCIDigest)),
+		eventMeta(prev.ChartVersion, intvalues.Digest(digest.Canonical, req.Values).String(),
+			addAppVersion(prev.AppVersion), addOCIDigest(prev.OCIDigest), addSnapshot(prev)),
 		corev1.EventTypeWarning,
 		v2.RollbackFailedReason,
 		eventMessageWithLog(msg, buffer),
@@ -159,13 +164,14 @@ func (r *RollbackRemediation) success(req *Request, prev *v2.Snapshot) {
 	msg := fmt.Sprintf(fmtRollbackRemediationSuccess, prev.FullReleaseName(), prev.VersionedChartName())
 
 	// Mark remediation success on object.
+	req.Object.Status.RollbackFailures = 0
 	conditions.MarkTrue(req.Object, v2.RemediatedCondition, v2.RollbackSucceededReason, "%s", msg)
 
 	// Record event.
 	r.eventRecorder.AnnotatedEventf(
 		req.Object,
-		eventMeta(prev.ChartVersion, chartutil.DigestValues(digest.Canonical, helmchartutil.Values(req.Values)).String(),
-			addAppVersion(prev.AppVersion), addOCIDigest(prev.OCIDigest)),
+		eventMeta(prev.ChartVersion, intvalues.Digest(digest.Canonical, req.Values).String(),
+			addAppVersion(prev.AppVersion), addOCIDigest(prev.OCIDigest), addSnapshot(prev)),
 		corev1.EventTypeNormal,
 		v2.RollbackSucceededReason,
 		msg,
@@ -20,15 +20,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
-	. "github.com/onsi/gomega"
+	helmreleaseutil "github.com/jessesimpson36/helm/v4/pkg/release/util"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
-	helmreleaseutil "github.com/jessesimpson36/helm/v4/pkg/release/v1util"
 	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
 	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
@@ -406,6 +407,7 @@ func TestRollbackRemediation_failure(t *testing.T) {
 			*conditions.FalseCondition(v2.RemediatedCondition, v2.RollbackFailedReason, expectMsg),
 		}))
 		g.Expect(req.Object.Status.Failures).To(Equal(int64(1)))
+		g.Expect(req.Object.Status.RollbackFailures).To(Equal(int64(1)))
 		g.Expect(recorder.GetEvents()).To(ConsistOf([]corev1.Event{
 			{
 				Type:    corev1.EventTypeWarning,
@@ -415,7 +417,11 @@ func TestRollbackRemediation_failure(t *testing.T) {
 					Annotations: map[string]string{
 						eventMetaGroupKey(eventv1.MetaRevisionKey): prev.Chart.Metadata.Version,
 						eventMetaGroupKey(metaAppVersionKey):       prev.Chart.Metadata.AppVersion,
-						eventMetaGroupKey(eventv1.MetaTokenKey):    chartutil.DigestValues(digest.Canonical, req.Values).String(),
+						eventMetaGroupKey(eventv1.MetaTokenKey):    chartutil.DigestValues(digest.Canonical, req.Values.AsMap()).String(),
+						eventMetaGroupKey(metaChartNameKey):        prev.Chart.Metadata.Name,
+						eventMetaGroupKey(metaChartVersionKey):     prev.Chart.Metadata.Version,
+						eventMetaGroupKey(metaTargetNamespaceKey):  prev.Namespace,
+						eventMetaGroupKey(metaReleaseRevisionKey):  strconv.Itoa(prev.Version),
 					},
 				},
 			},
@@ -456,6 +462,7 @@ func TestRollbackRemediation_success(t *testing.T) {
 		eventRecorder: recorder,
 	}
 	req := &Request{Object: &v2.HelmRelease{}, Values: map[string]interface{}{"foo": "bar"}}
+	req.Object.Status.RollbackFailures = 2
 	r.success(req, release.ObservedToSnapshot(release.ObserveRelease(prev)))
 
 	expectMsg := fmt.Sprintf(fmtRollbackRemediationSuccess,
@@ -466,6 +473,7 @@ func TestRollbackRemediation_success(t *testing.T) {
 		*conditions.TrueCondition(v2.RemediatedCondition, v2.RollbackSucceededReason, expectMsg),
 	}))
 	g.Expect(req.Object.Status.Failures).To(Equal(int64(0)))
+	g.Expect(req.Object.Status.RollbackFailures).To(Equal(int64(0)))
 	g.Expect(recorder.GetEvents()).To(ConsistOf([]corev1.Event{
 		{
 			Type:    corev1.EventTypeNormal,
@@ -475,7 +483,11 @@ func TestRollbackRemediation_success(t *testing.T) {
 				Annotations: map[string]string{
 					eventMetaGroupKey(eventv1.MetaRevisionKey): prev.Chart.Metadata.Version,
 					eventMetaGroupKey(metaAppVersionKey):       prev.Chart.Metadata.AppVersion,
-					eventMetaGroupKey(eventv1.MetaTokenKey):    chartutil.DigestValues(digest.Canonical, req.Values).String(),
+					eventMetaGroupKey(eventv1.MetaTokenKey):    chartutil.DigestValues(digest.Canonical, req.Values.AsMap()).String(),
+					eventMetaGroupKey(metaChartNameKey):        prev.Chart.Metadata.Name,
+					eventMetaGroupKey(metaChartVersionKey):     prev.Chart.Metadata.Version,
+					eventMetaGroupKey(metaTargetNamespaceKey):  prev.Namespace,
+					eventMetaGroupKey(metaReleaseRevisionKey):  strconv.Itoa(prev.Version),
 				},
 			},
 		},
@@ -20,7 +20,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	helmchartutil "helm.sh/helm/v3/pkg/chartutil"
 
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/conditions"
@@ -33,6 +32,7 @@ import (
 	"github.com/fluxcd/helm-controller/internal/digest"
 	interrors "github.com/fluxcd/helm-controller/internal/errors"
 	"github.com/fluxcd/helm-controller/internal/postrender"
+	"github.com/fluxcd/helm-controller/internal/release"
 )
 
 // ReleaseStatus represents the status of a Helm release as determined by
@@ -91,7 +91,7 @@ type ReleaseState struct {
 // to the v2.HelmRelease object. It returns a ReleaseState that indicates
 // the status of the release, and an error if the state could not be determined.
 func DetermineReleaseState(ctx context.Context, cfg *action.ConfigFactory, req *Request) (ReleaseState, error) {
-	rls, err := action.LastRelease(cfg.Build(nil), req.Object.GetReleaseName())
+	rls, err := action.LastRelease(cfg.Store(), req.Object.GetReleaseName())
 	if err != nil {
 		if errors.Is(err, action.ErrReleaseNotFound) {
 			return ReleaseState{Status: ReleaseStatusAbsent, Reason: "no release in storage for object"}, nil
@@ -137,13 +137,8 @@ func DetermineReleaseState(ctx context.Context, cfg *action.ConfigFactory, req *
 		return ReleaseState{Status: ReleaseStatusAbsent, Reason: "found uninstalled release in storage"}, nil
 	case helmrelease.StatusDeployed:
 		// Verify the release is in sync with the desired configuration.
-		if err = action.VerifyRelease(rls, cur, req.Chart.Metadata, helmchartutil.Values(req.Values)); err != nil {
-			switch err {
-			case action.ErrChartChanged, action.ErrConfigDigest:
-				return ReleaseState{Status: ReleaseStatusOutOfSync, Reason: err.Error()}, nil
-			default:
-				return ReleaseState{Status: ReleaseStatusUnknown}, err
-			}
+		if cmp := release.Observe(req.Object, req.Chart.Metadata, req.Values, rls); !cmp.Matches() {
+			return ReleaseState{Status: ReleaseStatusOutOfSync, Reason: cmp.Reason}, nil
 		}
 
 		// Verify if postrender digest has changed if config has not been
@@ -155,10 +150,7 @@ func DetermineReleaseState(ctx context.Context, cfg *action.ConfigFactory, req *
 		// for new generations only.
 		ready := conditions.Get(req.Object, meta.ReadyCondition)
 		if ready != nil && ready.ObservedGeneration != req.Object.Generation {
-			var postrenderersDigest string
-			if req.Object.Spec.PostRenderers != nil {
-				postrenderersDigest = postrender.Digest(digest.Canonical, req.Object.Spec.PostRenderers).String()
-			}
+			postrenderersDigest := postrender.ObjectDigest(digest.Canonical, req.Object)
 			if postrenderersDigest != req.Object.Status.ObservedPostRenderersDigest {
 				return ReleaseState{Status: ReleaseStatusOutOfSync, Reason: "postrenderers digest has changed"}, nil
 			}
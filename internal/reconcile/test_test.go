@@ -20,14 +20,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
-	. "github.com/onsi/gomega"
+	helmreleaseutil "github.com/jessesimpson36/helm/v4/pkg/release/util"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
-	helmreleaseutil "github.com/jessesimpson36/helm/v4/pkg/release/v1util"
 	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
 	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
@@ -508,6 +509,10 @@ func TestTest_failure(t *testing.T) {
 						eventMetaGroupKey(eventv1.MetaRevisionKey): cur.Chart.Metadata.Version,
 						eventMetaGroupKey(metaAppVersionKey):       cur.Chart.Metadata.AppVersion,
 						eventMetaGroupKey(eventv1.MetaTokenKey):    chartutil.DigestValues(digest.Canonical, cur.Config).String(),
+						eventMetaGroupKey(metaChartNameKey):        cur.Chart.Metadata.Name,
+						eventMetaGroupKey(metaChartVersionKey):     cur.Chart.Metadata.Version,
+						eventMetaGroupKey(metaTargetNamespaceKey):  cur.Namespace,
+						eventMetaGroupKey(metaReleaseRevisionKey):  strconv.Itoa(cur.Version),
 					},
 				},
 			},
@@ -605,6 +610,10 @@ func TestTest_success(t *testing.T) {
 						eventMetaGroupKey(eventv1.MetaRevisionKey): cur.Chart.Metadata.Version,
 						eventMetaGroupKey(metaAppVersionKey):       cur.Chart.Metadata.AppVersion,
 						eventMetaGroupKey(eventv1.MetaTokenKey):    chartutil.DigestValues(digest.Canonical, cur.Config).String(),
+						eventMetaGroupKey(metaChartNameKey):        cur.Chart.Metadata.Name,
+						eventMetaGroupKey(metaChartVersionKey):     cur.Chart.Metadata.Version,
+						eventMetaGroupKey(metaTargetNamespaceKey):  cur.Namespace,
+						eventMetaGroupKey(metaReleaseRevisionKey):  strconv.Itoa(cur.Version),
 					},
 				},
 			},
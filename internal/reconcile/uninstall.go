@@ -29,7 +29,6 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/fluxcd/pkg/runtime/conditions"
-	"github.com/fluxcd/pkg/runtime/logger"
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/action"
@@ -81,7 +80,7 @@ func NewUninstall(cfg *action.ConfigFactory, recorder record.EventRecorder) *Uni
 func (r *Uninstall) Reconcile(ctx context.Context, req *Request) error {
 	var (
 		cur    = req.Object.Status.History.Latest().DeepCopy()
-		logBuf = action.NewLogBuffer(action.NewDebugLog(ctrl.LoggerFrom(ctx).V(logger.DebugLevel)), 10)
+		logBuf = newActionLogBuffer(ctx, req.Object, r.Name())
 		cfg    = r.configFactory.Build(logBuf.Log, observeUninstall(req.Object))
 	)
 
@@ -143,6 +142,14 @@ func (r *Uninstall) Reconcile(ctx context.Context, req *Request) error {
 
 	// Mark success.
 	r.success(req)
+
+	// Without a release, there are no hooks left to expose the environment
+	// to. Delete any Secret left behind by an interrupted install or upgrade.
+	if hookEnv, err := action.NewHookEnv(cfg, req.Object); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to delete hook environment")
+	} else {
+		deleteHookEnv(ctx, hookEnv)
+	}
 	return nil
 }
 
@@ -171,13 +178,13 @@ func (r *Uninstall) failure(req *Request, buffer *action.LogBuffer, err error) {
 
 	// Mark remediation failure on object.
 	req.Object.Status.Failures++
-	conditions.MarkFalse(req.Object, v2.ReleasedCondition, v2.UninstallFailedReason, "%s", msg)
+	conditions.MarkFalse(req.Object, v2.ReleasedCondition, v2.UninstallFailedReason, "%s", conditionMessageWithLog(msg, buffer))
 
 	// Record warning event, this message contains more data than the
 	// Condition summary.
 	r.eventRecorder.AnnotatedEventf(
 		req.Object,
-		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest)),
+		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest), addSnapshot(cur)),
 		corev1.EventTypeWarning, v2.UninstallFailedReason,
 		eventMessageWithLog(msg, buffer),
 	)
@@ -198,7 +205,7 @@ func (r *Uninstall) success(req *Request) {
 	// Condition summary.
 	r.eventRecorder.AnnotatedEventf(
 		req.Object,
-		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest)),
+		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest), addSnapshot(cur)),
 		corev1.EventTypeNormal,
 		v2.UninstallSucceededReason,
 		msg,
@@ -65,7 +65,7 @@ func (r *Unlock) Reconcile(_ context.Context, req *Request) error {
 	cfg := r.configFactory.Build(nil, observeUnlock(req.Object))
 
 	// Retrieve last release object.
-	rls, err := action.LastRelease(cfg, req.Object.GetReleaseName())
+	rls, err := action.LastRelease(release.NewStorageStore(cfg.Releases), req.Object.GetReleaseName())
 	if err != nil {
 		// Ignore not found error. Assume caller will decide what to do
 		// when it re-assess state to determine the next action.
@@ -119,7 +119,7 @@ func (r *Unlock) failure(req *Request, cur *v2.Snapshot, status helmrelease.Stat
 	// Record warning event.
 	r.eventRecorder.AnnotatedEventf(
 		req.Object,
-		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest)),
+		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest), addSnapshot(cur)),
 		corev1.EventTypeWarning,
 		"PendingRelease",
 		msg,
@@ -138,7 +138,7 @@ func (r *Unlock) success(req *Request, cur *v2.Snapshot, status helmrelease.Stat
 	// Record event.
 	r.eventRecorder.AnnotatedEventf(
 		req.Object,
-		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest)),
+		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest), addSnapshot(cur)),
 		corev1.EventTypeNormal,
 		"PendingRelease",
 		msg,
@@ -19,7 +19,6 @@ package reconcile
 import (
 	"context"
 	"fmt"
-	helmchartutil "helm.sh/helm/v3/pkg/chartutil"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -28,11 +27,16 @@ import (
 
 	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/logger"
+	helmaction "github.com/jessesimpson36/helm/v4/pkg/action"
+	"github.com/jessesimpson36/helm/v4/pkg/kube"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/action"
+	"github.com/fluxcd/helm-controller/internal/diff"
 	"github.com/fluxcd/helm-controller/internal/digest"
-	"github.com/fluxcd/pkg/chartutil"
+	"github.com/fluxcd/helm-controller/internal/release"
+	intvalues "github.com/fluxcd/helm-controller/internal/values"
 )
 
 // Upgrade is an ActionReconciler which attempts to upgrade a Helm release
@@ -43,7 +47,8 @@ import (
 //
 // On upgrade success, the object is marked with Released=True and emits an
 // event. In addition, the object is marked with TestSuccess=False if tests
-// are enabled to indicate we are awaiting the results.
+// are enabled to indicate we are awaiting the results, and the rendered
+// notes of the release are recorded in Status.LastReleaseNotes.
 // On failure, the object is marked with Released=False and emits a warning
 // event. Only an error which resulted in a modification to the Helm storage
 // counts towards a failure for the active remediation strategy.
@@ -66,9 +71,10 @@ func NewUpgrade(cfg *action.ConfigFactory, recorder record.EventRecorder) *Upgra
 
 func (r *Upgrade) Reconcile(ctx context.Context, req *Request) error {
 	var (
-		logBuf      = action.NewLogBuffer(action.NewDebugLog(ctrl.LoggerFrom(ctx).V(logger.DebugLevel)), 10)
+		logBuf      = newActionLogBuffer(ctx, req.Object, r.Name())
 		obsReleases = make(observedReleases)
-		cfg         = r.configFactory.Build(logBuf.Log, observeRelease(obsReleases))
+		progress    = newWaitProgress(r.eventRecorder, req.Object, progressEventInterval, logBuf.Log)
+		cfg         = r.configFactory.Build(progress.Log, observeRelease(obsReleases))
 	)
 
 	defer summarize(req)
@@ -80,12 +86,49 @@ func (r *Upgrade) Reconcile(ctx context.Context, req *Request) error {
 	conditions.Delete(req.Object, v2.TestSuccessCondition)
 	conditions.Delete(req.Object, v2.RemediatedCondition)
 
+	// Capture the current release, to summarize the changes made by the
+	// upgrade and to prune the resources removed by it.
+	var currentManifest string
+	current, err := action.LastRelease(release.NewStorageStore(cfg.Releases), req.Object.GetReleaseName())
+	if err == nil {
+		currentManifest = current.Manifest
+	}
+
+	// Expose the environment of the hooks of the upgrade, until it has
+	// completed.
+	hookEnv, err := action.NewHookEnv(cfg, req.Object)
+	if err != nil {
+		r.failure(req, logBuf, err)
+		return err
+	}
+	if err := hookEnv.Apply(ctx, req.UpgradeEnv); err != nil {
+		r.failure(req, logBuf, err)
+		return err
+	}
+	defer deleteHookEnv(ctx, hookEnv)
+
+	// Validate the rendered objects against the schema of a remote cluster,
+	// which may run a different Kubernetes version than the cluster of the
+	// controller, before running the Helm upgrade action.
+	if req.Object.Spec.KubeConfig != nil {
+		if err := action.ValidateUpgrade(ctx, cfg, req.Object, req.Chart, req.Values, kube.ManagedFieldsManager); err != nil {
+			err = fmt.Errorf("dry-run on target cluster failed: %w", err)
+			r.failure(req, logBuf, err)
+			return err
+		}
+	}
+
 	// Run the Helm upgrade action.
-	_, err := action.Upgrade(ctx, cfg, req.Object, req.Chart, req.Values)
+	rls, err := action.Upgrade(ctx, cfg, req.Object, req.Chart, req.Values)
 
 	// Record the history of releases observed during the upgrade.
 	obsReleases.recordOnObject(req.Object, mutateOCIDigest)
 
+	// Prune the history of the release if the upgrade stored a release.
+	if len(obsReleases) > 0 {
+		pruneHistory(r.configFactory, r.eventRecorder, req.Object)
+	}
+
 	if err != nil {
 		r.failure(req, logBuf, err)
 
@@ -105,7 +148,11 @@ func (r *Upgrade) Reconcile(ctx context.Context, req *Request) error {
 		return nil
 	}
 
-	r.success(req)
+	r.success(req, summarizeChanges(ctx, currentManifest, rls))
+	recordReleaseNotes(r.eventRecorder, req.Object, rls)
+	if req.Object.Spec.Prune {
+		r.prune(ctx, cfg, req, current, rls)
+	}
 	return nil
 }
 
@@ -139,14 +186,15 @@ func (r *Upgrade) failure(req *Request, buffer *action.LogBuffer, err error) {
 
 	// Mark upgrade failure on object.
 	req.Object.Status.Failures++
-	conditions.MarkFalse(req.Object, v2.ReleasedCondition, v2.UpgradeFailedReason, "%s", msg)
+	conditions.MarkFalse(req.Object, v2.ReleasedCondition, v2.UpgradeFailedReason, "%s", conditionMessageWithLog(msg, buffer))
 
 	// Record warning event, this message contains more data than the
 	// Condition summary.
 	r.eventRecorder.AnnotatedEventf(
 		req.Object,
-		eventMeta(req.Chart.Metadata.Version, chartutil.DigestValues(digest.Canonical, helmchartutil.Values(req.Values)).String(),
-			addAppVersion(req.Chart.AppVersion()), addOCIDigest(req.Object.Status.LastAttemptedRevisionDigest)),
+		eventMeta(req.Chart.Metadata.Version, intvalues.Digest(digest.Canonical, req.Values).String(),
+			addAppVersion(req.Chart.AppVersion()), addOCIDigest(req.Object.Status.LastAttemptedRevisionDigest),
+			addChart(req.Chart.Name(), req.Chart.Metadata.Version), addTargetNamespace(req.Object.GetReleaseNamespace())),
 		corev1.EventTypeWarning,
 		v2.UpgradeFailedReason,
 		eventMessageWithLog(msg, buffer),
@@ -158,7 +206,9 @@ func (r *Upgrade) failure(req *Request, buffer *action.LogBuffer, err error) {
 // event. In addition, it marks TestSuccessCondition=False when tests are
 // enabled to indicate we are awaiting test results after having made the
 // release.
-func (r *Upgrade) success(req *Request) {
+// The given summary of the changes made by the upgrade is included in the
+// event, but not in the condition message.
+func (r *Upgrade) success(req *Request, changes string) {
 	// Compose success message.
 	cur := req.Object.Status.History.Latest()
 	msg := fmt.Sprintf(fmtUpgradeSuccess, cur.FullReleaseName(), cur.VersionedChartName())
@@ -173,9 +223,58 @@ func (r *Upgrade) success(req *Request) {
 	// Record event.
 	r.eventRecorder.AnnotatedEventf(
 		req.Object,
-		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest)),
+		eventMeta(cur.ChartVersion, cur.ConfigDigest, addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest), addSnapshot(cur)),
 		corev1.EventTypeNormal,
 		v2.UpgradeSucceededReason,
-		msg,
+		eventMessageWithChanges(msg, changes),
 	)
 }
+
+// maxChangedResources is the maximum number of resources included in the
+// summary of the changes made by an upgrade.
+const maxChangedResources = 20
+
+// summarizeChanges returns a summary of the resources added, changed and
+// removed by the upgraded release, compared to the given manifest of the
+// release before the upgrade. It returns an empty string if the changes can
+// not be determined.
+func summarizeChanges(ctx context.Context, current string, upgraded *helmrelease.Release) string {
+	if upgraded == nil {
+		return ""
+	}
+	changes, err := diff.Manifests(current, upgraded.Manifest)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).V(logger.DebugLevel).Info(fmt.Sprintf("failed to summarize changes of upgrade: %s", err))
+		return ""
+	}
+	return changes.Summarize(maxChangedResources)
+}
+
+// eventMessageWithChanges returns an event message composed of the given
+// message and the summary of the changes, if any.
+func eventMessageWithChanges(msg, changes string) string {
+	if changes == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s\n\nChanges: %s", msg, changes)
+}
+
+// prune deletes the resources of the previous release which are absent from
+// the upgraded release, and emits an event for the result. A failure to
+// prune does not fail the upgrade, as the release itself has been made.
+func (r *Upgrade) prune(ctx context.Context, cfg *helmaction.Configuration, req *Request,
+	previous, upgraded *helmrelease.Release) {
+	changeSet, err := action.Prune(ctx, cfg, previous, upgraded)
+
+	cur := req.Object.Status.History.Latest()
+	switch {
+	case err != nil:
+		r.eventRecorder.AnnotatedEventf(req.Object, eventMeta(cur.ChartVersion, cur.ConfigDigest,
+			addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest), addSnapshot(cur)), corev1.EventTypeWarning,
+			"PruneFailed", "Failed to prune resources removed from release %s: %s", cur.FullReleaseName(), err)
+	case changeSet != nil && len(changeSet.Entries) > 0:
+		r.eventRecorder.AnnotatedEventf(req.Object, eventMeta(cur.ChartVersion, cur.ConfigDigest,
+			addAppVersion(cur.AppVersion), addOCIDigest(cur.OCIDigest), addSnapshot(cur)), corev1.EventTypeNormal,
+			"Pruned", "Pruned resources removed from release %s:\n%s", cur.FullReleaseName(), changeSet.String())
+	}
+}
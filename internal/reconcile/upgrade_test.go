@@ -20,16 +20,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
-	. "github.com/onsi/gomega"
 	helmchart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
 	helmchartutil "github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
+	helmreleaseutil "github.com/jessesimpson36/helm/v4/pkg/release/util"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
-	helmreleaseutil "github.com/jessesimpson36/helm/v4/pkg/release/v1util"
 	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
 	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -643,7 +644,10 @@ func TestUpgrade_failure(t *testing.T) {
 						eventMetaGroupKey(metaOCIDigestKey):        obj.Status.LastAttemptedRevisionDigest,
 						eventMetaGroupKey(eventv1.MetaRevisionKey): chrt.Metadata.Version,
 						eventMetaGroupKey(metaAppVersionKey):       chrt.Metadata.AppVersion,
-						eventMetaGroupKey(eventv1.MetaTokenKey):    chartutil.DigestValues(digest.Canonical, req.Values).String(),
+						eventMetaGroupKey(eventv1.MetaTokenKey):    chartutil.DigestValues(digest.Canonical, req.Values.AsMap()).String(),
+						eventMetaGroupKey(metaChartNameKey):        chrt.Name(),
+						eventMetaGroupKey(metaChartVersionKey):     chrt.Metadata.Version,
+						eventMetaGroupKey(metaTargetNamespaceKey):  obj.GetReleaseNamespace(),
 					},
 				},
 			},
@@ -697,7 +701,7 @@ func TestUpgrade_success(t *testing.T) {
 		req := &Request{
 			Object: obj.DeepCopy(),
 		}
-		r.success(req)
+		r.success(req, "")
 
 		expectMsg := fmt.Sprintf(fmtUpgradeSuccess,
 			fmt.Sprintf("%s/%s.v%d", mockReleaseNamespace, mockReleaseName, obj.Status.History.Latest().Version),
@@ -716,12 +720,41 @@ func TestUpgrade_success(t *testing.T) {
 						eventMetaGroupKey(eventv1.MetaRevisionKey): obj.Status.History.Latest().ChartVersion,
 						eventMetaGroupKey(metaAppVersionKey):       obj.Status.History.Latest().AppVersion,
 						eventMetaGroupKey(eventv1.MetaTokenKey):    obj.Status.History.Latest().ConfigDigest,
+						eventMetaGroupKey(metaChartNameKey):        obj.Status.History.Latest().ChartName,
+						eventMetaGroupKey(metaChartVersionKey):     obj.Status.History.Latest().ChartVersion,
+						eventMetaGroupKey(metaTargetNamespaceKey):  obj.Status.History.Latest().Namespace,
+						eventMetaGroupKey(metaReleaseRevisionKey):  strconv.Itoa(obj.Status.History.Latest().Version),
 					},
 				},
 			},
 		}))
 	})
 
+	t.Run("records success with changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := testutil.NewFakeRecorder(10, false)
+		r := &Upgrade{
+			eventRecorder: recorder,
+		}
+
+		req := &Request{
+			Object: obj.DeepCopy(),
+		}
+		r.success(req, "1 added, 0 changed, 0 removed\nService/default/podinfo added")
+
+		expectMsg := fmt.Sprintf(fmtUpgradeSuccess,
+			fmt.Sprintf("%s/%s.v%d", mockReleaseNamespace, mockReleaseName, obj.Status.History.Latest().Version),
+			fmt.Sprintf("%s@%s", obj.Status.History.Latest().ChartName, obj.Status.History.Latest().ChartVersion))
+
+		g.Expect(conditions.GetMessage(req.Object, v2.ReleasedCondition)).To(Equal(expectMsg))
+
+		events := recorder.GetEvents()
+		g.Expect(events).To(HaveLen(1))
+		g.Expect(events[0].Message).To(Equal(expectMsg +
+			"\n\nChanges: 1 added, 0 changed, 0 removed\nService/default/podinfo added"))
+	})
+
 	t.Run("records success with TestSuccess=False", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -734,7 +767,7 @@ func TestUpgrade_success(t *testing.T) {
 		obj.Spec.Test = &v2.Test{Enable: true}
 
 		req := &Request{Object: obj}
-		r.success(req)
+		r.success(req, "")
 
 		g.Expect(conditions.IsTrue(req.Object, v2.ReleasedCondition)).To(BeTrue())
 
@@ -747,3 +780,26 @@ func TestUpgrade_success(t *testing.T) {
 		g.Expect(cond.Message).To(Equal(expectMsg))
 	})
 }
+
+func Test_summarizeChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	current := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: podinfo
+data:
+  key: value
+`
+	upgraded := &helmrelease.Release{Manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: podinfo
+data:
+  key: changed
+`}
+
+	g.Expect(summarizeChanges(context.TODO(), current, upgraded)).To(Equal("0 added, 1 changed, 0 removed\nConfigMap/podinfo changed"))
+	g.Expect(summarizeChanges(context.TODO(), current, nil)).To(BeEmpty())
+	g.Expect(summarizeChanges(context.TODO(), "invalid: [", upgraded)).To(BeEmpty())
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"fmt"
+
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+
+	"github.com/fluxcd/helm-controller/internal/diff"
+)
+
+// Changes holds the differences between two versions of a release.
+type Changes struct {
+	// Chart describes the change of the chart in the format of
+	// '<name>@<version> -> <name>@<version>'. It is empty when the chart
+	// did not change.
+	Chart string `json:"chart,omitempty"`
+	// Values holds the changes to the values of the release.
+	Values diff.ValuesChanges `json:"values,omitempty"`
+	// Manifest holds the changes to the resources of the release.
+	Manifest diff.ManifestChanges `json:"manifest,omitempty"`
+}
+
+// Empty returns true if there are no changes.
+func (c Changes) Empty() bool {
+	return c.Chart == "" && c.Values.Empty() && c.Manifest.Empty()
+}
+
+// Diff returns the Changes between the current and desired release. A nil
+// current release is treated as an empty release.
+func Diff(current, desired *helmrelease.Release) (Changes, error) {
+	if current == nil {
+		current = &helmrelease.Release{}
+	}
+	if desired == nil {
+		desired = &helmrelease.Release{}
+	}
+
+	var changes Changes
+	if from, to := versionedChartName(current), versionedChartName(desired); from != to {
+		changes.Chart = fmt.Sprintf("%s -> %s", from, to)
+	}
+	changes.Values = diff.Values(current.Config, desired.Config)
+
+	manifest, err := diff.Manifests(current.Manifest, desired.Manifest)
+	if err != nil {
+		return Changes{}, fmt.Errorf("failed to compare manifests of releases: %w", err)
+	}
+	changes.Manifest = manifest
+	return changes, nil
+}
+
+// Summarize returns a one-line summary of the given release in the format of
+// '<namespace>/<name>.v<version> with chart <name>@<version> (<status>)'.
+func Summarize(rls *helmrelease.Release) string {
+	if rls == nil {
+		return ""
+	}
+	var status helmrelease.Status
+	if rls.Info != nil {
+		status = rls.Info.Status
+	}
+	return fmt.Sprintf("%s/%s.v%d with chart %s (%s)", rls.Namespace, rls.Name, rls.Version,
+		versionedChartName(rls), status)
+}
+
+// versionedChartName returns the chart of the given release in the format
+// of '<name>@<version>', or an empty string when the release has no chart
+// metadata.
+func versionedChartName(rls *helmrelease.Release) string {
+	if rls.Chart == nil || rls.Chart.Metadata == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s@%s", rls.Chart.Metadata.Name, rls.Chart.Metadata.Version)
+}
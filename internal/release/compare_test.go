@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+const (
+	compareManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+data:
+  foo: bar
+`
+	compareManifestExtra = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: extra
+  namespace: default
+`
+)
+
+func TestDiff(t *testing.T) {
+	current := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+		Name:      "podinfo",
+		Version:   1,
+		Status:    helmrelease.StatusDeployed,
+		Namespace: "default",
+	}, testutil.ReleaseWithConfig(map[string]interface{}{"name": "value"}))
+	current.Manifest = compareManifest
+
+	t.Run("no changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		changes, err := Diff(current, current)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(changes.Empty()).To(BeTrue())
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		desired := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+			Name:      "podinfo",
+			Version:   2,
+			Status:    helmrelease.StatusPendingUpgrade,
+			Namespace: "default",
+		}, testutil.ReleaseWithConfig(map[string]interface{}{"name": "other", "replicas": 2}))
+		desired.Chart.Metadata.Version = "0.2.0"
+		desired.Manifest = compareManifest + compareManifestExtra
+
+		changes, err := Diff(current, desired)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(changes.Empty()).To(BeFalse())
+		g.Expect(changes.Chart).To(Equal("foo@0.1.0-beta.1 -> foo@0.2.0"))
+		g.Expect(changes.Values.Changed).To(Equal([]string{"name"}))
+		g.Expect(changes.Values.Added).To(Equal([]string{"replicas"}))
+		g.Expect(changes.Manifest.Added).To(ConsistOf(ContainSubstring("extra")))
+		g.Expect(changes.Manifest.Changed).To(BeEmpty())
+	})
+
+	t.Run("no current release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		changes, err := Diff(nil, current)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(changes.Chart).To(Equal(" -> foo@0.1.0-beta.1"))
+		g.Expect(changes.Values.Added).To(Equal([]string{"name"}))
+		g.Expect(changes.Manifest.Added).To(HaveLen(1))
+	})
+}
+
+func TestSummarize(t *testing.T) {
+	g := NewWithT(t)
+
+	rls := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+		Name:      "podinfo",
+		Version:   3,
+		Status:    helmrelease.StatusDeployed,
+		Namespace: "default",
+	})
+	g.Expect(Summarize(rls)).To(Equal("default/podinfo.v3 with chart foo@0.1.0-beta.1 (deployed)"))
+	g.Expect(Summarize(nil)).To(BeEmpty())
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+
+	rspb "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+)
+
+var (
+	b64       = base64.StdEncoding
+	magicGzip = []byte{0x1f, 0x8b, 0x08}
+)
+
+// Decode decodes the data of a Helm storage record into a release
+// type. Data must contain a base64 encoded gzipped string of a
+// valid release, otherwise an error is returned.
+//
+// It is copied over from the Helm project to be able to deal
+// with encoded releases.
+// Ref: https://github.com/helm/helm/blob/v3.9.0/pkg/storage/driver/util.go#L56
+func Decode(data string) (*rspb.Release, error) {
+	// base64 decode string
+	b, err := b64.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < len(magicGzip) {
+		return nil, errors.New("release data is too short")
+	}
+
+	// For backwards compatibility with releases that were stored before
+	// compression was introduced we skip decompression if the
+	// gzip magic header is not found
+	if bytes.Equal(b[0:3], magicGzip) {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		b2, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		b = b2
+	}
+
+	var rls rspb.Release
+	// unmarshal release object bytes
+	if err := json.Unmarshal(b, &rls); err != nil {
+		return nil, err
+	}
+	return &rls, nil
+}
+
+// Encode encodes the given release into the data of a Helm storage record,
+// as a base64 encoded gzipped string. It is the counterpart of Decode, and
+// allows e.g. tests to write synthetic storage records.
+//
+// It is copied over from the Helm project.
+// Ref: https://github.com/helm/helm/blob/v3.9.0/pkg/storage/driver/util.go#L35
+func Encode(rls *rspb.Release) (string, error) {
+	b, err := json.Marshal(rls)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err = w.Write(b); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+	return b64.EncodeToString(buf.Bytes()), nil
+}
@@ -1,5 +1,5 @@
 /*
-Copyright 2022 The Flux authors
+Copyright 2025 The Flux authors
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -19,52 +19,118 @@ package release
 import (
 	"bytes"
 	"compress/gzip"
-	"encoding/base64"
 	"encoding/json"
-	"io"
+	"testing"
 
 	rspb "github.com/jessesimpson36/helm/v4/pkg/release/v1"
-)
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
-var (
-	b64       = base64.StdEncoding
-	magicGzip = []byte{0x1f, 0x8b, 0x08}
+	"github.com/fluxcd/helm-controller/internal/testutil"
 )
 
-// decodeRelease decodes the bytes of data into a release
-// type. Data must contain a base64 encoded gzipped string of a
-// valid release, otherwise an error is returned.
-//
-// It is copied over from the Helm project to be able to deal
-// with encoded releases.
-// Ref: https://github.com/helm/helm/blob/v3.9.0/pkg/storage/driver/util.go#L56
-func decodeRelease(data string) (*rspb.Release, error) {
-	// base64 decode string
-	b, err := b64.DecodeString(data)
+func TestDecode(t *testing.T) {
+	rls := &rspb.Release{Name: "podinfo", Namespace: "default", Version: 3}
+	b, err := json.Marshal(rls)
 	if err != nil {
-		return nil, err
+		t.Fatal(err)
+	}
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	if _, err = w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
 	}
 
-	// For backwards compatibility with releases that were stored before
-	// compression was introduced we skip decompression if the
-	// gzip magic header is not found
-	if bytes.Equal(b[0:3], magicGzip) {
-		r, err := gzip.NewReader(bytes.NewReader(b))
-		if err != nil {
-			return nil, err
-		}
-		defer r.Close()
-		b2, err := io.ReadAll(r)
-		if err != nil {
-			return nil, err
-		}
-		b = b2
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "gzipped release",
+			data: b64.EncodeToString(gz.Bytes()),
+		},
+		{
+			name: "uncompressed release",
+			data: b64.EncodeToString(b),
+		},
+		{
+			name:    "invalid base64",
+			data:    "invalid!",
+			wantErr: true,
+		},
+		{
+			name:    "too short",
+			data:    b64.EncodeToString([]byte("{")),
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			data:    b64.EncodeToString([]byte("invalid")),
+			wantErr: true,
+		},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
 
-	var rls rspb.Release
-	// unmarshal release object bytes
-	if err := json.Unmarshal(b, &rls); err != nil {
-		return nil, err
+			got, err := Decode(tt.data)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Name).To(Equal(rls.Name))
+			g.Expect(got.Namespace).To(Equal(rls.Namespace))
+			g.Expect(got.Version).To(Equal(rls.Version))
+		})
 	}
-	return &rls, nil
+}
+
+func TestEncode(t *testing.T) {
+	g := NewWithT(t)
+
+	rls := testutil.BuildRelease(&rspb.MockReleaseOptions{
+		Name:      "podinfo",
+		Version:   1,
+		Status:    rspb.StatusDeployed,
+		Namespace: "default",
+	})
+
+	data, err := Encode(rls)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	got, err := Decode(data)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(Summarize(got)).To(Equal(Summarize(rls)))
+	g.Expect(got.Manifest).To(Equal(rls.Manifest))
+	g.Expect(got.Config).To(Equal(rls.Config))
+
+	// A synthetic storage record written with the encoded release can be
+	// read by the Helm Secrets storage driver.
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1.podinfo.v1",
+			Namespace: "default",
+			Labels: map[string]string{
+				"name":    "podinfo",
+				"owner":   "helm",
+				"status":  "deployed",
+				"version": "1",
+			},
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{"release": []byte(data)},
+	}).CoreV1().Secrets("default")
+
+	stored, err := NewSecretStore(client).LastDeployed("podinfo")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(stored.Version).To(Equal(1))
+	g.Expect(stored.Manifest).To(Equal(rls.Manifest))
 }
@@ -19,6 +19,7 @@ package release
 import (
 	"encoding/json"
 
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 	"github.com/opencontainers/go-digest"
 )
 
@@ -34,3 +35,13 @@ func Digest(algo digest.Algorithm, rel Observation) digest.Digest {
 	}
 	return digester.Digest()
 }
+
+// DigestRelease calculates the digest of the Observation of the given
+// release, with the given OCI digest of the chart artifact (if any). The
+// result equals the digest of the v2.Snapshot recorded for the release,
+// allowing the release in the storage to be compared against it.
+func DigestRelease(algo digest.Algorithm, rls *helmrelease.Release, ociDigest string) digest.Digest {
+	obs := ObserveRelease(rls)
+	obs.OCIDigest = ociDigest
+	return Digest(algo, obs)
+}
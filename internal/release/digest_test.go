@@ -19,9 +19,12 @@ package release
 import (
 	"testing"
 
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 	. "github.com/onsi/gomega"
 
 	"github.com/opencontainers/go-digest"
+
+	"github.com/fluxcd/helm-controller/internal/testutil"
 )
 
 func TestDigest(t *testing.T) {
@@ -49,3 +52,21 @@ func TestDigest(t *testing.T) {
 		})
 	}
 }
+
+func TestDigestRelease(t *testing.T) {
+	g := NewWithT(t)
+
+	rls := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+		Name:      "podinfo",
+		Version:   1,
+		Status:    helmrelease.StatusDeployed,
+		Namespace: "default",
+	})
+
+	obs := ObserveRelease(rls)
+	obs.OCIDigest = "sha256:foo"
+	snapshot := ObservedToSnapshot(obs)
+
+	g.Expect(DigestRelease(digest.SHA256, rls, "sha256:foo").String()).To(Equal(snapshot.Digest))
+	g.Expect(DigestRelease(digest.SHA256, rls, "").String()).ToNot(Equal(snapshot.Digest))
+}
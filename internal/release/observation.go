@@ -19,15 +19,18 @@ package release
 import (
 	"encoding/json"
 	"io"
+	"regexp"
+	"strings"
 
 	"github.com/mitchellh/copystructure"
 	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
 	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 
 	v2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/helm-controller/internal/digest"
-	"github.com/fluxcd/pkg/chartutil"
+	intvalues "github.com/fluxcd/helm-controller/internal/values"
 )
 
 var (
@@ -56,6 +59,51 @@ func IgnoreHookTestEvents(rel *Observation) {
 	}
 }
 
+// HideSecrets removes the Secret resources from the manifests of the release
+// and its hooks. For example, to prevent the rendered content of Secrets from
+// being surfaced outside the Helm storage object.
+//
+// It must not be used while generating a digest for the object, as it
+// changes the outcome.
+func HideSecrets(rel *Observation) {
+	rel.Manifest = HideSecretsInManifest(rel.Manifest)
+	for i := range rel.Hooks {
+		rel.Hooks[i].Manifest = HideSecretsInManifest(rel.Hooks[i].Manifest)
+	}
+}
+
+// manifestSeparator matches the YAML document separator in a (multi-document)
+// manifest.
+var manifestSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// HideSecretsInManifest returns the given (multi-document) YAML manifest
+// without any documents of kind Secret.
+func HideSecretsInManifest(manifest string) string {
+	if !strings.Contains(manifest, "Secret") {
+		return manifest
+	}
+
+	docs := manifestSeparator.Split(manifest, -1)
+	kept := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		if isSecretManifest(doc) {
+			continue
+		}
+		kept = append(kept, doc)
+	}
+	return strings.Join(kept, "---")
+}
+
+// isSecretManifest returns true if the YAML document describes a core/v1
+// Secret.
+func isSecretManifest(doc string) bool {
+	var typeMeta metav1.TypeMeta
+	if err := yaml.Unmarshal([]byte(doc), &typeMeta); err != nil {
+		return false
+	}
+	return typeMeta.Kind == "Secret" && typeMeta.APIVersion == "v1"
+}
+
 // Observation is a copy of a Helm release object, as observed to be written
 // to the storage by a storage.Observer. The object is detached from the Helm
 // storage object, and mutations to it do not change the underlying release
@@ -164,7 +212,7 @@ func ObservedToSnapshot(rls Observation) *v2.Snapshot {
 		AppVersion:    rls.ChartMetadata.AppVersion,
 		ChartName:     rls.ChartMetadata.Name,
 		ChartVersion:  rls.ChartMetadata.Version,
-		ConfigDigest:  chartutil.DigestValues(digest.Canonical, rls.Config).String(),
+		ConfigDigest:  intvalues.Digest(digest.Canonical, rls.Config).String(),
 		FirstDeployed: metav1.NewTime(rls.Info.FirstDeployed.Time),
 		LastDeployed:  metav1.NewTime(rls.Info.LastDeployed.Time),
 		Deleted:       metav1.NewTime(rls.Info.Deleted.Time),
@@ -91,6 +91,75 @@ func TestIgnoreHookTestEvents(t *testing.T) {
 	}
 }
 
+func TestHideSecrets(t *testing.T) {
+	const configMap = `
+# Source: chart/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  foo: bar
+`
+	const secret = `
+# Source: chart/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+stringData:
+  password: hunter2
+`
+
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{
+			name:     "removes Secrets",
+			manifest: "---" + configMap + "---" + secret + "---" + configMap,
+			want:     "---" + configMap + "---" + configMap,
+		},
+		{
+			name:     "only Secrets",
+			manifest: "---" + secret,
+			want:     "",
+		},
+		{
+			name:     "no Secrets",
+			manifest: "---" + configMap,
+			want:     "---" + configMap,
+		},
+		{
+			name:     "Secret of other API group",
+			manifest: "---\napiVersion: example.com/v1\nkind: Secret\n",
+			want:     "---\napiVersion: example.com/v1\nkind: Secret\n",
+		},
+		{
+			name:     "empty manifest",
+			manifest: "",
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obs := Observation{
+				Manifest: tt.manifest,
+				Hooks: []helmrelease.Hook{
+					{Name: "hook", Manifest: tt.manifest},
+				},
+			}
+			HideSecrets(&obs)
+			g.Expect(obs.Manifest).To(Equal(tt.want))
+			g.Expect(obs.Hooks[0].Manifest).To(Equal(tt.want))
+			g.Expect(obs.Manifest).ToNot(ContainSubstring("hunter2"))
+		})
+	}
+}
+
 func TestObservation_Targets(t *testing.T) {
 	tests := []struct {
 		name            string
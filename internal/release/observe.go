@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"fmt"
+
+	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	"github.com/opencontainers/go-digest"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	intvalues "github.com/fluxcd/helm-controller/internal/values"
+)
+
+// Action is the Helm action required to bring a release in the storage in
+// line with the desired state of a v2.HelmRelease.
+type Action string
+
+const (
+	// ActionNone indicates the release matches the desired state.
+	ActionNone Action = "none"
+	// ActionInstall indicates there is no (installed) release for the
+	// desired state, and a new release must be installed.
+	ActionInstall Action = "install"
+	// ActionUpgrade indicates the release does not match the desired state,
+	// and must be upgraded.
+	ActionUpgrade Action = "upgrade"
+)
+
+// Comparison is the result of comparing a release in the storage with the
+// desired state of a v2.HelmRelease.
+type Comparison struct {
+	// Action is the Helm action required to bring the release in line with
+	// the desired state.
+	Action Action
+	// Reason for the Action. Empty when Action is ActionNone.
+	Reason string
+
+	// NameMatch is true if the name of the release matches the release
+	// name of the object.
+	NameMatch bool
+	// NamespaceMatch is true if the namespace of the release matches the
+	// release namespace of the object.
+	NamespaceMatch bool
+	// ChartMatch is true if the chart name and version of the release match
+	// the desired chart metadata.
+	ChartMatch bool
+	// ValuesMatch is true if the config digest of the latest release
+	// Snapshot of the object matches the digest of the desired values.
+	ValuesMatch bool
+}
+
+// Matches returns true if the release matches the desired state.
+func (c Comparison) Matches() bool {
+	return c.Action == ActionNone
+}
+
+// Observe compares the given release from the storage with the desired state
+// of the given object, as described by its release name and namespace, the
+// metadata of the chart to release, and the composed values. It returns a
+// Comparison with the Helm action required to bring the release in line
+// with the desired state.
+//
+// The values are compared against the config digest of the latest Snapshot
+// in the history of the object, which records the values the object was
+// last released with. An object without a Snapshot is therefore considered
+// to have changed values.
+//
+// A nil, uninstalled or foreign release (with a different name or namespace)
+// results in ActionInstall, while a change to the chart name or version, or
+// the values results in ActionUpgrade. Any further state of the release
+// (e.g. a pending or failed status, or drift in the cluster) is not taken
+// into account, and must be handled by the caller.
+func Observe(obj *v2.HelmRelease, metadata *chart.Metadata, values map[string]interface{}, rls *helmrelease.Release) Comparison {
+	if rls == nil {
+		return Comparison{Action: ActionInstall, Reason: "no release in storage"}
+	}
+
+	c := Comparison{
+		NameMatch:      rls.Name == ShortenName(obj.GetReleaseName()),
+		NamespaceMatch: rls.Namespace == obj.GetReleaseNamespace(),
+		ChartMatch:     chartMatches(rls, metadata),
+		ValuesMatch:    valuesMatch(obj.Status.History.Latest(), values),
+	}
+
+	switch {
+	case !c.NameMatch:
+		c.Action, c.Reason = ActionInstall, fmt.Sprintf("release name '%s' does not match '%s'", rls.Name, ShortenName(obj.GetReleaseName()))
+	case !c.NamespaceMatch:
+		c.Action, c.Reason = ActionInstall, fmt.Sprintf("release namespace '%s' does not match '%s'", rls.Namespace, obj.GetReleaseNamespace())
+	case rls.Info != nil && rls.Info.Status == helmrelease.StatusUninstalled:
+		c.Action, c.Reason = ActionInstall, "release has been uninstalled"
+	case !c.ChartMatch:
+		c.Action, c.Reason = ActionUpgrade, "release chart changed"
+	case !c.ValuesMatch:
+		c.Action, c.Reason = ActionUpgrade, "release config values changed"
+	default:
+		c.Action = ActionNone
+	}
+	return c
+}
+
+// valuesMatch returns true if the config digest of the given snapshot
+// matches the given values. A nil snapshot never matches.
+func valuesMatch(snapshot *v2.Snapshot, values map[string]interface{}) bool {
+	if snapshot == nil {
+		return false
+	}
+	return intvalues.Verify(digest.Digest(snapshot.ConfigDigest), values)
+}
+
+// chartMatches returns true if the chart name and version of the given
+// release equal the given chart metadata. A nil metadata always matches.
+func chartMatches(rls *helmrelease.Release, metadata *chart.Metadata) bool {
+	if metadata == nil {
+		return true
+	}
+	if rls.Chart == nil || rls.Chart.Metadata == nil {
+		return false
+	}
+	return rls.Chart.Metadata.Name == metadata.Name && rls.Chart.Metadata.Version == metadata.Version
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+func TestObserve(t *testing.T) {
+	values := map[string]interface{}{"foo": "bar"}
+
+	mockRelease := func(status helmrelease.Status, mutate ...func(*helmrelease.Release)) *helmrelease.Release {
+		rls := testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+			Name:      "podinfo",
+			Namespace: "default",
+			Version:   1,
+			Status:    status,
+			Chart:     testutil.BuildChart(),
+		}, testutil.ReleaseWithConfig(map[string]interface{}{"foo": "bar"}))
+		for _, m := range mutate {
+			m(rls)
+		}
+		return rls
+	}
+
+	newObject := func(history ...*v2.Snapshot) *v2.HelmRelease {
+		return &v2.HelmRelease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "podinfo",
+				Namespace: "flux-system",
+			},
+			Spec: v2.HelmReleaseSpec{
+				ReleaseName:     "podinfo",
+				TargetNamespace: "default",
+			},
+			Status: v2.HelmReleaseStatus{
+				History: history,
+			},
+		}
+	}
+	snapshot := ObservedToSnapshot(ObserveRelease(mockRelease(helmrelease.StatusDeployed)))
+
+	tests := []struct {
+		name       string
+		noSnapshot bool
+		metadata   *chart.Metadata
+		values     map[string]interface{}
+		rls        *helmrelease.Release
+		want       Comparison
+	}{
+		{
+			name:     "matches",
+			metadata: testutil.BuildChart().Metadata,
+			values:   values,
+			rls:      mockRelease(helmrelease.StatusDeployed),
+			want: Comparison{
+				Action:         ActionNone,
+				NameMatch:      true,
+				NamespaceMatch: true,
+				ChartMatch:     true,
+				ValuesMatch:    true,
+			},
+		},
+		{
+			name:     "nil chart metadata matches any chart",
+			metadata: nil,
+			values:   values,
+			rls:      mockRelease(helmrelease.StatusDeployed),
+			want: Comparison{
+				Action:         ActionNone,
+				NameMatch:      true,
+				NamespaceMatch: true,
+				ChartMatch:     true,
+				ValuesMatch:    true,
+			},
+		},
+		{
+			name:     "no release",
+			metadata: testutil.BuildChart().Metadata,
+			values:   values,
+			rls:      nil,
+			want: Comparison{
+				Action: ActionInstall,
+				Reason: "no release in storage",
+			},
+		},
+		{
+			name:     "uninstalled release",
+			metadata: testutil.BuildChart().Metadata,
+			values:   values,
+			rls:      mockRelease(helmrelease.StatusUninstalled),
+			want: Comparison{
+				Action:         ActionInstall,
+				Reason:         "release has been uninstalled",
+				NameMatch:      true,
+				NamespaceMatch: true,
+				ChartMatch:     true,
+				ValuesMatch:    true,
+			},
+		},
+		{
+			name:     "release name differs",
+			metadata: testutil.BuildChart().Metadata,
+			values:   values,
+			rls: mockRelease(helmrelease.StatusDeployed, func(rls *helmrelease.Release) {
+				rls.Name = "other"
+			}),
+			want: Comparison{
+				Action:         ActionInstall,
+				Reason:         "release name 'other' does not match 'podinfo'",
+				NamespaceMatch: true,
+				ChartMatch:     true,
+				ValuesMatch:    true,
+			},
+		},
+		{
+			name:     "release namespace differs",
+			metadata: testutil.BuildChart().Metadata,
+			values:   values,
+			rls: mockRelease(helmrelease.StatusDeployed, func(rls *helmrelease.Release) {
+				rls.Namespace = "other"
+			}),
+			want: Comparison{
+				Action:      ActionInstall,
+				Reason:      "release namespace 'other' does not match 'default'",
+				NameMatch:   true,
+				ChartMatch:  true,
+				ValuesMatch: true,
+			},
+		},
+		{
+			name:     "chart name changed",
+			metadata: testutil.BuildChart(testutil.ChartWithName("other")).Metadata,
+			values:   values,
+			rls:      mockRelease(helmrelease.StatusDeployed),
+			want: Comparison{
+				Action:         ActionUpgrade,
+				Reason:         "release chart changed",
+				NameMatch:      true,
+				NamespaceMatch: true,
+				ValuesMatch:    true,
+			},
+		},
+		{
+			name:     "chart version changed",
+			metadata: testutil.BuildChart(testutil.ChartWithVersion("9.9.9")).Metadata,
+			values:   values,
+			rls:      mockRelease(helmrelease.StatusDeployed),
+			want: Comparison{
+				Action:         ActionUpgrade,
+				Reason:         "release chart changed",
+				NameMatch:      true,
+				NamespaceMatch: true,
+				ValuesMatch:    true,
+			},
+		},
+		{
+			name:     "values changed",
+			metadata: testutil.BuildChart().Metadata,
+			values:   map[string]interface{}{"bar": "foo"},
+			rls:      mockRelease(helmrelease.StatusDeployed),
+			want: Comparison{
+				Action:         ActionUpgrade,
+				Reason:         "release config values changed",
+				NameMatch:      true,
+				NamespaceMatch: true,
+				ChartMatch:     true,
+			},
+		},
+		{
+			name:       "no release snapshot",
+			noSnapshot: true,
+			metadata:   testutil.BuildChart().Metadata,
+			values:     values,
+			rls:        mockRelease(helmrelease.StatusDeployed),
+			want: Comparison{
+				Action:         ActionUpgrade,
+				Reason:         "release config values changed",
+				NameMatch:      true,
+				NamespaceMatch: true,
+				ChartMatch:     true,
+			},
+		},
+		{
+			name:     "failed release with unchanged config",
+			metadata: testutil.BuildChart().Metadata,
+			values:   values,
+			rls:      mockRelease(helmrelease.StatusFailed),
+			want: Comparison{
+				Action:         ActionNone,
+				NameMatch:      true,
+				NamespaceMatch: true,
+				ChartMatch:     true,
+				ValuesMatch:    true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := newObject(snapshot)
+			if tt.noSnapshot {
+				obj = newObject()
+			}
+
+			got := Observe(obj, tt.metadata, tt.values, tt.rls)
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(got.Matches()).To(Equal(tt.want.Action == ActionNone))
+		})
+	}
+}
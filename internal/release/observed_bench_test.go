@@ -19,8 +19,8 @@ package release
 import (
 	"testing"
 
+	release "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 	"github.com/opencontainers/go-digest"
-	"github.com/jessesimpson36/helm/v4/pkg/release/v1"
 
 	intdigest "github.com/fluxcd/helm-controller/internal/digest"
 )
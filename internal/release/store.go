@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"errors"
+
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
+	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// Store provides read access to the releases in a Helm storage. It allows
+// code which inspects releases to be tested against an in-memory storage,
+// without the need for a live cluster, and alternative storage backends to
+// be used without changes to the code reading from it.
+//
+// All methods return an error which is helmdriver.ErrReleaseNotFound when
+// no (matching) release exists.
+type Store interface {
+	// Get returns the release with the given name and version.
+	Get(name string, version int) (*helmrelease.Release, error)
+	// Last returns the latest version of the release with the given name,
+	// regardless of its status.
+	Last(name string) (*helmrelease.Release, error)
+	// History returns all versions of the release with the given name.
+	History(name string) ([]*helmrelease.Release, error)
+	// LastDeployed returns the version of the release with the given name
+	// which was deployed last.
+	LastDeployed(name string) (*helmrelease.Release, error)
+	// Observe returns the Observation of the release with the given name
+	// and version, with the given filters applied.
+	Observe(name string, version int, filter ...DataFilter) (Observation, error)
+}
+
+// NewStore returns a Store backed by the given Helm storage driver.
+func NewStore(driver helmdriver.Driver) Store {
+	return NewStorageStore(helmstorage.Init(driver))
+}
+
+// NewStorageStore returns a Store backed by the given Helm storage, e.g. the
+// storage of a Helm action configuration.
+func NewStorageStore(storage *helmstorage.Storage) Store {
+	return &storageStore{storage: storage}
+}
+
+// NewMemoryStore returns a Store backed by an in-memory Helm storage driver
+// for the given namespace, holding the given releases.
+func NewMemoryStore(namespace string, releases ...*helmrelease.Release) (Store, error) {
+	driver := helmdriver.NewMemory()
+	driver.SetNamespace(namespace)
+	store := &storageStore{storage: helmstorage.Init(driver)}
+	for _, rls := range releases {
+		if err := store.storage.Create(rls); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// NewSecretStore returns a Store backed by the Secrets of the given client,
+// as written by the Helm Secrets storage driver.
+func NewSecretStore(client corev1client.SecretInterface) Store {
+	return NewStore(helmdriver.NewSecrets(client))
+}
+
+// storageStore implements Store using a Helm storage.Storage.
+type storageStore struct {
+	storage *helmstorage.Storage
+}
+
+func (s *storageStore) Get(name string, version int) (*helmrelease.Release, error) {
+	return s.storage.Get(name, version)
+}
+
+func (s *storageStore) Last(name string) (*helmrelease.Release, error) {
+	return s.storage.Last(name)
+}
+
+func (s *storageStore) History(name string) ([]*helmrelease.Release, error) {
+	history, err := s.storage.History(name)
+	if err == nil && len(history) == 0 {
+		err = helmdriver.ErrReleaseNotFound
+	}
+	return history, err
+}
+
+func (s *storageStore) LastDeployed(name string) (*helmrelease.Release, error) {
+	rls, err := s.storage.Deployed(name)
+	if errors.Is(err, helmdriver.ErrNoDeployedReleases) {
+		return nil, helmdriver.ErrReleaseNotFound
+	}
+	return rls, err
+}
+
+func (s *storageStore) Observe(name string, version int, filter ...DataFilter) (Observation, error) {
+	rls, err := s.storage.Get(name, version)
+	if err != nil {
+		return Observation{}, err
+	}
+	return ObserveRelease(rls, filter...), nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	helmrelease "github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	helmstorage "github.com/jessesimpson36/helm/v4/pkg/storage"
+	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+func testReleases() []*helmrelease.Release {
+	return []*helmrelease.Release{
+		testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+			Name:      "podinfo",
+			Version:   1,
+			Status:    helmrelease.StatusSuperseded,
+			Namespace: "default",
+		}),
+		testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+			Name:      "podinfo",
+			Version:   2,
+			Status:    helmrelease.StatusDeployed,
+			Namespace: "default",
+		}),
+		testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+			Name:      "podinfo",
+			Version:   3,
+			Status:    helmrelease.StatusFailed,
+			Namespace: "default",
+		}),
+		testutil.BuildRelease(&helmrelease.MockReleaseOptions{
+			Name:      "failed",
+			Version:   1,
+			Status:    helmrelease.StatusFailed,
+			Namespace: "default",
+		}),
+	}
+}
+
+func TestStore(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store {
+			s, err := NewMemoryStore("default", testReleases()...)
+			NewWithT(t).Expect(err).ToNot(HaveOccurred())
+			return s
+		},
+		"secret": func(t *testing.T) Store {
+			client := fake.NewSimpleClientset().CoreV1().Secrets("default")
+			s := helmstorage.Init(helmdriver.NewSecrets(client))
+			for _, rls := range testReleases() {
+				NewWithT(t).Expect(s.Create(rls)).To(Succeed())
+			}
+			return NewSecretStore(client)
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Run("Get", func(t *testing.T) {
+				g := NewWithT(t)
+				s := newStore(t)
+
+				rls, err := s.Get("podinfo", 2)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(rls.Version).To(Equal(2))
+
+				_, err = s.Get("podinfo", 4)
+				g.Expect(err).To(MatchError(helmdriver.ErrReleaseNotFound))
+			})
+
+			t.Run("Last", func(t *testing.T) {
+				g := NewWithT(t)
+				s := newStore(t)
+
+				rls, err := s.Last("podinfo")
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(rls.Version).To(Equal(3))
+
+				_, err = s.Last("missing")
+				g.Expect(err).To(MatchError(helmdriver.ErrReleaseNotFound))
+			})
+
+			t.Run("History", func(t *testing.T) {
+				g := NewWithT(t)
+				s := newStore(t)
+
+				history, err := s.History("podinfo")
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(history).To(HaveLen(3))
+
+				_, err = s.History("missing")
+				g.Expect(err).To(MatchError(helmdriver.ErrReleaseNotFound))
+			})
+
+			t.Run("LastDeployed", func(t *testing.T) {
+				g := NewWithT(t)
+				s := newStore(t)
+
+				rls, err := s.LastDeployed("podinfo")
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(rls.Version).To(Equal(2))
+
+				_, err = s.LastDeployed("failed")
+				g.Expect(err).To(MatchError(helmdriver.ErrReleaseNotFound))
+
+				_, err = s.LastDeployed("missing")
+				g.Expect(err).To(MatchError(helmdriver.ErrReleaseNotFound))
+			})
+
+			t.Run("Observe", func(t *testing.T) {
+				g := NewWithT(t)
+				s := newStore(t)
+
+				obs, err := s.Observe("podinfo", 2)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(obs.Targets("podinfo", "default", 2)).To(BeTrue())
+				g.Expect(obs.Info.Status).To(Equal(helmrelease.StatusDeployed))
+
+				_, err = s.Observe("podinfo", 4)
+				g.Expect(err).To(MatchError(helmdriver.ErrReleaseNotFound))
+			})
+		})
+	}
+}
@@ -22,7 +22,7 @@ import (
 	"os"
 	"testing"
 
-	"github.com/jessesimpson36/helm/v4/pkg/release/v1"
+	release "github.com/jessesimpson36/helm/v4/pkg/release/v1"
 )
 
 var (
@@ -54,7 +54,7 @@ func decodeReleaseFromFile(path string) (*release.Release, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load encoded release data: %w", err)
 	}
-	rel, err := decodeRelease(string(b))
+	rel, err := Decode(string(b))
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode release data: %w", err)
 	}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"time"
+)
+
+// DefaultClockSkewTolerance is the default tolerance for clock skew between
+// the controller and the Kubernetes API server, or other Helm clients
+// recording release timestamps, e.g. another controller instance or the
+// Helm CLI on a workstation.
+const DefaultClockSkewTolerance = 5 * time.Second
+
+// IsNewer returns true if timestamp a is more recent than timestamp b by
+// more than the given tolerance for clock skew between the clocks which
+// recorded them. Timestamps within the tolerance of each other are
+// considered equally recent.
+func IsNewer(a, b time.Time, tolerance time.Duration) bool {
+	return a.Sub(b) > tolerance
+}
+
+// Elapsed returns the duration elapsed between timestamp t and now, less
+// the given tolerance for clock skew between the clock which recorded t and
+// the clock of now. It is never negative, which can otherwise happen when
+// the clock which recorded t is ahead.
+func Elapsed(t, now time.Time, tolerance time.Duration) time.Duration {
+	if d := now.Sub(t) - tolerance; d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Until returns the duration until timestamp t is considered in the past
+// by IsNewer with the given tolerance, i.e. the duration after which an
+// action scheduled at t can safely be taken.
+func Until(t, now time.Time, tolerance time.Duration) time.Duration {
+	if d := t.Sub(now) + tolerance; d > 0 {
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/helm-controller/internal/testutil"
+)
+
+func TestIsNewer(t *testing.T) {
+	ts := testutil.MustParseHelmTime("2006-01-02T15:04:05Z").Time
+
+	tests := []struct {
+		name string
+		a    time.Time
+		b    time.Time
+		want bool
+	}{
+		{name: "newer beyond tolerance", a: ts.Add(10 * time.Second), b: ts, want: true},
+		{name: "newer within tolerance", a: ts.Add(3 * time.Second), b: ts, want: false},
+		{name: "equal", a: ts, b: ts, want: false},
+		{name: "older", a: ts.Add(-10 * time.Second), b: ts, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsNewer(tt.a, tt.b, DefaultClockSkewTolerance)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestElapsed(t *testing.T) {
+	now := testutil.MustParseHelmTime("2006-01-02T15:04:05Z").Time
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Duration
+	}{
+		{name: "past beyond tolerance", t: now.Add(-time.Minute), want: 55 * time.Second},
+		{name: "past within tolerance", t: now.Add(-3 * time.Second), want: 0},
+		{name: "future", t: now.Add(time.Minute), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Elapsed(tt.t, now, DefaultClockSkewTolerance)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestUntil(t *testing.T) {
+	now := testutil.MustParseHelmTime("2006-01-02T15:04:05Z").Time
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Duration
+	}{
+		{name: "future", t: now.Add(time.Minute), want: 65 * time.Second},
+		{name: "past within tolerance", t: now.Add(-3 * time.Second), want: 2 * time.Second},
+		{name: "past beyond tolerance", t: now.Add(-time.Minute), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Until(tt.t, now, DefaultClockSkewTolerance)).To(Equal(tt.want))
+			g.Expect(IsNewer(now.Add(tt.want+time.Nanosecond), tt.t, DefaultClockSkewTolerance)).To(BeTrue())
+		})
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Options configures a Store.
+type Options struct {
+	// Dir is the directory the files are retained in. An empty value
+	// disables the retention.
+	Dir string
+	// MaxCount is the maximum number of releases retained.
+	MaxCount int
+	// MaxSize is the maximum total size in bytes of the retained files.
+	MaxSize int64
+}
+
+// Store retains the files of the last failed reconciliation of a release on
+// disk, e.g. the downloaded chart artifact and the rendered manifest, for
+// engineers to inspect after the fact. Each release is retained in its own
+// directory, which is replaced on the next failure and removed once the
+// release reconciles successfully.
+//
+// The Store is bounded by the number of retained releases and their total
+// size, evicting the least recently retained releases first.
+type Store struct {
+	dir      string
+	maxCount int
+	maxSize  int64
+
+	mu sync.Mutex
+}
+
+// NewStore returns a new Store which retains at most maxCount releases with
+// a total size of at most maxSize bytes in the given directory, creating the
+// directory if it does not exist. It returns nil if dir is empty, which is a
+// valid (disabled) Store.
+func NewStore(dir string, maxCount int, maxSize int64) (*Store, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if maxCount < 1 {
+		return nil, fmt.Errorf("invalid max count '%d': must be greater than zero", maxCount)
+	}
+	if maxSize < 1 {
+		return nil, fmt.Errorf("invalid max size '%d': must be greater than zero", maxSize)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create retention directory: %w", err)
+	}
+	return &Store{dir: dir, maxCount: maxCount, maxSize: maxSize}, nil
+}
+
+// Dir returns the directory the files of the release with the given
+// namespace and name are retained in.
+func (s *Store) Dir(namespace, name string) string {
+	if s == nil {
+		return ""
+	}
+	return filepath.Join(s.dir, namespace+"_"+name)
+}
+
+// Retain replaces the retained files of the release with the given namespace
+// and name with the given files, keyed by their file name. Releases of which
+// the files exceed the max size on their own are not retained. After
+// retaining the files, releases are evicted until the Store is within its
+// bounds again.
+func (s *Store) Retain(namespace, name string, files map[string][]byte) error {
+	if s == nil {
+		return nil
+	}
+
+	var size int64
+	for _, b := range files {
+		size += int64(len(b))
+	}
+	if size > s.maxSize {
+		return fmt.Errorf("size of %d bytes exceeds max size of %d bytes", size, s.maxSize)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.MkdirTemp(s.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	for fileName, b := range files {
+		if err := os.WriteFile(filepath.Join(tmp, filepath.Base(fileName)), b, 0o600); err != nil {
+			return err
+		}
+	}
+
+	dir := s.Dir(namespace, name)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return err
+	}
+	return s.evict(dir)
+}
+
+// Remove removes the retained files of the release with the given namespace
+// and name, if any.
+func (s *Store) Remove(namespace, name string) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return os.RemoveAll(s.Dir(namespace, name))
+}
+
+type entry struct {
+	path string
+	size int64
+	mod  int64
+}
+
+// evict removes the least recently retained releases until the Store is
+// within its bounds, never evicting the given (just retained) directory.
+func (s *Store) evict(keep string) error {
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	var (
+		entries []entry
+		total   int64
+	)
+	for _, de := range dirEntries {
+		if !de.IsDir() || strings.HasPrefix(de.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(s.dir, de.Name())
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		size, err := dirSize(path)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{path: path, size: size, mod: info.ModTime().UnixNano()})
+		total += size
+	}
+
+	// Sort from least to most recently retained.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].mod < entries[j].mod
+	})
+
+	count := len(entries)
+	for _, e := range entries {
+		if count <= s.maxCount && total <= s.maxSize {
+			break
+		}
+		if e.path == keep {
+			continue
+		}
+		if err := os.RemoveAll(e.path); err != nil {
+			return err
+		}
+		count--
+		total -= e.size
+	}
+	return nil
+}
+
+// dirSize returns the total size of the regular files in the given
+// directory.
+func dirSize(dir string) (int64, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil {
+			return 0, err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+	}
+	return size, nil
+}
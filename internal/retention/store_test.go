@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNewStore(t *testing.T) {
+	g := NewWithT(t)
+
+	s, err := NewStore("", 1, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(s).To(BeNil())
+
+	// A nil Store is a no-op.
+	g.Expect(s.Retain("default", "podinfo", map[string][]byte{"chart.tgz": []byte("chart")})).To(Succeed())
+	g.Expect(s.Remove("default", "podinfo")).To(Succeed())
+
+	_, err = NewStore(t.TempDir(), 0, 1)
+	g.Expect(err).To(HaveOccurred())
+	_, err = NewStore(t.TempDir(), 1, 0)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestStore_Retain(t *testing.T) {
+	g := NewWithT(t)
+
+	s, err := NewStore(t.TempDir(), 2, 10)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Age the retained releases, as the order of eviction is determined by
+	// the modification time of their directories.
+	age := func(name string, d time.Duration) {
+		ts := time.Now().Add(-d)
+		g.Expect(os.Chtimes(s.Dir("default", name), ts, ts)).To(Succeed())
+	}
+
+	g.Expect(s.Retain("default", "a", map[string][]byte{"chart.tgz": []byte("aaa")})).To(Succeed())
+	age("a", 2*time.Minute)
+	g.Expect(s.Retain("default", "b", map[string][]byte{"chart.tgz": []byte("bbb")})).To(Succeed())
+	age("b", time.Minute)
+
+	// Files are replaced on the next failure.
+	g.Expect(s.Retain("default", "b", map[string][]byte{"manifest.yaml": []byte("b")})).To(Succeed())
+	age("b", time.Minute)
+	g.Expect(filepath.Join(s.Dir("default", "b"), "chart.tgz")).ToNot(BeAnExistingFile())
+	g.Expect(filepath.Join(s.Dir("default", "b"), "manifest.yaml")).To(BeAnExistingFile())
+
+	// Exceeding the max count evicts the least recently retained release.
+	g.Expect(s.Retain("default", "c", map[string][]byte{"chart.tgz": []byte("ccc")})).To(Succeed())
+	g.Expect(s.Dir("default", "a")).ToNot(BeADirectory())
+	g.Expect(s.Dir("default", "b")).To(BeADirectory())
+	g.Expect(s.Dir("default", "c")).To(BeADirectory())
+
+	// Exceeding the max size evicts releases until it fits.
+	age("c", time.Second)
+	g.Expect(s.Retain("default", "d", map[string][]byte{"chart.tgz": []byte("ddddddddd")})).To(Succeed())
+	g.Expect(s.Dir("default", "b")).ToNot(BeADirectory())
+	g.Expect(s.Dir("default", "c")).ToNot(BeADirectory())
+	g.Expect(s.Dir("default", "d")).To(BeADirectory())
+
+	// Releases exceeding the max size on their own are not retained.
+	g.Expect(s.Retain("default", "e", map[string][]byte{"chart.tgz": []byte("eeeeeeeeeee")})).ToNot(Succeed())
+	g.Expect(s.Dir("default", "e")).ToNot(BeADirectory())
+
+	g.Expect(s.Remove("default", "d")).To(Succeed())
+	g.Expect(s.Dir("default", "d")).ToNot(BeADirectory())
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scan submits the rendered manifests of a release, and the
+// container images referenced by them, to an external security scanner,
+// e.g. an adapter in front of Trivy or Grype.
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+const (
+	// DefaultTimeout is the timeout of a scan request when the Client does
+	// not configure one.
+	DefaultTimeout = 2 * time.Minute
+
+	// maxResponseSize is the maximum size of the response of the scanner.
+	maxResponseSize = 10 << 20
+)
+
+// severityRank holds the order of the severities, from lowest to highest.
+// Any other severity reported by a scanner (e.g. 'UNKNOWN' or 'NEGLIGIBLE')
+// ranks below SecuritySeverityLow.
+var severityRank = []v2.SecuritySeverity{
+	v2.SecuritySeverityLow,
+	v2.SecuritySeverityMedium,
+	v2.SecuritySeverityHigh,
+	v2.SecuritySeverityCritical,
+}
+
+// Request is the payload submitted to the scanner.
+type Request struct {
+	// Name of the Helm release.
+	Name string `json:"name"`
+	// Namespace of the Helm release.
+	Namespace string `json:"namespace"`
+	// Revision is the chart version of the release.
+	Revision string `json:"revision"`
+	// Manifests is the (multi-document) YAML of the rendered manifests.
+	Manifests string `json:"manifests"`
+	// Images holds the container images referenced by the manifests.
+	Images []string `json:"images"`
+}
+
+// Result is the response of the scanner.
+type Result struct {
+	// Findings holds the findings of the scan.
+	Findings []Finding `json:"findings"`
+}
+
+// Finding is a single finding of a scan, e.g. a vulnerability in an image
+// or a misconfiguration in a manifest.
+type Finding struct {
+	// ID of the finding, e.g. a CVE identifier.
+	ID string `json:"id"`
+	// Severity of the finding.
+	Severity v2.SecuritySeverity `json:"severity"`
+	// Target of the finding, e.g. an image or manifest.
+	Target string `json:"target,omitempty"`
+	// Title is a short description of the finding.
+	Title string `json:"title,omitempty"`
+}
+
+// String returns the finding in the format of '<id> (<severity>) in <target>'.
+func (f Finding) String() string {
+	if f.Target == "" {
+		return fmt.Sprintf("%s (%s)", f.ID, f.Severity)
+	}
+	return fmt.Sprintf("%s (%s) in %s", f.ID, f.Severity, f.Target)
+}
+
+// AtOrAbove returns the findings with a severity at or above the given
+// threshold.
+func (r Result) AtOrAbove(threshold v2.SecuritySeverity) []Finding {
+	minRank := rank(threshold)
+	var findings []Finding
+	for _, f := range r.Findings {
+		if rank(f.Severity) >= minRank {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// rank returns the rank of the given severity, which is -1 for unknown
+// severities.
+func rank(s v2.SecuritySeverity) int {
+	return slices.Index(severityRank, v2.SecuritySeverity(strings.ToUpper(string(s))))
+}
+
+// Client submits scan requests to the scanner at URL.
+type Client struct {
+	// URL of the scanner, to which the Request is POSTed.
+	URL string
+	// HTTPClient is the client used to make the request, e.g. configured
+	// with the CA bundle and client certificate of the scanner. Defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+	// TokenFile is the path to a file containing a bearer token to
+	// authenticate with the scanner. It is read for every request, to pick
+	// up a rotated token.
+	TokenFile string
+	// Timeout of a scan request. Defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Scan submits the given Request to the scanner, and returns the Result.
+func (c *Client) Scan(ctx context.Context, req Request) (*Result, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode scan request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scan request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.TokenFile != "" {
+		token, err := os.ReadFile(c.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scanner token file: %w", err)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit scan request to '%s': %w", c.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to submit scan request to '%s' (status: %s)", c.URL, resp.Status)
+	}
+	var result Result
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode scan result of '%s': %w", c.URL, err)
+	}
+	return &result, nil
+}
+
+// Images returns the sorted, unique container images referenced by the
+// containers, init containers and ephemeral containers of the objects in
+// the given (multi-document) YAML manifest, including those nested in
+// templates of e.g. Deployments or CronJobs.
+func Images(manifest string) ([]string, error) {
+	objects, err := ssautil.ReadObjects(strings.NewReader(manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read objects from manifest: %w", err)
+	}
+
+	var images []string
+	for _, obj := range objects {
+		images = appendImages(images, obj.Object)
+	}
+	slices.Sort(images)
+	return slices.Compact(images), nil
+}
+
+// appendImages appends the images of the containers found in the given
+// value to images, and returns the result.
+func appendImages(images []string, v interface{}) []string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, nested := range v {
+			switch k {
+			case "containers", "initContainers", "ephemeralContainers":
+				if containers, ok := nested.([]interface{}); ok {
+					for _, c := range containers {
+						if c, ok := c.(map[string]interface{}); ok {
+							if image, ok := c["image"].(string); ok && image != "" {
+								images = append(images, image)
+							}
+						}
+					}
+					continue
+				}
+			}
+			images = appendImages(images, nested)
+		}
+	case []interface{}:
+		for _, nested := range v {
+			images = appendImages(images, nested)
+		}
+	}
+	return images
+}
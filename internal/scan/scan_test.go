@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+const testManifest = `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: default
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          image: busybox:1.36
+      containers:
+        - name: podinfo
+          image: ghcr.io/stefanprodan/podinfo:6.5.0
+        - name: sidecar
+          image: busybox:1.36
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+  namespace: default
+spec:
+  schedule: "@daily"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: cleanup
+              image: alpine:3.19
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+data:
+  image: not-an-image
+`
+
+func TestImages(t *testing.T) {
+	g := NewWithT(t)
+
+	images, err := Images(testManifest)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(images).To(Equal([]string{
+		"alpine:3.19",
+		"busybox:1.36",
+		"ghcr.io/stefanprodan/podinfo:6.5.0",
+	}))
+
+	_, err = Images("invalid: [")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestResult_AtOrAbove(t *testing.T) {
+	result := Result{
+		Findings: []Finding{
+			{ID: "CVE-1", Severity: "LOW"},
+			{ID: "CVE-2", Severity: "medium"},
+			{ID: "CVE-3", Severity: "HIGH"},
+			{ID: "CVE-4", Severity: "CRITICAL"},
+			{ID: "CVE-5", Severity: "UNKNOWN"},
+		},
+	}
+
+	tests := []struct {
+		threshold v2.SecuritySeverity
+		want      []string
+	}{
+		{threshold: v2.SecuritySeverityLow, want: []string{"CVE-1", "CVE-2", "CVE-3", "CVE-4"}},
+		{threshold: v2.SecuritySeverityMedium, want: []string{"CVE-2", "CVE-3", "CVE-4"}},
+		{threshold: v2.SecuritySeverityHigh, want: []string{"CVE-3", "CVE-4"}},
+		{threshold: v2.SecuritySeverityCritical, want: []string{"CVE-4"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.threshold), func(t *testing.T) {
+			g := NewWithT(t)
+
+			var got []string
+			for _, f := range result.AtOrAbove(tt.threshold) {
+				got = append(got, f.ID)
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestClient_Scan(t *testing.T) {
+	g := NewWithT(t)
+
+	var received Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/scan" || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Result{
+			Findings: []Finding{{ID: "CVE-1", Severity: "HIGH", Target: "busybox:1.36"}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	c := &Client{URL: srv.URL + "/scan"}
+	result, err := c.Scan(context.TODO(), Request{
+		Name:      "podinfo",
+		Namespace: "default",
+		Revision:  "6.5.0",
+		Manifests: testManifest,
+		Images:    []string{"busybox:1.36"},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.Findings).To(HaveLen(1))
+	g.Expect(result.Findings[0].String()).To(Equal("CVE-1 (HIGH) in busybox:1.36"))
+	g.Expect(received.Name).To(Equal("podinfo"))
+	g.Expect(received.Images).To(Equal([]string{"busybox:1.36"}))
+
+	c = &Client{URL: srv.URL + "/missing"}
+	_, err = c.Scan(context.TODO(), Request{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("404"))
+}
+
+func TestClient_Scan_TokenFile(t *testing.T) {
+	g := NewWithT(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Result{})
+	}))
+	t.Cleanup(srv.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	g.Expect(os.WriteFile(tokenFile, []byte("token\n"), 0o600)).To(Succeed())
+
+	c := &Client{URL: srv.URL, TokenFile: tokenFile}
+	_, err := c.Scan(context.TODO(), Request{})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	c = &Client{URL: srv.URL}
+	_, err = c.Scan(context.TODO(), Request{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("401"))
+
+	c = &Client{URL: srv.URL, TokenFile: filepath.Join(t.TempDir(), "missing")}
+	_, err = c.Scan(context.TODO(), Request{})
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// AnnotatedSecrets is a Secrets client which adds a set of annotations to the
+// Secrets it creates or updates. It can be used to annotate the Secrets
+// written by the Helm storage driver, which does not support annotations
+// itself.
+//
+// When configured with an Owner, the Secrets are annotated with the identity
+// of the Owner, and the client refuses to create, update or delete the
+// Secrets of a release owned by another HelmRelease.
+type AnnotatedSecrets struct {
+	corev1client.SecretInterface
+
+	annotations map[string]string
+	owner       *Owner
+}
+
+// NewAnnotatedSecrets returns a new AnnotatedSecrets for the given client,
+// annotations and (optional) owner.
+func NewAnnotatedSecrets(client corev1client.SecretInterface, annotations map[string]string, owner *Owner) *AnnotatedSecrets {
+	return &AnnotatedSecrets{SecretInterface: client, annotations: mergeAnnotations(annotations, owner), owner: owner}
+}
+
+// Create annotates and creates the given Secret.
+func (s *AnnotatedSecrets) Create(ctx context.Context, secret *corev1.Secret, opts metav1.CreateOptions) (*corev1.Secret, error) {
+	if err := s.VerifyOwner(ctx, secret.Labels[helmStorageNameLabel]); err != nil {
+		return nil, err
+	}
+	annotate(&secret.ObjectMeta, s.annotations)
+	return s.SecretInterface.Create(ctx, secret, opts)
+}
+
+// Update annotates and updates the given Secret.
+func (s *AnnotatedSecrets) Update(ctx context.Context, secret *corev1.Secret, opts metav1.UpdateOptions) (*corev1.Secret, error) {
+	if err := s.verifyExisting(ctx, secret.Name); err != nil {
+		return nil, err
+	}
+	annotate(&secret.ObjectMeta, s.annotations)
+	return s.SecretInterface.Update(ctx, secret, opts)
+}
+
+// Delete deletes the Secret with the given name.
+func (s *AnnotatedSecrets) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	if err := s.verifyExisting(ctx, name); err != nil {
+		return err
+	}
+	return s.SecretInterface.Delete(ctx, name, opts)
+}
+
+// VerifyOwner returns an OwnershipError if any of the Secrets of the release
+// with the given name is owned by another HelmRelease.
+func (s *AnnotatedSecrets) VerifyOwner(ctx context.Context, release string) error {
+	if s.owner == nil || release == "" {
+		return nil
+	}
+	list, err := s.SecretInterface.List(ctx, releaseSelector(release))
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		if err := verifyOwner(&list.Items[i], s.owner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *AnnotatedSecrets) verifyExisting(ctx context.Context, name string) error {
+	if s.owner == nil {
+		return nil
+	}
+	existing, err := s.SecretInterface.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return verifyOwner(existing, s.owner)
+}
+
+// AnnotatedConfigMaps is a ConfigMaps client which adds a set of annotations
+// to the ConfigMaps it creates or updates. Like AnnotatedSecrets, it refuses
+// to modify the ConfigMaps of a release owned by another HelmRelease when
+// configured with an Owner.
+type AnnotatedConfigMaps struct {
+	corev1client.ConfigMapInterface
+
+	annotations map[string]string
+	owner       *Owner
+}
+
+// NewAnnotatedConfigMaps returns a new AnnotatedConfigMaps for the given
+// client, annotations and (optional) owner.
+func NewAnnotatedConfigMaps(client corev1client.ConfigMapInterface, annotations map[string]string, owner *Owner) *AnnotatedConfigMaps {
+	return &AnnotatedConfigMaps{ConfigMapInterface: client, annotations: mergeAnnotations(annotations, owner), owner: owner}
+}
+
+// Create annotates and creates the given ConfigMap.
+func (c *AnnotatedConfigMaps) Create(ctx context.Context, cm *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	if err := c.VerifyOwner(ctx, cm.Labels[helmStorageNameLabel]); err != nil {
+		return nil, err
+	}
+	annotate(&cm.ObjectMeta, c.annotations)
+	return c.ConfigMapInterface.Create(ctx, cm, opts)
+}
+
+// Update annotates and updates the given ConfigMap.
+func (c *AnnotatedConfigMaps) Update(ctx context.Context, cm *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+	if err := c.verifyExisting(ctx, cm.Name); err != nil {
+		return nil, err
+	}
+	annotate(&cm.ObjectMeta, c.annotations)
+	return c.ConfigMapInterface.Update(ctx, cm, opts)
+}
+
+// Delete deletes the ConfigMap with the given name.
+func (c *AnnotatedConfigMaps) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	if err := c.verifyExisting(ctx, name); err != nil {
+		return err
+	}
+	return c.ConfigMapInterface.Delete(ctx, name, opts)
+}
+
+// VerifyOwner returns an OwnershipError if any of the ConfigMaps of the
+// release with the given name is owned by another HelmRelease.
+func (c *AnnotatedConfigMaps) VerifyOwner(ctx context.Context, release string) error {
+	if c.owner == nil || release == "" {
+		return nil
+	}
+	list, err := c.ConfigMapInterface.List(ctx, releaseSelector(release))
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		if err := verifyOwner(&list.Items[i], c.owner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *AnnotatedConfigMaps) verifyExisting(ctx context.Context, name string) error {
+	if c.owner == nil {
+		return nil
+	}
+	existing, err := c.ConfigMapInterface.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return verifyOwner(existing, c.owner)
+}
+
+// mergeAnnotations returns the given annotations with the annotations
+// identifying the owner, which take precedence.
+func mergeAnnotations(annotations map[string]string, owner *Owner) map[string]string {
+	if owner == nil {
+		return annotations
+	}
+	merged := make(map[string]string, len(annotations)+3)
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	for k, v := range owner.Annotations() {
+		merged[k] = v
+	}
+	return merged
+}
+
+func annotate(obj *metav1.ObjectMeta, annotations map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+	if obj.Annotations == nil {
+		obj.Annotations = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		obj.Annotations[k] = v
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestAnnotatedSecrets(t *testing.T) {
+	g := NewWithT(t)
+
+	annotations := map[string]string{"example.com/owner": "team-a"}
+	client := NewAnnotatedSecrets(fake.NewSimpleClientset().CoreV1().Secrets("default"), annotations, nil)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "sh.helm.release.v1.podinfo.v1",
+			Annotations: map[string]string{"existing": "value"},
+		},
+	}
+	got, err := client.Create(context.TODO(), secret, metav1.CreateOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Annotations).To(Equal(map[string]string{
+		"existing":          "value",
+		"example.com/owner": "team-a",
+	}))
+
+	// Helm constructs a new object on update, without any annotations.
+	got, err = client.Update(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.podinfo.v1"},
+	}, metav1.UpdateOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Annotations).To(Equal(annotations))
+}
+
+func TestAnnotatedConfigMaps(t *testing.T) {
+	g := NewWithT(t)
+
+	annotations := map[string]string{"example.com/owner": "team-a"}
+	client := NewAnnotatedConfigMaps(fake.NewSimpleClientset().CoreV1().ConfigMaps("default"), annotations, nil)
+
+	got, err := client.Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.podinfo.v1"},
+	}, metav1.CreateOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Annotations).To(Equal(annotations))
+
+	got, err = client.Update(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.podinfo.v1"},
+	}, metav1.UpdateOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Annotations).To(Equal(annotations))
+}
+
+func TestAnnotatedSecrets_Owner(t *testing.T) {
+	g := NewWithT(t)
+
+	newSecret := func(name, release string, annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Labels:      map[string]string{"owner": "helm", helmStorageNameLabel: release},
+				Annotations: annotations,
+			},
+		}
+	}
+	other := &Owner{Name: "other", Namespace: "default", UID: "other-uid"}
+	recreated := &Owner{Name: "podinfo", Namespace: "default", UID: "previous-uid"}
+	clientset := fake.NewSimpleClientset(
+		newSecret("sh.helm.release.v1.owned.v1", "owned", other.Annotations()),
+		newSecret("sh.helm.release.v1.adopted.v1", "adopted", nil),
+		newSecret("sh.helm.release.v1.recreated.v1", "recreated", recreated.Annotations()),
+	)
+
+	owner := &Owner{Name: "podinfo", Namespace: "default", UID: "podinfo-uid"}
+	client := NewAnnotatedSecrets(clientset.CoreV1().Secrets("default"), nil, owner)
+
+	// Releases owned by another HelmRelease can not be modified.
+	err := client.VerifyOwner(context.TODO(), "owned")
+	g.Expect(err).To(HaveOccurred())
+	var ownershipErr *OwnershipError
+	g.Expect(errors.As(err, &ownershipErr)).To(BeTrue())
+	g.Expect(ownershipErr.Owner).To(Equal("default/other"))
+
+	_, err = client.Create(context.TODO(), newSecret("sh.helm.release.v1.owned.v2", "owned", nil), metav1.CreateOptions{})
+	g.Expect(errors.As(err, &ownershipErr)).To(BeTrue())
+	_, err = client.Update(context.TODO(), newSecret("sh.helm.release.v1.owned.v1", "owned", nil), metav1.UpdateOptions{})
+	g.Expect(errors.As(err, &ownershipErr)).To(BeTrue())
+	err = client.Delete(context.TODO(), "sh.helm.release.v1.owned.v1", metav1.DeleteOptions{})
+	g.Expect(errors.As(err, &ownershipErr)).To(BeTrue())
+
+	// Releases without an owner are adopted.
+	g.Expect(client.VerifyOwner(context.TODO(), "adopted")).To(Succeed())
+	got, err := client.Update(context.TODO(), newSecret("sh.helm.release.v1.adopted.v1", "adopted", nil), metav1.UpdateOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Annotations).To(HaveKeyWithValue(v2.StorageOwnerUIDAnnotation, "podinfo-uid"))
+	g.Expect(client.Delete(context.TODO(), "sh.helm.release.v1.adopted.v1", metav1.DeleteOptions{})).To(Succeed())
+
+	// Releases of a recreated HelmRelease are adopted, and annotated with
+	// its new UID.
+	g.Expect(client.VerifyOwner(context.TODO(), "recreated")).To(Succeed())
+	got, err = client.Update(context.TODO(), newSecret("sh.helm.release.v1.recreated.v1", "recreated", recreated.Annotations()), metav1.UpdateOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Annotations).To(HaveKeyWithValue(v2.StorageOwnerUIDAnnotation, "podinfo-uid"))
+
+	// New releases are annotated with the owner.
+	got, err = client.Create(context.TODO(), newSecret("sh.helm.release.v1.new.v1", "new", nil), metav1.CreateOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Annotations).To(Equal(owner.Annotations()))
+}
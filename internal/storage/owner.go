@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// helmStorageNameLabel is the label the Helm storage drivers set to the name
+// of the release on its Secrets and ConfigMaps.
+const helmStorageNameLabel = "name"
+
+// Owner identifies the HelmRelease which owns the releases written to the
+// Helm storage.
+type Owner struct {
+	Name      string
+	Namespace string
+	UID       string
+}
+
+// Annotations returns the annotations identifying the Owner, to be added to
+// the Helm storage objects of its releases.
+func (o *Owner) Annotations() map[string]string {
+	if o == nil {
+		return nil
+	}
+	return map[string]string{
+		v2.StorageOwnerNameAnnotation:      o.Name,
+		v2.StorageOwnerNamespaceAnnotation: o.Namespace,
+		v2.StorageOwnerUIDAnnotation:       o.UID,
+	}
+}
+
+// OwnershipError is returned when a Helm storage object of a release is
+// owned by another HelmRelease than the Owner the storage is configured
+// with.
+type OwnershipError struct {
+	// Object is the name of the Helm storage object.
+	Object string
+	// Owner is the namespaced name of the HelmRelease owning the object.
+	Owner string
+}
+
+func (e *OwnershipError) Error() string {
+	return fmt.Sprintf("Helm storage object '%s' is owned by HelmRelease '%s'", e.Object, e.Owner)
+}
+
+// OwnerVerifier verifies the Helm storage objects of a release are not owned
+// by another HelmRelease.
+type OwnerVerifier interface {
+	// VerifyOwner returns an OwnershipError if any of the Helm storage
+	// objects of the release with the given name is owned by another
+	// HelmRelease.
+	VerifyOwner(ctx context.Context, release string) error
+}
+
+// verifyOwner returns an OwnershipError if the given object is annotated
+// with the UID of another owner than o. Objects without an owner UID, e.g.
+// written by the Helm CLI or an older version of the controller, are
+// adopted. So are objects owned by a HelmRelease with the same namespace
+// and name as o, e.g. when the HelmRelease has been recreated, which are
+// annotated with the new UID the next time they are written.
+func verifyOwner(obj metav1.Object, o *Owner) error {
+	if o == nil {
+		return nil
+	}
+	annotations := obj.GetAnnotations()
+	uid, ok := annotations[v2.StorageOwnerUIDAnnotation]
+	if !ok || uid == o.UID {
+		return nil
+	}
+	if annotations[v2.StorageOwnerNamespaceAnnotation] == o.Namespace &&
+		annotations[v2.StorageOwnerNameAnnotation] == o.Name {
+		return nil
+	}
+	return &OwnershipError{
+		Object: obj.GetName(),
+		Owner:  annotations[v2.StorageOwnerNamespaceAnnotation] + "/" + annotations[v2.StorageOwnerNameAnnotation],
+	}
+}
+
+// releaseSelector returns the label selector matching the Helm storage
+// objects of the release with the given name.
+func releaseSelector(release string) metav1.ListOptions {
+	return metav1.ListOptions{LabelSelector: "owner=helm," + helmStorageNameLabel + "=" + release}
+}
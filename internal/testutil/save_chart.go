@@ -23,9 +23,9 @@ import (
 	"strings"
 
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	chart "github.com/jessesimpson36/helm/v4/pkg/chart/v2"
+	chartutil "github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
 	"github.com/opencontainers/go-digest"
-	"github.com/jessesimpson36/helm/v4/pkg/chart/v2"
-	"github.com/jessesimpson36/helm/v4/pkg/chart/v2/util"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/rand"
 )
@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer used to record the spans of the
+// controller.
+const tracerName = "github.com/fluxcd/helm-controller"
+
+// Options configures the export of the spans of the controller.
+type Options struct {
+	// Endpoint is the URL of the OTLP/HTTP endpoint the spans are exported
+	// to, e.g. "http://otel-collector.monitoring:4318". An empty endpoint
+	// disables the tracing.
+	Endpoint string
+	// SampleRatio is the ratio of the reconciliations which are traced,
+	// between 0 and 1.
+	SampleRatio float64
+}
+
+// Provider exports the spans recorded by the controller to an OTLP endpoint.
+// It implements the manager.Runnable interface, to flush the remaining spans
+// when the controller is shutting down.
+type Provider struct {
+	tp *sdktrace.TracerProvider
+}
+
+// NewProvider returns a Provider which exports the spans to the endpoint of
+// the given Options, and registers it as the global tracer provider. It
+// returns nil if no endpoint is configured, in which case no spans are
+// recorded.
+func NewProvider(ctx context.Context, serviceName string, opts Options) (*Provider, error) {
+	if opts.Endpoint == "" {
+		return nil, nil
+	}
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid sample ratio '%v': must be between 0 and 1", opts.SampleRatio)
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+	return &Provider{tp: tp}, nil
+}
+
+// Start blocks until the context is canceled, after which the remaining
+// spans are flushed. It implements the manager.Runnable interface.
+func (p *Provider) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return p.tp.Shutdown(shutdownCtx)
+}
+
+// NeedLeaderElection returns false, as the spans of a controller which is
+// not the leader (e.g. of the webhooks) should be exported as well.
+func (p *Provider) NeedLeaderElection() bool {
+	return false
+}
+
+// StartSpan starts a span with the given name and attributes as a child of
+// any span in the given context. The returned context contains the span,
+// which must be ended using EndSpan.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends the given span, recording the given error (if any) on it.
+// The message of the error is passed through the given redact functions
+// before it is recorded, e.g. to remove sensitive content from it.
+func EndSpan(span trace.Span, err error, redact ...func(string) string) {
+	if err != nil {
+		if len(redact) > 0 {
+			msg := err.Error()
+			for _, fn := range redact {
+				msg = fn(msg)
+			}
+			err = errors.New(msg)
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewProvider(t *testing.T) {
+	t.Run("disabled without endpoint", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p, err := NewProvider(context.TODO(), "helm-controller", Options{SampleRatio: 1})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(p).To(BeNil())
+	})
+
+	t.Run("invalid sample ratio", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := NewProvider(context.TODO(), "helm-controller", Options{
+			Endpoint:    "http://localhost:4318",
+			SampleRatio: 2,
+		})
+		g.Expect(err).To(MatchError(ContainSubstring("invalid sample ratio")))
+	})
+}
+
+func TestStartSpan(t *testing.T) {
+	g := NewWithT(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+	})
+
+	ctx, parent := StartSpan(context.TODO(), "reconcile", attribute.String("helmrelease.name", "podinfo"))
+	_, child := StartSpan(ctx, "install")
+	EndSpan(child, errors.New("install failed"))
+	EndSpan(parent, nil)
+
+	spans := exporter.GetSpans()
+	g.Expect(spans).To(HaveLen(2))
+
+	g.Expect(spans[0].Name).To(Equal("install"))
+	g.Expect(spans[0].Parent.SpanID()).To(Equal(spans[1].SpanContext.SpanID()))
+	g.Expect(spans[0].Status.Code).To(Equal(codes.Error))
+	g.Expect(spans[0].Status.Description).To(Equal("install failed"))
+
+	g.Expect(spans[1].Name).To(Equal("reconcile"))
+	g.Expect(spans[1].Attributes).To(ContainElement(attribute.String("helmrelease.name", "podinfo")))
+	g.Expect(spans[1].Status.Code).To(Equal(codes.Unset))
+}
+
+func TestEndSpan_redact(t *testing.T) {
+	g := NewWithT(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+	})
+
+	_, span := StartSpan(context.TODO(), "install")
+	EndSpan(span, errors.New("install failed: password=secret"), func(msg string) string {
+		return strings.ReplaceAll(msg, "secret", "***")
+	})
+
+	spans := exporter.GetSpans()
+	g.Expect(spans).To(HaveLen(1))
+	g.Expect(spans[0].Status.Description).To(Equal("install failed: password=***"))
+	g.Expect(spans[0].Events).To(HaveLen(1))
+	g.Expect(spans[0].Events[0].Attributes).To(ContainElement(attribute.String("exception.message", "install failed: password=***")))
+}
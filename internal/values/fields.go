@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package values
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jessesimpson36/helm/v4/pkg/strvals"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// ErrFieldNotFound is returned when the field path of a
+// v2.FieldValuesReference does not select any value.
+var ErrFieldNotFound = errors.New("field not found")
+
+// MergeFieldReferences merges the values of the fields referenced by refs
+// into values, in the order given. The referents are looked up in the given
+// namespace. A not found referent or field is ignored if the reference is
+// marked as optional.
+func MergeFieldReferences(ctx context.Context, c client.Reader, namespace string,
+	values map[string]interface{}, refs ...v2.FieldValuesReference) (map[string]interface{}, error) {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	for _, ref := range refs {
+		v, err := fieldValue(ctx, c, namespace, ref)
+		if err != nil {
+			if ref.Optional && (apierrors.IsNotFound(err) || errors.Is(err, ErrFieldNotFound)) {
+				continue
+			}
+			return nil, err
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode value of field '%s' of %s '%s/%s': %w",
+				ref.FieldPath, ref.Kind, namespace, ref.Name, err)
+		}
+		if err = strvals.ParseJSON(fmt.Sprintf("%s=%s", ref.TargetPath, b), values); err != nil {
+			return nil, fmt.Errorf("unable to merge value of field '%s' of %s '%s/%s' at path '%s': %w",
+				ref.FieldPath, ref.Kind, namespace, ref.Name, ref.TargetPath, err)
+		}
+	}
+	return values, nil
+}
+
+// fieldValue returns the value selected by the field path of the reference
+// from the referent in the given namespace.
+func fieldValue(ctx context.Context, c client.Reader, namespace string, ref v2.FieldValuesReference) (interface{}, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(ref.APIVersion)
+	obj.SetKind(ref.Kind)
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, obj); err != nil {
+		return nil, fmt.Errorf("could not get %s '%s/%s': %w", ref.Kind, namespace, ref.Name, err)
+	}
+
+	path := ref.FieldPath
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	jp := jsonpath.New(ref.Name).AllowMissingKeys(true)
+	if err := jp.Parse(path); err != nil {
+		return nil, fmt.Errorf("invalid field path '%s': %w", ref.FieldPath, err)
+	}
+	results, err := jp.FindResults(obj.UnstructuredContent())
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate field path '%s' for %s '%s/%s': %w",
+			ref.FieldPath, ref.Kind, namespace, ref.Name, err)
+	}
+
+	var values []interface{}
+	for _, r := range results {
+		for _, v := range r {
+			values = append(values, v.Interface())
+		}
+	}
+	switch len(values) {
+	case 0:
+		return nil, fmt.Errorf("field path '%s' of %s '%s/%s': %w", ref.FieldPath, ref.Kind, namespace, ref.Name, ErrFieldNotFound)
+	case 1:
+		return values[0], nil
+	default:
+		return nil, fmt.Errorf("field path '%s' of %s '%s/%s' selects %d values, expected a single value",
+			ref.FieldPath, ref.Kind, namespace, ref.Name, len(values))
+	}
+}
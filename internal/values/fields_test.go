@@ -0,0 +1,160 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package values
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestMergeFieldReferences(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cache",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "cache"},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.0.0.1",
+			Ports: []corev1.ServicePort{
+				{Name: "redis", Port: 6379},
+				{Name: "metrics", Port: 9121},
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc).Build()
+
+	ref := func(fieldPath, targetPath string) v2.FieldValuesReference {
+		return v2.FieldValuesReference{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Name:       "cache",
+			FieldPath:  fieldPath,
+			TargetPath: targetPath,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		refs    []v2.FieldValuesReference
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:   "merges scalar value",
+			values: map[string]interface{}{"replicas": 2},
+			refs:   []v2.FieldValuesReference{ref("{.spec.clusterIP}", "cache.host")},
+			want: map[string]interface{}{
+				"replicas": 2,
+				"cache":    map[string]interface{}{"host": "10.0.0.1"},
+			},
+		},
+		{
+			name: "merges path without braces",
+			refs: []v2.FieldValuesReference{ref(".spec.ports[0].port", "cache.port")},
+			want: map[string]interface{}{
+				"cache": map[string]interface{}{"port": float64(6379)},
+			},
+		},
+		{
+			name: "merges map value",
+			refs: []v2.FieldValuesReference{ref("{.metadata.labels}", "labels")},
+			want: map[string]interface{}{
+				"labels": map[string]interface{}{"app": "cache"},
+			},
+		},
+		{
+			name: "overwrites existing value",
+			values: map[string]interface{}{
+				"cache": map[string]interface{}{"host": "localhost", "port": 6379},
+			},
+			refs: []v2.FieldValuesReference{ref("{.spec.clusterIP}", "cache.host")},
+			want: map[string]interface{}{
+				"cache": map[string]interface{}{"host": "10.0.0.1", "port": 6379},
+			},
+		},
+		{
+			name:    "multiple values",
+			refs:    []v2.FieldValuesReference{ref("{.spec.ports[*].port}", "ports")},
+			wantErr: "selects 2 values, expected a single value",
+		},
+		{
+			name:    "field not found",
+			refs:    []v2.FieldValuesReference{ref("{.status.endpoint}", "endpoint")},
+			wantErr: "field not found",
+		},
+		{
+			name: "optional field not found",
+			refs: []v2.FieldValuesReference{func() v2.FieldValuesReference {
+				r := ref("{.status.endpoint}", "endpoint")
+				r.Optional = true
+				return r
+			}()},
+			want: map[string]interface{}{},
+		},
+		{
+			name: "referent not found",
+			refs: []v2.FieldValuesReference{func() v2.FieldValuesReference {
+				r := ref("{.spec.clusterIP}", "cache.host")
+				r.Name = "missing"
+				return r
+			}()},
+			wantErr: "could not get Service 'default/missing'",
+		},
+		{
+			name: "optional referent not found",
+			refs: []v2.FieldValuesReference{func() v2.FieldValuesReference {
+				r := ref("{.spec.clusterIP}", "cache.host")
+				r.Name = "missing"
+				r.Optional = true
+				return r
+			}()},
+			want: map[string]interface{}{},
+		},
+		{
+			name:    "invalid field path",
+			refs:    []v2.FieldValuesReference{ref("{.spec[}", "cache.host")},
+			wantErr: "invalid field path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := MergeFieldReferences(context.TODO(), c, "default", tt.values, tt.refs...)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package values
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jessesimpson36/helm/v4/pkg/strvals"
+
+	"github.com/fluxcd/pkg/chartutil"
+)
+
+// MergeAtPath merges layer into values. When targetPath is empty, layer is
+// merged at the root of values, with the keys of layer taking precedence.
+// Otherwise, layer is set at the YAML dot notation targetPath.
+func MergeAtPath(values map[string]interface{}, targetPath string, layer map[string]interface{}) (map[string]interface{}, error) {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	if targetPath == "" {
+		return chartutil.MergeMaps(values, layer), nil
+	}
+
+	b, err := json.Marshal(layer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode values: %w", err)
+	}
+	if err = strvals.ParseJSON(fmt.Sprintf("%s=%s", targetPath, b), values); err != nil {
+		return nil, fmt.Errorf("unable to merge values at path '%s': %w", targetPath, err)
+	}
+	return values, nil
+}
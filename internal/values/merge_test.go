@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package values
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMergeAtPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     map[string]interface{}
+		targetPath string
+		layer      map[string]interface{}
+		want       map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name: "merges at root",
+			values: map[string]interface{}{
+				"replicas": 1,
+				"image":    map[string]interface{}{"repository": "podinfo", "tag": "6.0.0"},
+			},
+			layer: map[string]interface{}{
+				"image": map[string]interface{}{"tag": "6.1.0"},
+			},
+			want: map[string]interface{}{
+				"replicas": 1,
+				"image":    map[string]interface{}{"repository": "podinfo", "tag": "6.1.0"},
+			},
+		},
+		{
+			name:   "merges into nil values",
+			values: nil,
+			layer:  map[string]interface{}{"replicas": 2},
+			want:   map[string]interface{}{"replicas": 2},
+		},
+		{
+			name:       "sets at target path",
+			values:     map[string]interface{}{"replicas": 1},
+			targetPath: "base.image",
+			layer:      map[string]interface{}{"tag": "6.1.0"},
+			want: map[string]interface{}{
+				"replicas": 1,
+				"base": map[string]interface{}{
+					"image": map[string]interface{}{"tag": "6.1.0"},
+				},
+			},
+		},
+		{
+			name:       "invalid target path",
+			values:     map[string]interface{}{},
+			targetPath: "a[",
+			layer:      map[string]interface{}{"tag": "6.1.0"},
+			wantErr:    "unable to merge values at path 'a['",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := MergeAtPath(tt.values, tt.targetPath, tt.layer)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package values
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/fluxcd/pkg/chartutil"
+	"github.com/opencontainers/go-digest"
+)
+
+// Digest returns the digest of the normalized form of the given values,
+// calculated using the given algorithm. Semantically identical values
+// composed from different sources result in the same digest.
+func Digest(algo digest.Algorithm, values map[string]interface{}) digest.Digest {
+	return chartutil.DigestValues(algo, Normalize(values))
+}
+
+// Verify returns true if the given digest matches the normalized form of
+// the given values.
+func Verify(d digest.Digest, values map[string]interface{}) bool {
+	return chartutil.VerifyValues(d, Normalize(values))
+}
+
+// Normalize returns a copy of the given values in a canonical form, to
+// ensure semantically identical values result in the same digest,
+// regardless of the source they were composed from. The copy is only meant
+// to be digested: the values passed to Helm are not normalized, as charts
+// may depend on the type of a number, e.g. using the kindIs template
+// function.
+//
+// Numbers with an integral value which fits in an int64 are represented as
+// int64, and all other numbers as float64. This prevents e.g. a 1000000
+// composed from a JSON source from being encoded as 1e+06. Maps with
+// non-string keys are converted to maps with string keys. Map keys are
+// unordered in Go, and sorted on encoding.
+func Normalize(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+	return normalizeValue(values).(map[string]interface{})
+}
+
+// normalizeValue returns the canonical form of the given value.
+func normalizeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			out[k] = normalizeValue(val)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			out[fmt.Sprint(k)] = normalizeValue(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = normalizeValue(val)
+		}
+		return out
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint:
+		return normalizeUint(uint64(v))
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		return normalizeUint(v)
+	case float32:
+		return normalizeFloat(float64(v))
+	case float64:
+		return normalizeFloat(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return normalizeFloat(f)
+		}
+		return v.String()
+	default:
+		return v
+	}
+}
+
+// normalizeUint returns the given uint64 as an int64 if it fits, or as a
+// float64 otherwise.
+func normalizeUint(v uint64) interface{} {
+	if v > math.MaxInt64 {
+		return float64(v)
+	}
+	return int64(v)
+}
+
+// normalizeFloat returns the given float64 as an int64 if it has an integral
+// value which fits in an int64, or as is otherwise.
+func normalizeFloat(v float64) interface{} {
+	if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+		return int64(v)
+	}
+	return v
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package values
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/helm-controller/internal/digest"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{
+			name:   "nil",
+			values: nil,
+			want:   nil,
+		},
+		{
+			name: "integral numbers",
+			values: map[string]interface{}{
+				"int":     3,
+				"uint":    uint32(3),
+				"float":   float64(1000000),
+				"number":  json.Number("42"),
+				"enabled": true,
+			},
+			want: map[string]interface{}{
+				"int":     int64(3),
+				"uint":    int64(3),
+				"float":   int64(1000000),
+				"number":  int64(42),
+				"enabled": true,
+			},
+		},
+		{
+			name: "fractional and out of range numbers",
+			values: map[string]interface{}{
+				"float":  0.5,
+				"number": json.Number("1.5"),
+				"large":  1e20,
+				"uint":   uint64(math.MaxUint64),
+			},
+			want: map[string]interface{}{
+				"float":  0.5,
+				"number": 1.5,
+				"large":  1e20,
+				"uint":   float64(math.MaxUint64),
+			},
+		},
+		{
+			name: "nested maps and lists",
+			values: map[string]interface{}{
+				"nested": map[interface{}]interface{}{
+					"replicas": float64(2),
+					1:          "one",
+				},
+				"ports": []interface{}{float64(80), map[string]interface{}{"port": float64(443)}},
+			},
+			want: map[string]interface{}{
+				"nested": map[string]interface{}{
+					"replicas": int64(2),
+					"1":        "one",
+				},
+				"ports": []interface{}{int64(80), map[string]interface{}{"port": int64(443)}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Normalize(tt.values)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNormalize_digest(t *testing.T) {
+	g := NewWithT(t)
+
+	fromYAML := map[string]interface{}{"replicas": 3, "resources": map[string]interface{}{"cpu": 0.5}}
+	fromJSON := map[string]interface{}{"resources": map[string]interface{}{"cpu": 0.5}, "replicas": float64(3)}
+
+	d := Digest(digest.Canonical, fromYAML)
+	g.Expect(d).To(Equal(Digest(digest.Canonical, fromJSON)))
+	g.Expect(Verify(d, fromJSON)).To(BeTrue())
+	g.Expect(Verify(d, map[string]interface{}{"replicas": 4})).To(BeFalse())
+
+	// The values themselves are left untouched.
+	g.Expect(fromJSON["replicas"]).To(Equal(float64(3)))
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook implements the admission webhooks of the controller.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+	intreconcile "github.com/fluxcd/helm-controller/internal/reconcile"
+)
+
+const (
+	// releaseNameMaxLength is the maximum length of a Helm release name.
+	releaseNameMaxLength = 53
+)
+
+// releaseNameRegexp matches valid Helm release names, as validated by Helm.
+var releaseNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// HelmReleaseValidator validates the admission requests for HelmReleases.
+//
+// On creation, and on updates changing the spec, it rejects HelmReleases
+// which are guaranteed to fail to reconcile, e.g. because of a malformed
+// version constraint or a dependency cycle. This provides synchronous
+// feedback to the user, instead of a failing reconciliation.
+//
+// It rejects the deletion of a HelmRelease marked as critical with the
+// v2.CriticalAnnotation, unless the v2.BreakGlassAnnotation is present. The
+// deletion of the namespace of a critical HelmRelease is rejected by the
+// NamespaceValidator.
+type HelmReleaseValidator struct {
+	// Client is used to look up the dependencies of a HelmRelease.
+	Client client.Reader
+}
+
+var _ admission.CustomValidator = &HelmReleaseValidator{}
+
+// SetupWithManager registers the validating webhook for HelmReleases with
+// the webhook server of the given manager.
+func (v *HelmReleaseValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v2.HelmRelease{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates the creation of a HelmRelease.
+func (v *HelmReleaseValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	hr, ok := obj.(*v2.HelmRelease)
+	if !ok {
+		return nil, fmt.Errorf("expected a HelmRelease, got %T", obj)
+	}
+	return v.validate(ctx, hr)
+}
+
+// ValidateUpdate validates the update of a HelmRelease. The
+// v2.PreviewAnnotation can not be added, changed or removed, as it is only
+// honoured at creation. Other updates which do not change the spec, or are
+// made while the HelmRelease is being deleted, are always allowed. This
+// ensures e.g. annotations can still be added to a HelmRelease which was
+// admitted before a validation was introduced.
+func (v *HelmReleaseValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldHr, ok := oldObj.(*v2.HelmRelease)
+	if !ok {
+		return nil, fmt.Errorf("expected a HelmRelease, got %T", oldObj)
+	}
+	hr, ok := newObj.(*v2.HelmRelease)
+	if !ok {
+		return nil, fmt.Errorf("expected a HelmRelease, got %T", newObj)
+	}
+	if !hr.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	if oldHr.GetPreviewID() != hr.GetPreviewID() {
+		fldPath := field.NewPath("metadata", "annotations").Key(v2.PreviewAnnotation)
+		return nil, apierrors.NewInvalid(v2.GroupVersion.WithKind(v2.HelmReleaseKind).GroupKind(), hr.GetName(),
+			field.ErrorList{field.Forbidden(fldPath, "can only be set at creation")})
+	}
+	if apiequality.Semantic.DeepEqual(oldHr.Spec, hr.Spec) {
+		return nil, nil
+	}
+	return v.validate(ctx, hr)
+}
+
+// ValidateDelete rejects the deletion of a HelmRelease which is protected
+// from deletion, as determined by v2.HelmRelease.IsDeletionProtected.
+func (v *HelmReleaseValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	hr, ok := obj.(*v2.HelmRelease)
+	if !ok {
+		return nil, fmt.Errorf("expected a HelmRelease, got %T", obj)
+	}
+	if hr.IsDeletionProtected() {
+		return nil, fmt.Errorf("HelmRelease '%s/%s' is marked as critical with the '%s' annotation, "+
+			"annotate it with '%s=true' to allow its deletion",
+			hr.GetNamespace(), hr.GetName(), v2.CriticalAnnotation, v2.BreakGlassAnnotation)
+	}
+	return nil, nil
+}
+
+// validate returns an Invalid error listing the problems with the spec of
+// the given HelmRelease, and warnings for options without effect.
+func (v *HelmReleaseValidator) validate(ctx context.Context, hr *v2.HelmRelease) (admission.Warnings, error) {
+	specPath := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateReleaseName(hr, specPath.Child("releaseName"))...)
+	allErrs = append(allErrs, validatePreview(hr, field.NewPath("metadata", "annotations").Key(v2.PreviewAnnotation))...)
+	allErrs = append(allErrs, validateVersions(hr, specPath)...)
+	allErrs = append(allErrs, validateChartTemplateExpressions(hr, specPath)...)
+	allErrs = append(allErrs, validateActions(hr, specPath)...)
+	allErrs = append(allErrs, validateValues(hr, specPath.Child("values"))...)
+
+	dependsOnErrs, err := v.validateDependsOn(ctx, hr, specPath.Child("dependsOn"))
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, dependsOnErrs...)
+
+	var warnings admission.Warnings
+	if hr.GetInstall().CreateNamespace && hr.Spec.TargetNamespace == "" {
+		warnings = append(warnings, "spec.install.createNamespace has no effect without spec.targetNamespace")
+	}
+
+	if len(allErrs) > 0 {
+		return warnings, apierrors.NewInvalid(v2.GroupVersion.WithKind(v2.HelmReleaseKind).GroupKind(), hr.GetName(), allErrs)
+	}
+	return warnings, nil
+}
+
+// validateReleaseName validates the release name of the HelmRelease against
+// the requirements of Helm.
+func validateReleaseName(hr *v2.HelmRelease, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	name := hr.Spec.ReleaseName
+	if name == "" {
+		return nil
+	}
+	if len(name) > releaseNameMaxLength {
+		allErrs = append(allErrs, field.TooLong(fldPath, name, releaseNameMaxLength))
+	}
+	if !releaseNameRegexp.MatchString(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name,
+			"must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character"))
+	}
+	return allErrs
+}
+
+// validatePreview validates the identifier of the preview environment the
+// HelmRelease is stamped for is a DNS label, and the release name and target
+// namespace suffixed with it do not exceed their maximum length. A release
+// name composed from the name of the HelmRelease is shortened by the
+// controller, and is therefore not validated.
+func validatePreview(hr *v2.HelmRelease, fldPath *field.Path) field.ErrorList {
+	id := hr.GetPreviewID()
+	if id == "" {
+		return nil
+	}
+	var allErrs field.ErrorList
+	for _, msg := range validation.IsDNS1123Label(id) {
+		allErrs = append(allErrs, field.Invalid(fldPath, id, msg))
+	}
+	if hr.Spec.ReleaseName != "" {
+		if name := hr.GetReleaseName(); len(name) > releaseNameMaxLength {
+			allErrs = append(allErrs, field.Invalid(fldPath, id,
+				fmt.Sprintf("release name '%s' suffixed with the preview identifier must be no more than %d characters",
+					name, releaseNameMaxLength)))
+		}
+	}
+	if hr.Spec.TargetNamespace != "" {
+		if ns := hr.GetReleaseNamespace(); len(ns) > validation.DNS1123LabelMaxLength {
+			allErrs = append(allErrs, field.Invalid(fldPath, id,
+				fmt.Sprintf("target namespace '%s' suffixed with the preview identifier must be no more than %d characters",
+					ns, validation.DNS1123LabelMaxLength)))
+		}
+	}
+	return allErrs
+}
+
+// validateChartTemplateExpressions validates the Go template expressions in
+// the chart name and version of the chart template can be parsed. Their
+// rendering depends on the labels and annotations of the object, and is left
+// to the controller.
+func validateChartTemplateExpressions(hr *v2.HelmRelease, specPath *field.Path) field.ErrorList {
+	if hr.Spec.Chart == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	for _, f := range []struct {
+		name  string
+		value string
+	}{
+		{"chart", hr.Spec.Chart.Spec.Chart},
+		{"version", hr.Spec.Chart.Spec.Version},
+	} {
+		if !intreconcile.IsChartTemplateExpression(f.value) {
+			continue
+		}
+		if _, err := intreconcile.ParseChartTemplateExpression(f.value); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("chart", "spec", f.name), f.value,
+				fmt.Sprintf("invalid template: %s", err)))
+		}
+	}
+	return allErrs
+}
+
+// validateVersions validates the semver range constraints of the chart
+// template and the dependencies of the HelmRelease.
+func validateVersions(hr *v2.HelmRelease, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if hr.Spec.Chart != nil && hr.Spec.Chart.Spec.SourceRef.Kind == sourcev1.HelmRepositoryKind {
+		if version := hr.Spec.Chart.Spec.Version; version != "" && !intreconcile.IsChartTemplateExpression(version) {
+			if _, err := semver.NewConstraint(version); err != nil {
+				allErrs = append(allErrs, field.Invalid(specPath.Child("chart", "spec", "version"), version,
+					fmt.Sprintf("invalid semver range: %s", err)))
+			}
+		}
+	}
+	for i, dep := range hr.Spec.DependsOn {
+		if dep.Version == "" {
+			continue
+		}
+		if _, err := semver.NewConstraint(dep.Version); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("dependsOn").Index(i).Child("version"), dep.Version,
+				fmt.Sprintf("invalid semver range: %s", err)))
+		}
+	}
+	return allErrs
+}
+
+// validateActions validates the configuration of the Helm actions of the
+// HelmRelease does not contain conflicting options.
+func validateActions(hr *v2.HelmRelease, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	install := hr.GetInstall()
+	if install.SkipCRDs && install.CRDs != "" && install.CRDs != v2.Skip {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("install", "crds"), install.CRDs,
+			"conflicts with spec.install.skipCRDs"))
+	}
+	remediation, ok := hr.GetUpgrade().GetRemediation().(v2.UpgradeRemediation)
+	if ok && remediation.MaxRollbackFailures > 0 && remediation.GetStrategy() != v2.RollbackRemediationStrategy {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("upgrade", "remediation", "maxRollbackFailures"),
+			remediation.MaxRollbackFailures,
+			fmt.Sprintf("only applies to the '%s' remediation strategy", v2.RollbackRemediationStrategy)))
+	}
+	return allErrs
+}
+
+// validateValues validates the inline values of the HelmRelease are a map.
+func validateValues(hr *v2.HelmRelease, fldPath *field.Path) field.ErrorList {
+	if hr.Spec.Values == nil || len(hr.Spec.Values.Raw) == 0 {
+		return nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(hr.Spec.Values.Raw, &values); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, string(hr.Spec.Values.Raw), "must be a map of values")}
+	}
+	return nil
+}
+
+// validateDependsOn validates the dependencies of the HelmRelease do not
+// (transitively) depend on the HelmRelease itself. Dependencies which do
+// not exist (yet) are ignored.
+func (v *HelmReleaseValidator) validateDependsOn(ctx context.Context, hr *v2.HelmRelease, fldPath *field.Path) (field.ErrorList, error) {
+	self := client.ObjectKeyFromObject(hr)
+	visited := map[types.NamespacedName]bool{}
+
+	var findCycle func(obj *v2.HelmRelease, path []string) ([]string, error)
+	findCycle = func(obj *v2.HelmRelease, path []string) ([]string, error) {
+		for _, dep := range obj.Spec.DependsOn {
+			key := types.NamespacedName{Namespace: dep.Namespace, Name: dep.Name}
+			if key.Namespace == "" {
+				key.Namespace = obj.GetNamespace()
+			}
+			if key == self {
+				return append(path, key.String()), nil
+			}
+			if visited[key] || v.Client == nil {
+				continue
+			}
+			visited[key] = true
+
+			var depHr v2.HelmRelease
+			if err := v.Client.Get(ctx, key, &depHr); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get dependency '%s': %w", key, err)
+			}
+			if cycle, err := findCycle(&depHr, append(path, key.String())); cycle != nil || err != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+
+	cycle, err := findCycle(hr, []string{self.String()})
+	if err != nil {
+		return nil, err
+	}
+	if cycle != nil {
+		return field.ErrorList{field.Invalid(fldPath, strings.Join(cycle, " -> "), "dependency cycle detected")}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// HelmReleaseDefaulter sets the configured defaults on the fields of a
+// HelmRelease which are not set when the HelmRelease is created or updated.
+// This allows a platform team to enforce a baseline for all HelmReleases,
+// which is persisted in the spec of the HelmReleases.
+//
+// A field is considered set when it is present in the admitted object, which
+// allows e.g. an explicit interval of '0s' or zero retries to be retained.
+type HelmReleaseDefaulter struct {
+	// Interval is the default for .spec.interval. Zero does not default it.
+	Interval time.Duration
+	// Timeout is the default for .spec.timeout. Zero does not default it.
+	Timeout time.Duration
+	// MaxHistory is the default for .spec.maxHistory. Nil does not default
+	// it.
+	MaxHistory *int
+	// InstallRetries is the default for .spec.install.remediation.retries.
+	// Zero does not default it.
+	InstallRetries int
+	// UpgradeRetries is the default for .spec.upgrade.remediation.retries.
+	// Zero does not default it.
+	UpgradeRetries int
+}
+
+var _ admission.CustomDefaulter = &HelmReleaseDefaulter{}
+
+// SetupWithManager registers the mutating webhook for HelmReleases with the
+// webhook server of the given manager.
+func (d *HelmReleaseDefaulter) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v2.HelmRelease{}).
+		WithDefaulter(d).
+		Complete()
+}
+
+// Default sets the configured defaults on the given HelmRelease.
+func (d *HelmReleaseDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	hr, ok := obj.(*v2.HelmRelease)
+	if !ok {
+		return fmt.Errorf("expected a HelmRelease, got %T", obj)
+	}
+
+	isSet, err := admittedFields(ctx)
+	if err != nil {
+		return err
+	}
+
+	if d.Interval > 0 && !isSet(hr.Spec.Interval.Duration != 0, "spec", "interval") {
+		hr.Spec.Interval = metav1.Duration{Duration: d.Interval}
+	}
+	if d.Timeout > 0 && !isSet(hr.Spec.Timeout != nil, "spec", "timeout") {
+		hr.Spec.Timeout = &metav1.Duration{Duration: d.Timeout}
+	}
+	if d.MaxHistory != nil && !isSet(hr.Spec.MaxHistory != nil, "spec", "maxHistory") {
+		maxHistory := *d.MaxHistory
+		hr.Spec.MaxHistory = &maxHistory
+	}
+	if d.InstallRetries != 0 {
+		install := hr.GetInstall()
+		if !isSet(install.Remediation != nil && install.Remediation.Retries != 0, "spec", "install", "remediation", "retries") {
+			if hr.Spec.Install == nil {
+				hr.Spec.Install = &v2.Install{}
+			}
+			if hr.Spec.Install.Remediation == nil {
+				hr.Spec.Install.Remediation = &v2.InstallRemediation{}
+			}
+			hr.Spec.Install.Remediation.Retries = d.InstallRetries
+		}
+	}
+	if d.UpgradeRetries != 0 {
+		upgrade := hr.GetUpgrade()
+		if !isSet(upgrade.Remediation != nil && upgrade.Remediation.Retries != 0, "spec", "upgrade", "remediation", "retries") {
+			if hr.Spec.Upgrade == nil {
+				hr.Spec.Upgrade = &v2.Upgrade{}
+			}
+			if hr.Spec.Upgrade.Remediation == nil {
+				hr.Spec.Upgrade.Remediation = &v2.UpgradeRemediation{}
+			}
+			hr.Spec.Upgrade.Remediation.Retries = d.UpgradeRetries
+		}
+	}
+	return nil
+}
+
+// admittedFields returns a function which reports if the field at the given
+// path is present in the object of the admission request in the context.
+// Without an admission request in the context, it falls back to the given
+// value, which is expected to report if the field has a non-zero value.
+func admittedFields(ctx context.Context) (func(fallback bool, path ...string) bool, error) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || len(req.Object.Raw) == 0 {
+		return func(fallback bool, _ ...string) bool {
+			return fallback
+		}, nil
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(req.Object.Raw, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode admitted object: %w", err)
+	}
+	return func(_ bool, path ...string) bool {
+		_, found, _ := unstructured.NestedFieldNoCopy(raw, path...)
+		return found
+	}, nil
+}
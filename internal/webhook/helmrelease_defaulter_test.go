@@ -0,0 +1,161 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestHelmReleaseDefaulter_Default(t *testing.T) {
+	defaulter := &HelmReleaseDefaulter{
+		Interval:       10 * time.Minute,
+		Timeout:        15 * time.Minute,
+		MaxHistory:     ptr.To(3),
+		InstallRetries: 2,
+		UpgradeRetries: 4,
+	}
+
+	tests := []struct {
+		name      string
+		defaulter *HelmReleaseDefaulter
+		raw       string
+		spec      v2.HelmReleaseSpec
+		want      v2.HelmReleaseSpec
+	}{
+		{
+			name:      "sets defaults",
+			defaulter: defaulter,
+			raw:       `{"spec":{}}`,
+			want: v2.HelmReleaseSpec{
+				Interval:   metav1.Duration{Duration: 10 * time.Minute},
+				Timeout:    &metav1.Duration{Duration: 15 * time.Minute},
+				MaxHistory: ptr.To(3),
+				Install: &v2.Install{
+					Remediation: &v2.InstallRemediation{Retries: 2},
+				},
+				Upgrade: &v2.Upgrade{
+					Remediation: &v2.UpgradeRemediation{Retries: 4},
+				},
+			},
+		},
+		{
+			name:      "retains set fields",
+			defaulter: defaulter,
+			raw:       `{"spec":{"interval":"0s","timeout":"1m","maxHistory":0,"install":{"remediation":{"retries":0}},"upgrade":{"remediation":{"retries":-1}}}}`,
+			spec: v2.HelmReleaseSpec{
+				Timeout:    &metav1.Duration{Duration: time.Minute},
+				MaxHistory: ptr.To(0),
+				Install: &v2.Install{
+					Remediation: &v2.InstallRemediation{},
+				},
+				Upgrade: &v2.Upgrade{
+					Remediation: &v2.UpgradeRemediation{Retries: -1},
+				},
+			},
+			want: v2.HelmReleaseSpec{
+				Timeout:    &metav1.Duration{Duration: time.Minute},
+				MaxHistory: ptr.To(0),
+				Install: &v2.Install{
+					Remediation: &v2.InstallRemediation{},
+				},
+				Upgrade: &v2.Upgrade{
+					Remediation: &v2.UpgradeRemediation{Retries: -1},
+				},
+			},
+		},
+		{
+			name:      "retains other install and upgrade fields",
+			defaulter: defaulter,
+			raw:       `{"spec":{"interval":"5m","install":{"createNamespace":true},"upgrade":{"remediation":{"strategy":"uninstall"}}}}`,
+			spec: v2.HelmReleaseSpec{
+				Interval: metav1.Duration{Duration: 5 * time.Minute},
+				Install:  &v2.Install{CreateNamespace: true},
+				Upgrade: &v2.Upgrade{
+					Remediation: &v2.UpgradeRemediation{Strategy: ptr.To(v2.UninstallRemediationStrategy)},
+				},
+			},
+			want: v2.HelmReleaseSpec{
+				Interval:   metav1.Duration{Duration: 5 * time.Minute},
+				Timeout:    &metav1.Duration{Duration: 15 * time.Minute},
+				MaxHistory: ptr.To(3),
+				Install: &v2.Install{
+					CreateNamespace: true,
+					Remediation:     &v2.InstallRemediation{Retries: 2},
+				},
+				Upgrade: &v2.Upgrade{
+					Remediation: &v2.UpgradeRemediation{
+						Strategy: ptr.To(v2.UninstallRemediationStrategy),
+						Retries:  4,
+					},
+				},
+			},
+		},
+		{
+			name:      "without configured defaults",
+			defaulter: &HelmReleaseDefaulter{},
+			raw:       `{"spec":{}}`,
+			want:      v2.HelmReleaseSpec{},
+		},
+		{
+			name:      "without admission request",
+			defaulter: defaulter,
+			spec: v2.HelmReleaseSpec{
+				Interval: metav1.Duration{Duration: 5 * time.Minute},
+			},
+			want: v2.HelmReleaseSpec{
+				Interval:   metav1.Duration{Duration: 5 * time.Minute},
+				Timeout:    &metav1.Duration{Duration: 15 * time.Minute},
+				MaxHistory: ptr.To(3),
+				Install: &v2.Install{
+					Remediation: &v2.InstallRemediation{Retries: 2},
+				},
+				Upgrade: &v2.Upgrade{
+					Remediation: &v2.UpgradeRemediation{Retries: 4},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.TODO()
+			if tt.raw != "" {
+				ctx = admission.NewContextWithRequest(ctx, admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Object: runtime.RawExtension{Raw: []byte(tt.raw)},
+					},
+				})
+			}
+
+			obj := &v2.HelmRelease{Spec: tt.spec}
+			g.Expect(tt.defaulter.Default(ctx, obj)).To(Succeed())
+			g.Expect(obj.Spec).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,377 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestHelmReleaseValidator_ValidateDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     string
+	}{
+		{
+			name: "allows deletion",
+		},
+		{
+			name:        "rejects deletion of critical HelmRelease",
+			annotations: map[string]string{v2.CriticalAnnotation: "true"},
+			wantErr:     "HelmRelease 'kube-system/cilium' is marked as critical with the 'helm.toolkit.fluxcd.io/critical' annotation",
+		},
+		{
+			name: "allows deletion of critical HelmRelease with break-glass",
+			annotations: map[string]string{
+				v2.CriticalAnnotation:   "true",
+				v2.BreakGlassAnnotation: "true",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cilium",
+					Namespace:   "kube-system",
+					Annotations: tt.annotations,
+				},
+			}
+
+			_, err := (&HelmReleaseValidator{}).ValidateDelete(context.TODO(), obj)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestHelmReleaseValidator_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		spec         v2.HelmReleaseSpec
+		objects      []*v2.HelmRelease
+		wantErr      []string
+		wantWarnings []string
+	}{
+		{
+			name: "valid spec",
+			spec: v2.HelmReleaseSpec{
+				ReleaseName: "podinfo",
+				Chart: &v2.HelmChartTemplate{
+					Spec: v2.HelmChartTemplateSpec{
+						Chart:     "podinfo",
+						Version:   ">=6.0.0 <7.0.0",
+						SourceRef: v2.CrossNamespaceObjectReference{Kind: sourcev1.HelmRepositoryKind, Name: "podinfo"},
+					},
+				},
+				DependsOn: []v2.DependencyReference{{Name: "redis", Version: "~7.2"}},
+				Values:    &apiextensionsv1.JSON{Raw: []byte(`{"replicaCount":2}`)},
+			},
+		},
+		{
+			name: "release name too long",
+			spec: v2.HelmReleaseSpec{
+				ReleaseName: "a-very-long-release-name-which-exceeds-the-helm-limits",
+			},
+			wantErr: []string{"spec.releaseName: Too long"},
+		},
+		{
+			name: "invalid release name",
+			spec: v2.HelmReleaseSpec{
+				ReleaseName: "Podinfo_",
+			},
+			wantErr: []string{"spec.releaseName: Invalid value"},
+		},
+		{
+			name:        "preview",
+			annotations: map[string]string{v2.PreviewAnnotation: "pr-1"},
+			spec: v2.HelmReleaseSpec{
+				ReleaseName:     "podinfo",
+				TargetNamespace: "podinfo",
+			},
+		},
+		{
+			name:        "invalid preview identifier",
+			annotations: map[string]string{v2.PreviewAnnotation: "PR_1"},
+			wantErr:     []string{"metadata.annotations[helm.toolkit.fluxcd.io/preview]: Invalid value"},
+		},
+		{
+			name:        "release name with preview suffix too long",
+			annotations: map[string]string{v2.PreviewAnnotation: "pr-1"},
+			spec: v2.HelmReleaseSpec{
+				ReleaseName: "a-long-release-name-which-fits-the-helm-limits-as-is",
+			},
+			wantErr: []string{"release name 'a-long-release-name-which-fits-the-helm-limits-as-is-pr-1'"},
+		},
+		{
+			name:        "target namespace with preview suffix too long",
+			annotations: map[string]string{v2.PreviewAnnotation: "pr-1"},
+			spec: v2.HelmReleaseSpec{
+				ReleaseName:     "podinfo",
+				TargetNamespace: "a-long-target-namespace-which-fits-the-limits-of-a-dns-label",
+			},
+			wantErr: []string{"target namespace 'a-long-target-namespace-which-fits-the-limits-of-a-dns-label-pr-1'"},
+		},
+		{
+			name: "malformed chart version range",
+			spec: v2.HelmReleaseSpec{
+				Chart: &v2.HelmChartTemplate{
+					Spec: v2.HelmChartTemplateSpec{
+						Chart:     "podinfo",
+						Version:   ">= 6.x.y",
+						SourceRef: v2.CrossNamespaceObjectReference{Kind: sourcev1.HelmRepositoryKind, Name: "podinfo"},
+					},
+				},
+			},
+			wantErr: []string{"spec.chart.spec.version: Invalid value"},
+		},
+		{
+			name: "ignores chart version of GitRepository",
+			spec: v2.HelmReleaseSpec{
+				Chart: &v2.HelmChartTemplate{
+					Spec: v2.HelmChartTemplateSpec{
+						Chart:     "./charts/podinfo",
+						Version:   "not-a-range",
+						SourceRef: v2.CrossNamespaceObjectReference{Kind: sourcev1.GitRepositoryKind, Name: "podinfo"},
+					},
+				},
+			},
+		},
+		{
+			name: "chart version template",
+			spec: v2.HelmReleaseSpec{
+				Chart: &v2.HelmChartTemplate{
+					Spec: v2.HelmChartTemplateSpec{
+						Chart:     "{{ .Labels.app }}",
+						Version:   `{{ index .Labels "version" | default "*" }}`,
+						SourceRef: v2.CrossNamespaceObjectReference{Kind: sourcev1.HelmRepositoryKind, Name: "podinfo"},
+					},
+				},
+			},
+		},
+		{
+			name: "malformed chart name template",
+			spec: v2.HelmReleaseSpec{
+				Chart: &v2.HelmChartTemplate{
+					Spec: v2.HelmChartTemplateSpec{
+						Chart:     "{{ .Labels.app",
+						SourceRef: v2.CrossNamespaceObjectReference{Kind: sourcev1.HelmRepositoryKind, Name: "podinfo"},
+					},
+				},
+			},
+			wantErr: []string{"spec.chart.spec.chart: Invalid value"},
+		},
+		{
+			name: "malformed dependency version range",
+			spec: v2.HelmReleaseSpec{
+				DependsOn: []v2.DependencyReference{{Name: "redis", Version: "seven"}},
+			},
+			wantErr: []string{"spec.dependsOn[0].version: Invalid value"},
+		},
+		{
+			name: "conflicting install CRDs options",
+			spec: v2.HelmReleaseSpec{
+				Install: &v2.Install{SkipCRDs: true, CRDs: v2.CreateReplace},
+			},
+			wantErr: []string{"spec.install.crds: Invalid value"},
+		},
+		{
+			name: "max rollback failures with uninstall remediation",
+			spec: v2.HelmReleaseSpec{
+				Upgrade: &v2.Upgrade{
+					Remediation: &v2.UpgradeRemediation{
+						Strategy:            ptr.To(v2.UninstallRemediationStrategy),
+						MaxRollbackFailures: 3,
+					},
+				},
+			},
+			wantErr: []string{"spec.upgrade.remediation.maxRollbackFailures: Invalid value"},
+		},
+		{
+			name: "values not a map",
+			spec: v2.HelmReleaseSpec{
+				Values: &apiextensionsv1.JSON{Raw: []byte(`["replicaCount"]`)},
+			},
+			wantErr: []string{"spec.values: Invalid value"},
+		},
+		{
+			name: "create namespace without target namespace",
+			spec: v2.HelmReleaseSpec{
+				Install: &v2.Install{CreateNamespace: true},
+			},
+			wantWarnings: []string{"spec.install.createNamespace has no effect without spec.targetNamespace"},
+		},
+		{
+			name: "depends on itself",
+			spec: v2.HelmReleaseSpec{
+				DependsOn: []v2.DependencyReference{{Name: "podinfo"}},
+			},
+			wantErr: []string{"default/podinfo -> default/podinfo"},
+		},
+		{
+			name: "transitive dependency cycle",
+			spec: v2.HelmReleaseSpec{
+				DependsOn: []v2.DependencyReference{{Name: "backend"}},
+			},
+			objects: []*v2.HelmRelease{
+				newHelmRelease("backend", "default", v2.DependencyReference{Name: "database", Namespace: "data"}),
+				newHelmRelease("database", "data", v2.DependencyReference{Name: "podinfo", Namespace: "default"}),
+			},
+			wantErr: []string{"default/podinfo -> default/backend -> data/database -> default/podinfo"},
+		},
+		{
+			name: "acyclic dependencies",
+			spec: v2.HelmReleaseSpec{
+				DependsOn: []v2.DependencyReference{{Name: "backend"}, {Name: "missing"}},
+			},
+			objects: []*v2.HelmRelease{
+				newHelmRelease("backend", "default", v2.DependencyReference{Name: "database"}),
+				newHelmRelease("database", "default"),
+			},
+		},
+		{
+			name: "reports all errors",
+			spec: v2.HelmReleaseSpec{
+				ReleaseName: "Podinfo",
+				DependsOn:   []v2.DependencyReference{{Name: "redis", Version: "seven"}},
+				Values:      &apiextensionsv1.JSON{Raw: []byte(`"replicaCount"`)},
+			},
+			wantErr: []string{"spec.releaseName", "spec.dependsOn[0].version", "spec.values"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(v2.AddToScheme(scheme)).To(Succeed())
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, obj := range tt.objects {
+				builder.WithObjects(obj)
+			}
+
+			obj := &v2.HelmRelease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "podinfo",
+					Namespace:   "default",
+					Annotations: tt.annotations,
+				},
+				Spec: tt.spec,
+			}
+
+			warnings, err := (&HelmReleaseValidator{Client: builder.Build()}).ValidateCreate(context.TODO(), obj)
+			if len(tt.wantWarnings) > 0 {
+				g.Expect(warnings).To(ConsistOf(tt.wantWarnings))
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+			if len(tt.wantErr) > 0 {
+				g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				for _, want := range tt.wantErr {
+					g.Expect(err).To(MatchError(ContainSubstring(want)))
+				}
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestHelmReleaseValidator_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	invalid := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "podinfo",
+			Namespace: "default",
+		},
+		Spec: v2.HelmReleaseSpec{
+			ReleaseName: "Podinfo",
+		},
+	}
+	v := &HelmReleaseValidator{}
+
+	// Updates which do not change the spec are allowed.
+	annotated := invalid.DeepCopy()
+	annotated.Annotations = map[string]string{v2.CriticalAnnotation: "true"}
+	_, err := v.ValidateUpdate(context.TODO(), invalid, annotated)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Updates while the object is being deleted are allowed.
+	deleting := invalid.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	deleting.Spec.ReleaseName = "Podinfo_"
+	_, err = v.ValidateUpdate(context.TODO(), invalid, deleting)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Updates changing the spec are validated.
+	changed := invalid.DeepCopy()
+	changed.Spec.ReleaseName = "Podinfo_"
+	_, err = v.ValidateUpdate(context.TODO(), invalid, changed)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+	fixed := invalid.DeepCopy()
+	fixed.Spec.ReleaseName = "podinfo"
+	_, err = v.ValidateUpdate(context.TODO(), invalid, fixed)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The preview annotation can not be added, changed or removed.
+	preview := fixed.DeepCopy()
+	preview.Annotations = map[string]string{v2.PreviewAnnotation: "pr-1"}
+	_, err = v.ValidateUpdate(context.TODO(), fixed, preview)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+	changedPreview := preview.DeepCopy()
+	changedPreview.Annotations[v2.PreviewAnnotation] = "pr-2"
+	_, err = v.ValidateUpdate(context.TODO(), preview, changedPreview)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+	_, err = v.ValidateUpdate(context.TODO(), preview, fixed)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+}
+
+func newHelmRelease(name, namespace string, dependsOn ...v2.DependencyReference) *v2.HelmRelease {
+	return &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: v2.HelmReleaseSpec{
+			DependsOn: dependsOn,
+		},
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// NamespaceValidator validates the admission requests for Namespaces.
+//
+// It rejects the deletion of a Namespace containing a HelmRelease which is
+// protected from deletion, as determined by
+// v2.HelmRelease.IsDeletionProtected. While the HelmReleaseValidator rejects
+// the deletion of the HelmRelease itself, the deletion of its Namespace
+// would otherwise still remove the other objects in it, including the
+// resources of the release.
+type NamespaceValidator struct {
+	// Client is used to list the HelmReleases in a Namespace. It should read
+	// directly from the API server, as the Namespace may not be watched by
+	// the controller.
+	Client client.Reader
+}
+
+var _ admission.CustomValidator = &NamespaceValidator{}
+
+// SetupWithManager registers the validating webhook for Namespaces with the
+// webhook server of the given manager.
+func (v *NamespaceValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.Namespace{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate allows the creation of any Namespace.
+func (v *NamespaceValidator) ValidateCreate(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate allows any update of a Namespace.
+func (v *NamespaceValidator) ValidateUpdate(context.Context, runtime.Object, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete rejects the deletion of a Namespace which contains any
+// HelmRelease protected from deletion.
+func (v *NamespaceValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return nil, fmt.Errorf("expected a Namespace, got %T", obj)
+	}
+
+	var list v2.HelmReleaseList
+	if err := v.Client.List(ctx, &list, client.InNamespace(ns.GetName())); err != nil {
+		return nil, fmt.Errorf("failed to list HelmReleases in namespace '%s': %w", ns.GetName(), err)
+	}
+	var protected []string
+	for _, hr := range list.Items {
+		if hr.IsDeletionProtected() {
+			protected = append(protected, hr.GetName())
+		}
+	}
+	if len(protected) > 0 {
+		return nil, fmt.Errorf("namespace '%s' contains HelmReleases marked as critical with the '%s' annotation: %s, "+
+			"annotate them with '%s=true' to allow the deletion of the namespace",
+			ns.GetName(), v2.CriticalAnnotation, strings.Join(protected, ", "), v2.BreakGlassAnnotation)
+	}
+	return nil, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+func TestNamespaceValidator_ValidateDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		namespace   string
+		wantErr     string
+	}{
+		{
+			name:      "allows deletion",
+			namespace: "kube-system",
+		},
+		{
+			name:        "rejects deletion of namespace with critical HelmRelease",
+			annotations: map[string]string{v2.CriticalAnnotation: "true"},
+			namespace:   "kube-system",
+			wantErr:     "namespace 'kube-system' contains HelmReleases marked as critical with the 'helm.toolkit.fluxcd.io/critical' annotation: cilium",
+		},
+		{
+			name: "allows deletion of namespace with critical HelmRelease with break-glass",
+			annotations: map[string]string{
+				v2.CriticalAnnotation:   "true",
+				v2.BreakGlassAnnotation: "true",
+			},
+			namespace: "kube-system",
+		},
+		{
+			name:        "allows deletion of other namespace",
+			annotations: map[string]string{v2.CriticalAnnotation: "true"},
+			namespace:   "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			hr := newHelmRelease("cilium", "kube-system")
+			hr.SetAnnotations(tt.annotations)
+
+			scheme := runtime.NewScheme()
+			g.Expect(v2.AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(hr).Build()
+
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tt.namespace}}
+			_, err := (&NamespaceValidator{Client: c}).ValidateDelete(context.TODO(), ns)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
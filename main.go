@@ -17,15 +17,19 @@ limitations under the License.
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	flag "github.com/spf13/pflag"
 	"github.com/jessesimpson36/helm/v4/pkg/kube"
+	helmdriver "github.com/jessesimpson36/helm/v4/pkg/storage/driver"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/utils/ptr"
@@ -33,7 +37,10 @@ import (
 	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlcfg "sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/fluxcd/pkg/runtime/acl"
 	"github.com/fluxcd/pkg/runtime/client"
@@ -55,10 +62,23 @@ import (
 	// +kubebuilder:scaffold:imports
 
 	intacl "github.com/fluxcd/helm-controller/internal/acl"
+	"github.com/fluxcd/helm-controller/internal/action"
 	"github.com/fluxcd/helm-controller/internal/controller"
+	intevents "github.com/fluxcd/helm-controller/internal/events"
 	"github.com/fluxcd/helm-controller/internal/features"
+	"github.com/fluxcd/helm-controller/internal/inflight"
 	intkube "github.com/fluxcd/helm-controller/internal/kube"
+	"github.com/fluxcd/helm-controller/internal/liveness"
+	"github.com/fluxcd/helm-controller/internal/loader"
 	"github.com/fluxcd/helm-controller/internal/oomwatch"
+	"github.com/fluxcd/helm-controller/internal/postrender"
+	"github.com/fluxcd/helm-controller/internal/probe"
+	intreconcile "github.com/fluxcd/helm-controller/internal/reconcile"
+	"github.com/fluxcd/helm-controller/internal/release"
+	"github.com/fluxcd/helm-controller/internal/retention"
+	"github.com/fluxcd/helm-controller/internal/scan"
+	"github.com/fluxcd/helm-controller/internal/tracing"
+	"github.com/fluxcd/helm-controller/internal/webhook"
 )
 
 const controllerName = "helm-controller"
@@ -81,17 +101,38 @@ func main() {
 	var (
 		metricsAddr               string
 		eventsAddr                string
+		eventVerbosity            string
+		eventDedupWindow          time.Duration
 		healthAddr                string
 		concurrent                int
 		requeueDependency         time.Duration
+		resyncInterval            time.Duration
 		gracefulShutdownTimeout   time.Duration
+		clockSkewTolerance        time.Duration
+		sourceStaleThreshold      time.Duration
+		discoveryCacheTTL         time.Duration
 		httpRetry                 int
+		httpClientOptions         loader.HTTPClientOptions
+		artifactCacheSize         int
+		artifactCacheServerAddr   string
+		artifactCachePeer         string
+		artifactCacheTokenFile    string
+		maxArtifactSize           int64
+		maxChartSize              int64
+		securityScannerURL        string
+		securityScannerTimeout    time.Duration
+		securityScannerHTTP       loader.HTTPClientOptions
+		securityScannerTokenFile  string
+		failedArtifactRetention   retention.Options
+		tracingOptions            tracing.Options
 		clientOptions             client.Options
 		kubeConfigOpts            client.KubeConfigOptions
 		featureGates              feathelper.FeatureGates
 		logOptions                logger.Options
 		aclOptions                acl.Options
 		leaderElectionOptions     leaderelection.Options
+		leaderElectionID          string
+		leaderElectionNamespace   string
 		rateLimiterOptions        helper.RateLimiterOptions
 		watchOptions              helper.WatchOptions
 		intervalJitterOptions     jitter.IntervalOptions
@@ -100,24 +141,100 @@ func main() {
 		oomWatchMaxMemoryPath     string
 		oomWatchCurrentMemoryPath string
 		snapshotDigestAlgo        string
+		defaultStorageDriver      string
+		postDeployCheckAllowlist  []string
+		defaultMaxHistory         int
+		artifactVerification      string
+		clusterAttributes         map[string]string
+		overridableFeatureGates   []string
+		requireKubeConfigTLS      bool
+		enableDebugEndpoints      bool
+		controllerConfigName      string
+		reconcileStallThreshold   time.Duration
+		webhookPort               int
+		webhookCertDir            string
+		webhookDefaults           webhook.HelmReleaseDefaulter
+		webhookDefaultMaxHistory  int
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080",
 		"The address the metric endpoint binds to.")
 	flag.StringVar(&eventsAddr, "events-addr", "",
 		"The address of the events receiver.")
+	flag.StringVar(&eventVerbosity, "event-verbosity", v2.EventVerbosityInfo,
+		"The verbosity of the events recorded for HelmReleases, regardless of the events address. One of 'info' or 'warning'. With 'warning', info-level events are suppressed. Can be overridden on individual HelmReleases using the '"+v2.EventVerbosityAnnotation+"' annotation.")
+	flag.DurationVar(&eventDedupWindow, "event-dedup-window", 30*time.Minute,
+		"The window within which an event identical to the last event with the same reason for a HelmRelease is suppressed, to prevent persistent failures from flooding the events. A value of 0 disables the deduplication.")
 	flag.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
+	flag.DurationVar(&reconcileStallThreshold, "reconcile-stall-threshold", 0,
+		"The duration after which the health and readiness probes fail when no HelmRelease has been reconciled, nor is being reconciled, while the work queue is not empty, or when the API server can not be reached. A value of 0 disables these checks.")
 	flag.IntVar(&concurrent, "concurrent", 4,
 		"The number of concurrent HelmRelease reconciles.")
 	flag.DurationVar(&requeueDependency, "requeue-dependency", 30*time.Second,
 		"The interval at which failing dependencies are reevaluated.")
+	flag.DurationVar(&resyncInterval, "resync-interval", 24*time.Hour,
+		"The interval at which HelmReleases with an interval of zero are reconciled as a safety measure. A value of 0 disables the resync.")
 	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", 600*time.Second,
-		"The duration given to the reconciler to finish before forcibly stopping.")
+		"The duration given to the reconciler to finish in-flight Helm actions before forcibly stopping.")
+	flag.DurationVar(&clockSkewTolerance, "clock-skew-tolerance", release.DefaultClockSkewTolerance,
+		"The tolerance for clock skew between the controller and the Kubernetes API server or other Helm clients when comparing timestamps.")
+	flag.DurationVar(&sourceStaleThreshold, "source-stale-threshold", time.Hour,
+		"The duration after which the source of a HelmRelease which has not been ready is reported as stale in the SourceDrift condition. A value of 0 disables the check.")
+	flag.DurationVar(&discoveryCacheTTL, "discovery-cache-ttl", 10*time.Minute,
+		"The duration for which the discovered API resources of a target cluster are shared between the reconciliations of the HelmReleases targeting it. A value of 0 disables the cache.")
 	flag.IntVar(&httpRetry, "http-retry", 9,
 		"The maximum number of retries when failing to fetch artifacts over HTTP.")
+	flag.DurationVar(&httpClientOptions.Timeout, "http-timeout", 2*time.Minute,
+		"The timeout of a single attempt to fetch an artifact over HTTP, including reading the response body. A value of 0 disables the timeout.")
+	flag.StringVar(&httpClientOptions.CAFile, "http-ca-file", "",
+		"The path to a PEM encoded CA bundle used to verify the TLS certificate of the artifact server, in addition to the system roots.")
+	flag.StringVar(&httpClientOptions.CertFile, "http-cert-file", "",
+		"The path to a PEM encoded client certificate used to authenticate with the artifact server. Requires '--http-key-file' to be set.")
+	flag.StringVar(&httpClientOptions.KeyFile, "http-key-file", "",
+		"The path to the PEM encoded private key of the client certificate used to authenticate with the artifact server.")
+	flag.StringVar(&httpClientOptions.ProxyURL, "http-proxy-url", "",
+		"The URL of the proxy used to fetch artifacts. If not set, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used.")
+	flag.IntVar(&artifactCacheSize, "artifact-cache-size", 0,
+		"The maximum number of chart artifacts to keep in the in-memory cache, keyed by their digest. A value of 0 disables the cache.")
+	flag.StringVar(&artifactCacheServerAddr, "artifact-cache-server-addr", "",
+		"The address the artifact cache server binds to, to serve cached chart artifacts to peers. Requires '--artifact-cache-size' to be set.")
+	flag.StringVar(&artifactCachePeer, "artifact-cache-peer", "",
+		"The URL of an artifact cache server to pull chart artifacts from before downloading them from the source.")
+	flag.StringVar(&artifactCacheTokenFile, "artifact-cache-token-file", "",
+		"The path to a file holding the bearer token required by the artifact cache server, and presented to the artifact cache peer. Without a token, the server can only bind to a loopback address.")
+	flag.Int64Var(&maxArtifactSize, "max-artifact-size", 50<<20,
+		"The maximum size in bytes of a chart artifact. The download of a larger artifact is aborted. A value of 0 disables the limit.")
+	flag.Int64Var(&maxChartSize, "max-chart-size", 100<<20,
+		"The maximum size in bytes of a chart once decompressed from its artifact. The loading of a larger chart is aborted. A value of 0 disables the limit.")
+	flag.StringVar(&securityScannerURL, "security-scanner-url", "",
+		"The URL of the security scanner the rendered manifests of HelmReleases configuring a security scan are submitted to before they are applied.")
+	flag.DurationVar(&securityScannerTimeout, "security-scanner-timeout", scan.DefaultTimeout,
+		"The timeout of a request to the security scanner.")
+	flag.StringVar(&securityScannerHTTP.CAFile, "security-scanner-ca-file", "",
+		"The path to a PEM encoded CA bundle used to verify the TLS certificate of the security scanner, in addition to the system roots.")
+	flag.StringVar(&securityScannerHTTP.CertFile, "security-scanner-cert-file", "",
+		"The path to a PEM encoded client certificate used to authenticate with the security scanner. Requires '--security-scanner-key-file' to be set.")
+	flag.StringVar(&securityScannerHTTP.KeyFile, "security-scanner-key-file", "",
+		"The path to the PEM encoded private key of the client certificate used to authenticate with the security scanner.")
+	flag.StringVar(&securityScannerTokenFile, "security-scanner-token-file", "",
+		"The path to a file containing a bearer token used to authenticate with the security scanner. The file is read for every request.")
+	flag.StringVar(&failedArtifactRetention.Dir, "failed-artifact-retention-dir", "",
+		"The directory to retain the chart artifact, values and rendered manifest of the last failed reconciliation of each HelmRelease in, for postmortem debugging. The files are removed once the HelmRelease is Ready again. Empty disables the retention.")
+	flag.IntVar(&failedArtifactRetention.MaxCount, "failed-artifact-retention-max-count", 10,
+		"The maximum number of HelmReleases of which the failed reconciliation is retained. The least recently retained are evicted first.")
+	flag.Int64Var(&failedArtifactRetention.MaxSize, "failed-artifact-retention-max-size", 100<<20,
+		"The maximum total size in bytes of the retained files of failed reconciliations. The least recently retained are evicted first.")
+	flag.StringVar(&tracingOptions.Endpoint, "tracing-endpoint", "",
+		"The URL of the OTLP/HTTP endpoint to export OpenTelemetry traces of the reconciliations to, e.g. 'http://otel-collector.monitoring:4318'. Empty disables tracing.")
+	flag.Float64Var(&tracingOptions.SampleRatio, "tracing-sample-ratio", 1,
+		"The ratio of reconciliations to trace, between 0 and 1. Requires '--tracing-endpoint' to be set.")
 	flag.StringVar(&intkube.DefaultServiceAccountName, "default-service-account", "",
 		"Default service account used for impersonation.")
+	flag.BoolVar(&postrender.EnforceNamespace, "enforce-release-namespace", false,
+		"Rewrite the namespace of all rendered resources with a namespace set to the release namespace, to prevent charts from creating resources in other namespaces.")
+	flag.BoolVar(&intreconcile.AttachLogsToConditions, "attach-helm-logs-to-conditions", false,
+		"Append the last Helm logs of a failed action to the message of the Released or Remediated condition, in addition to the message of the event.")
 	flag.Uint8Var(&oomWatchMemoryThreshold, "oom-watch-memory-threshold", 95,
 		"The memory threshold in percentage at which the OOM watcher will trigger a graceful shutdown. Requires feature gate 'OOMWatch' to be enabled.")
 	flag.DurationVar(&oomWatchInterval, "oom-watch-interval", 500*time.Millisecond,
@@ -128,11 +245,47 @@ func main() {
 		"The path to the cgroup current memory usage file. Requires feature gate 'OOMWatch' to be enabled. If not set, the path will be automatically detected.")
 	flag.StringVar(&snapshotDigestAlgo, "snapshot-digest-algo", intdigest.Canonical.String(),
 		"The algorithm to use to calculate the digest of Helm release storage snapshots.")
+	flag.StringVar(&defaultStorageDriver, "default-storage-driver", "secret",
+		"The Helm storage driver used for HelmReleases which do not specify one. One of 'secret', 'configmap' or 'sql'. The connection string for 'sql' is read from the HELM_DRIVER_SQL_CONNECTION_STRING environment variable.")
+	flag.StringSliceVar(&postDeployCheckAllowlist, "post-deploy-check-allowed-hosts", nil,
+		"The hosts (e.g. 'podinfo.default.svc', '*.svc.cluster.local'), IP addresses and CIDRs (e.g. '10.0.0.0/8') the post-deploy checks of HelmReleases are allowed to connect to. When not set, post-deploy checks are not allowed to connect to any target.")
+	flag.IntVar(&defaultMaxHistory, "default-max-history", action.DefaultMaxHistory,
+		"The number of Helm release versions to keep for HelmReleases which do not specify a max history. A value of 0 keeps all versions.")
+	flag.StringVar(&artifactVerification, "artifact-verification", string(v2.ArtifactVerificationEnforce),
+		"The minimum strictness of the verification of chart artifacts, which HelmReleases can only make stricter. One of 'enforce', 'warn' or 'disabled'.")
+	flag.StringToStringVar(&clusterAttributes, "cluster-attributes", nil,
+		"The attributes of the cluster (e.g. 'region=eu-west-1,tier=production') used to select the chart overrides of a HelmRelease.")
+	flag.StringSliceVar(&overridableFeatureGates, "feature-gates-overridable", nil,
+		"The feature gates which can be overridden on individual HelmReleases using the '"+v2.FeatureGatesAnnotation+"' annotation, to enable (or disable) them for a subset of the HelmReleases. One or more of 'AllowDNSLookups', 'AdoptLegacyReleases', 'HideSecrets' or 'SharedHelmCharts'.")
+	flag.IntVar(&webhookPort, "webhook-port", 0,
+		"The port the webhook server for HelmReleases and Namespaces binds to. A value of 0 disables the webhook server.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "",
+		"The directory containing the 'tls.crt' and 'tls.key' files of the serving certificate of the webhook server. Defaults to the directory of controller-runtime.")
+	flag.DurationVar(&webhookDefaults.Interval, "webhook-default-interval", 0,
+		"The interval the mutating webhook sets on HelmReleases which do not specify one. A value of 0 disables the default.")
+	flag.DurationVar(&webhookDefaults.Timeout, "webhook-default-timeout", 0,
+		"The timeout the mutating webhook sets on HelmReleases which do not specify one. A value of 0 disables the default.")
+	flag.IntVar(&webhookDefaultMaxHistory, "webhook-default-max-history", -1,
+		"The max history the mutating webhook sets on HelmReleases which do not specify one. A negative value disables the default.")
+	flag.IntVar(&webhookDefaults.InstallRetries, "webhook-default-install-retries", 0,
+		"The number of install remediation retries the mutating webhook sets on HelmReleases which do not specify it. A value of 0 disables the default.")
+	flag.IntVar(&webhookDefaults.UpgradeRetries, "webhook-default-upgrade-retries", 0,
+		"The number of upgrade remediation retries the mutating webhook sets on HelmReleases which do not specify it. A value of 0 disables the default.")
+	flag.BoolVar(&requireKubeConfigTLS, "require-kubeconfig-tls", false,
+		"Require the KubeConfigs of HelmReleases targeting remote clusters to connect over TLS with certificate verification, to ensure values and manifests are encrypted in transit. Can not be combined with '--insecure-kubeconfig-tls'.")
+	flag.BoolVar(&enableDebugEndpoints, "enable-debug-endpoints", false,
+		"Serve a '"+inflight.HandlerPath+"' endpoint listing the reconciliations in progress on the metrics address.")
+	flag.StringVar(&controllerConfigName, "controller-config", "",
+		"The name of the cluster-scoped ControllerConfig to watch, of which the defaults and policies take precedence over the respective flags and are applied without a restart. Disabled when empty.")
 
 	clientOptions.BindFlags(flag.CommandLine)
 	logOptions.BindFlags(flag.CommandLine)
 	aclOptions.BindFlags(flag.CommandLine)
 	leaderElectionOptions.BindFlags(flag.CommandLine)
+	flag.StringVar(&leaderElectionID, "leader-election-id", "",
+		"The name of the Lease used for leader election. Defaults to a name derived from the controller name and the watch label selector, which allows multiple controllers watching different HelmReleases to each elect a leader.")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "",
+		"The namespace of the Lease used for leader election. Defaults to the namespace the controller runs in.")
 	rateLimiterOptions.BindFlags(flag.CommandLine)
 	kubeConfigOpts.BindFlags(flag.CommandLine)
 	featureGates.BindFlags(flag.CommandLine)
@@ -149,6 +302,16 @@ func main() {
 		setupLog.Error(err, "unable to load feature gates")
 		os.Exit(1)
 	}
+	if err := features.SetOverridable(overridableFeatureGates); err != nil {
+		setupLog.Error(err, "unable to configure overridable feature gates")
+		os.Exit(1)
+	}
+
+	if requireKubeConfigTLS && kubeConfigOpts.InsecureTLS {
+		setupLog.Error(errors.New("'--require-kubeconfig-tls' can not be combined with '--insecure-kubeconfig-tls'"),
+			"invalid KubeConfig options")
+		os.Exit(1)
+	}
 
 	if err := intervalJitterOptions.SetGlobalJitter(nil); err != nil {
 		setupLog.Error(err, "unable to set global jitter")
@@ -180,6 +343,16 @@ func main() {
 	if watchOptions.LabelSelector != "" {
 		leaderElectionId = leaderelection.GenerateID(leaderElectionId, watchOptions.LabelSelector)
 	}
+	if leaderElectionID != "" {
+		leaderElectionId = leaderElectionID
+	}
+
+	// Record the identity of this instance in the journal entries of the
+	// Helm actions it performs, to tell which replica was interrupted when
+	// running with multiple replicas.
+	if hostname, err := os.Hostname(); err == nil {
+		intreconcile.Holder = hostname
+	}
 
 	// Set the managedFields owner for resources reconciled from Helm charts.
 	kube.ManagedFieldsManager = controllerName
@@ -187,6 +360,32 @@ func main() {
 	// Configure the ACL policy.
 	intacl.AllowCrossNamespaceRef = !aclOptions.NoCrossNamespaceRefs
 
+	// Configure the security scanner.
+	if securityScannerURL != "" {
+		scannerHTTPClient, err := loader.NewHTTPClient(securityScannerHTTP)
+		if err != nil {
+			setupLog.Error(err, "unable to configure security scanner HTTP client")
+			os.Exit(1)
+		}
+		postrender.Scanner = &scan.Client{
+			URL:        securityScannerURL,
+			HTTPClient: scannerHTTPClient,
+			TokenFile:  securityScannerTokenFile,
+			Timeout:    securityScannerTimeout,
+		}
+	}
+
+	// Read the token shared with the artifact cache peers.
+	var artifactCacheToken string
+	if artifactCacheTokenFile != "" {
+		b, err := os.ReadFile(artifactCacheTokenFile)
+		if err != nil {
+			setupLog.Error(err, "unable to read artifact cache token")
+			os.Exit(1)
+		}
+		artifactCacheToken = strings.TrimSpace(string(b))
+	}
+
 	// Configure the digest algorithm.
 	if snapshotDigestAlgo != intdigest.Canonical.String() {
 		algo, err := intdigest.AlgorithmForName(snapshotDigestAlgo)
@@ -197,7 +396,51 @@ func main() {
 		intdigest.Canonical = algo
 	}
 
+	// Configure the default Helm storage driver.
+	storageDriver, err := action.ParseStorageDriver(defaultStorageDriver)
+	if err == nil && storageDriver == helmdriver.MemoryDriverName {
+		err = fmt.Errorf("'%s' storage driver is not supported", defaultStorageDriver)
+	}
+	if err != nil {
+		setupLog.Error(err, "unable to configure default Helm storage driver")
+		os.Exit(1)
+	}
+	action.DefaultStorageDriver = storageDriver
+
+	probeAllowlist, err := probe.ParseAllowlist(postDeployCheckAllowlist)
+	if err != nil {
+		setupLog.Error(err, "unable to configure post-deploy check allowlist")
+		os.Exit(1)
+	}
+
+	if defaultMaxHistory < 0 {
+		setupLog.Error(fmt.Errorf("invalid value '%d': must be zero or greater", defaultMaxHistory),
+			"unable to configure default max history")
+		os.Exit(1)
+	}
+	action.DefaultMaxHistory = defaultMaxHistory
+
+	switch v := v2.ArtifactVerification(artifactVerification); v {
+	case v2.ArtifactVerificationEnforce, v2.ArtifactVerificationWarn, v2.ArtifactVerificationDisabled:
+		action.DefaultArtifactVerification = v
+	default:
+		setupLog.Error(fmt.Errorf("invalid value '%s': must be one of 'enforce', 'warn' or 'disabled'", artifactVerification),
+			"unable to configure default artifact verification")
+		os.Exit(1)
+	}
+
+	eventVerbosity, err = intevents.ParseVerbosity(eventVerbosity)
+	if err != nil {
+		setupLog.Error(err, "unable to configure event verbosity")
+		os.Exit(1)
+	}
+
 	restConfig := client.GetConfigOrDie(clientOptions)
+	// Apply the effective rate limits of the manager to the clients of the
+	// Helm actions as well, as client-side throttling is disabled when the
+	// API server enforces API Priority and Fairness. Otherwise, the Helm
+	// actions would still be throttled during a wave of upgrades.
+	clientOptions.QPS, clientOptions.Burst = restConfig.QPS, restConfig.Burst
 
 	mgrConfig := ctrl.Options{
 		Scheme:                        scheme,
@@ -209,6 +452,7 @@ func main() {
 		RetryPeriod:                   &leaderElectionOptions.RetryPeriod,
 		GracefulShutdownTimeout:       &gracefulShutdownTimeout,
 		LeaderElectionID:              leaderElectionId,
+		LeaderElectionNamespace:       leaderElectionNamespace,
 		Logger:                        ctrl.Log,
 		Client: ctrlclient.Options{
 			Cache: &ctrlclient.CacheOptions{
@@ -230,6 +474,21 @@ func main() {
 		},
 	}
 
+	// Keep track of the reconciliations in progress, and serve them when the
+	// debug endpoints are enabled.
+	var inFlight *inflight.Tracker
+	if enableDebugEndpoints {
+		inFlight = inflight.NewTracker()
+		mgrConfig.Metrics.ExtraHandlers[inflight.HandlerPath] = inFlight.Handler()
+	}
+
+	if webhookPort > 0 {
+		mgrConfig.WebhookServer = ctrlwebhook.NewServer(ctrlwebhook.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		})
+	}
+
 	if watchNamespace != "" {
 		mgrConfig.Cache.DefaultNamespaces = map[string]ctrlcache.Config{
 			watchNamespace: ctrlcache.Config{},
@@ -243,6 +502,28 @@ func main() {
 	}
 
 	probes.SetupChecks(mgr, setupLog)
+	if reconcileStallThreshold > 0 {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+		if err != nil {
+			setupLog.Error(err, "unable to create discovery client for liveness checks")
+			os.Exit(1)
+		}
+		lc := liveness.New(strings.ToLower(v2.HelmReleaseKind), reconcileStallThreshold,
+			ctrlmetrics.Registry, mgr.Elected(), discoveryClient.RESTClient())
+		for name, check := range map[string]healthz.Checker{
+			"reconcile-loop": lc.ReconcileLoop,
+			"apiserver":      lc.APIServer,
+		} {
+			if err := mgr.AddHealthzCheck(name, check); err != nil {
+				setupLog.Error(err, "unable to create health check", "check", name)
+				os.Exit(1)
+			}
+			if err := mgr.AddReadyzCheck(name, check); err != nil {
+				setupLog.Error(err, "unable to create ready check", "check", name)
+				os.Exit(1)
+			}
+		}
+	}
 
 	metricsH := helper.NewMetrics(mgr, metrics.MustMakeRecorder(), v2.HelmReleaseFinalizer)
 	var eventRecorder *events.Recorder
@@ -268,23 +549,89 @@ func main() {
 		ctx = ow.Watch(ctx)
 	}
 
-	if err = (&controller.HelmReleaseReconciler{
-		Client:           mgr.GetClient(),
-		APIReader:        mgr.GetAPIReader(),
-		EventRecorder:    eventRecorder,
-		Metrics:          metricsH,
-		GetClusterConfig: ctrl.GetConfig,
-		ClientOpts:       clientOptions,
-		KubeConfigOpts:   kubeConfigOpts,
-		FieldManager:     controllerName,
-	}).SetupWithManager(ctx, mgr, controller.HelmReleaseReconcilerOptions{
+	tracingProvider, err := tracing.NewProvider(ctx, controllerName, tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to configure tracing")
+		os.Exit(1)
+	}
+	if tracingProvider != nil {
+		if err = mgr.Add(tracingProvider); err != nil {
+			setupLog.Error(err, "unable to add tracing provider to manager")
+			os.Exit(1)
+		}
+	}
+
+	helmReleaseReconciler := &controller.HelmReleaseReconciler{
+		Client:               mgr.GetClient(),
+		APIReader:            mgr.GetAPIReader(),
+		EventRecorder:        intevents.NewRecorder(eventRecorder, eventVerbosity, intevents.WithDedupWindow(eventDedupWindow)),
+		Metrics:              metricsH,
+		GetClusterConfig:     ctrl.GetConfig,
+		ClientOpts:           clientOptions,
+		KubeConfigOpts:       kubeConfigOpts,
+		RequireKubeConfigTLS: requireKubeConfigTLS,
+		FieldManager:         controllerName,
+		ClusterAttributes:    clusterAttributes,
+	}
+	if err = helmReleaseReconciler.SetupWithManager(ctx, mgr, controller.HelmReleaseReconcilerOptions{
 		DependencyRequeueInterval: requeueDependency,
+		ResyncInterval:            resyncInterval,
 		HTTPRetry:                 httpRetry,
+		HTTPClient:                httpClientOptions,
+		ArtifactCacheSize:         artifactCacheSize,
+		ArtifactCacheServerAddr:   artifactCacheServerAddr,
+		ArtifactCachePeer:         artifactCachePeer,
+		ArtifactCacheToken:        artifactCacheToken,
+		MaxArtifactSize:           maxArtifactSize,
+		MaxChartSize:              maxChartSize,
+		FailedArtifactRetention:   failedArtifactRetention,
+		MaxConcurrentReconciles:   concurrent,
+		InFlight:                  inFlight,
+		ShutdownGracePeriod:       gracefulShutdownTimeout,
+		ClockSkewTolerance:        clockSkewTolerance,
+		SourceStaleThreshold:      sourceStaleThreshold,
+		DiscoveryCacheTTL:         discoveryCacheTTL,
+		PostDeployCheckAllowlist:  probeAllowlist,
 		RateLimiter:               helper.GetRateLimiter(rateLimiterOptions),
 	}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", v2.HelmReleaseKind)
 		os.Exit(1)
 	}
+	if err = (&controller.HelmReleaseGroupReconciler{
+		Client:       mgr.GetClient(),
+		FieldManager: controllerName,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", v2.HelmReleaseGroupKind)
+		os.Exit(1)
+	}
+	if controllerConfigName != "" {
+		if err = (&controller.ControllerConfigReconciler{
+			Client:                mgr.GetClient(),
+			FieldManager:          controllerName,
+			Name:                  controllerConfigName,
+			HelmReleaseReconciler: helmReleaseReconciler,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", v2.ControllerConfigKind)
+			os.Exit(1)
+		}
+	}
+	if webhookPort > 0 {
+		if err = (&webhook.HelmReleaseValidator{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", v2.HelmReleaseKind)
+			os.Exit(1)
+		}
+		if err = (&webhook.NamespaceValidator{Client: mgr.GetAPIReader()}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Namespace")
+			os.Exit(1)
+		}
+		if webhookDefaultMaxHistory >= 0 {
+			webhookDefaults.MaxHistory = &webhookDefaultMaxHistory
+		}
+		if err = webhookDefaults.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", v2.HelmReleaseKind)
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -0,0 +1,315 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// load creates a number of synthetic HelmReleases in a cluster running the
+// helm-controller, waits for them to become ready, and reports the reconcile
+// throughput. If the metrics endpoint of the controller is provided, it also
+// reports the number of API write requests and the memory of the controller.
+//
+// Usage:
+//
+//	load [flags]
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2"
+)
+
+// loadLabel is the label set on the HelmReleases created by the load
+// generator, used to clean them up.
+const loadLabel = "helm.toolkit.fluxcd.io/load-test"
+
+// options holds the flags of the load generator.
+type options struct {
+	count      int
+	chartKind  string
+	chartName  string
+	timeout    time.Duration
+	metricsURL string
+	cleanup    bool
+}
+
+func main() {
+	flags := flag.NewFlagSet("load", flag.ExitOnError)
+
+	configFlags := genericclioptions.NewConfigFlags(true)
+	configFlags.AddFlags(flags)
+
+	var opts options
+	flags.IntVar(&opts.count, "count", 100,
+		"The number of HelmReleases to create.")
+	flags.StringVar(&opts.chartKind, "chart-kind", "OCIRepository",
+		"The kind of the chart source referenced by the HelmReleases, either 'OCIRepository' or 'HelmChart'.")
+	flags.StringVar(&opts.chartName, "chart-name", "podinfo",
+		"The name of the chart source referenced by the HelmReleases, in the namespace of the HelmReleases.")
+	flags.DurationVar(&opts.timeout, "timeout", 30*time.Minute,
+		"The time to wait for all the HelmReleases to become ready.")
+	flags.StringVar(&opts.metricsURL, "metrics-url", "",
+		"The URL of the metrics endpoint of the controller, e.g. http://localhost:8080/metrics.")
+	flags.BoolVar(&opts.cleanup, "cleanup", true,
+		"Delete the HelmReleases after the run.")
+	_ = flags.Parse(os.Args[1:])
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx, configFlags, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts options) error {
+	cfg, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	namespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return fmt.Errorf("failed to determine namespace: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v2.AddToScheme(scheme))
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	before, err := scrapeMetrics(ctx, opts.metricsURL)
+	if err != nil {
+		return err
+	}
+
+	runID := strconv.FormatInt(time.Now().Unix(), 10)
+	if opts.cleanup {
+		defer func() {
+			if err := c.DeleteAllOf(context.Background(), &v2.HelmRelease{}, client.InNamespace(namespace),
+				client.MatchingLabels{loadLabel: runID}); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to delete HelmReleases: %s\n", err)
+			}
+		}()
+	}
+
+	start := time.Now()
+	created := make(map[string]time.Time, opts.count)
+	for i := 0; i < opts.count; i++ {
+		obj := newHelmRelease(namespace, fmt.Sprintf("load-%s-%d", runID, i), runID, opts)
+		if err := c.Create(ctx, obj); err != nil {
+			return fmt.Errorf("failed to create HelmRelease '%s': %w", obj.Name, err)
+		}
+		created[obj.Name] = time.Now()
+	}
+	fmt.Printf("Created %d HelmReleases in %s\n", opts.count, time.Since(start).Round(time.Millisecond))
+
+	readyAfter := make(map[string]time.Duration, opts.count)
+	err = wait.PollUntilContextTimeout(ctx, time.Second, opts.timeout, true, func(ctx context.Context) (bool, error) {
+		list := &v2.HelmReleaseList{}
+		if err := c.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels{loadLabel: runID}); err != nil {
+			return false, err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if _, ok := readyAfter[obj.Name]; ok {
+				continue
+			}
+			if obj.Status.ObservedGeneration == obj.Generation && conditions.IsReady(obj) {
+				readyAfter[obj.Name] = time.Since(created[obj.Name])
+			}
+		}
+		return len(readyAfter) == opts.count, nil
+	})
+	elapsed := time.Since(start)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	after, err := scrapeMetrics(ctx, opts.metricsURL)
+	if err != nil {
+		return err
+	}
+
+	report(os.Stdout, opts, elapsed, readyAfter, before, after)
+	if len(readyAfter) != opts.count {
+		return fmt.Errorf("%d of %d HelmReleases did not become ready within %s",
+			opts.count-len(readyAfter), opts.count, opts.timeout)
+	}
+	return nil
+}
+
+// newHelmRelease returns a synthetic HelmRelease referencing the chart source
+// configured in the options.
+func newHelmRelease(namespace, name, runID string, opts options) *v2.HelmRelease {
+	return &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				loadLabel: runID,
+			},
+		},
+		Spec: v2.HelmReleaseSpec{
+			ChartRef: &v2.CrossNamespaceSourceReference{
+				Kind: opts.chartKind,
+				Name: opts.chartName,
+			},
+			Interval: metav1.Duration{Duration: time.Hour},
+		},
+	}
+}
+
+// report writes the results of the run to w.
+func report(w io.Writer, opts options, elapsed time.Duration, readyAfter map[string]time.Duration,
+	before, after metrics) {
+	durations := make([]time.Duration, 0, len(readyAfter))
+	for _, d := range readyAfter {
+		durations = append(durations, d)
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	fmt.Fprintf(w, "Ready:       %d/%d in %s\n", len(durations), opts.count, elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "Throughput:  %.2f releases/s\n", float64(len(durations))/elapsed.Seconds())
+	if len(durations) > 0 {
+		fmt.Fprintf(w, "Time to ready (p50/p95/max): %s/%s/%s\n",
+			percentile(durations, 50).Round(time.Millisecond),
+			percentile(durations, 95).Round(time.Millisecond),
+			durations[len(durations)-1].Round(time.Millisecond))
+	}
+
+	if before == nil || after == nil {
+		return
+	}
+	writes := after.sum("rest_client_requests_total", writeMethods...) -
+		before.sum("rest_client_requests_total", writeMethods...)
+	fmt.Fprintf(w, "API writes:  %.0f (%.2f/release)\n", writes, writes/float64(opts.count))
+	fmt.Fprintf(w, "Memory:      %.1f MiB resident, %.1f MiB heap in use\n",
+		after.sum("process_resident_memory_bytes")/(1<<20),
+		after.sum("go_memstats_heap_inuse_bytes")/(1<<20))
+}
+
+// percentile returns the p-th percentile of the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	i := (len(sorted)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+// writeMethods are the HTTP methods of the API write requests, as reported
+// in the method label of the rest_client_requests_total metric.
+var writeMethods = []string{"POST", "PUT", "PATCH", "DELETE"}
+
+// metrics holds the samples of a Prometheus text exposition, as a list of
+// label sets and values indexed by metric name.
+type metrics map[string][]sample
+
+type sample struct {
+	labels string
+	value  float64
+}
+
+// sum returns the sum of the values of the named metric. If methods are
+// given, only the samples with a matching method label are included.
+func (m metrics) sum(name string, methods ...string) float64 {
+	var total float64
+	for _, s := range m[name] {
+		if len(methods) > 0 && !hasMethod(s.labels, methods) {
+			continue
+		}
+		total += s.value
+	}
+	return total
+}
+
+func hasMethod(labels string, methods []string) bool {
+	for _, method := range methods {
+		if strings.Contains(labels, fmt.Sprintf("method=%q", method)) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrapeMetrics returns the metrics exposed at the given URL, or nil if the
+// URL is empty.
+func scrapeMetrics(ctx context.Context, url string) (metrics, error) {
+	if url == "" {
+		return nil, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape metrics: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to scrape metrics: unexpected status code %d", resp.StatusCode)
+	}
+	return parseMetrics(resp.Body)
+}
+
+// parseMetrics parses the samples of a Prometheus text exposition.
+func parseMetrics(r io.Reader) (metrics, error) {
+	m := metrics{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndexByte(line, ' ')
+		if i < 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		if err != nil {
+			continue
+		}
+		name, labels := line[:i], ""
+		if j := strings.IndexByte(name, '{'); j >= 0 {
+			name, labels = name[:j], name[j:]
+		}
+		m[name] = append(m[name], sample{labels: labels, value: value})
+	}
+	return m, scanner.Err()
+}
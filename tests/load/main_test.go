@@ -0,0 +1,55 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_parseMetrics(t *testing.T) {
+	g := NewWithT(t)
+
+	m, err := parseMetrics(strings.NewReader(`# HELP rest_client_requests_total Number of HTTP requests.
+# TYPE rest_client_requests_total counter
+rest_client_requests_total{code="200",host="10.96.0.1:443",method="GET"} 120
+rest_client_requests_total{code="200",host="10.96.0.1:443",method="PATCH"} 30
+rest_client_requests_total{code="201",host="10.96.0.1:443",method="POST"} 12
+rest_client_requests_total{code="409",host="10.96.0.1:443",method="PUT"} 1
+process_resident_memory_bytes 1.048576e+08
+`))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m.sum("rest_client_requests_total")).To(Equal(float64(163)))
+	g.Expect(m.sum("rest_client_requests_total", writeMethods...)).To(Equal(float64(43)))
+	g.Expect(m.sum("process_resident_memory_bytes")).To(Equal(float64(100 << 20)))
+	g.Expect(m.sum("does_not_exist")).To(BeZero())
+}
+
+func Test_percentile(t *testing.T) {
+	g := NewWithT(t)
+
+	var durations []time.Duration
+	for i := 1; i <= 20; i++ {
+		durations = append(durations, time.Duration(i)*time.Second)
+	}
+	g.Expect(percentile(durations, 50)).To(Equal(10 * time.Second))
+	g.Expect(percentile(durations, 95)).To(Equal(19 * time.Second))
+	g.Expect(percentile(durations[:1], 95)).To(Equal(time.Second))
+}